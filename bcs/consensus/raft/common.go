@@ -0,0 +1,144 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+)
+
+var (
+	ErrNotLeader       = errors.New("Node is not the raft leader.")
+	ErrProposer        = errors.New("Block's proposer is not a raft validator.")
+	ErrStaleTerm       = errors.New("Block's raft term is stale.")
+	ErrTermLeader      = errors.New("Raft term has been led by another proposer.")
+	ErrStorage         = errors.New("Raft storage of block is invalid.")
+	ErrAddress         = errors.New("Block's proposer must be equal to its address.")
+	ErrBlockSign       = errors.New("Blockid or sign of block is invalid.")
+	ErrEmptyValidators = errors.New("Raft validators can not be empty.")
+	ErrTargetParam     = errors.New("Target paramters are invalid, please check them.")
+	ErrAuth            = errors.New("Raft membership change needs a majority of current validators.")
+	ErrMembership      = errors.New("Raft membership change can only add or remove one validator at a time.")
+	ErrMsgSign         = errors.New("Raft message is not signed by its sender.")
+	ErrElection        = errors.New("Raft term lacks a majority of votes for the block's proposer.")
+)
+
+const (
+	raftBucket = "$raft"
+
+	validatorsKey          = "validators"
+	contractEditValidators = "editValidators"
+	contractGetValidators  = "getValidators"
+
+	fee = 1000
+
+	// 单位为毫秒
+	defaultPeriod          = 3000
+	defaultElectionTimeout = 3000
+	// leader每个选举超时周期内发送的心跳次数
+	heartbeatsPerTimeout = 3
+)
+
+type raftConfig struct {
+	// 初始validators，之后的成员变更通过editValidators完成
+	Validators []string
+	// 出块间隔，单位为毫秒
+	Period int64
+	// 选举超时的下限，实际超时在[ElectionTimeout, 2*ElectionTimeout)内随机，单位为毫秒
+	ElectionTimeout int64
+	Version         int64
+}
+
+// buildConfigs 与其他共识一致，数值类配置均以字符串形式给出
+func buildConfigs(input []byte) (*raftConfig, error) {
+	v := struct {
+		Validators      []string `json:"validators"`
+		Period          string   `json:"period"`
+		ElectionTimeout string   `json:"election_timeout"`
+		Version         string   `json:"version"`
+	}{}
+	if err := json.Unmarshal(input, &v); err != nil {
+		return nil, fmt.Errorf("unmarshal raft config error")
+	}
+	if len(v.Validators) == 0 {
+		return nil, ErrEmptyValidators
+	}
+
+	config := &raftConfig{
+		Validators:      v.Validators,
+		Period:          defaultPeriod,
+		ElectionTimeout: defaultElectionTimeout,
+	}
+	var err error
+	if v.Version != "" {
+		config.Version, err = strconv.ParseInt(v.Version, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse version error: %v, %v", err, v.Version)
+		}
+	}
+	if v.Period != "" {
+		config.Period, err = strconv.ParseInt(v.Period, 10, 64)
+		if err != nil || config.Period <= 0 {
+			return nil, fmt.Errorf("parse period error: %v, %v", err, v.Period)
+		}
+	}
+	if v.ElectionTimeout != "" {
+		config.ElectionTimeout, err = strconv.ParseInt(v.ElectionTimeout, 10, 64)
+		if err != nil || config.ElectionTimeout <= 0 {
+			return nil, fmt.Errorf("parse election_timeout error: %v, %v", err, v.ElectionTimeout)
+		}
+	}
+	return config, nil
+}
+
+// raftStorage 写入区块共识专有存储，Term为出块leader的任期
+// CommitHeight为出块时leader确认已复制到多数validators的最高区块，仅供查询
+// Votes仅出现在任期的第一个区块中，为多数派validators同意leader当选的签名选票
+type raftStorage struct {
+	Term         int64                         `json:"term"`
+	CommitHeight int64                         `json:"commit_height"`
+	Votes        []*raftPb.RequestVoteResponse `json:"votes,omitempty"`
+}
+
+func parseStorage(b []byte) (*raftStorage, error) {
+	if len(b) == 0 {
+		return nil, ErrStorage
+	}
+	s := &raftStorage{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, ErrStorage
+	}
+	return s, nil
+}
+
+// ValidatorsInfo editValidators写入合约bucket的validators集合
+type ValidatorsInfo struct {
+	Validators []string `json:"validators"`
+}
+
+func loadValidators(b []byte) ([]string, error) {
+	info := ValidatorsInfo{}
+	if err := json.Unmarshal(b, &info); err != nil {
+		return nil, err
+	}
+	if len(info.Validators) == 0 {
+		return nil, ErrEmptyValidators
+	}
+	return info.Validators, nil
+}
+
+func find(addr string, validators []string) bool {
+	for _, v := range validators {
+		if v == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// quorum 返回n个validators的多数派数量
+func quorum(n int) int {
+	return n/2 + 1
+}
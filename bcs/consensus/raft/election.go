@@ -0,0 +1,417 @@
+package raft
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/timer"
+	xuperp2p "github.com/xuperchain/xupercore/protos"
+)
+
+// 本文件实现raft的选举和心跳，状态变更均需持有c.mutex
+
+// run 按心跳间隔驱动状态机，leader发送心跳，follower和candidate检查选举超时
+func (c *raftConsensus) run(quitCh chan struct{}) {
+	ticker := time.NewTicker(c.electionTimeout() / heartbeatsPerTimeout)
+	defer ticker.Stop()
+	c.tick()
+	for {
+		select {
+		case <-ticker.C:
+			c.tick()
+		case <-quitCh:
+			return
+		}
+	}
+}
+
+func (c *raftConsensus) tick() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	if c.role == leader {
+		// 与多数派失联的leader主动退位，避免网络分区时旧leader持续出块
+		if now.Sub(c.lastQuorum) > c.electionTimeout() {
+			c.log.Warn("consensus:raft:tick: leader lost quorum, step down", "term", c.term)
+			c.becomeFollower(c.term, "")
+			return
+		}
+		c.heartbeat()
+		return
+	}
+	if now.Sub(c.lastHeard) < c.timeout || !find(c.address, c.validators) {
+		return
+	}
+	req, err := c.becomeCandidate()
+	if err != nil {
+		c.log.Error("consensus:raft:tick: become candidate error", "err", err)
+		return
+	}
+	go c.campaign(req)
+}
+
+// becomeFollower 进入term任期的follower状态，任期增加时清空投票记录并持久化
+func (c *raftConsensus) becomeFollower(term int64, leader string) {
+	if term > c.term {
+		c.term = term
+		c.votedFor = ""
+		if err := c.saveState(); err != nil {
+			c.log.Error("consensus:raft:becomeFollower: save raft state error", "err", err)
+		}
+	}
+	if c.role != follower || c.leader != leader {
+		c.log.Info("consensus:raft: become follower", "term", c.term, "leader", leader)
+	}
+	c.role = follower
+	c.leader = leader
+	c.lastHeard = time.Now()
+	c.resetTimeout()
+}
+
+// becomeCandidate 任期加一并投票给自己，投票记录落盘后才发出拉票请求
+func (c *raftConsensus) becomeCandidate() (*raftPb.RequestVote, error) {
+	c.term++
+	c.votedFor = c.address
+	if err := c.saveState(); err != nil {
+		return nil, err
+	}
+	c.role = candidate
+	c.leader = ""
+	c.lastHeard = time.Now()
+	c.resetTimeout()
+	lastTerm, lastHeight := c.lastLog()
+	c.log.Info("consensus:raft: become candidate", "term", c.term, "lastTerm", lastTerm, "lastHeight", lastHeight)
+	req := &raftPb.RequestVote{
+		Term:       c.term,
+		Candidate:  c.address,
+		LastTerm:   lastTerm,
+		LastHeight: lastHeight,
+	}
+	if err := c.signRequestVote(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// becomeLeader 成为leader，votes为多数派签名选票，作为当选证明写入本任期的第一个区块
+func (c *raftConsensus) becomeLeader(votes []*raftPb.RequestVoteResponse) {
+	c.role = leader
+	c.leader = c.address
+	c.votes = votes
+	c.lastQuorum = time.Now()
+	c.matchHeights = make(map[string]int64)
+	c.log.Info("consensus:raft: become leader", "term", c.term, "validators", c.validators)
+	c.heartbeat()
+}
+
+// campaign 向其他validators拉票，获得多数派签名选票后成为leader
+// 响应的签名者需为其声明的投票者，未签名或签名不符的响应直接忽略
+func (c *raftConsensus) campaign(req *raftPb.RequestVote) {
+	self := &raftPb.RequestVoteResponse{
+		Term:        req.Term,
+		Voter:       c.address,
+		VoteGranted: true,
+		Candidate:   c.address,
+	}
+	if err := c.signVote(self); err != nil {
+		c.log.Error("consensus:raft:campaign: sign vote error", "err", err)
+		return
+	}
+	granted := map[string]bool{c.address: true}
+	votes := []*raftPb.RequestVoteResponse{self}
+	msg := p2p.NewMessage(xuperp2p.XuperMessage_RAFT_REQUEST_VOTE, req, p2p.WithBCName(c.cCtx.BcName))
+	for _, resp := range c.sendToOthers(msg) {
+		vote := &raftPb.RequestVoteResponse{}
+		if err := p2p.Unmarshal(resp, vote); err != nil {
+			continue
+		}
+		if vote.Candidate != req.Candidate || c.verifyVote(vote) != nil {
+			c.log.Warn("consensus:raft:campaign: invalid vote", "voter", vote.Voter, "candidate", vote.Candidate)
+			continue
+		}
+		if vote.Term > req.Term {
+			c.mutex.Lock()
+			c.becomeFollower(vote.Term, "")
+			c.mutex.Unlock()
+			return
+		}
+		if vote.VoteGranted && vote.Term == req.Term && !granted[vote.Voter] {
+			granted[vote.Voter] = true
+			votes = append(votes, vote)
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// 拉票期间任期或角色已变化
+	if c.role != candidate || c.term != req.Term {
+		return
+	}
+	if c.countValidators(granted) >= quorum(len(c.validators)) {
+		c.becomeLeader(votes)
+	}
+}
+
+// heartbeat 签名并异步发送心跳
+func (c *raftConsensus) heartbeat() {
+	req, err := c.newAppendEntries()
+	if err != nil {
+		c.log.Error("consensus:raft:heartbeat: sign append entries error", "err", err)
+		return
+	}
+	go c.broadcastHeartbeat(req)
+}
+
+func (c *raftConsensus) newAppendEntries() (*raftPb.AppendEntries, error) {
+	tipBlock := c.cCtx.Ledger.GetTipBlock()
+	req := &raftPb.AppendEntries{
+		Term:         c.term,
+		Leader:       c.address,
+		TipHeight:    tipBlock.GetHeight(),
+		TipBlockid:   tipBlock.GetBlockid(),
+		CommitHeight: c.commitHeight,
+	}
+	if err := c.signAppendEntries(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// broadcastHeartbeat 发送心跳，根据签名有效的响应确认多数派并推进commitHeight
+func (c *raftConsensus) broadcastHeartbeat(req *raftPb.AppendEntries) {
+	acks := map[string]int64{c.address: req.TipHeight}
+	msg := p2p.NewMessage(xuperp2p.XuperMessage_RAFT_APPEND_ENTRIES, req, p2p.WithBCName(c.cCtx.BcName))
+	for _, resp := range c.sendToOthers(msg) {
+		ack := &raftPb.AppendEntriesResponse{}
+		if err := p2p.Unmarshal(resp, ack); err != nil {
+			continue
+		}
+		if ack.Leader != req.Leader || c.verifyAppendEntriesResp(ack) != nil {
+			c.log.Warn("consensus:raft:broadcastHeartbeat: invalid response", "follower", ack.Follower, "leader", ack.Leader)
+			continue
+		}
+		if ack.Term > req.Term {
+			c.mutex.Lock()
+			c.becomeFollower(ack.Term, "")
+			c.mutex.Unlock()
+			return
+		}
+		if ack.Success && ack.Term == req.Term {
+			acks[ack.Follower] = ack.MatchHeight
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.role != leader || c.term != req.Term {
+		return
+	}
+	granted := make(map[string]bool)
+	for addr, height := range acks {
+		granted[addr] = true
+		c.matchHeights[addr] = height
+	}
+	if c.countValidators(granted) >= quorum(len(c.validators)) {
+		c.lastQuorum = time.Now()
+	}
+	c.updateCommitHeight()
+}
+
+// updateCommitHeight 多数派validators账本均已达到的最高高度即为commitHeight
+func (c *raftConsensus) updateCommitHeight() {
+	var heights []int64
+	for _, v := range c.validators {
+		heights = append(heights, c.matchHeights[v])
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+	if commit := heights[quorum(len(heights))-1]; commit > c.commitHeight {
+		c.commitHeight = commit
+	}
+}
+
+// onRequestVote 处理拉票请求，每个任期只投一票，且只投给账本不旧于本地的candidate
+func (c *raftConsensus) onRequestVote(req *raftPb.RequestVote) *raftPb.RequestVoteResponse {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	resp := &raftPb.RequestVoteResponse{
+		Voter:     c.address,
+		Candidate: req.Candidate,
+	}
+	defer func() {
+		resp.Term = c.term
+	}()
+	if !find(req.Candidate, c.validators) || req.Term < c.term {
+		return resp
+	}
+	// 仍能收到leader心跳时拒绝拉票，避免被隔离后恢复的节点打断当前leader
+	if c.hasLeader() {
+		return resp
+	}
+	if req.Term > c.term {
+		c.becomeFollower(req.Term, "")
+	}
+	if c.votedFor != "" && c.votedFor != req.Candidate {
+		return resp
+	}
+	lastTerm, lastHeight := c.lastLog()
+	if req.LastTerm < lastTerm || (req.LastTerm == lastTerm && req.LastHeight < lastHeight) {
+		return resp
+	}
+	c.votedFor = req.Candidate
+	if err := c.saveState(); err != nil {
+		c.log.Error("consensus:raft:onRequestVote: save raft state error", "err", err)
+		c.votedFor = ""
+		return resp
+	}
+	c.lastHeard = time.Now()
+	resp.VoteGranted = true
+	return resp
+}
+
+// onAppendEntries 处理leader心跳
+func (c *raftConsensus) onAppendEntries(req *raftPb.AppendEntries) *raftPb.AppendEntriesResponse {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	resp := &raftPb.AppendEntriesResponse{
+		Follower: c.address,
+		Leader:   req.Leader,
+	}
+	defer func() {
+		resp.Term = c.term
+	}()
+	if req.Term < c.term || !find(req.Leader, c.validators) {
+		return resp
+	}
+	if req.Term == c.term && c.role == leader {
+		c.log.Error("consensus:raft:onAppendEntries: another leader in the same term", "term", req.Term, "leader", req.Leader)
+		return resp
+	}
+	c.becomeFollower(req.Term, req.Leader)
+	resp.Success = true
+	resp.MatchHeight = c.cCtx.Ledger.GetTipBlock().GetHeight()
+	return resp
+}
+
+// hasLeader 本节点为leader或在选举超时内收到过leader心跳
+func (c *raftConsensus) hasLeader() bool {
+	if c.role == leader {
+		return true
+	}
+	return c.leader != "" && time.Since(c.lastHeard) < c.electionTimeout()
+}
+
+func (c *raftConsensus) countValidators(addrs map[string]bool) int {
+	count := 0
+	for _, v := range c.validators {
+		if addrs[v] {
+			count++
+		}
+	}
+	return count
+}
+
+func (c *raftConsensus) saveState() error {
+	return c.store.save(&hardState{
+		Term:     c.term,
+		VotedFor: c.votedFor,
+	})
+}
+
+// registerToNetwork 注册选举和心跳消息的处理句柄，未配置网络时仅支持单个validator
+func (c *raftConsensus) registerToNetwork() error {
+	if c.cCtx.Network == nil {
+		return nil
+	}
+	handlers := map[xuperp2p.XuperMessage_MessageType]p2p.HandleFunc{
+		xuperp2p.XuperMessage_RAFT_REQUEST_VOTE:   c.handleRequestVote,
+		xuperp2p.XuperMessage_RAFT_APPEND_ENTRIES: c.handleAppendEntries,
+	}
+	for msgType, handle := range handlers {
+		sub := c.cCtx.Network.NewSubscriber(msgType, handle, p2p.WithFilterBCName(c.cCtx.BcName))
+		if err := c.cCtx.Network.Register(sub); err != nil {
+			return err
+		}
+		c.subscribers = append(c.subscribers, sub)
+	}
+	return nil
+}
+
+func (c *raftConsensus) unregisterToNetwork() {
+	for _, sub := range c.subscribers {
+		if err := c.cCtx.Network.UnRegister(sub); err != nil {
+			c.log.Warn("consensus:raft:unregisterToNetwork: unregister error", "err", err)
+		}
+	}
+	c.subscribers = nil
+}
+
+func (c *raftConsensus) handleRequestVote(ctx xctx.XContext, msg *xuperp2p.XuperMessage) (*xuperp2p.XuperMessage, error) {
+	req := &raftPb.RequestVote{}
+	if err := p2p.Unmarshal(msg, req); err != nil {
+		ctx.GetLog().Warn("consensus:raft:handleRequestVote: unmarshal error", "err", err)
+		return nil, err
+	}
+	// 拉票请求需由其声明的candidate签名
+	if err := c.verifyRequestVote(req); err != nil {
+		ctx.GetLog().Warn("consensus:raft:handleRequestVote: verify sign error", "err", err, "candidate", req.Candidate)
+		return nil, err
+	}
+	resp := c.onRequestVote(req)
+	if err := c.signVote(resp); err != nil {
+		return nil, err
+	}
+	return c.newResponse(msg, resp), nil
+}
+
+func (c *raftConsensus) handleAppendEntries(ctx xctx.XContext, msg *xuperp2p.XuperMessage) (*xuperp2p.XuperMessage, error) {
+	req := &raftPb.AppendEntries{}
+	if err := p2p.Unmarshal(msg, req); err != nil {
+		ctx.GetLog().Warn("consensus:raft:handleAppendEntries: unmarshal error", "err", err)
+		return nil, err
+	}
+	// 心跳需由其声明的leader签名
+	if err := c.verifyAppendEntries(req); err != nil {
+		ctx.GetLog().Warn("consensus:raft:handleAppendEntries: verify sign error", "err", err, "leader", req.Leader)
+		return nil, err
+	}
+	resp := c.onAppendEntries(req)
+	if err := c.signAppendEntriesResp(resp); err != nil {
+		return nil, err
+	}
+	return c.newResponse(msg, resp), nil
+}
+
+func (c *raftConsensus) newResponse(request *xuperp2p.XuperMessage, resp proto.Message) *xuperp2p.XuperMessage {
+	opts := []p2p.MessageOption{
+		p2p.WithBCName(request.GetHeader().GetBcname()),
+		p2p.WithLogId(request.GetHeader().GetLogid()),
+	}
+	return p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), resp, opts...)
+}
+
+// sendToOthers 向除本节点外的validators发送消息并收集响应
+func (c *raftConsensus) sendToOthers(msg *xuperp2p.XuperMessage) []*xuperp2p.XuperMessage {
+	c.mutex.Lock()
+	var others []string
+	for _, v := range c.validators {
+		if v != c.address {
+			others = append(others, v)
+		}
+	}
+	c.mutex.Unlock()
+	if c.cCtx.Network == nil || len(others) == 0 {
+		return nil
+	}
+	ctx := &xctx.BaseCtx{XLog: c.log, Timer: timer.NewXTimer()}
+	responses, err := c.cCtx.Network.SendMessageWithResponse(ctx, msg, p2p.WithAccounts(others))
+	if err != nil {
+		c.log.Debug("consensus:raft:sendToOthers: send message error", "type", msg.GetHeader().GetType(), "err", err)
+	}
+	return responses
+}
@@ -0,0 +1,106 @@
+package raft
+
+import (
+	"encoding/json"
+	"strings"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/contract"
+)
+
+// methodEditValidators 成员变更，需由当前validators的多数派共同签名发起
+// 与raft的单节点成员变更一致，每次只能增加或删除一个validator，保证新旧集合的多数派必然相交
+// Args: validators::以;分隔的全部validators钱包地址
+func (c *raftConsensus) methodEditValidators(contractCtx contract.KContext) (*contract.Response, error) {
+	curVali, err := c.getContractValidators(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if !isAuthorized(curVali, contractCtx.AuthRequire()) {
+		return common.NewContractErrResponse(common.StatusBadRequest, ErrAuth.Error()), ErrAuth
+	}
+
+	var validators []string
+	for _, v := range strings.Split(string(contractCtx.Args()["validators"]), ";") {
+		if v == "" || find(v, validators) {
+			continue
+		}
+		validators = append(validators, v)
+	}
+	if len(validators) == 0 {
+		return common.NewContractErrResponse(common.StatusBadRequest, ErrTargetParam.Error()), ErrTargetParam
+	}
+	if membershipDiff(curVali, validators) > 1 {
+		return common.NewContractErrResponse(common.StatusBadRequest, ErrMembership.Error()), ErrMembership
+	}
+	rawBytes, err := json.Marshal(&ValidatorsInfo{
+		Validators: validators,
+	})
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(raftBucket, []byte(c.validatorsKey()), rawBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	delta := contract.Limits{
+		XFee: fee,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse(rawBytes), nil
+}
+
+// methodGetValidators 获取最新写入的validators，变更在3个区块后生效
+// Return: validators::validators钱包地址
+func (c *raftConsensus) methodGetValidators(contractCtx contract.KContext) (*contract.Response, error) {
+	validators, err := c.getContractValidators(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	jsonBytes, err := json.Marshal(&ValidatorsInfo{
+		Validators: validators,
+	})
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	delta := contract.Limits{
+		XFee: fee / 1000,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse(jsonBytes), nil
+}
+
+func (c *raftConsensus) getContractValidators(contractCtx contract.KContext) ([]string, error) {
+	res, err := contractCtx.Get(raftBucket, []byte(c.validatorsKey()))
+	if err != nil || res == nil {
+		return c.config.Validators, nil
+	}
+	return loadValidators(res)
+}
+
+// isAuthorized 判断交易的签名地址中是否包含validators的多数派，authRequire中的账户形式为account/address
+func isAuthorized(validators []string, authRequire []string) bool {
+	signed := make(map[string]bool)
+	for _, auth := range authRequire {
+		addr := auth[strings.LastIndex(auth, "/")+1:]
+		if find(addr, validators) {
+			signed[addr] = true
+		}
+	}
+	return len(signed) >= quorum(len(validators))
+}
+
+// membershipDiff 返回新旧validators集合中仅出现在一方的地址个数
+func membershipDiff(oldVali, newVali []string) int {
+	diff := 0
+	for _, v := range oldVali {
+		if !find(v, newVali) {
+			diff++
+		}
+	}
+	for _, v := range newVali {
+		if !find(v, oldVali) {
+			diff++
+		}
+	}
+	return diff
+}
@@ -0,0 +1,61 @@
+package raft
+
+import (
+	"testing"
+
+	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+)
+
+func NewM() map[string]map[string][]byte {
+	a := make(map[string]map[string][]byte)
+	return a
+}
+
+func NewEditArgs(validators string) map[string][]byte {
+	return map[string][]byte{
+		"validators": []byte(validators),
+	}
+}
+
+func TestMethodEditValidators(t *testing.T) {
+	// FakeKContext的AuthRequire为validatorB和validatorC，为3个validators的多数派
+	r, _ := newRaft(t, bmock.Miner, validatorB, validatorC)
+	m := NewM()
+	newVali := "akf7qunmeaqb51Wu418d6TyPKp4jdLdpV"
+	if _, err := r.methodEditValidators(kmock.NewFakeKContext(NewEditArgs(""), m)); err != ErrTargetParam {
+		t.Error("empty validators should be rejected", "err", err)
+	}
+	// 每次只能变更一个validator
+	twoChanges := validatorB + ";" + validatorC + ";" + newVali
+	if _, err := r.methodEditValidators(kmock.NewFakeKContext(NewEditArgs(twoChanges), m)); err != ErrMembership {
+		t.Error("two changes at once should be rejected", "err", err)
+	}
+	added := bmock.Miner + ";" + validatorB + ";" + validatorC + ";" + newVali
+	if _, err := r.methodEditValidators(kmock.NewFakeKContext(NewEditArgs(added), m)); err != nil {
+		t.Fatal("methodEditValidators error", err)
+	}
+	resp, err := r.methodGetValidators(kmock.NewFakeKContext(nil, m))
+	if err != nil {
+		t.Fatal("methodGetValidators error", err)
+	}
+	validators, err := loadValidators(resp.Body)
+	if err != nil || len(validators) != 4 || validators[3] != newVali {
+		t.Error("validators error", "validators", validators)
+	}
+	// 4个validators时validatorB和validatorC不足多数派
+	removed := validatorB + ";" + validatorC + ";" + newVali
+	if _, err := r.methodEditValidators(kmock.NewFakeKContext(NewEditArgs(removed), m)); err != ErrAuth {
+		t.Error("minority should not change validators", "err", err)
+	}
+}
+
+func TestIsAuthorized(t *testing.T) {
+	validators := []string{bmock.Miner, validatorB, validatorC}
+	if !isAuthorized(validators, []string{"XC1111111111111111@xuper/" + validatorB, validatorC}) {
+		t.Error("account address should be accepted")
+	}
+	if isAuthorized(validators, []string{validatorB, validatorB}) {
+		t.Error("duplicated address should be counted once")
+	}
+}
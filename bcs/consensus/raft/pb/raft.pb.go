@@ -0,0 +1,421 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: raft.proto
+
+package raftPb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// RaftSign 消息签名，签名者地址需要与消息中的发送者一致
+type RaftSign struct {
+	Address              string   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	PublicKey            string   `protobuf:"bytes,2,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
+	Sign                 []byte   `protobuf:"bytes,3,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RaftSign) Reset()         { *m = RaftSign{} }
+func (m *RaftSign) String() string { return proto.CompactTextString(m) }
+func (*RaftSign) ProtoMessage()    {}
+func (*RaftSign) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b042552c306ae59b, []int{0}
+}
+
+func (m *RaftSign) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RaftSign.Unmarshal(m, b)
+}
+func (m *RaftSign) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RaftSign.Marshal(b, m, deterministic)
+}
+func (m *RaftSign) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RaftSign.Merge(m, src)
+}
+func (m *RaftSign) XXX_Size() int {
+	return xxx_messageInfo_RaftSign.Size(m)
+}
+func (m *RaftSign) XXX_DiscardUnknown() {
+	xxx_messageInfo_RaftSign.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RaftSign proto.InternalMessageInfo
+
+func (m *RaftSign) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *RaftSign) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *RaftSign) GetSign() []byte {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+// RequestVote candidate发起选举时的拉票请求
+// LastTerm、LastHeight为candidate账本最高区块的任期和高度，用于判断candidate的账本是否足够新
+type RequestVote struct {
+	Term                 int64     `protobuf:"varint,1,opt,name=Term,proto3" json:"Term,omitempty"`
+	Candidate            string    `protobuf:"bytes,2,opt,name=Candidate,proto3" json:"Candidate,omitempty"`
+	LastTerm             int64     `protobuf:"varint,3,opt,name=LastTerm,proto3" json:"LastTerm,omitempty"`
+	LastHeight           int64     `protobuf:"varint,4,opt,name=LastHeight,proto3" json:"LastHeight,omitempty"`
+	Sign                 *RaftSign `protobuf:"bytes,5,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RequestVote) Reset()         { *m = RequestVote{} }
+func (m *RequestVote) String() string { return proto.CompactTextString(m) }
+func (*RequestVote) ProtoMessage()    {}
+func (*RequestVote) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b042552c306ae59b, []int{1}
+}
+
+func (m *RequestVote) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequestVote.Unmarshal(m, b)
+}
+func (m *RequestVote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequestVote.Marshal(b, m, deterministic)
+}
+func (m *RequestVote) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequestVote.Merge(m, src)
+}
+func (m *RequestVote) XXX_Size() int {
+	return xxx_messageInfo_RequestVote.Size(m)
+}
+func (m *RequestVote) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequestVote.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequestVote proto.InternalMessageInfo
+
+func (m *RequestVote) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RequestVote) GetCandidate() string {
+	if m != nil {
+		return m.Candidate
+	}
+	return ""
+}
+
+func (m *RequestVote) GetLastTerm() int64 {
+	if m != nil {
+		return m.LastTerm
+	}
+	return 0
+}
+
+func (m *RequestVote) GetLastHeight() int64 {
+	if m != nil {
+		return m.LastHeight
+	}
+	return 0
+}
+
+func (m *RequestVote) GetSign() *RaftSign {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+// RequestVoteResponse 拉票响应，Term为投票者当前任期，供candidate发现更高任期后退回follower
+// 同意的选票签名覆盖任期和candidate，leader在任期的第一个区块中附上多数派选票作为当选证明
+type RequestVoteResponse struct {
+	Term                 int64     `protobuf:"varint,1,opt,name=Term,proto3" json:"Term,omitempty"`
+	Voter                string    `protobuf:"bytes,2,opt,name=Voter,proto3" json:"Voter,omitempty"`
+	VoteGranted          bool      `protobuf:"varint,3,opt,name=VoteGranted,proto3" json:"VoteGranted,omitempty"`
+	Candidate            string    `protobuf:"bytes,4,opt,name=Candidate,proto3" json:"Candidate,omitempty"`
+	Sign                 *RaftSign `protobuf:"bytes,5,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RequestVoteResponse) Reset()         { *m = RequestVoteResponse{} }
+func (m *RequestVoteResponse) String() string { return proto.CompactTextString(m) }
+func (*RequestVoteResponse) ProtoMessage()    {}
+func (*RequestVoteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b042552c306ae59b, []int{2}
+}
+
+func (m *RequestVoteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequestVoteResponse.Unmarshal(m, b)
+}
+func (m *RequestVoteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequestVoteResponse.Marshal(b, m, deterministic)
+}
+func (m *RequestVoteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequestVoteResponse.Merge(m, src)
+}
+func (m *RequestVoteResponse) XXX_Size() int {
+	return xxx_messageInfo_RequestVoteResponse.Size(m)
+}
+func (m *RequestVoteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequestVoteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequestVoteResponse proto.InternalMessageInfo
+
+func (m *RequestVoteResponse) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RequestVoteResponse) GetVoter() string {
+	if m != nil {
+		return m.Voter
+	}
+	return ""
+}
+
+func (m *RequestVoteResponse) GetVoteGranted() bool {
+	if m != nil {
+		return m.VoteGranted
+	}
+	return false
+}
+
+func (m *RequestVoteResponse) GetCandidate() string {
+	if m != nil {
+		return m.Candidate
+	}
+	return ""
+}
+
+func (m *RequestVoteResponse) GetSign() *RaftSign {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+// AppendEntries leader心跳，区块通过账本同步复制，心跳仅携带leader账本最高区块信息
+type AppendEntries struct {
+	Term                 int64     `protobuf:"varint,1,opt,name=Term,proto3" json:"Term,omitempty"`
+	Leader               string    `protobuf:"bytes,2,opt,name=Leader,proto3" json:"Leader,omitempty"`
+	TipHeight            int64     `protobuf:"varint,3,opt,name=TipHeight,proto3" json:"TipHeight,omitempty"`
+	TipBlockid           []byte    `protobuf:"bytes,4,opt,name=TipBlockid,proto3" json:"TipBlockid,omitempty"`
+	CommitHeight         int64     `protobuf:"varint,5,opt,name=CommitHeight,proto3" json:"CommitHeight,omitempty"`
+	Sign                 *RaftSign `protobuf:"bytes,6,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *AppendEntries) Reset()         { *m = AppendEntries{} }
+func (m *AppendEntries) String() string { return proto.CompactTextString(m) }
+func (*AppendEntries) ProtoMessage()    {}
+func (*AppendEntries) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b042552c306ae59b, []int{3}
+}
+
+func (m *AppendEntries) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendEntries.Unmarshal(m, b)
+}
+func (m *AppendEntries) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendEntries.Marshal(b, m, deterministic)
+}
+func (m *AppendEntries) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendEntries.Merge(m, src)
+}
+func (m *AppendEntries) XXX_Size() int {
+	return xxx_messageInfo_AppendEntries.Size(m)
+}
+func (m *AppendEntries) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendEntries.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendEntries proto.InternalMessageInfo
+
+func (m *AppendEntries) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendEntries) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *AppendEntries) GetTipHeight() int64 {
+	if m != nil {
+		return m.TipHeight
+	}
+	return 0
+}
+
+func (m *AppendEntries) GetTipBlockid() []byte {
+	if m != nil {
+		return m.TipBlockid
+	}
+	return nil
+}
+
+func (m *AppendEntries) GetCommitHeight() int64 {
+	if m != nil {
+		return m.CommitHeight
+	}
+	return 0
+}
+
+func (m *AppendEntries) GetSign() *RaftSign {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+// AppendEntriesResponse 心跳响应，MatchHeight为follower账本最高区块高度
+type AppendEntriesResponse struct {
+	Term                 int64     `protobuf:"varint,1,opt,name=Term,proto3" json:"Term,omitempty"`
+	Follower             string    `protobuf:"bytes,2,opt,name=Follower,proto3" json:"Follower,omitempty"`
+	Success              bool      `protobuf:"varint,3,opt,name=Success,proto3" json:"Success,omitempty"`
+	MatchHeight          int64     `protobuf:"varint,4,opt,name=MatchHeight,proto3" json:"MatchHeight,omitempty"`
+	Leader               string    `protobuf:"bytes,5,opt,name=Leader,proto3" json:"Leader,omitempty"`
+	Sign                 *RaftSign `protobuf:"bytes,6,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *AppendEntriesResponse) Reset()         { *m = AppendEntriesResponse{} }
+func (m *AppendEntriesResponse) String() string { return proto.CompactTextString(m) }
+func (*AppendEntriesResponse) ProtoMessage()    {}
+func (*AppendEntriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b042552c306ae59b, []int{4}
+}
+
+func (m *AppendEntriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendEntriesResponse.Unmarshal(m, b)
+}
+func (m *AppendEntriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendEntriesResponse.Marshal(b, m, deterministic)
+}
+func (m *AppendEntriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendEntriesResponse.Merge(m, src)
+}
+func (m *AppendEntriesResponse) XXX_Size() int {
+	return xxx_messageInfo_AppendEntriesResponse.Size(m)
+}
+func (m *AppendEntriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendEntriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendEntriesResponse proto.InternalMessageInfo
+
+func (m *AppendEntriesResponse) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendEntriesResponse) GetFollower() string {
+	if m != nil {
+		return m.Follower
+	}
+	return ""
+}
+
+func (m *AppendEntriesResponse) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *AppendEntriesResponse) GetMatchHeight() int64 {
+	if m != nil {
+		return m.MatchHeight
+	}
+	return 0
+}
+
+func (m *AppendEntriesResponse) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *AppendEntriesResponse) GetSign() *RaftSign {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RaftSign)(nil), "raftPb.RaftSign")
+	proto.RegisterType((*RequestVote)(nil), "raftPb.RequestVote")
+	proto.RegisterType((*RequestVoteResponse)(nil), "raftPb.RequestVoteResponse")
+	proto.RegisterType((*AppendEntries)(nil), "raftPb.AppendEntries")
+	proto.RegisterType((*AppendEntriesResponse)(nil), "raftPb.AppendEntriesResponse")
+}
+
+func init() { proto.RegisterFile("raft.proto", fileDescriptor_b042552c306ae59b) }
+
+var fileDescriptor_b042552c306ae59b = []byte{
+	// 382 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0x5d, 0x6b, 0xf2, 0x30,
+	0x14, 0xc7, 0xc9, 0xa3, 0xed, 0x53, 0x8f, 0x3e, 0xf0, 0x90, 0xbd, 0x50, 0x64, 0x8c, 0x22, 0xbb,
+	0xf0, 0xca, 0x8b, 0xed, 0x13, 0x38, 0xd9, 0x0b, 0xcc, 0x81, 0x44, 0xf1, 0x3e, 0x36, 0x51, 0xc3,
+	0x6a, 0xd3, 0x35, 0x91, 0xb1, 0x8f, 0xb3, 0x8b, 0x7d, 0x90, 0xb1, 0x2f, 0x36, 0x92, 0xbe, 0xd8,
+	0x0e, 0x11, 0xef, 0xce, 0xf9, 0x27, 0x39, 0xfc, 0xfe, 0xff, 0x9e, 0x02, 0xa4, 0x74, 0xa9, 0x07,
+	0x49, 0x2a, 0xb5, 0xc4, 0xae, 0xa9, 0x27, 0x8b, 0xde, 0x1c, 0x3c, 0x42, 0x97, 0x7a, 0x2a, 0x56,
+	0x31, 0xf6, 0xe1, 0xef, 0x90, 0xb1, 0x94, 0x2b, 0xe5, 0xa3, 0x00, 0xf5, 0x5b, 0xa4, 0x68, 0xf1,
+	0x05, 0xb4, 0x26, 0xdb, 0x45, 0x24, 0xc2, 0x27, 0xfe, 0xee, 0xff, 0xb1, 0x67, 0x3b, 0x01, 0x63,
+	0x68, 0x9a, 0xf7, 0x7e, 0x23, 0x40, 0xfd, 0x0e, 0xb1, 0x75, 0xef, 0x03, 0x41, 0x9b, 0xf0, 0xd7,
+	0x2d, 0x57, 0x7a, 0x2e, 0x35, 0x37, 0x77, 0x66, 0x3c, 0xdd, 0xd8, 0xc1, 0x0d, 0x62, 0x6b, 0x33,
+	0x75, 0x44, 0x63, 0x26, 0x18, 0xd5, 0xbc, 0x98, 0x5a, 0x0a, 0xb8, 0x0b, 0xde, 0x98, 0x2a, 0x6d,
+	0x5f, 0x35, 0xec, 0xab, 0xb2, 0xc7, 0x97, 0x00, 0xa6, 0x7e, 0xe4, 0x62, 0xb5, 0xd6, 0x7e, 0xd3,
+	0x9e, 0x56, 0x14, 0x7c, 0x95, 0x13, 0x39, 0x01, 0xea, 0xb7, 0xaf, 0xff, 0x0f, 0x32, 0xb3, 0x83,
+	0xc2, 0x69, 0xce, 0xf8, 0x89, 0xe0, 0xa4, 0xc2, 0x48, 0xb8, 0x4a, 0x64, 0xac, 0xf6, 0xb3, 0x9e,
+	0x82, 0x63, 0xee, 0xa4, 0x39, 0x67, 0xd6, 0xe0, 0x00, 0xda, 0xa6, 0x78, 0x48, 0x69, 0xac, 0x39,
+	0xb3, 0x98, 0x1e, 0xa9, 0x4a, 0x75, 0x8f, 0xcd, 0xdf, 0x1e, 0x8f, 0xe3, 0xfc, 0x46, 0xf0, 0x6f,
+	0x98, 0x24, 0x3c, 0x66, 0x77, 0xb1, 0x4e, 0x05, 0x57, 0x7b, 0x09, 0xcf, 0xc1, 0x1d, 0x73, 0xca,
+	0x4a, 0xc4, 0xbc, 0x33, 0x04, 0x33, 0x91, 0xe4, 0x51, 0x65, 0x41, 0xee, 0x04, 0x93, 0xe4, 0x4c,
+	0x24, 0xb7, 0x91, 0x0c, 0x5f, 0x04, 0xb3, 0x80, 0x1d, 0x52, 0x51, 0x70, 0x0f, 0x3a, 0x23, 0xb9,
+	0xd9, 0x88, 0x22, 0x6b, 0xc7, 0x0e, 0xa8, 0x69, 0xa5, 0x0b, 0xf7, 0xa0, 0x8b, 0x2f, 0x04, 0x67,
+	0x35, 0x17, 0x07, 0xf3, 0xee, 0x82, 0x77, 0x2f, 0xa3, 0x48, 0xbe, 0x95, 0x7e, 0xca, 0xde, 0xec,
+	0xe9, 0x74, 0x1b, 0x86, 0x66, 0x4f, 0xb3, 0xc4, 0x8b, 0xd6, 0x7c, 0x8f, 0x67, 0xaa, 0xc3, 0x75,
+	0x6d, 0x31, 0xaa, 0x52, 0x25, 0x25, 0xa7, 0x96, 0xd2, 0x51, 0x1e, 0x16, 0xae, 0xfd, 0x79, 0x6e,
+	0x7e, 0x02, 0x00, 0x00, 0xff, 0xff, 0xc2, 0x92, 0xef, 0xfc, 0x4a, 0x03, 0x00, 0x00,
+}
@@ -0,0 +1,403 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+	"github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/consensus/def"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// raft为validators之间的崩溃容错共识，适用于validators均可信的联盟链
+// 日志复制映射到区块上: leader的CompeteMaster返回出块，区块共识存储中记录leader的任期，
+// follower通过CheckMinerMatch校验区块来自当前任期的leader，区块在validators间的复制沿用账本同步
+// 选举、心跳消息均由发送者签名，任期的第一个区块附上多数派的签名选票，区块校验只依赖链上数据
+func init() {
+	consensus.Register("raft", NewRaftConsensus)
+}
+
+type role int
+
+const (
+	follower role = iota
+	candidate
+	leader
+)
+
+func (r role) String() string {
+	switch r {
+	case candidate:
+		return "candidate"
+	case leader:
+		return "leader"
+	}
+	return "follower"
+}
+
+type raftConsensus struct {
+	cCtx        cctx.ConsensusCtx
+	config      *raftConfig
+	status      *RaftStatus
+	store       *stateStore
+	contract    contract.Manager
+	kMethod     map[string]contract.KernMethod
+	log         logs.Logger
+	address     string
+	startHeight int64
+
+	mutex      sync.Mutex
+	validators []string
+	role       role
+	term       int64
+	votedFor   string
+	leader     string
+	// lastHeard 最近一次收到当前任期leader心跳或区块、或投出选票的时间
+	lastHeard time.Time
+	// lastQuorum leader最近一次收到多数派心跳响应的时间，超过选举超时仍未收到时退回follower
+	lastQuorum time.Time
+	// timeout 本轮随机选举超时
+	timeout time.Duration
+	// votes 当选leader时收集的多数派签名选票，写入本任期的第一个区块作为当选证明
+	votes []*raftPb.RequestVoteResponse
+	// matchHeights leader记录的各validator账本高度，用于计算commitHeight
+	matchHeights map[string]int64
+	commitHeight int64
+
+	running     bool
+	quitCh      chan struct{}
+	subscribers []p2p.Subscriber
+}
+
+// NewRaftConsensus 初始化实例
+func NewRaftConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensus.ConsensusImplInterface {
+	if cCtx.XLog == nil {
+		return nil
+	}
+	if cCtx.Crypto == nil || cCtx.Address == nil {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: CryptoClient in context is nil")
+		return nil
+	}
+	if cCtx.Ledger == nil {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: Ledger in context is nil")
+		return nil
+	}
+	if cCfg.ConsensusName != "raft" {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: consensus name in config is wrong", "name", cCfg.ConsensusName)
+		return nil
+	}
+	config, err := buildConfigs([]byte(cCfg.Config))
+	if err != nil {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: raft parse config", "error", err)
+		return nil
+	}
+
+	c := &raftConsensus{
+		cCtx:         cCtx,
+		config:       config,
+		store:        newStateStore(cCtx.DataDir, config.Version),
+		contract:     cCtx.Contract,
+		log:          cCtx.XLog,
+		address:      cCtx.Address.Address,
+		startHeight:  cCfg.StartHeight,
+		validators:   config.Validators,
+		matchHeights: make(map[string]int64),
+	}
+	c.status = &RaftStatus{
+		Version:     config.Version,
+		StartHeight: cCfg.StartHeight,
+		Index:       cCfg.Index,
+		raft:        c,
+	}
+	c.kMethod = map[string]contract.KernMethod{
+		contractEditValidators: c.methodEditValidators,
+		contractGetValidators:  c.methodGetValidators,
+	}
+	c.resetTimeout()
+	cCtx.XLog.Debug("consensus:raft:NewRaftConsensus: create a raft instance successfully!")
+	return c
+}
+
+// CompeteMaster 返回是否为矿工以及是否需要进行SyncBlock
+// 仅当前任期的leader出块，且leader仍能与多数派通信；raft的选举保证leader的账本不旧于多数派，因此leader无需同步
+func (c *raftConsensus) CompeteMaster(height int64) (bool, bool, error) {
+	time.Sleep(time.Duration(c.config.Period) * time.Millisecond)
+
+	tipBlock := c.cCtx.Ledger.GetTipBlock()
+	c.updateValidators(tipBlock.GetHeight() + 1)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.role == leader && time.Since(c.lastQuorum) <= c.electionTimeout() {
+		c.log.Debug("consensus:raft:CompeteMaster", "isMiner", true, "term", c.term, "height", tipBlock.GetHeight())
+		return true, false, nil
+	}
+	c.log.Debug("consensus:raft:CompeteMaster", "isMiner", false, "role", c.role, "term", c.term, "leader", c.leader)
+	return false, false, nil
+}
+
+// CheckMinerMatch 查看block是否合法，校验只依赖链上数据，各节点对同一区块的结论一致
+// 区块相当于leader发送的AppendEntries: 任期沿链单调不减，同一任期内仅有一个leader，
+// 任期的第一个区块需附上多数派validators同意proposer当选的签名选票
+func (c *raftConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.BlockInterface) (bool, error) {
+	if err := c.verifySign(ctx, block); err != nil {
+		return false, err
+	}
+	proposer := string(block.GetProposer())
+	validators, err := c.getValidators(block.GetHeight())
+	if err != nil {
+		return false, err
+	}
+	if !find(proposer, validators) {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: proposer is not a validator", "proposer", proposer,
+			"validators", validators, "blockId", utils.F(block.GetBlockid()))
+		return false, ErrProposer
+	}
+	storage, err := c.getStorage(block)
+	if err != nil {
+		return false, err
+	}
+	// 共识切换后的第一个区块的父区块不属于raft，视为新任期的第一个区块
+	var preTerm int64
+	preBlock, err := c.cCtx.Ledger.QueryBlockHeader(block.GetPreHash())
+	if err == nil && preBlock.GetHeight() >= c.startHeight {
+		preStorage, err := c.getStorage(preBlock)
+		if err != nil {
+			return false, err
+		}
+		preTerm = preStorage.Term
+		if storage.Term < preTerm {
+			return false, ErrStaleTerm
+		}
+		if storage.Term == preTerm && proposer != string(preBlock.GetProposer()) {
+			return false, ErrTermLeader
+		}
+	}
+	if storage.Term > preTerm || preTerm == 0 {
+		if err := c.verifyElection(storage.Term, proposer, storage.Votes, validators); err != nil {
+			ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: first block of term lacks election votes", "err", err,
+				"term", storage.Term, "proposer", proposer, "blockId", utils.F(block.GetBlockid()))
+			return false, err
+		}
+	}
+	c.observeBlock(storage.Term, proposer)
+	return true, nil
+}
+
+// verifySign 检查区块id、proposer地址与公钥以及签名
+func (c *raftConsensus) verifySign(ctx xcontext.XContext, block cctx.BlockInterface) error {
+	bid, err := block.MakeBlockId()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(bid, block.GetBlockid()) {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: equal blockid error")
+		return ErrBlockSign
+	}
+	k, err := c.cCtx.Crypto.GetEcdsaPublicKeyFromJsonStr(block.GetPublicKey())
+	if err != nil {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: get ecdsa from block error", "error", err)
+		return err
+	}
+	addr, err := c.cCtx.Crypto.GetAddressFromPublicKey(k)
+	if err != nil {
+		return err
+	}
+	if addr != string(block.GetProposer()) {
+		return ErrAddress
+	}
+	valid, err := c.cCtx.Crypto.VerifyECDSA(k, block.GetSign(), block.GetBlockid())
+	if err != nil {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: verifyECDSA error", "error", err, "sign", block.GetSign())
+		return err
+	}
+	if !valid {
+		return ErrBlockSign
+	}
+	return nil
+}
+
+// observeBlock 按已校验区块中的任期更新本地状态，区块视同leader心跳，不影响区块校验结果
+// 追块时的历史区块任期较低属正常情况，不改变本地状态
+func (c *raftConsensus) observeBlock(term int64, proposer string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if term > c.term || (term == c.term && c.role != leader) {
+		c.becomeFollower(term, proposer)
+	}
+}
+
+// ProcessBeforeMiner 开始挖矿前进行相应的处理, 返回truncate目标(如需裁剪), 返回写consensusStorage, 返回err
+func (c *raftConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.role != leader {
+		return nil, nil, ErrNotLeader
+	}
+	storage := raftStorage{
+		Term:         c.term,
+		CommitHeight: c.commitHeight,
+	}
+	// 本任期的第一个区块附上当选证明
+	if lastTerm, _ := c.lastLog(); lastTerm < c.term {
+		storage.Votes = c.votes
+	}
+	b, err := json.Marshal(storage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, b, nil
+}
+
+// CalculateBlock 矿工挖矿时共识需要做的工作, 如PoW时共识需要完成存在性证明
+func (c *raftConsensus) CalculateBlock(block cctx.BlockInterface) error {
+	return nil
+}
+
+// ProcessConfirmBlock 用于确认块后进行相应的处理
+func (c *raftConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
+	return nil
+}
+
+// GetConsensusStatus 获取区块链共识信息
+func (c *raftConsensus) GetConsensusStatus() (consensus.ConsensusStatus, error) {
+	return c.status, nil
+}
+
+// Start 共识实例的启动逻辑，恢复本地任期和投票记录后开始选举计时
+func (c *raftConsensus) Start() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.running {
+		return nil
+	}
+	state, err := c.store.load()
+	if err != nil {
+		c.log.Error("consensus:raft:Start: load raft state error", "err", err)
+		return err
+	}
+	c.term = state.Term
+	c.votedFor = state.VotedFor
+	c.lastHeard = time.Now()
+
+	for method, f := range c.kMethod {
+		// 若有历史句柄，删除老句柄
+		c.contract.GetKernRegistry().UnregisterKernMethod(raftBucket, method)
+		c.contract.GetKernRegistry().RegisterKernMethod(raftBucket, method, f)
+	}
+	if err := c.registerToNetwork(); err != nil {
+		c.log.Error("consensus:raft:Start: register to network error", "err", err)
+		return err
+	}
+	c.quitCh = make(chan struct{})
+	c.running = true
+	go c.run(c.quitCh)
+	return nil
+}
+
+// Stop 共识实例的挂起逻辑
+func (c *raftConsensus) Stop() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.running {
+		return nil
+	}
+	for method := range c.kMethod {
+		c.contract.GetKernRegistry().UnregisterKernMethod(raftBucket, method)
+	}
+	c.unregisterToNetwork()
+	close(c.quitCh)
+	c.running = false
+	c.role = follower
+	c.leader = ""
+	return nil
+}
+
+// ParseConsensusStorage 共识占用blockinterface的专有存储，特定共识需要提供parse接口，在此作为接口高亮
+func (c *raftConsensus) ParseConsensusStorage(block cctx.BlockInterface) (interface{}, error) {
+	return c.getStorage(block)
+}
+
+func (c *raftConsensus) getStorage(block cctx.BlockInterface) (*raftStorage, error) {
+	b, err := block.GetConsensusStorage()
+	if err != nil {
+		return nil, err
+	}
+	return parseStorage(b)
+}
+
+// lastLog 返回本地账本最高区块的任期和高度，用于选举时比较账本新旧
+func (c *raftConsensus) lastLog() (int64, int64) {
+	tipBlock := c.cCtx.Ledger.GetTipBlock()
+	if tipBlock.GetHeight() < c.startHeight {
+		return 0, tipBlock.GetHeight()
+	}
+	storage, err := c.getStorage(tipBlock)
+	if err != nil {
+		return 0, tipBlock.GetHeight()
+	}
+	return storage.Term, tipBlock.GetHeight()
+}
+
+// getValidators 返回高度height的validators，validators变更在包含变更tx的block的后3个块后生效
+func (c *raftConsensus) getValidators(height int64) ([]string, error) {
+	if height < c.startHeight+3 {
+		return c.config.Validators, nil
+	}
+	b, err := c.cCtx.Ledger.QueryBlockHeaderByHeight(height - 3)
+	if err != nil {
+		c.log.Error("consensus:raft:getValidators: QueryBlockHeaderByHeight error", "err", err, "height", height-3)
+		return nil, err
+	}
+	reader, err := c.cCtx.Ledger.CreateSnapshot(b.GetBlockid())
+	if err != nil {
+		c.log.Error("consensus:raft:getValidators: CreateSnapshot error", "err", err)
+		return nil, err
+	}
+	res, err := reader.Get(raftBucket, []byte(c.validatorsKey()))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || res.PureData == nil || res.PureData.Value == nil {
+		return c.config.Validators, nil
+	}
+	return loadValidators(res.PureData.Value)
+}
+
+// updateValidators 刷新本地validators，本节点被移出时退回follower
+func (c *raftConsensus) updateValidators(height int64) {
+	validators, err := c.getValidators(height)
+	if err != nil || len(validators) == 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.validators = validators
+	if c.role != follower && !find(c.address, validators) {
+		c.log.Warn("consensus:raft:updateValidators: local node has been removed from validators", "validators", validators)
+		c.becomeFollower(c.term, "")
+	}
+}
+
+func (c *raftConsensus) validatorsKey() string {
+	return fmt.Sprintf("%d_%s", c.config.Version, validatorsKey)
+}
+
+func (c *raftConsensus) electionTimeout() time.Duration {
+	return time.Duration(c.config.ElectionTimeout) * time.Millisecond
+}
+
+// resetTimeout 在[ElectionTimeout, 2*ElectionTimeout)内随机选取本轮选举超时，避免多个节点同时发起选举
+func (c *raftConsensus) resetTimeout() {
+	c.timeout = c.electionTimeout() + time.Duration(rand.Int63n(int64(c.electionTimeout())))
+}
@@ -0,0 +1,484 @@
+package raft
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+	_ "github.com/xuperchain/xupercore/bcs/consensus/single"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/consensus/def"
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	xuperp2p "github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	validatorB = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
+	validatorC = "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"
+)
+
+func getRaftConsensusConf(validators ...string) string {
+	c := map[string]interface{}{
+		"version":          "1",
+		"validators":       validators,
+		"period":           "10",
+		"election_timeout": "20",
+	}
+	j, _ := json.Marshal(c)
+	return string(j)
+}
+
+func prepare(config string) (*cctx.ConsensusCtx, error) {
+	l := kmock.NewFakeLedger([]byte(config))
+	cCtx, err := bmock.NewConsensusCtx(l)
+	if err != nil {
+		return nil, err
+	}
+	cCtx.Ledger = l
+	return cCtx, nil
+}
+
+func getConfig(config string) def.ConsensusConfig {
+	return def.ConsensusConfig{
+		ConsensusName: "raft",
+		Config:        config,
+		StartHeight:   1,
+		Index:         0,
+	}
+}
+
+func newRaft(t *testing.T, validators ...string) (*raftConsensus, *cctx.ConsensusCtx) {
+	conf := getRaftConsensusConf(validators...)
+	cCtx, err := prepare(conf)
+	if err != nil {
+		t.Fatal("prepare error", err)
+	}
+	i := NewRaftConsensus(*cCtx, getConfig(conf))
+	if i == nil {
+		t.Fatal("NewRaftConsensus error", "conf", conf)
+	}
+	return i.(*raftConsensus), cCtx
+}
+
+func newStorage(term int64, votes ...*raftPb.RequestVoteResponse) []byte {
+	b, _ := json.Marshal(&raftStorage{Term: term, Votes: votes})
+	return b
+}
+
+// newAccount 由种子生成测试账户
+func newAccount(t *testing.T, cc cctx.CryptoClient, seed string) *cctx.Address {
+	key, err := cc.GenerateKeyBySeed([]byte(seed))
+	if err != nil {
+		t.Fatal("GenerateKeyBySeed error", err)
+	}
+	addr, err := cc.GetAddressFromPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal("GetAddressFromPublicKey error", err)
+	}
+	priKey, err := cc.GetEcdsaPrivateKeyJsonFormatStr(key)
+	if err != nil {
+		t.Fatal("GetEcdsaPrivateKeyJsonFormatStr error", err)
+	}
+	pubKey, err := cc.GetEcdsaPublicKeyJsonFormatStr(key)
+	if err != nil {
+		t.Fatal("GetEcdsaPublicKeyJsonFormatStr error", err)
+	}
+	return &cctx.Address{
+		Address:       addr,
+		PrivateKey:    key,
+		PrivateKeyStr: priKey,
+		PublicKey:     &key.PublicKey,
+		PublicKeyStr:  pubKey,
+	}
+}
+
+// newSigner 返回以account身份签名的raft实例，用于构造其他validator发出的消息
+func newSigner(r *raftConsensus, account *cctx.Address) *raftConsensus {
+	cCtx := r.cCtx
+	cCtx.Address = account
+	return &raftConsensus{cCtx: cCtx, config: r.config, address: account.Address}
+}
+
+// newVote 返回voter签名的同意candidate在term任期当选的选票
+func newVote(t *testing.T, r *raftConsensus, voter *cctx.Address, term int64, candidate string) *raftPb.RequestVoteResponse {
+	vote := &raftPb.RequestVoteResponse{
+		Term:        term,
+		Voter:       voter.Address,
+		VoteGranted: true,
+		Candidate:   candidate,
+	}
+	if err := newSigner(r, voter).signVote(vote); err != nil {
+		t.Fatal("signVote error", err)
+	}
+	return vote
+}
+
+func TestBuildConfigs(t *testing.T) {
+	config, err := buildConfigs([]byte(`{"version": "2", "validators": ["a", "b"]}`))
+	if err != nil {
+		t.Fatal("buildConfigs error", err)
+	}
+	if config.Version != 2 || config.Period != defaultPeriod || config.ElectionTimeout != defaultElectionTimeout {
+		t.Error("default config error", "config", config)
+	}
+	if _, err := buildConfigs([]byte(`{"version": "2"}`)); err != ErrEmptyValidators {
+		t.Error("empty validators should be rejected", "err", err)
+	}
+	if _, err := buildConfigs([]byte(`{"validators": ["a"], "election_timeout": "0"}`)); err == nil {
+		t.Error("election_timeout should be positive")
+	}
+}
+
+func TestNewRaftConsensus(t *testing.T) {
+	conf := getRaftConsensusConf(bmock.Miner)
+	cCtx, err := prepare(conf)
+	if err != nil {
+		t.Fatal("prepare error", err)
+	}
+	if i := NewRaftConsensus(*cCtx, getConfig(conf)); i == nil {
+		t.Error("NewRaftConsensus error")
+	}
+	wrong := getConfig(conf)
+	wrong.ConsensusName = "single"
+	if i := NewRaftConsensus(*cCtx, wrong); i != nil {
+		t.Error("NewRaftConsensus check name error")
+	}
+}
+
+func TestElection(t *testing.T) {
+	r, _ := newRaft(t, bmock.Miner)
+	dir, err := ioutil.TempDir("", "raft-state")
+	if err != nil {
+		t.Fatal("TempDir error", err)
+	}
+	defer os.RemoveAll(dir)
+	r.store = newStateStore(dir, r.config.Version)
+	if _, _, err := r.ProcessBeforeMiner(3, time.Now().UnixNano()); err != ErrNotLeader {
+		t.Error("follower should not mine", "err", err)
+	}
+	if err := r.Start(); err != nil {
+		t.Fatal("Start error", err)
+	}
+	defer r.Stop()
+	// 单个validator在选举超时后直接成为leader
+	isMiner := false
+	for i := 0; i < 100 && !isMiner; i++ {
+		isMiner, _, _ = r.CompeteMaster(3)
+	}
+	if !isMiner {
+		t.Fatal("single validator should become leader")
+	}
+	_, storageBytes, err := r.ProcessBeforeMiner(3, time.Now().UnixNano())
+	if err != nil {
+		t.Fatal("ProcessBeforeMiner error", err)
+	}
+	storage, err := parseStorage(storageBytes)
+	if err != nil || storage.Term < 1 {
+		t.Error("storage error", "storage", string(storageBytes))
+	}
+	// 任期的第一个区块附上当选证明
+	if err := r.verifyElection(storage.Term, bmock.Miner, storage.Votes, r.validators); err != nil {
+		t.Error("storage should carry election votes", "err", err)
+	}
+	status, _ := r.GetConsensusStatus()
+	info := StatusInfo{}
+	json.Unmarshal(status.GetCurrentValidatorsInfo(), &info)
+	if info.Miner != bmock.Miner || info.Role != "leader" || status.GetCurrentTerm() != storage.Term {
+		t.Error("status error", "info", info)
+	}
+	// 任期和投票记录已持久化
+	state, err := newStateStore(dir, r.config.Version).load()
+	if err != nil || state.Term != storage.Term || state.VotedFor != bmock.Miner {
+		t.Error("hard state error", "state", state, "err", err)
+	}
+}
+
+func TestRequestVote(t *testing.T) {
+	r, _ := newRaft(t, bmock.Miner, validatorB, validatorC)
+	resp := r.onRequestVote(&raftPb.RequestVote{Term: 1, Candidate: validatorB, LastHeight: 2})
+	if !resp.VoteGranted || resp.Term != 1 {
+		t.Fatal("vote should be granted", "resp", resp)
+	}
+	// 同一任期只投一票
+	resp = r.onRequestVote(&raftPb.RequestVote{Term: 1, Candidate: validatorC, LastHeight: 2})
+	if resp.VoteGranted {
+		t.Error("vote twice in one term")
+	}
+	// 账本落后的candidate不能获得选票
+	resp = r.onRequestVote(&raftPb.RequestVote{Term: 2, Candidate: validatorC, LastHeight: 1})
+	if resp.VoteGranted || resp.Term != 2 {
+		t.Error("candidate with stale ledger should be rejected", "resp", resp)
+	}
+	resp = r.onRequestVote(&raftPb.RequestVote{Term: 2, Candidate: validatorC, LastHeight: 2})
+	if !resp.VoteGranted {
+		t.Error("vote should be granted in new term", "resp", resp)
+	}
+	// 非validator不能参选
+	resp = r.onRequestVote(&raftPb.RequestVote{Term: 3, Candidate: "unknown", LastHeight: 2})
+	if resp.VoteGranted || resp.Term != 2 {
+		t.Error("non-validator should be rejected", "resp", resp)
+	}
+}
+
+func TestAppendEntries(t *testing.T) {
+	r, _ := newRaft(t, bmock.Miner, validatorB, validatorC)
+	resp := r.onAppendEntries(&raftPb.AppendEntries{Term: 3, Leader: validatorB, TipHeight: 2})
+	if !resp.Success || resp.Term != 3 || resp.MatchHeight != 2 {
+		t.Fatal("heartbeat should be accepted", "resp", resp)
+	}
+	if r.role != follower || r.leader != validatorB {
+		t.Error("local node should follow the leader", "role", r.role, "leader", r.leader)
+	}
+	// 收到leader心跳期间拒绝拉票
+	vote := r.onRequestVote(&raftPb.RequestVote{Term: 4, Candidate: validatorC, LastHeight: 2})
+	if vote.VoteGranted || vote.Term != 3 {
+		t.Error("vote should be rejected while leader is alive", "resp", vote)
+	}
+	resp = r.onAppendEntries(&raftPb.AppendEntries{Term: 2, Leader: validatorC})
+	if resp.Success || resp.Term != 3 {
+		t.Error("stale heartbeat should be rejected", "resp", resp)
+	}
+}
+
+func TestCheckMinerMatch(t *testing.T) {
+	cc, _, err := bmock.NewCryptoClient()
+	if err != nil {
+		t.Fatal("NewCryptoClient error", err)
+	}
+	accountB := newAccount(t, cc, "raft-validator-b-seed-0123456789")
+	accountC := newAccount(t, cc, "raft-validator-c-seed-0123456789")
+	r, cCtx := newRaft(t, bmock.Miner, accountB.Address, accountC.Address)
+	l := cCtx.Ledger.(*kmock.FakeLedger)
+	l.SetConsensusStorage(2, newStorage(1))
+	selfVote := newVote(t, r, cCtx.Address, 2, bmock.Miner)
+	voteB := newVote(t, r, accountB, 2, bmock.Miner)
+
+	// 新任期的第一个区块需附上多数派的选票
+	b3, _ := bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, newStorage(2))
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrElection {
+		t.Error("block without votes should be rejected", "err", err)
+	}
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, newStorage(2, selfVote, selfVote))
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrElection {
+		t.Error("duplicated votes should be counted once", "err", err)
+	}
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address,
+		newStorage(2, selfVote, newVote(t, r, accountB, 1, bmock.Miner)))
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrElection {
+		t.Error("votes of other term should not be counted", "err", err)
+	}
+	forged := &raftPb.RequestVoteResponse{Term: 2, Voter: accountB.Address, VoteGranted: true, Candidate: bmock.Miner}
+	if err := r.signVote(forged); err != nil {
+		t.Fatal("signVote error", err)
+	}
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, newStorage(2, selfVote, forged))
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrMsgSign {
+		t.Error("vote not signed by its voter should be rejected", "err", err)
+	}
+
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, newStorage(2, selfVote, voteB))
+	if ok, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); !ok || err != nil {
+		t.Fatal("CheckMinerMatch error", "err", err)
+	}
+	if r.term != 2 || r.leader != bmock.Miner {
+		t.Error("block of higher term should update local state", "term", r.term, "leader", r.leader)
+	}
+	// 校验只依赖链上数据，本地已进入更高任期的节点对同一区块的结论不变
+	r.observeBlock(3, accountB.Address)
+	if ok, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); !ok || err != nil {
+		t.Error("verdict should not depend on local state", "err", err)
+	}
+	other, otherCtx := newRaft(t, bmock.Miner, accountB.Address, accountC.Address)
+	otherCtx.Ledger.(*kmock.FakeLedger).SetConsensusStorage(2, newStorage(1))
+	if ok, err := other.CheckMinerMatch(&cCtx.BaseCtx, b3); !ok || err != nil {
+		t.Error("verdict should not depend on local state", "err", err)
+	}
+
+	// 任期沿链不能减小
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, newStorage(0))
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrStaleTerm {
+		t.Error("term decrease should be rejected", "err", err)
+	}
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, []byte("invalid"))
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrStorage {
+		t.Error("invalid storage should be rejected", "err", err)
+	}
+	// 同一任期内的后续区块无需选票
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, newStorage(1))
+	if ok, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); !ok || err != nil {
+		t.Error("block in the same term should be accepted", "err", err)
+	}
+	// 同一任期已由其他leader出块
+	b2, _ := l.QueryBlockHeaderByHeight(2)
+	b2.(*kmock.FakeBlock).SetProposer(accountB.Address)
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrTermLeader {
+		t.Error("second leader in one term should be rejected", "err", err)
+	}
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, accountB, newStorage(1))
+	if ok, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); !ok || err != nil {
+		t.Error("block of the term leader should be accepted", "err", err)
+	}
+	// 非validator出块
+	r, cCtx = newRaft(t, accountB.Address, accountC.Address)
+	cCtx.Ledger.(*kmock.FakeLedger).SetConsensusStorage(2, newStorage(1))
+	b3, _ = bmock.NewBlockWithStorage(3, cCtx.Crypto, cCtx.Address, newStorage(2, selfVote, voteB))
+	if _, err := r.CheckMinerMatch(&cCtx.BaseCtx, b3); err != ErrProposer {
+		t.Error("non-validator proposer should be rejected", "err", err)
+	}
+}
+
+func TestMessageSign(t *testing.T) {
+	cc, _, err := bmock.NewCryptoClient()
+	if err != nil {
+		t.Fatal("NewCryptoClient error", err)
+	}
+	accountB := newAccount(t, cc, "raft-validator-b-seed-0123456789")
+	accountC := newAccount(t, cc, "raft-validator-c-seed-0123456789")
+	r, cCtx := newRaft(t, bmock.Miner, accountB.Address, accountC.Address)
+	dir, err := ioutil.TempDir("", "raft-state")
+	if err != nil {
+		t.Fatal("TempDir error", err)
+	}
+	defer os.RemoveAll(dir)
+	r.store = newStateStore(dir, r.config.Version)
+	signerB := newSigner(r, accountB)
+
+	newMsg := func(msgType xuperp2p.XuperMessage_MessageType, req proto.Message) *xuperp2p.XuperMessage {
+		return p2p.NewMessage(msgType, req, p2p.WithBCName(cCtx.BcName))
+	}
+	// 拉票请求需由candidate本人签名
+	req := &raftPb.RequestVote{Term: 1, Candidate: accountB.Address, LastHeight: 2}
+	if _, err := r.handleRequestVote(&cCtx.BaseCtx, newMsg(xuperp2p.XuperMessage_RAFT_REQUEST_VOTE, req)); err != ErrMsgSign {
+		t.Error("unsigned request vote should be rejected", "err", err)
+	}
+	forged := &raftPb.RequestVote{Term: 1, Candidate: accountC.Address, LastHeight: 2}
+	forged.Sign, _ = signerB.sign(signerB.requestVoteDigest(forged))
+	if _, err := r.handleRequestVote(&cCtx.BaseCtx, newMsg(xuperp2p.XuperMessage_RAFT_REQUEST_VOTE, forged)); err != ErrMsgSign {
+		t.Error("request vote signed by other validator should be rejected", "err", err)
+	}
+	if err := signerB.signRequestVote(req); err != nil {
+		t.Fatal("signRequestVote error", err)
+	}
+	respMsg, err := r.handleRequestVote(&cCtx.BaseCtx, newMsg(xuperp2p.XuperMessage_RAFT_REQUEST_VOTE, req))
+	if err != nil {
+		t.Fatal("handleRequestVote error", err)
+	}
+	vote := &raftPb.RequestVoteResponse{}
+	if err := p2p.Unmarshal(respMsg, vote); err != nil {
+		t.Fatal("unmarshal vote error", err)
+	}
+	if !vote.VoteGranted || vote.Candidate != accountB.Address || signerB.verifyVote(vote) != nil {
+		t.Error("vote should be granted and signed by voter", "vote", vote)
+	}
+	// 选票可作为当选证明
+	if err := r.verifyElection(1, accountB.Address, []*raftPb.RequestVoteResponse{vote, newVote(t, r, accountB, 1, accountB.Address)},
+		r.validators); err != nil {
+		t.Error("votes should prove the election", "err", err)
+	}
+
+	// 心跳需由leader本人签名
+	heartbeat := &raftPb.AppendEntries{Term: 1, Leader: accountB.Address, TipHeight: 2}
+	if _, err := r.handleAppendEntries(&cCtx.BaseCtx, newMsg(xuperp2p.XuperMessage_RAFT_APPEND_ENTRIES, heartbeat)); err != ErrMsgSign {
+		t.Error("unsigned heartbeat should be rejected", "err", err)
+	}
+	if err := r.signAppendEntries(heartbeat); err != nil {
+		t.Fatal("signAppendEntries error", err)
+	}
+	if _, err := r.handleAppendEntries(&cCtx.BaseCtx, newMsg(xuperp2p.XuperMessage_RAFT_APPEND_ENTRIES, heartbeat)); err != ErrMsgSign {
+		t.Error("heartbeat signed by other validator should be rejected", "err", err)
+	}
+	if err := signerB.signAppendEntries(heartbeat); err != nil {
+		t.Fatal("signAppendEntries error", err)
+	}
+	respMsg, err = r.handleAppendEntries(&cCtx.BaseCtx, newMsg(xuperp2p.XuperMessage_RAFT_APPEND_ENTRIES, heartbeat))
+	if err != nil {
+		t.Fatal("handleAppendEntries error", err)
+	}
+	ack := &raftPb.AppendEntriesResponse{}
+	if err := p2p.Unmarshal(respMsg, ack); err != nil {
+		t.Fatal("unmarshal ack error", err)
+	}
+	if !ack.Success || ack.Leader != accountB.Address || signerB.verifyAppendEntriesResp(ack) != nil {
+		t.Error("heartbeat should be accepted and acked with sign", "ack", ack)
+	}
+	// 篡改签名后的响应无效
+	ack.MatchHeight++
+	if err := signerB.verifyAppendEntriesResp(ack); err != ErrMsgSign {
+		t.Error("tampered ack should be rejected", "err", err)
+	}
+}
+
+func TestSwitchFromSingle(t *testing.T) {
+	singleConf, _ := json.Marshal(map[string]string{
+		"version": "0",
+		"miner":   bmock.Miner,
+		"period":  "10",
+	})
+	genesisConf, _ := json.Marshal(def.ConsensusConfig{
+		ConsensusName: "single",
+		Config:        string(singleConf),
+	})
+	cCtx, err := prepare(string(genesisConf))
+	if err != nil {
+		t.Fatal("prepare error", err)
+	}
+	pc, err := consensus.NewPluggableConsensus(*cCtx)
+	if err != nil {
+		t.Fatal("NewPluggableConsensus error", err)
+	}
+
+	// 在高度30切换为raft
+	args, _ := json.Marshal(map[string]interface{}{
+		"name": "raft",
+		"config": map[string]interface{}{
+			"version":          "1",
+			"validators":       []string{bmock.Miner},
+			"period":           "10",
+			"election_timeout": "20",
+		},
+	})
+	update := cCtx.Contract.GetKernRegistry().(*kmock.FakeRegistry).M["updateConsensus"]
+	fakeCtx := kmock.NewFakeKContext(map[string][]byte{
+		"args":   args,
+		"height": []byte(strconv.FormatInt(29, 10)),
+	}, make(map[string]map[string][]byte))
+	if _, err := update(fakeCtx); err != nil {
+		t.Fatal("updateConsensus error", err)
+	}
+	l := cCtx.Ledger.(*kmock.FakeLedger)
+	for h := 3; h <= 29; h++ {
+		l.Put(kmock.NewBlock(h))
+	}
+	if err := pc.SwitchConsensus(29); err != nil {
+		t.Fatal("SwitchConsensus error", err)
+	}
+	status, err := pc.GetConsensusStatus()
+	if err != nil || status.GetConsensusName() != "raft" || status.GetConsensusBeginInfo() != 30 {
+		t.Fatal("consensus should be switched to raft", "err", err)
+	}
+
+	// 切换后的第一个区块父区块为single出块，无raft存储
+	isMiner := false
+	for i := 0; i < 100 && !isMiner; i++ {
+		isMiner, _, _ = pc.CompeteMaster(30)
+	}
+	if !isMiner {
+		t.Fatal("raft leader should be elected after switch")
+	}
+	_, storage, err := pc.ProcessBeforeMiner(30, time.Now().UnixNano())
+	if err != nil {
+		t.Fatal("ProcessBeforeMiner error", err)
+	}
+	b30, _ := bmock.NewBlockWithStorage(30, cCtx.Crypto, cCtx.Address, storage)
+	if ok, err := pc.CheckMinerMatch(&cCtx.BaseCtx, b30); !ok || err != nil {
+		t.Error("CheckMinerMatch error", "err", err)
+	}
+}
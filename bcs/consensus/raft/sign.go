@@ -0,0 +1,150 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
+)
+
+// 本文件实现raft消息的签名和校验，签名者需要与消息中声明的发送者一致
+// 同意的选票同时作为leader当选的证明写入任期的第一个区块，区块校验因此只依赖链上数据
+
+const (
+	requestVoteMsgPrefix       = "raft-request-vote"
+	voteMsgPrefix              = "raft-vote"
+	appendEntriesMsgPrefix     = "raft-append-entries"
+	appendEntriesRespMsgPrefix = "raft-append-entries-response"
+)
+
+// makeMsgDigest 计算消息摘要，加入消息类型前缀、链名和共识版本，避免签名被挪用到其他消息或其他链
+func (c *raftConsensus) makeMsgDigest(prefix string, fields ...interface{}) ([]byte, error) {
+	var msgBuf bytes.Buffer
+	encoder := json.NewEncoder(&msgBuf)
+	for _, field := range append([]interface{}{prefix, c.cCtx.BcName, c.config.Version}, fields...) {
+		if err := encoder.Encode(field); err != nil {
+			return nil, err
+		}
+	}
+	hasher := c.cCtx.Hasher
+	if hasher == nil {
+		hasher = hash.DefaultHasher
+	}
+	return hasher.DoubleHash(msgBuf.Bytes()), nil
+}
+
+func (c *raftConsensus) requestVoteDigest(req *raftPb.RequestVote) ([]byte, error) {
+	return c.makeMsgDigest(requestVoteMsgPrefix, req.Term, req.Candidate, req.LastTerm, req.LastHeight)
+}
+
+func (c *raftConsensus) voteDigest(vote *raftPb.RequestVoteResponse) ([]byte, error) {
+	return c.makeMsgDigest(voteMsgPrefix, vote.Term, vote.Candidate, vote.Voter, vote.VoteGranted)
+}
+
+func (c *raftConsensus) appendEntriesDigest(req *raftPb.AppendEntries) ([]byte, error) {
+	return c.makeMsgDigest(appendEntriesMsgPrefix, req.Term, req.Leader, req.TipHeight, req.TipBlockid, req.CommitHeight)
+}
+
+func (c *raftConsensus) appendEntriesRespDigest(ack *raftPb.AppendEntriesResponse) ([]byte, error) {
+	return c.makeMsgDigest(appendEntriesRespMsgPrefix, ack.Term, ack.Leader, ack.Follower, ack.Success, ack.MatchHeight)
+}
+
+// sign 使用本节点私钥对摘要签名
+func (c *raftConsensus) sign(digest []byte, err error) (*raftPb.RaftSign, error) {
+	if err != nil {
+		return nil, err
+	}
+	sign, err := c.cCtx.Crypto.SignECDSA(c.cCtx.Address.PrivateKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	return &raftPb.RaftSign{
+		Address:   c.address,
+		PublicKey: c.cCtx.Address.PublicKeyStr,
+		Sign:      sign,
+	}, nil
+}
+
+// verify 校验签名者为sender，且公钥与地址匹配、签名有效
+func (c *raftConsensus) verify(sign *raftPb.RaftSign, sender string, digest []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	if sign == nil || sign.GetAddress() != sender {
+		return ErrMsgSign
+	}
+	k, err := c.cCtx.Crypto.GetEcdsaPublicKeyFromJsonStr(sign.GetPublicKey())
+	if err != nil {
+		return ErrMsgSign
+	}
+	addr, err := c.cCtx.Crypto.GetAddressFromPublicKey(k)
+	if err != nil || addr != sender {
+		return ErrMsgSign
+	}
+	valid, err := c.cCtx.Crypto.VerifyECDSA(k, sign.GetSign(), digest)
+	if err != nil || !valid {
+		return ErrMsgSign
+	}
+	return nil
+}
+
+func (c *raftConsensus) signRequestVote(req *raftPb.RequestVote) (err error) {
+	req.Sign, err = c.sign(c.requestVoteDigest(req))
+	return err
+}
+
+func (c *raftConsensus) verifyRequestVote(req *raftPb.RequestVote) error {
+	digest, err := c.requestVoteDigest(req)
+	return c.verify(req.GetSign(), req.GetCandidate(), digest, err)
+}
+
+func (c *raftConsensus) signVote(vote *raftPb.RequestVoteResponse) (err error) {
+	vote.Sign, err = c.sign(c.voteDigest(vote))
+	return err
+}
+
+func (c *raftConsensus) verifyVote(vote *raftPb.RequestVoteResponse) error {
+	digest, err := c.voteDigest(vote)
+	return c.verify(vote.GetSign(), vote.GetVoter(), digest, err)
+}
+
+func (c *raftConsensus) signAppendEntries(req *raftPb.AppendEntries) (err error) {
+	req.Sign, err = c.sign(c.appendEntriesDigest(req))
+	return err
+}
+
+func (c *raftConsensus) verifyAppendEntries(req *raftPb.AppendEntries) error {
+	digest, err := c.appendEntriesDigest(req)
+	return c.verify(req.GetSign(), req.GetLeader(), digest, err)
+}
+
+func (c *raftConsensus) signAppendEntriesResp(ack *raftPb.AppendEntriesResponse) (err error) {
+	ack.Sign, err = c.sign(c.appendEntriesRespDigest(ack))
+	return err
+}
+
+func (c *raftConsensus) verifyAppendEntriesResp(ack *raftPb.AppendEntriesResponse) error {
+	digest, err := c.appendEntriesRespDigest(ack)
+	return c.verify(ack.GetSign(), ack.GetFollower(), digest, err)
+}
+
+// verifyElection 校验votes中有多数派validators签名同意proposer成为term任期的leader，同一validator只计一次
+func (c *raftConsensus) verifyElection(term int64, proposer string, votes []*raftPb.RequestVoteResponse,
+	validators []string) error {
+	granted := make(map[string]bool)
+	for _, vote := range votes {
+		if !vote.GetVoteGranted() || vote.GetTerm() != term || vote.GetCandidate() != proposer ||
+			!find(vote.GetVoter(), validators) || granted[vote.GetVoter()] {
+			continue
+		}
+		if err := c.verifyVote(vote); err != nil {
+			return err
+		}
+		granted[vote.GetVoter()] = true
+	}
+	if len(granted) < quorum(len(validators)) {
+		return ErrElection
+	}
+	return nil
+}
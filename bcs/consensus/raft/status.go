@@ -0,0 +1,61 @@
+package raft
+
+import (
+	"encoding/json"
+)
+
+type StatusInfo struct {
+	Validators   []string `json:"validators"`
+	Miner        string   `json:"miner"`
+	Role         string   `json:"role"`
+	CommitHeight int64    `json:"commit_height"`
+}
+
+// RaftStatus 实现了ConsensusStatus接口
+type RaftStatus struct {
+	Version     int64 `json:"version"`
+	StartHeight int64 `json:"startHeight"`
+	Index       int   `json:"index"`
+	raft        *raftConsensus
+}
+
+// 获取共识版本号
+func (s *RaftStatus) GetVersion() int64 {
+	return s.Version
+}
+
+// 共识起始高度
+func (s *RaftStatus) GetConsensusBeginInfo() int64 {
+	return s.StartHeight
+}
+
+// 获取共识item所在consensus slice中的index
+func (s *RaftStatus) GetStepConsensusIndex() int {
+	return s.Index
+}
+
+// 获取共识类型
+func (s *RaftStatus) GetConsensusName() string {
+	return "raft"
+}
+
+// 获取当前raft任期
+func (s *RaftStatus) GetCurrentTerm() int64 {
+	s.raft.mutex.Lock()
+	defer s.raft.mutex.Unlock()
+	return s.raft.term
+}
+
+// 获取当前validators及leader信息
+func (s *RaftStatus) GetCurrentValidatorsInfo() []byte {
+	s.raft.mutex.Lock()
+	defer s.raft.mutex.Unlock()
+	i := StatusInfo{
+		Validators:   s.raft.validators,
+		Miner:        s.raft.leader,
+		Role:         s.raft.role.String(),
+		CommitHeight: s.raft.commitHeight,
+	}
+	b, _ := json.Marshal(i)
+	return b
+}
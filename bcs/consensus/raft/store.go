@@ -0,0 +1,79 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// hardState 为raft需要在投票前持久化的状态，节点重启后需恢复，否则可能在同一任期重复投票
+type hardState struct {
+	Term     int64  `json:"term"`
+	VotedFor string `json:"votedFor"`
+}
+
+// stateStore 使用链数据目录下的单个文件存储hardState，不同版本的raft实例使用不同文件
+// 写入时先写临时文件再rename，保证文件内容完整；path为空时不持久化
+type stateStore struct {
+	path string
+}
+
+func newStateStore(dataDir string, version int64) *stateStore {
+	if dataDir == "" {
+		return &stateStore{}
+	}
+	return &stateStore{
+		path: filepath.Join(dataDir, fmt.Sprintf("raft_state_%d.json", version)),
+	}
+}
+
+// load 读取本地状态，文件不存在时返回零值状态
+func (s *stateStore) load() (*hardState, error) {
+	state := &hardState{}
+	if s.path == "" {
+		return state, nil
+	}
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *stateStore) save(state *hardState) error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 )
 
@@ -21,12 +23,29 @@ const (
 	contractVote              = "voteCandidate"
 	contractRevokeVote        = "revokeVote"
 	contractGetTdposInfos     = "getTdposInfos"
+	contractSubmitEvidence    = evidence.MethodSubmitEvidence
+	contractClaimReward       = "claimVoterReward"
+	contractGetReward         = "getVoterReward"
 
 	tdposBucket   = "$tdpos"
 	xposBucket    = "$xpos"
 	nominateKey   = "nominate"
 	voteKeyPrefix = "vote_"
 	revokeKey     = "revoke"
+	// 双签惩罚相关key
+	jailKey           = "jail"
+	evidenceKeyPrefix = "evidence_"
+	// 投票收益分成相关key
+	commissionKey   = "commission"
+	rewardKeyPrefix = "reward_"
+	settledKey      = "settled"
+
+	// MaxCommission 佣金比例的单位，即commission为百分比
+	MaxCommission = 100
+	// 未声明佣金比例的候选人保留全部奖励
+	defaultCommission = MaxCommission
+	// 未配置max_settle_terms时每次领取最多结算的轮数
+	defaultMaxSettleTerms = 10
 
 	NOMINATETYPE = "nominate"
 	VOTETYPE     = "vote"
@@ -47,6 +66,11 @@ var (
 	ErrValueNotFound    = errors.New("value not found, please check your input parameters")
 	ErrSchedule         = errors.New("minerScheduling overflow")
 	ErrNotFound         = errors.New("Key not found")
+	ErrSlashDisabled    = errors.New("slash config is not set")
+	ErrRewardDisabled   = errors.New("reward config is not set")
+	ErrCommission       = errors.New("commission should be an integer in [0, 100]")
+	ErrSettleHeight     = errors.New("settle height is invalid")
+	ErrNoAward          = errors.New("ledger can not calculate block award")
 )
 
 // tdpos 共识机制的配置
@@ -69,6 +93,20 @@ type tdposConfig struct {
 	// 系统指定的前两轮的候选人名单
 	InitProposer map[string][]string `json:"init_proposer"`
 	EnableBFT    map[string]bool     `json:"bft_config,omitempty"`
+	// 双签惩罚策略，存在即开启证据提交
+	SlashConfig *evidence.Policy `json:"slash_config,omitempty"`
+	// 投票收益分成配置，存在即开启
+	RewardConfig *rewardConfig `json:"reward_config,omitempty"`
+	// leader替换策略，存在即开启，轮值到最近未出块的proposer时由顺位下一个proposer代为出块
+	Reputation *reputation.Config `json:"reputation,omitempty"`
+}
+
+// rewardConfig 投票收益分成配置
+// 开启后区块奖励全部转入共识合约bucket，每轮结束后按候选人声明的佣金比例分给proposer，
+// 其余部分按选出该轮候选人时的投票快照分给该候选人的投票者，由proposer和投票者自行领取
+type rewardConfig struct {
+	// MaxSettleTerms 每次领取时最多结算的轮数，用于限制单笔交易的执行开销
+	MaxSettleTerms int64 `json:"max_settle_terms,omitempty"`
 }
 
 func (tp *tdposConsensus) needSync() bool {
@@ -146,6 +184,9 @@ func buildConfigs(input []byte) (*tdposConfig, error) {
 	type tempStruct struct {
 		InitProposer map[string][]string `json:"init_proposer"`
 		EnableBFT    map[string]bool     `json:"bft_config,omitempty"`
+		SlashConfig  *evidence.Policy    `json:"slash_config,omitempty"`
+		RewardConfig *rewardConfig       `json:"reward_config,omitempty"`
+		Reputation   *reputation.Config  `json:"reputation,omitempty"`
 	}
 	var temp tempStruct
 	err = json.Unmarshal(input, &temp)
@@ -165,6 +206,27 @@ func buildConfigs(input []byte) (*tdposConfig, error) {
 
 	tdposCfg.InitProposer = temp.InitProposer
 	tdposCfg.EnableBFT = temp.EnableBFT
+	if temp.SlashConfig != nil {
+		if err := temp.SlashConfig.Validate(); err != nil {
+			return nil, err
+		}
+		tdposCfg.SlashConfig = temp.SlashConfig
+	}
+	if temp.RewardConfig != nil {
+		if temp.RewardConfig.MaxSettleTerms < 0 {
+			return nil, fmt.Errorf("reward_config max_settle_terms set error")
+		}
+		if temp.RewardConfig.MaxSettleTerms == 0 {
+			temp.RewardConfig.MaxSettleTerms = defaultMaxSettleTerms
+		}
+		tdposCfg.RewardConfig = temp.RewardConfig
+	}
+	if temp.Reputation != nil {
+		if err := temp.Reputation.Validate(); err != nil {
+			return nil, err
+		}
+		tdposCfg.Reputation = temp.Reputation
+	}
 
 	return tdposCfg, nil
 }
@@ -3,10 +3,13 @@ package tdpos
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
 
 	"github.com/xuperchain/xupercore/kernel/contract"
@@ -21,12 +24,26 @@ import (
 //                value = <${from_addr}, ${ballot_count}>
 // 3. 撤销动作相关  key = "revoke_${candi_addr}"
 //                value = <${from_addr}, <(${TYPE_VOTE/TYPE_NOMINATE}, ${ballot_count})>>
+// 4. 双签禁闭相关  key = "jail"
+//                value = <${candi_addr}, ${jail_until_term}>
+// 5. 双签证据相关  key = "evidence_${type}_${offender}_${slot}"
+//                value = evidence.Record
+// 6. 佣金比例相关  key = "commission"
+//                value = <${candi_addr}, ${commission}>
+// 7. 待领取奖励相关 key = "reward_${addr}"
+//                value = ${amount}
+// 8. 奖励结算相关  key = "settled"
+//                value = ${settled_height}
 // 以上所有的数据读通过快照读取, 快照读取的是当前区块的前三个区块的值
 // 以上所有数据都更新到各自的链上存储中，直接走三代合约写入，去除原Finalize的最后写入更新机制
 // 由于三代合约读写集限制，不能针对同一个ExeInput触发并行操作，后到的tx将会出现读写集错误，即针对同一个大key的操作同一个区块只能顺序执行
 // 撤销走的是proposal合约，但目前看来proposal没有指明height
 
 // runNominateCandidate 执行提名候选人
+// Args:
+//	candidate::候选人钱包地址
+//	amount::提名质押数
+//	commission::可选，开启reward_config时候选人保留的奖励百分比，未声明时保留全部奖励
 func (tp *tdposConsensus) runNominateCandidate(contractCtx contract.KContext) (*contract.Response, error) {
 	// 1.1 核查nominate合约参数有效性
 	candidateName, err := tp.checkArgs(contractCtx.Args())
@@ -39,6 +56,17 @@ func (tp *tdposConsensus) runNominateCandidate(contractCtx contract.KContext) (*
 	if amount <= 0 || err != nil {
 		return common.NewContractErrResponse(common.StatusErr, ErrAmount.Error()), ErrAmount
 	}
+	commissionBytes, hasCommission := contractCtx.Args()["commission"]
+	var commission int64
+	if hasCommission {
+		if tp.config.RewardConfig == nil {
+			return common.NewContractErrResponse(common.StatusErr, ErrRewardDisabled.Error()), ErrRewardDisabled
+		}
+		commission, err = strconv.ParseInt(string(commissionBytes), 10, 64)
+		if err != nil || commission < 0 || commission > MaxCommission {
+			return common.NewContractErrResponse(common.StatusErr, ErrCommission.Error()), ErrCommission
+		}
+	}
 	// 1.2 是否按照要求多签
 	if ok := tp.isAuthAddress(candidateName, contractCtx.Initiator(), contractCtx.AuthRequire()); !ok {
 		return common.NewContractErrResponse(common.StatusErr, ErrAuth.Error()), ErrAuth
@@ -82,6 +110,12 @@ func (tp *tdposConsensus) runNominateCandidate(contractCtx contract.KContext) (*
 	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(nKey), returnBytes); err != nil {
 		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
 	}
+	// 4. 记录佣金比例
+	if hasCommission {
+		if err := tp.updateCommission(contractCtx, candidateName, commission, false); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
 	delta := contract.Limits{
 		XFee: fee,
 	}
@@ -171,6 +205,12 @@ func (tp *tdposConsensus) runRevokeCandidate(contractCtx contract.KContext) (*co
 	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(nKey), nominateBytes); err != nil {
 		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
 	}
+	// 5. 删除佣金比例记录
+	if tp.config.RewardConfig != nil {
+		if err := tp.updateCommission(contractCtx, candidateName, 0, true); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
 	delta := contract.Limits{
 		XFee: fee,
 	}
@@ -409,6 +449,240 @@ func (tp *tdposConsensus) runGetTdposInfos(contractCtx contract.KContext) (*cont
 	return common.NewContractOKResponse(return_bytes), nil
 }
 
+// runSubmitEvidence 提交双签证据，校验通过后按slash_config禁闭作恶候选人并销毁其提名质押
+// Args:
+//	evidence::json格式的evidence.Evidence
+func (tp *tdposConsensus) runSubmitEvidence(contractCtx contract.KContext) (*contract.Response, error) {
+	// 1. 校验证据
+	e, err := evidence.ParseEvidence(contractCtx.Args())
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	record, err := tp.verifier.Verify(e)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	// 1.1 同一作恶行为仅惩罚一次
+	eKey := fmt.Sprintf("%s_%d_%s%s", tp.status.Name, tp.status.Version, evidenceKeyPrefix, record.Key())
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(eKey))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if res != nil {
+		return common.NewContractErrResponse(common.StatusErr, evidence.ErrEvidenceExist.Error()), evidence.ErrEvidenceExist
+	}
+
+	// 2. 禁闭，作恶时隙所在term之后的jailTerms轮内不能被选为候选人
+	jailTerms, burnRatio := tp.config.SlashConfig.Penalty(record.Type)
+	if jailTerms > 0 {
+		term, _, _ := tp.election.minerScheduling(record.Timestamp)
+		record.JailUntil = term + jailTerms
+		if err := tp.jail(contractCtx, record.Offender, record.JailUntil); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
+
+	// 3. 按比例销毁作恶候选人的提名质押
+	if burnRatio > 0 {
+		burned, err := tp.burnNominate(contractCtx, record.Offender, burnRatio)
+		if err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+		record.Burned = burned
+	}
+
+	// 4. 写入证据记录
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(eKey), recordBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	delta := contract.Limits{
+		XFee: fee,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse(recordBytes), nil
+}
+
+// jail 更新禁闭记录，重复禁闭时取较晚的term
+func (tp *tdposConsensus) jail(contractCtx contract.KContext, candidate string, untilTerm int64) error {
+	jKey := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, jailKey)
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(jKey))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return err
+	}
+	jailValue := NewJailValue()
+	if res != nil {
+		if err := json.Unmarshal(res, &jailValue); err != nil {
+			tp.log.Error("tdpos::runSubmitEvidence::load jail read set err.")
+			return err
+		}
+	}
+	if jailValue[candidate] >= untilTerm {
+		return nil
+	}
+	jailValue[candidate] = untilTerm
+	jailBytes, err := json.Marshal(jailValue)
+	if err != nil {
+		return err
+	}
+	return contractCtx.Put(tp.election.bindContractBucket, []byte(jKey), jailBytes)
+}
+
+// burnNominate 按ratio销毁candidate每个提名者锁定的治理代币，并相应扣减提名记录，返回销毁总量
+func (tp *tdposConsensus) burnNominate(contractCtx contract.KContext, candidate string, ratio int64) (int64, error) {
+	nKey := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, nominateKey)
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(nKey))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return 0, err
+	}
+	nominateValue := NewNominateValue()
+	if res != nil {
+		if err := json.Unmarshal(res, &nominateValue); err != nil {
+			tp.log.Error("tdpos::runSubmitEvidence::load nominate read set err.")
+			return 0, err
+		}
+	}
+	record, ok := nominateValue[candidate]
+	if !ok {
+		return 0, nil
+	}
+	// 按地址排序，保证各节点合约调用顺序一致
+	var froms []string
+	for from := range record {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+	var total int64
+	for _, from := range froms {
+		amount := record[from] * ratio / evidence.MaxRatio
+		if amount <= 0 {
+			continue
+		}
+		tokenArgs := map[string][]byte{
+			"from":      []byte(from),
+			"amount":    []byte(fmt.Sprintf("%d", amount)),
+			"lock_type": []byte(utils.GovernTokenTypeTDPOS),
+		}
+		resp, err := contractCtx.Call("xkernel", utils.GovernTokenKernelContract, "Burn", tokenArgs)
+		if err != nil {
+			return 0, err
+		}
+		// 锁定余额不足时仅销毁锁定部分
+		if resp != nil && len(resp.Body) > 0 {
+			if burned, err := strconv.ParseInt(string(resp.Body), 10, 64); err == nil && burned < amount {
+				amount = burned
+			}
+		}
+		record[from] -= amount
+		total += amount
+	}
+	nominateBytes, err := json.Marshal(nominateValue)
+	if err != nil {
+		return 0, err
+	}
+	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(nKey), nominateBytes); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// runClaimReward 结算已结束的轮次并领取发起者的待领取奖励，奖励从共识合约bucket转给发起者
+// Args:
+//	height::结算截止高度，只结算下一轮首个区块不超过该高度的轮次，一般取当前最高区块高度
+func (tp *tdposConsensus) runClaimReward(contractCtx contract.KContext) (*contract.Response, error) {
+	height, err := strconv.ParseInt(string(contractCtx.Args()["height"]), 10, 64)
+	if err != nil || height <= 0 {
+		return common.NewContractErrResponse(common.StatusErr, ErrSettleHeight.Error()), ErrSettleHeight
+	}
+	// 1. 结算，结算结果与领取者无关，任何地址均可触发
+	if _, err := tp.settleReward(contractCtx, height); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+
+	// 2. 领取发起者的待领取奖励
+	pending, err := tp.getPendingReward(contractCtx, contractCtx.Initiator())
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if pending.Sign() > 0 {
+		if err := contractCtx.Transfer(tp.election.bindContractBucket, contractCtx.Initiator(), pending); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+		if err := tp.putPendingReward(contractCtx, contractCtx.Initiator(), big.NewInt(0)); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
+	delta := contract.Limits{
+		XFee: fee,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse([]byte(pending.String())), nil
+}
+
+// runGetReward 查询地址已结算但未领取的奖励，尚未结算的轮次需领取时结算
+// Args:
+//	address::可选，查询的地址，默认为发起者
+func (tp *tdposConsensus) runGetReward(contractCtx contract.KContext) (*contract.Response, error) {
+	addr := string(contractCtx.Args()["address"])
+	if addr == "" {
+		addr = contractCtx.Initiator()
+	}
+	pending, err := tp.getPendingReward(contractCtx, addr)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	settled, err := tp.getSettledHeight(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	returnMap := map[string]interface{}{
+		"address":        addr,
+		"reward":         pending.String(),
+		"settled_height": settled,
+	}
+	returnBytes, err := json.Marshal(returnMap)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	delta := contract.Limits{
+		XFee: fee / 1000,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse(returnBytes), nil
+}
+
+// updateCommission 更新候选人的佣金比例，remove为true时删除该候选人的记录
+func (tp *tdposConsensus) updateCommission(contractCtx contract.KContext, candidate string, commission int64, remove bool) error {
+	cKey := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, commissionKey)
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(cKey))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return err
+	}
+	commissionValue := NewCommissionValue()
+	if res != nil {
+		if err := json.Unmarshal(res, &commissionValue); err != nil {
+			tp.log.Error("tdpos::updateCommission::load commission read set err.")
+			return err
+		}
+	}
+	if remove {
+		if _, ok := commissionValue[candidate]; !ok {
+			return nil
+		}
+		delete(commissionValue, candidate)
+	} else {
+		commissionValue[candidate] = commission
+	}
+	commissionBytes, err := json.Marshal(commissionValue)
+	if err != nil {
+		return err
+	}
+	return contractCtx.Put(tp.election.bindContractBucket, []byte(cKey), commissionBytes)
+}
+
 func (tp *tdposConsensus) checkArgs(txArgs map[string][]byte) (string, error) {
 	candidateBytes := txArgs["candidate"]
 	candidateName := string(candidateBytes)
@@ -442,6 +716,20 @@ func NewRevokeValue() revokeValue {
 	return make(map[string][]revokeItem)
 }
 
+// jailValue 候选人禁闭至的term，term不大于该值时不能被选为候选人
+type jailValue map[string]int64
+
+func NewJailValue() jailValue {
+	return make(map[string]int64)
+}
+
+// commissionValue 候选人保留的奖励百分比
+type commissionValue map[string]int64
+
+func NewCommissionValue() commissionValue {
+	return make(map[string]int64)
+}
+
 func (tp *tdposConsensus) isAuthAddress(candidate string, initiator string, authRequire []string) bool {
 	if strings.HasSuffix(initiator, candidate) {
 		return true
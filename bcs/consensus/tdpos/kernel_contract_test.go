@@ -3,7 +3,12 @@ package tdpos
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
+	xledger "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/consensus/mock"
 	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
 )
@@ -202,3 +207,138 @@ func TestRunRevokeVote(t *testing.T) {
 	fakeCtx := mock.NewFakeKContext(NewNominateArgs(), NewM())
 	tdpos.runRevokeVote(fakeCtx)
 }
+
+func getSlashTdposConsensusConf() string {
+	return `{
+		"version": "2",
+        "timestamp": "1559021720000000000",
+        "proposer_num": "2",
+        "period": "3000",
+        "alternate_interval": "3000",
+        "term_interval": "6000",
+        "block_num": "20",
+        "vote_unit_price": "1",
+        "init_proposer": {
+            "1": ["TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY", "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"]
+        },
+		"slash_config": {"jail_terms": 2, "burn_ratio": 10}
+	}`
+}
+
+// newSignedBlock 使用共识上下文中的节点私钥签名区块头
+func newSignedBlock(cCtx *cctx.ConsensusCtx, height, timestamp int64, preHash []byte, t *testing.T) *lpb.InternalBlock {
+	b := &lpb.InternalBlock{
+		Version:   1,
+		PreHash:   preHash,
+		Proposer:  []byte(cCtx.Address.Address),
+		Pubkey:    []byte(cCtx.Address.PublicKeyStr),
+		Height:    height,
+		Timestamp: timestamp,
+	}
+	id, err := xledger.MakeBlockIDWithHasher(b, cCtx.Hasher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Blockid = id
+	if b.Sign, err = cCtx.Crypto.SignECDSA(cCtx.Address.PrivateKey, id); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func NewEvidenceArgs(blocks ...*lpb.InternalBlock) map[string][]byte {
+	req, _ := evidence.NewEvidenceRequest(tdposBucket, &evidence.Evidence{
+		Type:   evidence.TypeBlock,
+		Blocks: blocks,
+	})
+	return req.Args
+}
+
+func TestRunSubmitEvidence(t *testing.T) {
+	cCtx, err := prepare(getSlashTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	i := NewTdposConsensus(*cCtx, getConfig(getSlashTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	if _, ok := tdpos.kMethod[contractSubmitEvidence]; !ok {
+		t.Error("submitEvidence should be registered with slash_config.")
+		return
+	}
+	// term 1, pos 0, blockPos 1 的同一时隙内的两个区块
+	ts := tdpos.config.InitTimestamp + int64(7*time.Second)
+	b1 := newSignedBlock(cCtx, 10, ts, []byte{1}, t)
+	b2 := newSignedBlock(cCtx, 10, ts+int64(500*time.Millisecond), []byte{2}, t)
+
+	n := NewNominateValue()
+	n[cCtx.Address.Address] = map[string]int64{
+		cCtx.Address.Address:                10,
+		"TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY": 20,
+	}
+	nb, _ := json.Marshal(n)
+	fakeCtx := mock.NewFakeKContext(NewEvidenceArgs(b1, b2), NewM())
+	fakeCtx.Put(tdposBucket, []byte("tdpos_2_"+nominateKey), nb)
+	if _, err := tdpos.runSubmitEvidence(fakeCtx); err != nil {
+		t.Error("runSubmitEvidence error.", "err", err)
+		return
+	}
+	// 禁闭至term 1+2，销毁10%的提名质押
+	res, _ := fakeCtx.Get(tdposBucket, []byte("tdpos_2_"+jailKey))
+	jail := NewJailValue()
+	if err := json.Unmarshal(res, &jail); err != nil || jail[cCtx.Address.Address] != 3 {
+		t.Error("jail error.", "jail", jail)
+		return
+	}
+	res, _ = fakeCtx.Get(tdposBucket, []byte("tdpos_2_"+nominateKey))
+	n = NewNominateValue()
+	json.Unmarshal(res, &n)
+	if n[cCtx.Address.Address][cCtx.Address.Address] != 9 || n[cCtx.Address.Address]["TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"] != 18 {
+		t.Error("burn error.", "nominate", n)
+		return
+	}
+	// 同一证据不能重复提交
+	if _, err := tdpos.runSubmitEvidence(fakeCtx); err != evidence.ErrEvidenceExist {
+		t.Error("repeat evidence should be rejected.", "err", err)
+		return
+	}
+	// 不同时隙且不同高度的区块不构成证据
+	b3 := newSignedBlock(cCtx, 11, ts+int64(3*time.Second), []byte{2}, t)
+	fakeCtx = mock.NewFakeKContext(NewEvidenceArgs(b1, b3), NewM())
+	if _, err := tdpos.runSubmitEvidence(fakeCtx); err != evidence.ErrNotConflict {
+		t.Error("non-conflicting blocks should be rejected.", "err", err)
+	}
+}
+
+func TestReportEvidences(t *testing.T) {
+	cCtx, err := prepare(getSlashTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	txRely := kmock.NewFakeTxRely()
+	cCtx.Tx = txRely
+	i := NewTdposConsensus(*cCtx, getConfig(getSlashTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	ts := tdpos.config.InitTimestamp + int64(7*time.Second)
+	b1 := newSignedBlock(cCtx, 10, ts, []byte{1}, t)
+	b2 := newSignedBlock(cCtx, 10, ts+int64(500*time.Millisecond), []byte{2}, t)
+	tdpos.reportEvidences(tdpos.detector.AddBlock(b1))
+	tdpos.reportEvidences(tdpos.detector.AddBlock(b2))
+
+	// 发现双签后提交调用submitEvidence的交易
+	select {
+	case req := <-txRely.Requests:
+		if req.GetContractName() != tdposBucket || req.GetMethodName() != contractSubmitEvidence {
+			t.Error("evidence request error.", "req", req)
+			return
+		}
+		fakeCtx := mock.NewFakeKContext(req.GetArgs(), NewM())
+		fakeCtx.Put(tdposBucket, []byte("tdpos_2_"+nominateKey), []byte("{}"))
+		if _, err := tdpos.runSubmitEvidence(fakeCtx); err != nil {
+			t.Error("evidence tx should be accepted.", "err", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("evidence tx not submitted.")
+	}
+}
@@ -0,0 +1,312 @@
+package tdpos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+)
+
+// 本文件实现tdpos的投票收益分成
+// 开启reward_config后矿工将区块奖励全部转入共识合约bucket，合约按轮结算:
+// 每轮中proposer出块所得的奖励按其佣金比例归proposer，其余部分按选出该轮候选人的投票快照按票数分给其投票者，
+// 除不尽的部分归proposer，结算结果累计在各地址的待领取奖励中，由proposer和投票者通过claimVoterReward领取
+// 结算只处理已经结束的完整轮次，且只读取领取交易参数指定高度及以下的区块，保证各节点执行结果一致
+// 区块从合约执行所在的区块沿PreHash回溯读取，不使用主干的高度索引，分叉上的区块不会按主干结算，回溯的区块数计入资源消耗
+
+var ErrAwardTx = errors.New("award of block should be transferred to consensus contract when reward config is set")
+
+// awardCalculator 由账本实现，返回对应高度的区块奖励
+type awardCalculator interface {
+	CalcAward(height int64) *big.Int
+}
+
+// awardTxGetter 由xledger的BlockAgent实现，用于获取区块的矿工奖励交易
+type awardTxGetter interface {
+	GetAwardTx() *lpb.Transaction
+}
+
+// execBlockGetter 由kernel合约上下文实现，返回合约执行所在的区块
+type execBlockGetter interface {
+	ExecBlock() ledger.BlockHandle
+}
+
+// GetAwardAddress 实现consensus.AwardInterface，开启投票收益分成时区块奖励转入共识合约bucket
+func (tp *tdposConsensus) GetAwardAddress(height int64) string {
+	if tp.config.RewardConfig == nil || height < tp.rewardBeginHeight() {
+		return ""
+	}
+	return tp.election.bindContractBucket
+}
+
+// rewardBeginHeight 第一个奖励转入共识合约bucket的区块高度
+func (tp *tdposConsensus) rewardBeginHeight() int64 {
+	if tp.status.StartHeight < 1 {
+		return 1
+	}
+	return tp.status.StartHeight
+}
+
+// checkAwardTx 开启投票收益分成时，区块奖励必须全部转入共识合约bucket，保证结算时奖励足额
+func (tp *tdposConsensus) checkAwardTx(block cctx.BlockInterface) error {
+	if tp.config.RewardConfig == nil || block.GetHeight() < tp.rewardBeginHeight() {
+		return nil
+	}
+	getter, ok := block.(awardTxGetter)
+	if !ok {
+		return nil
+	}
+	awardTx := getter.GetAwardTx()
+	if awardTx == nil {
+		return ErrAwardTx
+	}
+	for _, output := range awardTx.TxOutputs {
+		if string(output.ToAddr) != tp.election.bindContractBucket {
+			return ErrAwardTx
+		}
+	}
+	return nil
+}
+
+// settleReward 结算高度不超过height的所有已结束的轮次，每次最多结算MaxSettleTerms轮，返回结算到的高度
+func (tp *tdposConsensus) settleReward(contractCtx contract.KContext, height int64) (int64, error) {
+	settled, err := tp.getSettledHeight(contractCtx)
+	if err != nil {
+		return 0, err
+	}
+	if height <= settled {
+		return settled, nil
+	}
+	// 结算还需读取settled-3处的快照
+	begin := settled - 3
+	if begin < 0 {
+		begin = 0
+	}
+	blocks, scanned, err := tp.rewardBlocks(contractCtx, begin, height)
+	if err != nil {
+		return 0, err
+	}
+	// 按回溯的区块数收取费用
+	contractCtx.AddResourceUsed(contract.Limits{
+		XFee: scanned * fee / 1000,
+	})
+	rewards := make(map[string]*big.Int)
+	for i := int64(0); i < tp.config.RewardConfig.MaxSettleTerms; i++ {
+		end, awards, err := tp.termAwards(blocks[settled+1-begin:])
+		if err != nil {
+			return 0, err
+		}
+		// 该轮尚未结束
+		if end < 0 {
+			break
+		}
+		var snapshot ledger.BlockHandle
+		if settled-3 > 0 {
+			snapshot = blocks[settled-3-begin]
+		}
+		if err := tp.splitAwards(snapshot, awards, rewards); err != nil {
+			return 0, err
+		}
+		settled = end
+	}
+
+	// 按地址排序写入，保证各节点读写集一致
+	var addrs []string
+	for addr := range rewards {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		pending, err := tp.getPendingReward(contractCtx, addr)
+		if err != nil {
+			return 0, err
+		}
+		if err := tp.putPendingReward(contractCtx, addr, pending.Add(pending, rewards[addr])); err != nil {
+			return 0, err
+		}
+	}
+	sKey := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, settledKey)
+	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(sKey), []byte(fmt.Sprintf("%d", settled))); err != nil {
+		return 0, err
+	}
+	return settled, nil
+}
+
+// rewardBlocks 返回合约执行所在分支上高度begin到end的区块，从执行区块的父区块沿PreHash回溯，
+// 预执行时没有执行区块，从账本最高区块开始回溯，只能读取执行中区块之前的区块，同时返回回溯读取的区块数
+func (tp *tdposConsensus) rewardBlocks(contractCtx contract.KContext, begin, end int64) ([]ledger.BlockHandle, int64, error) {
+	var block ledger.BlockHandle
+	if g, ok := contractCtx.(execBlockGetter); ok && g.ExecBlock() != nil {
+		if end >= g.ExecBlock().GetHeight() {
+			return nil, 0, ErrSettleHeight
+		}
+		var err error
+		if block, err = tp.election.ledger.QueryBlockHeader(g.ExecBlock().GetPreHash()); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		block = tp.election.ledger.GetTipBlock()
+		if block == nil || end > block.GetHeight() {
+			return nil, 0, ErrSettleHeight
+		}
+	}
+	blocks := make([]ledger.BlockHandle, end-begin+1)
+	scanned := int64(1)
+	for block.GetHeight() >= begin {
+		if block.GetHeight() <= end {
+			blocks[block.GetHeight()-begin] = block
+		}
+		if block.GetHeight() == begin {
+			break
+		}
+		var err error
+		if block, err = tp.election.ledger.QueryBlockHeader(block.GetPreHash()); err != nil {
+			return nil, 0, err
+		}
+		scanned++
+	}
+	return blocks, scanned, nil
+}
+
+// termAwards 统计blocks中首个区块所在轮次每个proposer的出块奖励，返回该轮最后一个区块的高度
+// 该轮的区块未全部包含在blocks中时视为未结束，返回-1
+func (tp *tdposConsensus) termAwards(blocks []ledger.BlockHandle) (int64, map[string]*big.Int, error) {
+	term := int64(-1)
+	awards := make(map[string]*big.Int)
+	for _, block := range blocks {
+		h := block.GetHeight()
+		in, err := ParseConsensusStorage(block)
+		if err != nil {
+			return -1, nil, err
+		}
+		storage, ok := in.(*common.ConsensusStorage)
+		if !ok {
+			return -1, nil, ErrValueNotFound
+		}
+		if term < 0 {
+			term = storage.CurTerm
+		}
+		if storage.CurTerm != term {
+			return h - 1, awards, nil
+		}
+		proposer := string(block.GetProposer())
+		if _, ok := awards[proposer]; !ok {
+			awards[proposer] = big.NewInt(0)
+		}
+		awards[proposer].Add(awards[proposer], tp.award.CalcAward(h))
+	}
+	return -1, nil, nil
+}
+
+// splitAwards 按snapshot区块处的快照分配一轮的奖励，链起始阶段没有快照时snapshot为nil
+// 与calTopKNominator一致，选出该轮候选人使用的是上一轮最后一个区块settled-3处的快照
+func (tp *tdposConsensus) splitAwards(snapshot ledger.BlockHandle, awards map[string]*big.Int, rewards map[string]*big.Int) error {
+	commissions := NewCommissionValue()
+	res, err := tp.getRewardSnapshot(snapshot, fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, commissionKey))
+	if err != nil {
+		return err
+	}
+	if res != nil {
+		if err := json.Unmarshal(res, &commissions); err != nil {
+			return err
+		}
+	}
+	for proposer, total := range awards {
+		commission, ok := commissions[proposer]
+		if !ok {
+			commission = defaultCommission
+		}
+		var votes voteValue
+		if commission < MaxCommission {
+			key := fmt.Sprintf("%s_%d_%s%s", tp.status.Name, tp.status.Version, voteKeyPrefix, proposer)
+			res, err := tp.getRewardSnapshot(snapshot, key)
+			if err != nil {
+				return err
+			}
+			if res != nil {
+				votes = NewvoteValue()
+				if err := json.Unmarshal(res, &votes); err != nil {
+					return err
+				}
+			}
+		}
+		var ballots int64
+		for _, ballot := range votes {
+			ballots += ballot
+		}
+		// 投票者按票数分配总奖励中除佣金外的部分
+		distributed := big.NewInt(0)
+		if ballots > 0 {
+			share := new(big.Int).Mul(total, big.NewInt(MaxCommission-commission))
+			share.Div(share, big.NewInt(MaxCommission))
+			for voter, ballot := range votes {
+				amount := new(big.Int).Mul(share, big.NewInt(ballot))
+				amount.Div(amount, big.NewInt(ballots))
+				addReward(rewards, voter, amount)
+				distributed.Add(distributed, amount)
+			}
+		}
+		addReward(rewards, proposer, new(big.Int).Sub(total, distributed))
+	}
+	return nil
+}
+
+// getRewardSnapshot 读取选出该轮候选人时的快照，链起始阶段没有快照时返回nil
+func (tp *tdposConsensus) getRewardSnapshot(snapshot ledger.BlockHandle, key string) ([]byte, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	return tp.election.getBlockSnapshotKey(snapshot.GetBlockid(), tp.election.bindContractBucket, []byte(key))
+}
+
+func addReward(rewards map[string]*big.Int, addr string, amount *big.Int) {
+	if amount.Sign() <= 0 {
+		return
+	}
+	if _, ok := rewards[addr]; !ok {
+		rewards[addr] = big.NewInt(0)
+	}
+	rewards[addr].Add(rewards[addr], amount)
+}
+
+// getSettledHeight 返回已结算的最后一个区块高度
+func (tp *tdposConsensus) getSettledHeight(contractCtx contract.KContext) (int64, error) {
+	sKey := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, settledKey)
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(sKey))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return 0, err
+	}
+	if res == nil {
+		return tp.rewardBeginHeight() - 1, nil
+	}
+	return strconv.ParseInt(string(res), 10, 64)
+}
+
+func (tp *tdposConsensus) getPendingReward(contractCtx contract.KContext, addr string) (*big.Int, error) {
+	rKey := fmt.Sprintf("%s_%d_%s%s", tp.status.Name, tp.status.Version, rewardKeyPrefix, addr)
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(rKey))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return nil, err
+	}
+	if res == nil {
+		return big.NewInt(0), nil
+	}
+	pending, ok := new(big.Int).SetString(string(res), 10)
+	if !ok {
+		return nil, ErrValueNotFound
+	}
+	return pending, nil
+}
+
+func (tp *tdposConsensus) putPendingReward(contractCtx contract.KContext, addr string, amount *big.Int) error {
+	rKey := fmt.Sprintf("%s_%d_%s%s", tp.status.Name, tp.status.Version, rewardKeyPrefix, addr)
+	return contractCtx.Put(tp.election.bindContractBucket, []byte(rKey), []byte(amount.String()))
+}
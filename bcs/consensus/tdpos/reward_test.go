@@ -0,0 +1,287 @@
+package tdpos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/consensus/mock"
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	rewardProposerA = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
+	rewardProposerB = "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"
+	rewardVoter1    = "akf7qunmeaqb51Wu418d6TyPKp4jdLdpV"
+	rewardVoter2    = "dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN"
+)
+
+func getRewardTdposConsensusConf() string {
+	return `{
+		"version": "2",
+        "timestamp": "1559021720000000000",
+        "proposer_num": "2",
+        "period": "3000",
+        "alternate_interval": "3000",
+        "term_interval": "6000",
+        "block_num": "20",
+        "vote_unit_price": "1",
+        "init_proposer": {
+            "1": ["TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY", "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"]
+        },
+		"reward_config": {}
+	}`
+}
+
+func NewClaimRewardArgs(height string) map[string][]byte {
+	a := make(map[string][]byte)
+	a["height"] = []byte(height)
+	return a
+}
+
+func TestRewardConfig(t *testing.T) {
+	xconfig, err := buildConfigs([]byte(getRewardTdposConsensusConf()))
+	if err != nil {
+		t.Error("Config unmarshal err", "err", err)
+		return
+	}
+	if xconfig.RewardConfig == nil || xconfig.RewardConfig.MaxSettleTerms != defaultMaxSettleTerms {
+		t.Error("reward config should use default max_settle_terms.", "config", xconfig.RewardConfig)
+	}
+}
+
+func TestRunNominateCommission(t *testing.T) {
+	cCtx, err := prepare(getTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	i := NewTdposConsensus(*cCtx, getConfig(getTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	args := NewNominateArgs()
+	args["commission"] = []byte("20")
+	if _, err := tdpos.runNominateCandidate(mock.NewFakeKContext(args, NewM())); err != ErrRewardDisabled {
+		t.Error("commission should be rejected without reward config.", "err", err)
+		return
+	}
+
+	cCtx, err = prepare(getRewardTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	i = NewTdposConsensus(*cCtx, getConfig(getRewardTdposConsensusConf()))
+	tdpos, _ = i.(*tdposConsensus)
+	if _, ok := tdpos.kMethod[contractClaimReward]; !ok {
+		t.Error("claimVoterReward should be registered with reward_config.")
+		return
+	}
+	args["commission"] = []byte("101")
+	if _, err := tdpos.runNominateCandidate(mock.NewFakeKContext(args, NewM())); err != ErrCommission {
+		t.Error("commission over 100 should be rejected.", "err", err)
+		return
+	}
+	args["commission"] = []byte("20")
+	fakeCtx := mock.NewFakeKContext(args, NewM())
+	if _, err := tdpos.runNominateCandidate(fakeCtx); err != nil {
+		t.Error("runNominateCandidate error.", "err", err)
+		return
+	}
+	res, _ := fakeCtx.Get(tdposBucket, []byte("tdpos_2_"+commissionKey))
+	commissions := NewCommissionValue()
+	if err := json.Unmarshal(res, &commissions); err != nil || commissions[rewardProposerA] != 20 {
+		t.Error("commission error.", "commissions", commissions)
+	}
+}
+
+func TestRunClaimReward(t *testing.T) {
+	cCtx, err := prepare(getRewardTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	// 1. 构造区块，term1为高度1~5，term2为高度6~8，term3从高度9开始
+	l, _ := cCtx.Ledger.(*kmock.FakeLedger)
+	for h := 3; h <= 9; h++ {
+		l.Put(kmock.NewBlock(h))
+	}
+	terms := []int64{0, 1, 1, 1, 1, 1, 2, 2, 2, 3}
+	for h := 1; h <= 9; h++ {
+		l.SetConsensusStorage(h, SetTdposStorage(terms[h], nil))
+		b, _ := l.QueryBlockHeaderByHeight(int64(h))
+		b.(*kmock.FakeBlock).SetProposer(rewardProposerA)
+	}
+	b, _ := l.QueryBlockHeaderByHeight(8)
+	b.(*kmock.FakeBlock).SetProposer(rewardProposerB)
+	// 2. 选出term2候选人时的快照，A保留20%的奖励，B未声明佣金
+	commissions := NewCommissionValue()
+	commissions[rewardProposerA] = 20
+	cb, _ := json.Marshal(commissions)
+	l.SetSnapshot(tdposBucket, []byte("tdpos_2_"+commissionKey), cb)
+	votes := NewvoteValue()
+	votes[rewardVoter1] = 3
+	votes[rewardVoter2] = 1
+	vb, _ := json.Marshal(votes)
+	l.SetSnapshot(tdposBucket, []byte("tdpos_2_"+voteKeyPrefix+rewardProposerA), vb)
+
+	i := NewTdposConsensus(*cCtx, getConfig(getRewardTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	if tdpos.GetAwardAddress(2) != tdposBucket {
+		t.Error("award should be transferred to consensus contract.")
+		return
+	}
+	// 3. term1没有快照，奖励全部归A；term2中A出块2个，投票者分得160；term3未结束不结算
+	m := NewM()
+	resp, err := tdpos.runClaimReward(mock.NewFakeKContext(NewClaimRewardArgs("9"), m))
+	if err != nil {
+		t.Error("runClaimReward error.", "err", err)
+		return
+	}
+	if string(resp.Body) != "540" {
+		t.Error("claimed reward error.", "reward", string(resp.Body))
+		return
+	}
+	want := map[string]string{
+		rewardProposerA: "0",
+		rewardProposerB: "100",
+		rewardVoter1:    "120",
+		rewardVoter2:    "40",
+	}
+	for addr, reward := range want {
+		args := map[string][]byte{"address": []byte(addr)}
+		resp, err := tdpos.runGetReward(mock.NewFakeKContext(args, m))
+		if err != nil {
+			t.Error("runGetReward error.", "err", err)
+			return
+		}
+		info := make(map[string]interface{})
+		json.Unmarshal(resp.Body, &info)
+		if info["reward"] != reward || info["settled_height"] != float64(8) {
+			t.Error("pending reward error.", "addr", addr, "info", info)
+			return
+		}
+	}
+	// 4. 已结算的轮次不会重复结算
+	resp, err = tdpos.runClaimReward(mock.NewFakeKContext(NewClaimRewardArgs("9"), m))
+	if err != nil || string(resp.Body) != "0" {
+		t.Error("repeat claim error.", "err", err)
+	}
+}
+
+// execKContext 带有执行区块并记录资源消耗的合约上下文
+type execKContext struct {
+	*mock.FakeKContext
+	block ledger.BlockHandle
+	used  contract.Limits
+}
+
+func (c *execKContext) ExecBlock() ledger.BlockHandle {
+	return c.block
+}
+
+func (c *execKContext) AddResourceUsed(delta contract.Limits) {
+	c.used.Add(delta)
+}
+
+func TestClaimRewardOnFork(t *testing.T) {
+	cCtx, err := prepare(getRewardTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	// 1. 主干term1为高度1~5，term2为高度6~8，高度8由B出块；分叉从高度7分出，分叉上高度8由A出块
+	l, _ := cCtx.Ledger.(*kmock.FakeLedger)
+	for h := 3; h <= 9; h++ {
+		l.Put(kmock.NewBlock(h))
+	}
+	terms := []int64{0, 1, 1, 1, 1, 1, 2, 2, 2, 3}
+	for h := 1; h <= 9; h++ {
+		l.SetConsensusStorage(h, SetTdposStorage(terms[h], nil))
+		b, _ := l.QueryBlockHeaderByHeight(int64(h))
+		b.(*kmock.FakeBlock).SetProposer(rewardProposerA)
+	}
+	b, _ := l.QueryBlockHeaderByHeight(8)
+	b.(*kmock.FakeBlock).SetProposer(rewardProposerB)
+	fork8 := &kmock.FakeBlock{Height: 8, Blockid: []byte{0x88}, PreHash: []byte{7},
+		Proposer: rewardProposerA, ConsensusStorage: SetTdposStorage(2, nil)}
+	fork9 := &kmock.FakeBlock{Height: 9, Blockid: []byte{0x89}, PreHash: fork8.Blockid,
+		Proposer: rewardProposerA, ConsensusStorage: SetTdposStorage(3, nil)}
+	l.Put(fork8)
+	l.Put(fork9)
+	commissions := NewCommissionValue()
+	commissions[rewardProposerA] = 20
+	cb, _ := json.Marshal(commissions)
+	l.SetSnapshot(tdposBucket, []byte("tdpos_2_"+commissionKey), cb)
+	votes := NewvoteValue()
+	votes[rewardVoter1] = 3
+	votes[rewardVoter2] = 1
+	vb, _ := json.Marshal(votes)
+	l.SetSnapshot(tdposBucket, []byte("tdpos_2_"+voteKeyPrefix+rewardProposerA), vb)
+
+	i := NewTdposConsensus(*cCtx, getConfig(getRewardTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	// 2. 执行区块在主干上时按主干结算，term2中A出块2个
+	trunkCtx := &execKContext{
+		FakeKContext: mock.NewFakeKContext(NewClaimRewardArgs("9"), NewM()),
+		block:        &kmock.FakeBlock{Height: 10, Blockid: []byte{10}, PreHash: []byte{9}},
+	}
+	resp, err := tdpos.runClaimReward(trunkCtx)
+	if err != nil || string(resp.Body) != "540" {
+		t.Error("claim reward on trunk error.", "err", err, "resp", resp)
+		return
+	}
+	// 3. 执行区块在分叉上时按分叉结算，term2中A出块3个，投票者分得240
+	forkCtx := &execKContext{
+		FakeKContext: mock.NewFakeKContext(NewClaimRewardArgs("9"), NewM()),
+		block:        &kmock.FakeBlock{Height: 10, Blockid: []byte{0x8a}, PreHash: fork9.Blockid},
+	}
+	resp, err = tdpos.runClaimReward(forkCtx)
+	if err != nil || string(resp.Body) != "560" {
+		t.Error("claim reward on fork error.", "err", err, "resp", resp)
+		return
+	}
+	// 4. 回溯了高度9到0共10个区块，按区块数收取费用
+	if forkCtx.used.XFee != fee+10*fee/1000 {
+		t.Error("claim reward fee error.", "used", forkCtx.used)
+		return
+	}
+	// 5. 只能结算执行区块之前的区块
+	forkCtx.FakeKContext = mock.NewFakeKContext(NewClaimRewardArgs("10"), NewM())
+	if _, err := tdpos.runClaimReward(forkCtx); err != ErrSettleHeight {
+		t.Error("claim reward beyond exec block should be rejected.", "err", err)
+	}
+}
+
+func TestCheckAwardTx(t *testing.T) {
+	cCtx, err := prepare(getRewardTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	i := NewTdposConsensus(*cCtx, getConfig(getRewardTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	newAwardBlock := func(to string) *state.BlockAgent {
+		return state.NewBlockAgent(&lpb.InternalBlock{
+			Height: 5,
+			Transactions: []*lpb.Transaction{
+				{
+					Coinbase: true,
+					TxOutputs: []*protos.TxOutput{
+						{ToAddr: []byte(to), Amount: []byte{100}},
+					},
+				},
+			},
+		})
+	}
+	if err := tdpos.checkAwardTx(newAwardBlock(rewardProposerA)); err != ErrAwardTx {
+		t.Error("award to miner should be rejected.", "err", err)
+		return
+	}
+	if err := tdpos.checkAwardTx(newAwardBlock(tdposBucket)); err != nil {
+		t.Error("award to consensus contract should be accepted.", "err", err)
+	}
+}
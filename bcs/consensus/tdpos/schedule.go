@@ -7,6 +7,7 @@ import (
 	"time"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/lib/logs"
 )
@@ -40,6 +41,9 @@ type tdposSchedule struct {
 	consensusVersion   int64
 	bindContractBucket string
 
+	// 非nil时开启leader替换
+	reputation *reputation.Reputation
+
 	log    logs.Logger
 	ledger cctx.LedgerRely
 }
@@ -128,7 +132,12 @@ func (s *tdposSchedule) getSnapshotKey(height int64, bucket string, key []byte)
 		s.log.Debug("tdpos::getSnapshotKey::QueryBlockByHeight err.", "err", err)
 		return nil, err
 	}
-	reader, err := s.ledger.CreateSnapshot(block.GetBlockid())
+	return s.getBlockSnapshotKey(block.GetBlockid(), bucket, key)
+}
+
+// getBlockSnapshotKey 读取指定区块处的快照
+func (s *tdposSchedule) getBlockSnapshotKey(blockid []byte, bucket string, key []byte) ([]byte, error) {
+	reader, err := s.ledger.CreateSnapshot(blockid)
 	if err != nil {
 		s.log.Error("tdpos::getSnapshotKey::CreateSnapshot err.", "err", err)
 		return nil, err
@@ -162,7 +171,15 @@ func (s *tdposSchedule) GetLeader(round int64) string {
 	if pos >= s.proposerNum {
 		return ""
 	}
-	return proposers[pos]
+	return s.getLeader(round, proposers, pos)
+}
+
+// getLeader 返回proposers中第pos个时隙在round实际应出块的节点，开启reputation时会跳过最近未出块的节点
+func (s *tdposSchedule) getLeader(round int64, proposers []string, pos int64) string {
+	if s.reputation == nil {
+		return proposers[pos]
+	}
+	return s.reputation.Substitute(round, proposers, proposers[pos])
 }
 
 func (s *tdposSchedule) calAddTime(round int64, tipHeight int64) int64 {
@@ -258,8 +275,17 @@ func (s *tdposSchedule) calTopKNominator(height int64) ([]string, error) {
 		s.log.Error("tdpos::calculateTopK::load nominate read set err.")
 		return nil, err
 	}
+	jailed, err := s.getJailed(height)
+	if err != nil {
+		s.log.Error("tdpos::calculateTopK::load jail read set err.", "err", err)
+		return nil, err
+	}
 	var termBallotSli termBallotsSlice
 	for candidate := range nominateValue {
+		// 双签被禁闭的候选人跳过
+		if jailed[candidate] {
+			continue
+		}
 		candidateBallot := &termBallots{
 			Address: candidate,
 		}
@@ -299,6 +325,34 @@ func (s *tdposSchedule) calTopKNominator(height int64) ([]string, error) {
 	return proposers, nil
 }
 
+// getJailed 返回以height计算下一轮候选人时仍处于禁闭期的候选人
+// 禁闭记录与提名记录同样读取height-3的快照，下一轮的term按height所在term加一计算
+func (s *tdposSchedule) getJailed(height int64) (map[string]bool, error) {
+	jKey := fmt.Sprintf("%s_%d_%s", s.consensusName, s.consensusVersion, jailKey)
+	res, err := s.getSnapshotKey(height-3, s.bindContractBucket, []byte(jKey))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	jailValue := NewJailValue()
+	if err := json.Unmarshal(res, &jailValue); err != nil {
+		return nil, err
+	}
+	term, err := s.getTerm(height)
+	if err != nil {
+		return nil, err
+	}
+	jailed := make(map[string]bool)
+	for candidate, untilTerm := range jailValue {
+		if term+1 <= untilTerm {
+			jailed[candidate] = true
+		}
+	}
+	return jailed, nil
+}
+
 // CalHisProposers 主要用于追块时、计算历史高度所对应的候选人值
 func (s *tdposSchedule) CalOldProposers(height int64, timestamp int64, storage []byte) ([]string, error) {
 	if height < s.startHeight+3 {
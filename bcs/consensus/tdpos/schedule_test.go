@@ -2,10 +2,12 @@ package tdpos
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
 )
 
@@ -63,6 +65,56 @@ func TestGetLeader(t *testing.T) {
 	s.GetLeader(3)
 }
 
+func TestGetLeaderReputation(t *testing.T) {
+	cStr := `{
+		"version": "2",
+        "timestamp": "1559021720000000000",
+        "proposer_num": "2",
+        "period": "3000",
+        "alternate_interval": "3000",
+        "term_interval": "6000",
+        "block_num": "20",
+        "vote_unit_price": "1",
+        "init_proposer": {
+            "1": ["dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"]
+        },
+		"reputation": {"window": 10, "probe_interval": 1}
+	}`
+	if _, err := buildConfigs([]byte(cStr)); err != reputation.ErrInvalidConfig {
+		t.Error("invalid reputation config should be rejected.", "err", err)
+		return
+	}
+	cStr = strings.Replace(cStr, `"probe_interval": 1`, `"probe_interval": 3`, 1)
+	tdposCfg, err := buildConfigs([]byte(cStr))
+	if err != nil || tdposCfg.Reputation == nil {
+		t.Error("Config unmarshal err", "err", err)
+		return
+	}
+	cCtx, err := prepare(cStr)
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	i := NewTdposConsensus(*cCtx, getConfig(cStr))
+	tdpos, _ := i.(*tdposConsensus)
+	if tdpos.election.reputation == nil {
+		t.Error("reputation should be enabled.")
+		return
+	}
+	// fake ledger的block均由 dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN 生成
+	l, _ := cCtx.Ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 22; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	proposers := tdposCfg.InitProposer["1"]
+	if leader := tdpos.election.getLeader(23, proposers, 1); leader != proposers[0] {
+		t.Error("unreliable proposer should be skipped.", "leader", leader)
+	}
+	if leader := tdpos.election.getLeader(23, proposers, 0); leader != proposers[0] {
+		t.Error("reliable proposer should be kept.", "leader", leader)
+	}
+}
+
 func NominateKey1() []byte {
 	n := NewNominateValue()
 	m1 := make(map[string]int64)
@@ -241,3 +293,61 @@ func TestMinerScheduling(t *testing.T) {
 		return
 	}
 }
+
+func TestCalTopKNominatorJail(t *testing.T) {
+	cStr := getTdposConsensusConf()
+	tdposCfg, err := buildConfigs([]byte(cStr))
+	if err != nil {
+		t.Error("Config unmarshal err", "err", err)
+	}
+	cCtx, err := prepare(getTdposConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	s := NewSchedule(tdposCfg, cCtx.XLog, cCtx.Ledger, 1)
+	if s == nil {
+		t.Error("NewSchedule error.")
+		return
+	}
+	l, _ := s.ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 6; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	l.SetConsensusStorage(3, SetTdposStorage(1, nil))
+	l.SetConsensusStorage(4, SetTdposStorage(2, nil))
+	l.SetConsensusStorage(5, SetTdposStorage(2, nil))
+	l.SetConsensusStorage(6, SetTdposStorage(3, nil))
+	prefix := "tdpos_2_"
+	l.SetSnapshot(tdposBucket, []byte(prefix+nominateKey), NominateKey1())
+	l.SetSnapshot(tdposBucket, []byte(prefix+voteKeyPrefix+"TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"), VoteKey1())
+	l.SetSnapshot(tdposBucket, []byte(prefix+voteKeyPrefix+"SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"), VoteKey2())
+	l.SetSnapshot(tdposBucket, []byte(prefix+voteKeyPrefix+"akf7qunmeaqb51Wu418d6TyPKp4jdLdpV"), VoteKey3())
+	p, err := s.calTopKNominator(6)
+	if err != nil {
+		t.Error("calTopKNominator error.", "err", err)
+		return
+	}
+	if !common.AddressEqual(p, []string{"akf7qunmeaqb51Wu418d6TyPKp4jdLdpV", "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"}) {
+		t.Error("calTopKNominator cal err.", "p", p)
+		return
+	}
+
+	// 高度6所在term为3，禁闭至term 3的候选人可参与term 4的选举
+	jail := NewJailValue()
+	jail["akf7qunmeaqb51Wu418d6TyPKp4jdLdpV"] = 3
+	jb, _ := json.Marshal(jail)
+	l.SetSnapshot(tdposBucket, []byte(prefix+jailKey), jb)
+	p, _ = s.calTopKNominator(6)
+	if !common.AddressEqual(p, []string{"akf7qunmeaqb51Wu418d6TyPKp4jdLdpV", "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"}) {
+		t.Error("jail should have expired.", "p", p)
+		return
+	}
+	jail["akf7qunmeaqb51Wu418d6TyPKp4jdLdpV"] = 4
+	jb, _ = json.Marshal(jail)
+	l.SetSnapshot(tdposBucket, []byte(prefix+jailKey), jb)
+	p, _ = s.calTopKNominator(6)
+	if !common.AddressEqual(p, []string{"TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY", "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"}) {
+		t.Error("jailed candidate should be skipped.", "p", p)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/xuperchain/xupercore/kernel/common/xcontext"
@@ -13,9 +14,12 @@ import (
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	quorumcert "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
 
 	"github.com/xuperchain/xupercore/kernel/consensus/def"
 	"github.com/xuperchain/xupercore/lib/logs"
@@ -36,6 +40,11 @@ type tdposConsensus struct {
 	contract  contract.Manager
 	kMethod   map[string]contract.KernMethod
 	log       logs.Logger
+	// 开启slash_config时用于校验和发现双签证据
+	verifier *evidence.Verifier
+	detector *evidence.Detector
+	// 开启reward_config时用于结算每个区块的奖励
+	award awardCalculator
 }
 
 func NewTdposConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensus.ConsensusImplInterface {
@@ -67,6 +76,9 @@ func NewTdposConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consens
 		return nil
 	}
 	schedule.address = cCtx.Network.PeerInfo().Account
+	if xconfig.Reputation != nil {
+		schedule.reputation = reputation.NewReputation(xconfig.Reputation, cCtx.Ledger, schedule.GetValidators, cCfg.StartHeight)
+	}
 
 	status := &TdposStatus{
 		Version:     xconfig.Version,
@@ -98,6 +110,24 @@ func NewTdposConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consens
 		contractGetTdposInfos:     tdpos.runGetTdposInfos,
 	}
 
+	if xconfig.SlashConfig != nil {
+		tdposKMethods[contractSubmitEvidence] = tdpos.runSubmitEvidence
+		// 开启BFT时在initBFT中替换为smr使用的crypto
+		tdpos.verifier = evidence.NewVerifier(cCrypto.NewCBFTCryptoWithHasher(cCtx.Address, cCtx.Crypto, cCtx.Hasher), tdpos.slotOf)
+		tdpos.detector = evidence.NewDetector(tdpos.verifier)
+	}
+
+	if xconfig.RewardConfig != nil {
+		award, ok := cCtx.Ledger.(awardCalculator)
+		if !ok {
+			cCtx.XLog.Error("consensus:tdpos:NewTdposConsensus: reward config is set", "err", ErrNoAward)
+			return nil
+		}
+		tdpos.award = award
+		tdposKMethods[contractClaimReward] = tdpos.runClaimReward
+		tdposKMethods[contractGetReward] = tdpos.runGetReward
+	}
+
 	tdpos.kMethod = tdposKMethods
 
 	// 凡属于共识升级的逻辑，新建的Tdpos实例将直接将当前值置为true，原因是上一共识模块已经在当前值生成了高度为trigger height的区块，新的实例会再生成一边
@@ -137,9 +167,9 @@ Again:
 	}
 	// 查当前term 和 pos是否是自己
 	tp.election.curTerm = term
-	tp.election.miner = tp.election.validators[pos]
+	tp.election.miner = tp.election.getLeader(tp.election.ledger.QueryTipBlockHeader().GetHeight()+1, tp.election.validators, pos)
 	// master check
-	if tp.election.validators[pos] == tp.election.address {
+	if tp.election.miner == tp.election.address {
 		tp.log.Debug("consensus:tdpos:CompeteMaster: now xterm infos", "term", term, "pos", pos, "blockPos", blockPos, "master", true, "height", tp.election.ledger.QueryTipBlockHeader().GetHeight())
 		s := tp.needSync()
 		return true, s, nil
@@ -176,12 +206,17 @@ func (tp *tdposConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.Bloc
 		tp.log.Error("consensus:tdpos:CheckMinerMatch: CalculateProposers error", "err", err)
 		return false, err
 	}
-	if wantProposers[pos] != string(block.GetProposer()) {
+	if want := tp.election.getLeader(block.GetHeight(), wantProposers, pos); want != string(block.GetProposer()) {
 		tp.log.Error("consensus:tdpos:CheckMinerMatch: invalid proposer",
-			"want", wantProposers[pos], "have", string(block.GetProposer()),
+			"want", want, "have", string(block.GetProposer()),
 			"wantProposers", wantProposers, "pos", pos)
 		return false, ErrInvalidProposer
 	}
+	tp.detectBlock(block)
+	if err := tp.checkAwardTx(block); err != nil {
+		tp.log.Warn("consensus:tdpos:CheckMinerMatch: invalid award tx", "err", err, "blockid", utils.F(block.GetBlockid()))
+		return false, err
+	}
 
 	if !tp.election.enableChainedBFT {
 		return true, nil
@@ -196,6 +231,7 @@ func (tp *tdposConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.Bloc
 		tp.log.Warn("consensus:tdpos:CheckMinerMatch: OldQCToNew error.", "logid", ctx.GetLog().GetLogId(), "err", err, "blockId", utils.F(block.GetBlockid()))
 		return false, err
 	}
+	tp.detectVotes(justify)
 	preBlock, _ := tp.election.ledger.QueryBlockHeader(block.GetPreHash())
 	prestorage, _ := preBlock.GetConsensusStorage()
 	validators, err := tp.election.CalOldProposers(preBlock.GetHeight(), preBlock.GetTimestamp(), prestorage)
@@ -223,7 +259,7 @@ func (tp *tdposConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, [
 			"blockPos", blockPos, "tp.election.blockNum", tp.election.blockNum, "pos", pos, "tp.election.proposerNum", tp.election.proposerNum)
 		return nil, nil, ErrTimeoutBlock
 	}
-	if tp.election.validators[pos] != tp.election.address {
+	if tp.election.getLeader(height, tp.election.validators, pos) != tp.election.address {
 		return nil, nil, ErrTimeoutBlock
 	}
 	storage := common.ConsensusStorage{
@@ -258,6 +294,10 @@ func (tp *tdposConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, [
 	if truncate {
 		tp.log.Warn("consensus:tdpos:ProcessBeforeMiner: last block not confirmed, walk to previous block",
 			"target", utils.F(qc.GetProposalId()), "ledger", tipBlock.GetHeight())
+		if err := evidence.CheckRedo(tipBlock, qc.GetProposalId(), tp.election.address, tp.election.ledger.QueryBlockHeader); err != nil {
+			tp.log.Warn("consensus:tdpos:ProcessBeforeMiner: skip redo", "err", err)
+			return nil, nil, err
+		}
 		storage.TargetBits = int32(tipBlock.GetHeight())
 		storageBytes, _ := json.Marshal(storage)
 		return qc.GetProposalId(), storageBytes, nil
@@ -294,7 +334,7 @@ func (tp *tdposConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
 		return ErrSchedule
 	}
 	var nextValidators []string
-	if tp.election.validators[pos] == tp.election.address && string(block.GetProposer()) == tp.election.address {
+	if tp.election.getLeader(block.GetHeight(), tp.election.validators, pos) == tp.election.address && string(block.GetProposer()) == tp.election.address {
 		// 如果是当前矿工，检测到下一轮需变更validates，且下一轮proposer并不在节点列表中，此时需在广播列表中新加入节点
 		nextValidators = tp.election.GetValidators(block.GetHeight() + 1)
 	}
@@ -327,14 +367,20 @@ func (tp *tdposConsensus) Start() error {
 
 func (tp *tdposConsensus) initBFT() error {
 	// create smr/ chained-bft实例, 需要新建CBFTCrypto、pacemaker和saftyrules实例
-	cryptoClient := cCrypto.NewCBFTCrypto(tp.cCtx.Address, tp.cCtx.Crypto)
+	cryptoClient := cCrypto.NewCBFTCryptoWithHasher(tp.cCtx.Address, tp.cCtx.Crypto, tp.cCtx.Hasher)
+	cryptoClient.BcName = tp.bcName
+	if tp.verifier != nil {
+		tp.verifier.Crypto = cryptoClient
+	}
 	qcTree := quorumcert.InitQCTree(tp.status.StartHeight, tp.cCtx.Ledger, tp.cCtx.XLog)
 	if qcTree == nil {
 		tp.log.Error("consensus:tdpos:NewTdposConsensus: init QCTree err", "startHeight", tp.status.StartHeight)
 		return errors.New("init bft init qcTree error")
 	}
+	// view超时时长为一个矿工的完整出块周期，即该周期内未收到新的QC则进入超时流程
 	pacemaker := &chainedBft.DefaultPaceMaker{
 		CurrentView: tp.status.StartHeight,
+		BaseTimeout: time.Duration(tp.election.period*tp.election.blockNum) * time.Millisecond,
 	}
 	// 重启状态检查1，pacemaker需要重置
 	tipHeight := tp.cCtx.Ledger.QueryTipBlockHeader().GetHeight()
@@ -344,9 +390,14 @@ func (tp *tdposConsensus) initBFT() error {
 	saftyrules := &chainedBft.DefaultSaftyRules{
 		Crypto: cryptoClient,
 		QcTree: qcTree,
+		Store:  chainedBft.NewSafetyStore(tp.cCtx.DataDir),
 		Log:    tp.cCtx.XLog,
 	}
 	smr := chainedBft.NewSmr(tp.bcName, tp.election.address, tp.log, tp.cCtx.Network, cryptoClient, pacemaker, saftyrules, tp.election, qcTree)
+	if smr == nil {
+		tp.log.Error("consensus:tdpos:initBFT: create smr error")
+		return errors.New("init bft smr error")
+	}
 	// 重启状态检查2，重做tipBlock，此时需重装载justify签名
 	if !bytes.Equal(qcTree.GetGenesisQC().In.GetProposalId(), qcTree.GetRootQC().In.GetProposalId()) {
 		for i := int64(0); i < 3; i++ {
@@ -392,3 +443,53 @@ func (tp *tdposConsensus) GetJustifySigns(block cctx.BlockInterface) []*chainedB
 	signs := common.OldSignToNew(b)
 	return signs
 }
+
+// slotOf 返回timestamp对应的出块时隙，tdpos中每个(term, pos, blockPos)仅允许出一个区块
+func (tp *tdposConsensus) slotOf(timestamp int64) (string, error) {
+	term, pos, blockPos := tp.election.minerScheduling(timestamp)
+	if blockPos < 0 || blockPos >= tp.election.blockNum || pos >= tp.election.proposerNum {
+		return "", ErrSchedule
+	}
+	return fmt.Sprintf("%d_%d_%d", term, pos, blockPos), nil
+}
+
+// detectBlock 记录通过proposer校验的区块，发现双签时输出可直接提交的证据
+func (tp *tdposConsensus) detectBlock(block cctx.BlockInterface) {
+	if tp.detector == nil {
+		return
+	}
+	tp.reportEvidences(tp.detector.AddBlock(evidence.BlockHeader(block)))
+}
+
+// detectVotes 记录justify中的vote签名，发现同一validator对冲突区块均投票时输出证据
+func (tp *tdposConsensus) detectVotes(justify quorumcert.QuorumCertInterface) {
+	if tp.detector == nil || justify == nil {
+		return
+	}
+	tp.reportEvidences(tp.detector.AddVotes(justify.GetProposalId(), justify.GetSignsInfo()))
+}
+
+// reportEvidences 将发现的双签证据以交易形式提交到链上，由绑定合约的submitEvidence执行惩罚
+func (tp *tdposConsensus) reportEvidences(evidences []*evidence.Evidence) {
+	for _, e := range evidences {
+		req, err := evidence.NewEvidenceRequest(tp.election.bindContractBucket, e)
+		if err != nil {
+			continue
+		}
+		tp.log.Warn("consensus:tdpos: found double sign evidence", "type", e.Type, "proposer", string(e.Blocks[0].Proposer),
+			"contract", req.ContractName, "method", req.MethodName, "evidence", string(req.Args[evidence.ArgEvidence]))
+		if tp.cCtx.Tx != nil {
+			// 区块校验过程中发现证据，异步提交避免与状态机的处理互相等待
+			go tp.submitEvidence(req)
+		}
+	}
+}
+
+func (tp *tdposConsensus) submitEvidence(req *protos.InvokeRequest) {
+	txid, err := tp.cCtx.Tx.InvokeTx(&tp.cCtx.BaseCtx, req)
+	if err != nil {
+		tp.log.Error("consensus:tdpos: submit evidence tx error", "err", err)
+		return
+	}
+	tp.log.Info("consensus:tdpos: submit evidence tx", "txid", utils.F(txid))
+}
@@ -0,0 +1,57 @@
+package xpoa
+
+import (
+	"encoding/hex"
+	"sync"
+
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/lib/crypto/bls"
+)
+
+// blsKeyReader 从账本读取validator通过registerBlsKey注册的BLS公钥
+// 注册后的公钥不可修改，因此读取成功的结果可直接缓存
+type blsKeyReader struct {
+	bucket string
+	ledger cctx.LedgerRely
+	cache  sync.Map
+}
+
+func newBlsKeyReader(bucket string, ledger cctx.LedgerRely) *blsKeyReader {
+	return &blsKeyReader{
+		bucket: bucket,
+		ledger: ledger,
+	}
+}
+
+// GetBlsPublicKey 返回address注册的BLS公钥，未注册时返回nil
+func (r *blsKeyReader) GetBlsPublicKey(address string) (*bls.PublicKey, error) {
+	if v, ok := r.cache.Load(address); ok {
+		return v.(*bls.PublicKey), nil
+	}
+	reader, err := r.ledger.GetTipXMSnapshotReader()
+	if err != nil {
+		return nil, err
+	}
+	res, err := reader.Get(r.bucket, []byte(blsKeyPrefix+address))
+	if err != nil || res == nil {
+		return nil, err
+	}
+	pk, err := bls.NewPublicKey(res)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Store(address, pk)
+	return pk, nil
+}
+
+// loadBlsPrivateKey 解析本地节点hex编码的BLS私钥，未配置时返回nil
+func loadBlsPrivateKey(addr *cctx.Address) (*bls.PrivateKey, error) {
+	if addr == nil || addr.BlsPrivateKeyStr == "" {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(addr.BlsPrivateKeyStr)
+	if err != nil {
+		return nil, err
+	}
+	return bls.NewPrivateKey(b)
+}
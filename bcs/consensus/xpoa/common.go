@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 )
 
 var (
@@ -15,6 +18,10 @@ var (
 	tooLowHeight     = errors.New("The height should be higher than 3.")
 	aclErr           = errors.New("Xpoa needs valid acl account.")
 	scheduleErr      = errors.New("minerScheduling overflow")
+	blsKeyErr        = errors.New("Bls public key or proof is invalid.")
+	blsKeyExistErr   = errors.New("Bls public key has been registered.")
+	initSmrErr       = errors.New("Init chained-bft smr error.")
+	slashConfigErr   = errors.New("Xpoa has no stake, burn ratio in slash config should be 0.")
 )
 
 const (
@@ -23,6 +30,15 @@ const (
 	validateKeys         = "validates"
 	contractGetValidates = "getValidates"
 	contractEditValidate = "editValidates"
+	// BLS模式下validator注册BLS公钥的合约方法
+	contractRegisterBlsKey = "registerBlsKey"
+	blsKeyPrefix           = "bls_"
+	// bft_config中开启BLS聚合签名的配置项
+	enableBlsKey = "enable_bls"
+	// 提交双签证据的合约方法
+	contractSubmitEvidence = evidence.MethodSubmitEvidence
+	jailKey                = "jail"
+	evidenceKeyPrefix      = "evidence_"
 
 	fee = 1000
 
@@ -37,9 +53,30 @@ type xpoaConfig struct {
 	Period       int64        `json:"period"`
 	InitProposer ProposerInfo `json:"init_proposer"`
 
+	// 存在即开启BFT，其中"enable_bls"为true时开启BLS聚合签名
 	EnableBFT map[string]bool `json:"bft_config,omitempty"`
+
+	// 双签惩罚策略，存在即开启证据提交，xpoa没有质押，仅支持禁闭
+	SlashConfig *evidence.Policy `json:"slash_config,omitempty"`
+
+	// leader替换策略，存在即开启，轮值到最近未出块的validator时由顺位下一个validator代为出块
+	Reputation *reputation.Config `json:"reputation,omitempty"`
+}
+
+// validateSlashConfig 校验xpoa的双签惩罚策略
+func validateSlashConfig(p *evidence.Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.BurnRatio != 0 || p.VoteBurnRatio != 0 {
+		return slashConfigErr
+	}
+	return nil
 }
 
+// jailValue validator禁闭至的时间戳，单位为纳秒
+type jailValue map[string]int64
+
 type ProposerInfo struct {
 	Address []string `json:"address"`
 }
@@ -1,14 +1,18 @@
 package xpoa
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/lib/crypto/bls"
 )
 
 // runChangeValidates 候选人变更，替代原三代合约的add_validates/delete_validates/change_validates三个操作方法
@@ -104,6 +108,110 @@ func (x *xpoaConsensus) methodGetValidates(contractCtx contract.KContext) (*cont
 	return common.NewContractOKResponse(jsonBytes), nil
 }
 
+// methodRegisterBlsKey 注册发起者的BLS公钥，BLS模式下validator需注册后其vote签名才能被聚合验证
+// Args: public_key::hex编码的BLS公钥, proof::hex编码的公钥持有证明
+// 公钥注册后不可修改，避免已生成的聚合签名因公钥变更而无法验证
+func (x *xpoaConsensus) methodRegisterBlsKey(contractCtx contract.KContext) (*contract.Response, error) {
+	txArgs := contractCtx.Args()
+	pkBytes, err := hex.DecodeString(string(txArgs["public_key"]))
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, blsKeyErr.Error()), blsKeyErr
+	}
+	proof, err := hex.DecodeString(string(txArgs["proof"]))
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, blsKeyErr.Error()), blsKeyErr
+	}
+	// 持有证明用于防止rogue key攻击
+	pk, err := bls.NewPublicKey(pkBytes)
+	if err != nil || !pk.VerifyPossession(proof) {
+		return common.NewContractErrResponse(common.StatusBadRequest, blsKeyErr.Error()), blsKeyErr
+	}
+
+	key := []byte(blsKeyPrefix + contractCtx.Initiator())
+	oldKey, err := contractCtx.Get(x.election.bindContractBucket, key)
+	if err == nil && oldKey != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, blsKeyExistErr.Error()), blsKeyExistErr
+	}
+	if err := contractCtx.Put(x.election.bindContractBucket, key, pk.Bytes()); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	delta := contract.Limits{
+		XFee: fee,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse(pk.Bytes()), nil
+}
+
+// methodSubmitEvidence 提交双签证据，校验通过后按slash_config禁闭作恶validator
+// xpoa没有轮次概念，禁闭时长按JailTerms个完整的validators轮换周期计算
+// Args: evidence::json格式的evidence.Evidence
+func (x *xpoaConsensus) methodSubmitEvidence(contractCtx contract.KContext) (*contract.Response, error) {
+	// 1. 校验证据
+	e, err := evidence.ParseEvidence(contractCtx.Args())
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
+	record, err := x.verifier.Verify(e)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
+	// 1.1 同一作恶行为仅惩罚一次
+	eKey := []byte(fmt.Sprintf("%d_%s%s", x.election.consensusVersion, evidenceKeyPrefix, record.Key()))
+	res, err := contractCtx.Get(x.election.bindContractBucket, eKey)
+	if err == nil && res != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, evidence.ErrEvidenceExist.Error()), evidence.ErrEvidenceExist
+	}
+
+	// 2. 禁闭，禁闭期间validator不参与出块和投票
+	jailTerms, _ := x.config.SlashConfig.Penalty(record.Type)
+	if jailTerms > 0 {
+		curVali := x.election.initValidators
+		curValiBytes, err := contractCtx.Get(x.election.bindContractBucket,
+			[]byte(fmt.Sprintf("%d_%s", x.election.consensusVersion, validateKeys)))
+		if err == nil && curValiBytes != nil {
+			if curVali, err = loadValidatorsMultiInfo(curValiBytes); err != nil {
+				return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+			}
+		}
+		termTime := x.election.period * x.election.blockNum * int64(len(curVali)) * int64(time.Millisecond)
+		record.JailUntil = record.Timestamp + jailTerms*termTime
+
+		jKey := []byte(fmt.Sprintf("%d_%s", x.election.consensusVersion, jailKey))
+		jail := make(jailValue)
+		jailBytes, err := contractCtx.Get(x.election.bindContractBucket, jKey)
+		if err == nil && jailBytes != nil {
+			if err := json.Unmarshal(jailBytes, &jail); err != nil {
+				return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+			}
+		}
+		// 重复禁闭时取较晚的时间
+		if jail[record.Offender] < record.JailUntil {
+			jail[record.Offender] = record.JailUntil
+		}
+		jailBytes, err = json.Marshal(jail)
+		if err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+		if err := contractCtx.Put(x.election.bindContractBucket, jKey, jailBytes); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
+
+	// 3. 写入证据记录
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(x.election.bindContractBucket, eKey, recordBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	delta := contract.Limits{
+		XFee: fee,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse(recordBytes), nil
+}
+
 // isAuthAddress 判断输入aks是否能在贪心下仍能满足签名数量>33%(Chained-BFT装载) or 50%(一般情况)
 func (x *xpoaConsensus) isAuthAddress(validators []string, aks map[string]float64, threshold float64, enableBFT bool) bool {
 	// 0. 是否是单个候选人
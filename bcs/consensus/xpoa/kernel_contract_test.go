@@ -1,10 +1,19 @@
 package xpoa
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
+	xledger "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/lib/crypto/bls"
 )
 
 var (
@@ -110,3 +119,183 @@ func TestIsAuthAddress(t *testing.T) {
 		t.Error("isAuthAddress err.")
 	}
 }
+
+func getBlsXpoaConsensusConf() string {
+	return `{
+		"version": "2",
+        "period":3000,
+        "block_num":10,
+        "init_proposer": {
+            "address" : ["dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"]
+        },
+		"bft_config":{"enable_bls":true}
+	}`
+}
+
+func NewBlsArgs(sk, proofKey *bls.PrivateKey) map[string][]byte {
+	proof, _ := proofKey.ProvePossession()
+	a := make(map[string][]byte)
+	a["public_key"] = []byte(hex.EncodeToString(sk.PublicKey().Bytes()))
+	a["proof"] = []byte(hex.EncodeToString(proof))
+	return a
+}
+
+func TestMethodRegisterBlsKey(t *testing.T) {
+	cCtx, err := prepare(getBlsXpoaConsensusConf())
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	i := NewXpoaConsensus(*cCtx, getConfig(getBlsXpoaConsensusConf()))
+	xpoa, ok := i.(*xpoaConsensus)
+	if !ok {
+		t.Error("transfer err.")
+		return
+	}
+	if _, ok := xpoa.kMethod[contractRegisterBlsKey]; !ok || !xpoa.election.enableBLS {
+		t.Error("bls config error")
+		return
+	}
+	sk, _ := bls.GenerateKey(nil)
+	other, _ := bls.GenerateKey(nil)
+	m := NewEditM()
+	// 持有证明与公钥不匹配
+	if _, err := xpoa.methodRegisterBlsKey(mock.NewFakeKContext(NewBlsArgs(sk, other), m)); err != blsKeyErr {
+		t.Error("methodRegisterBlsKey invalid proof error", "error", err)
+		return
+	}
+	fakeCtx := mock.NewFakeKContext(NewBlsArgs(sk, sk), m)
+	if _, err := xpoa.methodRegisterBlsKey(fakeCtx); err != nil {
+		t.Error("methodRegisterBlsKey error", "error", err)
+		return
+	}
+	res, _ := fakeCtx.Get(xpoa.election.bindContractBucket, []byte(blsKeyPrefix+fakeCtx.Initiator()))
+	if !bytes.Equal(res, sk.PublicKey().Bytes()) {
+		t.Error("methodRegisterBlsKey store error")
+		return
+	}
+	// 公钥注册后不可修改
+	if _, err := xpoa.methodRegisterBlsKey(mock.NewFakeKContext(NewBlsArgs(other, other), m)); err != blsKeyExistErr {
+		t.Error("methodRegisterBlsKey register twice error", "error", err)
+	}
+}
+
+func getSlashXpoaConsensusConf(burnRatio int) string {
+	return fmt.Sprintf(`{
+		"version": "2",
+        "period":3000,
+        "block_num":10,
+        "init_proposer": {
+            "address" : ["dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"]
+        },
+		"slash_config": {"jail_terms": 2, "burn_ratio": %d}
+	}`, burnRatio)
+}
+
+// newSignedBlock 使用共识上下文中的节点私钥签名区块头
+func newSignedBlock(cCtx *cctx.ConsensusCtx, height, timestamp int64, preHash []byte, t *testing.T) *lpb.InternalBlock {
+	b := &lpb.InternalBlock{
+		Version:   1,
+		PreHash:   preHash,
+		Proposer:  []byte(cCtx.Address.Address),
+		Pubkey:    []byte(cCtx.Address.PublicKeyStr),
+		Height:    height,
+		Timestamp: timestamp,
+	}
+	id, err := xledger.MakeBlockIDWithHasher(b, cCtx.Hasher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Blockid = id
+	if b.Sign, err = cCtx.Crypto.SignECDSA(cCtx.Address.PrivateKey, id); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func NewEvidenceArgs(blocks ...*lpb.InternalBlock) map[string][]byte {
+	req, _ := evidence.NewEvidenceRequest(poaBucket, &evidence.Evidence{
+		Type:   evidence.TypeBlock,
+		Blocks: blocks,
+	})
+	return req.Args
+}
+
+func TestMethodSubmitEvidence(t *testing.T) {
+	cCtx, err := prepare(getSlashXpoaConsensusConf(10))
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	if i := NewXpoaConsensus(*cCtx, getConfig(getSlashXpoaConsensusConf(10))); i != nil {
+		t.Error("xpoa should reject burn ratio.")
+		return
+	}
+	i := NewXpoaConsensus(*cCtx, getConfig(getSlashXpoaConsensusConf(0)))
+	xpoa, ok := i.(*xpoaConsensus)
+	if !ok {
+		t.Error("transfer err.")
+		return
+	}
+	// 同一个period内的两个区块
+	ts := int64(1000*time.Hour) + int64(500*time.Millisecond)
+	b1 := newSignedBlock(cCtx, 10, ts, []byte{1}, t)
+	b2 := newSignedBlock(cCtx, 10, ts+int64(time.Second), []byte{2}, t)
+	fakeCtx := mock.NewFakeKContext(NewEvidenceArgs(b1, b2), NewEditM())
+	if _, err := xpoa.methodSubmitEvidence(fakeCtx); err != nil {
+		t.Error("methodSubmitEvidence error", "error", err)
+		return
+	}
+	// 禁闭2个完整轮换周期，即2*3000ms*10*2个validator
+	res, _ := fakeCtx.Get(poaBucket, []byte(fmt.Sprintf("2_%s", jailKey)))
+	jail := make(jailValue)
+	if err := json.Unmarshal(res, &jail); err != nil || jail[cCtx.Address.Address] != ts+int64(120*time.Second) {
+		t.Error("jail error", "jail", jail)
+		return
+	}
+	if _, err := xpoa.methodSubmitEvidence(fakeCtx); err != evidence.ErrEvidenceExist {
+		t.Error("repeat evidence should be rejected", "error", err)
+		return
+	}
+	b3 := newSignedBlock(cCtx, 11, ts+int64(3*time.Second), []byte{2}, t)
+	fakeCtx = mock.NewFakeKContext(NewEvidenceArgs(b1, b3), NewEditM())
+	if _, err := xpoa.methodSubmitEvidence(fakeCtx); err != evidence.ErrNotConflict {
+		t.Error("non-conflicting blocks should be rejected", "error", err)
+	}
+}
+
+func TestReportEvidences(t *testing.T) {
+	cCtx, err := prepare(getSlashXpoaConsensusConf(0))
+	if err != nil {
+		t.Error("prepare error", "error", err)
+		return
+	}
+	txRely := mock.NewFakeTxRely()
+	cCtx.Tx = txRely
+	i := NewXpoaConsensus(*cCtx, getConfig(getSlashXpoaConsensusConf(0)))
+	xpoa, ok := i.(*xpoaConsensus)
+	if !ok {
+		t.Error("transfer err.")
+		return
+	}
+	ts := int64(1000*time.Hour) + int64(500*time.Millisecond)
+	b1 := newSignedBlock(cCtx, 10, ts, []byte{1}, t)
+	b2 := newSignedBlock(cCtx, 10, ts+int64(time.Second), []byte{2}, t)
+	xpoa.reportEvidences(xpoa.detector.AddBlock(b1))
+	xpoa.reportEvidences(xpoa.detector.AddBlock(b2))
+
+	// 发现双签后提交调用submitEvidence的交易
+	select {
+	case req := <-txRely.Requests:
+		if req.GetContractName() != poaBucket || req.GetMethodName() != evidence.MethodSubmitEvidence {
+			t.Error("evidence request error", "req", req)
+			return
+		}
+		fakeCtx := mock.NewFakeKContext(req.GetArgs(), NewEditM())
+		if _, err := xpoa.methodSubmitEvidence(fakeCtx); err != nil {
+			t.Error("evidence tx should be accepted", "error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("evidence tx not submitted")
+	}
+}
@@ -1,10 +1,12 @@
 package xpoa
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 	"github.com/xuperchain/xupercore/kernel/consensus/context"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/lib/logs"
@@ -26,10 +28,14 @@ type xpoaSchedule struct {
 	startHeight    int64
 
 	enableBFT          bool
+	enableBLS          bool
 	consensusName      string
 	consensusVersion   int64
 	bindContractBucket string
 
+	// 非nil时开启leader替换
+	reputation *reputation.Reputation
+
 	log    logs.Logger
 	ledger cctx.LedgerRely
 }
@@ -48,6 +54,7 @@ func NewXpoaSchedule(xconfig *xpoaConfig, cCtx context.ConsensusCtx, startHeight
 	}
 	if xconfig.EnableBFT != nil {
 		s.enableBFT = true
+		s.enableBLS = xconfig.EnableBFT[enableBlsKey]
 		s.consensusName = "xpoa"
 		s.bindContractBucket = xpoaBucket
 	}
@@ -103,7 +110,15 @@ func (s *xpoaSchedule) GetLeader(round int64) string {
 		nTime += s.period * int64(time.Millisecond)
 	}
 	_, pos, _ := s.minerScheduling(nTime, len(v))
-	return v[pos]
+	return s.getLeader(round, v, pos)
+}
+
+// getLeader 返回validators中第pos个时隙在round实际应出块的节点，开启reputation时会跳过最近未出块的节点
+func (s *xpoaSchedule) getLeader(round int64, validators []string, pos int64) string {
+	if s.reputation == nil {
+		return validators[pos]
+	}
+	return s.reputation.Substitute(round, validators, validators[pos])
 }
 
 // GetValidators 用于计算目标round候选人信息，同时更新schedule address到internet地址映射
@@ -167,7 +182,7 @@ func (s *xpoaSchedule) GetLocalLeader(timestamp int64, round int64, storage []by
 	}
 	s.log.Debug("xpoa schedule miner Scheduling", "pos", pos, "blockPos",
 		blockPos, "timestamp", timestamp, "validators", localValidators, "leader", localValidators[pos])
-	return localValidators[pos]
+	return s.getLeader(round, localValidators, pos)
 }
 
 // getValidatesByBlockId 根据当前输入blockid，用快照的方式在xmodel中寻找<=当前blockid的最新的候选人值，若无则使用xuper.json中指定的初始值
@@ -209,7 +224,36 @@ func (s *xpoaSchedule) getValidates(height int64) ([]string, error) {
 		s.log.Error("Xpoa::getValidates::getValidatesByBlockId error.", "err", err)
 		return nil, err
 	}
-	return validators, nil
+	return s.filterJailed(b, validators), nil
+}
+
+// filterJailed 过滤掉在区块b时仍处于双签禁闭期的validator，禁闭记录同样读取区块b的快照
+// 过滤后为空时保持原集合，避免链停止出块
+func (s *xpoaSchedule) filterJailed(b cctx.BlockInterface, validators []string) []string {
+	reader, err := s.ledger.CreateSnapshot(b.GetBlockid())
+	if err != nil {
+		return validators
+	}
+	res, err := reader.Get(s.bindContractBucket, []byte(fmt.Sprintf("%d_%s", s.consensusVersion, jailKey)))
+	if err != nil || res == nil || res.PureData == nil || res.PureData.Value == nil {
+		return validators
+	}
+	jail := make(jailValue)
+	if err := json.Unmarshal(res.PureData.Value, &jail); err != nil {
+		s.log.Error("Xpoa::filterJailed::unmarshal jail error.", "err", err)
+		return validators
+	}
+	var result []string
+	for _, v := range validators {
+		if b.GetTimestamp() < jail[v] {
+			continue
+		}
+		result = append(result, v)
+	}
+	if len(result) == 0 {
+		return validators
+	}
+	return result
 }
 
 func (s *xpoaSchedule) UpdateValidator(height int64) bool {
@@ -8,6 +8,7 @@ import (
 
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
 )
 
@@ -72,6 +73,27 @@ func TestGetLeader(t *testing.T) {
 	}
 }
 
+func TestGetLeaderReputation(t *testing.T) {
+	s, err := NewSchedule("dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", InitValidators, true)
+	if err != nil {
+		t.Error("newSchedule error.")
+		return
+	}
+	// fake ledger的block均由 dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN 生成
+	l, _ := s.ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 22; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	if leader := s.getLeader(23, InitValidators, 1); leader != InitValidators[1] {
+		t.Error("getLeader without reputation error.", "leader", leader)
+		return
+	}
+	s.reputation = reputation.NewReputation(&reputation.Config{Window: 10, ProbeInterval: 3}, s.ledger, s.GetValidators, 0)
+	if leader := s.getLeader(23, InitValidators, 1); leader != InitValidators[0] {
+		t.Error("unreliable validator should be skipped.", "leader", leader)
+	}
+}
+
 func TestGetValidates(t *testing.T) {
 	s, err := NewSchedule("dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", InitValidators, true)
 	if err != nil {
@@ -96,3 +118,45 @@ func TestGetValidates(t *testing.T) {
 		t.Error("AddressEqual error1.", "v", v)
 	}
 }
+
+func TestGetValidatesJail(t *testing.T) {
+	s, err := NewSchedule("dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", InitValidators, true)
+	if err != nil {
+		t.Error("newSchedule error.")
+		return
+	}
+	l, _ := s.ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 6; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	l.SetSnapshot(poaBucket, []byte(fmt.Sprintf("0_%s", validateKeys)), ValidateKey1())
+	jail := jailValue{
+		"iYjtLcW6SVCiousAb5DFKWtWroahhEj4u": time.Now().Add(time.Hour).UnixNano(),
+	}
+	jb, _ := json.Marshal(jail)
+	l.SetSnapshot(poaBucket, []byte(fmt.Sprintf("0_%s", jailKey)), jb)
+	v, _ := s.getValidates(6)
+	if !common.AddressEqual(v, InitValidators) {
+		t.Error("jailed validator should be filtered.", "v", v)
+		return
+	}
+	// 禁闭到期
+	jail["iYjtLcW6SVCiousAb5DFKWtWroahhEj4u"] = time.Now().Add(-time.Hour).UnixNano()
+	jb, _ = json.Marshal(jail)
+	l.SetSnapshot(poaBucket, []byte(fmt.Sprintf("0_%s", jailKey)), jb)
+	v, _ = s.getValidates(6)
+	if !common.AddressEqual(v, newValidators) {
+		t.Error("jail should have expired.", "v", v)
+		return
+	}
+	// 全部被禁闭时保持原集合
+	for _, addr := range newValidators {
+		jail[addr] = time.Now().Add(time.Hour).UnixNano()
+	}
+	jb, _ = json.Marshal(jail)
+	l.SetSnapshot(poaBucket, []byte(fmt.Sprintf("0_%s", jailKey)), jb)
+	v, _ = s.getValidates(6)
+	if !common.AddressEqual(v, newValidators) {
+		t.Error("validators should be kept when all are jailed.", "v", v)
+	}
+}
@@ -3,6 +3,7 @@ package xpoa
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/xuperchain/xupercore/kernel/common/xcontext"
@@ -12,11 +13,14 @@ import (
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	quorumcert "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/reputation"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/consensus/def"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 func init() {
@@ -35,6 +39,9 @@ type xpoaConsensus struct {
 	contract      contract.Manager
 	kMethod       map[string]contract.KernMethod
 	log           logs.Logger
+	// 开启slash_config时用于校验和发现双签证据
+	verifier *evidence.Verifier
+	detector *evidence.Detector
 }
 
 // NewXpoaConsensus 初始化实例
@@ -72,6 +79,20 @@ func NewXpoaConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensu
 		return nil
 	}
 
+	if xconfig.SlashConfig != nil {
+		if err := validateSlashConfig(xconfig.SlashConfig); err != nil {
+			cCtx.XLog.Error("consensus:xpoa:NewXpoaConsensus: slash_config error", "error", err)
+			return nil
+		}
+	}
+
+	if xconfig.Reputation != nil {
+		if err := xconfig.Reputation.Validate(); err != nil {
+			cCtx.XLog.Error("consensus:xpoa:NewXpoaConsensus: reputation error", "error", err)
+			return nil
+		}
+	}
+
 	version, err := ParseVersion(cCfg.Config)
 	if err != nil {
 		cCtx.XLog.Error("consensus:xpoa:NewXpoaConsensus: version error", "error", err)
@@ -83,6 +104,9 @@ func NewXpoaConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensu
 		cCtx.XLog.Error("consensus:xpoa:NewXpoaSchedule error")
 		return nil
 	}
+	if xconfig.Reputation != nil {
+		schedule.reputation = reputation.NewReputation(xconfig.Reputation, cCtx.Ledger, schedule.GetValidators, cCfg.StartHeight)
+	}
 	// 创建status实例
 	status := &XpoaStatus{
 		Name:        "poa",
@@ -112,6 +136,17 @@ func NewXpoaConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensu
 		contractGetValidates: xpoa.methodGetValidates,
 	}
 
+	if schedule.enableBLS {
+		xpoaKMethods[contractRegisterBlsKey] = xpoa.methodRegisterBlsKey
+	}
+
+	if xconfig.SlashConfig != nil {
+		xpoaKMethods[contractSubmitEvidence] = xpoa.methodSubmitEvidence
+		// 开启BFT时在initBFT中替换为smr使用的crypto
+		xpoa.verifier = evidence.NewVerifier(cCrypto.NewCBFTCryptoWithHasher(cCtx.Address, cCtx.Crypto, cCtx.Hasher), xpoa.slotOf)
+		xpoa.detector = evidence.NewDetector(xpoa.verifier)
+	}
+
 	xpoa.kMethod = xpoaKMethods
 
 	// 凡属于共识升级的逻辑，新建的Xpoa实例将直接将当前值置为true，原因是上一共识模块已经在当前值生成了高度为trigger height的区块，新的实例会再生成一边
@@ -124,14 +159,32 @@ func NewXpoaConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensu
 
 func (x *xpoaConsensus) initBFT() error {
 	// create smr/ chained-bft实例, 需要新建CBFTCrypto、pacemaker和saftyrules实例
-	cryptoClient := cCrypto.NewCBFTCrypto(x.cCtx.Address, x.cCtx.Crypto)
+	cryptoClient := cCrypto.NewCBFTCryptoWithHasher(x.cCtx.Address, x.cCtx.Crypto, x.cCtx.Hasher)
+	cryptoClient.BcName = x.cCtx.BcName
+	if x.election.enableBLS {
+		// 本地未配置BLS私钥时仍可验证聚合签名，但无法作为validator投票
+		blsKey, err := loadBlsPrivateKey(x.cCtx.Address)
+		if err != nil {
+			x.log.Error("consensus:xpoa:initBFT: load bls private key error", "err", err)
+			return err
+		}
+		if blsKey == nil {
+			x.log.Warn("consensus:xpoa:initBFT: bls private key is empty, local node cannot vote")
+		}
+		cryptoClient.EnableBls(blsKey, newBlsKeyReader(x.election.bindContractBucket, x.cCtx.Ledger))
+	}
+	if x.verifier != nil {
+		x.verifier.Crypto = cryptoClient
+	}
 	qcTree := quorumcert.InitQCTree(x.status.StartHeight, x.cCtx.Ledger, x.cCtx.XLog)
 	if qcTree == nil {
 		x.log.Error("consensus:xpoa:NewXpoaConsensus: init QCTree err", "startHeight", x.status.StartHeight)
 		return nil
 	}
+	// view超时时长为一个矿工的完整出块周期，即该周期内未收到新的QC则进入超时流程
 	pacemaker := &chainedBft.DefaultPaceMaker{
 		CurrentView: x.status.StartHeight,
+		BaseTimeout: time.Duration(x.election.period*x.election.blockNum) * time.Millisecond,
 	}
 	// 重启状态检查1，pacemaker需要重置
 	tipHeight := x.cCtx.Ledger.QueryTipBlockHeader().GetHeight()
@@ -141,9 +194,14 @@ func (x *xpoaConsensus) initBFT() error {
 	saftyrules := &chainedBft.DefaultSaftyRules{
 		Crypto: cryptoClient,
 		QcTree: qcTree,
+		Store:  chainedBft.NewSafetyStore(x.cCtx.DataDir),
 		Log:    x.cCtx.XLog,
 	}
 	smr := chainedBft.NewSmr(x.cCtx.BcName, x.election.address, x.log, x.cCtx.Network, cryptoClient, pacemaker, saftyrules, x.election, qcTree)
+	if smr == nil {
+		x.log.Error("consensus:xpoa:initBFT: create smr error")
+		return initSmrErr
+	}
 	// 重启状态检查2，重做tipBlock，此时需重装载justify签名
 	if !bytes.Equal(qcTree.GetGenesisQC().In.GetProposalId(), qcTree.GetRootQC().In.GetProposalId()) {
 		for i := int64(0); i < 3; i++ {
@@ -152,6 +210,7 @@ func (x *xpoaConsensus) initBFT() error {
 				break
 			}
 			smr.LoadVotes(b.GetPreHash(), x.GetJustifySigns(b))
+			smr.LoadAggregateSign(b.GetPreHash(), x.GetJustifyAggregateSign(b))
 		}
 	}
 	x.smr = smr
@@ -188,7 +247,7 @@ Again:
 		x.log.Debug("consensus:xpoa:CompeteMaster: minerScheduling err", "pos", pos, "blockPos", blockPos)
 		goto Again
 	}
-	x.election.miner = x.election.validators[pos]
+	x.election.miner = x.election.getLeader(tipBlock.GetHeight()+1, x.election.validators, pos)
 	if x.election.miner == x.election.address {
 		x.log.Debug("consensus:xpoa:CompeteMaster", "isMiner", true, "height", tipBlock.GetHeight())
 		needSync := tipBlock.GetHeight() == 0 || string(tipBlock.GetProposer()) != x.election.miner
@@ -214,6 +273,7 @@ func (x *xpoaConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.BlockI
 			"have", string(block.GetProposer()), "blockId", utils.F(block.GetBlockid()))
 		return false, MinerSelectErr
 	}
+	x.detectBlock(block)
 	if !x.election.enableBFT {
 		return true, nil
 	}
@@ -228,6 +288,7 @@ func (x *xpoaConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.BlockI
 			"blockId", utils.F(block.GetBlockid()))
 		return false, err
 	}
+	x.detectVotes(justify)
 	preBlock, _ := x.election.ledger.QueryBlockHeader(block.GetPreHash())
 	preConStoreBytes, _ := preBlock.GetConsensusStorage()
 	validators, _ := x.election.GetLocalValidates(preBlock.GetTimestamp(), justify.GetProposalView(), preConStoreBytes)
@@ -269,6 +330,10 @@ func (x *xpoaConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []b
 	if truncate {
 		x.log.Warn("consensus:xpoa:ProcessBeforeMiner: last block not confirmed, walk to previous block",
 			"target", utils.F(qc.GetProposalId()), "ledger", tipBlock.GetHeight())
+		if err := evidence.CheckRedo(tipBlock, qc.GetProposalId(), x.election.address, x.election.ledger.QueryBlockHeader); err != nil {
+			x.log.Warn("consensus:xpoa:ProcessBeforeMiner: skip redo", "err", err)
+			return nil, nil, err
+		}
 		storage.TargetBits = int32(tipBlock.GetHeight())
 		bytes, _ := json.Marshal(storage)
 		return qc.GetProposalId(), bytes, nil
@@ -307,7 +372,7 @@ func (x *xpoaConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
 
 	var minerValidator []string
 	// 如果是当前矿工，则发送Proposal消息
-	if x.election.validators[pos] == x.election.address && string(block.GetProposer()) == x.election.address {
+	if x.election.getLeader(block.GetHeight(), x.election.validators, pos) == x.election.address && string(block.GetProposer()) == x.election.address {
 		minerValidator = x.election.GetValidators(block.GetHeight() + 1)
 	}
 
@@ -375,3 +440,58 @@ func (x *xpoaConsensus) GetJustifySigns(block cctx.BlockInterface) []*chainedBft
 	signs := common.OldSignToNew(b)
 	return signs
 }
+
+// GetJustifyAggregateSign 获取BLS模式下justify中的聚合签名
+func (x *xpoaConsensus) GetJustifyAggregateSign(block cctx.BlockInterface) *chainedBftPb.QuorumCertAggregateSign {
+	b, err := block.GetConsensusStorage()
+	if err != nil {
+		return nil
+	}
+	return common.OldAggregateSignToNew(b)
+}
+
+// slotOf 返回timestamp对应的出块时隙，xpoa中每个period仅允许出一个区块
+func (x *xpoaConsensus) slotOf(timestamp int64) (string, error) {
+	return fmt.Sprintf("%d", timestamp/int64(time.Millisecond)/x.election.period), nil
+}
+
+// detectBlock 记录通过proposer校验的区块，发现双签时输出可直接提交的证据
+func (x *xpoaConsensus) detectBlock(block cctx.BlockInterface) {
+	if x.detector == nil {
+		return
+	}
+	x.reportEvidences(x.detector.AddBlock(evidence.BlockHeader(block)))
+}
+
+// detectVotes 记录justify中的vote签名，发现同一validator对冲突区块均投票时输出证据
+func (x *xpoaConsensus) detectVotes(justify quorumcert.QuorumCertInterface) {
+	if x.detector == nil || justify == nil {
+		return
+	}
+	x.reportEvidences(x.detector.AddVotes(justify.GetProposalId(), justify.GetSignsInfo()))
+}
+
+// reportEvidences 将发现的双签证据以交易形式提交到链上，由绑定合约的submitEvidence执行惩罚
+func (x *xpoaConsensus) reportEvidences(evidences []*evidence.Evidence) {
+	for _, e := range evidences {
+		req, err := evidence.NewEvidenceRequest(x.election.bindContractBucket, e)
+		if err != nil {
+			continue
+		}
+		x.log.Warn("consensus:xpoa: found double sign evidence", "type", e.Type, "proposer", string(e.Blocks[0].Proposer),
+			"contract", req.ContractName, "method", req.MethodName, "evidence", string(req.Args[evidence.ArgEvidence]))
+		if x.cCtx.Tx != nil {
+			// 区块校验过程中发现证据，异步提交避免与状态机的处理互相等待
+			go x.submitEvidence(req)
+		}
+	}
+}
+
+func (x *xpoaConsensus) submitEvidence(req *protos.InvokeRequest) {
+	txid, err := x.cCtx.Tx.InvokeTx(&x.cCtx.BaseCtx, req)
+	if err != nil {
+		x.log.Error("consensus:xpoa: submit evidence tx error", "err", err)
+		return
+	}
+	x.log.Info("consensus:xpoa: submit evidence tx", "txid", utils.F(txid))
+}
@@ -166,6 +166,18 @@ func DetermineEVMAddress(evmAddr crypto.Address) (string, string, error) {
 	return addr, addrType, nil
 }
 
+// EVMAddressToXchainAccount 将evm地址转换为链上的xchain地址、合约名或者带前后缀的完整合约账户
+func EVMAddressToXchainAccount(evmAddr crypto.Address, chainName string) (string, error) {
+	addr, addrType, err := DetermineEVMAddress(evmAddr)
+	if err != nil {
+		return "", err
+	}
+	if addrType == contractAccountType {
+		addr = utils.GetAccountPrefix() + addr + "@" + chainName
+	}
+	return addr, nil
+}
+
 // determine an xchain address
 func DetermineXchainAddress(xAddr string) (string, string, error) {
 	var addr crypto.Address
@@ -133,6 +133,24 @@ func TestDetermineEVMAddress(t *testing.T) {
 	}
 }
 
+func TestEVMAddressToXchainAccount(t *testing.T) {
+	cases := map[string]string{
+		"3131313231313131313131313131313131313133": "XC1111111111111113@xuper",
+		"313131312D2D2D73746F72616765646174613131": "storagedata11",
+		"D1824C1050F55CA7E564243CE087706CACF1C687": "jSPJQSAR3NWoKcSFMxYGfcY8KVskvNMtm",
+	}
+	for evmAddrHex, expect := range cases {
+		evmAddr, _ := crypto.AddressFromHexString(evmAddrHex)
+		account, err := EVMAddressToXchainAccount(evmAddr, "xuper")
+		if err != nil {
+			t.Error(err)
+		}
+		if account != expect {
+			t.Errorf("expect %s got %s", expect, account)
+		}
+	}
+}
+
 func TestDetermineXchainAddress(t *testing.T) {
 	// contract account
 	evmAddrHex := "3131313231313131313131313131313131313133"
@@ -25,18 +25,27 @@ const (
 	initializeMethod    = "initialize"
 	evmParamJSONEncoded = "jsonEncoded"
 	evmInput            = "input"
+	evmRawTx            = "raw_tx"
 )
 
 type evmCreator struct {
 	vm *evm.EVM
+	// 校验以太坊交易的链id
+	chainId int64
 }
 
-func newEvmCreator(_ *bridge.InstanceCreatorConfig) (bridge.InstanceCreator, error) {
+func newEvmCreator(config *bridge.InstanceCreatorConfig) (bridge.InstanceCreator, error) {
 	opt := evm.Options{}
 	vm := evm.New(opt)
-	return &evmCreator{
+	creator := &evmCreator{
 		vm: vm,
-	}, nil
+	}
+	if config != nil {
+		if cfg, ok := config.VMConfig.(*contract.EVMConfig); ok {
+			creator.chainId = cfg.ChainId
+		}
+	}
+	return creator, nil
 }
 
 // CreateInstance instances an evm virtual machine instance which can run a single contract call
@@ -50,6 +59,7 @@ func (e *evmCreator) CreateInstance(ctx *bridge.Context, cp bridge.ContractCodeP
 		blockState: blockState,
 		cp:         cp,
 		fromCache:  ctx.ReadFromCache,
+		chainId:    e.chainId,
 	}, nil
 }
 
@@ -66,6 +76,7 @@ type evmInstance struct {
 	abi        []byte
 	gasUsed    uint64
 	fromCache  bool
+	chainId    int64
 }
 
 func (i *evmInstance) Exec() error {
@@ -101,6 +112,17 @@ func (i *evmInstance) Exec() error {
 		return i.deployContract()
 	}
 
+	// 部署的合约地址
+	callee, err := ContractNameToEVMAddress(i.ctx.ContractName)
+	if err != nil {
+		return err
+	}
+
+	// 中继的以太坊交易以交易的发起地址调用合约，参数即交易的data
+	if raw, ok := i.ctx.Args[evmRawTx]; ok {
+		return i.execRawTx(raw, callee)
+	}
+
 	var caller crypto.Address
 	if IsContractAccount(i.state.ctx.Initiator) {
 		caller, err = ContractAccountToEVMAddress(i.state.ctx.Initiator)
@@ -112,12 +134,6 @@ func (i *evmInstance) Exec() error {
 		return err
 	}
 
-	// 部署的合约地址
-	callee, err := ContractNameToEVMAddress(i.ctx.ContractName)
-	if err != nil {
-		return err
-	}
-
 	gas := uint64(contract.MaxLimits.Cpu)
 
 	// 如果客户端已经将参数进行了 abi 编码，那么此处不需要再进行编码，而且返回的结果也不需要 abi 解码。否则此处需要将参数 abi 编码同时将结果 abi 解码。
@@ -0,0 +1,70 @@
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/execution/evm/abi"
+
+	xchainpb "github.com/xuperchain/xupercore/protos"
+)
+
+// PackEventToLog 根据合约abi将合约事件还原为evm日志的topics和data，
+// 合约事件由unpackEventFromAbi按abi解码生成，事件body为json格式的参数数组
+func PackEventToLog(abiByte []byte, event *xchainpb.ContractEvent) ([]binary.Word256, []byte, error) {
+	spec, err := abi.ReadSpec(abiByte)
+	if err != nil {
+		return nil, nil, err
+	}
+	eventSpec, ok := spec.EventsByName[event.GetName()]
+	if !ok {
+		return nil, nil, fmt.Errorf("event %s not found in abi", event.GetName())
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(event.GetBody(), &raws); err != nil {
+		return nil, nil, err
+	}
+	vals := abi.GetPackingTypes(eventSpec.Inputs)
+	if len(raws) != len(vals) {
+		return nil, nil, fmt.Errorf("event %s expect %d args, got %d", event.GetName(), len(vals), len(raws))
+	}
+
+	args := make([]interface{}, len(vals))
+	for i := range vals {
+		// bytes类型的参数在事件中以hex字符串保存
+		if _, ok := vals[i].(*[]byte); ok {
+			var s string
+			if err := json.Unmarshal(raws[i], &s); err != nil {
+				return nil, nil, err
+			}
+			b, err := hex.DecodeString(s)
+			if err != nil {
+				return nil, nil, err
+			}
+			args[i] = b
+			continue
+		}
+		if err := json.Unmarshal(raws[i], vals[i]); err != nil {
+			return nil, nil, err
+		}
+		args[i] = packingValue(vals[i])
+	}
+	return abi.PackEvent(eventSpec, args...)
+}
+
+// packingValue 将解码得到的参数指针转换为abi编码可以接受的值
+func packingValue(v interface{}) interface{} {
+	if n, ok := v.(*big.Int); ok {
+		return n.String()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		return rv.Elem().Interface()
+	}
+	return v
+}
@@ -0,0 +1,61 @@
+package evm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/evm/abi"
+	"github.com/hyperledger/burrow/execution/exec"
+)
+
+func TestPackEventToLog(t *testing.T) {
+	abiJson := `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"from","type":"address"},{"indexed":true,"internalType":"uint256","name":"id","type":"uint256"},{"indexed":false,"internalType":"string","name":"key","type":"string"},{"indexed":false,"internalType":"uint256","name":"value","type":"uint256"},{"indexed":false,"internalType":"bytes32","name":"hash","type":"bytes32"},{"indexed":false,"internalType":"bool","name":"ok","type":"bool"}],"name":"transferEvent","type":"event"}]`
+	contractName := "transferEvent"
+
+	spec, err := abi.ReadSpec([]byte(abiJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventSpec := spec.EventsByName["transferEvent"]
+	from, err := ContractNameToEVMAddress("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := make([]byte, 32)
+	hash[0], hash[31] = 0xab, 0xcd
+	topics, data, err := abi.PackEvent(eventSpec, from, "7", "test", "12", hash, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := unpackEventFromAbi([]byte(abiJson), contractName, &exec.LogEvent{
+		Address: crypto.ZeroAddress,
+		Topics:  topics,
+		Data:    data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotTopics, gotData, err := PackEventToLog([]byte(abiJson), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gotTopics) != len(topics) {
+		t.Fatalf("expect %d topics, got %d", len(topics), len(gotTopics))
+	}
+	for i := range topics {
+		if gotTopics[i] != topics[i] {
+			t.Fatalf("topic %d mismatch, expect %x got %x", i, topics[i], gotTopics[i])
+		}
+	}
+	if !bytes.Equal(gotData, data) {
+		t.Fatalf("data mismatch, expect %x got %x", data, gotData)
+	}
+
+	event.Name = "notExist"
+	if _, _, err := PackEventToLog([]byte(abiJson), event); err == nil {
+		t.Fatal("expect error for unknown event")
+	}
+}
@@ -0,0 +1,331 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/engine"
+	"github.com/hyperledger/burrow/execution/exec"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+const (
+	// EthNonceBucket 以太坊地址下一笔交易的nonce，key为20字节的以太坊地址，value为十进制字符串
+	EthNonceBucket = "eth_nonce"
+	// EthTxBucket 已执行的以太坊交易，key为交易哈希，value为交易发起地址，
+	// 数据的RefTxid即执行该交易的链上交易id
+	EthTxBucket = "eth_tx"
+)
+
+var (
+	ErrInvalidRawTx     = errors.New("invalid raw transaction")
+	ErrTypedTx          = errors.New("typed transaction not supported, use legacy transaction")
+	ErrInvalidSignature = errors.New("invalid transaction signature")
+	ErrInvalidChainId   = errors.New("invalid chain id")
+	ErrRawTxDisabled    = errors.New("eth raw transaction disabled, evm chain id not configured")
+	ErrUnprotectedTx    = errors.New("only replay-protected (EIP-155) transactions allowed")
+	ErrRawTxCallee      = errors.New("eth raw transaction target mismatch with contract")
+	ErrRawTxValue       = errors.New("transfer value not supported")
+	ErrRawTxKnown       = errors.New("already known")
+	ErrNonceTooLow      = errors.New("nonce too low")
+	ErrNonceTooHigh     = errors.New("nonce too high")
+)
+
+// secp256k1曲线阶的一半，签名的S值不能超过该值，避免同一交易出现多种合法签名
+var secp256k1HalfN, _ = new(big.Int).SetString("7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a0", 16)
+
+// RawTx 以太坊legacy交易，支持EIP-155签名
+type RawTx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	GasLimit uint64
+	// 目标地址，为空表示部署合约
+	To    []byte
+	Value *big.Int
+	Data  []byte
+
+	// 交易哈希，即原始交易的keccak256
+	Hash []byte
+	// 根据签名恢复的发起地址
+	From crypto.Address
+	// 是否为包含chainId的EIP-155签名，非EIP-155签名的交易可以在其他链上重放
+	Protected bool
+}
+
+// DecodeRawTx 解析以太坊原始交易并根据签名恢复交易发起地址，
+// EIP-155签名的交易需要与chainId一致
+func DecodeRawTx(raw []byte, chainId int64) (*RawTx, error) {
+	if len(raw) == 0 {
+		return nil, ErrInvalidRawTx
+	}
+	// EIP-2718 typed transaction以小于0x7f的类型字节开头
+	if raw[0] <= 0x7f {
+		return nil, ErrTypedTx
+	}
+	items, err := rlpDecodeList(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != 9 {
+		return nil, ErrInvalidRawTx
+	}
+
+	tx := &RawTx{
+		GasPrice: new(big.Int).SetBytes(items[1]),
+		To:       items[3],
+		Value:    new(big.Int).SetBytes(items[4]),
+		Data:     items[5],
+		Hash:     crypto.Keccak256(raw),
+	}
+	if tx.Nonce, err = rlpUint64(items[0]); err != nil {
+		return nil, err
+	}
+	if tx.GasLimit, err = rlpUint64(items[2]); err != nil {
+		return nil, err
+	}
+	if len(tx.To) != 0 && len(tx.To) != crypto.AddressLength {
+		return nil, ErrInvalidRawTx
+	}
+
+	// 计算签名原文，EIP-155签名原文包含chainId
+	v := new(big.Int).SetBytes(items[6])
+	signItems := items[:6]
+	var recId int64
+	switch {
+	case v.Cmp(big.NewInt(27)) == 0 || v.Cmp(big.NewInt(28)) == 0:
+		recId = v.Int64() - 27
+	case v.Cmp(big.NewInt(35)) >= 0:
+		id := new(big.Int).Sub(v, big.NewInt(35))
+		id.Rsh(id, 1)
+		if !id.IsInt64() || id.Int64() != chainId {
+			return nil, ErrInvalidChainId
+		}
+		recId = v.Int64() - 35 - 2*chainId
+		signItems = append(append([][]byte{}, signItems...), big.NewInt(chainId).Bytes(), nil, nil)
+		tx.Protected = true
+	default:
+		return nil, ErrInvalidSignature
+	}
+
+	r, s := items[7], items[8]
+	if len(r) == 0 || len(r) > 32 || len(s) == 0 || len(s) > 32 ||
+		new(big.Int).SetBytes(s).Cmp(secp256k1HalfN) > 0 {
+		return nil, ErrInvalidSignature
+	}
+	sig := make([]byte, 65)
+	sig[0] = byte(27 + recId)
+	copy(sig[33-len(r):33], r)
+	copy(sig[65-len(s):], s)
+	pub, err := crypto.PublicKeyFromSignature(sig, crypto.Keccak256(rlpEncodeList(signItems)))
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	tx.From = pub.GetAddress()
+	return tx, nil
+}
+
+// rlpUint64 解析rlp编码的整数，整数使用去掉前导零的大端字节表示
+func rlpUint64(b []byte) (uint64, error) {
+	if len(b) > 8 || (len(b) > 0 && b[0] == 0) {
+		return 0, ErrInvalidRawTx
+	}
+	buf := make([]byte, 8)
+	copy(buf[8-len(b):], b)
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+// rlpDecodeList 解析元素都是字符串的rlp列表
+func rlpDecodeList(b []byte) ([][]byte, error) {
+	isList, content, rest, err := rlpSplit(b)
+	if err != nil {
+		return nil, err
+	}
+	if !isList || len(rest) != 0 {
+		return nil, ErrInvalidRawTx
+	}
+
+	var items [][]byte
+	for len(content) > 0 {
+		var item []byte
+		isList, item, content, err = rlpSplit(content)
+		if err != nil {
+			return nil, err
+		}
+		if isList {
+			return nil, ErrInvalidRawTx
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// rlpSplit 解析第一个rlp元素，返回元素是否为列表、元素内容和剩余数据
+func rlpSplit(b []byte) (bool, []byte, []byte, error) {
+	if len(b) == 0 {
+		return false, nil, nil, ErrInvalidRawTx
+	}
+	prefix := b[0]
+	switch {
+	case prefix < 0x80:
+		return false, b[:1], b[1:], nil
+	case prefix < 0xb8:
+		return rlpSplitContent(false, b, 1, uint64(prefix-0x80))
+	case prefix < 0xc0:
+		size, err := rlpSize(b, int(prefix-0xb7))
+		if err != nil {
+			return false, nil, nil, err
+		}
+		return rlpSplitContent(false, b, 1+int(prefix-0xb7), size)
+	case prefix < 0xf8:
+		return rlpSplitContent(true, b, 1, uint64(prefix-0xc0))
+	default:
+		size, err := rlpSize(b, int(prefix-0xf7))
+		if err != nil {
+			return false, nil, nil, err
+		}
+		return rlpSplitContent(true, b, 1+int(prefix-0xf7), size)
+	}
+}
+
+func rlpSize(b []byte, n int) (uint64, error) {
+	if len(b) < 1+n || b[1] == 0 {
+		return 0, ErrInvalidRawTx
+	}
+	size, err := rlpUint64(b[1 : 1+n])
+	if err != nil || size < 56 {
+		return 0, ErrInvalidRawTx
+	}
+	return size, nil
+}
+
+func rlpSplitContent(isList bool, b []byte, offset int, size uint64) (bool, []byte, []byte, error) {
+	if size > uint64(len(b)-offset) {
+		return false, nil, nil, fmt.Errorf("rlp data too short: %v", ErrInvalidRawTx)
+	}
+	end := offset + int(size)
+	return isList, b[offset:end], b[end:], nil
+}
+
+// rlpEncodeList 将字符串列表编码为rlp格式
+func rlpEncodeList(items [][]byte) []byte {
+	var content []byte
+	for _, item := range items {
+		if len(item) == 1 && item[0] < 0x80 {
+			content = append(content, item[0])
+			continue
+		}
+		content = append(content, rlpHeader(0x80, len(item))...)
+		content = append(content, item...)
+	}
+	return append(rlpHeader(0xc0, len(content)), content...)
+}
+
+func rlpHeader(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(size))
+	i := 0
+	for buf[i] == 0 {
+		i++
+	}
+	return append([]byte{offset + 55 + byte(8-i)}, buf[i:]...)
+}
+
+// execRawTx 执行中继的以太坊交易，合约内的msg.sender和tx.origin都是交易的发起地址，
+// 即发起地址转换得到的xchain地址，与提交交易并支付手续费的中继者无关
+func (i *evmInstance) execRawTx(raw []byte, callee crypto.Address) error {
+	tx, err := i.verifyRawTx(raw, callee)
+	if err != nil {
+		return err
+	}
+
+	gas := uint64(contract.MaxLimits.Cpu)
+	params := engine.CallParams{
+		CallType: exec.CallTypeCode,
+		Origin:   tx.From,
+		Caller:   tx.From,
+		Callee:   callee,
+		Input:    tx.Data,
+		Value:    big.NewInt(0),
+		Gas:      &gas,
+	}
+	out, err := i.vm.Execute(i.state, i.blockState, i, params, i.code)
+	if err != nil {
+		return err
+	}
+
+	i.gasUsed = uint64(contract.MaxLimits.Cpu) - *params.Gas
+	i.ctx.Output = &pb.Response{
+		Status: 200,
+		Body:   out,
+	}
+	return nil
+}
+
+// verifyRawTx 校验以太坊交易的签名、目标合约和nonce，并在链上状态中记录nonce和交易哈希，
+// 各节点执行时都基于链上状态校验，同一交易不能重复执行
+func (i *evmInstance) verifyRawTx(raw []byte, callee crypto.Address) (*RawTx, error) {
+	if i.chainId <= 0 {
+		return nil, ErrRawTxDisabled
+	}
+	tx, err := DecodeRawTx(raw, i.chainId)
+	if err != nil {
+		return nil, err
+	}
+	if !tx.Protected {
+		return nil, ErrUnprotectedTx
+	}
+	if !bytes.Equal(tx.To, callee.Bytes()) {
+		return nil, ErrRawTxCallee
+	}
+	if tx.Value.Sign() != 0 || (i.ctx.TransferAmount != "" && i.ctx.TransferAmount != "0") {
+		return nil, ErrRawTxValue
+	}
+
+	state := i.ctx.State
+	if _, err := state.Get(EthTxBucket, tx.Hash); err == nil {
+		return nil, ErrRawTxKnown
+	} else if err != sandbox.ErrNotFound {
+		return nil, err
+	}
+	value, err := state.Get(EthNonceBucket, tx.From.Bytes())
+	if err != nil && err != sandbox.ErrNotFound {
+		return nil, err
+	}
+	nonce, err := ParseEthNonce(value)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Nonce < nonce {
+		return nil, ErrNonceTooLow
+	}
+	if tx.Nonce > nonce {
+		return nil, ErrNonceTooHigh
+	}
+
+	if err := state.Put(EthNonceBucket, tx.From.Bytes(), []byte(strconv.FormatUint(nonce+1, 10))); err != nil {
+		return nil, err
+	}
+	if err := state.Put(EthTxBucket, tx.Hash, tx.From.Bytes()); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ParseEthNonce 解析链上记录的以太坊地址下一笔交易的nonce，没有执行过交易的地址为0
+func ParseEthNonce(value []byte) (uint64, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseUint(string(value), 10, 64)
+}
@@ -0,0 +1,146 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/burrow/crypto"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+// EIP-155示例交易，私钥为0x4646...46
+const eip155RawTx = "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+
+func TestDecodeRawTx(t *testing.T) {
+	raw, _ := hex.DecodeString(eip155RawTx)
+	tx, err := DecodeRawTx(raw, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce != 9 || tx.GasLimit != 21000 {
+		t.Errorf("unexpected nonce %d gas %d", tx.Nonce, tx.GasLimit)
+	}
+	if !tx.Protected {
+		t.Error("eip155 tx should be protected")
+	}
+	if tx.Value.String() != "1000000000000000000" {
+		t.Errorf("unexpected value %s", tx.Value)
+	}
+	if got := hex.EncodeToString(tx.To); got != strings.Repeat("35", 20) {
+		t.Errorf("unexpected to %s", got)
+	}
+	if got := strings.ToLower(tx.From.String()); got != "9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f" {
+		t.Errorf("unexpected sender %s", got)
+	}
+
+	if _, err := DecodeRawTx(raw, 2); err != ErrInvalidChainId {
+		t.Errorf("expect chain id error, got %v", err)
+	}
+	if _, err := DecodeRawTx(append([]byte{0x02}, raw...), 1); err != ErrTypedTx {
+		t.Errorf("expect typed tx error, got %v", err)
+	}
+	if _, err := DecodeRawTx(raw[:len(raw)-1], 1); err == nil {
+		t.Error("expect error for truncated tx")
+	}
+}
+
+// signRawTx 构造并签名以太坊legacy交易，chainId为0时使用非EIP-155签名
+func signRawTx(t *testing.T, key crypto.PrivateKey, nonce uint64, to crypto.Address, data []byte, chainId int64) []byte {
+	items := [][]byte{new(big.Int).SetUint64(nonce).Bytes(), nil, big.NewInt(21000).Bytes(), to.Bytes(), nil, data}
+	signItems := items
+	if chainId > 0 {
+		signItems = append(append([][]byte{}, items...), big.NewInt(chainId).Bytes(), nil, nil)
+	}
+	msg := rlpEncodeList(signItems)
+	sig, err := key.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(sig.RawBytes(), &rs); err != nil {
+		t.Fatal(err)
+	}
+
+	// 根据恢复的地址确定recovery id
+	r, sv := rs.R.Bytes(), rs.S.Bytes()
+	compact := make([]byte, 65)
+	copy(compact[33-len(r):33], r)
+	copy(compact[65-len(sv):], sv)
+	for recId := int64(0); recId < 2; recId++ {
+		compact[0] = byte(27 + recId)
+		pub, err := crypto.PublicKeyFromSignature(compact, crypto.Keccak256(msg))
+		if err != nil || pub.GetAddress() != key.GetPublicKey().GetAddress() {
+			continue
+		}
+		v := 27 + recId
+		if chainId > 0 {
+			v = 35 + 2*chainId + recId
+		}
+		return rlpEncodeList(append(items, big.NewInt(v).Bytes(), r, sv))
+	}
+	t.Fatal("recover signature failed")
+	return nil
+}
+
+func TestVerifyRawTx(t *testing.T) {
+	key := crypto.PrivateKeyFromSecret("eth sender", crypto.CurveTypeSecp256k1)
+	from := key.GetPublicKey().GetAddress()
+	callee, _ := ContractNameToEVMAddress("counter")
+	state := sandbox.NewXModelCache(&contract.SandboxConfig{XMReader: sandbox.NewMemXModel()})
+	newInstance := func(chainId int64) *evmInstance {
+		return &evmInstance{
+			ctx:     &bridge.Context{ContractName: "counter", State: state},
+			chainId: chainId,
+		}
+	}
+	ins := newInstance(1)
+
+	raw := signRawTx(t, key, 0, callee, []byte{1}, 1)
+	if _, err := newInstance(0).verifyRawTx(raw, callee); err != ErrRawTxDisabled {
+		t.Errorf("expect disabled error, got %v", err)
+	}
+	if _, err := ins.verifyRawTx(signRawTx(t, key, 0, callee, []byte{1}, 0), callee); err != ErrUnprotectedTx {
+		t.Errorf("expect unprotected error, got %v", err)
+	}
+	other, _ := ContractNameToEVMAddress("other")
+	if _, err := ins.verifyRawTx(raw, other); err != ErrRawTxCallee {
+		t.Errorf("expect callee error, got %v", err)
+	}
+	if _, err := ins.verifyRawTx(signRawTx(t, key, 1, callee, []byte{1}, 1), callee); err != ErrNonceTooHigh {
+		t.Errorf("expect nonce too high error, got %v", err)
+	}
+
+	// 发起地址即合约调用者，nonce和交易哈希记录在状态中
+	tx, err := ins.verifyRawTx(raw, callee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.From != from || !bytes.Equal(tx.Data, []byte{1}) {
+		t.Error("unexpected sender or data", tx.From)
+	}
+	value, _ := state.Get(EthNonceBucket, from.Bytes())
+	if nonce, _ := ParseEthNonce(value); nonce != 1 {
+		t.Errorf("expect nonce 1, got %d", nonce)
+	}
+	if value, _ := state.Get(EthTxBucket, tx.Hash); !bytes.Equal(value, from.Bytes()) {
+		t.Error("tx hash should be recorded with sender")
+	}
+
+	// 重放同一交易
+	if _, err := ins.verifyRawTx(raw, callee); err != ErrRawTxKnown {
+		t.Errorf("expect already known error, got %v", err)
+	}
+	if _, err := ins.verifyRawTx(signRawTx(t, key, 0, callee, []byte{2}, 1), callee); err != ErrNonceTooLow {
+		t.Errorf("expect nonce too low error, got %v", err)
+	}
+	if _, err := ins.verifyRawTx(signRawTx(t, key, 1, callee, []byte{2}, 1), callee); err != nil {
+		t.Errorf("next nonce should pass, got %v", err)
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/hyperledger/burrow/acm"
 	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/evm"
 	"github.com/hyperledger/burrow/permission"
 
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 )
 
 type stateManager struct {
@@ -113,46 +115,63 @@ func (s *stateManager) Transfer(from, to crypto.Address, amount *big.Int) error
 	return s.ctx.State.Transfer(fromAddr, toAddr, amount)
 }
 
+// blockStateManager 为EVM提供合约执行所在区块的信息
 type blockStateManager struct {
 	ctx *bridge.Context
+
+	// ancestor 已查询到的最早的祖先区块，继续向前查找时从该区块开始
+	ancestor  ledger.BlockHandle
+	ancestors map[uint64][]byte
 }
 
 func newBlockStateManager(ctx *bridge.Context) *blockStateManager {
 	return &blockStateManager{
-		ctx: ctx,
+		ctx:       ctx,
+		ancestor:  ctx.Block,
+		ancestors: make(map[uint64][]byte),
 	}
 }
 
-// LastBlockHeight
+// LastBlockHeight 返回合约执行所在区块的高度，对应block.number
 func (s *blockStateManager) LastBlockHeight() uint64 {
-	// TODO
-	return 0
-	//block, err := s.ctx.Core.QueryLastBlock()
-	//if err != nil {
-	//	return 0
-	//}
-	//return uint64(block.GetHeight())
+	if s.ctx.Block == nil {
+		return 0
+	}
+	return uint64(s.ctx.Block.GetHeight())
 }
 
-// LastBlockTime
+// LastBlockTime 返回合约执行所在区块的时间，对应block.timestamp
+// 区块时间在打包前无法确定，各执行路径统一使用父区块的时间戳
 func (s *blockStateManager) LastBlockTime() time.Time {
-	// TODO
-	return time.Time{}
-	//block, err := s.ctx.Core.QueryLastBlock()
-	//if err != nil {
-	//	return time.Time{}
-	//}
-	//timestamp := block.GetTimestamp()
-	//return time.Unix(timestamp/1e9, timestamp%1e9)
+	if s.ctx.Block == nil {
+		return time.Time{}
+	}
+	timestamp := s.ctx.Block.GetTimestamp()
+	return time.Unix(timestamp/1e9, timestamp%1e9)
 }
 
-// LastBlockHeight
+// BlockHash 返回祖先区块的id，对应blockhash(height)
+// 沿父区块向前查找，执行所在区块在分叉上时同样适用，不是最近256个祖先区块时返回全零哈希
 func (s *blockStateManager) BlockHash(height uint64) ([]byte, error) {
-	return nil, nil
-	// TODO
-	//block, err := s.ctx.Core.QueryBlockByHeight(int64(height))
-	//if err != nil {
-	//	return nil, err
-	//}
-	//return block.GetBlockid(), nil
+	zero := make([]byte, binary.Word256Bytes)
+	if s.ctx.Block == nil {
+		return zero, nil
+	}
+	blockHeight := uint64(s.ctx.Block.GetHeight())
+	if height >= blockHeight || blockHeight-height > evm.MaximumAllowedBlockLookBack {
+		return zero, nil
+	}
+	if id, ok := s.ancestors[height]; ok {
+		return id, nil
+	}
+
+	for uint64(s.ancestor.GetHeight()) > height {
+		parent, err := s.ctx.Core.QueryBlock(s.ancestor.GetPreHash())
+		if err != nil || parent.GetHeight() != s.ancestor.GetHeight()-1 {
+			return zero, nil
+		}
+		s.ancestor = parent
+		s.ancestors[uint64(parent.GetHeight())] = parent.GetBlockid()
+	}
+	return s.ancestors[height], nil
 }
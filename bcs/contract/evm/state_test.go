@@ -1,11 +1,18 @@
 package evm
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/hyperledger/burrow/crypto"
 
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 )
 
 func TestNewStateManager(t *testing.T) {
@@ -19,3 +26,79 @@ func TestNewStateManager(t *testing.T) {
 
 	st.RemoveAccount(crypto.Address{})
 }
+
+// forkChainCore 按区块id查询区块，用于构造分叉
+type forkChainCore struct {
+	contract.ChainCore
+	blocks map[string]*xldgpb.InternalBlock
+}
+
+func (c *forkChainCore) QueryBlock(blockid []byte) (ledger.BlockHandle, error) {
+	block, ok := c.blocks[string(blockid)]
+	if !ok {
+		return nil, errors.New("block not found")
+	}
+	return state.NewBlockAgent(block), nil
+}
+
+func TestBlockStateManager(t *testing.T) {
+	core := &forkChainCore{blocks: make(map[string]*xldgpb.InternalBlock)}
+	addBlock := func(id, preHash string, height int64) {
+		core.blocks[id] = &xldgpb.InternalBlock{Blockid: []byte(id), PreHash: []byte(preHash), Height: height}
+	}
+	// 主干 a_1..a_9，分叉 a_7 <- f_8 <- f_9 <- 执行区块(高度10)
+	for h := int64(1); h <= 9; h++ {
+		addBlock(fmt.Sprintf("a_%d", h), fmt.Sprintf("a_%d", h-1), h)
+	}
+	addBlock("f_8", "a_7", 8)
+	addBlock("f_9", "f_8", 9)
+
+	block := state.NewBlockAgent(&xldgpb.InternalBlock{
+		Blockid:   []byte("f_10"),
+		PreHash:   []byte("f_9"),
+		Height:    10,
+		Timestamp: 1600000000 * 1e9,
+	})
+	st := newBlockStateManager(&bridge.Context{Core: core, Block: block})
+
+	if height := st.LastBlockHeight(); height != 10 {
+		t.Errorf("unexpected block height %d", height)
+	}
+	if ts := st.LastBlockTime().Unix(); ts != 1600000000 {
+		t.Errorf("unexpected block time %d", ts)
+	}
+
+	zero := make([]byte, 32)
+	cases := map[uint64][]byte{
+		9:  []byte("f_9"),
+		8:  []byte("f_8"),
+		7:  []byte("a_7"),
+		1:  []byte("a_1"),
+		0:  zero, // 祖先区块不存在
+		10: zero, // 执行所在的区块
+		11: zero,
+	}
+	for height, want := range cases {
+		got, err := st.BlockHash(height)
+		if err != nil {
+			t.Errorf("BlockHash(%d) error: %v", height, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("BlockHash(%d) = %s, want %s", height, got, want)
+		}
+	}
+
+	// 超出256个祖先区块
+	far := state.NewBlockAgent(&xldgpb.InternalBlock{Blockid: []byte("x"), PreHash: []byte("a_9"), Height: 300})
+	st = newBlockStateManager(&bridge.Context{Core: core, Block: far})
+	if got, _ := st.BlockHash(9); !bytes.Equal(got, zero) {
+		t.Errorf("BlockHash out of range should be zero, got %s", got)
+	}
+
+	// 没有执行区块时不提供区块信息
+	st = newBlockStateManager(&bridge.Context{Core: core})
+	if got, _ := st.BlockHash(1); st.LastBlockHeight() != 0 || !bytes.Equal(got, zero) {
+		t.Error("block info should be empty without block")
+	}
+}
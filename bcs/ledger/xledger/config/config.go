@@ -17,6 +17,8 @@ type XLedgerConf struct {
 	BlockCacheSize int        `yaml:"blockCacheSize,omitempty"`
 	TxCacheSize    int        `yaml:"txCacheSize,omitempty"`
 	MempoolTxLimit int        `yaml:"mempoolTxLimit,omitempty"`
+	// 账本裁剪配置
+	Prune PruneConfig `yaml:"prune,omitempty"`
 }
 
 type UtxoConfig struct {
@@ -24,6 +26,20 @@ type UtxoConfig struct {
 	TmpLockSeconds int `yaml:"tmplockSeconds,omitempty"`
 }
 
+// PruneConfig 账本裁剪配置，只裁剪旧区块的交易详情，区块头和merkle根永久保留
+// KeepBlocks和KeepDays都为0时不裁剪，同时配置时两者都满足才裁剪
+type PruneConfig struct {
+	// 保留最近多少个区块的交易详情
+	KeepBlocks int64 `yaml:"keepBlocks,omitempty"`
+	// 保留最近多少天的交易详情
+	KeepDays int `yaml:"keepDays,omitempty"`
+}
+
+// Enabled 是否开启了账本裁剪
+func (c PruneConfig) Enabled() bool {
+	return c.KeepBlocks > 0 || c.KeepDays > 0
+}
+
 func LoadLedgerConf(cfgFile string) (*XLedgerConf, error) {
 	cfg := GetDefLedgerConf()
 	err := cfg.loadConf(cfgFile)
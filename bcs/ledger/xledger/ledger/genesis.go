@@ -18,6 +18,7 @@ const awardCacheSize = 1000
 type RootConfig struct {
 	Version   string `json:"version"`
 	Crypto    string `json:"crypto"`
+	Hash      string `json:"hash"` // 区块id、交易id和merkle树的哈希算法：sha256(默认)、sm3、sha3
 	Kvengine  string `json:"kvengine"`
 	Consensus struct {
 		Type  string `json:"type"`
@@ -59,6 +60,8 @@ type RootConfig struct {
 	XTokenAdmins map[string]bool `json:"xtoken_admins"`
 	// XToken fee
 	XTokenFee map[string]int64 `json:"xtoken_fee"`
+	// EthChainId 链上接受的以太坊交易的EIP-155链id，决定交易是否有效，为0时不接受以太坊交易
+	EthChainId int64 `json:"eth_chain_id"`
 }
 
 // GasPrice define gas rate for utxo
@@ -107,6 +110,11 @@ func (rc *RootConfig) GetCryptoType() string {
 	return "default"
 }
 
+// GetHashType get hash algorithm of block id, txid and merkle tree, empty means sha256
+func (rc *RootConfig) GetHashType() string {
+	return rc.Hash
+}
+
 // GetIrreversibleSlideWindow get irreversible slide window
 func (rc *RootConfig) GetIrreversibleSlideWindow() int64 {
 	irreversibleSlideWindow, _ := strconv.Atoi(rc.IrreversibleSlideWindow)
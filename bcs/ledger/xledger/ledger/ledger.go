@@ -18,6 +18,7 @@ import (
 	"github.com/xuperchain/xupercore/lib/cache"
 	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
 	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/lib/storage/kvdb"
@@ -37,6 +38,10 @@ var (
 	ErrRootBlockAlreadyExist = errors.New("this ledger already has genesis block")
 	// ErrTxNotConfirmed return tx not confirmed error
 	ErrTxNotConfirmed = errors.New("transaction not confirmed")
+	// ErrTxPruned is returned when the body of a confirmed transaction has been pruned
+	ErrTxPruned = errors.New("transaction has been pruned")
+	// ErrBlockPruned is returned when transactions of a block have been pruned
+	ErrBlockPruned = errors.New("block transactions have been pruned")
 	// NumCPU returns the number of CPU cores for the current system
 	NumCPU = runtime.NumCPU()
 )
@@ -87,7 +92,12 @@ type Ledger struct {
 	blkHeaderCache *cache.LRUCache // block header cache, 加速fetchBlock
 	txCache        *cache.LRUCache // tx cache
 	cryptoClient   cryptoBase.CryptoClient
-	confirmBatch   kvdb.Batch //新增区块
+	hasher         *hash.Hasher   // 区块id、交易id和merkle树使用的哈希算法
+	confirmBatch   kvdb.Batch     //新增区块
+	prunedTable    kvdb.Database  // 已裁剪交易表，txid到blockid的映射
+	prunedHeight   int64          // 已裁剪到的主干高度
+	pruneExitCh    chan struct{}  // 通知后台裁剪任务退出
+	pruneExitWG    sync.WaitGroup // 等待后台裁剪任务退出
 }
 
 // ConfirmStatus block status
@@ -138,6 +148,7 @@ func newLedger(lctx *LedgerCtx, createIfMissing bool, genesisCfg []byte) (*Ledge
 	ledger.blocksTable = kvdb.NewTable(baseDB, pb.BlocksTablePrefix)
 	ledger.pendingTable = kvdb.NewTable(baseDB, pb.PendingBlocksTablePrefix)
 	ledger.heightTable = kvdb.NewTable(baseDB, pb.BlockHeightPrefix)
+	ledger.prunedTable = kvdb.NewTable(baseDB, pb.PrunedTxTablePrefix)
 	ledger.xlog = lctx.XLog
 	ledger.meta = &pb.LedgerMeta{}
 
@@ -198,11 +209,27 @@ func newLedger(lctx *LedgerCtx, createIfMissing bool, genesisCfg []byte) (*Ledge
 	}
 	ledger.cryptoClient = crypto
 
+	// 根据创世块配置实例化区块id、交易id和merkle树的哈希算法
+	hashType := ledger.GenesisBlock.GetConfig().GetHashType()
+	hasher, err := hash.GetHasher(hashType)
+	if err != nil {
+		lctx.XLog.Warn("failed to create hasher", "hashType", hashType, "err", err)
+		return nil, err
+	}
+	ledger.hasher = hasher
+
+	// 加载裁剪进度
+	if err := ledger.loadPrunedHeight(); err != nil {
+		lctx.XLog.Warn("failed to load pruned height", "err", err)
+		return nil, err
+	}
+
 	return ledger, nil
 }
 
 // Close close an instance of ledger
 func (l *Ledger) Close() {
+	l.StopPrune()
 	l.baseDB.Close()
 }
 
@@ -256,12 +283,12 @@ func (l *Ledger) FormatRootBlock(txList []*pb.Transaction) (*pb.InternalBlock, e
 	block := &pb.InternalBlock{Version: RootBlockVersion}
 	block.Transactions = txList
 	block.TxCount = int32(len(txList))
-	block.MerkleTree = MakeMerkleTree(txList)
+	block.MerkleTree = MakeMerkleTreeWithHasher(txList, l.hasher)
 	if len(block.MerkleTree) > 0 {
 		block.MerkleRoot = block.MerkleTree[len(block.MerkleTree)-1]
 	}
 	var err error
-	block.Blockid, err = MakeBlockID(block)
+	block.Blockid, err = MakeBlockIDWithHasher(block, l.hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -326,7 +353,7 @@ func (l *Ledger) formatBlock(txList []*pb.Transaction,
 		}
 		block.MerkleTree = fakeTree
 	} else {
-		block.MerkleTree = MakeMerkleTree(txList)
+		block.MerkleTree = MakeMerkleTreeWithHasher(txList, l.hasher)
 	}
 	if failedTxs != nil {
 		block.FailedTxs = failedTxs
@@ -337,7 +364,7 @@ func (l *Ledger) formatBlock(txList []*pb.Transaction,
 		block.MerkleRoot = block.MerkleTree[len(block.MerkleTree)-1]
 	}
 	var err error
-	block.Blockid, err = MakeBlockID(block)
+	block.Blockid, err = MakeBlockIDWithHasher(block, l.hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -539,7 +566,7 @@ func (l *Ledger) UpdateBlockChainData(txid string, ptxid string, publickey strin
 	return nil
 }
 
-func (l *Ledger) parallelCheckTx(txs []*pb.Transaction, block *pb.InternalBlock) (map[string]bool, [][]byte) {
+func (l *Ledger) parallelCheckTx(txs []*pb.Transaction, block *pb.InternalBlock) (map[string]bool, map[string]bool, [][]byte) {
 	txData := make([][]byte, len(txs))
 
 	parallelLevel := NumCPU
@@ -550,6 +577,8 @@ func (l *Ledger) parallelCheckTx(txs []*pb.Transaction, block *pb.InternalBlock)
 	mu := &sync.Mutex{}
 	wg := &sync.WaitGroup{}
 	txExist := map[string]bool{}
+	txPruned := map[string]bool{}
+	checkPruned := l.GetPrunedHeight() > 0
 	total := len(txs)
 	wg.Add(total)
 	for i := 0; i <= parallelLevel; i++ {
@@ -570,8 +599,13 @@ func (l *Ledger) parallelCheckTx(txs []*pb.Transaction, block *pb.InternalBlock)
 				}
 				if !DisableTxDedup || !block.InTrunk {
 					hasTx, _ := l.confirmedTable.Has(tx.Txid)
+					isPruned := false
+					if !hasTx && checkPruned {
+						isPruned, _ = l.prunedTable.Has(tx.Txid)
+					}
 					mu.Lock()
 					txExist[string(tx.Txid)] = hasTx
+					txPruned[string(tx.Txid)] = isPruned
 					mu.Unlock()
 				}
 				wg.Done()
@@ -583,7 +617,7 @@ func (l *Ledger) parallelCheckTx(txs []*pb.Transaction, block *pb.InternalBlock)
 	}
 	wg.Wait()
 	close(ch)
-	return txExist, txData
+	return txExist, txPruned, txData
 }
 
 func traceMiner() func(string) {
@@ -709,7 +743,7 @@ func (l *Ledger) ConfirmBlock(block *pb.InternalBlock, isRoot bool) ConfirmStatu
 		l.xlog.Warn("update branch info fail", "updateBranchErr", updateBranchErr)
 		return confirmStatus
 	}
-	txExist, txData := l.parallelCheckTx(realTransactions, block)
+	txExist, txPruned, txData := l.parallelCheckTx(realTransactions, block)
 	cbNum := 0
 	oldBlockCache := map[string]*pb.InternalBlock{}
 	trace("checktx")
@@ -730,6 +764,13 @@ func (l *Ledger) ConfirmBlock(block *pb.InternalBlock, isRoot bool) ConfirmStatu
 			l.xlog.Warn("marshal trasaction failed when confirm block")
 			return confirmStatus
 		}
+		if txPruned[string(tx.Txid)] {
+			// 只有足够深的主干区块才会被裁剪，交易不可能再被打包到其他区块
+			confirmStatus.Succ = false
+			confirmStatus.Error = ErrTxDuplicated
+			l.xlog.Warn("transaction duplicated in previous pruned block", "txid", utils.F(tx.Txid))
+			return confirmStatus
+		}
 		hasTx := txExist[string(tx.Txid)]
 		if !hasTx {
 			batchWrite.Put(append([]byte(pb.ConfirmedTablePrefix), tx.Txid...), pbTxBuf)
@@ -844,6 +885,9 @@ func (l *Ledger) queryBlock(blockid []byte, needBody bool) (*pb.InternalBlock, e
 		for _, txid := range block.MerkleTree[:block.TxCount] {
 			pbTxBuf, kvErr := l.confirmedTable.Get(txid)
 			if kvErr != nil {
+				if l.isTxPruned(txid) {
+					return block, ErrBlockPruned
+				}
 				l.xlog.Warn("tx not found", "kvErr", kvErr, "txid", utils.F(txid))
 				return block, kvErr
 			}
@@ -888,7 +932,11 @@ func (l *Ledger) HasTransaction(txid []byte) (bool, error) {
 		return true, nil
 	}
 	table := l.confirmedTable
-	return table.Has(txid)
+	exist, err := table.Has(txid)
+	if err != nil || exist {
+		return exist, err
+	}
+	return l.isTxPruned(txid), nil
 }
 
 // QueryTransaction query a transaction in the ledger and return it if exist
@@ -903,6 +951,9 @@ func (l *Ledger) QueryTransaction(txid []byte) (*pb.Transaction, error) {
 	pbTxBuf, kvErr := table.Get(txid)
 	if kvErr != nil {
 		if def.NormalizedKVError(kvErr) == def.ErrKVNotFound {
+			if l.isTxPruned(txid) {
+				return nil, ErrTxPruned
+			}
 			return nil, ErrTxNotFound
 		}
 		return nil, kvErr
@@ -923,7 +974,8 @@ func (l *Ledger) IsTxInTrunk(txid []byte) bool {
 	table := l.confirmedTable
 	pbTxBuf, kvErr := table.Get(txid)
 	if kvErr != nil {
-		return false
+		// 已裁剪交易只会出现在主干区块上
+		return l.isTxPruned(txid)
 	}
 	realTx := &pb.Transaction{}
 	pbErr := proto.Unmarshal(pbTxBuf, realTx)
@@ -1034,7 +1086,13 @@ func (l *Ledger) Dump() ([][]string, error) {
 	return blocks, nil
 }
 
+// GetHasher 获取区块id、交易id和merkle树使用的哈希算法
+func (l *Ledger) GetHasher() *hash.Hasher {
+	return l.hasher
+}
+
 // GetGenesisBlock returns genesis block if it exists
+
 func (l *Ledger) GetGenesisBlock() *GenesisBlock {
 	if l.GenesisBlock != nil {
 		return l.GenesisBlock
@@ -1242,7 +1300,7 @@ func (l *Ledger) Truncate(utxovmLastID []byte) error {
 
 // VerifyBlock verify block
 func (l *Ledger) VerifyBlock(block *pb.InternalBlock, logid string) (bool, error) {
-	blkid, err := MakeBlockID(block)
+	blkid, err := MakeBlockIDWithHasher(block, l.hasher)
 	if err != nil {
 		l.xlog.Warn("VerifyBlock MakeBlockID error", "logid", logid, "error", err)
 		return false, nil
@@ -1253,7 +1311,7 @@ func (l *Ledger) VerifyBlock(block *pb.InternalBlock, logid string) (bool, error
 		return false, nil
 	}
 
-	errv := VerifyMerkle(block)
+	errv := VerifyMerkleWithHasher(block, l.hasher)
 	if errv != nil {
 		l.xlog.Warn("VerifyMerkle error", "logid", logid, "error", errv)
 		return false, nil
@@ -1284,6 +1342,13 @@ func (l *Ledger) QueryBlockByTxid(txid []byte) (*pb.InternalBlock, error) {
 		return nil, ErrTxNotConfirmed
 	}
 	tx, err := l.QueryTransaction(txid)
+	if err == ErrTxPruned {
+		blockid, err := l.prunedTable.Get(txid)
+		if err != nil {
+			return nil, err
+		}
+		return l.queryBlock(blockid, false)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,6 @@ package ledger
 
 import (
 	"bytes"
-	"crypto/sha256"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -22,15 +21,8 @@ func getLeafSize(txCount int) int {
 	return 1 << exponent // 2^exponent
 }
 
-func merkleDoubleSha256(left, right, result []byte) []byte {
-	sum1 := sha256.New()
-	sum1.Write(left)
-	sum1.Write(right)
-	result = sum1.Sum(result[:0])
-
-	sum2 := sha256.New()
-	sum2.Write(result)
-	result = sum2.Sum(result[:0])
+func merkleDoubleHash(hasher *hash.Hasher, left, right, result []byte) []byte {
+	copy(result, hasher.DoubleHash(left, right))
 	return result
 }
 
@@ -47,8 +39,13 @@ func arenaAllocator(elemSize, elemNumer int) func() []byte {
 	}
 }
 
-// MakeMerkleTree generate merkele-tree
+// MakeMerkleTree generate merkele-tree using default hash algorithm
 func MakeMerkleTree(txList []*pb.Transaction) [][]byte {
+	return MakeMerkleTreeWithHasher(txList, hash.DefaultHasher)
+}
+
+// MakeMerkleTreeWithHasher generate merkele-tree using specified hash algorithm
+func MakeMerkleTreeWithHasher(txList []*pb.Transaction, hasher *hash.Hasher) [][]byte {
 	txCount := len(txList)
 	if txCount == 0 {
 		return nil
@@ -72,11 +69,11 @@ func MakeMerkleTree(txList []*pb.Transaction) [][]byte {
 		case tree[i+1] == nil: //没有右孩子
 			// concat := bytes.Join([][]byte{tree[i], tree[i]}, []byte{})
 			// tree[noneLeafOffset] = hash.DoubleSha256(concat)
-			tree[noneLeafOffset] = merkleDoubleSha256(tree[i], tree[i], alloc())
+			tree[noneLeafOffset] = merkleDoubleHash(hasher, tree[i], tree[i], alloc())
 		default: //左右都有
 			// concat := bytes.Join([][]byte{tree[i], tree[i+1]}, []byte{})
 			// tree[noneLeafOffset] = hash.DoubleSha256(concat)
-			tree[noneLeafOffset] = merkleDoubleSha256(tree[i], tree[i+1], alloc())
+			tree[noneLeafOffset] = merkleDoubleHash(hasher, tree[i], tree[i+1], alloc())
 		}
 		noneLeafOffset++
 	}
@@ -198,13 +195,29 @@ func encodeJustify(buf *bytes.Buffer, block *pb.InternalBlock) error {
 			}
 		}
 	}
+	// 聚合签名仅在BLS模式下存在，不影响原有区块的id
+	if block.Justify.AggregateSign != nil {
+		err = binary.Write(buf, binary.LittleEndian, block.Justify.AggregateSign.Bitmap)
+		if err != nil {
+			return err
+		}
+		err = binary.Write(buf, binary.LittleEndian, block.Justify.AggregateSign.Sign)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// VerifyMerkle
+// VerifyMerkle verify merkle root using default hash algorithm
 func VerifyMerkle(block *pb.InternalBlock) error {
+	return VerifyMerkleWithHasher(block, hash.DefaultHasher)
+}
+
+// VerifyMerkleWithHasher verify merkle root using specified hash algorithm
+func VerifyMerkleWithHasher(block *pb.InternalBlock, hasher *hash.Hasher) error {
 	blockid := block.Blockid
-	merkleTree := MakeMerkleTree(block.Transactions)
+	merkleTree := MakeMerkleTreeWithHasher(block.Transactions, hasher)
 	if len(merkleTree) > 0 {
 		merkleRoot := merkleTree[len(merkleTree)-1]
 		if !(bytes.Equal(merkleRoot, block.MerkleRoot)) {
@@ -216,8 +229,13 @@ func VerifyMerkle(block *pb.InternalBlock) error {
 	}
 }
 
-// MakeBlockID generate BlockID
+// MakeBlockID generate BlockID using default hash algorithm
 func MakeBlockID(block *pb.InternalBlock) ([]byte, error) {
+	return MakeBlockIDWithHasher(block, hash.DefaultHasher)
+}
+
+// MakeBlockIDWithHasher generate BlockID using specified hash algorithm
+func MakeBlockIDWithHasher(block *pb.InternalBlock, hasher *hash.Hasher) ([]byte, error) {
 	buf := new(bytes.Buffer)
 	err := binary.Write(buf, binary.LittleEndian, block.Version)
 	if err != nil {
@@ -277,5 +295,5 @@ func MakeBlockID(block *pb.InternalBlock) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("encodeJustify failed, err=%v", err)
 	}
-	return hash.DoubleSha256(buf.Bytes()), nil
+	return hasher.DoubleHash(buf.Bytes()), nil
 }
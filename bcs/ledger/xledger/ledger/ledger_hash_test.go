@@ -13,7 +13,7 @@ func TestMerkleHash(t *testing.T) {
 	left := []byte("hello")
 	right := []byte("world")
 	result := make([]byte, 32)
-	result = merkleDoubleSha256(left, right, result)
+	result = merkleDoubleHash(hash.DefaultHasher, left, right, result)
 
 	result1 := hash.DoubleSha256(append(left, right...))
 	if !bytes.Equal(result, result1) {
@@ -21,6 +21,39 @@ func TestMerkleHash(t *testing.T) {
 	}
 }
 
+func TestBlockIDWithAggregateSign(t *testing.T) {
+	block := &pb.InternalBlock{
+		Version: 1,
+		PreHash: []byte("prehash"),
+		Justify: &pb.QuorumCert{
+			ProposalId: []byte("proposal"),
+			ViewNumber: 2,
+			SignInfos:  &pb.QCSignInfos{},
+		},
+	}
+	legacyID, err := MakeBlockID(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 老格式区块的id计算方式不变，聚合签名需计入区块id
+	block.Justify.AggregateSign = &pb.QCAggregateSign{
+		Bitmap: []byte{3},
+		Sign:   []byte("sign"),
+	}
+	aggID, err := MakeBlockID(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(legacyID, aggID) {
+		t.Fatal("aggregate sign should be included in block id")
+	}
+	block.Justify.AggregateSign.Sign = []byte("forged")
+	forgedID, _ := MakeBlockID(block)
+	if bytes.Equal(aggID, forgedID) {
+		t.Fatal("block id should change with aggregate sign")
+	}
+}
+
 func BenchmarkNormalMerkle(b *testing.B) {
 	var txs []*pb.Transaction
 	for i := 0; i < 10000; i++ {
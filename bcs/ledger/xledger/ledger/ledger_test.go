@@ -1,12 +1,14 @@
 package ledger
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	//"io/ioutil"
 	"math/big"
@@ -18,6 +20,9 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/kernel/mock"
+	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/client/schnorr"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 	"github.com/xuperchain/xupercore/lib/logs"
 	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
 	"github.com/xuperchain/xupercore/protos"
@@ -27,6 +32,11 @@ const AliceAddress = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
 const BobAddress = "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"
 
 func openLedger() (*Ledger, error) {
+	return openLedgerWithCrypto("", "")
+}
+
+// openLedgerWithCrypto 使用指定密码学类型和哈希算法的创世块创建账本，为空时使用默认类型
+func openLedgerWithCrypto(cryptoType, hashType string) (*Ledger, error) {
 	workspace, dirErr := ioutil.TempDir("/tmp", "")
 	if dirErr != nil {
 		return nil, dirErr
@@ -45,9 +55,11 @@ func openLedger() (*Ledger, error) {
 	}
 	lctx.EnvCfg.ChainDir = workspace
 
-	genesisConf := []byte(`
+	genesisConf := []byte(fmt.Sprintf(`
 		{
     "version": "1",
+    "crypto": "%s",
+    "hash": "%s",
     "predistribution": [
         {
             "address": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
@@ -76,7 +88,7 @@ func openLedger() (*Ledger, error) {
         }
     }
 }
-    `)
+    `, cryptoType, hashType))
 	ledgerIns, err := CreateLedger(lctx, genesisConf)
 	if err != nil {
 		return nil, err
@@ -494,3 +506,103 @@ func TestTruncate(t *testing.T) {
 
 	ledger.Close()
 }
+
+func TestSchnorrBlockSign(t *testing.T) {
+	ledger, err := openLedgerWithCrypto(cryptoClient.CryptoTypeSchnorr, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	ecdsaPk, err := ledger.cryptoClient.GenerateKeyBySeed([]byte("schnorr-miner-seed-0123456789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	miner, err := ledger.cryptoClient.GetAddressFromPublicKey(&ecdsaPk.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coinbase := &pb.Transaction{Coinbase: true, Desc: []byte("schnorr")}
+	coinbase.Txid, _ = txhash.MakeTransactionID(coinbase)
+	block, err := ledger.FormatBlock([]*pb.Transaction{coinbase}, []byte(miner), ecdsaPk, 223456789, 0, 0, []byte("pre"), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(block.Pubkey), schnorr.CurveName) {
+		t.Fatalf("public key of block should be schnorr key, got %s", block.Pubkey)
+	}
+	if ok, _ := ledger.VerifyBlock(block, "TestSchnorrBlockSign"); !ok {
+		t.Fatal("verify schnorr block failed")
+	}
+
+	// ECDSA签名的区块不能通过Schnorr链的校验
+	defaultClient, err := cryptoClient.CreateCryptoClient(cryptoClient.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaSign, err := defaultClient.SignECDSA(ecdsaPk, block.Blockid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Sign = ecdsaSign
+	if ok, _ := ledger.VerifyBlock(block, "TestSchnorrBlockSign"); ok {
+		t.Fatal("block signed by ecdsa should not pass verification")
+	}
+}
+
+func TestSM3BlockHash(t *testing.T) {
+	ledger, err := openLedgerWithCrypto("gm", hash.HashTypeSM3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	if ledger.GetHasher().Name() != hash.HashTypeSM3 {
+		t.Fatalf("expect hasher %s, got %s", hash.HashTypeSM3, ledger.GetHasher().Name())
+	}
+
+	ecdsaPk, err := ledger.cryptoClient.GenerateKeyBySeed([]byte("sm3-miner-seed-0123456789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	miner, err := ledger.cryptoClient.GetAddressFromPublicKey(&ecdsaPk.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coinbase := &pb.Transaction{Coinbase: true, Desc: []byte("sm3")}
+	coinbase.Txid, _ = txhash.MakeTransactionIDWithHasher(coinbase, ledger.GetHasher())
+	emptyTx := &pb.Transaction{Version: 1, Desc: []byte("sm3 empty tx")}
+	emptyTx.Txid, _ = txhash.MakeTransactionIDWithHasher(emptyTx, ledger.GetHasher())
+	if sha256Txid, _ := txhash.MakeTransactionID(emptyTx); bytes.Equal(sha256Txid, emptyTx.Txid) {
+		t.Fatal("txid of sm3 chain should not be sha256 digest")
+	}
+	txs := []*pb.Transaction{coinbase, emptyTx}
+	block, err := ledger.FormatBlock(txs, []byte(miner), ecdsaPk, 223456789, 0, 0, []byte("pre"), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := ledger.VerifyBlock(block, "TestSM3BlockHash"); !ok {
+		t.Fatal("verify sm3 block failed")
+	}
+
+	// SM3链的区块id和merkle根不同于默认的SHA256结果
+	sha256ID, err := MakeBlockID(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sha256ID, block.Blockid) {
+		t.Fatal("block id of sm3 chain should not be sha256 digest")
+	}
+	if err := VerifyMerkle(block); err == nil {
+		t.Fatal("merkle root of sm3 chain should not pass sha256 verification")
+	}
+	block.Blockid = sha256ID
+	if ok, _ := ledger.VerifyBlock(block, "TestSM3BlockHash"); ok {
+		t.Fatal("block with sha256 block id should not pass sm3 chain verification")
+	}
+}
+
+func TestUnknownHashType(t *testing.T) {
+	if _, err := openLedgerWithCrypto("", "md5"); err == nil {
+		t.Fatal("create ledger with unsupported hash type should fail")
+	}
+}
@@ -0,0 +1,265 @@
+package ledger
+
+import (
+	"bytes"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	// PrunedHeightKey 已裁剪高度在meta表中的key
+	PrunedHeightKey = "PrunedHeight"
+	// 后台裁剪任务检查间隔
+	pruneCheckInterval = 10 * time.Second
+	// 每轮最多裁剪的区块数，避免长时间占用磁盘IO
+	pruneBlocksPerRound = 100
+)
+
+var (
+	// MinPruneDepth 被裁剪区块距离主干末端的最小深度，保证被裁剪的区块不会再回滚
+	MinPruneDepth int64 = 100
+)
+
+// TxRetainer 判断已确认交易是否需要保留交易详情，例如交易写入的数据仍被状态机引用
+type TxRetainer func(tx *pb.Transaction) bool
+
+// 待裁剪的区块
+type pruneBlock struct {
+	blockid []byte
+	height  int64
+	txs     []*pb.Transaction
+}
+
+func (l *Ledger) loadPrunedHeight() error {
+	buf, err := l.metaTable.Get([]byte(PrunedHeightKey))
+	if err != nil {
+		if def.NormalizedKVError(err) == def.ErrKVNotFound {
+			return nil
+		}
+		return err
+	}
+	height, err := strconv.ParseInt(string(buf), 10, 64)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt64(&l.prunedHeight, height)
+	return nil
+}
+
+// GetPrunedHeight 返回已裁剪交易详情的最大主干高度，0表示没有裁剪过，创世块不会被裁剪
+func (l *Ledger) GetPrunedHeight() int64 {
+	return atomic.LoadInt64(&l.prunedHeight)
+}
+
+func (l *Ledger) isTxPruned(txid []byte) bool {
+	if l.GetPrunedHeight() == 0 {
+		return false
+	}
+	exist, _ := l.prunedTable.Has(txid)
+	return exist
+}
+
+// StartPrune 启动后台裁剪任务，按配置增量删除旧区块的交易详情，未开启裁剪时直接返回
+// retain返回true的交易不会被裁剪，为nil时裁剪所有符合条件的交易
+func (l *Ledger) StartPrune(retain TxRetainer) {
+	conf := l.ctx.LedgerCfg.Prune
+	if !conf.Enabled() || l.pruneExitCh != nil {
+		return
+	}
+	l.xlog.Info("start ledger prune", "keepBlocks", conf.KeepBlocks, "keepDays", conf.KeepDays,
+		"prunedHeight", l.GetPrunedHeight())
+
+	l.pruneExitCh = make(chan struct{})
+	l.pruneExitWG.Add(1)
+	go func(exitCh chan struct{}) {
+		defer l.pruneExitWG.Done()
+		ticker := time.NewTicker(pruneCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-exitCh:
+				return
+			case <-ticker.C:
+				if _, err := l.Prune(retain); err != nil {
+					l.xlog.Warn("prune ledger failed", "err", err)
+				}
+			}
+		}
+	}(l.pruneExitCh)
+}
+
+// StopPrune 停止后台裁剪任务，等待正在进行的一轮裁剪结束
+func (l *Ledger) StopPrune() {
+	if l.pruneExitCh == nil {
+		return
+	}
+	close(l.pruneExitCh)
+	l.pruneExitWG.Wait()
+	l.pruneExitCh = nil
+}
+
+// Prune 执行一轮裁剪，返回本轮裁剪的交易数
+func (l *Ledger) Prune(retain TxRetainer) (int, error) {
+	from := l.GetPrunedHeight() + 1
+	target := l.pruneTargetHeight()
+	if target < from {
+		return 0, nil
+	}
+	if target-from >= pruneBlocksPerRound {
+		target = from + pruneBlocksPerRound - 1
+	}
+
+	var deadline int64
+	if keepDays := l.ctx.LedgerCfg.Prune.KeepDays; keepDays > 0 {
+		deadline = time.Now().Add(-time.Duration(keepDays) * 24 * time.Hour).UnixNano()
+	}
+
+	// 先检查状态机引用，再收集可回滚区块的引用，
+	// 这样检查期间新确认的区块对旧版本数据的引用也能被覆盖
+	blocks := make([]*pruneBlock, 0, target-from+1)
+	for height := from; height <= target; height++ {
+		header, err := l.QueryBlockHeaderByHeight(height)
+		if err != nil {
+			return 0, err
+		}
+		if deadline > 0 && header.Timestamp > deadline {
+			break
+		}
+		blk, err := l.collectPruneTxs(header, retain)
+		if err != nil {
+			return 0, err
+		}
+		blocks = append(blocks, blk)
+	}
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+	refs, err := l.reversibleTxRefs()
+	if err != nil {
+		return 0, err
+	}
+
+	batch := l.baseDB.NewBatch()
+	count := 0
+	for _, blk := range blocks {
+		for _, tx := range blk.txs {
+			if refs[string(tx.Txid)] {
+				continue
+			}
+			batch.Delete(append([]byte(pb.ConfirmedTablePrefix), tx.Txid...))
+			batch.Put(append([]byte(pb.PrunedTxTablePrefix), tx.Txid...), blk.blockid)
+			count++
+		}
+	}
+	prunedHeight := blocks[len(blocks)-1].height
+	batch.Put(append([]byte(pb.MetaTablePrefix), PrunedHeightKey...), []byte(strconv.FormatInt(prunedHeight, 10)))
+
+	// 与ConfirmBlock互斥写入，只影响已经不可逆的区块
+	l.mutex.Lock()
+	err = batch.Write()
+	if err == nil {
+		atomic.StoreInt64(&l.prunedHeight, prunedHeight)
+		for _, blk := range blocks {
+			l.blockCache.Del(string(blk.blockid))
+			for _, tx := range blk.txs {
+				if !refs[string(tx.Txid)] {
+					l.txCache.Del(string(tx.Txid))
+				}
+			}
+		}
+	}
+	l.mutex.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	l.xlog.Info("prune ledger succ", "fromHeight", from, "toHeight", prunedHeight, "prunedTxs", count)
+	return count, nil
+}
+
+// pruneTargetHeight 返回本次最多可以裁剪到的主干高度
+func (l *Ledger) pruneTargetHeight() int64 {
+	l.mutex.RLock()
+	tipHeight := l.meta.TrunkHeight
+	l.mutex.RUnlock()
+
+	keep := l.reversibleDepth()
+	if keepBlocks := l.ctx.LedgerCfg.Prune.KeepBlocks; keepBlocks > keep {
+		keep = keepBlocks
+	}
+	return tipHeight - keep
+}
+
+// reversibleDepth 可能被回滚的区块深度
+func (l *Ledger) reversibleDepth() int64 {
+	depth := l.GetIrreversibleSlideWindow()
+	if depth < MinPruneDepth {
+		depth = MinPruneDepth
+	}
+	return depth
+}
+
+// collectPruneTxs 收集区块中可以裁剪的交易
+func (l *Ledger) collectPruneTxs(header *pb.InternalBlock, retain TxRetainer) (*pruneBlock, error) {
+	blk := &pruneBlock{
+		blockid: header.Blockid,
+		height:  header.Height,
+	}
+	for _, txid := range header.MerkleTree[:header.TxCount] {
+		pbTxBuf, err := l.confirmedTable.Get(txid)
+		if err != nil {
+			if def.NormalizedKVError(err) == def.ErrKVNotFound {
+				continue
+			}
+			return nil, err
+		}
+		tx := &pb.Transaction{}
+		if err := proto.Unmarshal(pbTxBuf, tx); err != nil {
+			return nil, err
+		}
+		// 交易被其他主干区块打包时，以交易记录的区块为准
+		if !bytes.Equal(tx.Blockid, header.Blockid) {
+			l.xlog.Debug("skip prune tx of other block", "txid", utils.F(txid), "blockid", utils.F(tx.Blockid))
+			continue
+		}
+		// 被标记修改的交易需要保留，后续交易验证依赖其修改生效高度
+		if tx.GetModifyBlock() != nil && tx.ModifyBlock.Marked {
+			continue
+		}
+		if retain != nil && retain(tx) {
+			continue
+		}
+		blk.txs = append(blk.txs, tx)
+	}
+	return blk, nil
+}
+
+// reversibleTxRefs 收集可能回滚的区块中交易读集引用的交易，回滚时需要读取这些交易恢复旧版本数据
+func (l *Ledger) reversibleTxRefs() (map[string]bool, error) {
+	l.mutex.RLock()
+	tipHeight := l.meta.TrunkHeight
+	l.mutex.RUnlock()
+
+	refs := make(map[string]bool)
+	for height := tipHeight - l.reversibleDepth(); height <= tipHeight; height++ {
+		if height <= 0 {
+			continue
+		}
+		block, err := l.QueryBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.Transactions {
+			for _, txIn := range tx.TxInputsExt {
+				refs[string(txIn.RefTxid)] = true
+			}
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,125 @@
+package ledger
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// newPruneTestTx 构造用Desc区分的测试交易
+func newPruneTestTx(name string, txs map[string]*pb.Transaction, inputsExt ...*protos.TxInputExt) *pb.Transaction {
+	tx := &pb.Transaction{Desc: []byte(name), TxInputsExt: inputsExt}
+	tx.Txid, _ = txhash.MakeTransactionID(tx)
+	txs[name] = tx
+	return tx
+}
+
+func TestPrune(t *testing.T) {
+	ledger, err := openLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	defer func(depth int64) { MinPruneDepth = depth }(MinPruneDepth)
+	MinPruneDepth = 1
+	ledger.ctx.LedgerCfg.Prune.KeepBlocks = 1
+
+	ecdsaPk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := make(map[string]*pb.Transaction)
+	coinbase := &pb.Transaction{Coinbase: true, Desc: []byte(`{"maxblocksize" : "128"}`)}
+	coinbase.Txid, _ = txhash.MakeTransactionID(coinbase)
+	txs["coinbase"] = coinbase
+	root, err := ledger.FormatRootBlock([]*pb.Transaction{coinbase})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(root, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+
+	// 第4个区块的交易读集引用了p2，p2在可回滚区块中被引用，需要保留
+	// p3m是被标记修改的交易，也需要保留
+	marked := newPruneTestTx("p3m", txs)
+	marked.ModifyBlock = &pb.ModifyBlock{Marked: true, EffectiveHeight: 3}
+	blockTxs := [][]*pb.Transaction{
+		{newPruneTestTx("p1a", txs), newPruneTestTx("p1b", txs)},
+		{newPruneTestTx("p2", txs)},
+		{newPruneTestTx("p3", txs), marked},
+		{newPruneTestTx("p4", txs, &protos.TxInputExt{Bucket: "b", Key: []byte("k"), RefTxid: txs["p2"].Txid})},
+	}
+	preHash := root.Blockid
+	for i, blkTxs := range blockTxs {
+		block, err := ledger.FormatBlock(blkTxs, []byte("xchain-Miner"), ecdsaPk, int64(223456789+i), 0, 0, preHash, big.NewInt(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := ledger.ConfirmBlock(block, false); !status.Succ {
+			t.Fatalf("confirm block %d fail", i+1)
+		}
+		preHash = block.Blockid
+	}
+
+	retain := func(tx *pb.Transaction) bool {
+		return string(tx.Desc) == "p1b"
+	}
+	count, err := ledger.Prune(retain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || ledger.GetPrunedHeight() != 3 {
+		t.Fatalf("unexpected prune result, count:%d, prunedHeight:%d", count, ledger.GetPrunedHeight())
+	}
+	if count, _ := ledger.Prune(retain); count != 0 {
+		t.Fatalf("nothing should be pruned, got %d", count)
+	}
+
+	for _, txid := range []string{"p1a", "p3"} {
+		if _, err := ledger.QueryTransaction(txs[txid].Txid); err != ErrTxPruned {
+			t.Fatalf("query pruned tx %s expect ErrTxPruned, got %v", txid, err)
+		}
+		if exist, _ := ledger.HasTransaction(txs[txid].Txid); !exist {
+			t.Fatalf("pruned tx %s should exist", txid)
+		}
+		if !ledger.IsTxInTrunk(txs[txid].Txid) {
+			t.Fatalf("pruned tx %s should be in trunk", txid)
+		}
+	}
+	for _, txid := range []string{"coinbase", "p1b", "p2", "p3m", "p4"} {
+		if _, err := ledger.QueryTransaction(txs[txid].Txid); err != nil {
+			t.Fatalf("query retained tx %s failed, err:%v", txid, err)
+		}
+	}
+
+	block, err := ledger.QueryBlockByTxid(txs["p1a"].Txid)
+	if err != nil || block.Height != 1 {
+		t.Fatalf("query block by pruned tx failed, err:%v", err)
+	}
+	if _, err := ledger.QueryBlockByHeight(1); err != ErrBlockPruned {
+		t.Fatalf("query pruned block expect ErrBlockPruned, got %v", err)
+	}
+	header, err := ledger.QueryBlockHeaderByHeight(1)
+	if err != nil || header.TxCount != 2 || len(header.MerkleTree) == 0 {
+		t.Fatalf("header of pruned block should be kept, err:%v", err)
+	}
+	if _, err := ledger.QueryBlockByHeight(2); err != nil {
+		t.Fatalf("query block with retained txs failed, err:%v", err)
+	}
+
+	// 已裁剪的交易不能被再次打包
+	dupBlock, err := ledger.FormatBlock([]*pb.Transaction{txs["p1a"]}, []byte("xchain-Miner"), ecdsaPk, 223456800, 0, 0, preHash, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(dupBlock, false); status.Succ || status.Error != ErrTxDuplicated {
+		t.Fatalf("confirm block with pruned tx expect ErrTxDuplicated, got %v", status.Error)
+	}
+}
@@ -0,0 +1,77 @@
+package ledger
+
+import (
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+var (
+	ErrSnapshotBlockTooLow = errors.New("snapshot block is not higher than trunk height")
+)
+
+// SaveSnapshotTxs 保存状态快照中引用的已确认交易，用于快速同步
+// 交易所在区块不在本地账本中，只写入已确认交易表
+func (l *Ledger) SaveSnapshotTxs(txs []*pb.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	batchWrite := l.baseDB.NewBatch()
+	for _, tx := range txs {
+		pbTxBuf, err := proto.Marshal(tx)
+		if err != nil {
+			l.xlog.Warn("marshal snapshot tx failed", "txid", utils.F(tx.Txid), "err", err)
+			return err
+		}
+		batchWrite.Put(append([]byte(pb.ConfirmedTablePrefix), tx.Txid...), pbTxBuf)
+	}
+	return batchWrite.Write()
+}
+
+// ConfirmSnapshotBlock 将状态快照对应的区块头设置为主干末端，用于快速同步
+// 快照区块之前的区块不在本地账本中，之后可以从该区块开始继续正常同步
+func (l *Ledger) ConfirmSnapshotBlock(block *pb.InternalBlock) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if block.Height <= l.meta.TrunkHeight {
+		l.xlog.Warn("snapshot block is too low", "height", block.Height, "trunkHeight", l.meta.TrunkHeight)
+		return ErrSnapshotBlockTooLow
+	}
+
+	header := proto.Clone(block).(*pb.InternalBlock)
+	header.Transactions = nil
+	header.InTrunk = true
+	header.NextHash = nil
+
+	batchWrite := l.baseDB.NewBatch()
+	if err := l.saveBlock(header, batchWrite); err != nil {
+		return err
+	}
+	oldTip := l.meta.TipBlockid
+	if err := l.updateBranchInfo(header.Blockid, oldTip, header.Height, batchWrite); err != nil {
+		l.xlog.Warn("update branch info failed when confirm snapshot block", "err", err)
+		return err
+	}
+	newMeta := proto.Clone(l.meta).(*pb.LedgerMeta)
+	newMeta.TipBlockid = header.Blockid
+	newMeta.TrunkHeight = header.Height
+	metaBuf, err := proto.Marshal(newMeta)
+	if err != nil {
+		l.xlog.Warn("marshal meta fail", "err", err)
+		return err
+	}
+	batchWrite.Put([]byte(pb.MetaTablePrefix), metaBuf)
+	if err := batchWrite.Write(); err != nil {
+		l.xlog.Warn("batch write failed when confirm snapshot block", "err", err)
+		return err
+	}
+	l.meta = newMeta
+	l.blockCache.Add(string(header.Blockid), header)
+
+	l.xlog.Info("confirm snapshot block succ", "blockid", utils.F(header.Blockid), "height", header.Height)
+	return nil
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 )
 
 type BlockAgent struct {
-	blk *lpb.InternalBlock
+	blk    *lpb.InternalBlock
+	hasher *hash.Hasher
 }
 
 // 兼容xledger账本历史原因共识部分字段分开存储在区块中
@@ -21,8 +23,14 @@ type ConsensusStorage struct {
 }
 
 func NewBlockAgent(blk *lpb.InternalBlock) *BlockAgent {
+	return NewBlockAgentWithHasher(blk, hash.DefaultHasher)
+}
+
+// NewBlockAgentWithHasher 使用链配置的哈希算法计算BlockId
+func NewBlockAgentWithHasher(blk *lpb.InternalBlock, hasher *hash.Hasher) *BlockAgent {
 	return &BlockAgent{
-		blk: blk,
+		blk:    blk,
+		hasher: hasher,
 	}
 }
 
@@ -88,7 +96,7 @@ func (t *BlockAgent) SetItem(item string, value interface{}) error {
 
 // 计算BlockId
 func (t *BlockAgent) MakeBlockId() ([]byte, error) {
-	blkId, err := ledger.MakeBlockID(t.blk)
+	blkId, err := ledger.MakeBlockIDWithHasher(t.blk, t.hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -124,3 +132,33 @@ func (t *BlockAgent) GetTxIDs() []string {
 	return txIDs
 
 }
+
+// GetAwardTx 返回区块的矿工奖励交易，区块不含交易或首个交易不是coinbase交易时返回nil
+func (t *BlockAgent) GetAwardTx() *lpb.Transaction {
+	if len(t.blk.Transactions) == 0 || !t.blk.Transactions[0].Coinbase {
+		return nil
+	}
+	return t.blk.Transactions[0]
+}
+
+// GetBlockHeader 返回不含交易的区块头，仍可独立验证blockid和区块签名
+func (t *BlockAgent) GetBlockHeader() *lpb.InternalBlock {
+	return &lpb.InternalBlock{
+		Version:     t.blk.Version,
+		Nonce:       t.blk.Nonce,
+		Blockid:     t.blk.Blockid,
+		PreHash:     t.blk.PreHash,
+		Proposer:    t.blk.Proposer,
+		Sign:        t.blk.Sign,
+		Pubkey:      t.blk.Pubkey,
+		MerkleRoot:  t.blk.MerkleRoot,
+		Height:      t.blk.Height,
+		Timestamp:   t.blk.Timestamp,
+		TxCount:     t.blk.TxCount,
+		CurTerm:     t.blk.CurTerm,
+		CurBlockNum: t.blk.CurBlockNum,
+		FailedTxs:   t.blk.FailedTxs,
+		TargetBits:  t.blk.TargetBits,
+		Justify:     t.blk.Justify,
+	}
+}
@@ -0,0 +1,332 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/meta"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	// 导入快照时单个batch写入的数据量上限
+	snapshotBatchSize = 4 * 1024 * 1024
+	// 导入中的快照区块id，账本确认快照区块后删除，存在时说明状态机数据不完整
+	snapshotImportKey = "snapshot_import"
+)
+
+// SnapshotReader 状态快照数据读取接口，数据读取完毕时返回io.EOF
+type SnapshotReader interface {
+	Read() (key []byte, value []byte, err error)
+}
+
+// NewSnapshotIterator 获取状态机最新区块对应的全量数据迭代器以及区块id，用于导出状态快照
+// 未确认交易在各节点上不同，迭代器在只读视图中回滚未确认交易，并跳过未确认交易表，
+// 保证各节点在同一区块导出的数据一致，导出过程不修改状态机和mempool。
+// 迭代器读取的是创建时的数据，只支持Next正向遍历，调用方负责Release
+func (t *State) NewSnapshotIterator() ([]byte, kvdb.Iterator, error) {
+	// 独占锁保证存储迭代器与未确认交易列表一致
+	t.utxo.Mutex.Lock()
+	defer t.utxo.Mutex.Unlock()
+
+	unconfirmTxs, _, err := t.tx.SortUnconfirmedTx(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	view := newUndoView()
+	for i := len(unconfirmTxs) - 1; i >= 0; i-- {
+		if err := t.undoTxInView(unconfirmTxs[i], view); err != nil {
+			t.log.Warn("undo unconfirm tx in view failed when export snapshot", "txid", utils.F(unconfirmTxs[i].Txid), "err", err)
+			return nil, nil, err
+		}
+	}
+
+	blockid := append([]byte{}, t.latestBlockid...)
+	iter := newUndoViewIterator(t.ldb.NewIteratorWithPrefix(nil), view, []byte(pb.UnconfirmedTablePrefix))
+	return blockid, iter, nil
+}
+
+// undoTxInView 将回滚交易的数据变更写入只读视图，与undoTxInternal一致但不修改存储和缓存
+func (t *State) undoTxInView(tx *pb.Transaction, view *undoView) error {
+	if err := t.xmodel.UndoTxInView(tx, view); err != nil {
+		return err
+	}
+	for _, txInput := range tx.TxInputs {
+		utxoKey := utxo.GenUtxoKeyWithPrefix(txInput.FromAddr, txInput.RefTxid, txInput.RefOffset)
+		uItem := &utxo.UtxoItem{
+			Amount:       big.NewInt(0).SetBytes(txInput.Amount),
+			FrozenHeight: txInput.FrozenHeight,
+		}
+		uBinary, err := uItem.Dumps()
+		if err != nil {
+			return err
+		}
+		view.Put([]byte(utxoKey), uBinary)
+	}
+	for offset, txOutput := range tx.TxOutputs {
+		if bytes.Equal(txOutput.ToAddr, []byte(FeePlaceholder)) ||
+			big.NewInt(0).SetBytes(txOutput.Amount).Sign() == 0 {
+			continue
+		}
+		view.Delete([]byte(utxo.GenUtxoKeyWithPrefix(txOutput.ToAddr, tx.Txid, int32(offset))))
+	}
+	return nil
+}
+
+// undoView 记录回滚交易产生的数据变更，value为nil表示删除，只用于构造只读视图，不能写入存储
+type undoView struct {
+	kvs  map[string][]byte
+	size int
+}
+
+func newUndoView() *undoView {
+	return &undoView{kvs: make(map[string][]byte)}
+}
+
+func (v *undoView) ValueSize() int {
+	return v.size
+}
+
+func (v *undoView) Write() error {
+	return errors.New("undo view is read only")
+}
+
+func (v *undoView) Reset() {
+	v.kvs = make(map[string][]byte)
+	v.size = 0
+}
+
+func (v *undoView) Put(key []byte, value []byte) error {
+	v.kvs[string(key)] = append([]byte{}, value...)
+	v.size += len(key) + len(value)
+	return nil
+}
+
+func (v *undoView) Delete(key []byte) error {
+	v.kvs[string(key)] = nil
+	v.size += len(key)
+	return nil
+}
+
+func (v *undoView) PutIfAbsent(key []byte, value []byte) error {
+	if _, ok := v.kvs[string(key)]; ok {
+		return nil
+	}
+	return v.Put(key, value)
+}
+
+func (v *undoView) Exist(key []byte) bool {
+	_, ok := v.kvs[string(key)]
+	return ok
+}
+
+// undoViewIterator 按key顺序合并存储迭代器和回滚视图，视图中的数据覆盖存储数据，跳过skipPrefix开头的key
+type undoViewIterator struct {
+	iter       kvdb.Iterator
+	iterValid  bool
+	keys       []string
+	values     map[string][]byte
+	pos        int
+	skipPrefix []byte
+
+	key   []byte
+	value []byte
+}
+
+func newUndoViewIterator(iter kvdb.Iterator, view *undoView, skipPrefix []byte) *undoViewIterator {
+	keys := make([]string, 0, len(view.kvs))
+	for key := range view.kvs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return &undoViewIterator{
+		iter:       iter,
+		iterValid:  iter.Next(),
+		keys:       keys,
+		values:     view.kvs,
+		skipPrefix: skipPrefix,
+	}
+}
+
+func (it *undoViewIterator) Next() bool {
+	for it.iterValid || it.pos < len(it.keys) {
+		var key, value []byte
+		switch {
+		case !it.iterValid || (it.pos < len(it.keys) && it.keys[it.pos] < string(it.iter.Key())):
+			key, value = []byte(it.keys[it.pos]), it.values[it.keys[it.pos]]
+			it.pos++
+		case it.pos < len(it.keys) && it.keys[it.pos] == string(it.iter.Key()):
+			key, value = []byte(it.keys[it.pos]), it.values[it.keys[it.pos]]
+			it.pos++
+			it.iterValid = it.iter.Next()
+		default:
+			key, value = append([]byte{}, it.iter.Key()...), append([]byte{}, it.iter.Value()...)
+			it.iterValid = it.iter.Next()
+		}
+		// 视图中删除的数据
+		if value == nil || bytes.HasPrefix(key, it.skipPrefix) {
+			continue
+		}
+		it.key, it.value = key, value
+		return true
+	}
+	it.key, it.value = nil, nil
+	return false
+}
+
+func (it *undoViewIterator) Key() []byte {
+	return it.key
+}
+
+func (it *undoViewIterator) Value() []byte {
+	return it.value
+}
+
+func (it *undoViewIterator) Prev() bool {
+	return false
+}
+
+func (it *undoViewIterator) Last() bool {
+	return false
+}
+
+func (it *undoViewIterator) First() bool {
+	return false
+}
+
+func (it *undoViewIterator) Error() error {
+	return it.iter.Error()
+}
+
+func (it *undoViewIterator) Release() {
+	it.iter.Release()
+}
+
+// ImportSnapshot 使用状态快照替换状态机全部数据，用于新节点快速同步
+// 清空数据前先记录导入中的快照区块，账本确认快照区块后由FinishSnapshotImport删除，
+// 导入或确认中断时可以通过GetSnapshotImport发现状态机数据不完整，重新导入即可
+func (t *State) ImportSnapshot(blockid []byte, reader SnapshotReader) error {
+	// 重建utxo时沿用原来的锁，避免并发持有锁的调用方出现问题
+	utxoMutex := t.utxo.Mutex
+	utxoMutex.Lock()
+	defer utxoMutex.Unlock()
+
+	t.log.Info("start import state snapshot", "blockid", utils.F(blockid))
+	importKey := []byte(pb.MetaTablePrefix + snapshotImportKey)
+	if err := t.ldb.Put(importKey, blockid); err != nil {
+		return err
+	}
+	if err := t.clearStateDB(importKey); err != nil {
+		t.log.Warn("clear state db failed when import snapshot", "err", err)
+		return err
+	}
+
+	batch := t.ldb.NewBatch()
+	count := 0
+	for {
+		key, value, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.log.Warn("read state snapshot failed", "err", err)
+			return err
+		}
+		if bytes.Equal(key, importKey) {
+			return fmt.Errorf("state snapshot contains reserved key %s", key)
+		}
+		batch.Put(key, value)
+		count++
+		if batch.ValueSize() >= snapshotBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	// 重新加载依赖存储数据初始化的组件
+	metaHandle, err := meta.NewMeta(t.sctx, t.ldb)
+	if err != nil {
+		return fmt.Errorf("reload meta failed after import snapshot:%s", err)
+	}
+	utxoVM, err := utxo.MakeUtxo(t.sctx, metaHandle, t.sctx.LedgerCfg.Utxo.CacheSize,
+		t.sctx.LedgerCfg.Utxo.TmpLockSeconds, t.ldb)
+	if err != nil {
+		return fmt.Errorf("reload utxo failed after import snapshot:%s", err)
+	}
+	utxoVM.Mutex = utxoMutex
+	txHandle, err := tx.NewTx(t.sctx, t.ldb)
+	if err != nil {
+		return fmt.Errorf("reload tx failed after import snapshot:%s", err)
+	}
+	latestBlockid, err := metaHandle.MetaTable.Get([]byte(LatestBlockKey))
+	if err != nil && def.NormalizedKVError(err) != def.ErrKVNotFound {
+		return err
+	}
+	if !bytes.Equal(latestBlockid, blockid) {
+		t.log.Warn("state snapshot not match block", "expect", utils.F(blockid), "got", utils.F(latestBlockid))
+		return fmt.Errorf("state snapshot not match block %x", blockid)
+	}
+
+	t.meta = metaHandle
+	t.utxo = utxoVM
+	t.tx = txHandle
+	t.latestBlockid = latestBlockid
+	t.xmodel.CleanCache()
+	if err := t.tx.LoadUnconfirmedTxFromDisk(); err != nil {
+		return err
+	}
+	t.log.Info("import state snapshot succ", "blockid", utils.F(blockid), "count", count)
+	return nil
+}
+
+// GetSnapshotImport 获取导入中的快照区块id，账本还未确认快照区块时返回true
+func (t *State) GetSnapshotImport() ([]byte, bool) {
+	blockid, err := t.ldb.Get([]byte(pb.MetaTablePrefix + snapshotImportKey))
+	if err != nil {
+		return nil, false
+	}
+	return blockid, true
+}
+
+// FinishSnapshotImport 账本确认快照区块后调用，快照导入完成
+func (t *State) FinishSnapshotImport() error {
+	return t.ldb.Delete([]byte(pb.MetaTablePrefix + snapshotImportKey))
+}
+
+// clearStateDB 删除状态机存储中除保留key外的全部数据
+func (t *State) clearStateDB(reserved []byte) error {
+	iter := t.ldb.NewIteratorWithPrefix(nil)
+	defer iter.Release()
+
+	batch := t.ldb.NewBatch()
+	for iter.Next() {
+		if bytes.Equal(iter.Key(), reserved) {
+			continue
+		}
+		batch.Delete(append([]byte{}, iter.Key()...))
+		if batch.ValueSize() >= snapshotBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
@@ -0,0 +1,259 @@
+package state
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	ledger_pkg "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	crypto_client "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+type sliceSnapshotReader struct {
+	keys   [][]byte
+	values [][]byte
+	pos    int
+}
+
+func (r *sliceSnapshotReader) Read() ([]byte, []byte, error) {
+	if r.pos >= len(r.keys) {
+		return nil, nil, io.EOF
+	}
+	r.pos++
+	return r.keys[r.pos-1], r.values[r.pos-1], nil
+}
+
+func newStateForSnapshotTest(t *testing.T, workspace string, rootQuota string) (*State, []byte) {
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+
+	lctx, err := ledger_pkg.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = workspace
+	ledger, err := ledger_pkg.CreateLedger(lctx, GenesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := txn.GenerateRootTx([]byte(`
+       {
+        "version" : "1"
+        , "consensus" : {
+                "miner" : "0x00000000000"
+        }
+        , "predistribution":[
+                {
+                        "address" : "` + BobAddress + `",
+                        "quota" : "` + rootQuota + `"
+                }
+        ]
+        , "maxblocksize" : "128"
+        , "period" : "5000"
+        , "award" : "1000"
+		}
+    `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := ledger.FormatRootBlock([]*pb.Transaction{tx})
+	confirmStatus := ledger.ConfirmBlock(block, true)
+	if !confirmStatus.Succ {
+		t.Fatal("confirm block fail")
+	}
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx, err := context.NewStateCtx(econf, "xuper", ledger, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx.EnvCfg.ChainDir = workspace
+	stateHandle, err := NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stateHandle.Play(block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return stateHandle, block.Blockid
+}
+
+func TestImportSnapshot(t *testing.T) {
+	srcWorkspace, err := ioutil.TempDir("/tmp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcWorkspace)
+	dstWorkspace, err := ioutil.TempDir("/tmp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstWorkspace)
+
+	srcState, srcBlockid := newStateForSnapshotTest(t, srcWorkspace, "10000000")
+	defer srcState.Close()
+	dstState, _ := newStateForSnapshotTest(t, dstWorkspace, "20000000")
+	defer dstState.Close()
+
+	blockid, iter, err := srcState.NewSnapshotIterator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(blockid, srcBlockid) {
+		t.Fatalf("unexpected snapshot blockid %x", blockid)
+	}
+	reader := &sliceSnapshotReader{}
+	for iter.Next() {
+		reader.keys = append(reader.keys, append([]byte{}, iter.Key()...))
+		reader.values = append(reader.values, append([]byte{}, iter.Value()...))
+	}
+	iter.Release()
+	if len(reader.keys) == 0 {
+		t.Fatal("empty state snapshot")
+	}
+
+	// 快照与区块不匹配
+	if err := dstState.ImportSnapshot([]byte("bad blockid"), &sliceSnapshotReader{
+		keys:   reader.keys,
+		values: reader.values,
+	}); err == nil {
+		t.Fatal("import snapshot with bad blockid should fail")
+	}
+	// 导入失败时保留导入标记，状态机数据不完整
+	if importBlockid, ok := dstState.GetSnapshotImport(); !ok || string(importBlockid) != "bad blockid" {
+		t.Fatal("failed import should keep the import mark")
+	}
+
+	if err := dstState.ImportSnapshot(blockid, reader); err != nil {
+		t.Fatal(err)
+	}
+	if importBlockid, ok := dstState.GetSnapshotImport(); !ok || !bytes.Equal(importBlockid, blockid) {
+		t.Fatal("import mark should be kept until ledger confirms the snapshot block")
+	}
+	if err := dstState.FinishSnapshotImport(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dstState.GetSnapshotImport(); ok {
+		t.Fatal("import mark should be removed after finish")
+	}
+	if !bytes.Equal(dstState.GetLatestBlockid(), srcBlockid) {
+		t.Fatalf("unexpected latest blockid %x", dstState.GetLatestBlockid())
+	}
+	balance, err := dstState.GetBalance(BobAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.String() != "10000000" {
+		t.Fatalf("unexpected balance %s", balance.String())
+	}
+	srcMeta, dstMeta := srcState.GetMeta(), dstState.GetMeta()
+	if srcMeta.GetUtxoTotal() != dstMeta.GetUtxoTotal() {
+		t.Fatalf("unexpected utxo total %s", dstMeta.GetUtxoTotal())
+	}
+}
+
+// readSnapshot 读取状态机导出的全部快照数据
+func readSnapshot(t *testing.T, stateHandle *State) *sliceSnapshotReader {
+	_, iter, err := stateHandle.NewSnapshotIterator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iter.Release()
+	reader := &sliceSnapshotReader{}
+	for iter.Next() {
+		reader.keys = append(reader.keys, append([]byte{}, iter.Key()...))
+		reader.values = append(reader.values, append([]byte{}, iter.Value()...))
+	}
+	if err := iter.Error(); err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+// makeTransferTx 构造Bob转账给Alice的交易
+func makeTransferTx(t *testing.T, stateHandle *State, amount *big.Int) *pb.Transaction {
+	tx := &pb.Transaction{
+		Nonce:       "nonce",
+		Timestamp:   time.Now().UnixNano(),
+		Version:     1,
+		Initiator:   BobAddress,
+		AuthRequire: []string{BobAddress},
+	}
+	txInputs, _, utxoTotal, err := stateHandle.SelectUtxos(BobAddress, amount, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.TxInputs = txInputs
+	tx.TxOutputs = []*protos.TxOutput{
+		{ToAddr: []byte(AliceAddress), Amount: amount.Bytes()},
+		{ToAddr: []byte(BobAddress), Amount: new(big.Int).Sub(utxoTotal, amount).Bytes()},
+	}
+	signBobTx(t, stateHandle, tx)
+	return tx
+}
+
+// signBobTx 使用Bob的私钥签名交易并生成txid
+func signBobTx(t *testing.T, stateHandle *State, tx *pb.Transaction) {
+	sign, err := txhash.ProcessSignTx(stateHandle.sctx.Crypt, tx, []byte(BobPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.InitiatorSigns = []*protos.SignatureInfo{{PublicKey: BobPubkey, Sign: sign}}
+	tx.AuthRequireSigns = tx.InitiatorSigns
+	tx.Txid, _ = txhash.MakeTransactionID(tx)
+}
+
+func TestSnapshotIteratorWithUnconfirmedTx(t *testing.T) {
+	workspace, err := ioutil.TempDir("/tmp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspace)
+	stateHandle, _ := newStateForSnapshotTest(t, workspace, "10000000")
+	defer stateHandle.Close()
+
+	confirmed := readSnapshot(t, stateHandle)
+
+	// 未确认交易只在本节点的mempool中
+	amount := big.NewInt(100)
+	tx := makeTransferTx(t, stateHandle, amount)
+	if err := stateHandle.DoTx(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// 导出的数据与没有未确认交易时一致
+	withUnconfirmed := readSnapshot(t, stateHandle)
+	if len(withUnconfirmed.keys) != len(confirmed.keys) {
+		t.Fatalf("snapshot kv count changed by unconfirmed tx, %d != %d", len(withUnconfirmed.keys), len(confirmed.keys))
+	}
+	for i := range confirmed.keys {
+		if !bytes.Equal(confirmed.keys[i], withUnconfirmed.keys[i]) || !bytes.Equal(confirmed.values[i], withUnconfirmed.values[i]) {
+			t.Fatalf("snapshot kv changed by unconfirmed tx, key:%s", confirmed.keys[i])
+		}
+	}
+
+	// 导出不回滚mempool和状态机中的未确认交易
+	if _, ok := stateHandle.GetUnconfirmedTxFromId(tx.Txid); !ok {
+		t.Fatal("unconfirmed tx should stay in mempool after export")
+	}
+	balance, err := stateHandle.GetBalance(AliceAddress)
+	if err != nil || balance.Cmp(amount) != 0 {
+		t.Fatal("unconfirmed tx should stay in state after export", balance, err)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	aclBase "github.com/xuperchain/xupercore/kernel/permission/acl/base"
 	"github.com/xuperchain/xupercore/lib/cache"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/lib/storage/kvdb"
@@ -61,6 +62,9 @@ var (
 	ErrGetReservedContracts = errors.New("Get reserved contracts error")
 
 	ErrMempoolIsFull = errors.New("Mempool is full")
+
+	// 持有读锁执行交易时发现需要删除 mempool 中的交易
+	errNeedRoomForTx = errors.New("need to make room for tx in mempool")
 )
 
 const (
@@ -88,6 +92,8 @@ type State struct {
 	tx            *tx.Tx         //未确认交易表
 	ldb           kvdb.Database
 	latestBlockid []byte
+	// 在最新区块上校验通过的未确认交易
+	pendingVerified pendingVerified
 
 	// 最新区块高度通知装置
 	heightNotifier *BlockHeightNotifier
@@ -192,10 +198,57 @@ func (t *State) GetUnconfirmedTx(dedup bool, sizeLimit int) ([]*pb.Transaction,
 	return t.tx.GetUnconfirmedTx(dedup, sizeLimit)
 }
 
+// FilterPendingTxs 在最新区块之后的待打包区块中重新校验未在最新区块上校验过的交易（用于矿工打包区块）
+// 交易的执行环境随父区块变化，校验失败的交易以及引用其输出的交易不能打包
+func (t *State) FilterPendingTxs(txs []*pb.Transaction) []*pb.Transaction {
+	parent := t.GetLatestBlockid()
+	dropped := make(map[string]bool)
+	result := make([]*pb.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		txid := string(tx.GetTxid())
+		if relyOnTxs(tx, dropped) {
+			dropped[txid] = true
+			continue
+		}
+		if !tx.Autogen && !tx.Coinbase && !t.pendingVerified.has(parent, txid) {
+			if ok, err := t.VerifyTx(tx); !ok {
+				t.log.Info("drop tx which is invalid in pending block", "txid", utils.F(tx.Txid), "err", err)
+				dropped[txid] = true
+				continue
+			}
+		}
+		result = append(result, tx)
+	}
+	return result
+}
+
+// relyOnTxs 判断交易是否引用了txids中交易的utxo或读写集
+func relyOnTxs(tx *pb.Transaction, txids map[string]bool) bool {
+	for _, input := range tx.GetTxInputs() {
+		if txids[string(input.GetRefTxid())] {
+			return true
+		}
+	}
+	for _, input := range tx.GetTxInputsExt() {
+		if txids[string(input.GetRefTxid())] {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *State) GetLatestBlockid() []byte {
 	return t.latestBlockid
 }
 
+// hasher 账本配置的交易id和摘要哈希算法，未关联账本时使用默认算法
+func (t *State) hasher() *hash.Hasher {
+	if t.sctx == nil || t.sctx.Ledger == nil {
+		return hash.DefaultHasher
+	}
+	return t.sctx.Ledger.GetHasher()
+}
+
 func (t *State) QueryUtxoRecord(accountName string, displayCount int64) (*pb.UtxoRecordDetail, error) {
 	return t.utxo.QueryUtxoRecord(accountName, displayCount)
 }
@@ -393,6 +446,30 @@ func (t *State) BucketCacheDelete(bucket, version string) {
 	t.xmodel.BucketCacheDelete(bucket, version)
 }
 
+// IsTxReferenced 交易详情是否仍被状态机引用，可作为账本裁剪的交易保留策略
+// 交易写入的数据仍是当前版本，或者交易输出的utxo还未花费时，都认为交易仍被引用
+func (t *State) IsTxReferenced(tx *pb.Transaction) bool {
+	if t.xmodel.IsTxReferenced(tx) {
+		return true
+	}
+	for offset, txOutput := range tx.TxOutputs {
+		if bytes.Equal(txOutput.ToAddr, []byte(FeePlaceholder)) {
+			continue
+		}
+		utxoKey := utxo.GenUtxoKeyWithPrefix(txOutput.ToAddr, tx.Txid, int32(offset))
+		exist, err := t.ldb.Has([]byte(utxoKey))
+		if err != nil {
+			// 读取失败时保守处理，认为仍被引用
+			t.log.Warn("check tx utxo referenced failed", "txid", utils.F(tx.Txid), "err", err)
+			return true
+		}
+		if exist {
+			return true
+		}
+	}
+	return false
+}
+
 // 执行区块
 func (t *State) Play(blockid []byte) error {
 	return t.PlayAndRepost(blockid, false, true)
@@ -632,7 +709,7 @@ func (t *State) GetTimerTx(blockHeight int64) (*pb.Transaction, error) {
 	inputs := xmodel.GetTxInputs(rwSet.RSet)
 	outputs := xmodel.GetTxOutputs(rwSet.WSet)
 
-	autoTx, err := tx.GenerateAutoTxWithRWSets(inputs, outputs)
+	autoTx, err := tx.GenerateAutoTxWithRWSetsAndHasher(inputs, outputs, t.hasher())
 	if err != nil {
 		return nil, err
 	}
@@ -782,9 +859,26 @@ func (t *State) doTxSync(tx *pb.Transaction) error {
 		t.log.Warn("    fail to marshal tx", "pbErr", pbErr)
 		return pbErr
 	}
+	// 需要替换或驱逐 mempool 中的交易时，回滚和重做被删除的交易会修改其他交易涉及的 utxo 和读写集，
+	// 这些 key 不在当前交易的 SpLock 范围内，需要持有独占锁；其他交易只持有读锁并发执行
+	exclusive := t.tx.Mempool.NeedRoomForTx(tx)
+	err := t.doTxWithLock(tx, pbTxBuf, exclusive)
+	if err == errNeedRoomForTx {
+		err = t.doTxWithLock(tx, pbTxBuf, true)
+	}
+	return err
+}
+
+// doTxWithLock 持有状态机锁执行交易，exclusive 为 false 时持有读锁，此时不能删除 mempool 中的交易
+func (t *State) doTxWithLock(tx *pb.Transaction, pbTxBuf []byte, exclusive bool) error {
 	recvTime := time.Now()
-	t.utxo.Mutex.RLock()
-	defer t.utxo.Mutex.RUnlock() //lock guard
+	if exclusive {
+		t.utxo.Mutex.Lock()
+		defer t.utxo.Mutex.Unlock()
+	} else {
+		t.utxo.Mutex.RLock()
+		defer t.utxo.Mutex.RUnlock() //lock guard
+	}
 	spLockKeys := t.utxo.SpLock.ExtractLockKeys(tx)
 	succLockKeys, lockOK := t.utxo.SpLock.TryLock(spLockKeys)
 	defer t.utxo.SpLock.Unlock(succLockKeys)
@@ -804,10 +898,39 @@ func (t *State) doTxSync(tx *pb.Transaction) error {
 		return ErrAlreadyInUnconfirmed
 	}
 
-	if t.tx.Mempool.Full() {
+	// 持有读锁时不删除 mempool 中的交易，期间其他交易进入 mempool 导致需要删除交易时，改为持有独占锁重试。
+	if !exclusive {
+		if t.tx.Mempool.NeedRoomForTx(tx) {
+			return errNeedRoomForTx
+		}
+		return t.doUnconfirmedTx(tx, pbTxBuf)
+	}
+
+	// 与 mempool 中的交易冲突时按照手续费替换，mempool 满时驱逐手续费率最低的交易。
+	removedTxs, err := t.tx.Mempool.MakeRoomForTx(tx)
+	if err == txpkg.ErrMempoolFull {
 		t.log.Warn("The tx mempool if full", "txid", utils.F(tx.Txid))
 		return ErrMempoolIsFull
 	}
+	if err != nil {
+		t.log.Info("tx can not replace conflicting txs in mempool", "txid", utils.F(tx.Txid), "err", err)
+		return err
+	}
+	if len(removedTxs) > 0 {
+		if err := t.undoRemovedTxs(removedTxs); err != nil {
+			return err
+		}
+	}
+
+	err = t.doUnconfirmedTx(tx, pbTxBuf)
+	if err != nil && len(removedTxs) > 0 {
+		t.redoRemovedTxs(removedTxs)
+	}
+	return err
+}
+
+// doUnconfirmedTx 执行交易并放入 mempool 和未确认交易表
+func (t *State) doUnconfirmedTx(tx *pb.Transaction, pbTxBuf []byte) error {
 	batch := t.ldb.NewBatch()
 	cacheFiller := &utxo.CacheFiller{}
 	beginTime := time.Now()
@@ -846,6 +969,51 @@ func (t *State) doTxSync(tx *pb.Transaction) error {
 	return nil
 }
 
+// undoRemovedTxs 回滚从 mempool 中替换或驱逐的交易，txs 中子交易在前
+func (t *State) undoRemovedTxs(txs []*pb.Transaction) error {
+	batch := t.ldb.NewBatch()
+	for _, tx := range txs {
+		t.log.Info("undo tx removed from mempool", "txid", utils.F(tx.Txid))
+		if err := t.undoTxInternal(tx, batch); err != nil {
+			t.log.Warn("fail to undo tx removed from mempool", "txid", utils.F(tx.Txid), "err", err)
+			t.ClearCache()
+			t.restoreMempool(txs)
+			return err
+		}
+		batch.Delete(append([]byte(pb.UnconfirmedTablePrefix), tx.Txid...))
+	}
+	if err := batch.Write(); err != nil {
+		t.log.Warn("fail to save to ldb", "writeErr", err)
+		t.ClearCache()
+		t.restoreMempool(txs)
+		return err
+	}
+	return nil
+}
+
+// redoRemovedTxs 新交易执行失败时，重新执行被替换或驱逐的交易，txs 中子交易在前
+func (t *State) redoRemovedTxs(txs []*pb.Transaction) {
+	for i := len(txs) - 1; i >= 0; i-- {
+		tx := txs[i]
+		pbTxBuf, err := proto.Marshal(tx)
+		if err == nil {
+			err = t.doUnconfirmedTx(tx, pbTxBuf)
+		}
+		if err != nil {
+			t.log.Warn("fail to redo tx removed from mempool", "txid", utils.F(tx.Txid), "err", err)
+		}
+	}
+}
+
+// restoreMempool 状态回滚失败时，把交易放回 mempool，txs 中子交易在前
+func (t *State) restoreMempool(txs []*pb.Transaction) {
+	for i := len(txs) - 1; i >= 0; i-- {
+		if err := t.tx.Mempool.PutTx(txs[i]); err != nil {
+			t.log.Warn("fail to restore tx to mempool", "txid", utils.F(txs[i].Txid), "err", err)
+		}
+	}
+}
+
 func (t *State) doTxInternal(tx *pb.Transaction, batch kvdb.Batch, cacheFiller *utxo.CacheFiller) error {
 	t.utxo.CleanBatchCache(batch) // 根据 batch 清理缓存。
 	if tx.GetModifyBlock() == nil || (tx.GetModifyBlock() != nil && !tx.ModifyBlock.Marked) {
@@ -928,9 +1096,30 @@ func (t *State) QueryBlock(blockid []byte) (kledger.BlockHandle, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewBlockAgent(block), nil
+	return NewBlockAgentWithHasher(block, t.hasher()), nil
+
+}
+
+// PendingBlock 返回状态机最新区块的下一个区块，作为预执行和交易进入交易池时合约执行所在的区块
+func (t *State) PendingBlock() (kledger.BlockHandle, error) {
+	return t.pendingBlockOn(t.GetLatestBlockid())
+}
 
+// pendingBlockOn 返回以parentID为父区块的下一个区块
+// 打包前无法确定区块时间，预执行、进入交易池和区块校验都使用父区块的时间戳，
+// 交易打包进以同一区块为父区块的区块时，各节点因此看到相同的区块高度和时间
+func (t *State) pendingBlockOn(parentID []byte) (kledger.BlockHandle, error) {
+	parent, err := t.sctx.Ledger.QueryBlockHeader(parentID)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockAgentWithHasher(&pb.InternalBlock{
+		Height:    parent.GetHeight() + 1,
+		PreHash:   parent.GetBlockid(),
+		Timestamp: parent.GetTimestamp(),
+	}, t.hasher()), nil
 }
+
 func (t *State) QueryTransaction(txid []byte) (*pb2.Transaction, error) {
 	ltx, err := t.sctx.Ledger.QueryTransaction(txid)
 	if err != nil {
@@ -1201,7 +1390,7 @@ func (t *State) procTodoBlkForWalk(todoBlocks []*pb.InternalBlock) (err error) {
 
 			// 校验普通交易合法性
 			if !tx.Autogen && !tx.Coinbase {
-				if ok, err := t.ImmediateVerifyTx(tx, false); !ok {
+				if ok, err := t.immediateVerifyTx(tx, false, todoBlk); !ok {
 					return fmt.Errorf("immediate verify tx error.txid:%s,err:%v", showTxId, err)
 				}
 			}
@@ -1504,3 +1693,17 @@ func (t *State) GetContractDesc(contractName string) (*protos.WasmCodeDesc, erro
 	}
 	return valDesc, err
 }
+
+// GetContractAbi 查询evm合约的abi
+func (t *State) GetContractAbi(contractName string) ([]byte, error) {
+	verdata, err := t.xmodel.Get("contract", bridge.ContractAbiKey(contractName))
+	if err != nil {
+		t.log.Warn("GetContractAbi get version data error", "error", err.Error())
+		return nil, err
+	}
+	abiBuf := verdata.GetPureData().GetValue()
+	if len(abiBuf) == 0 {
+		return nil, fmt.Errorf("contract %s has no abi", contractName)
+	}
+	return abiBuf, nil
+}
@@ -14,6 +14,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+
 	_ "github.com/xuperchain/xupercore/bcs/contract/evm"
 	_ "github.com/xuperchain/xupercore/bcs/contract/native"
 	_ "github.com/xuperchain/xupercore/bcs/contract/xvm"
@@ -723,3 +725,285 @@ func NewAcl(legAgent *LedgerAgent, cmg contract.Manager) (aclBase.AclManager, er
 	}
 	return aclObj, nil
 }
+
+func TestSchnorrVerifySignatures(t *testing.T) {
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+	log, err := logs.NewLogger("", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeSchnorr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &State{sctx: &context.StateCtx{Crypt: crypt}, log: log}
+
+	keys := make([]*ecdsa.PrivateKey, 0, 2)
+	addrs := make([]string, 0, 2)
+	pubJSONs := make([]string, 0, 2)
+	for _, seed := range []string{"schnorr-alice-seed-0123456789", "schnorr-bob-seed-0123456789"} {
+		key, err := crypt.GenerateKeyBySeed([]byte(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := crypt.GetAddressFromPublicKey(&key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubJSON, err := crypt.GetEcdsaPublicKeyJsonFormatStr(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		addrs = append(addrs, addr)
+		pubJSONs = append(pubJSONs, pubJSON)
+	}
+	tx := &pb.Transaction{
+		Desc:        []byte("schnorr"),
+		Initiator:   addrs[0],
+		AuthRequire: []string{addrs[1]},
+		Timestamp:   time.Now().UnixNano(),
+	}
+	digest, err := txhash.MakeTxDigestHash(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 普通签名
+	signTx := proto.Clone(tx).(*pb.Transaction)
+	for idx, key := range keys {
+		sign, err := crypt.SignECDSA(key, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signInfo := &protos.SignatureInfo{PublicKey: pubJSONs[idx], Sign: sign}
+		if idx == 0 {
+			signTx.InitiatorSigns = append(signTx.InitiatorSigns, signInfo)
+		} else {
+			signTx.AuthRequireSigns = append(signTx.AuthRequireSigns, signInfo)
+		}
+	}
+	ok, verified, err := st.verifySignatures(signTx, digest, nil)
+	if !ok || err != nil || !verified[addrs[0]] || !verified[addrs[1]] {
+		t.Fatalf("verify schnorr signatures failed, err:%v", err)
+	}
+	signTx.AuthRequireSigns[0].Sign = signTx.InitiatorSigns[0].Sign
+	if ok, _, _ := st.verifySignatures(signTx, digest, nil); ok {
+		t.Fatal("verify signatures should fail with wrong signature")
+	}
+
+	// XuperSign多重签名
+	multiTx := proto.Clone(tx).(*pb.Transaction)
+	multiSign, err := crypt.MultiSign(keys, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	multiTx.XuperSign = &pb.XuperSignature{
+		PublicKeys: [][]byte{[]byte(pubJSONs[0]), []byte(pubJSONs[1])},
+		Signature:  multiSign,
+	}
+	ok, verified, err = st.verifySignatures(multiTx, digest, nil)
+	if !ok || err != nil || !verified[addrs[0]] || !verified[addrs[1]] {
+		t.Fatalf("verify schnorr XuperSign failed, err:%v", err)
+	}
+	multiTx.XuperSign.PublicKeys = multiTx.XuperSign.PublicKeys[:1]
+	if ok, _, _ := st.verifySignatures(multiTx, digest, nil); ok {
+		t.Fatal("verify XuperSign should fail with missing public key")
+	}
+}
+
+func TestPendingBlockContext(t *testing.T) {
+	workspace, err := ioutil.TempDir("/tmp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspace)
+	stateHandle, rootBlockid := newStateForSnapshotTest(t, workspace, "10000000")
+	defer stateHandle.Close()
+	ledger := stateHandle.sctx.Ledger
+	root, err := ledger.QueryBlockHeader(rootBlockid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 预执行和进入交易池时合约执行在待打包区块中，区块时间为父区块的时间戳
+	pending, err := stateHandle.PendingBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.GetHeight() != root.GetHeight()+1 || pending.GetTimestamp() != root.GetTimestamp() ||
+		!bytes.Equal(pending.GetPreHash(), rootBlockid) {
+		t.Fatalf("unexpected pending block, height:%d timestamp:%d", pending.GetHeight(), pending.GetTimestamp())
+	}
+
+	makeBlock := func(txs []*pb.Transaction, preHash []byte, timestamp int64) *pb.InternalBlock {
+		awardTx, err := txn.GenerateAwardTx("miner-1", "1000", []byte("award"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		block, err := ledger.FormatBlock(append(txs, awardTx), []byte("miner-1"), ecdsaPk, timestamp, 0, 0,
+			preHash, stateHandle.GetTotal())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := ledger.ConfirmBlock(block, false); !status.Succ {
+			t.Fatal("confirm block fail")
+		}
+		return block
+	}
+
+	// 交易不在本节点交易池中，校验区块时合约看到的区块与交易进入交易池时相同
+	tx := makeTransferTx(t, stateHandle, big.NewInt(100))
+	block := makeBlock([]*pb.Transaction{tx}, rootBlockid, root.GetTimestamp()+10*int64(time.Second))
+	execBlock, err := stateHandle.execBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execBlock.GetHeight() != pending.GetHeight() || execBlock.GetTimestamp() != pending.GetTimestamp() ||
+		!bytes.Equal(execBlock.GetPreHash(), pending.GetPreHash()) {
+		t.Fatalf("exec block differs from pending block, height:%d timestamp:%d", execBlock.GetHeight(), execBlock.GetTimestamp())
+	}
+	if err := stateHandle.Play(block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+
+	// 交易池中的交易只在校验时的父区块上免校验
+	tx2 := makeTransferTx(t, stateHandle, big.NewInt(100))
+	if ok, err := stateHandle.VerifyTx(tx2); !ok {
+		t.Fatal(err)
+	}
+	if err := stateHandle.DoTx(tx2); err != nil {
+		t.Fatal(err)
+	}
+	if !stateHandle.pendingVerified.has(block.Blockid, string(tx2.Txid)) {
+		t.Fatal("verified tx should be recorded on the latest block")
+	}
+	emptyBlock := makeBlock(nil, block.Blockid, block.Timestamp+10*int64(time.Second))
+	if err := stateHandle.Play(emptyBlock.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if stateHandle.pendingVerified.has(emptyBlock.Blockid, string(tx2.Txid)) {
+		t.Fatal("tx verified on an older block should be verified again")
+	}
+	// 矿工打包前按新的待打包区块重新校验
+	txs := stateHandle.FilterPendingTxs([]*pb.Transaction{tx2})
+	if len(txs) != 1 || !stateHandle.pendingVerified.has(emptyBlock.Blockid, string(tx2.Txid)) {
+		t.Fatal("valid tx should be kept and recorded after verified again")
+	}
+}
+
+func TestDoTxReplace(t *testing.T) {
+	workspace, err := ioutil.TempDir("/tmp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspace)
+	stateHandle, _ := newStateForSnapshotTest(t, workspace, "10000000")
+	defer stateHandle.Close()
+
+	amount := big.NewInt(100)
+	tx := makeTransferTx(t, stateHandle, amount)
+	if err := stateHandle.DoTx(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// 花费同一个utxo并支付手续费的交易替换mempool中的交易，替换时持有独占锁回滚被替换的交易
+	fee := big.NewInt(10)
+	change := new(big.Int).SetBytes(tx.TxOutputs[1].Amount)
+	replace := &pb.Transaction{
+		Nonce:       "replace",
+		Timestamp:   time.Now().UnixNano(),
+		Version:     1,
+		Initiator:   BobAddress,
+		AuthRequire: []string{BobAddress},
+		TxInputs:    tx.TxInputs,
+		TxOutputs: []*protos.TxOutput{
+			{ToAddr: []byte(AliceAddress), Amount: amount.Bytes()},
+			{ToAddr: []byte(BobAddress), Amount: change.Sub(change, fee).Bytes()},
+			{ToAddr: []byte(pb.FeePlaceholder), Amount: fee.Bytes()},
+		},
+	}
+	signBobTx(t, stateHandle, replace)
+	if !stateHandle.tx.Mempool.NeedRoomForTx(replace) {
+		t.Fatal("conflicting tx should need room in mempool")
+	}
+	if err := stateHandle.DoTx(replace); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stateHandle.GetUnconfirmedTxFromId(tx.Txid); ok {
+		t.Fatal("replaced tx should be removed from mempool")
+	}
+	if _, ok := stateHandle.GetUnconfirmedTxFromId(replace.Txid); !ok {
+		t.Fatal("replacing tx should be in mempool")
+	}
+	balance, err := stateHandle.GetBalance(AliceAddress)
+	if err != nil || balance.Cmp(amount) != 0 {
+		t.Fatal("unexpected balance after replace", balance, err)
+	}
+}
+
+func TestIsTxReferenced(t *testing.T) {
+	workspace, err := ioutil.TempDir("/tmp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspace)
+	stateHandle, rootBlockid := newStateForSnapshotTest(t, workspace, "10000000")
+	defer stateHandle.Close()
+	root, err := stateHandle.sctx.Ledger.QueryBlock(rootBlockid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTx := root.Transactions[0]
+	if !stateHandle.IsTxReferenced(rootTx) {
+		t.Fatal("tx with unspent utxo should be referenced")
+	}
+
+	// 花费创世交易的utxo后，创世交易不再被引用，新交易的utxo未花费仍被引用
+	tx := makeTransferTx(t, stateHandle, big.NewInt(100))
+	if err := stateHandle.DoTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if stateHandle.IsTxReferenced(rootTx) {
+		t.Fatal("tx whose utxo are all spent should not be referenced")
+	}
+	if !stateHandle.IsTxReferenced(tx) {
+		t.Fatal("tx with unspent utxo should be referenced")
+	}
+}
+
+func TestTxCertContext(t *testing.T) {
+	workspace, err := ioutil.TempDir("/tmp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspace)
+	stateHandle, rootBlockid := newStateForSnapshotTest(t, workspace, "10000000")
+	defer stateHandle.Close()
+	root, err := stateHandle.sctx.Ledger.QueryBlockHeader(rootBlockid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := makeTransferTx(t, stateHandle, big.NewInt(100))
+	tx.InitiatorSigns[0].CertChain = [][]byte{[]byte("cert")}
+	// 进入交易池和校验区块时，证书有效期都按父区块的时间戳检查
+	blocks := []*pb.InternalBlock{
+		{PreHash: stateHandle.GetLatestBlockid()},
+		{PreHash: rootBlockid, Height: root.GetHeight() + 1, Timestamp: root.GetTimestamp() + 10*int64(time.Second)},
+	}
+	for i, block := range blocks {
+		certs, err := stateHandle.txCertContext(tx, block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if certs == nil || len(certs.Chains[BobAddress]) != 1 || certs.Time.UnixNano() != root.GetTimestamp() {
+			t.Fatalf("case %d: unexpected cert context %+v", i, certs)
+		}
+	}
+}
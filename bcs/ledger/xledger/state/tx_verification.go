@@ -22,6 +22,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/kernel/permission/acl/rule"
 	aclu "github.com/xuperchain/xupercore/kernel/permission/acl/utils"
 	"github.com/xuperchain/xupercore/lib/crypto/client"
 	"github.com/xuperchain/xupercore/lib/metrics"
@@ -41,12 +42,22 @@ import (
 //  6. run contract requests and verify if the RWSet result is the same with preExed RWSet (heavy
 //     operation, keep it at last)
 func (t *State) ImmediateVerifyTx(tx *pb.Transaction, isRootTx bool) (bool, error) {
+	return t.immediateVerifyTx(tx, isRootTx, nil)
+}
+
+// immediateVerifyTx 校验交易，block为交易所在的区块，交易尚未打包时为nil，此时在最新区块之后的待打包区块中校验
+func (t *State) immediateVerifyTx(tx *pb.Transaction, isRootTx bool, block *pb.InternalBlock) (bool, error) {
 	beginTime := time.Now()
 	code := "InvalidTx"
 	defer func() {
 		metrics.CallMethodCounter.WithLabelValues(t.sctx.BCName, "ImmediateVerifyTx", code).Inc()
 		metrics.CallMethodHistogram.WithLabelValues(t.sctx.BCName, "ImmediateVerifyTx").Observe(time.Since(beginTime).Seconds())
 	}()
+	// 执行环境只由父区块决定，先固定父区块，避免校验过程中最新区块变化
+	pending := block == nil
+	if pending {
+		block = &pb.InternalBlock{PreHash: t.GetLatestBlockid()}
+	}
 
 	// Pre processing of tx data
 	if !isRootTx && tx.Version == RootTxVersion {
@@ -71,7 +82,7 @@ func (t *State) ImmediateVerifyTx(tx *pb.Transaction, isRootTx bool) (bool, erro
 	// Start transaction verification workflow
 	if tx.Version > RootTxVersion {
 		// verify txid
-		txid, err := txhash.MakeTransactionID(tx)
+		txid, err := txhash.MakeTransactionIDWithHasher(tx, t.hasher())
 		if err != nil {
 			t.log.Warn("ImmediateVerifyTx: call MakeTransactionID failed", "error", err)
 			return false, err
@@ -82,14 +93,21 @@ func (t *State) ImmediateVerifyTx(tx *pb.Transaction, isRootTx bool) (bool, erro
 		}
 
 		// get digestHash
-		digestHash, err := txhash.MakeTxDigestHash(tx)
+		digestHash, err := txhash.MakeTxDigestHashWithHasher(tx, t.hasher())
 		if err != nil {
 			t.log.Warn("ImmediateVerifyTx: call MakeTxDigestHash failed", "error", err)
 			return false, err
 		}
 
+		// cert chains of signers for CA_SERVER permission model
+		certs, err := t.txCertContext(tx, block)
+		if err != nil {
+			t.log.Warn("ImmediateVerifyTx: get cert context failed", "error", err)
+			return false, err
+		}
+
 		// verify signatures
-		ok, verifiedID, err := t.verifySignatures(tx, digestHash)
+		ok, verifiedID, err := t.verifySignatures(tx, digestHash, certs)
 		if !ok {
 			t.log.Warn("ImmediateVerifyTx: verifySignatures failed", "error", err)
 			return ok, ErrInvalidSignature
@@ -99,14 +117,14 @@ func (t *State) ImmediateVerifyTx(tx *pb.Transaction, isRootTx bool) (bool, erro
 		authUsers := t.removeDuplicateUser(tx.GetInitiator(), tx.GetAuthRequire())
 
 		// veify tx UTXO input permission (Account ACL)
-		ok, err = t.verifyUTXOPermission(tx, verifiedID)
+		ok, err = t.verifyUTXOPermission(tx, verifiedID, certs)
 		if !ok {
 			t.log.Warn("ImmediateVerifyTx: verifyUTXOPermission failed", "error", err)
 			return ok, ErrACLNotEnough
 		}
 
 		// verify contract requests' permission using ACL
-		ok, err = t.verifyContractPermission(tx, authUsers)
+		ok, err = t.verifyContractPermission(tx, authUsers, certs)
 		if !ok {
 			t.log.Warn("ImmediateVerifyTx: verifyContractPermission failed", "error", err)
 			return ok, ErrACLNotEnough
@@ -120,13 +138,13 @@ func (t *State) ImmediateVerifyTx(tx *pb.Transaction, isRootTx bool) (bool, erro
 		}
 
 		// verify the permission of RWSet using ACL
-		ok, err = t.verifyRWSetPermission(tx, verifiedID)
+		ok, err = t.verifyRWSetPermission(tx, verifiedID, certs)
 		if !ok {
 			t.log.Warn("ImmediateVerifyTx: verifyRWSetPermission failed", "error", err)
 			return ok, ErrACLNotEnough
 		}
 		// verify RWSet(run contracts and compare RWSet)
-		ok, err = t.verifyTxRWSets(tx)
+		ok, err = t.verifyTxRWSets(tx, block)
 		if err != nil {
 			t.log.Warn("ImmediateVerifyTx: verifyTxRWSets failed", "error", err)
 			// reset error message
@@ -143,6 +161,9 @@ func (t *State) ImmediateVerifyTx(tx *pb.Transaction, isRootTx bool) (bool, erro
 		}
 	}
 
+	if pending {
+		t.pendingVerified.add(block.GetPreHash(), string(tx.GetTxid()))
+	}
 	code = "OK"
 	return true, nil
 }
@@ -183,7 +204,7 @@ func (t *State) ImmediateVerifyAutoTx(blockHeight int64, tx *pb.Transaction, isR
 	// Start transaction verification workflow
 	if tx.Version > RootTxVersion {
 		// verify txid
-		txid, err := txhash.MakeTransactionID(tx)
+		txid, err := txhash.MakeTransactionIDWithHasher(tx, t.hasher())
 		if err != nil {
 			t.log.Warn("ImmediateVerifyTx: call MakeTransactionID failed", "error", err)
 			return false, err
@@ -214,7 +235,8 @@ func (t *State) ImmediateVerifyAutoTx(blockHeight int64, tx *pb.Transaction, isR
 }
 
 // Note that if tx.XuperSign is not nil, the signature verification use XuperSign process
-func (t *State) verifySignatures(tx *pb.Transaction, digestHash []byte) (bool, map[string]bool, error) {
+func (t *State) verifySignatures(tx *pb.Transaction, digestHash []byte,
+	certs *rule.CertContext) (bool, map[string]bool, error) {
 	// XuperSign is not empty, use XuperSign verify
 	if tx.GetXuperSign() != nil {
 		return t.verifyXuperSign(tx, digestHash)
@@ -262,7 +284,7 @@ func (t *State) verifySignatures(tx *pb.Transaction, digestHash []byte) (bool, m
 			verifiedAddr[addr] = true
 			initiatorAddr = append(initiatorAddr, tx.Initiator+"/"+addr)
 		}
-		ok, err := aclu.IdentifyAccount(t.sctx.AclMgr, tx.Initiator, initiatorAddr)
+		ok, err := aclu.IdentifyAccountWithCerts(t.sctx.AclMgr, tx.Initiator, initiatorAddr, certs)
 		if !ok {
 			t.log.Warn("verifySignatures initiator permission check failed",
 				"account", tx.Initiator, "error", err)
@@ -287,6 +309,47 @@ func (t *State) verifySignatures(tx *pb.Transaction, digestHash []byte) (bool, m
 	return true, verifiedAddr, nil
 }
 
+// txCertContext collects cert chains attached to signatures of tx for CA_SERVER permission model,
+// expiry of certs is checked with the timestamp of the parent block and CRLs are read from the state
+// of the parent block, the same block context as contract execution, so that pre-exec, mempool and
+// block verification get the same result on all nodes
+func (t *State) txCertContext(tx *pb.Transaction, block *pb.InternalBlock) (*rule.CertContext, error) {
+	chains := make(map[string][][]byte)
+	signs := append(append([]*protos.SignatureInfo{}, tx.InitiatorSigns...), tx.AuthRequireSigns...)
+	for _, sign := range signs {
+		if len(sign.CertChain) == 0 {
+			continue
+		}
+		ak, err := t.sctx.Crypt.GetEcdsaPublicKeyFromJsonStr(sign.PublicKey)
+		if err != nil {
+			continue
+		}
+		addr, err := t.sctx.Crypt.GetAddressFromPublicKey(ak)
+		if err != nil {
+			continue
+		}
+		chains[addr] = sign.CertChain
+	}
+	if len(chains) == 0 {
+		return nil, nil
+	}
+
+	execBlock, err := t.execBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := t.CreateXMSnapshotReader(execBlock.GetPreHash())
+	if err != nil {
+		return nil, err
+	}
+	return &rule.CertContext{
+		Chains: chains,
+		Time:   time.Unix(0, execBlock.GetTimestamp()),
+		CRLs:   aclu.NewCRLReader(reader),
+		Crypto: t.sctx.Crypt,
+	}, nil
+}
+
 func (t *State) verifyXuperSign(tx *pb.Transaction, digestHash []byte) (bool, map[string]bool, error) {
 	uniqueAddrs := make(map[string]bool)
 	// get all addresses
@@ -334,7 +397,8 @@ func (t *State) verifyXuperSign(tx *pb.Transaction, digestHash []byte) (bool, ma
 //	1). PKI technology for transferring from address
 //	2). Account ACL for transferring from account
 //	3). Contract logic transferring from contract
-func (t *State) verifyUTXOPermission(tx *pb.Transaction, verifiedID map[string]bool) (bool, error) {
+func (t *State) verifyUTXOPermission(tx *pb.Transaction, verifiedID map[string]bool,
+	certs *rule.CertContext) (bool, error) {
 	// verify tx input
 	conUtxoInputs, err := xmodel.ParseContractUtxoInputs(tx)
 	if err != nil {
@@ -379,7 +443,7 @@ func (t *State) verifyUTXOPermission(tx *pb.Transaction, verifiedID map[string]b
 				t.log.Warn("verifyUTXOPermission error, account might not exist", "account", name, "error", err)
 				return false, ErrInvalidAccount
 			}
-			if ok, err := aclu.IdentifyAccount(t.sctx.AclMgr, string(name), tx.AuthRequire); !ok {
+			if ok, err := aclu.IdentifyAccountWithCerts(t.sctx.AclMgr, string(name), tx.AuthRequire, certs); !ok {
 				t.log.Warn("verifyUTXOPermission error, failed to IdentifyAccount", "error", err)
 				return false, ErrACLNotEnough
 			}
@@ -397,7 +461,7 @@ func (t *State) verifyUTXOPermission(tx *pb.Transaction, verifiedID map[string]b
 // verifyContractOwnerPermission check if the transaction has the permission of a contract owner.
 // this usually happens in account management operations.
 func (t *State) verifyContractOwnerPermission(contractName string, tx *pb.Transaction,
-	verifiedID map[string]bool) (bool, error) {
+	verifiedID map[string]bool, certs *rule.CertContext) (bool, error) {
 	versionData, confirmed, err := t.xmodel.GetWithTxStatus(aclu.GetContract2AccountBucket(), []byte(contractName))
 	if err != nil || versionData == nil {
 		return false, err
@@ -410,7 +474,7 @@ func (t *State) verifyContractOwnerPermission(contractName string, tx *pb.Transa
 	if verifiedID[accountName] {
 		return true, nil
 	}
-	ok, err := aclu.IdentifyAccount(t.sctx.AclMgr, accountName, tx.AuthRequire)
+	ok, err := aclu.IdentifyAccountWithCerts(t.sctx.AclMgr, accountName, tx.AuthRequire, certs)
 	if err == nil && ok {
 		verifiedID[accountName] = true
 	}
@@ -418,7 +482,8 @@ func (t *State) verifyContractOwnerPermission(contractName string, tx *pb.Transa
 }
 
 // verifyRWSetPermission verify the permission of RWSet using ACL
-func (t *State) verifyRWSetPermission(tx *pb.Transaction, verifiedID map[string]bool) (bool, error) {
+func (t *State) verifyRWSetPermission(tx *pb.Transaction, verifiedID map[string]bool,
+	certs *rule.CertContext) (bool, error) {
 	req := tx.GetContractRequests()
 	// if not contract, pass directly
 	if req == nil {
@@ -438,7 +503,7 @@ func (t *State) verifyRWSetPermission(tx *pb.Transaction, verifiedID map[string]
 			if verifiedID[accountName] {
 				continue
 			}
-			ok, err := aclu.IdentifyAccount(t.sctx.AclMgr, accountName, tx.AuthRequire)
+			ok, err := aclu.IdentifyAccountWithCerts(t.sctx.AclMgr, accountName, tx.AuthRequire, certs)
 			if !ok {
 				t.log.Warn("verifyRWSetPermission check account bucket failed",
 					"account", accountName, "AuthRequire ", tx.AuthRequire, "error", err)
@@ -453,7 +518,7 @@ func (t *State) verifyRWSetPermission(tx *pb.Transaction, verifiedID map[string]
 				return false, errors.New("invalid raw key")
 			}
 			contractName := string(key[:idx])
-			ok, contractErr := t.verifyContractOwnerPermission(contractName, tx, verifiedID)
+			ok, contractErr := t.verifyContractOwnerPermission(contractName, tx, verifiedID, certs)
 			if !ok {
 				t.log.Warn("verifyRWSetPermission check contract bucket failed",
 					"contract", contractName, "AuthRequire ", tx.AuthRequire, "error", contractErr)
@@ -470,7 +535,7 @@ func (t *State) verifyRWSetPermission(tx *pb.Transaction, verifiedID map[string]
 			if verifiedID[accountName] {
 				continue
 			}
-			ok, accountErr := aclu.IdentifyAccount(t.sctx.AclMgr, accountName, tx.AuthRequire)
+			ok, accountErr := aclu.IdentifyAccountWithCerts(t.sctx.AclMgr, accountName, tx.AuthRequire, certs)
 			if !ok {
 				t.log.Warn("verifyRWSetPermission check contract2account bucket failed",
 					"account", accountName, "AuthRequire ", tx.AuthRequire, "error", accountErr)
@@ -483,7 +548,8 @@ func (t *State) verifyRWSetPermission(tx *pb.Transaction, verifiedID map[string]
 }
 
 // verifyContractValid verify the permission of contract requests using ACL
-func (t *State) verifyContractPermission(tx *pb.Transaction, allUsers []string) (bool, error) {
+func (t *State) verifyContractPermission(tx *pb.Transaction, allUsers []string,
+	certs *rule.CertContext) (bool, error) {
 	req := tx.GetContractRequests()
 	if req == nil {
 		// if no contract requests, no need to verify
@@ -495,7 +561,7 @@ func (t *State) verifyContractPermission(tx *pb.Transaction, allUsers []string)
 		contractName := tmpReq.GetContractName()
 		methodName := tmpReq.GetMethodName()
 
-		ok, err := aclu.CheckContractMethodPerm(t.sctx.AclMgr, allUsers, contractName, methodName)
+		ok, err := aclu.CheckContractMethodPermWithCerts(t.sctx.AclMgr, allUsers, contractName, methodName, certs)
 		if err != nil || !ok {
 			t.log.Warn("verify contract method ACL failed ", "contract", contractName, "method",
 				methodName, "error", err)
@@ -541,7 +607,7 @@ func (t *State) verifyContractTxAmount(tx *pb.Transaction) (bool, error) {
 }
 
 // verifyTxRWSets verify tx read sets and write sets
-func (t *State) verifyTxRWSets(tx *pb.Transaction) (bool, error) {
+func (t *State) verifyTxRWSets(tx *pb.Transaction, block *pb.InternalBlock) (bool, error) {
 	if t.VerifyReservedWhitelist(tx) {
 		t.log.Info("verifyReservedWhitelist true", "txid", fmt.Sprintf("%x", tx.GetTxid()))
 		return true, nil
@@ -583,9 +649,16 @@ func (t *State) verifyTxRWSets(tx *pb.Transaction) (bool, error) {
 		return false, err
 	}
 	utxoReader := sandbox.NewUTXOReaderFromInput(utxoInput)
+	// 跨链查询使用交易中记录的带背书的查询结果重放，保证验证结果确定
+	crossQueryInfos, err := sandbox.ParseCrossQuery(tx)
+	if err != nil {
+		return false, err
+	}
+	crossQueryReader := sandbox.NewCrossQueryReaderFromInfos(crossQueryInfos, t.sctx.Crypt)
 	sandBoxConfig := &contract.SandboxConfig{
-		XMReader:   reader,
-		UTXOReader: utxoReader,
+		XMReader:         reader,
+		UTXOReader:       utxoReader,
+		CrossQueryReader: crossQueryReader,
 	}
 	sandBox, err := t.sctx.ContractMgr.NewStateSandbox(sandBoxConfig)
 	if err != nil {
@@ -598,11 +671,16 @@ func (t *State) verifyTxRWSets(tx *pb.Transaction) (bool, error) {
 		return false, err
 	}
 
+	execBlock, err := t.execBlock(block)
+	if err != nil {
+		return false, err
+	}
 	contextConfig := &contract.ContextConfig{
 		State:       sandBox,
 		Initiator:   tx.GetInitiator(),
 		AuthRequire: tx.GetAuthRequire(),
 		ChainName:   t.sctx.BCName,
+		Block:       execBlock,
 	}
 	gasLimit, err := getGasLimitFromTx(tx)
 	if err != nil {
@@ -687,6 +765,35 @@ func (t *State) verifyTxRWSets(tx *pb.Transaction) (bool, error) {
 	return true, nil
 }
 
+// execBlock 返回交易在block中执行时合约所见的区块，只由block的父区块决定，见pendingBlockOn
+func (t *State) execBlock(block *pb.InternalBlock) (kledger.BlockHandle, error) {
+	return t.pendingBlockOn(block.GetPreHash())
+}
+
+// pendingVerified 记录在同一父区块上校验通过的未确认交易
+// 打包进以该区块为父区块的区块时交易的执行环境不变，无需重复校验；最新区块变化后记录全部失效
+type pendingVerified struct {
+	mutex  sync.Mutex
+	parent []byte
+	txids  map[string]bool
+}
+
+func (p *pendingVerified) add(parent []byte, txid string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if !bytes.Equal(p.parent, parent) {
+		p.parent = parent
+		p.txids = make(map[string]bool)
+	}
+	p.txids[txid] = true
+}
+
+func (p *pendingVerified) has(parent []byte, txid string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return bytes.Equal(p.parent, parent) && p.txids[txid]
+}
+
 // verifyAutoTxRWSets verify auto tx read sets and write sets
 func (t *State) verifyAutoTxRWSets(tx, autoTx *pb.Transaction) (bool, error) {
 	txRsets := tx.GetTxInputsExt()
@@ -759,7 +866,7 @@ func (t *State) verifyMarkedTx(tx *pb.Transaction) error {
 		return errors.New("invalide arg type: sign byte")
 	}
 	tx.ModifyBlock = &pb.ModifyBlock{}
-	digestHash, err := txhash.MakeTxDigestHash(tx)
+	digestHash, err := txhash.MakeTxDigestHashWithHasher(tx, t.hasher())
 	if err != nil {
 		t.log.Warn("verifyMarkedTx call MakeTxDigestHash failed", "error", err)
 		return err
@@ -809,6 +916,7 @@ func (t *State) checkRelyOnMarkedTxid(reftxid []byte, blockid []byte) (bool, boo
 	isRely := false
 	reftx, err := t.sctx.Ledger.QueryTransaction(reftxid)
 	if err != nil {
+		// 账本裁剪会保留被标记的交易，已裁剪的交易一定未被标记，与全量节点结果一致
 		return true, isRely, nil
 	}
 	if reftx.GetModifyBlock() != nil && reftx.ModifyBlock.Marked {
@@ -903,7 +1011,7 @@ func (t *State) verifyBlockTxs(block *pb.InternalBlock, isRootTx bool, unconfirm
 		wg.Add(1)
 		go func(txs []*pb.Transaction) {
 			defer wg.Done()
-			verifyErr := t.verifyDAGTxs(block.Height, txs, isRootTx, unconfirmToConfirm)
+			verifyErr := t.verifyDAGTxs(block, txs, isRootTx, unconfirmToConfirm)
 			onceBody := func() {
 				err = verifyErr
 			}
@@ -917,23 +1025,24 @@ func (t *State) verifyBlockTxs(block *pb.InternalBlock, isRootTx bool, unconfirm
 	return err
 }
 
-func (t *State) verifyDAGTxs(blockHeight int64, txs []*pb.Transaction, isRootTx bool, unconfirmToConfirm map[string]bool) error {
+func (t *State) verifyDAGTxs(block *pb.InternalBlock, txs []*pb.Transaction, isRootTx bool, unconfirmToConfirm map[string]bool) error {
 	for _, tx := range txs {
 		if tx == nil {
 			return errors.New("verifyTx error, tx is nil")
 		}
 		txid := string(tx.GetTxid())
-		if unconfirmToConfirm[txid] == false {
+		// 交易池中的交易只有在同一父区块上校验过时才能跳过校验，否则按区块中的执行环境重新校验
+		if !unconfirmToConfirm[txid] || !t.pendingVerified.has(block.GetPreHash(), txid) {
 			if t.verifyAutogenTxValid(tx) {
 				// 校验auto tx
-				if ok, err := t.ImmediateVerifyAutoTx(blockHeight, tx, isRootTx); !ok {
+				if ok, err := t.ImmediateVerifyAutoTx(block.Height, tx, isRootTx); !ok {
 					t.log.Warn("dotx failed to ImmediateVerifyAutoTx", "txid", fmt.Sprintf("%x", tx.Txid), "err", err)
 					return errors.New("dotx failed to ImmediateVerifyAutoTx error")
 				}
 			}
 			if !tx.Autogen && !tx.Coinbase {
 				// 校验用户交易
-				if ok, err := t.ImmediateVerifyTx(tx, isRootTx); !ok {
+				if ok, err := t.immediateVerifyTx(tx, isRootTx, block); !ok {
 					t.log.Warn("dotx failed to ImmediateVerifyTx", "txid", fmt.Sprintf("%x", tx.Txid), "err", err)
 					ok, isRelyOnMarkedTx, err := t.verifyMarked(tx)
 					if isRelyOnMarkedTx {
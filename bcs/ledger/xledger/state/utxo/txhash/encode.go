@@ -1,13 +1,13 @@
 package txhash
 
 import (
-	"crypto/sha256"
 	"encoding/binary"
 	"io"
 	"log"
 	"sort"
 
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 	"github.com/xuperchain/xupercore/protos"
 )
 
@@ -87,9 +87,9 @@ func (e *encoder) Encode(x interface{}) {
 	}
 }
 
-// txDigestHashV2 make tx hash using double sha256
-func txDigestHashV2(tx *pb.Transaction, includeSigns bool) []byte {
-	h := sha256.New()
+// txDigestHashV2 make tx hash using double hash of hasher, nil hasher means double sha256
+func txDigestHashV2(tx *pb.Transaction, includeSigns bool, hasher *hash.Hasher) []byte {
+	h := hasher.New()
 	enc := newEncoder(h)
 
 	// encode TxInputs
@@ -161,6 +161,10 @@ func txDigestHashV2(tx *pb.Transaction, includeSigns bool) []byte {
 		for _, sig := range sigs {
 			enc.Encode(sig.PublicKey)
 			enc.Encode(sig.Sign)
+			// only encode cert chain when it exists to keep txid of existing tx unchanged
+			if len(sig.CertChain) > 0 {
+				enc.Encode(sig.CertChain)
+			}
 		}
 	}
 	if includeSigns {
@@ -178,6 +182,5 @@ func txDigestHashV2(tx *pb.Transaction, includeSigns bool) []byte {
 	enc.Encode(tx.GetHDInfo().GetHdPublicKey())
 	enc.Encode(tx.GetHDInfo().GetOriginalHash())
 
-	sum := sha256.Sum256(h.Sum(nil))
-	return sum[:]
+	return hasher.DoubleHashSum(h)
 }
@@ -9,29 +9,39 @@ import (
 	"github.com/xuperchain/xupercore/lib/crypto/hash"
 )
 
-// MakeTransactionID 事务id生成
+// MakeTransactionID 事务id生成，使用默认哈希算法
 func MakeTransactionID(tx *pb.Transaction) ([]byte, error) {
+	return MakeTransactionIDWithHasher(tx, hash.DefaultHasher)
+}
+
+// MakeTransactionIDWithHasher 使用链配置的哈希算法生成事务id
+func MakeTransactionIDWithHasher(tx *pb.Transaction, hasher *hash.Hasher) ([]byte, error) {
 	if tx.Version >= 3 {
-		return txDigestHashV2(tx, true), nil
+		return txDigestHashV2(tx, true, hasher), nil
 	}
 	coreData, err := encodeTxData(tx, true)
 	if err != nil {
 		return nil, err
 	}
-	return hash.DoubleSha256(coreData), nil
+	return hasher.DoubleHash(coreData), nil
 }
 
-// MakeTxDigestHash 生成交易关键信息的hash, 不含汇款人公钥、签名等字段
+// MakeTxDigestHash 生成交易关键信息的hash, 不含汇款人公钥、签名等字段，使用默认哈希算法
 func MakeTxDigestHash(tx *pb.Transaction) ([]byte, error) {
+	return MakeTxDigestHashWithHasher(tx, hash.DefaultHasher)
+}
+
+// MakeTxDigestHashWithHasher 使用链配置的哈希算法生成交易关键信息的hash
+func MakeTxDigestHashWithHasher(tx *pb.Transaction, hasher *hash.Hasher) ([]byte, error) {
 	if tx.Version >= 3 {
-		return txDigestHashV2(tx, false), nil
+		return txDigestHashV2(tx, false, hasher), nil
 	}
 
 	coreData, err := encodeTxData(tx, false)
 	if err != nil {
 		return nil, err
 	}
-	return hash.DoubleSha256(coreData), nil
+	return hasher.DoubleHash(coreData), nil
 }
 
 // encodeTxData encode core transaction data into bytes
@@ -163,11 +173,17 @@ func encodeTxData(tx *pb.Transaction, includeSigns bool) ([]byte, error) {
 
 // ProcessSignTx 签名Tx
 func ProcessSignTx(cryptoClient crypto_base.CryptoClient, tx *pb.Transaction, jsonSK []byte) ([]byte, error) {
+	return ProcessSignTxWithHasher(cryptoClient, tx, jsonSK, hash.DefaultHasher)
+}
+
+// ProcessSignTxWithHasher 使用链配置的哈希算法计算摘要并签名Tx
+func ProcessSignTxWithHasher(cryptoClient crypto_base.CryptoClient, tx *pb.Transaction, jsonSK []byte,
+	hasher *hash.Hasher) ([]byte, error) {
 	privateKey, err := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(string(jsonSK))
 	if err != nil {
 		return nil, err
 	}
-	digestHash, dhErr := MakeTxDigestHash(tx)
+	digestHash, dhErr := MakeTxDigestHashWithHasher(tx, hasher)
 	if dhErr != nil {
 		return nil, dhErr
 	}
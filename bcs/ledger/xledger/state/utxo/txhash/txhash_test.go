@@ -9,6 +9,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	crypto_client "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 )
 
 var (
@@ -34,7 +35,7 @@ func BenchmarkTxHashV2(b *testing.B) {
 	tx := readTxFile(b, "tx.pb")
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		txDigestHashV2(tx, true)
+		txDigestHashV2(tx, true, nil)
 	}
 }
 
@@ -79,3 +80,30 @@ func TestTestSignTx(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestTxHashWithHasher(t *testing.T) {
+	tx := readTxFile(t, "tx.pb")
+	for _, version := range []int32{1, 3} {
+		tx.Version = version
+		txids := make(map[string]bool)
+		for _, hashType := range []string{hash.HashTypeSha256, hash.HashTypeSM3, hash.HashTypeSha3} {
+			hasher, err := hash.GetHasher(hashType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txid, err := MakeTransactionIDWithHasher(tx, hasher)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txids[hex.EncodeToString(txid)] = true
+		}
+		if len(txids) != 3 {
+			t.Fatalf("txid of different hash types should differ when version = %d", version)
+		}
+		// 默认哈希算法与原有的交易id保持一致
+		txid, _ := MakeTransactionID(tx)
+		if !txids[hex.EncodeToString(txid)] {
+			t.Fatalf("txid of default hasher changed when version = %d", version)
+		}
+	}
+}
@@ -30,6 +30,8 @@ func MarshalMessages(msgs interface{}) ([]byte, error) {
 	}
 
 	var buf proto.Buffer
+	// 消息中可能包含map，需要保证序列化结果确定，否则验证交易时读写集可能不一致
+	buf.SetDeterministic(true)
 	buf.EncodeVarint(uint64(value.Len()))
 	for i := 0; i < value.Len(); i++ {
 		msg := value.Index(i).Interface().(proto.Message)
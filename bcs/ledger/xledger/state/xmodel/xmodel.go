@@ -63,6 +63,11 @@ func (s *XModel) CreateSnapshot(blkId []byte) (kledger.XMReader, error) {
 		return nil, fmt.Errorf("query block header fail.block_id:%s, err:%v",
 			hex.EncodeToString(blkId), err)
 	}
+	// 已裁剪高度及以下的区块交易详情可能已被删除，不支持在这些区块上创建快照
+	if prunedHeight := s.ledger.GetPrunedHeight(); prunedHeight > 0 && blkInfo.Height <= prunedHeight {
+		return nil, fmt.Errorf("%w. block_id:%s height:%d pruned_height:%d", ErrSnapshotPruned,
+			hex.EncodeToString(blkId), blkInfo.Height, prunedHeight)
+	}
 
 	xms := &xModSnapshot{
 		xmod:      s,
@@ -140,6 +145,15 @@ func (s *XModel) DoTx(tx *pb.Transaction, batch kvdb.Batch) error {
 // UndoTx rollback a transaction and update extUtxoTable
 func (s *XModel) UndoTx(tx *pb.Transaction, batch kvdb.Batch) error {
 	s.cleanCache(batch)
+	return s.undoTx(tx, batch, true)
+}
+
+// UndoTxInView 只将回滚交易的数据变更写入batch，不修改缓存，用于构造回滚交易后的只读视图
+func (s *XModel) UndoTxInView(tx *pb.Transaction, batch kvdb.Batch) error {
+	return s.undoTx(tx, batch, false)
+}
+
+func (s *XModel) undoTx(tx *pb.Transaction, batch kvdb.Batch, updateCache bool) error {
 	inputVersionMap := map[string]string{}
 	for _, txIn := range tx.TxInputsExt {
 		rawKey := string(makeRawKey(txIn.Bucket, txIn.Key))
@@ -156,7 +170,9 @@ func (s *XModel) UndoTx(tx *pb.Transaction, batch kvdb.Batch) error {
 			delKey := append([]byte(pb.ExtUtxoTablePrefix), bucketAndKey...)
 			batch.Delete(delKey)
 			s.logger.Trace("    undo xmodel del", "delkey", string(delKey))
-			s.batchCache.Store(string(bucketAndKey), "")
+			if updateCache {
+				s.batchCache.Store(string(bucketAndKey), "")
+			}
 		} else {
 			verData, err := s.fetchVersionedData(txOut.Bucket, previousVersion)
 			if err != nil {
@@ -178,12 +194,41 @@ func (s *XModel) UndoTx(tx *pb.Transaction, batch kvdb.Batch) error {
 					batch.Delete(delKey) //remove garbage in gc table
 				}
 			}
-			s.batchCache.Store(string(bucketAndKey), previousVersion)
+			if updateCache {
+				s.batchCache.Store(string(bucketAndKey), previousVersion)
+			}
 		}
 	}
 	return nil
 }
 
+// IsTxReferenced 交易写入的数据是否仍是状态机中的当前版本（包括删除标记），
+// 状态机数据保存在交易中，被引用的交易详情不能被裁剪
+func (s *XModel) IsTxReferenced(tx *pb.Transaction) bool {
+	for offset, txOut := range tx.TxOutputsExt {
+		if txOut.Bucket == TransientBucket {
+			continue
+		}
+		table := s.extUtxoTable
+		if isDelFlag(txOut.Value) {
+			table = s.extUtxoDelTable
+		}
+		version, err := table.Get(makeRawKey(txOut.Bucket, txOut.Key))
+		if err != nil {
+			if kvdb.ErrNotFound(err) {
+				continue
+			}
+			// 读取失败时保守处理，认为仍被引用
+			s.logger.Warn("check tx referenced failed", "txid", hex.EncodeToString(tx.Txid), "err", err)
+			return true
+		}
+		if string(version) == MakeVersion(tx.Txid, int32(offset)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *XModel) fetchVersionedData(bucket, version string) (*kledger.VersionedData, error) {
 	value, ok := s.bucketCacheGet(bucket, version)
 	if ok {
@@ -3,14 +3,19 @@ package xmodel
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/protos"
 )
 
+// ErrSnapshotPruned 快照高度对应的版本数据所在交易已被账本裁剪，无法读取
+var ErrSnapshotPruned = errors.New("snapshot data has been pruned")
+
 type xModSnapshot struct {
 	xmod      *XModel
 	logger    logs.Logger
@@ -34,6 +39,12 @@ func (t *xModSnapshot) Get(bucket string, key []byte) (*kledger.VersionedData, e
 		return nil, fmt.Errorf("get newest version data fail.err:%v", err)
 	}
 
+	return t.getByNewestVersion(bucket, key, newestVD)
+}
+
+// 从最新版本数据开始，沿交易的TxInputsExt回溯，找到快照高度时的版本数据
+func (t *xModSnapshot) getByNewestVersion(bucket string, key []byte,
+	newestVD *kledger.VersionedData) (*kledger.VersionedData, error) {
 	// 通过txid串联查询，直到找到<=blkHeight的交易
 	var verValue *kledger.VersionedData
 	cursor := &xModListCursor{newestVD.RefTxid, newestVD.RefOffset}
@@ -45,6 +56,10 @@ func (t *xModSnapshot) Get(bucket string, key []byte) (*kledger.VersionedData, e
 
 		// 通过txid查询交易信息
 		txInfo, _, err := t.xmod.QueryTx(cursor.txid)
+		if err == ledger.ErrTxPruned {
+			// 裁剪只保留当前版本数据，被覆盖的历史版本无法再回溯
+			return nil, fmt.Errorf("%w. bucket:%s key:%s txid:%x", ErrSnapshotPruned, bucket, key, cursor.txid)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("query tx fail.err:%v", err)
 		}
@@ -77,8 +92,13 @@ func (t *xModSnapshot) Get(bucket string, key []byte) (*kledger.VersionedData, e
 	return verValue, nil
 }
 
+// Select 遍历快照高度时bucket内[startKey, endKey)范围的数据，快照时不存在或已删除的key会被跳过
 func (t *xModSnapshot) Select(bucket string, startKey []byte, endKey []byte) (kledger.XMIterator, error) {
-	return nil, fmt.Errorf("xmodel snapshot temporarily not supported select")
+	if !t.isInit() || bucket == "" {
+		return nil, fmt.Errorf("xmod snapshot not init or param set error")
+	}
+
+	return newXModSnapshotIterator(t, bucket, startKey, endKey), nil
 }
 
 func (t *xModSnapshot) isInit() bool {
@@ -0,0 +1,130 @@
+package xmodel
+
+import (
+	"bytes"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	kledger "github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+// xModSnapshotIterator 快照范围查询迭代器
+// 快照时存在的key当前可能已被删除，因此需要同时遍历extUtxoTable和extUtxoDelTable，
+// 按key有序归并后，再从最新版本回溯到快照高度时的版本
+type xModSnapshotIterator struct {
+	snapshot *xModSnapshot
+	bucket   string
+	extIter  kvdb.Iterator
+	delIter  kvdb.Iterator
+	extValid bool
+	delValid bool
+	value    *kledger.VersionedData
+	err      error
+}
+
+func newXModSnapshotIterator(snapshot *xModSnapshot, bucket string,
+	startKey []byte, endKey []byte) *xModSnapshotIterator {
+	rawStartKey := makeRawKey(bucket, startKey)
+	rawEndKey := makeRawKey(bucket, endKey)
+	iter := &xModSnapshotIterator{
+		snapshot: snapshot,
+		bucket:   bucket,
+		extIter:  snapshot.xmod.extUtxoTable.NewIteratorWithRange(rawStartKey, rawEndKey),
+		delIter:  snapshot.xmod.extUtxoDelTable.NewIteratorWithRange(rawStartKey, rawEndKey),
+	}
+	iter.extValid = iter.extIter.Next()
+	iter.delValid = iter.delIter.Next()
+	return iter
+}
+
+// Value get data pointer to VersionedData for xModSnapshotIterator
+func (si *xModSnapshotIterator) Value() *kledger.VersionedData {
+	return si.value
+}
+
+// Next check if next element exist
+func (si *xModSnapshotIterator) Next() bool {
+	for si.err == nil {
+		version, ok := si.nextVersion()
+		if !ok {
+			return false
+		}
+		newestVD, err := si.snapshot.xmod.fetchVersionedData(si.bucket, version)
+		if err != nil {
+			si.err = err
+			return false
+		}
+		verData, err := si.snapshot.getByNewestVersion(si.bucket, newestVD.GetPureData().GetKey(), newestVD)
+		if err != nil {
+			si.err = err
+			return false
+		}
+		// 快照时key尚未写入或已被删除
+		if IsEmptyVersionedData(verData) || isDelFlag(verData.GetPureData().GetValue()) {
+			continue
+		}
+		si.value = verData
+		return true
+	}
+	return false
+}
+
+// nextVersion 归并两张表，返回下一个key的最新版本
+// 同一个key删除后再次写入时两张表都有记录，以extUtxoTable中的版本为准
+func (si *xModSnapshotIterator) nextVersion() (string, bool) {
+	if !si.extValid && !si.delValid {
+		return "", false
+	}
+	if !si.extValid {
+		version := string(si.delIter.Value())
+		si.delValid = si.delIter.Next()
+		return version, true
+	}
+	if !si.delValid {
+		version := string(si.extIter.Value())
+		si.extValid = si.extIter.Next()
+		return version, true
+	}
+
+	extKey := si.extIter.Key()[len(pb.ExtUtxoTablePrefix):]
+	delKey := si.delIter.Key()[len(pb.ExtUtxoDelTablePrefix):]
+	cmp := bytes.Compare(extKey, delKey)
+	if cmp > 0 {
+		version := string(si.delIter.Value())
+		si.delValid = si.delIter.Next()
+		return version, true
+	}
+	version := string(si.extIter.Value())
+	if cmp == 0 {
+		si.delValid = si.delIter.Next()
+	}
+	si.extValid = si.extIter.Next()
+	return version, true
+}
+
+// Key get key for xModSnapshotIterator
+func (si *xModSnapshotIterator) Key() []byte {
+	v := si.Value()
+	if v == nil {
+		return nil
+	}
+	return v.GetPureData().GetKey()
+}
+
+// Error return error info for xModSnapshotIterator
+func (si *xModSnapshotIterator) Error() error {
+	if err := si.extIter.Error(); err != nil {
+		return err
+	}
+	if err := si.delIter.Error(); err != nil {
+		return err
+	}
+	return si.err
+}
+
+// Close release xModSnapshotIterator
+func (si *xModSnapshotIterator) Close() {
+	si.extIter.Release()
+	si.delIter.Release()
+	si.value = nil
+}
@@ -1,9 +1,14 @@
 package xmodel
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
@@ -115,3 +120,197 @@ func TestGet(t *testing.T) {
 
 	ledger.Close()
 }
+
+func TestSelect(t *testing.T) {
+	workspace, dirErr := ioutil.TempDir("/tmp", "")
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+	os.RemoveAll(workspace)
+	defer os.RemoveAll(workspace)
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+
+	lctx, err := ledger_pkg.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = workspace
+
+	ledger, err := ledger_pkg.CreateLedger(lctx, GenesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	t0 := &pb.Transaction{}
+	t0.TxOutputs = append(t0.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	t0.Coinbase = true
+	t0.Desc = []byte(`{"maxblocksize" : "128"}`)
+	t0.Txid, _ = txhash.MakeTransactionID(t0)
+	rootBlock, err := ledger.FormatRootBlock([]*pb.Transaction{t0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(rootBlock, true); !status.Succ {
+		t.Fatal(fmt.Errorf("confirm block fail"))
+	}
+
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx, err := context.NewStateCtx(econf, "xuper", ledger, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx.EnvCfg.ChainDir = workspace
+
+	storePath := sctx.EnvCfg.GenDataAbsPath(sctx.EnvCfg.ChainDir)
+	storePath = filepath.Join(storePath, sctx.BCName)
+	stateDBPath := filepath.Join(storePath, def.StateStrgDirName)
+	kvParam := &kvdb.KVParameter{
+		DBPath:                stateDBPath,
+		KVEngineType:          sctx.LedgerCfg.KVEngineType,
+		MemCacheSize:          ledger_pkg.MemCacheSize,
+		FileHandlersCacheSize: ledger_pkg.FileHandlersCacheSize,
+		OtherPaths:            sctx.LedgerCfg.OtherPaths,
+		StorageType:           sctx.LedgerCfg.StorageType,
+	}
+	ldb, err := kvdb.CreateKVInstance(kvParam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xmod, err := NewXModel(sctx, ldb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecdsaPk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 将交易打包进区块并更新xmodel
+	doBlock := func(tx *pb.Transaction, preHash []byte) *pb.InternalBlock {
+		tx.Txid, _ = txhash.MakeTransactionID(tx)
+		block, err := ledger.FormatBlock([]*pb.Transaction{tx}, []byte(BobAddress), ecdsaPk,
+			223456789, 0, 0, preHash, big.NewInt(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := ledger.ConfirmBlock(block, false); !status.Succ {
+			t.Fatal(fmt.Errorf("confirm block fail"))
+		}
+		batch := ldb.NewBatch()
+		if err := xmod.DoTx(tx, batch); err != nil {
+			t.Fatal(err)
+		}
+		if err := batch.Write(); err != nil {
+			t.Fatal(err)
+		}
+		return block
+	}
+
+	// 区块1写入key_1、key_2、key_3
+	tx1 := &pb.Transaction{Desc: []byte("tx1")}
+	for _, k := range []string{"key_1", "key_2", "key_3"} {
+		tx1.TxInputsExt = append(tx1.TxInputsExt, &protos.TxInputExt{Bucket: "bucket1", Key: []byte(k)})
+		tx1.TxOutputsExt = append(tx1.TxOutputsExt,
+			&protos.TxOutputExt{Bucket: "bucket1", Key: []byte(k), Value: []byte("v1")})
+	}
+	block1 := doBlock(tx1, rootBlock.Blockid)
+
+	// 区块2更新key_1、删除key_3、新增key_4
+	tx2 := &pb.Transaction{
+		Desc: []byte("tx2"),
+		TxInputsExt: []*protos.TxInputExt{
+			{Bucket: "bucket1", Key: []byte("key_1"), RefTxid: tx1.Txid, RefOffset: 0},
+			{Bucket: "bucket1", Key: []byte("key_3"), RefTxid: tx1.Txid, RefOffset: 2},
+			{Bucket: "bucket1", Key: []byte("key_4")},
+		},
+		TxOutputsExt: []*protos.TxOutputExt{
+			{Bucket: "bucket1", Key: []byte("key_1"), Value: []byte("v2")},
+			{Bucket: "bucket1", Key: []byte("key_3"), Value: []byte(DelFlag)},
+			{Bucket: "bucket1", Key: []byte("key_4"), Value: []byte("v2")},
+		},
+	}
+	block2 := doBlock(tx2, block1.Blockid)
+
+	testCases := []struct {
+		blockid []byte
+		expect  map[string]string
+	}{
+		{rootBlock.Blockid, map[string]string{}},
+		{block1.Blockid, map[string]string{"key_1": "v1", "key_2": "v1", "key_3": "v1"}},
+		{block2.Blockid, map[string]string{"key_1": "v2", "key_2": "v1", "key_4": "v2"}},
+	}
+	for i, tc := range testCases {
+		xmsp, err := xmod.CreateSnapshot(tc.blockid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iter, err := xmsp.Select("bucket1", []byte("key_0"), []byte("key_9"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := map[string]string{}
+		var lastKey []byte
+		for iter.Next() {
+			if lastKey != nil && string(iter.Key()) <= string(lastKey) {
+				t.Fatalf("case %d: keys out of order, %s after %s", i, iter.Key(), lastKey)
+			}
+			lastKey = iter.Key()
+			result[string(iter.Key())] = string(iter.Value().GetPureData().GetValue())
+		}
+		if iter.Error() != nil {
+			t.Fatal(iter.Error())
+		}
+		iter.Close()
+		if len(result) != len(tc.expect) {
+			t.Fatalf("case %d: expect %v, got %v", i, tc.expect, result)
+		}
+		for k, v := range tc.expect {
+			if result[k] != v {
+				t.Fatalf("case %d: expect %v, got %v", i, tc.expect, result)
+			}
+		}
+	}
+
+	// 区块3更新key_2，区块4、5写入新key，裁剪到区块4后tx1不再被引用
+	tx3 := &pb.Transaction{
+		Desc:         []byte("tx3"),
+		TxInputsExt:  []*protos.TxInputExt{{Bucket: "bucket1", Key: []byte("key_2"), RefTxid: tx1.Txid, RefOffset: 1}},
+		TxOutputsExt: []*protos.TxOutputExt{{Bucket: "bucket1", Key: []byte("key_2"), Value: []byte("v3")}},
+	}
+	preHash := doBlock(tx3, block2.Blockid).Blockid
+	for _, k := range []string{"key_5", "key_6"} {
+		tx := &pb.Transaction{
+			Desc:         []byte(k),
+			TxInputsExt:  []*protos.TxInputExt{{Bucket: "bucket1", Key: []byte(k)}},
+			TxOutputsExt: []*protos.TxOutputExt{{Bucket: "bucket1", Key: []byte(k), Value: []byte("v")}},
+		}
+		preHash = doBlock(tx, preHash).Blockid
+	}
+	defer func(depth int64) { ledger_pkg.MinPruneDepth = depth }(ledger_pkg.MinPruneDepth)
+	ledger_pkg.MinPruneDepth = 1
+	lctx.LedgerCfg.Prune.KeepBlocks = 1
+	if count, err := ledger.Prune(xmod.IsTxReferenced); err != nil || count != 1 {
+		t.Fatalf("prune ledger failed, count:%d err:%v", count, err)
+	}
+	if _, err := xmod.CreateSnapshot(block1.Blockid); !errors.Is(err, ErrSnapshotPruned) {
+		t.Fatalf("snapshot below pruned height expect ErrSnapshotPruned, got %v", err)
+	}
+	xmsp, err := xmod.CreateSnapshot(preHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{"key_1": "v2", "key_2": "v3", "key_6": "v"} {
+		vData, err := xmsp.Get("bucket1", []byte(k))
+		if err != nil || string(vData.GetPureData().GetValue()) != v {
+			t.Fatalf("get %s from snapshot after prune failed, err:%v", k, err)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package tx
+
+import (
+	"container/heap"
+	"math/big"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+const (
+	// 替换冲突交易时，新交易的手续费率至少要比每个被替换交易高出的百分比。
+	minReplaceFeeBump = 10
+	// mempool 满时，只从手续费率最低的这些交易中选择驱逐的交易子树，避免遍历整个 mempool。
+	maxEvictCandidates = 64
+)
+
+// txFee 交易（或一组交易）的手续费和大小，手续费率即每字节手续费。
+type txFee struct {
+	fee  *big.Int
+	size int64
+}
+
+func newTxFee(tx *pb.Transaction) txFee {
+	size := int64(proto.Size(tx))
+	if size <= 0 {
+		size = 1
+	}
+	return txFee{fee: tx.GetFee(), size: size}
+}
+
+func (f txFee) add(o txFee) txFee {
+	return txFee{
+		fee:  new(big.Int).Add(f.fee, o.fee),
+		size: f.size + o.size,
+	}
+}
+
+// cmpRate 比较两个手续费率，bump 为 o 的手续费率需要额外提高的百分比。
+// f 的手续费率小于、等于、大于 o 的手续费率 * (100+bump)% 时分别返回 -1、0、1。
+func (f txFee) cmpRate(o txFee, bump int64) int {
+	left := new(big.Int).Mul(f.fee, big.NewInt(o.size*100))
+	right := new(big.Int).Mul(o.fee, big.NewInt(f.size*(100+bump)))
+	return left.Cmp(right)
+}
+
+// feeItem RangeByFee 中交易的排序信息。
+type feeItem struct {
+	node     *Node
+	index    int   // 交易在 Range 中的遍历顺序。
+	priority txFee // 交易自身和所有依赖于它的交易中最高的手续费率。
+	pending  int   // 还未遍历的依赖交易数量。
+
+	deps       []*feeItem // 需要先于此交易遍历的交易。
+	dependents []*feeItem // 依赖于此交易的交易。
+}
+
+// feeHeap 按照手续费率从高到低排序，手续费率相同时按照 Range 的遍历顺序排序。
+type feeHeap []*feeItem
+
+func (h feeHeap) Len() int { return len(h) }
+
+func (h feeHeap) Less(i, j int) bool {
+	if c := h[i].priority.cmpRate(h[j].priority, 0); c != 0 {
+		return c > 0
+	}
+	return h[i].index < h[j].index
+}
+
+func (h feeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *feeHeap) Push(x interface{}) { *h = append(*h, x.(*feeItem)) }
+
+func (h *feeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// RangeByFee 按照手续费率（每字节手续费）从高到低遍历交易，同时保证交易之间的依赖关系：
+// 父交易先于子交易，同一个 key 的只读交易先于写交易。
+// 子交易的手续费率高于父交易时，父交易按照子交易的手续费率排序（子交易为父交易付费）。
+// 手续费率相同的交易按照 Range 的顺序遍历，所以没有手续费时与 Range 的遍历顺序一致。
+func (m *Mempool) RangeByFee(f func(tx *pb.Transaction) bool) {
+	if f == nil {
+		return
+	}
+
+	m.m.Lock()
+	defer func() {
+		if err := recover(); err != nil {
+			m.log.Error("Mempool RangeByFee panic", "error", err)
+		}
+		m.m.Unlock()
+	}()
+
+	// Range 的遍历顺序满足所有依赖关系，在此基础上按照手续费率调整顺序。
+	txs := make([]*pb.Transaction, 0, len(m.unconfirmed))
+	m.rangeTxs(func(tx *pb.Transaction) bool {
+		txs = append(txs, tx)
+		return true
+	})
+
+	items := make(map[*Node]*feeItem, len(txs))
+	ordered := make([]*feeItem, 0, len(txs))
+	for i, tx := range txs {
+		n, ok := m.unconfirmed[string(tx.GetTxid())]
+		if !ok || items[n] != nil {
+			continue
+		}
+		fee := newTxFee(tx)
+		item := &feeItem{node: n, index: i, priority: fee}
+		items[n] = item
+		ordered = append(ordered, item)
+	}
+
+	for _, item := range ordered {
+		deps := item.node.getAllParent()
+		for r := range item.node.getReadonlyBrotherNodes(nil) {
+			deps = append(deps, r)
+		}
+		seen := make(map[*feeItem]bool, len(deps))
+		for _, d := range deps {
+			di, ok := items[d]
+			// 只保留与 Range 顺序一致的依赖，保证不会出现循环依赖。
+			if !ok || seen[di] || di.index >= item.index {
+				continue
+			}
+			seen[di] = true
+			item.deps = append(item.deps, di)
+			di.dependents = append(di.dependents, item)
+			item.pending++
+		}
+	}
+
+	// 逆序遍历，把依赖交易的手续费率传递给被依赖的交易。
+	for i := len(ordered) - 1; i >= 0; i-- {
+		item := ordered[i]
+		for _, d := range item.deps {
+			if item.priority.cmpRate(d.priority, 0) > 0 {
+				d.priority = item.priority
+			}
+		}
+	}
+
+	h := make(feeHeap, 0, len(ordered))
+	for _, item := range ordered {
+		if item.pending == 0 {
+			h = append(h, item)
+		}
+	}
+	heap.Init(&h)
+	for h.Len() > 0 {
+		item := heap.Pop(&h).(*feeItem)
+		if !f(item.node.tx) {
+			return
+		}
+		for _, d := range item.dependents {
+			d.pending--
+			if d.pending == 0 {
+				heap.Push(&h, d)
+			}
+		}
+	}
+}
+
+// NeedRoomForTx tx 进入 mempool 前是否需要删除交易，即与未确认交易冲突或者 mempool 已满。
+func (m *Mempool) NeedRoomForTx(tx *pb.Transaction) bool {
+	if tx == nil {
+		return false
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+	return len(m.unconfirmed) >= m.txLimit || len(m.findConflictNodes(tx)) > 0
+}
+
+// MakeRoomForTx 为 tx 进入 mempool 删除交易，返回所有被删除的交易，子交易在前，上层需要回滚这些交易。
+// 1.替换（replace-by-fee）：tx 与未确认交易冲突（双花同一个 utxo 或者写同一个 key 的同一个版本）时，
+// tx 的手续费率需要比每个冲突交易至少高 minReplaceFeeBump%，并且手续费高于所有被替换交易（包括子交易）的手续费之和；
+// 2.驱逐：mempool 满时，驱逐子树手续费率（交易及其所有子交易的手续费之和除以大小之和）最低且低于 tx 的交易子树。
+// 不满足条件时不删除任何交易。
+func (m *Mempool) MakeRoomForTx(tx *pb.Transaction) ([]*pb.Transaction, error) {
+	if tx == nil {
+		return nil, nil
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	newFee := newTxFee(tx)
+	removed := make(map[*Node]bool)
+	roots := make([]*Node, 0, 1)
+
+	// 冲突交易的子树。
+	conflicts := m.findConflictNodes(tx)
+	if len(conflicts) > 0 {
+		replacedFee := txFee{fee: big.NewInt(0)}
+		for _, c := range conflicts {
+			if newFee.cmpRate(newTxFee(c.tx), minReplaceFeeBump) < 0 {
+				return nil, ErrFeeTooLow
+			}
+			roots = append(roots, c)
+			m.dfs(c, removed, func(n *Node) {
+				replacedFee = replacedFee.add(newTxFee(n.tx))
+			})
+		}
+		if newFee.fee.Cmp(replacedFee.fee) <= 0 {
+			return nil, ErrFeeTooLow
+		}
+		for _, p := range m.refNodes(tx) {
+			if removed[p] {
+				return nil, ErrReplaceAncestor
+			}
+		}
+	}
+
+	// 手续费率最低的交易子树。
+	need := len(m.unconfirmed) - m.txLimit + 1
+	for n := range removed {
+		if _, ok := m.unconfirmed[n.txid]; ok {
+			need--
+		}
+	}
+	if need > 0 {
+		evictRoots, ok := m.findEvictRoots(tx, newFee, removed, need)
+		if !ok {
+			return nil, ErrMempoolFull
+		}
+		roots = append(roots, evictRoots...)
+	}
+
+	result := make([]*pb.Transaction, 0, len(removed))
+	for _, n := range roots {
+		result = append(result, m.deleteTx(n.txid)...)
+	}
+	if len(result) > 0 {
+		m.log.Debug("Mempool MakeRoomForTx", "txid", tx.HexTxid(), "conflicts", len(conflicts), "removed", len(result))
+	}
+	return result, nil
+}
+
+// findConflictNodes 找到与 tx 双花同一个 utxo 或者写同一个 key 的同一个版本的未确认交易。
+func (m *Mempool) findConflictNodes(tx *pb.Transaction) []*Node {
+	txid := string(tx.GetTxid())
+	result := make([]*Node, 0, 1)
+	seen := make(map[*Node]bool)
+	add := func(n *Node) {
+		if n == nil || n.tx == nil || n.txid == txid || seen[n] {
+			return
+		}
+		if _, ok := m.unconfirmed[n.txid]; !ok {
+			return
+		}
+		seen[n] = true
+		result = append(result, n)
+	}
+
+	for _, input := range tx.GetTxInputs() {
+		p := m.getNode(string(input.GetRefTxid()))
+		offset := int(input.GetRefOffset())
+		if p != nil && offset >= 0 && offset < len(p.txOutputs) {
+			add(p.txOutputs[offset])
+		}
+	}
+
+	node := NewNode(txid, tx)
+	for i, input := range tx.GetTxInputsExt() {
+		if node.isReadonlyKey(i) {
+			continue
+		}
+		if len(input.GetRefTxid()) == 0 {
+			if m.emptyTxIDNode != nil {
+				add(m.emptyTxIDNode.bucketKeyToNode[input.GetBucket()+string(input.GetKey())])
+			}
+			continue
+		}
+		p := m.getNode(string(input.GetRefTxid()))
+		offset := int(input.GetRefOffset())
+		if p != nil && offset >= 0 && offset < len(p.txOutputsExt) {
+			add(p.txOutputsExt[offset])
+		}
+	}
+	return result
+}
+
+// refNodes tx 引用的所有未确认交易。
+func (m *Mempool) refNodes(tx *pb.Transaction) []*Node {
+	result := make([]*Node, 0, len(tx.GetTxInputs())+len(tx.GetTxInputsExt()))
+	for _, input := range tx.GetTxInputs() {
+		if n, ok := m.unconfirmed[string(input.GetRefTxid())]; ok {
+			result = append(result, n)
+		}
+	}
+	for _, input := range tx.GetTxInputsExt() {
+		if n, ok := m.unconfirmed[string(input.GetRefTxid())]; ok {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// evictCandidate 可以驱逐的交易子树。
+type evictCandidate struct {
+	root  *Node
+	nodes map[*Node]bool
+	fee   txFee
+	count int // 子树中未确认交易的数量。
+}
+
+// findEvictRoots 从手续费率最低的 maxEvictCandidates 个交易中，按照子树手续费率从低到高选择子树，
+// 直到可以腾出 need 个位置。子树手续费率需要低于 newFee，且不能包含 tx 依赖的交易和已经删除的交易。
+// 选中的子树会加入 removed。
+func (m *Mempool) findEvictRoots(tx *pb.Transaction, newFee txFee, removed map[*Node]bool, need int) ([]*Node, bool) {
+	type nodeFee struct {
+		node *Node
+		fee  txFee
+	}
+	nodes := make([]nodeFee, 0, len(m.unconfirmed))
+	for _, n := range m.unconfirmed {
+		if removed[n] || n.tx == nil {
+			continue
+		}
+		fee := newTxFee(n.tx)
+		if fee.cmpRate(newFee, 0) >= 0 {
+			continue
+		}
+		nodes = append(nodes, nodeFee{node: n, fee: fee})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if c := nodes[i].fee.cmpRate(nodes[j].fee, 0); c != 0 {
+			return c < 0
+		}
+		return nodes[i].node.txid < nodes[j].node.txid
+	})
+	if len(nodes) > maxEvictCandidates {
+		nodes = nodes[:maxEvictCandidates]
+	}
+
+	refs := m.refNodes(tx)
+	candidates := make([]*evictCandidate, 0, len(nodes))
+	for _, nf := range nodes {
+		c := &evictCandidate{
+			root:  nf.node,
+			nodes: make(map[*Node]bool),
+			fee:   txFee{fee: big.NewInt(0)},
+		}
+		m.dfs(nf.node, c.nodes, func(n *Node) {
+			c.fee = c.fee.add(newTxFee(n.tx))
+			if _, ok := m.unconfirmed[n.txid]; ok {
+				c.count++
+			}
+		})
+		if c.fee.cmpRate(newFee, 0) >= 0 {
+			continue
+		}
+		dependent := false
+		for _, r := range refs {
+			if c.nodes[r] {
+				dependent = true
+				break
+			}
+		}
+		if !dependent {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].fee.cmpRate(candidates[j].fee, 0) < 0
+	})
+
+	roots := make([]*Node, 0, 1)
+	chosen := make(map[*Node]bool)
+	for _, c := range candidates {
+		if need <= 0 {
+			break
+		}
+		overlap := false
+		for n := range c.nodes {
+			if chosen[n] || removed[n] {
+				overlap = true
+				break
+			}
+		}
+		if overlap {
+			continue
+		}
+		for n := range c.nodes {
+			chosen[n] = true
+		}
+		roots = append(roots, c.root)
+		need -= c.count
+	}
+	if need > 0 {
+		return nil, false
+	}
+	for n := range chosen {
+		removed[n] = true
+	}
+	return roots, true
+}
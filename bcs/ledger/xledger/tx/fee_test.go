@@ -0,0 +1,201 @@
+package tx
+
+import (
+	"math/big"
+	"testing"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func newMempoolForFeeTest(t *testing.T, txLimit int) *Mempool {
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+	l, _ := logs.NewLogger("1111", "test")
+	isTest = true
+	dbTxs["feeRoot"] = &pb.Transaction{
+		Txid: []byte("feeRoot"),
+		TxOutputs: []*protos.TxOutput{
+			{Amount: []byte("1")},
+			{Amount: []byte("1")},
+			{Amount: []byte("1")},
+			{Amount: []byte("1")},
+		},
+	}
+	return NewMempool(nil, l, txLimit)
+}
+
+// newFeeTxForTest 构造花费 refTxid 第 offset 个输出的交易，第一个输出可以被子交易花费。
+func newFeeTxForTest(id, refTxid string, offset int32, fee int64) *pb.Transaction {
+	tx := &pb.Transaction{
+		Txid: []byte(id),
+		TxInputs: []*protos.TxInput{
+			{RefTxid: []byte(refTxid), RefOffset: offset},
+		},
+		TxOutputs: []*protos.TxOutput{
+			{ToAddr: []byte("a"), Amount: []byte("1")},
+		},
+	}
+	if fee > 0 {
+		tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{
+			ToAddr: []byte(pb.FeePlaceholder),
+			Amount: big.NewInt(fee).Bytes(),
+		})
+	}
+	return tx
+}
+
+func rangeByFeeIDs(m *Mempool) []string {
+	ids := make([]string, 0)
+	m.RangeByFee(func(tx *pb.Transaction) bool {
+		ids = append(ids, string(tx.Txid))
+		return true
+	})
+	return ids
+}
+
+func TestRangeByFee(t *testing.T) {
+	m := newMempoolForFeeTest(t, 0)
+	txs := []*pb.Transaction{
+		newFeeTxForTest("feeA", "feeRoot", 0, 0),
+		newFeeTxForTest("feeB", "feeA", 0, 10000), // 子交易为父交易付费
+		newFeeTxForTest("feeC", "feeRoot", 1, 1000),
+		newFeeTxForTest("feeD", "feeRoot", 2, 0),
+	}
+	for _, tx := range txs {
+		if err := m.PutTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := rangeByFeeIDs(m)
+	want := []string{"feeA", "feeB", "feeC", "feeD"}
+	if len(got) != len(want) {
+		t.Fatalf("RangeByFee got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("RangeByFee got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRangeByFeeWithoutFee(t *testing.T) {
+	m := newMempoolForFeeTest(t, 0)
+	txs := []*pb.Transaction{
+		newFeeTxForTest("feeA", "feeRoot", 0, 0),
+		newFeeTxForTest("feeB", "feeA", 0, 0),
+		newFeeTxForTest("feeC", "feeRoot", 1, 0),
+		newFeeTxForTest("feeD", "feeB", 0, 0),
+	}
+	for _, tx := range txs {
+		if err := m.PutTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := make([]string, 0)
+	m.Range(func(tx *pb.Transaction) bool {
+		want = append(want, string(tx.Txid))
+		return true
+	})
+	got := rangeByFeeIDs(m)
+	if len(got) != len(txs) || len(got) != len(want) {
+		t.Fatalf("RangeByFee got %v, Range got %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("RangeByFee got %v, Range got %v", got, want)
+		}
+	}
+}
+
+func TestMakeRoomForTxReplace(t *testing.T) {
+	m := newMempoolForFeeTest(t, 0)
+	if err := m.PutTx(newFeeTxForTest("feeA", "feeRoot", 0, 100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PutTx(newFeeTxForTest("feeB", "feeA", 0, 100)); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.NeedRoomForTx(newFeeTxForTest("feeC", "feeRoot", 1, 100)) {
+		t.Fatal("tx without conflicts should not need room")
+	}
+
+	// 手续费不高于被替换交易及其子交易的手续费之和。
+	low := newFeeTxForTest("feeE", "feeRoot", 0, 150)
+	if !m.NeedRoomForTx(low) {
+		t.Fatal("conflicting tx should need room")
+	}
+	if _, err := m.MakeRoomForTx(low); err != ErrFeeTooLow {
+		t.Fatalf("MakeRoomForTx expect ErrFeeTooLow, got %v", err)
+	}
+	if !m.HasTx("feeA") || !m.HasTx("feeB") {
+		t.Fatal("txs should not be removed when replace failed")
+	}
+
+	high := newFeeTxForTest("feeF", "feeRoot", 0, 1000)
+	removed, err := m.MakeRoomForTx(high)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 || string(removed[0].Txid) != "feeB" || string(removed[1].Txid) != "feeA" {
+		t.Fatalf("MakeRoomForTx removed unexpected txs: %v", removed)
+	}
+	if m.HasTx("feeA") || m.HasTx("feeB") {
+		t.Fatal("replaced txs should be removed from mempool")
+	}
+	if err := m.PutTx(high); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不能替换自己依赖的交易。
+	child := newFeeTxForTest("feeG", "feeF", 0, 100000)
+	child.TxInputs = append(child.TxInputs, &protos.TxInput{RefTxid: []byte("feeRoot"), RefOffset: 0})
+	if _, err := m.MakeRoomForTx(child); err != ErrReplaceAncestor {
+		t.Fatalf("MakeRoomForTx expect ErrReplaceAncestor, got %v", err)
+	}
+}
+
+func TestMakeRoomForTxEvict(t *testing.T) {
+	m := newMempoolForFeeTest(t, 3)
+	txs := []*pb.Transaction{
+		newFeeTxForTest("feeA", "feeRoot", 0, 10),
+		newFeeTxForTest("feeB", "feeA", 0, 20),
+		newFeeTxForTest("feeC", "feeRoot", 1, 5000),
+	}
+	for _, tx := range txs {
+		if err := m.PutTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !m.Full() || !m.NeedRoomForTx(newFeeTxForTest("feeD", "feeRoot", 2, 0)) {
+		t.Fatal("mempool should be full")
+	}
+
+	// 没有手续费率更低的交易可以驱逐。
+	if _, err := m.MakeRoomForTx(newFeeTxForTest("feeD", "feeRoot", 2, 0)); err != ErrMempoolFull {
+		t.Fatalf("MakeRoomForTx expect ErrMempoolFull, got %v", err)
+	}
+
+	tx := newFeeTxForTest("feeD", "feeRoot", 2, 1000)
+	removed, err := m.MakeRoomForTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 || string(removed[0].Txid) != "feeB" || string(removed[1].Txid) != "feeA" {
+		t.Fatalf("MakeRoomForTx evicted unexpected txs: %v", removed)
+	}
+	if err := m.PutTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if !m.HasTx("feeC") || !m.HasTx("feeD") {
+		t.Fatal("high fee txs should stay in mempool")
+	}
+}
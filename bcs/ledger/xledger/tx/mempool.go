@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gammazero/deque"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/lib/logs"
 )
@@ -24,6 +25,12 @@ const (
 var (
 	// ErrTxExist tx already in mempool when put tx.
 	ErrTxExist = errors.New("tx already in mempool")
+	// ErrMempoolFull mempool is full and no tx can be evicted.
+	ErrMempoolFull = errors.New("The tx mempool is full")
+	// ErrFeeTooLow tx fee too low to replace conflicting txs in mempool.
+	ErrFeeTooLow = errors.New("tx fee too low to replace conflicting txs in mempool")
+	// ErrReplaceAncestor tx conflicts with its own ancestors in mempool.
+	ErrReplaceAncestor = errors.New("tx can not replace its own ancestors in mempool")
 )
 
 // Mempool tx mempool.
@@ -117,6 +124,11 @@ func (m *Mempool) Range(f func(tx *pb.Transaction) bool) {
 		m.m.Unlock()
 	}()
 
+	m.rangeTxs(f)
+}
+
+// rangeTxs 按照拓扑排序遍历节点交易，调用方需持有锁。
+func (m *Mempool) rangeTxs(f func(tx *pb.Transaction) bool) {
 	m.log.Debug("Mempool Range", "confirmed", len(m.confirmed), "unconfirmed", len(m.unconfirmed), "orphans", len(m.orphans), "bucketKeyNodes", len(m.bucketKeyNodes))
 	var q deque.Deque
 	nodeInputSumMap := make(map[*Node]int, len(m.confirmed))
@@ -318,7 +330,7 @@ func (m *Mempool) PutTx(tx *pb.Transaction) error {
 	defer m.m.Unlock()
 
 	if len(m.unconfirmed) >= m.txLimit {
-		return errors.New("The tx mempool is full")
+		return ErrMempoolFull
 	}
 
 	m.log.Debug("Mempool PutTx", "txid", tx.HexTxid())
@@ -1176,14 +1188,13 @@ func (m *Mempool) processTxInputs(node *Node, retrieve bool) (bool, error) {
 			}
 
 		} else {
-			if dbTx, _ := m.queryTxFromDB(id); dbTx != nil {
-				n := NewNode(string(dbTx.Txid), dbTx)
+			if n := m.queryConfirmedNode(id); n != nil {
 				if forDeleteNode, err := node.updateInput(i, int(input.RefOffset), n, retrieve); err != nil {
 					return false, err
 				} else if forDeleteNode != nil {
 					m.deleteTx(forDeleteNode.txid)
 				}
-				m.confirmed[string(dbTx.Txid)] = n
+				m.confirmed[id] = n
 
 			} else {
 				// 孤儿交易
@@ -1268,8 +1279,7 @@ func (m *Mempool) processTxInputsExt(node *Node, retrieve bool) (bool, error) {
 			}
 
 		} else {
-			if dbTx, _ := m.queryTxFromDB(id); dbTx != nil {
-				n := NewNode(string(dbTx.GetTxid()), dbTx)
+			if n := m.queryConfirmedNode(id); n != nil {
 				offset := int(input.RefOffset)
 				if forDeleteNode, err := node.updateInputExt(index, offset, n, retrieve); err != nil {
 					return isOrphan, err
@@ -1304,10 +1314,26 @@ func (m *Mempool) queryTxFromDB(txid string) (*pb.Transaction, error) {
 	if !isTest { // 单测使用。
 		return m.Tx.ledger.QueryTransaction([]byte(txid))
 	}
-	tx, _ := dbTxs[txid]
+	tx, ok := dbTxs[txid]
+	if ok && tx == nil {
+		return nil, ledger.ErrTxPruned
+	}
 	return tx, nil
 }
 
+// queryConfirmedNode 查询已确认交易并构造节点，交易详情已被裁剪时构造不含交易内容的节点，
+// 避免引用已裁剪交易的交易被当作孤儿交易
+func (m *Mempool) queryConfirmedNode(txid string) *Node {
+	dbTx, err := m.queryTxFromDB(txid)
+	if dbTx != nil {
+		return NewNode(string(dbTx.GetTxid()), dbTx)
+	}
+	if err == ledger.ErrTxPruned {
+		return NewNode(txid, nil)
+	}
+	return nil
+}
+
 // 在 ConfirmTx 时，如果当前交易不在 mempool 中，那么删除掉所有与此交易有冲突的交易。
 func (m *Mempool) processConflict(tx *pb.Transaction) error {
 	for _, input := range tx.GetTxInputs() {
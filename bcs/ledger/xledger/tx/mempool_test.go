@@ -54,6 +54,34 @@ func TestPutOrphanTx(t *testing.T) {
 	// printMempool(m)
 }
 
+func TestPutTxWithPrunedParent(t *testing.T) {
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+	l, _ := logs.NewLogger("1111", "test")
+	isTest = true
+	dbTxs["prunedTest"] = nil // 交易详情已被裁剪
+	defer delete(dbTxs, "prunedTest")
+	m := NewMempool(nil, l, 0)
+	input := []*protos.TxInput{{RefTxid: []byte("prunedTest"), RefOffset: 1}}
+	output := []*protos.TxOutput{{Amount: []byte("1")}}
+	inputsExt := []*protos.TxInputExt{{Bucket: "b", Key: []byte("k"), RefTxid: []byte("prunedTest"), RefOffset: 2}}
+	outputsExt := []*protos.TxOutputExt{{Bucket: "b", Key: []byte("k"), Value: []byte("v")}}
+	tx := NewTxForTest([]byte("prunedChild"), input, output, inputsExt, outputsExt)
+	if err := m.PutTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	// 引用已裁剪交易的交易不是孤儿交易。
+	if len(m.orphans) != 0 || len(m.unconfirmed) != 1 {
+		t.Fatal("tx referencing pruned tx should be unconfirmed")
+	}
+	if _, ok := m.confirmed["prunedTest"]; !ok {
+		t.Fatal("pruned tx should be a confirmed node")
+	}
+}
+
 func NewTxForTest(txid []byte, txInputs []*protos.TxInput, txOutput []*protos.TxOutput,
 	txInputsExt []*protos.TxInputExt, txOutputsExt []*protos.TxOutputExt) *pb.Transaction {
 	return &pb.Transaction{
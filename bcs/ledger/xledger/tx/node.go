@@ -231,7 +231,7 @@ func (n *Node) updateInputExt(index, offset int, node *Node, retrieve bool) (*No
 			indexa := offset + 1 - len(node.txOutputsExt)
 			if indexa > 0 {
 				if !readonly {
-					node.txOutputsExt = append(node.txOutputsExt, make([]*Node, index)...)
+					node.txOutputsExt = append(node.txOutputsExt, make([]*Node, indexa)...)
 				} else {
 
 				}
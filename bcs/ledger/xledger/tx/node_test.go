@@ -51,6 +51,30 @@ func TestNewNode(t *testing.T) {
 	}
 }
 
+func TestUpdateInputExtMockNode(t *testing.T) {
+	// 写key的交易引用mock节点上偏移较大的输出，mock节点需要补齐到offset+1个输出
+	testTx := &pb.Transaction{
+		TxInputsExt: []*protos.TxInputExt{
+			{Bucket: "bucket", Key: []byte("key"), RefOffset: 3},
+		},
+		TxOutputsExt: []*protos.TxOutputExt{
+			{Bucket: "bucket", Key: []byte("key"), Value: []byte("value")},
+		},
+	}
+	n := NewNode("test", testTx)
+	mock := NewNode("", nil)
+
+	if _, err := n.updateInputExt(0, 3, mock, false); err != nil {
+		t.Fatal("update input ext error", err)
+	}
+	if len(mock.txOutputsExt) != 4 || mock.txOutputsExt[3] != n {
+		t.Error("mock node outputs error", len(mock.txOutputsExt))
+	}
+	if n.txInputsExt[0] != mock {
+		t.Error("node inputs error")
+	}
+}
+
 func TestGetAllChildren(t *testing.T) {
 
 }
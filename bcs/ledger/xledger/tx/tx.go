@@ -15,6 +15,7 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/storage/kvdb"
 	"github.com/xuperchain/xupercore/lib/utils"
@@ -49,6 +50,7 @@ type Tx struct {
 // RootJSON xuper.json对应的struct，目前先只写了utxovm关注的字段
 type RootJSON struct {
 	Version   string `json:"version"`
+	Hash      string `json:"hash"`
 	Consensus struct {
 		Miner string `json:"miner"`
 	} `json:"consensus"`
@@ -74,6 +76,11 @@ func NewTx(sctx *context.StateCtx, stateDB kvdb.Database) (*Tx, error) {
 
 // 生成奖励TX
 func GenerateAwardTx(address, awardAmount string, desc []byte) (*pb.Transaction, error) {
+	return GenerateAwardTxWithHasher(address, awardAmount, desc, hash.DefaultHasher)
+}
+
+// 使用链配置的哈希算法生成奖励TX
+func GenerateAwardTxWithHasher(address, awardAmount string, desc []byte, hasher *hash.Hasher) (*pb.Transaction, error) {
 	utxoTx := &pb.Transaction{Version: TxVersion}
 	amount := big.NewInt(0)
 	amount.SetString(awardAmount, 10) // 10进制转换大整数
@@ -87,16 +94,21 @@ func GenerateAwardTx(address, awardAmount string, desc []byte) (*pb.Transaction,
 	utxoTx.Desc = desc
 	utxoTx.Coinbase = true
 	utxoTx.Timestamp = time.Now().UnixNano()
-	utxoTx.Txid, _ = txhash.MakeTransactionID(utxoTx)
+	utxoTx.Txid, _ = txhash.MakeTransactionIDWithHasher(utxoTx, hasher)
 	return utxoTx, nil
 }
 
 // 生成只有Desc的空交易
 func GenerateEmptyTx(desc []byte) (*pb.Transaction, error) {
+	return GenerateEmptyTxWithHasher(desc, hash.DefaultHasher)
+}
+
+// 使用链配置的哈希算法生成只有Desc的空交易
+func GenerateEmptyTxWithHasher(desc []byte, hasher *hash.Hasher) (*pb.Transaction, error) {
 	utxoTx := &pb.Transaction{Version: TxVersion}
 	utxoTx.Desc = desc
 	utxoTx.Timestamp = time.Now().UnixNano()
-	txid, err := txhash.MakeTransactionID(utxoTx)
+	txid, err := txhash.MakeTransactionIDWithHasher(utxoTx, hasher)
 	utxoTx.Txid = txid
 	utxoTx.Autogen = true
 	return utxoTx, err
@@ -104,6 +116,12 @@ func GenerateEmptyTx(desc []byte) (*pb.Transaction, error) {
 
 // 生成只有读写集的空交易
 func GenerateAutoTxWithRWSets(inputs []*protos.TxInputExt, outputs []*protos.TxOutputExt) (*pb.Transaction, error) {
+	return GenerateAutoTxWithRWSetsAndHasher(inputs, outputs, hash.DefaultHasher)
+}
+
+// 使用链配置的哈希算法生成只有读写集的空交易
+func GenerateAutoTxWithRWSetsAndHasher(inputs []*protos.TxInputExt, outputs []*protos.TxOutputExt,
+	hasher *hash.Hasher) (*pb.Transaction, error) {
 
 	tx := &pb.Transaction{
 		Coinbase:     false,
@@ -115,20 +133,24 @@ func GenerateAutoTxWithRWSets(inputs []*protos.TxInputExt, outputs []*protos.TxO
 		TxOutputsExt: outputs,
 	}
 
-	txid, err := txhash.MakeTransactionID(tx)
+	txid, err := txhash.MakeTransactionIDWithHasher(tx, hasher)
 
 	tx.Txid = txid
 
 	return tx, err
 }
 
-// 通过创世块配置生成创世区块交易
+// 通过创世块配置生成创世区块交易，交易id使用创世块配置的哈希算法
 func GenerateRootTx(js []byte) (*pb.Transaction, error) {
 	jsObj := &RootJSON{}
 	jsErr := json.Unmarshal(js, jsObj)
 	if jsErr != nil {
 		return nil, jsErr
 	}
+	hasher, err := hash.GetHasher(jsObj.Hash)
+	if err != nil {
+		return nil, err
+	}
 	utxoTx := &pb.Transaction{Version: RootTxVersion}
 	for _, pd := range jsObj.Predistribution {
 		amount := big.NewInt(0)
@@ -143,7 +165,7 @@ func GenerateRootTx(js []byte) (*pb.Transaction, error) {
 	}
 	utxoTx.Desc = js
 	utxoTx.Coinbase = true
-	utxoTx.Txid, _ = txhash.MakeTransactionID(utxoTx)
+	utxoTx.Txid, _ = txhash.MakeTransactionIDWithHasher(utxoTx, hasher)
 	return utxoTx, nil
 }
 
@@ -190,6 +212,7 @@ func (t *Tx) QueryTx(txid []byte) (*pb.Transaction, error) {
 }
 
 // GetUnconfirmedTx 挖掘一批unconfirmed的交易打包，返回的结果要保证是按照交易执行的先后顺序
+// 在满足交易依赖关系的前提下，优先打包手续费率（每字节手续费）高的交易
 // maxSize: 打包交易最大的长度（in byte）, -1（小于0） 表示不限制
 func (t *Tx) GetUnconfirmedTx(dedup bool, sizeLimit int) ([]*pb.Transaction, error) {
 	result := make([]*pb.Transaction, 0, 100)
@@ -211,7 +234,7 @@ func (t *Tx) GetUnconfirmedTx(dedup bool, sizeLimit int) ([]*pb.Transaction, err
 		return true
 	}
 
-	t.Mempool.Range(f)
+	t.Mempool.RangeByFee(f)
 	t.UnconfirmTxAmount = int64(t.Mempool.GetTxCounnt())
 	if len(result) > 0 {
 		t.log.Debug("Tx GetUnconfirmedTx", "UnconfirmTxCount", t.UnconfirmTxAmount, "packTxs", len(result))
@@ -62,6 +62,10 @@ func TestTx(t *testing.T) {
 	if rtxErr != nil {
 		t.Fatal(rtxErr)
 	}
+	_, rtxErr = GenerateRootTx([]byte(`{"version": "1", "hash": "md5"}`))
+	if rtxErr == nil {
+		t.Fatal("generate root tx with unsupported hash type should fail")
+	}
 
 	workspace, dirErr := ioutil.TempDir("/tmp", "")
 	if dirErr != nil {
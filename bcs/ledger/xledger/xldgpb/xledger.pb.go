@@ -231,6 +231,55 @@ func (m *SignInfo) GetSign() []byte {
 	return nil
 }
 
+// QCAggregateSign is the BLS aggregate signature with a bitmap of signers,
+// bit i is set when the i-th validator signed.
+type QCAggregateSign struct {
+	Bitmap               []byte   `protobuf:"bytes,1,opt,name=Bitmap,proto3" json:"Bitmap,omitempty"`
+	Sign                 []byte   `protobuf:"bytes,2,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QCAggregateSign) Reset()         { *m = QCAggregateSign{} }
+func (m *QCAggregateSign) String() string { return proto.CompactTextString(m) }
+func (*QCAggregateSign) ProtoMessage()    {}
+func (*QCAggregateSign) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b639a3762518476d, []int{2}
+}
+
+func (m *QCAggregateSign) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QCAggregateSign.Unmarshal(m, b)
+}
+func (m *QCAggregateSign) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QCAggregateSign.Marshal(b, m, deterministic)
+}
+func (m *QCAggregateSign) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QCAggregateSign.Merge(m, src)
+}
+func (m *QCAggregateSign) XXX_Size() int {
+	return xxx_messageInfo_QCAggregateSign.Size(m)
+}
+func (m *QCAggregateSign) XXX_DiscardUnknown() {
+	xxx_messageInfo_QCAggregateSign.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QCAggregateSign proto.InternalMessageInfo
+
+func (m *QCAggregateSign) GetBitmap() []byte {
+	if m != nil {
+		return m.Bitmap
+	}
+	return nil
+}
+
+func (m *QCAggregateSign) GetSign() []byte {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
 // QuorumCert is a data type that combines a collection of signatures from replicas.
 type QuorumCert struct {
 	// The id of Proposal this QC certified.
@@ -244,17 +293,20 @@ type QuorumCert struct {
 	ViewNumber int64 `protobuf:"varint,4,opt,name=ViewNumber,proto3" json:"ViewNumber,omitempty"`
 	// SignInfos is the signs of the leader gathered from replicas
 	// of a specifically certType.
-	SignInfos            *QCSignInfos `protobuf:"bytes,5,opt,name=SignInfos,proto3" json:"SignInfos,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	SignInfos *QCSignInfos `protobuf:"bytes,5,opt,name=SignInfos,proto3" json:"SignInfos,omitempty"`
+	// AggregateSign is the BLS aggregate signature of replicas,
+	// used instead of SignInfos when BLS is enabled.
+	AggregateSign        *QCAggregateSign `protobuf:"bytes,6,opt,name=AggregateSign,proto3" json:"AggregateSign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *QuorumCert) Reset()         { *m = QuorumCert{} }
 func (m *QuorumCert) String() string { return proto.CompactTextString(m) }
 func (*QuorumCert) ProtoMessage()    {}
 func (*QuorumCert) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{2}
+	return fileDescriptor_b639a3762518476d, []int{3}
 }
 
 func (m *QuorumCert) XXX_Unmarshal(b []byte) error {
@@ -310,6 +362,13 @@ func (m *QuorumCert) GetSignInfos() *QCSignInfos {
 	return nil
 }
 
+func (m *QuorumCert) GetAggregateSign() *QCAggregateSign {
+	if m != nil {
+		return m.AggregateSign
+	}
+	return nil
+}
+
 type HDInfo struct {
 	// HDPublickey
 	HdPublicKey []byte `protobuf:"bytes,1,opt,name=hd_public_key,json=hdPublicKey,proto3" json:"hd_public_key,omitempty"`
@@ -324,7 +383,7 @@ func (m *HDInfo) Reset()         { *m = HDInfo{} }
 func (m *HDInfo) String() string { return proto.CompactTextString(m) }
 func (*HDInfo) ProtoMessage()    {}
 func (*HDInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{3}
+	return fileDescriptor_b639a3762518476d, []int{4}
 }
 
 func (m *HDInfo) XXX_Unmarshal(b []byte) error {
@@ -372,7 +431,7 @@ func (m *XuperSignature) Reset()         { *m = XuperSignature{} }
 func (m *XuperSignature) String() string { return proto.CompactTextString(m) }
 func (*XuperSignature) ProtoMessage()    {}
 func (*XuperSignature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{4}
+	return fileDescriptor_b639a3762518476d, []int{5}
 }
 
 func (m *XuperSignature) XXX_Unmarshal(b []byte) error {
@@ -458,7 +517,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{5}
+	return fileDescriptor_b639a3762518476d, []int{6}
 }
 
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
@@ -643,7 +702,7 @@ func (m *LedgerMeta) Reset()         { *m = LedgerMeta{} }
 func (m *LedgerMeta) String() string { return proto.CompactTextString(m) }
 func (*LedgerMeta) ProtoMessage()    {}
 func (*LedgerMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{6}
+	return fileDescriptor_b639a3762518476d, []int{7}
 }
 
 func (m *LedgerMeta) XXX_Unmarshal(b []byte) error {
@@ -720,7 +779,7 @@ func (m *UtxoMeta) Reset()         { *m = UtxoMeta{} }
 func (m *UtxoMeta) String() string { return proto.CompactTextString(m) }
 func (*UtxoMeta) ProtoMessage()    {}
 func (*UtxoMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{7}
+	return fileDescriptor_b639a3762518476d, []int{8}
 }
 
 func (m *UtxoMeta) XXX_Unmarshal(b []byte) error {
@@ -881,7 +940,7 @@ func (m *InternalBlock) Reset()         { *m = InternalBlock{} }
 func (m *InternalBlock) String() string { return proto.CompactTextString(m) }
 func (*InternalBlock) ProtoMessage()    {}
 func (*InternalBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{8}
+	return fileDescriptor_b639a3762518476d, []int{9}
 }
 
 func (m *InternalBlock) XXX_Unmarshal(b []byte) error {
@@ -1057,7 +1116,7 @@ func (m *Utxo) Reset()         { *m = Utxo{} }
 func (m *Utxo) String() string { return proto.CompactTextString(m) }
 func (*Utxo) ProtoMessage()    {}
 func (*Utxo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{9}
+	return fileDescriptor_b639a3762518476d, []int{10}
 }
 
 func (m *Utxo) XXX_Unmarshal(b []byte) error {
@@ -1133,7 +1192,7 @@ func (m *ModifyBlock) Reset()         { *m = ModifyBlock{} }
 func (m *ModifyBlock) String() string { return proto.CompactTextString(m) }
 func (*ModifyBlock) ProtoMessage()    {}
 func (*ModifyBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{10}
+	return fileDescriptor_b639a3762518476d, []int{11}
 }
 
 func (m *ModifyBlock) XXX_Unmarshal(b []byte) error {
@@ -1205,7 +1264,7 @@ func (m *TxDataAccount) Reset()         { *m = TxDataAccount{} }
 func (m *TxDataAccount) String() string { return proto.CompactTextString(m) }
 func (*TxDataAccount) ProtoMessage()    {}
 func (*TxDataAccount) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{11}
+	return fileDescriptor_b639a3762518476d, []int{12}
 }
 
 func (m *TxDataAccount) XXX_Unmarshal(b []byte) error {
@@ -1260,7 +1319,7 @@ func (m *UtxoRecord) Reset()         { *m = UtxoRecord{} }
 func (m *UtxoRecord) String() string { return proto.CompactTextString(m) }
 func (*UtxoRecord) ProtoMessage()    {}
 func (*UtxoRecord) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{12}
+	return fileDescriptor_b639a3762518476d, []int{13}
 }
 
 func (m *UtxoRecord) XXX_Unmarshal(b []byte) error {
@@ -1315,7 +1374,7 @@ func (m *UtxoKey) Reset()         { *m = UtxoKey{} }
 func (m *UtxoKey) String() string { return proto.CompactTextString(m) }
 func (*UtxoKey) ProtoMessage()    {}
 func (*UtxoKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{13}
+	return fileDescriptor_b639a3762518476d, []int{14}
 }
 
 func (m *UtxoKey) XXX_Unmarshal(b []byte) error {
@@ -1370,7 +1429,7 @@ func (m *UtxoRecordDetail) Reset()         { *m = UtxoRecordDetail{} }
 func (m *UtxoRecordDetail) String() string { return proto.CompactTextString(m) }
 func (*UtxoRecordDetail) ProtoMessage()    {}
 func (*UtxoRecordDetail) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{14}
+	return fileDescriptor_b639a3762518476d, []int{15}
 }
 
 func (m *UtxoRecordDetail) XXX_Unmarshal(b []byte) error {
@@ -1424,7 +1483,7 @@ func (m *BalanceDetailInfo) Reset()         { *m = BalanceDetailInfo{} }
 func (m *BalanceDetailInfo) String() string { return proto.CompactTextString(m) }
 func (*BalanceDetailInfo) ProtoMessage()    {}
 func (*BalanceDetailInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{15}
+	return fileDescriptor_b639a3762518476d, []int{16}
 }
 
 func (m *BalanceDetailInfo) XXX_Unmarshal(b []byte) error {
@@ -1474,7 +1533,7 @@ func (m *UtxoOutput) Reset()         { *m = UtxoOutput{} }
 func (m *UtxoOutput) String() string { return proto.CompactTextString(m) }
 func (*UtxoOutput) ProtoMessage()    {}
 func (*UtxoOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b639a3762518476d, []int{16}
+	return fileDescriptor_b639a3762518476d, []int{17}
 }
 
 func (m *UtxoOutput) XXX_Unmarshal(b []byte) error {
@@ -1515,6 +1574,7 @@ func init() {
 	proto.RegisterEnum("xldgpb.QCState", QCState_name, QCState_value)
 	proto.RegisterType((*QCSignInfos)(nil), "xldgpb.QCSignInfos")
 	proto.RegisterType((*SignInfo)(nil), "xldgpb.SignInfo")
+	proto.RegisterType((*QCAggregateSign)(nil), "xldgpb.QCAggregateSign")
 	proto.RegisterType((*QuorumCert)(nil), "xldgpb.QuorumCert")
 	proto.RegisterType((*HDInfo)(nil), "xldgpb.HDInfo")
 	proto.RegisterType((*XuperSignature)(nil), "xldgpb.XuperSignature")
@@ -1538,129 +1598,132 @@ func init() {
 }
 
 var fileDescriptor_b639a3762518476d = []byte{
-	// 1983 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x58, 0xeb, 0x52, 0x1b, 0xc9,
-	0x15, 0xb6, 0x10, 0x20, 0xe9, 0xe8, 0x82, 0x68, 0x3b, 0xde, 0x59, 0xbc, 0xb6, 0xb1, 0xec, 0xd4,
-	0x12, 0x97, 0x03, 0x15, 0xa7, 0xb2, 0xd9, 0xcd, 0xad, 0x0a, 0x24, 0xb1, 0x28, 0x80, 0xc0, 0xcd,
-	0x60, 0x53, 0xa9, 0x54, 0x4d, 0x8d, 0x46, 0x2d, 0xa9, 0x0b, 0x69, 0x5a, 0xe9, 0xee, 0x81, 0xc1,
-	0x8f, 0x90, 0x47, 0xc8, 0x23, 0xe4, 0x41, 0xf2, 0x2f, 0xaf, 0x93, 0xdf, 0xa9, 0xbe, 0x8d, 0x46,
-	0xb8, 0xf0, 0x2f, 0xf5, 0xf9, 0xfa, 0x9c, 0xd3, 0x7d, 0xee, 0x3d, 0x82, 0xef, 0x07, 0x91, 0xd8,
-	0x9b, 0x92, 0xe1, 0x98, 0xf0, 0xbd, 0x34, 0xfb, 0x1d, 0x8e, 0xe7, 0x03, 0x47, 0xee, 0xce, 0x39,
-	0x93, 0x0c, 0xad, 0x1b, 0x74, 0xeb, 0x65, 0x9a, 0xcc, 0x09, 0x8f, 0x18, 0x27, 0x7b, 0x7a, 0x43,
-	0xec, 0x45, 0x2c, 0x96, 0x3c, 0x8c, 0xa4, 0x61, 0xdc, 0x7a, 0xfe, 0x05, 0x43, 0x5e, 0xcf, 0xd6,
-	0xab, 0x2f, 0xb6, 0xe7, 0x84, 0xcf, 0xa8, 0x10, 0x94, 0xc5, 0x86, 0xa5, 0xb5, 0x0f, 0xd5, 0x0f,
-	0xed, 0x0b, 0x3a, 0x8e, 0x7b, 0xf1, 0x88, 0x09, 0xf4, 0x7e, 0x89, 0xf4, 0x0a, 0xdb, 0xc5, 0x9d,
-	0xea, 0xfb, 0xe6, 0xae, 0xb9, 0xcf, 0xae, 0xdb, 0xc0, 0x79, 0xa6, 0xd6, 0x47, 0x28, 0x3b, 0x02,
-	0x79, 0x50, 0xda, 0x1f, 0x0e, 0x39, 0x11, 0x4a, 0xb6, 0xb0, 0x53, 0xc1, 0x8e, 0x44, 0xdf, 0x41,
-	0xe5, 0x3c, 0x19, 0x4c, 0x69, 0x74, 0x4c, 0xee, 0xbc, 0x15, 0xbd, 0xb7, 0x00, 0x10, 0x82, 0x55,
-	0xa5, 0xc3, 0x2b, 0x6e, 0x17, 0x76, 0x6a, 0x58, 0xaf, 0x5b, 0xff, 0x29, 0x00, 0x7c, 0x48, 0x18,
-	0x4f, 0x66, 0x6d, 0xc2, 0x25, 0x7a, 0x01, 0x70, 0xce, 0xd9, 0x9c, 0x89, 0x70, 0xda, 0x1b, 0x6a,
-	0xed, 0x35, 0x9c, 0x43, 0xd0, 0x36, 0x54, 0x1d, 0x75, 0x2a, 0xc6, 0xfa, 0x88, 0x1a, 0xce, 0x43,
-	0xe8, 0x35, 0xac, 0xfa, 0x77, 0x73, 0xa2, 0x0f, 0x69, 0xbc, 0xdf, 0x70, 0x56, 0x7d, 0x68, 0x5f,
-	0xc8, 0x50, 0x12, 0xac, 0x37, 0xd5, 0x31, 0x1f, 0x29, 0xb9, 0xed, 0x27, 0xb3, 0x01, 0xe1, 0xde,
-	0xea, 0x76, 0x61, 0xa7, 0x88, 0x73, 0x08, 0xfa, 0x0d, 0x54, 0x16, 0xfe, 0x59, 0xdb, 0x2e, 0xec,
-	0x54, 0xdf, 0x3f, 0xce, 0x69, 0x72, 0x5b, 0x78, 0xc1, 0xd5, 0xfa, 0x00, 0xeb, 0x47, 0x1d, 0xed,
-	0x9e, 0x16, 0xd4, 0x27, 0xc3, 0x60, 0xae, 0xcd, 0x0e, 0xae, 0xc9, 0x9d, 0x35, 0xa3, 0x3a, 0x19,
-	0x2e, 0x5c, 0xf1, 0x1a, 0xea, 0x8c, 0xd3, 0x31, 0x8d, 0xc3, 0x69, 0x30, 0x09, 0xc5, 0xc4, 0x5a,
-	0x52, 0x73, 0xe0, 0x51, 0x28, 0x26, 0xad, 0x33, 0x68, 0x5c, 0xa9, 0xd8, 0xaa, 0x43, 0x42, 0x99,
-	0x70, 0x82, 0x5e, 0x42, 0x75, 0xa1, 0xd7, 0x44, 0xae, 0x86, 0x61, 0xee, 0xd4, 0xea, 0x00, 0x08,
-	0xc7, 0x6d, 0x75, 0x2e, 0x80, 0xd6, 0xff, 0xd6, 0xa1, 0xea, 0xf3, 0x30, 0x16, 0x61, 0x24, 0x29,
-	0x8b, 0x55, 0x40, 0x64, 0x4a, 0x9d, 0x9f, 0xf5, 0x5a, 0x05, 0x77, 0x30, 0x65, 0xd1, 0x35, 0x1d,
-	0x5a, 0x79, 0x47, 0xa2, 0x77, 0x50, 0x91, 0x69, 0x40, 0xe3, 0x79, 0x22, 0x85, 0x57, 0xd4, 0x49,
-	0xb3, 0x61, 0x12, 0x4c, 0xec, 0xfa, 0x69, 0x4f, 0xe1, 0xb8, 0x2c, 0xcd, 0x42, 0xa0, 0x3d, 0x00,
-	0x99, 0x06, 0x2c, 0x91, 0x9a, 0x7d, 0xd5, 0xe6, 0x58, 0xc6, 0x7e, 0xa6, 0x37, 0x70, 0x45, 0xda,
-	0x95, 0x50, 0x97, 0x19, 0x12, 0x11, 0x79, 0xeb, 0xe6, 0x32, 0x6a, 0x8d, 0xb6, 0xa0, 0x1c, 0x31,
-	0x1a, 0x0f, 0x42, 0x41, 0xbc, 0xd2, 0x76, 0x61, 0xa7, 0x8c, 0x33, 0x1a, 0x3d, 0x81, 0xb5, 0x98,
-	0xc5, 0x11, 0xf1, 0xca, 0x3a, 0xcf, 0x0c, 0xa1, 0x1c, 0x20, 0xe9, 0x8c, 0x08, 0x19, 0xce, 0xe6,
-	0x5e, 0x45, 0x07, 0x76, 0x01, 0x28, 0xe3, 0x6e, 0x08, 0x57, 0x95, 0xe1, 0xc1, 0x76, 0x61, 0x67,
-	0x0d, 0x3b, 0x52, 0xed, 0x84, 0x89, 0x64, 0x63, 0x12, 0x7b, 0x55, 0x7d, 0x90, 0x23, 0xd1, 0x0f,
-	0x50, 0xcf, 0xcc, 0x0e, 0x48, 0x2a, 0xbd, 0x6f, 0xb4, 0x2d, 0xe8, 0x9e, 0xe9, 0xdd, 0x54, 0xe2,
-	0xaa, 0xb3, 0xbe, 0x9b, 0x4a, 0xf4, 0x13, 0x34, 0x16, 0x0e, 0xd0, 0x82, 0x9e, 0x16, 0x7c, 0x7c,
-	0xdf, 0x09, 0x4a, 0xb2, 0x96, 0xf9, 0x41, 0x89, 0x1e, 0xc0, 0xa6, 0xeb, 0x01, 0x01, 0x27, 0xff,
-	0x48, 0x88, 0x90, 0xc2, 0xfb, 0x56, 0x4b, 0xff, 0xc2, 0x49, 0xf7, 0xe2, 0x1b, 0x76, 0x4d, 0xb0,
-	0xd9, 0xc5, 0x4d, 0xc7, 0x6f, 0x01, 0x9d, 0x09, 0x34, 0xa6, 0x92, 0x86, 0x92, 0x71, 0x6f, 0xcb,
-	0x94, 0x62, 0x06, 0xa0, 0x57, 0x50, 0x0b, 0x13, 0x39, 0xd1, 0xda, 0x29, 0x27, 0xde, 0xb3, 0xed,
-	0xe2, 0x4e, 0x05, 0x57, 0x15, 0x86, 0x0d, 0x84, 0xfe, 0x02, 0x1b, 0x19, 0x7f, 0xa0, 0x72, 0x48,
-	0x78, 0xdf, 0x2d, 0x5f, 0x21, 0xcb, 0x4b, 0xdd, 0x2e, 0x1a, 0x19, 0xb7, 0xc2, 0x05, 0x6a, 0x03,
-	0xca, 0x1f, 0x61, 0x55, 0x3c, 0xff, 0x9a, 0x8a, 0x66, 0xee, 0x7c, 0xa3, 0xe4, 0xd7, 0x80, 0x38,
-	0x89, 0x08, 0xbd, 0x21, 0xc3, 0x60, 0x11, 0xd7, 0x17, 0x3a, 0xae, 0x9b, 0x6e, 0xc7, 0xcf, 0xe2,
-	0xfb, 0x3b, 0x00, 0xdd, 0x0d, 0xf5, 0x61, 0xde, 0x4b, 0x5d, 0xb8, 0x4f, 0x5d, 0xe1, 0x2e, 0xd7,
-	0x12, 0xae, 0xa4, 0x8e, 0x46, 0x3f, 0x40, 0x6d, 0xc6, 0x86, 0x74, 0x74, 0x17, 0xe8, 0x5c, 0xf7,
-	0xb6, 0x97, 0x2b, 0xfe, 0x54, 0xef, 0x1d, 0xa8, 0x2d, 0x5c, 0x9d, 0x2d, 0x08, 0xf4, 0x3d, 0x94,
-	0x8e, 0x3a, 0x01, 0x8d, 0x47, 0xcc, 0x7b, 0xa5, 0x45, 0x1a, 0x4e, 0xc4, 0xb4, 0x02, 0x6c, 0x5b,
-	0x42, 0x4b, 0x00, 0x9c, 0xe8, 0x9e, 0x7d, 0x4a, 0x64, 0xa8, 0x9c, 0xcf, 0x19, 0x93, 0x81, 0xab,
-	0x33, 0xdb, 0x1f, 0x14, 0x76, 0x60, 0x6b, 0xed, 0x25, 0x54, 0x25, 0x9d, 0x07, 0xcb, 0x95, 0x08,
-	0x92, 0xce, 0x1d, 0xc3, 0x2b, 0xa8, 0x49, 0x9e, 0xc4, 0xd7, 0xc1, 0x84, 0xd0, 0xf1, 0x44, 0xea,
-	0x76, 0x57, 0xc4, 0x55, 0x8d, 0x1d, 0x69, 0xa8, 0xf5, 0xaf, 0x35, 0x28, 0x5f, 0xca, 0x94, 0xe9,
-	0x33, 0x7f, 0x09, 0x8d, 0x69, 0x28, 0x89, 0xb8, 0x7f, 0x6a, 0xdd, 0xa0, 0x4e, 0x6d, 0x0b, 0xea,
-	0x6a, 0xa5, 0xda, 0x4b, 0x30, 0xa5, 0x42, 0x7a, 0x2b, 0x26, 0x31, 0x14, 0x78, 0x4c, 0xee, 0x4e,
-	0xa8, 0x90, 0xe8, 0x39, 0x40, 0x22, 0x53, 0x16, 0x48, 0x26, 0xc3, 0xa9, 0x3e, 0xb8, 0x82, 0x2b,
-	0x0a, 0xf1, 0x15, 0xa0, 0x6a, 0x36, 0xbc, 0x19, 0x77, 0xc8, 0x34, 0xbc, 0xb3, 0x9d, 0x35, 0xa3,
-	0xd1, 0x3b, 0xd8, 0x4c, 0xe2, 0x88, 0xc5, 0x23, 0xca, 0x67, 0x7e, 0xba, 0x3f, 0x63, 0x49, 0x2c,
-	0x75, 0x7f, 0x2d, 0xe2, 0x2f, 0x37, 0xd0, 0x1b, 0x68, 0xcc, 0xc2, 0xd4, 0x5c, 0x38, 0x10, 0xf4,
-	0x33, 0xd1, 0xbd, 0xa1, 0x88, 0x6b, 0xb3, 0x30, 0xd5, 0x17, 0xbe, 0xa0, 0x9f, 0x09, 0xea, 0xa8,
-	0x14, 0x11, 0x84, 0xab, 0x14, 0x71, 0x55, 0x20, 0xbc, 0xd2, 0xd7, 0xaa, 0x65, 0xd3, 0x09, 0xb4,
-	0x1d, 0xbf, 0xd2, 0x32, 0x62, 0x7c, 0x40, 0x87, 0x43, 0x12, 0x67, 0x6a, 0x74, 0x6b, 0x79, 0x58,
-	0x4b, 0x26, 0xe0, 0xd4, 0xa0, 0x3f, 0xc3, 0xb3, 0x98, 0xdc, 0x06, 0x61, 0x14, 0x29, 0x03, 0x02,
-	0x4e, 0x04, 0x4b, 0x78, 0x44, 0x82, 0xd0, 0x58, 0x6a, 0xfa, 0x91, 0x17, 0x93, 0xdb, 0x7d, 0xc3,
-	0x81, 0x2d, 0x83, 0x35, 0xf8, 0x47, 0xf8, 0x86, 0x72, 0x4e, 0x74, 0x4f, 0x1a, 0x4c, 0x89, 0xb6,
-	0xd1, 0x04, 0x53, 0xb7, 0xab, 0x22, 0x7e, 0x68, 0xfb, 0xbe, 0xe4, 0xc5, 0x94, 0x0e, 0xc9, 0x27,
-	0x1a, 0x0f, 0xd9, 0xad, 0x6e, 0x67, 0xf7, 0x24, 0x73, 0xdb, 0xe8, 0x1d, 0x94, 0xc7, 0xa1, 0x38,
-	0xe7, 0x34, 0x22, 0x5e, 0x4d, 0x9b, 0x9b, 0x75, 0xe9, 0x9f, 0x2d, 0x8e, 0x33, 0x0e, 0xf4, 0x33,
-	0x3c, 0x19, 0x73, 0x96, 0xcc, 0x83, 0x68, 0x12, 0xd2, 0x9c, 0xa3, 0xea, 0x5f, 0x73, 0x14, 0xd2,
-	0x22, 0x6d, 0x25, 0xe1, 0x3c, 0xd5, 0xfa, 0xef, 0x1a, 0xd4, 0x7b, 0xb1, 0x24, 0x3c, 0x0e, 0xa7,
-	0xa6, 0x98, 0x72, 0xbd, 0xb9, 0xb0, 0xdc, 0x9b, 0xb3, 0x4e, 0xbf, 0xa2, 0x71, 0xdb, 0xe9, 0x73,
-	0x83, 0xaa, 0xb8, 0x3c, 0xa8, 0xbe, 0x85, 0xf2, 0x9c, 0x13, 0x33, 0x57, 0x57, 0xcd, 0xd6, 0x9c,
-	0x13, 0x35, 0x52, 0x55, 0x72, 0xce, 0xf5, 0x63, 0x81, 0x70, 0x9d, 0x77, 0x35, 0x9c, 0xd1, 0x6a,
-	0x00, 0xe9, 0xb6, 0x61, 0x07, 0x90, 0x5a, 0xa3, 0xa7, 0xb0, 0x3e, 0x4f, 0x06, 0x6a, 0x88, 0x97,
-	0x34, 0x6a, 0x29, 0x55, 0x9f, 0x33, 0xc2, 0xaf, 0xa7, 0x24, 0x50, 0x55, 0xab, 0xf3, 0xa4, 0x86,
-	0xc1, 0x40, 0x98, 0x31, 0xa9, 0x04, 0x6d, 0x65, 0x9a, 0xa0, 0x5b, 0x6a, 0x79, 0x3e, 0xc1, 0xfd,
-	0xf9, 0xf4, 0x7b, 0x55, 0xd5, 0xd9, 0x7c, 0x16, 0x5e, 0xd5, 0x4e, 0x0c, 0xdb, 0x55, 0x72, 0xb3,
-	0x1b, 0x2f, 0x31, 0x2a, 0x93, 0x65, 0x1a, 0xe8, 0x9c, 0xd2, 0x51, 0x5c, 0xc3, 0x25, 0x99, 0xb6,
-	0x75, 0x52, 0x2d, 0xae, 0x2a, 0x39, 0x21, 0x5e, 0xdd, 0xbc, 0x19, 0x0c, 0xe4, 0x73, 0xa2, 0x1d,
-	0x19, 0x25, 0xdc, 0x27, 0x7c, 0xe6, 0x35, 0xf5, 0x85, 0x1c, 0xa9, 0x5e, 0x5b, 0x51, 0xc2, 0x75,
-	0x78, 0xfa, 0xc9, 0xcc, 0xdb, 0x34, 0x3d, 0x26, 0x07, 0xa1, 0x36, 0xc0, 0x28, 0xa4, 0x53, 0xd5,
-	0x9d, 0x53, 0xe1, 0x21, 0x7d, 0xdd, 0x37, 0xee, 0xba, 0x4b, 0xf1, 0xdd, 0x3d, 0xd4, 0x7c, 0x7e,
-	0x2a, 0xba, 0xb1, 0xe4, 0x77, 0xb8, 0x32, 0x72, 0xb4, 0x7a, 0x8d, 0xc9, 0x90, 0x8f, 0x89, 0x3c,
-	0xa0, 0x52, 0x78, 0x8f, 0xf5, 0xf5, 0x73, 0x08, 0x7a, 0x07, 0xa5, 0xbf, 0x26, 0x42, 0xd2, 0xd1,
-	0x9d, 0xf7, 0x44, 0xe7, 0x19, 0xca, 0xde, 0x62, 0xd9, 0xcb, 0x11, 0x3b, 0x16, 0xe5, 0x0a, 0x1a,
-	0x07, 0xba, 0x11, 0x7a, 0x0d, 0x33, 0xca, 0x69, 0xec, 0x2b, 0x12, 0x3d, 0x83, 0x4a, 0x4c, 0x52,
-	0x69, 0x32, 0x63, 0xc3, 0x84, 0x5f, 0x01, 0x2a, 0x35, 0xb6, 0xfe, 0x04, 0x8d, 0xe5, 0x2b, 0xa2,
-	0x26, 0x14, 0xdd, 0xf3, 0xad, 0x82, 0xd5, 0x52, 0x65, 0xe2, 0x4d, 0x38, 0x4d, 0x88, 0x7d, 0xdb,
-	0x1a, 0xe2, 0x0f, 0x2b, 0x3f, 0x16, 0x5a, 0xff, 0x2c, 0xc0, 0xaa, 0x6a, 0xb6, 0x2a, 0xf0, 0xb6,
-	0xda, 0x4d, 0x83, 0xb5, 0x94, 0xc2, 0x25, 0x53, 0xef, 0x64, 0xdb, 0xcc, 0x2d, 0xa5, 0x32, 0x52,
-	0xb2, 0x73, 0x93, 0x63, 0x26, 0x8f, 0x33, 0x5a, 0x45, 0x86, 0x93, 0x91, 0xaf, 0x9e, 0x68, 0x36,
-	0x8f, 0x2d, 0xa9, 0xd2, 0x88, 0x93, 0xd1, 0xd9, 0x68, 0x24, 0x88, 0x69, 0xa0, 0x6b, 0x78, 0x01,
-	0xb4, 0xfe, 0x5d, 0x80, 0x6a, 0x6e, 0x68, 0xa9, 0xe6, 0x4f, 0x46, 0x23, 0x12, 0x49, 0x7a, 0x43,
-	0x82, 0xec, 0xc5, 0x57, 0xc1, 0xf5, 0x0c, 0xd5, 0x4a, 0x9f, 0xc2, 0xfa, 0x2c, 0xe4, 0xd7, 0xc4,
-	0xcc, 0x9b, 0x32, 0xb6, 0x14, 0xfa, 0x15, 0x34, 0x17, 0xe2, 0x4b, 0xf3, 0x66, 0x23, 0xc3, 0x6d,
-	0x1f, 0x7a, 0x0e, 0x90, 0x7b, 0xf8, 0xae, 0x9a, 0xd9, 0x30, 0xcf, 0x7f, 0x01, 0xe8, 0x12, 0x5b,
-	0xd3, 0x1b, 0x7a, 0xdd, 0x1a, 0x41, 0xdd, 0x4f, 0x3b, 0xa1, 0x0c, 0x6d, 0x4f, 0xd4, 0x4f, 0xb1,
-	0xe5, 0xcf, 0x0b, 0x4b, 0xe6, 0x7c, 0x6b, 0xfc, 0xef, 0x7c, 0xfb, 0x1a, 0xea, 0x23, 0xce, 0x3e,
-	0x93, 0x78, 0xf9, 0x76, 0x35, 0x03, 0xda, 0x71, 0xc8, 0x00, 0x54, 0x80, 0x30, 0x89, 0x18, 0xd7,
-	0x0e, 0x54, 0x23, 0xab, 0x9d, 0x45, 0xca, 0xce, 0x30, 0x53, 0x33, 0x2f, 0xcc, 0x88, 0xdb, 0xcf,
-	0x1f, 0x96, 0x43, 0xd4, 0x47, 0x06, 0x95, 0x64, 0x96, 0xbd, 0x82, 0x6d, 0x3a, 0x2a, 0xfd, 0xc7,
-	0xe4, 0x0e, 0xeb, 0xcd, 0xd6, 0x05, 0x94, 0x2c, 0x90, 0x0f, 0xa4, 0x35, 0xc9, 0x05, 0xf2, 0x29,
-	0xac, 0x33, 0x13, 0x45, 0x6b, 0x92, 0xa1, 0x72, 0xa6, 0x16, 0xf3, 0xa6, 0xaa, 0xd0, 0x36, 0x17,
-	0x66, 0x74, 0x88, 0x0c, 0xe9, 0x14, 0xed, 0x42, 0x99, 0xcd, 0x49, 0xac, 0x70, 0xad, 0x3f, 0x57,
-	0x21, 0x0b, 0x5e, 0x9c, 0xf1, 0xa0, 0xf7, 0x00, 0x2a, 0x2f, 0xc8, 0x50, 0x4b, 0xac, 0x3c, 0x28,
-	0x91, 0xe3, 0x52, 0x32, 0xc6, 0x9d, 0x5a, 0xa6, 0xf8, 0xb0, 0xcc, 0x82, 0xab, 0xd5, 0x83, 0xcd,
-	0x83, 0x70, 0x1a, 0xc6, 0x11, 0x31, 0x17, 0x75, 0x5f, 0x8f, 0x03, 0x03, 0x3a, 0x5f, 0x58, 0x52,
-	0x95, 0x02, 0x15, 0x87, 0x5a, 0xdc, 0x66, 0x60, 0x46, 0xb7, 0xfe, 0x6e, 0xa2, 0x67, 0x1e, 0xc9,
-	0x68, 0x07, 0xca, 0x2a, 0x1a, 0xea, 0x39, 0x62, 0x3f, 0x5f, 0x6b, 0x4b, 0x57, 0xc9, 0x76, 0xd1,
-	0x1b, 0xa8, 0xeb, 0x77, 0xca, 0x05, 0x99, 0x92, 0x48, 0xda, 0xd4, 0xae, 0xe0, 0x65, 0xf0, 0xed,
-	0x2d, 0x6c, 0xe6, 0x7a, 0xab, 0xfa, 0x52, 0x4c, 0x04, 0xda, 0x80, 0xaa, 0x7f, 0x15, 0x5c, 0xf6,
-	0x3b, 0xdd, 0xc3, 0x5e, 0xbf, 0xdb, 0x7c, 0x84, 0x1a, 0x00, 0xfe, 0x55, 0xd0, 0x3f, 0xeb, 0x5e,
-	0xf5, 0x2e, 0xfc, 0x66, 0xc1, 0xd2, 0xed, 0xb3, 0xfe, 0x61, 0x0f, 0x9f, 0x36, 0x57, 0x50, 0x13,
-	0x6a, 0xfe, 0x55, 0x70, 0x78, 0x89, 0xdb, 0xfb, 0x7e, 0xef, 0xac, 0xdf, 0x2c, 0x5a, 0xe4, 0xb2,
-	0xef, 0x78, 0x56, 0x51, 0x1d, 0x2a, 0x8a, 0x67, 0xbf, 0x77, 0xd2, 0xed, 0x34, 0xd7, 0xde, 0xfa,
-	0x50, 0x35, 0x2f, 0x99, 0xec, 0xc8, 0x83, 0x93, 0xb3, 0xf6, 0x71, 0xd0, 0xc5, 0xf8, 0x0c, 0x37,
-	0x1f, 0x2d, 0x00, 0x1f, 0x5f, 0xf6, 0x8f, 0x9b, 0x05, 0xa5, 0xd1, 0x00, 0x07, 0x78, 0xbf, 0xdf,
-	0x3e, 0x6a, 0xae, 0xa0, 0x4d, 0xa8, 0x1b, 0xc4, 0x5d, 0xac, 0xf8, 0xf6, 0x04, 0x4a, 0xf6, 0x7b,
-	0x17, 0xd5, 0xa0, 0xdc, 0xef, 0x7e, 0x0a, 0x3e, 0xf6, 0xba, 0x9f, 0x9a, 0x8f, 0x50, 0x15, 0x4a,
-	0xe7, 0xb8, 0x7b, 0xbe, 0x8f, 0xbb, 0xe6, 0xfa, 0xe7, 0xb8, 0x1b, 0xb4, 0xcf, 0x4e, 0x4f, 0x7b,
-	0x7e, 0x73, 0x05, 0x01, 0xac, 0xdb, 0x75, 0x51, 0xad, 0x3b, 0xdd, 0x76, 0xaf, 0xd3, 0x6d, 0xae,
-	0x1e, 0xfc, 0xf1, 0x6f, 0x3f, 0x8d, 0xa9, 0x9c, 0x24, 0x83, 0xdd, 0x88, 0xcd, 0xf6, 0xcc, 0xbf,
-	0x0d, 0x6a, 0x96, 0xef, 0x2d, 0xfe, 0x78, 0x78, 0xf0, 0x3f, 0x8f, 0xc1, 0xba, 0x7e, 0x11, 0xfc,
-	0xf6, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x36, 0xac, 0x5e, 0x88, 0x17, 0x11, 0x00, 0x00,
+	// 2029 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x58, 0xdd, 0x6e, 0x1b, 0xb9,
+	0xf5, 0x8f, 0x2c, 0xdb, 0x92, 0x8e, 0x3e, 0x2c, 0x33, 0xf9, 0x27, 0xb3, 0xce, 0x26, 0x71, 0x94,
+	0xfc, 0xb1, 0x6e, 0x90, 0xda, 0x68, 0x8a, 0x6e, 0x77, 0xdb, 0xa6, 0x80, 0x2c, 0x29, 0x1b, 0x35,
+	0xb1, 0xec, 0xd0, 0x93, 0x0f, 0x14, 0x05, 0x06, 0xa3, 0x11, 0x25, 0x11, 0x96, 0x86, 0x53, 0x92,
+	0xe3, 0x8c, 0x73, 0xdf, 0x9b, 0x3e, 0x42, 0x1f, 0xa1, 0xef, 0xd2, 0xd7, 0xe9, 0x75, 0xc1, 0xaf,
+	0xd1, 0xc8, 0x81, 0x73, 0x25, 0x9e, 0x1f, 0xcf, 0x39, 0x24, 0xcf, 0xf7, 0x08, 0x7e, 0x18, 0x47,
+	0xe2, 0x68, 0x41, 0x26, 0x33, 0xc2, 0x8f, 0xb2, 0xfc, 0x77, 0x32, 0x4b, 0xc6, 0x8e, 0x3c, 0x4c,
+	0x38, 0x93, 0x0c, 0x6d, 0x1b, 0x74, 0xef, 0x51, 0x96, 0x26, 0x84, 0x47, 0x8c, 0x93, 0x23, 0xbd,
+	0x21, 0x8e, 0x22, 0x16, 0x4b, 0x1e, 0x46, 0xd2, 0x30, 0xee, 0x3d, 0xf8, 0x8a, 0xa1, 0xa8, 0x67,
+	0xef, 0xf1, 0x57, 0xdb, 0x09, 0xe1, 0x4b, 0x2a, 0x04, 0x65, 0xb1, 0x61, 0xe9, 0x74, 0xa1, 0xfe,
+	0xae, 0x77, 0x4e, 0x67, 0xf1, 0x30, 0x9e, 0x32, 0x81, 0x5e, 0xac, 0x91, 0x5e, 0x69, 0xbf, 0x7c,
+	0x50, 0x7f, 0xd1, 0x3e, 0x34, 0xf7, 0x39, 0x74, 0x1b, 0xb8, 0xc8, 0xd4, 0xf9, 0x00, 0x55, 0x47,
+	0x20, 0x0f, 0x2a, 0xdd, 0xc9, 0x84, 0x13, 0xa1, 0x64, 0x4b, 0x07, 0x35, 0xec, 0x48, 0xf4, 0x3d,
+	0xd4, 0xce, 0xd2, 0xf1, 0x82, 0x46, 0x6f, 0xc8, 0x95, 0xb7, 0xa1, 0xf7, 0x56, 0x00, 0x42, 0xb0,
+	0xa9, 0x74, 0x78, 0xe5, 0xfd, 0xd2, 0x41, 0x03, 0xeb, 0x75, 0xe7, 0x25, 0xec, 0xbc, 0xeb, 0x75,
+	0x67, 0x33, 0x4e, 0x66, 0xa1, 0x24, 0x0a, 0x42, 0x77, 0x61, 0xfb, 0x98, 0xca, 0x65, 0x98, 0x68,
+	0xed, 0x0d, 0x6c, 0xa9, 0x5c, 0x7c, 0xa3, 0x20, 0xfe, 0x8f, 0x0d, 0x80, 0x77, 0x29, 0xe3, 0xe9,
+	0xb2, 0x47, 0xb8, 0x44, 0x0f, 0x01, 0xce, 0x38, 0x4b, 0x98, 0x08, 0x17, 0xc3, 0x89, 0x15, 0x2f,
+	0x20, 0x68, 0x1f, 0xea, 0x8e, 0x3a, 0x11, 0x33, 0xab, 0xa9, 0x08, 0xa1, 0x27, 0xb0, 0xe9, 0x5f,
+	0x25, 0x44, 0xdf, 0xb1, 0xf5, 0x62, 0xc7, 0x19, 0xe5, 0x5d, 0xef, 0x5c, 0x86, 0x92, 0x60, 0xbd,
+	0xa9, 0x8e, 0xf9, 0x40, 0xc9, 0xe7, 0x51, 0xba, 0x1c, 0x13, 0xee, 0x6d, 0xee, 0x97, 0x0e, 0xca,
+	0xb8, 0x80, 0xa0, 0xdf, 0x40, 0x6d, 0x65, 0xde, 0xad, 0xfd, 0xd2, 0x41, 0xfd, 0xc5, 0xed, 0x82,
+	0x26, 0xb7, 0x85, 0x57, 0x5c, 0xe8, 0x25, 0x34, 0xd7, 0xac, 0xe0, 0x6d, 0x6b, 0xb1, 0x7b, 0x2b,
+	0xb1, 0xb5, 0x6d, 0xbc, 0xce, 0xdd, 0x79, 0x07, 0xdb, 0xaf, 0xfb, 0xda, 0x39, 0x1d, 0x68, 0xce,
+	0x27, 0x41, 0xa2, 0x8d, 0x1e, 0x5c, 0x90, 0x2b, 0x6b, 0x85, 0xfa, 0x7c, 0xb2, 0x72, 0xc4, 0x13,
+	0x68, 0x32, 0x4e, 0x67, 0x34, 0x0e, 0x17, 0xc1, 0x3c, 0x14, 0x73, 0x6b, 0x88, 0x86, 0x03, 0x5f,
+	0x87, 0x62, 0xde, 0x39, 0x85, 0xd6, 0x27, 0x15, 0x59, 0x4a, 0x7f, 0x28, 0x53, 0x4e, 0xd0, 0x23,
+	0xa8, 0xaf, 0xf4, 0x9a, 0xb8, 0x69, 0x60, 0x48, 0x9c, 0x5a, 0xed, 0x7e, 0xe1, 0xb8, 0xad, 0xce,
+	0x15, 0xd0, 0xf9, 0xef, 0x36, 0xd4, 0x7d, 0x1e, 0xc6, 0x22, 0x8c, 0x24, 0x65, 0xb1, 0xf2, 0xa7,
+	0xcc, 0xa8, 0x73, 0x93, 0x5e, 0xab, 0xd0, 0x1a, 0x2f, 0x58, 0x74, 0x41, 0x27, 0x56, 0xde, 0x91,
+	0xe8, 0x39, 0xd4, 0x64, 0x16, 0xd0, 0x38, 0x49, 0xa5, 0xf0, 0xca, 0x3a, 0x64, 0x77, 0x4c, 0x78,
+	0x8b, 0x43, 0x3f, 0x1b, 0x2a, 0x1c, 0x57, 0xa5, 0x59, 0x08, 0x74, 0x04, 0x20, 0xb3, 0x80, 0xa5,
+	0x52, 0xb3, 0x6f, 0xda, 0x08, 0xcf, 0xd9, 0x4f, 0xf5, 0x06, 0xae, 0x49, 0xbb, 0x12, 0xea, 0x32,
+	0x13, 0x22, 0x22, 0x6d, 0xf6, 0x06, 0xd6, 0x6b, 0xb4, 0x07, 0xd5, 0x88, 0xd1, 0x78, 0x1c, 0x0a,
+	0xe2, 0x55, 0xf6, 0x4b, 0x07, 0x55, 0x9c, 0xd3, 0xe8, 0x0e, 0x6c, 0xc5, 0x2c, 0x8e, 0x88, 0x57,
+	0xd5, 0x51, 0x6e, 0x08, 0x65, 0x00, 0x49, 0x97, 0x44, 0xc8, 0x70, 0x99, 0x78, 0x35, 0x1d, 0x17,
+	0x2b, 0x40, 0x3d, 0xee, 0x92, 0x70, 0x95, 0x97, 0x1e, 0xec, 0x97, 0x0e, 0xb6, 0xb0, 0x23, 0xd5,
+	0x4e, 0x98, 0x4a, 0x36, 0x23, 0xb1, 0x57, 0xd7, 0x07, 0x39, 0x12, 0xfd, 0x08, 0xcd, 0xfc, 0xd9,
+	0x01, 0xc9, 0xa4, 0x77, 0x4f, 0xbf, 0x05, 0x5d, 0x7b, 0xfa, 0x20, 0x93, 0xb8, 0xee, 0x5e, 0x3f,
+	0xc8, 0x24, 0xfa, 0x19, 0x5a, 0x2b, 0x03, 0x68, 0x41, 0x4f, 0x0b, 0xde, 0xbe, 0x6e, 0x04, 0x25,
+	0xd9, 0xc8, 0xed, 0xa0, 0x44, 0x8f, 0x61, 0xd7, 0x55, 0xa0, 0x80, 0x93, 0xbf, 0xa7, 0x44, 0x48,
+	0xe1, 0x7d, 0xa7, 0xa5, 0xff, 0xcf, 0x49, 0x0f, 0xe3, 0x4b, 0x76, 0x41, 0xb0, 0xd9, 0xc5, 0x6d,
+	0xc7, 0x6f, 0x01, 0x1d, 0x09, 0x34, 0xa6, 0x92, 0x86, 0x92, 0x71, 0x6f, 0xcf, 0x14, 0x82, 0x1c,
+	0x40, 0x8f, 0xa1, 0x11, 0xa6, 0x72, 0xae, 0xb5, 0x53, 0x4e, 0xbc, 0xfb, 0xfb, 0xe5, 0x83, 0x1a,
+	0xae, 0x2b, 0x0c, 0x1b, 0x08, 0xfd, 0x19, 0x76, 0x72, 0xfe, 0x40, 0xc5, 0x90, 0xf0, 0xbe, 0x5f,
+	0xbf, 0x42, 0x1e, 0x97, 0xba, 0x58, 0xb5, 0x72, 0x6e, 0x85, 0x0b, 0xd4, 0x03, 0x54, 0x3c, 0xc2,
+	0xaa, 0x78, 0xf0, 0x2d, 0x15, 0xed, 0xc2, 0xf9, 0x46, 0xc9, 0xaf, 0x01, 0x71, 0x12, 0x11, 0x7a,
+	0x49, 0x26, 0xc1, 0xca, 0xaf, 0x0f, 0xb5, 0x5f, 0x77, 0xdd, 0x8e, 0x9f, 0xfb, 0xf7, 0x77, 0x00,
+	0xba, 0x16, 0xeb, 0xc3, 0xbc, 0x47, 0x3a, 0x81, 0xef, 0xba, 0x04, 0x5e, 0xcf, 0x25, 0x5c, 0xcb,
+	0x1c, 0x8d, 0x7e, 0x84, 0xc6, 0x92, 0x4d, 0xe8, 0xf4, 0x2a, 0xd0, 0xb1, 0xee, 0xed, 0xaf, 0x17,
+	0x8c, 0x13, 0xbd, 0x77, 0xac, 0xb6, 0x70, 0x7d, 0xb9, 0x22, 0xd0, 0x0f, 0x50, 0x79, 0xdd, 0x0f,
+	0x68, 0x3c, 0x65, 0xde, 0x63, 0x2d, 0xd2, 0x72, 0x22, 0xa6, 0x14, 0x60, 0x5b, 0x12, 0x3a, 0x02,
+	0xe0, 0xad, 0xee, 0x18, 0x27, 0x44, 0x86, 0xca, 0xf8, 0x9c, 0x31, 0x19, 0xb8, 0x3c, 0xb3, 0xf5,
+	0x41, 0x61, 0xc7, 0x36, 0xd7, 0x1e, 0x41, 0x5d, 0xd2, 0x24, 0x58, 0xcf, 0x44, 0x90, 0x34, 0x71,
+	0x0c, 0x8f, 0xa1, 0x21, 0x79, 0x1a, 0x5f, 0x04, 0x73, 0x42, 0x67, 0x73, 0xa9, 0xab, 0x65, 0x19,
+	0xd7, 0x35, 0xf6, 0x5a, 0x43, 0x9d, 0x7f, 0x6d, 0x41, 0xf5, 0xbd, 0xcc, 0x98, 0x3e, 0xf3, 0xff,
+	0xa1, 0xb5, 0x08, 0x25, 0x11, 0xd7, 0x4f, 0x6d, 0x1a, 0xd4, 0xa9, 0xed, 0x40, 0x53, 0xad, 0x54,
+	0x79, 0x09, 0x16, 0x54, 0x48, 0x6f, 0xc3, 0x04, 0x86, 0x02, 0xdf, 0x90, 0xab, 0xb7, 0x54, 0x48,
+	0xf4, 0x00, 0x20, 0x95, 0x19, 0x0b, 0x24, 0x93, 0xe1, 0x42, 0x1f, 0x5c, 0xc3, 0x35, 0x85, 0xf8,
+	0x0a, 0x50, 0x39, 0x1b, 0x5e, 0xce, 0xfa, 0x64, 0x11, 0x5e, 0xd9, 0xc2, 0x9c, 0xd3, 0xe8, 0x39,
+	0xec, 0xa6, 0x71, 0xc4, 0xe2, 0x29, 0xe5, 0x4b, 0x3f, 0xeb, 0x2e, 0x59, 0x1a, 0x4b, 0x5d, 0x9e,
+	0xcb, 0xf8, 0xeb, 0x0d, 0xf4, 0x14, 0x5a, 0xcb, 0x30, 0x33, 0x17, 0x0e, 0x04, 0xfd, 0x42, 0x74,
+	0x6d, 0x28, 0xe3, 0xc6, 0x32, 0xcc, 0xf4, 0x85, 0xcf, 0xe9, 0x17, 0x82, 0xfa, 0x2a, 0x44, 0x04,
+	0xe1, 0x2a, 0x44, 0x5c, 0x16, 0x08, 0xaf, 0xf2, 0xad, 0x6c, 0xd9, 0x75, 0x02, 0x3d, 0xc7, 0xaf,
+	0xb4, 0x4c, 0x19, 0x1f, 0xd3, 0xc9, 0x84, 0xc4, 0xb9, 0x1a, 0x5d, 0x5a, 0x6e, 0xd6, 0x92, 0x0b,
+	0x38, 0x35, 0xe8, 0x25, 0xdc, 0x8f, 0xc9, 0xe7, 0x20, 0x8c, 0x22, 0xf5, 0x80, 0x80, 0x13, 0xc1,
+	0x52, 0x1e, 0x91, 0x20, 0x34, 0x2f, 0x35, 0xf5, 0xc8, 0x8b, 0xc9, 0xe7, 0xae, 0xe1, 0xc0, 0x96,
+	0xc1, 0x3e, 0xf8, 0x27, 0xb8, 0x47, 0x39, 0x27, 0xba, 0x26, 0x8d, 0x17, 0x44, 0xbf, 0xd1, 0x38,
+	0x53, 0x97, 0xab, 0x32, 0xbe, 0x69, 0xfb, 0xba, 0xe4, 0xf9, 0x82, 0x4e, 0xc8, 0x47, 0x1a, 0x4f,
+	0xd8, 0x67, 0x5d, 0xce, 0xae, 0x49, 0x16, 0xb6, 0xd1, 0x73, 0xa8, 0xce, 0x42, 0x71, 0xc6, 0x69,
+	0x44, 0xbc, 0x86, 0x7e, 0x6e, 0x5e, 0xa5, 0x7f, 0xb1, 0x38, 0xce, 0x39, 0xd0, 0x2f, 0x70, 0x67,
+	0xc6, 0x59, 0x9a, 0x04, 0xd1, 0x3c, 0xa4, 0x05, 0x43, 0x35, 0xbf, 0x65, 0x28, 0xa4, 0x45, 0x7a,
+	0x4a, 0xc2, 0x59, 0xaa, 0xf3, 0x9f, 0x2d, 0x68, 0x0e, 0x63, 0x49, 0x78, 0x1c, 0x2e, 0x4c, 0x32,
+	0x15, 0x6a, 0x73, 0x69, 0xbd, 0x36, 0xe7, 0x95, 0x7e, 0x43, 0xe3, 0xb6, 0xd2, 0x17, 0x1a, 0x55,
+	0x79, 0xbd, 0x51, 0x7d, 0x07, 0xd5, 0x84, 0x13, 0xd3, 0x57, 0x37, 0xcd, 0x56, 0xc2, 0x89, 0x6a,
+	0xa9, 0x2a, 0x38, 0x13, 0x3d, 0x6b, 0x10, 0xae, 0xe3, 0xae, 0x81, 0x73, 0x5a, 0x35, 0x20, 0xe1,
+	0xfa, 0x7e, 0x03, 0xeb, 0xb5, 0x9a, 0x84, 0x92, 0x74, 0xac, 0x9a, 0x78, 0xc5, 0x4c, 0x42, 0x86,
+	0x52, 0xf9, 0xb9, 0x24, 0xfc, 0x62, 0x41, 0x02, 0x95, 0xb5, 0x3a, 0x4e, 0x1a, 0x18, 0x0c, 0x84,
+	0x19, 0x93, 0x4a, 0xd0, 0x66, 0xa6, 0x71, 0xba, 0xa5, 0xd6, 0xfb, 0x13, 0x5c, 0xef, 0x4f, 0xbf,
+	0x57, 0x59, 0x9d, 0xf7, 0x67, 0xe1, 0xd5, 0x6d, 0xc7, 0xb0, 0x55, 0xa5, 0xd0, 0xbb, 0xf1, 0x1a,
+	0xa3, 0x7a, 0xb2, 0xcc, 0x02, 0x1d, 0x53, 0xda, 0x8b, 0x5b, 0xb8, 0x22, 0xb3, 0x9e, 0x0e, 0xaa,
+	0xd5, 0x55, 0x25, 0x27, 0xc4, 0x6b, 0x9a, 0x99, 0xc1, 0x40, 0x3e, 0x27, 0xda, 0x90, 0x51, 0xca,
+	0x7d, 0xc2, 0x97, 0x5e, 0x5b, 0x5f, 0xc8, 0x91, 0x6a, 0x58, 0x8b, 0x52, 0xae, 0xdd, 0x33, 0x4a,
+	0x97, 0xde, 0xae, 0xa9, 0x31, 0x05, 0x08, 0xf5, 0x00, 0xa6, 0x21, 0x5d, 0xa8, 0xea, 0x9c, 0x09,
+	0x0f, 0xe9, 0xeb, 0x3e, 0x75, 0xd7, 0x5d, 0xf3, 0xef, 0xe1, 0x2b, 0xcd, 0xe7, 0x67, 0x62, 0x10,
+	0x4b, 0x7e, 0x85, 0x6b, 0x53, 0x47, 0xab, 0x61, 0x4e, 0x86, 0x7c, 0x46, 0xe4, 0x31, 0x95, 0xc2,
+	0xbb, 0xad, 0xaf, 0x5f, 0x40, 0xd0, 0x73, 0xa8, 0xfc, 0x25, 0x15, 0x92, 0x4e, 0xaf, 0xbc, 0x3b,
+	0x3a, 0xce, 0x50, 0x3e, 0x93, 0xe5, 0x83, 0x27, 0x76, 0x2c, 0xca, 0x14, 0x34, 0x0e, 0x74, 0x21,
+	0xf4, 0x5a, 0xa6, 0x95, 0xd3, 0xd8, 0x57, 0x24, 0xba, 0x0f, 0xb5, 0x98, 0x64, 0xd2, 0x44, 0xc6,
+	0x8e, 0x71, 0xbf, 0x02, 0x54, 0x68, 0xec, 0xfd, 0x09, 0x5a, 0xeb, 0x57, 0x44, 0x6d, 0x28, 0xbb,
+	0xf1, 0xad, 0x86, 0xd5, 0x52, 0x45, 0xe2, 0x65, 0xb8, 0x48, 0x89, 0x9d, 0xac, 0x0d, 0xf1, 0x87,
+	0x8d, 0x9f, 0x4a, 0x9d, 0x7f, 0x96, 0x60, 0x53, 0x15, 0x5b, 0xe5, 0x78, 0x9b, 0xed, 0x76, 0x76,
+	0x36, 0x94, 0xc2, 0x25, 0x53, 0x53, 0xba, 0x2d, 0xe6, 0x96, 0x52, 0x11, 0x29, 0xd9, 0x99, 0x89,
+	0x31, 0x13, 0xc7, 0x39, 0xad, 0x3c, 0xc3, 0xc9, 0xd4, 0x57, 0x23, 0x9a, 0x8d, 0x63, 0x4b, 0xaa,
+	0x30, 0xe2, 0x64, 0x7a, 0x3a, 0x9d, 0x0a, 0x62, 0x0a, 0xe8, 0x16, 0x5e, 0x01, 0x9d, 0x7f, 0x97,
+	0xa0, 0x5e, 0x68, 0x5a, 0xaa, 0xf8, 0x93, 0xe9, 0x94, 0x44, 0x92, 0x5e, 0x92, 0x20, 0x9f, 0xf8,
+	0x6a, 0xb8, 0x99, 0xa3, 0x5a, 0xe9, 0x5d, 0xd8, 0x5e, 0x86, 0xfc, 0x82, 0x98, 0x7e, 0x53, 0xc5,
+	0x96, 0x42, 0xbf, 0x82, 0xf6, 0x4a, 0x7c, 0xad, 0xdf, 0xec, 0xe4, 0xb8, 0xad, 0x43, 0x0f, 0x00,
+	0x0a, 0x83, 0xef, 0xa6, 0xe9, 0x0d, 0x49, 0xf1, 0xfb, 0x43, 0xa7, 0xd8, 0x96, 0xde, 0xd0, 0xeb,
+	0xce, 0x14, 0x9a, 0x7e, 0xd6, 0x0f, 0x65, 0x68, 0x6b, 0xa2, 0x1e, 0xc5, 0xd6, 0x3f, 0x6e, 0x2c,
+	0x59, 0xb0, 0xad, 0xb1, 0xbf, 0xb3, 0xed, 0x13, 0x68, 0x4e, 0x39, 0xfb, 0x42, 0xe2, 0xf5, 0xdb,
+	0x35, 0x0c, 0x68, 0xdb, 0x21, 0x03, 0x50, 0x0e, 0xc2, 0x24, 0x62, 0x5c, 0x1b, 0x50, 0xb5, 0xac,
+	0x5e, 0xee, 0x29, 0xdb, 0xc3, 0x4c, 0xce, 0x3c, 0x34, 0x2d, 0xae, 0x5b, 0x3c, 0xac, 0x80, 0xa8,
+	0x6f, 0x14, 0x2a, 0xc9, 0x32, 0x9f, 0x82, 0x6d, 0x38, 0x2a, 0xfd, 0x6f, 0xc8, 0x15, 0xd6, 0x9b,
+	0x9d, 0x73, 0xa8, 0x58, 0xa0, 0xe8, 0x48, 0xfb, 0x24, 0xe7, 0xc8, 0xbb, 0xb0, 0xcd, 0x8c, 0x17,
+	0xed, 0x93, 0x0c, 0x55, 0x78, 0x6a, 0xb9, 0xf8, 0x54, 0xe5, 0xda, 0xf6, 0xea, 0x19, 0x7d, 0x22,
+	0x43, 0xba, 0x40, 0x87, 0x50, 0x65, 0x09, 0x89, 0x15, 0xae, 0xf5, 0x17, 0x32, 0x64, 0xc5, 0x8b,
+	0x73, 0x1e, 0xf4, 0x02, 0x40, 0xc5, 0x05, 0x99, 0x68, 0x89, 0x8d, 0x1b, 0x25, 0x0a, 0x5c, 0x4a,
+	0xc6, 0x98, 0x53, 0xcb, 0x94, 0x6f, 0x96, 0x59, 0x71, 0x75, 0x86, 0xb0, 0x7b, 0x1c, 0x2e, 0xc2,
+	0x38, 0x22, 0xe6, 0xa2, 0xee, 0xdb, 0x75, 0x6c, 0x40, 0x67, 0x0b, 0x4b, 0xaa, 0x54, 0xa0, 0xe2,
+	0x95, 0x16, 0xb7, 0x11, 0x98, 0xd3, 0x9d, 0xbf, 0x19, 0xef, 0x99, 0x21, 0x19, 0x1d, 0x40, 0x55,
+	0x79, 0x43, 0x8d, 0x23, 0xf6, 0xe3, 0xb9, 0xb1, 0x76, 0x95, 0x7c, 0x17, 0x3d, 0x85, 0xa6, 0x9e,
+	0x53, 0xce, 0xc9, 0x82, 0x44, 0xd2, 0x86, 0x76, 0x0d, 0xaf, 0x83, 0xcf, 0x3e, 0xc3, 0x6e, 0xa1,
+	0xb6, 0xaa, 0x0f, 0xcd, 0x54, 0xa0, 0x1d, 0xa8, 0xfb, 0x9f, 0x82, 0xf7, 0xa3, 0xfe, 0xe0, 0xd5,
+	0x70, 0x34, 0x68, 0xdf, 0x42, 0x2d, 0x00, 0xff, 0x53, 0x30, 0x3a, 0x1d, 0x7c, 0x1a, 0x9e, 0xfb,
+	0xed, 0x92, 0xa5, 0x7b, 0xa7, 0xa3, 0x57, 0x43, 0x7c, 0xd2, 0xde, 0x40, 0x6d, 0x68, 0xf8, 0x9f,
+	0x82, 0x57, 0xef, 0x71, 0xaf, 0xeb, 0x0f, 0x4f, 0x47, 0xed, 0xb2, 0x45, 0xde, 0x8f, 0x1c, 0xcf,
+	0x26, 0x6a, 0x42, 0x4d, 0xf1, 0x74, 0x87, 0x6f, 0x07, 0xfd, 0xf6, 0xd6, 0x33, 0x1f, 0xea, 0x66,
+	0x92, 0xc9, 0x8f, 0x3c, 0x7e, 0x7b, 0xda, 0x7b, 0x13, 0x0c, 0x30, 0x3e, 0xc5, 0xed, 0x5b, 0x2b,
+	0xc0, 0xc7, 0xef, 0x47, 0x6f, 0xda, 0x25, 0xa5, 0xd1, 0x00, 0xc7, 0xb8, 0x3b, 0xea, 0xbd, 0x6e,
+	0x6f, 0xa0, 0x5d, 0x68, 0x1a, 0xc4, 0x5d, 0xac, 0xfc, 0xec, 0x2d, 0x54, 0xec, 0xe7, 0x32, 0x6a,
+	0x40, 0x75, 0x34, 0xf8, 0x18, 0x7c, 0x18, 0x0e, 0x3e, 0xb6, 0x6f, 0xa1, 0x3a, 0x54, 0xce, 0xf0,
+	0xe0, 0xac, 0x8b, 0x07, 0xe6, 0xfa, 0x67, 0x78, 0x10, 0xf4, 0x4e, 0x4f, 0x4e, 0x86, 0x7e, 0x7b,
+	0x03, 0x01, 0x6c, 0xdb, 0x75, 0x59, 0xad, 0xfb, 0x83, 0xde, 0xb0, 0x3f, 0x68, 0x6f, 0x1e, 0xff,
+	0xf1, 0xaf, 0x3f, 0xcf, 0xa8, 0x9c, 0xa7, 0xe3, 0xc3, 0x88, 0x2d, 0x8f, 0xcc, 0x7f, 0x1d, 0xaa,
+	0x97, 0x1f, 0xad, 0xfe, 0xf6, 0xb8, 0xf1, 0x1f, 0x97, 0xf1, 0xb6, 0x9e, 0x08, 0x7e, 0xfb, 0xbf,
+	0x00, 0x00, 0x00, 0xff, 0xff, 0x72, 0xc3, 0xab, 0xc3, 0x95, 0x11, 0x00, 0x00,
 }
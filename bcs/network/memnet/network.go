@@ -0,0 +1,403 @@
+package memnet
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// 节点地址前缀，完整格式为/memnet/<网络名>/<节点名>
+	addressPrefix = "/memnet/"
+)
+
+var (
+	ErrAddressIllegal = errors.New("memnet address illegal, want /memnet/<network>/<node>")
+	ErrNodeExist      = errors.New("memnet node already exist")
+	ErrNodeNotExist   = errors.New("memnet node not exist")
+	ErrMessageDropped = errors.New("memnet message dropped")
+	ErrUnreachable    = errors.New("memnet peer unreachable")
+)
+
+var (
+	netMu    sync.Mutex
+	networks = make(map[string]*Network)
+)
+
+// Address 返回节点在指定模拟网络中的地址，用于p2p配置中的address
+func Address(network, node string) string {
+	return addressPrefix + network + "/" + node
+}
+
+// parseAddress 解析/memnet/<network>/<node>格式的地址
+func parseAddress(address string) (string, string, error) {
+	if !strings.HasPrefix(address, addressPrefix) {
+		return "", "", ErrAddressIllegal
+	}
+	parts := strings.Split(strings.TrimPrefix(address, addressPrefix), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", ErrAddressIllegal
+	}
+	return parts[0], parts[1], nil
+}
+
+// NewNetwork 新建名为name的模拟网络，已存在同名网络时替换，seed决定延迟、丢包、重复等随机行为
+func NewNetwork(name string, seed int64) *Network {
+	n := &Network{
+		name:  name,
+		seed:  seed,
+		nodes: make(map[string]*MemNetServer),
+		links: make(map[string]*rand.Rand),
+		group: make(map[string]int),
+	}
+
+	netMu.Lock()
+	defer netMu.Unlock()
+	networks[name] = n
+	return n
+}
+
+// GetNetwork 返回名为name的模拟网络，不存在时返回nil
+func GetNetwork(name string) *Network {
+	netMu.Lock()
+	defer netMu.Unlock()
+	return networks[name]
+}
+
+// RemoveNetwork 移除名为name的模拟网络，已加入的节点不受影响
+func RemoveNetwork(name string) {
+	netMu.Lock()
+	defer netMu.Unlock()
+	delete(networks, name)
+}
+
+// loadNetwork 返回名为name的模拟网络，不存在时以seed=0新建
+func loadNetwork(name string) *Network {
+	netMu.Lock()
+	defer netMu.Unlock()
+	if n, ok := networks[name]; ok {
+		return n
+	}
+	n := &Network{
+		name:  name,
+		nodes: make(map[string]*MemNetServer),
+		links: make(map[string]*rand.Rand),
+		group: make(map[string]int),
+	}
+	networks[name] = n
+	return n
+}
+
+// Stats 模拟网络的消息统计
+type Stats struct {
+	// Sent 发送的消息数，包括请求和响应
+	Sent int64
+	// Delivered 送达的消息数，包括重复送达
+	Delivered int64
+	// Dropped 因丢包或网络分区未送达的消息数
+	Dropped int64
+	// Duplicated 重复送达的消息数
+	Duplicated int64
+}
+
+// Network 进程内的模拟网络，同一网络内的节点直接交换XuperMessage
+// 每条有向链路使用由seed和两端节点名确定的独立随机数序列，
+// 因此同一链路上按相同顺序发送的消息，其延迟、丢包和重复结果在多次运行间保持一致
+type Network struct {
+	name string
+	seed int64
+
+	mutex sync.Mutex
+	nodes map[string]*MemNetServer
+	links map[string]*rand.Rand
+	// group 节点所在的分区，不同分区的节点之间无法通信，未指定分区的节点属于分区0
+	group map[string]int
+
+	minLatency    time.Duration
+	maxLatency    time.Duration
+	dropRate      float64
+	duplicateRate float64
+
+	// 进行中的异步投递
+	pending sync.WaitGroup
+	stats   Stats
+}
+
+// Name 返回网络名
+func (n *Network) Name() string {
+	return n.name
+}
+
+// SetLatency 设置单程延迟，每条消息的延迟在[min, max]内随机
+func (n *Network) SetLatency(min, max time.Duration) {
+	if max < min {
+		max = min
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.minLatency = min
+	n.maxLatency = max
+}
+
+// SetDropRate 设置单条消息的丢失概率
+func (n *Network) SetDropRate(rate float64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.dropRate = rate
+}
+
+// SetDuplicateRate 设置单条消息被重复投递的概率
+func (n *Network) SetDuplicateRate(rate float64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.duplicateRate = rate
+}
+
+// Partition 将节点划分为若干分区，分区之间的消息全部丢失，未列出的节点属于同一个默认分区
+// 已在途的消息在投递时按新的分区判断
+func (n *Network) Partition(groups ...[]string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.group = make(map[string]int)
+	for i, nodes := range groups {
+		for _, node := range nodes {
+			n.group[node] = i + 1
+		}
+	}
+}
+
+// Heal 取消网络分区
+func (n *Network) Heal() {
+	n.Partition()
+}
+
+// Nodes 返回已加入网络的节点名，按字典序排列
+func (n *Network) Nodes() []string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.nodeList("")
+}
+
+// Flush 等待所有在途的异步消息投递完成
+func (n *Network) Flush() {
+	n.pending.Wait()
+}
+
+// Stats 返回消息统计
+func (n *Network) Stats() Stats {
+	return Stats{
+		Sent:       atomic.LoadInt64(&n.stats.Sent),
+		Delivered:  atomic.LoadInt64(&n.stats.Delivered),
+		Dropped:    atomic.LoadInt64(&n.stats.Dropped),
+		Duplicated: atomic.LoadInt64(&n.stats.Duplicated),
+	}
+}
+
+func (n *Network) join(node *MemNetServer) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if _, ok := n.nodes[node.id]; ok {
+		return ErrNodeExist
+	}
+	n.nodes[node.id] = node
+	return nil
+}
+
+func (n *Network) leave(id string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.nodes, id)
+}
+
+// nodeList 返回除exclude外的节点名，调用方需持有锁
+func (n *Network) nodeList(exclude string) []string {
+	ids := make([]string, 0, len(n.nodes))
+	for id := range n.nodes {
+		if id != exclude {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// peers 返回from之外的全部节点名
+func (n *Network) peers(from string) []string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.nodeList(from)
+}
+
+// lookupAccount 根据节点账户地址查找节点名
+func (n *Network) lookupAccount(account string) (string, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, id := range n.nodeList("") {
+		if n.nodes[id].account == account {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func (n *Network) peerInfos(exclude string) []*pb.PeerInfo {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	var infos []*pb.PeerInfo
+	for _, id := range n.nodeList(exclude) {
+		infos = append(infos, &pb.PeerInfo{
+			Id:      id,
+			Address: Address(n.name, id),
+			Account: n.nodes[id].account,
+		})
+	}
+	return infos
+}
+
+// fate 单条消息在链路上的投递结果
+type fate struct {
+	drop      bool
+	duplicate bool
+	latency   time.Duration
+}
+
+// roll 按from->to链路的随机数序列决定一条消息的投递结果
+func (n *Network) roll(from, to string) fate {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	key := from + "->" + to
+	r, ok := n.links[key]
+	if !ok {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		r = rand.New(rand.NewSource(n.seed ^ int64(h.Sum64())))
+		n.links[key] = r
+	}
+
+	// 每条消息固定消耗三个随机数，避免参数调整影响后续消息的随机序列
+	dropRoll, dupRoll, latencyRoll := r.Float64(), r.Float64(), r.Int63()
+	f := fate{
+		drop:      dropRoll < n.dropRate,
+		duplicate: dupRoll < n.duplicateRate,
+		latency:   n.minLatency,
+	}
+	if n.maxLatency > n.minLatency {
+		f.latency += time.Duration(latencyRoll % int64(n.maxLatency-n.minLatency+1))
+	}
+	return f
+}
+
+// reachable 判断from到to的消息能否送达，to不在网络中时返回nil
+func (n *Network) reachable(from, to string) (*MemNetServer, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	node, ok := n.nodes[to]
+	if !ok {
+		return nil, false
+	}
+	return node, n.group[from] == n.group[to]
+}
+
+// transmit 模拟一次单程传输，返回接收节点
+// 消息被丢弃或不可达时返回错误，duplicate表示该消息需被重复投递
+func (n *Network) transmit(from, to string) (*MemNetServer, bool, error) {
+	atomic.AddInt64(&n.stats.Sent, 1)
+	f := n.roll(from, to)
+	if f.latency > 0 {
+		time.Sleep(f.latency)
+	}
+	if f.drop {
+		atomic.AddInt64(&n.stats.Dropped, 1)
+		return nil, false, ErrMessageDropped
+	}
+	node, ok := n.reachable(from, to)
+	if node == nil {
+		atomic.AddInt64(&n.stats.Dropped, 1)
+		return nil, false, ErrNodeNotExist
+	}
+	if !ok {
+		atomic.AddInt64(&n.stats.Dropped, 1)
+		return nil, false, ErrUnreachable
+	}
+	atomic.AddInt64(&n.stats.Delivered, 1)
+	return node, f.duplicate, nil
+}
+
+// deliver 将消息交给接收节点处理，duplicate为true时再重复投递一次
+func (n *Network) deliver(node *MemNetServer, msg *pb.XuperMessage, stream *respStream, duplicate bool) {
+	node.receive(msg, stream)
+	if duplicate {
+		atomic.AddInt64(&n.stats.Delivered, 1)
+		atomic.AddInt64(&n.stats.Duplicated, 1)
+		node.receive(msg, stream)
+	}
+}
+
+// send 异步投递消息，不等待处理结果
+func (n *Network) send(from, to string, msg *pb.XuperMessage) {
+	n.pending.Add(1)
+	go func() {
+		defer n.pending.Done()
+		node, duplicate, err := n.transmit(from, to)
+		if err != nil {
+			return
+		}
+		n.deliver(node, msg, newRespStream(), duplicate)
+	}()
+}
+
+// request 投递请求并等待响应，请求或响应任一方向被丢弃时返回错误
+func (n *Network) request(from, to string, msg *pb.XuperMessage) (*pb.XuperMessage, error) {
+	node, duplicate, err := n.transmit(from, to)
+	if err != nil {
+		return nil, err
+	}
+	stream := newRespStream()
+	n.deliver(node, msg, stream, duplicate)
+	resp := stream.response()
+	if resp == nil {
+		return nil, fmt.Errorf("memnet no response from %s", to)
+	}
+
+	// 响应原路返回，同样受延迟、丢包及分区影响，重复的响应由请求方丢弃
+	if _, _, err = n.transmit(to, from); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// respStream 记录对端处理器返回的第一个响应
+type respStream struct {
+	mutex sync.Mutex
+	resp  *pb.XuperMessage
+}
+
+func newRespStream() *respStream {
+	return &respStream{}
+}
+
+func (s *respStream) Send(msg *pb.XuperMessage) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.resp == nil {
+		s.resp = proto.Clone(msg).(*pb.XuperMessage)
+	}
+	return nil
+}
+
+func (s *respStream) response() *pb.XuperMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.resp
+}
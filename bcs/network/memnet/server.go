@@ -0,0 +1,263 @@
+package memnet
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/network"
+	"github.com/xuperchain/xupercore/kernel/network/config"
+	netCtx "github.com/xuperchain/xupercore/kernel/network/context"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	ServerName = "memnet"
+)
+
+var (
+	ErrEmptyPeer  = errors.New("empty peer")
+	ErrNoResponse = errors.New("no response")
+)
+
+func init() {
+	network.Register(ServerName, NewMemNetServer)
+}
+
+// MemNetServer 进程内模拟网络的p2p实现，用于多节点测试
+// 节点地址格式为/memnet/<网络名>/<节点名>，同一网络名的节点加入同一个Network，节点名即peerID
+type MemNetServer struct {
+	ctx    *netCtx.NetCtx
+	log    logs.Logger
+	config *config.NetConf
+
+	network    *Network
+	id         string
+	account    string
+	dispatcher p2p.Dispatcher
+	scores     *p2p.ScoreBoard
+}
+
+var _ p2p.Server = &MemNetServer{}
+
+// NewMemNetServer create MemNetServer instance
+func NewMemNetServer() p2p.Server {
+	return &MemNetServer{}
+}
+
+// Init initialize memnet server using given config
+func (p *MemNetServer) Init(ctx *netCtx.NetCtx) error {
+	name, id, err := parseAddress(ctx.P2PConf.Address)
+	if err != nil {
+		ctx.GetLog().Error("memnet address error", "address", ctx.P2PConf.Address, "error", err)
+		return err
+	}
+
+	p.ctx = ctx
+	p.log = ctx.GetLog()
+	p.config = ctx.P2PConf
+	p.network = loadNetwork(name)
+	p.id = id
+	p.dispatcher = p2p.NewDispatcher(ctx)
+	p.scores = p2p.NewScoreBoard(ctx.P2PConf, p.banPeer)
+
+	// 测试环境中节点可以没有账户，此时以节点名作为账户
+	keyPath := ctx.EnvCfg.GenDataAbsPath(ctx.EnvCfg.KeyDir)
+	p.account, err = xaddress.LoadAddress(keyPath)
+	if err != nil {
+		p.log.Warn("load account error, use node id as account", "path", keyPath, "id", id)
+		p.account = id
+	}
+	return nil
+}
+
+// Start 加入模拟网络
+func (p *MemNetServer) Start() {
+	p.log.Info("StartMemNetServer", "network", p.network.Name(), "id", p.id)
+	if err := p.network.join(p); err != nil {
+		p.log.Error("join memnet error", "network", p.network.Name(), "id", p.id, "error", err)
+	}
+}
+
+// Stop 离开模拟网络，之后发往该节点的消息全部丢失
+func (p *MemNetServer) Stop() {
+	p.log.Info("StopMemNetServer", "network", p.network.Name(), "id", p.id)
+	p.network.leave(p.id)
+}
+
+// receive 处理模拟网络投递的消息
+func (p *MemNetServer) receive(msg *pb.XuperMessage, stream p2p.Stream) {
+	if p.scores.IsBanned(msg.GetHeader().GetFrom()) {
+		p.log.Trace("memnet refuse message from banned peer", "from", msg.GetHeader().GetFrom())
+		return
+	}
+
+	if err := p.dispatcher.Dispatch(msg, stream); err != nil {
+		p.log.Warn("handle new message dispatch error", "log_id", msg.GetHeader().GetLogid(),
+			"type", msg.GetHeader().GetType(), "from", msg.GetHeader().GetFrom(), "error", err)
+	}
+}
+
+func (p *MemNetServer) NewSubscriber(typ pb.XuperMessage_MessageType, v interface{}, opts ...p2p.SubscriberOption) p2p.Subscriber {
+	return p2p.NewSubscriber(p.ctx, typ, v, opts...)
+}
+
+func (p *MemNetServer) Register(sub p2p.Subscriber) error {
+	return p.dispatcher.Register(sub)
+}
+
+func (p *MemNetServer) UnRegister(sub p2p.Subscriber) error {
+	return p.dispatcher.UnRegister(sub)
+}
+
+// SendMessage send message to peers using given filter strategy
+func (p *MemNetServer) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage, optFunc ...p2p.OptionFunc) error {
+	peerIDs := p.getPeerIDs(optFunc)
+	if len(peerIDs) <= 0 {
+		p.log.Warn("SendMessage peerID empty", "log_id", msg.GetHeader().GetLogid(),
+			"msgType", msg.GetHeader().GetType())
+		return ErrEmptyPeer
+	}
+
+	msg = p.outgoing(msg)
+	for _, peerID := range peerIDs {
+		if p.scores.IsBanned(peerID) {
+			continue
+		}
+		p.network.send(p.id, peerID, msg)
+	}
+	return nil
+}
+
+// SendMessageWithResponse send message to peers using given filter strategy, expect response from peers
+func (p *MemNetServer) SendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMessage, optFunc ...p2p.OptionFunc) ([]*pb.XuperMessage, error) {
+	opt := p2p.Apply(optFunc)
+	peerIDs := p.getPeerIDs(optFunc)
+	if len(peerIDs) <= 0 {
+		p.log.Warn("SendMessageWithResponse peerID empty", "log_id", msg.GetHeader().GetLogid(),
+			"msgType", msg.GetHeader().GetType())
+		return nil, ErrEmptyPeer
+	}
+
+	msg = p.outgoing(msg)
+	wg := sync.WaitGroup{}
+	// 响应按peerIDs的顺序返回，不受各请求完成先后的影响
+	results := make([]*pb.XuperMessage, len(peerIDs))
+	for i, peerID := range peerIDs {
+		i, peerID := i, peerID
+		if p.scores.IsBanned(peerID) {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := p.network.request(p.id, peerID, msg)
+			if err != nil {
+				p.log.Debug("memnet request error", "log_id", msg.GetHeader().GetLogid(), "peerID", peerID, "error", err)
+				p.ReportPeer(peerID, p2p.PeerEventTimeout)
+				return
+			}
+			p.ReportPeer(peerID, p2p.PeerEventUsefulResponse)
+			resp.Header.From = peerID
+			results[i] = resp
+		}()
+	}
+	wg.Wait()
+
+	threshold := int(float32(len(peerIDs)) * opt.Percent)
+	response := make([]*pb.XuperMessage, 0, len(peerIDs))
+	for _, resp := range results {
+		if resp == nil || !p2p.VerifyChecksum(resp) || !p2p.VerifyMessageType(msg, resp, resp.GetHeader().GetFrom()) {
+			continue
+		}
+		response = append(response, resp)
+		if len(response) >= threshold {
+			break
+		}
+	}
+	if len(response) <= 0 {
+		p.log.Warn("memnet: no response", "log_id", msg.GetHeader().GetLogid())
+		return nil, ErrNoResponse
+	}
+	return response, nil
+}
+
+// outgoing 复制待发送的消息并设置来源，避免接收方修改发送方持有的消息
+func (p *MemNetServer) outgoing(msg *pb.XuperMessage) *pb.XuperMessage {
+	msg = proto.Clone(msg).(*pb.XuperMessage)
+	msg.Header.From = p.id
+	return msg
+}
+
+// getPeerIDs 指定了peerID、地址或账户时发送给指定节点，否则发送给网络中的全部其他节点
+func (p *MemNetServer) getPeerIDs(optFunc []p2p.OptionFunc) []string {
+	opt := p2p.Apply(optFunc)
+	if len(opt.PeerIDs) <= 0 && len(opt.Addresses) <= 0 && len(opt.Accounts) <= 0 {
+		return p.network.peers(p.id)
+	}
+
+	uniq := make(map[string]bool)
+	peerIDs := make([]string, 0)
+	add := func(id string) {
+		if id == p.id || uniq[id] {
+			return
+		}
+		uniq[id] = true
+		peerIDs = append(peerIDs, id)
+	}
+
+	for _, id := range opt.PeerIDs {
+		add(id)
+	}
+	for _, address := range opt.Addresses {
+		if _, id, err := parseAddress(address); err == nil {
+			add(id)
+			continue
+		}
+		add(address)
+	}
+	for _, account := range opt.Accounts {
+		id, ok := p.network.lookupAccount(account)
+		if !ok {
+			p.log.Warn("memnet: get peer id by account failed", "account", account)
+			continue
+		}
+		add(id)
+	}
+	return peerIDs
+}
+
+func (p *MemNetServer) Context() *netCtx.NetCtx {
+	return p.ctx
+}
+
+func (p *MemNetServer) PeerInfo() pb.PeerInfo {
+	return pb.PeerInfo{
+		Id:      p.id,
+		Address: Address(p.network.Name(), p.id),
+		Account: p.account,
+		Peer:    p.network.peerInfos(p.id),
+	}
+}
+
+// ReportPeer 上报节点行为，评分过低的节点会被封禁
+func (p *MemNetServer) ReportPeer(peerID string, event p2p.PeerEvent) {
+	p.log.Debug("report peer", "peerID", peerID, "event", event)
+	p.scores.Report(peerID, event)
+}
+
+// PeerScores 返回节点评分
+func (p *MemNetServer) PeerScores() []*p2p.PeerScore {
+	return p.scores.Scores()
+}
+
+// banPeer 模拟网络没有连接需要关闭，封禁期间拒绝该节点的消息
+func (p *MemNetServer) banPeer(peerID string) {
+	p.log.Warn("peer score too low, ban peer", "peerID", peerID, "duration", p.config.BanDuration)
+}
@@ -0,0 +1,239 @@
+package memnet
+
+import (
+	"testing"
+	"time"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	nctx "github.com/xuperchain/xupercore/kernel/network/context"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+func Handler(ctx xctx.XContext, msg *pb.XuperMessage) (*pb.XuperMessage, error) {
+	typ := p2p.GetRespMessageType(msg.Header.Type)
+	resp := p2p.NewMessage(typ, msg, p2p.WithLogId(msg.Header.Logid))
+	return resp, nil
+}
+
+func newNode(t *testing.T, network, id string) (*MemNetServer, *nctx.NetCtx) {
+	ecfg, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := nctx.NewNetCtx(ecfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.P2PConf.Module = ServerName
+	ctx.P2PConf.Address = Address(network, id)
+	// 关闭节点评分，避免模拟丢包导致节点被封禁
+	ctx.P2PConf.BanScoreThreshold = 0
+
+	node := NewMemNetServer().(*MemNetServer)
+	if err := node.Init(ctx); err != nil {
+		t.Fatal("server init error", err)
+	}
+	node.Start()
+	if err := node.Register(p2p.NewSubscriber(ctx, pb.XuperMessage_GET_BLOCK, p2p.HandleFunc(Handler))); err != nil {
+		t.Fatal("register subscriber error", err)
+	}
+	return node, ctx
+}
+
+func newNodes(t *testing.T, network string, ids ...string) []*MemNetServer {
+	nodes := make([]*MemNetServer, 0, len(ids))
+	for _, id := range ids {
+		node, _ := newNode(t, network, id)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func request(node *MemNetServer, opts ...p2p.OptionFunc) ([]*pb.XuperMessage, error) {
+	msg := p2p.NewMessage(pb.XuperMessage_GET_BLOCK, nil)
+	return node.SendMessageWithResponse(node.ctx, msg, opts...)
+}
+
+func TestParseAddress(t *testing.T) {
+	name, id, err := parseAddress(Address("net", "node1"))
+	if err != nil || name != "net" || id != "node1" {
+		t.Error("parse address error", name, id, err)
+	}
+	for _, address := range []string{"/ip4/127.0.0.1/tcp/47101", "/memnet/net", "/memnet//node1"} {
+		if _, _, err := parseAddress(address); err != ErrAddressIllegal {
+			t.Error("illegal address should be rejected", address)
+		}
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	n := NewNetwork("TestSendMessage", 1)
+	defer RemoveNetwork(n.Name())
+	node1, ctx1 := newNode(t, n.Name(), "node1")
+	node2, ctx2 := newNode(t, n.Name(), "node2")
+	node3, _ := newNode(t, n.Name(), "node3")
+
+	ch1 := make(chan *pb.XuperMessage, 8)
+	ch2 := make(chan *pb.XuperMessage, 8)
+	node1.Register(p2p.NewSubscriber(ctx1, pb.XuperMessage_POSTTX, ch1))
+	node2.Register(p2p.NewSubscriber(ctx2, pb.XuperMessage_POSTTX, ch2))
+
+	msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	if err := node3.SendMessage(node3.ctx, msg); err != nil {
+		t.Fatal("send message error", err)
+	}
+	n.Flush()
+	if len(ch1) != 1 || len(ch2) != 1 {
+		t.Fatal("broadcast message not delivered", len(ch1), len(ch2))
+	}
+	if from := (<-ch1).GetHeader().GetFrom(); from != "node3" {
+		t.Error("message from error", from)
+	}
+
+	// 指定peerID时只发送给指定节点
+	msg = p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	node3.SendMessage(node3.ctx, msg, p2p.WithPeerIDs([]string{"node2"}))
+	n.Flush()
+	if len(ch1) != 0 || len(ch2) != 2 {
+		t.Error("message should only be sent to node2", len(ch1), len(ch2))
+	}
+
+	// 节点停止后不再收到消息
+	node2.Stop()
+	msg = p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	node3.SendMessage(node3.ctx, msg)
+	n.Flush()
+	if len(ch1) != 1 || len(ch2) != 2 {
+		t.Error("stopped node should not receive message", len(ch1), len(ch2))
+	}
+}
+
+func TestSendMessageWithResponse(t *testing.T) {
+	n := NewNetwork("TestSendMessageWithResponse", 1)
+	defer RemoveNetwork(n.Name())
+	nodes := newNodes(t, n.Name(), "node1", "node2", "node3")
+
+	responses, err := request(nodes[0])
+	if err != nil {
+		t.Fatal("request error", err)
+	}
+	if len(responses) != 2 || responses[0].GetHeader().GetFrom() != "node2" ||
+		responses[1].GetHeader().GetFrom() != "node3" {
+		t.Fatal("responses error", responses)
+	}
+	if responses[0].GetHeader().GetType() != pb.XuperMessage_GET_BLOCK_RES {
+		t.Error("response type error", responses[0].GetHeader().GetType())
+	}
+
+	// 测试节点没有账户时以节点名作为账户
+	responses, err = request(nodes[0], p2p.WithAccounts([]string{"node3"}))
+	if err != nil || len(responses) != 1 || responses[0].GetHeader().GetFrom() != "node3" {
+		t.Error("request with accounts error", responses, err)
+	}
+	if info := nodes[0].PeerInfo(); len(info.GetPeer()) != 2 {
+		t.Error("peer info error", info)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	n := NewNetwork("TestPartition", 1)
+	defer RemoveNetwork(n.Name())
+	nodes := newNodes(t, n.Name(), "node1", "node2", "node3")
+
+	n.Partition([]string{"node1"}, []string{"node2", "node3"})
+	if _, err := request(nodes[0]); err != ErrNoResponse {
+		t.Error("partitioned node should get no response", err)
+	}
+	responses, err := request(nodes[1])
+	if err != nil || len(responses) != 1 || responses[0].GetHeader().GetFrom() != "node3" {
+		t.Error("request in partition error", responses, err)
+	}
+
+	n.Heal()
+	responses, err = request(nodes[0])
+	if err != nil || len(responses) != 2 {
+		t.Error("request after heal error", responses, err)
+	}
+}
+
+// dropPattern 顺序发送count个请求，返回各请求是否成功
+func dropPattern(t *testing.T, name string, seed int64, count int) []bool {
+	n := NewNetwork(name, seed)
+	defer RemoveNetwork(n.Name())
+	n.SetDropRate(0.3)
+	nodes := newNodes(t, n.Name(), "node1", "node2")
+
+	pattern := make([]bool, count)
+	for i := range pattern {
+		_, err := request(nodes[0])
+		pattern[i] = err == nil
+	}
+	return pattern
+}
+
+func TestDropDeterministic(t *testing.T) {
+	count := 50
+	p1 := dropPattern(t, "TestDropDeterministic", 7, count)
+	p2 := dropPattern(t, "TestDropDeterministic", 7, count)
+	p3 := dropPattern(t, "TestDropDeterministic", 8, count)
+
+	succ, diff := 0, 0
+	for i := 0; i < count; i++ {
+		if p1[i] != p2[i] {
+			t.Fatal("same seed should drop the same messages", "index", i)
+		}
+		if p1[i] {
+			succ++
+		}
+		if p1[i] != p3[i] {
+			diff++
+		}
+	}
+	// 请求和响应各有0.3的丢失概率
+	if succ == 0 || succ == count {
+		t.Error("drop rate not applied", "succ", succ)
+	}
+	if diff == 0 {
+		t.Error("different seed should drop different messages")
+	}
+}
+
+func TestDuplicate(t *testing.T) {
+	n := NewNetwork("TestDuplicate", 1)
+	defer RemoveNetwork(n.Name())
+	n.SetDuplicateRate(1)
+	node1, ctx1 := newNode(t, n.Name(), "node1")
+	node2, _ := newNode(t, n.Name(), "node2")
+
+	ch := make(chan *pb.XuperMessage, 8)
+	node1.Register(p2p.NewSubscriber(ctx1, pb.XuperMessage_POSTTX, ch))
+	msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	node2.SendMessage(node2.ctx, msg)
+	n.Flush()
+
+	// 重复投递的消息由dispatcher去重
+	if len(ch) != 1 {
+		t.Error("duplicated message should be handled once", len(ch))
+	}
+	if stats := n.Stats(); stats.Duplicated != 1 || stats.Delivered != 2 {
+		t.Error("stats error", stats)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	n := NewNetwork("TestLatency", 1)
+	defer RemoveNetwork(n.Name())
+	n.SetLatency(20*time.Millisecond, 20*time.Millisecond)
+	nodes := newNodes(t, n.Name(), "node1", "node2")
+
+	begin := time.Now()
+	if _, err := request(nodes[0]); err != nil {
+		t.Fatal("request error", err)
+	}
+	// 请求和响应各有一次延迟
+	if cost := time.Since(begin); cost < 40*time.Millisecond {
+		t.Error("latency not applied", cost)
+	}
+}
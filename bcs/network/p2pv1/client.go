@@ -7,6 +7,8 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	prom "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
@@ -57,6 +59,9 @@ func (p *P2PServerV1) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerI
 	wg := sync.WaitGroup{}
 	for _, peerID := range peerIDs {
 		peerID := peerID
+		if p.scores.IsBanned(peerHost(peerID)) {
+			continue
+		}
 		conn, err := p.pool.Get(peerID)
 		if err != nil {
 			p.log.Warn("p2p: get conn error",
@@ -120,6 +125,9 @@ func (p *P2PServerV1) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 	respCh := make(chan *pb.XuperMessage, len(peerIDs))
 	for _, peerID := range peerIDs {
 		peerID := peerID
+		if p.scores.IsBanned(peerHost(peerID)) {
+			continue
+		}
 		conn, err := p.pool.Get(peerID)
 		if err != nil {
 			p.log.Warn("p2p: get conn error", "log_id", msg.GetHeader().GetLogid(),
@@ -133,8 +141,12 @@ func (p *P2PServerV1) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 
 			resp, err := conn.SendMessageWithResponse(ctx, msg)
 			if err != nil {
+				if status.Code(err) == codes.DeadlineExceeded {
+					p.ReportPeer(peerID, p2p.PeerEventTimeout)
+				}
 				return
 			}
+			p.ReportPeer(peerID, p2p.PeerEventUsefulResponse)
 			resp.Header.From = peerID
 			respCh <- resp
 		}(conn)
@@ -197,6 +197,24 @@ func (p *ConnPool) Get(addr string) (*Conn, error) {
 	return conn, nil
 }
 
+// Delete close and remove the connection of given addr
+func (p *ConnPool) Delete(addr string) {
+	if v, ok := p.pool.Load(addr); ok {
+		p.pool.Delete(addr)
+		v.(*Conn).Close()
+	}
+}
+
+// DeleteHost close and remove all the connections to given host
+func (p *ConnPool) DeleteHost(host string) {
+	p.pool.Range(func(key, value interface{}) bool {
+		if peerHost(key.(string)) == host {
+			p.Delete(key.(string))
+		}
+		return true
+	})
+}
+
 func (p *ConnPool) GetAll() map[string]string {
 	remotePeer := make(map[string]string, 32)
 	p.pool.Range(func(key, value interface{}) bool {
@@ -1,6 +1,7 @@
 package p2pv1
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -13,6 +14,7 @@ import (
 	"github.com/patrickmn/go-cache"
 	prom "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
@@ -34,6 +36,8 @@ var (
 	ErrAddressIllegal  = errors.New("address illegal")
 	ErrLoadAccount     = errors.New("load account error")
 	ErrAccountNotExist = errors.New("account not exist")
+	ErrPeerBanned      = errors.New("peer is banned")
+	ErrUnknownPeer     = errors.New("unknown remote peer")
 )
 
 func init() {
@@ -49,6 +53,7 @@ type P2PServerV1 struct {
 	address    multiaddr.Multiaddr
 	pool       *ConnPool
 	dispatcher p2p.Dispatcher
+	scores     *p2p.ScoreBoard
 
 	bootNodes    []string
 	staticNodes  map[string][]string
@@ -80,6 +85,7 @@ func (p *P2PServerV1) Init(ctx *netCtx.NetCtx) error {
 	p.config = ctx.P2PConf
 	p.pool = pool
 	p.dispatcher = p2p.NewDispatcher(ctx)
+	p.scores = p2p.NewScoreBoard(ctx.P2PConf, p.banPeer)
 
 	// address
 	p.address, err = multiaddr.NewMultiaddr(ctx.P2PConf.Address)
@@ -174,6 +180,20 @@ func (p *P2PServerV1) SendP2PMessage(stream pb.P2PService_SendP2PMessageServer)
 		return err
 	}
 
+	host, err := remoteHost(stream.Context())
+	if err != nil {
+		p.log.Warn("SendP2PMessage get remote peer error", "error", err)
+		return err
+	}
+	if p.scores.IsBanned(host) {
+		p.log.Trace("SendP2PMessage refuse message from banned peer", "host", host)
+		return ErrPeerBanned
+	}
+	// 消息来源由发送方自行填写，使用连接对端的IP，避免伪造来源导致其他节点被扣分
+	if msg.GetHeader() != nil {
+		msg.Header.From = sourceAddress(msg.GetHeader().GetFrom(), host)
+	}
+
 	if p.ctx.EnvCfg.MetricSwitch {
 		tm := time.Now()
 		defer func() {
@@ -248,6 +268,81 @@ func (p *P2PServerV1) PeerInfo() pb.PeerInfo {
 	return peerInfo
 }
 
+// ReportPeer 上报节点行为，评分过低的节点会被断开并封禁
+// p2pv1的节点没有身份认证，评分和封禁以节点IP为标识
+func (p *P2PServerV1) ReportPeer(peerID string, event p2p.PeerEvent) {
+	p.log.Debug("report peer", "peerID", peerID, "event", event)
+	p.scores.Report(peerHost(peerID), event)
+}
+
+// PeerScores 返回节点评分
+func (p *P2PServerV1) PeerScores() []*p2p.PeerScore {
+	return p.scores.Scores()
+}
+
+// banPeer 关闭到被封禁IP的全部连接，封禁期间拒绝来自该IP的消息
+func (p *P2PServerV1) banPeer(host string) {
+	p.log.Warn("peer score too low, ban peer", "host", host, "duration", p.config.BanDuration)
+	p.pool.DeleteHost(host)
+}
+
+// peerAddress p2pv1的节点以"ip:port"标识，消息来源为multiaddr时转换为"ip:port"
+func peerAddress(peerID string) string {
+	if !strings.HasPrefix(peerID, "/") {
+		return peerID
+	}
+
+	addr, err := multiaddr.NewMultiaddr(peerID)
+	if err != nil {
+		return peerID
+	}
+	_, host, err := manet.DialArgs(addr)
+	if err != nil {
+		return peerID
+	}
+	return host
+}
+
+// peerHost 返回节点地址中的IP，地址不合法时原样返回
+func peerHost(peerID string) string {
+	addr := peerAddress(peerID)
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// remoteHost 返回grpc连接对端的IP
+func remoteHost(ctx context.Context) (string, error) {
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.Addr == nil {
+		return "", ErrUnknownPeer
+	}
+	host, _, err := net.SplitHostPort(pr.Addr.String())
+	if err != nil {
+		return "", err
+	}
+	return host, nil
+}
+
+// sourceAddress 以连接对端的IP替换消息来源中的IP，保留来源声明的监听端口用于回复
+func sourceAddress(from, host string) string {
+	_, port, err := net.SplitHostPort(peerAddress(from))
+	if err != nil {
+		port = "0"
+	}
+	proto := "ip4"
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() != nil {
+			host = ip.To4().String()
+		} else {
+			proto = "ip6"
+		}
+	}
+	return fmt.Sprintf("/%s/%s/tcp/%s", proto, host, port)
+}
+
 // connectBootNodes connect to boot node
 func (p *P2PServerV1) connectBootNodes() {
 	p.bootNodes = p.config.BootNodes
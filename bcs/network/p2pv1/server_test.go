@@ -1,10 +1,15 @@
 package p2pv1
 
 import (
-	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"context"
+	"net"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/mock"
 	nctx "github.com/xuperchain/xupercore/kernel/network/context"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
@@ -97,3 +102,104 @@ func TestP2PServerV1(t *testing.T) {
 	startNode3(t)
 	time.Sleep(time.Second)
 }
+
+// mockStream 模拟指定对端地址的grpc stream
+type mockStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msg  *pb.XuperMessage
+	sent []*pb.XuperMessage
+}
+
+func newMockStream(remote string, msg *pb.XuperMessage) *mockStream {
+	addr, _ := net.ResolveTCPAddr("tcp", remote)
+	return &mockStream{
+		ctx: peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+		msg: msg,
+	}
+}
+
+func (s *mockStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockStream) Recv() (*pb.XuperMessage, error) {
+	return s.msg, nil
+}
+
+func (s *mockStream) Send(msg *pb.XuperMessage) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestPeerHost(t *testing.T) {
+	cases := map[string]string{
+		"/ip4/127.0.0.1/tcp/47101": "127.0.0.1",
+		"127.0.0.1:47101":          "127.0.0.1",
+		"/ip6/::1/tcp/47101":       "::1",
+		"illegal":                  "illegal",
+	}
+	for peerID, host := range cases {
+		if got := peerHost(peerID); got != host {
+			t.Errorf("peerHost(%s) = %s, want %s", peerID, got, host)
+		}
+	}
+}
+
+func TestSourceAddress(t *testing.T) {
+	if got := sourceAddress("/ip4/10.0.0.1/tcp/47101", "127.0.0.1"); got != "/ip4/127.0.0.1/tcp/47101" {
+		t.Error("source address error", got)
+	}
+	if got := sourceAddress("illegal", "127.0.0.1"); got != "/ip4/127.0.0.1/tcp/0" {
+		t.Error("source address error", got)
+	}
+	if got := sourceAddress("/ip4/10.0.0.1/tcp/47101", "::1"); got != "/ip6/::1/tcp/47101" {
+		t.Error("source address error", got)
+	}
+}
+
+func TestSendP2PMessageFrom(t *testing.T) {
+	mock.InitLogForTest()
+	ecfg, _ := mock.NewEnvConfForTest("p2pv1/node1/conf/env.yaml")
+	ctx, _ := nctx.NewNetCtx(ecfg)
+
+	node := NewP2PServerV1().(*P2PServerV1)
+	if err := node.Init(ctx); err != nil {
+		t.Fatalf("server init error: %v", err)
+	}
+	ch := make(chan *pb.XuperMessage, 8)
+	if err := node.Register(p2p.NewSubscriber(ctx, pb.XuperMessage_POSTTX, ch)); err != nil {
+		t.Fatalf("register subscriber error: %v", err)
+	}
+
+	// 伪造的来源被替换为连接对端的IP
+	msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	msg.Header.From = "/ip4/10.0.0.1/tcp/47101"
+	if err := node.SendP2PMessage(newMockStream("127.0.0.2:51234", msg)); err != nil {
+		t.Fatalf("SendP2PMessage error: %v", err)
+	}
+	select {
+	case recv := <-ch:
+		if recv.GetHeader().GetFrom() != "/ip4/127.0.0.2/tcp/47101" {
+			t.Error("message from error", recv.GetHeader().GetFrom())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not dispatched")
+	}
+
+	// 按来源上报的评分落在连接对端IP上，被封禁后拒绝该IP的消息
+	for i := 0; i < 100 && !node.scores.IsBanned("127.0.0.2"); i++ {
+		node.ReportPeer("/ip4/127.0.0.2/tcp/47101", p2p.PeerEventInvalidBlock)
+	}
+	if !node.scores.IsBanned("127.0.0.2") {
+		t.Fatal("peer should be banned")
+	}
+	if node.scores.IsBanned("10.0.0.1") {
+		t.Error("forged source should not be banned")
+	}
+	msg = p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	msg.Header.From = "/ip4/10.0.0.1/tcp/47101"
+	if err := node.SendP2PMessage(newMockStream("127.0.0.2:51235", msg)); err != ErrPeerBanned {
+		t.Error("banned peer should be refused", err)
+	}
+}
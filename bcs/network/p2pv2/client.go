@@ -46,6 +46,13 @@ func (p *P2PServerV2) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage,
 	}()
 
 	opt := p2p.Apply(optFunc)
+	if p.gossip != nil && isGossipMessage(msg, opt) {
+		err := p.gossipMessage(ctx, msg)
+		if err != ErrGossipNoPeer {
+			return err
+		}
+	}
+
 	filter := p.getFilter(msg, opt)
 	peers, _ := filter.Filter()
 	ctx.GetTimer().Mark("filter")
@@ -72,6 +79,34 @@ func (p *P2PServerV2) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage,
 	return p.sendMessage(ctx, msg, peerIDs)
 }
 
+// gossipMessage 通过gossipsub广播消息，已在mesh中的消息由mesh继续转发，处理器再次广播时直接忽略
+// 链的topic中还没有其他节点时返回ErrGossipNoPeer，由调用方改用stream发送
+func (p *P2PServerV2) gossipMessage(ctx xctx.XContext, msg *pb.XuperMessage) error {
+	if p.gossip.isRelayed(msg) {
+		ctx.GetLog().SetInfoField("relayed", true)
+		return nil
+	}
+
+	err := p.gossip.publish(msg)
+	ctx.GetTimer().Mark("publish")
+	if err != nil && err != ErrGossipNoPeer {
+		p.log.Warn("p2p: gossip publish error", "log_id", msg.GetHeader().GetLogid(),
+			"msgType", msg.GetHeader().GetType(), "error", err)
+	}
+	return err
+}
+
+// joinGossip 节点为某条链发送请求时加入该链的广播topic
+func (p *P2PServerV2) joinGossip(msg *pb.XuperMessage) {
+	bcname := msg.GetHeader().GetBcname()
+	if p.gossip == nil || bcname == "" {
+		return
+	}
+	if _, err := p.gossip.join(bcname); err != nil {
+		p.log.Warn("p2p: join gossip topic error", "bcname", bcname, "error", err)
+	}
+}
+
 func (p *P2PServerV2) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerIDs []peer.ID) error {
 	var wg sync.WaitGroup
 	for _, peerID := range peerIDs {
@@ -128,6 +163,7 @@ func (p *P2PServerV2) SendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 	}()
 
 	opt := p2p.Apply(optFunc)
+	p.joinGossip(msg)
 	filter := p.getFilter(msg, opt)
 	peers, _ := filter.Filter()
 
@@ -0,0 +1,265 @@
+package p2pv2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/patrickmn/go-cache"
+
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	ErrGossipNoPeer     = errors.New("no peer in gossip topic")
+	ErrGossipMessage    = errors.New("gossip message illegal")
+	ErrGossipNoResponse = errors.New("gossip message can not be responded")
+)
+
+// gossipMessageTypes 通过gossipsub广播的消息类型，请求/响应类消息仍使用stream直连
+var gossipMessageTypes = map[pb.XuperMessage_MessageType]bool{
+	pb.XuperMessage_POSTTX:      true,
+	pb.XuperMessage_SENDBLOCK:   true,
+	pb.XuperMessage_BATCHPOSTTX: true,
+	pb.XuperMessage_NEW_BLOCKID: true,
+}
+
+// isGossipMessage 判断消息是否通过gossipsub广播，指定了接收节点的消息仍然直连发送
+func isGossipMessage(msg *pb.XuperMessage, opt *p2p.Option) bool {
+	if !gossipMessageTypes[msg.GetHeader().GetType()] || msg.GetHeader().GetBcname() == "" {
+		return false
+	}
+	return len(opt.PeerIDs) <= 0 && len(opt.Addresses) <= 0 &&
+		len(opt.Accounts) <= 0 && len(opt.WhiteList) <= 0
+}
+
+// gossipTopic 返回链的广播topic
+func gossipTopic(bcname string) string {
+	return fmt.Sprintf("%s/%s/broadcast", prefix, bcname)
+}
+
+// decodeGossipMessage 解析gossipsub消息，消息来源取gossipsub签名的发布者，
+// 与stream直连时以连接对端作为来源一致，避免伪造来源
+func decodeGossipMessage(pmsg *pubsubpb.Message) (*pb.XuperMessage, error) {
+	msg := new(pb.XuperMessage)
+	if err := proto.Unmarshal(pmsg.GetData(), msg); err != nil {
+		return nil, err
+	}
+	if msg.GetHeader() == nil {
+		return nil, ErrGossipMessage
+	}
+
+	from, err := peer.IDFromBytes(pmsg.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	msg.Header.From = from.Pretty()
+	return msg, nil
+}
+
+// gossipKey 广播消息的去重key，只取消息类型、链名和消息内容，不含来源和logid，
+// 不同节点转发的同一内容得到相同的key。crc32校验和可以构造碰撞，内容使用哈希
+func gossipKey(msg *pb.XuperMessage) string {
+	header := msg.GetHeader()
+	buf := new(bytes.Buffer)
+	buf.WriteString(header.GetType().String())
+	buf.WriteString(header.GetBcname())
+	buf.Write(hash.DoubleSha256(msg.GetData().GetMsgInfo()))
+	return utils.F(hash.DoubleSha256(buf.Bytes()))
+}
+
+// gossipMessageID 以gossipKey作为gossipsub的消息id，各节点再次广播的同一内容在mesh中只传播一次
+func gossipMessageID(pmsg *pubsubpb.Message) string {
+	msg, err := decodeGossipMessage(pmsg)
+	if err != nil {
+		return pubsub.DefaultMsgIdFn(pmsg)
+	}
+	return gossipKey(msg)
+}
+
+// gossipStream 经gossipsub收到的消息没有对端stream，广播类消息也不需要响应
+type gossipStream struct{}
+
+func (s gossipStream) Send(*pb.XuperMessage) error {
+	return ErrGossipNoResponse
+}
+
+// gossip 基于gossipsub的广播，每条链使用独立的topic
+// 节点在首次为某条链发送消息时加入该链的topic
+type gossip struct {
+	srv    *P2PServerV2
+	log    logs.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+	ps     *pubsub.PubSub
+
+	mutex  sync.Mutex
+	topics map[string]*pubsub.Topic
+	// seen 已处理的广播消息，经stream和gossipsub收到的同一内容只处理一次
+	seen *cache.Cache
+	// relayed 已在mesh中的广播消息，包括经gossipsub收到的和本节点发布的，处理器再次广播时直接忽略
+	relayed *cache.Cache
+}
+
+// newGossip 需要在节点建立连接前创建，gossipsub只会与之后建立连接的节点交换订阅信息
+func newGossip(p *P2PServerV2) (*gossip, error) {
+	ctx, cancel := context.WithCancel(p.ctx)
+	opts := []pubsub.Option{
+		pubsub.WithMessageIdFn(gossipMessageID),
+		pubsub.WithMaxMessageSize(int(p.config.MaxMessageSize) << 20),
+	}
+	ps, err := pubsub.NewGossipSub(ctx, p.host, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	g := &gossip{
+		srv:    p,
+		log:    p.log,
+		ctx:    ctx,
+		cancel: cancel,
+		ps:     ps,
+		topics: make(map[string]*pubsub.Topic),
+		// 与gossipsub的消息去重时间保持一致
+		seen:    cache.New(pubsub.TimeCacheDuration, pubsub.TimeCacheDuration),
+		relayed: cache.New(pubsub.TimeCacheDuration, pubsub.TimeCacheDuration),
+	}
+	return g, nil
+}
+
+// join 加入链的广播topic并开始接收消息，已加入时直接返回
+func (g *gossip) join(bcname string) (*pubsub.Topic, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if topic, ok := g.topics[bcname]; ok {
+		return topic, nil
+	}
+
+	name := gossipTopic(bcname)
+	topic, err := g.ps.Join(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.ps.RegisterTopicValidator(name, g.validate); err != nil {
+		topic.Close()
+		return nil, err
+	}
+	sub, err := topic.Subscribe()
+	if err != nil {
+		g.ps.UnregisterTopicValidator(name)
+		topic.Close()
+		return nil, err
+	}
+
+	g.log.Info("join gossip topic", "bcname", bcname, "topic", name)
+	g.topics[bcname] = topic
+	go g.recv(sub)
+	return topic, nil
+}
+
+// validate 校验通过的消息才会被gossipsub继续转发，解析后的消息保存在ValidatorData中
+// 交易和区块的内容由订阅该消息的处理器校验，本节点没有订阅的消息不转发
+func (g *gossip) validate(ctx context.Context, from peer.ID, pmsg *pubsub.Message) pubsub.ValidationResult {
+	msg, err := decodeGossipMessage(pmsg.Message)
+	if err != nil {
+		g.log.Trace("gossip message decode error", "from", from, "error", err)
+		return pubsub.ValidationReject
+	}
+	header := msg.GetHeader()
+	if !gossipMessageTypes[header.GetType()] || gossipTopic(header.GetBcname()) != pmsg.GetTopic() ||
+		!p2p.VerifyChecksum(msg) {
+		g.log.Trace("gossip message illegal", "from", from, "type", header.GetType(),
+			"bcname", header.GetBcname(), "topic", pmsg.GetTopic())
+		return pubsub.ValidationReject
+	}
+	if g.srv.scores.IsBanned(from.Pretty()) || g.srv.scores.IsBanned(header.GetFrom()) {
+		return pubsub.ValidationIgnore
+	}
+	// 本节点发布的消息，内容已由处理器校验
+	if from == g.srv.id {
+		pmsg.ValidatorData = msg
+		return pubsub.ValidationAccept
+	}
+	if err := g.srv.dispatcher.Validate(msg); err != nil {
+		g.log.Trace("gossip message validate error", "from", from, "type", header.GetType(),
+			"bcname", header.GetBcname(), "error", err)
+		if err == p2p.ErrNotRegister {
+			return pubsub.ValidationIgnore
+		}
+		return pubsub.ValidationReject
+	}
+
+	pmsg.ValidatorData = msg
+	return pubsub.ValidationAccept
+}
+
+// recv 将topic中收到的消息交给dispatcher处理，直到节点停止
+func (g *gossip) recv(sub *pubsub.Subscription) {
+	defer sub.Cancel()
+	for {
+		pmsg, err := sub.Next(g.ctx)
+		if err != nil {
+			g.log.Trace("gossip subscription closed", "topic", sub.Topic(), "error", err)
+			return
+		}
+		// 本节点发布的消息
+		if pmsg.ReceivedFrom == g.srv.id {
+			continue
+		}
+		msg, ok := pmsg.ValidatorData.(*pb.XuperMessage)
+		if !ok {
+			continue
+		}
+
+		g.relayed.Set(gossipKey(msg), true, cache.DefaultExpiration)
+		g.srv.HandleMessage(gossipStream{}, msg)
+	}
+}
+
+// isRelayed 判断消息是否已在mesh中
+func (g *gossip) isRelayed(msg *pb.XuperMessage) bool {
+	_, ok := g.relayed.Get(gossipKey(msg))
+	return ok
+}
+
+// markSeen 标记广播消息已处理，消息第一次收到时返回true
+func (g *gossip) markSeen(msg *pb.XuperMessage) bool {
+	return g.seen.Add(gossipKey(msg), true, cache.DefaultExpiration) == nil
+}
+
+// publish 在链的topic中广播消息，topic中还没有其他节点时返回ErrGossipNoPeer
+func (g *gossip) publish(msg *pb.XuperMessage) error {
+	topic, err := g.join(msg.GetHeader().GetBcname())
+	if err != nil {
+		return err
+	}
+	if len(topic.ListPeers()) <= 0 {
+		return ErrGossipNoPeer
+	}
+
+	msg.Header.From = g.srv.PeerID()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := topic.Publish(g.ctx, data); err != nil {
+		return err
+	}
+	g.relayed.Set(gossipKey(msg), true, cache.DefaultExpiration)
+	return nil
+}
+
+func (g *gossip) stop() {
+	g.cancel()
+}
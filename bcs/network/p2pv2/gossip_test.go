@@ -0,0 +1,221 @@
+package p2pv2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	nctx "github.com/xuperchain/xupercore/kernel/network/context"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/timer"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// newGossipNode 创建只包含gossip所需组件的节点，监听随机端口
+func newGossipNode(t *testing.T) *P2PServerV2 {
+	ecfg, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := nctx.NewNetCtx(ecfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.P2PConf.EnableGossipsub = true
+
+	ho, err := libp2p.New(ctx, libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	if err != nil {
+		t.Fatal("create host error", err)
+	}
+	p := &P2PServerV2{
+		ctx:        ctx,
+		log:        ctx.GetLog(),
+		config:     ctx.P2PConf,
+		id:         ho.ID(),
+		host:       ho,
+		dispatcher: p2p.NewDispatcher(ctx),
+		scores:     p2p.NewScoreBoard(ctx.P2PConf, func(string) {}),
+	}
+	if p.gossip, err = newGossip(p); err != nil {
+		t.Fatal("create gossip error", err)
+	}
+	return p
+}
+
+func stopGossipNode(p *P2PServerV2) {
+	p.gossip.stop()
+	p.host.Close()
+}
+
+func TestIsGossipMessage(t *testing.T) {
+	msg := p2p.NewMessage(pb.XuperMessage_SENDBLOCK, nil, p2p.WithBCName("xuper"))
+	if !isGossipMessage(msg, p2p.Apply(nil)) {
+		t.Error("broadcast message should be gossiped")
+	}
+	if !isGossipMessage(msg, p2p.Apply([]p2p.OptionFunc{p2p.WithFilter([]p2p.FilterStrategy{p2p.NearestBucketStrategy})})) {
+		t.Error("broadcast message with filter should be gossiped")
+	}
+	if isGossipMessage(msg, p2p.Apply([]p2p.OptionFunc{p2p.WithPeerIDs([]string{"peer"})})) {
+		t.Error("message with target peers should not be gossiped")
+	}
+
+	msg = p2p.NewMessage(pb.XuperMessage_GET_BLOCK, nil, p2p.WithBCName("xuper"))
+	if isGossipMessage(msg, p2p.Apply(nil)) {
+		t.Error("request message should not be gossiped")
+	}
+	msg = p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	msg.Header.Bcname = ""
+	if isGossipMessage(msg, p2p.Apply(nil)) {
+		t.Error("message without bcname should not be gossiped")
+	}
+}
+
+func TestGossipMessageID(t *testing.T) {
+	msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil, p2p.WithBCName("xuper"))
+	msg.Header.From = "forged"
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	author, err := peer.Decode("QmQKp8pLWSgV4JiGjuULKV1JsdpxUtnDEUMP8sGaaUbwVL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pmsg := &pubsubpb.Message{Data: data, From: []byte(author)}
+	if id := gossipMessageID(pmsg); id != gossipKey(msg) {
+		t.Error("gossip message id should equal gossip key", id)
+	}
+
+	// 其他节点再次广播的同一内容，来源和logid不同，消息id相同
+	relay := p2p.NewMessage(pb.XuperMessage_POSTTX, nil, p2p.WithBCName("xuper"))
+	relay.Header.From = "relay"
+	if gossipKey(relay) != gossipKey(msg) {
+		t.Error("same content should have same gossip key")
+	}
+	other := p2p.NewMessage(pb.XuperMessage_POSTTX, nil, p2p.WithBCName("other"))
+	if gossipKey(other) == gossipKey(msg) {
+		t.Error("different chain should have different gossip key")
+	}
+
+	pmsg.Data = []byte("illegal")
+	if id := gossipMessageID(pmsg); id == "" {
+		t.Error("illegal message should use default message id")
+	}
+}
+
+func TestGossipValidate(t *testing.T) {
+	mock.InitLogForTest()
+	node := newGossipNode(t)
+	defer stopGossipNode(node)
+
+	remote, err := peer.Decode("QmQKp8pLWSgV4JiGjuULKV1JsdpxUtnDEUMP8sGaaUbwVL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPubsubMessage := func(bcname string) *pubsub.Message {
+		msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil, p2p.WithBCName(bcname))
+		data, _ := proto.Marshal(msg)
+		topic := gossipTopic(bcname)
+		return &pubsub.Message{Message: &pubsubpb.Message{
+			Data:  data,
+			From:  []byte(remote),
+			Topic: &topic,
+		}}
+	}
+
+	// 本节点没有订阅的消息不转发
+	if res := node.gossip.validate(context.Background(), remote, newPubsubMessage("xuper")); res != pubsub.ValidationIgnore {
+		t.Error("message without subscriber should be ignored", res)
+	}
+
+	validator := func(msg *pb.XuperMessage) error {
+		if msg.GetHeader().GetBcname() != "xuper" {
+			return errors.New("invalid")
+		}
+		return nil
+	}
+	ch := make(chan *pb.XuperMessage, 8)
+	node.Register(p2p.NewSubscriber(node.ctx, pb.XuperMessage_POSTTX, ch, p2p.WithValidator(validator)))
+	if res := node.gossip.validate(context.Background(), remote, newPubsubMessage("xuper")); res != pubsub.ValidationAccept {
+		t.Error("valid message should be accepted", res)
+	}
+	if res := node.gossip.validate(context.Background(), remote, newPubsubMessage("other")); res != pubsub.ValidationReject {
+		t.Error("invalid message should be rejected", res)
+	}
+}
+
+func TestGossipSeen(t *testing.T) {
+	mock.InitLogForTest()
+	node := newGossipNode(t)
+	defer stopGossipNode(node)
+
+	ch := make(chan *pb.XuperMessage, 8)
+	node.Register(p2p.NewSubscriber(node.ctx, pb.XuperMessage_POSTTX, ch))
+
+	// 同一内容先后经stream和gossipsub收到，来源和logid不同，只处理一次
+	msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil, p2p.WithBCName("xuper"))
+	msg.Header.From = "stream"
+	node.HandleMessage(gossipStream{}, msg)
+	relay := p2p.NewMessage(pb.XuperMessage_POSTTX, nil, p2p.WithBCName("xuper"))
+	relay.Header.From = "gossip"
+	node.HandleMessage(gossipStream{}, relay)
+
+	if len(ch) != 1 {
+		t.Error("same content should be handled once", len(ch))
+	}
+}
+
+func TestGossipBroadcast(t *testing.T) {
+	mock.InitLogForTest()
+	node1 := newGossipNode(t)
+	node2 := newGossipNode(t)
+	defer stopGossipNode(node1)
+	defer stopGossipNode(node2)
+
+	ch := make(chan *pb.XuperMessage, 8)
+	node2.Register(p2p.NewSubscriber(node2.ctx, pb.XuperMessage_POSTTX, ch))
+
+	addrInfo := peer.AddrInfo{ID: node2.host.ID(), Addrs: node2.host.Addrs()}
+	if err := node1.host.Connect(context.Background(), addrInfo); err != nil {
+		t.Fatal("connect error", err)
+	}
+	topic1, _ := node1.gossip.join("xuper")
+	topic2, _ := node2.gossip.join("xuper")
+	for i := 0; i < 50 && (len(topic1.ListPeers()) == 0 || len(topic2.ListPeers()) == 0); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	ctx := &xctx.BaseCtx{XLog: node1.log, Timer: timer.NewXTimer()}
+	msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil, p2p.WithBCName("xuper"))
+	if err := node1.SendMessage(ctx, msg); err != nil {
+		t.Fatal("send message error", err)
+	}
+
+	var recv *pb.XuperMessage
+	select {
+	case recv = <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("gossip message not received")
+	}
+	if recv.GetHeader().GetFrom() != node1.PeerID() {
+		t.Error("gossip message from error", recv.GetHeader().GetFrom())
+	}
+
+	// 处理器再次广播经gossipsub收到的消息时直接忽略，不会通过stream发送
+	if !node2.gossip.isRelayed(recv) {
+		t.Fatal("received message should be marked relayed")
+	}
+	if err := node2.SendMessage(ctx, recv); err != nil {
+		t.Error("relay gossip message error", err)
+	}
+}
@@ -63,10 +63,12 @@ var (
 	ErrBindAddress      = errors.New("bind address error")
 	ErrCreateKadDht     = errors.New("create kad dht error")
 	ErrCreateStreamPool = errors.New("create stream pool error")
+	ErrCreateGossip     = errors.New("create gossipsub error")
 	ErrCreateBootStrap  = errors.New("create bootstrap error pool error")
 	ErrConnectBootStrap = errors.New("error to connect to all bootstrap")
 	ErrLoadAccount      = errors.New("load account error")
 	ErrConnect          = errors.New("connect all boot and static peer error")
+	ErrPeerBanned       = errors.New("peer is banned")
 )
 
 // P2PServerV2 is the node in the network
@@ -80,6 +82,9 @@ type P2PServerV2 struct {
 	kdht       *dht.IpfsDHT
 	streamPool *StreamPool
 	dispatcher p2p.Dispatcher
+	scores     *p2p.ScoreBoard
+	// gossip 开启enableGossipsub时用于广播区块和交易，否则为nil
+	gossip *gossip
 
 	cancel context.CancelFunc
 
@@ -156,6 +161,9 @@ func (p *P2PServerV2) Init(ctx *netCtx.NetCtx) error {
 	// dispatcher
 	p.dispatcher = p2p.NewDispatcher(ctx)
 
+	// peer score
+	p.scores = p2p.NewScoreBoard(cfg, p.banPeer)
+
 	p.streamPool, err = NewStreamPool(ctx, p)
 	if err != nil {
 		return ErrCreateStreamPool
@@ -167,6 +175,13 @@ func (p *P2PServerV2) Init(ctx *netCtx.NetCtx) error {
 	// set broadcast peers limitation
 	MaxBroadCastPeers = cfg.MaxBroadcastPeers
 
+	if cfg.EnableGossipsub {
+		if p.gossip, err = newGossip(p); err != nil {
+			p.log.Error("create gossipsub error", "error", err)
+			return ErrCreateGossip
+		}
+	}
+
 	if err := p.connect(); err != nil {
 		p.log.Error("connect all boot and static peer error")
 		return ErrConnect
@@ -286,6 +301,11 @@ func (p *P2PServerV2) connect() error {
 }
 
 func (p *P2PServerV2) streamHandler(netStream network.Stream) {
+	if p.scores.IsBanned(netStream.Conn().RemotePeer().Pretty()) {
+		p.log.Trace("refuse stream from banned peer", "peerID", netStream.Conn().RemotePeer())
+		netStream.Reset()
+		return
+	}
 	if _, err := p.streamPool.NewStream(p.ctx, netStream); err != nil {
 		p.log.Warn("new stream error")
 	}
@@ -294,6 +314,9 @@ func (p *P2PServerV2) streamHandler(netStream network.Stream) {
 // Stop stop the node
 func (p *P2PServerV2) Stop() {
 	p.log.Info("StopP2PServer")
+	if p.gossip != nil {
+		p.gossip.stop()
+	}
 	if err := p.kdht.Close(); err != nil {
 		p.log.Warn("close P2P kdht error", "error", err)
 	}
@@ -330,6 +353,11 @@ func (p *P2PServerV2) HandleMessage(stream p2p.Stream, msg *pb.XuperMessage) err
 		return nil
 	}
 
+	// 同一条广播消息可能先后经stream和gossipsub收到，只处理一次
+	if p.gossip != nil && gossipMessageTypes[msg.GetHeader().GetType()] && !p.gossip.markSeen(msg) {
+		return nil
+	}
+
 	if p.ctx.EnvCfg.MetricSwitch {
 		tm := time.Now()
 		defer func() {
@@ -383,6 +411,33 @@ func (p *P2PServerV2) PeerInfo() pb.PeerInfo {
 	return peerInfo
 }
 
+// ReportPeer 上报节点行为，评分过低的节点会被断开并封禁
+func (p *P2PServerV2) ReportPeer(peerID string, event p2p.PeerEvent) {
+	p.log.Debug("report peer", "peerID", peerID, "event", event)
+	p.scores.Report(peerID, event)
+}
+
+// PeerScores 返回节点评分
+func (p *P2PServerV2) PeerScores() []*p2p.PeerScore {
+	return p.scores.Scores()
+}
+
+// banPeer 断开被封禁节点的连接，封禁期间拒绝该节点的连接和消息
+func (p *P2PServerV2) banPeer(peerID string) {
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		p.log.Warn("ban peer decode peer id error", "peerID", peerID, "error", err)
+		return
+	}
+
+	p.log.Warn("peer score too low, ban peer", "peerID", peerID, "duration", p.config.BanDuration)
+	p.streamPool.DelPeer(id)
+	p.kdht.RoutingTable().RemovePeer(id)
+	if err := p.host.Network().ClosePeer(id); err != nil {
+		p.log.Warn("close banned peer error", "peerID", peerID, "error", err)
+	}
+}
+
 func (p *P2PServerV2) getMultiAddr(peerID peer.ID, addrs []multiaddr.Multiaddr) string {
 	peerInfo := &peer.AddrInfo{
 		ID:    peerID,
@@ -147,6 +147,15 @@ func (s *Stream) Recv() {
 			s.reset()
 			return
 		}
+		if s.srv.scores.IsBanned(s.id.Pretty()) {
+			s.log.Trace("Stream Recv from banned peer, reset", "peerID", s.id.Pretty())
+			s.reset()
+			return
+		}
+		// 使用连接对端的节点id，避免伪造来源导致其他节点被扣分
+		if msg.GetHeader() != nil {
+			msg.Header.From = s.id.Pretty()
+		}
 		err = s.srv.HandleMessage(s, msg)
 		if err != nil {
 			s.reset()
@@ -230,10 +239,14 @@ func (s *Stream) waitResponse(ctx xctx.XContext, msg *pb.XuperMessage,
 			s.log.Warn("waitResponse ctx done", "log_id", msg.GetHeader().GetLogid(),
 				"type", msg.GetHeader().GetType(), "pid", s.id.Pretty(), "error", timeoutCtx.Err())
 			ctx.GetTimer().Mark("wait")
+			if timeoutCtx.Err() == context.DeadlineExceeded {
+				s.srv.ReportPeer(s.id.Pretty(), p2p.PeerEventTimeout)
+			}
 			return nil, timeoutCtx.Err()
 		case resp := <-observerCh:
 			if p2p.VerifyMessageType(msg, resp, s.id.Pretty()) {
 				ctx.GetTimer().Mark("read")
+				s.srv.ReportPeer(s.id.Pretty(), p2p.PeerEventUsefulResponse)
 				return resp, nil
 			}
 
@@ -50,6 +50,10 @@ func NewStreamPool(ctx *nctx.NetCtx, srv *P2PServerV2) (*StreamPool, error) {
 
 // Get will probe and return a stream
 func (sp *StreamPool) Get(ctx xctx.XContext, peerId peer.ID) (*Stream, error) {
+	if sp.srv.scores.IsBanned(peerId.Pretty()) {
+		return nil, ErrPeerBanned
+	}
+
 	if v, ok := sp.streams.Get(peerId.Pretty()); ok {
 		if stream, ok := v.(*Stream); ok {
 			if stream.Valid() {
@@ -132,3 +136,12 @@ func (sp *StreamPool) DelStream(stream *Stream) error {
 	sp.limit.DelStream(stream.MultiAddr().String())
 	return nil
 }
+
+// DelPeer delete the stream of given peer
+func (sp *StreamPool) DelPeer(peerId peer.ID) {
+	if v, ok := sp.streams.Get(peerId.Pretty()); ok {
+		if stream, ok := v.(*Stream); ok {
+			sp.DelStream(stream)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	// import要使用的内核核心组件驱动
 	_ "github.com/xuperchain/xupercore/bcs/consensus/pow"
+	_ "github.com/xuperchain/xupercore/bcs/consensus/raft"
 	_ "github.com/xuperchain/xupercore/bcs/consensus/single"
 	_ "github.com/xuperchain/xupercore/bcs/consensus/tdpos"
 	_ "github.com/xuperchain/xupercore/bcs/consensus/xpoa"
@@ -28,6 +29,7 @@ import (
 	_ "github.com/xuperchain/xupercore/kernel/contract/manager"
 	_ "github.com/xuperchain/xupercore/lib/crypto/client"
 	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/pebble"
 
 	"github.com/spf13/cobra"
 )
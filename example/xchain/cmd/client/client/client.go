@@ -10,6 +10,7 @@ import (
 	xdef "github.com/xuperchain/xupercore/example/xchain/common/def"
 	"github.com/xuperchain/xupercore/example/xchain/common/xchainpb"
 	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
 
 	"google.golang.org/grpc"
 )
@@ -51,8 +52,24 @@ func (t *XchainClient) SubmitTx(tx *xldgpb.Transaction) (*xchainpb.BaseResp, err
 	return resp, nil
 }
 
-func (t *XchainClient) PreExec() (*xchainpb.PreExecResp, error) {
-	return nil, fmt.Errorf("not impl")
+func (t *XchainClient) PreExec(reqs []*protos.InvokeRequest) (*xchainpb.PreExecResp, error) {
+	req := &xchainpb.PreExecReq{
+		Header:   t.genReqHeader(),
+		Bcname:   global.GFlagBCName,
+		Requests: reqs,
+	}
+
+	ctx := context.TODO()
+	resp, err := t.xclient.PreExec(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetHeader().GetErrCode() != 0 {
+		return nil, fmt.Errorf("ErrCode:%d ErrMsg:%s LogId:%s TraceId:%s", resp.GetHeader().GetErrCode(),
+			resp.GetHeader().GetErrMsg(), resp.GetHeader().GetLogId(), resp.GetHeader().GetTraceId())
+	}
+
+	return resp, nil
 }
 
 func (t *XchainClient) SelectUtxo(need *big.Int) (*xchainpb.SelectUtxoResp, error) {
@@ -27,6 +27,8 @@ func GetChainCmd() *ChainCmd {
 	chainCmdIns.Cmd.AddCommand(chaincmd.GetChainStatusCmd().GetCmd())
 	// create chain
 	chainCmdIns.Cmd.AddCommand(chaincmd.GetCreateChainCmd().GetCmd())
+	// check consensus upgrade proposal
+	chainCmdIns.Cmd.AddCommand(chaincmd.GetCheckConsensusCmd().GetCmd())
 
 	return chainCmdIns
 }
@@ -0,0 +1,96 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strconv"
+
+	"github.com/xuperchain/xupercore/example/xchain/cmd/client/client"
+	"github.com/xuperchain/xupercore/example/xchain/cmd/client/common/global"
+	xdef "github.com/xuperchain/xupercore/example/xchain/common/def"
+	"github.com/xuperchain/xupercore/protos"
+
+	"github.com/spf13/cobra"
+)
+
+type CheckConsensusCmd struct {
+	global.BaseCmd
+	// 共识升级提案的trigger高度
+	Height int64
+	// 待升级的共识配置文件，格式为{"name":共识名称, "config":共识配置}
+	Desc string
+}
+
+func GetCheckConsensusCmd() *CheckConsensusCmd {
+	checkConsensusCmdIns := new(CheckConsensusCmd)
+
+	checkConsensusCmdIns.Cmd = &cobra.Command{
+		Use:           "check-consensus",
+		Short:         "dry-run a consensus upgrade proposal.",
+		Example:       xdef.CmdLineName + " chain check-consensus --height [trigger height] --desc [consensus.json]",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkConsensusCmdIns.checkConsensus()
+		},
+	}
+
+	// 设置命令行参数并绑定变量
+	checkConsensusCmdIns.Cmd.Flags().Int64Var(&checkConsensusCmdIns.Height, "height", 0, "trigger height of the proposal")
+	checkConsensusCmdIns.Cmd.Flags().StringVar(&checkConsensusCmdIns.Desc, "desc", "", "consensus config file path")
+
+	return checkConsensusCmdIns
+}
+
+func (t *CheckConsensusCmd) checkConsensus() error {
+	desc, err := ioutil.ReadFile(t.Desc)
+	if err != nil {
+		log.Printf("read consensus config failed.desc:%s err:%v\n", t.Desc, err)
+		return fmt.Errorf("read consensus config failed")
+	}
+
+	xcli, err := client.NewXchainClient()
+	if err != nil {
+		log.Printf("grpc dial failed.err:%v\n", err)
+		return fmt.Errorf("grpc dial failed")
+	}
+
+	// 与共识升级提案调用updateConsensus的参数一致
+	req := &protos.InvokeRequest{
+		ModuleName:   "xkernel",
+		ContractName: "$consensus",
+		MethodName:   "checkUpdateConsensus",
+		Args: map[string][]byte{
+			"height": []byte(strconv.FormatInt(t.Height, 10)),
+			"args":   desc,
+		},
+	}
+	resp, err := xcli.PreExec([]*protos.InvokeRequest{req})
+	if err != nil {
+		log.Printf("pre exec failed.err:%v\n", err)
+		return fmt.Errorf("pre exec failed")
+	}
+	responses := resp.GetResponse().GetResponses()
+	if len(responses) == 0 {
+		return fmt.Errorf("empty check result")
+	}
+	body := responses[len(responses)-1].GetBody()
+
+	var output bytes.Buffer
+	if err := json.Indent(&output, body, "", "  "); err != nil {
+		log.Printf("json indent check result failed.err:%v\n", err)
+		return fmt.Errorf("json indent check result failed")
+	}
+	fmt.Println(output.String())
+
+	var result struct {
+		Pass bool `json:"pass"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || !result.Pass {
+		return fmt.Errorf("consensus upgrade check failed")
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/xuperchain/xupercore/example/xchain/common/xchainpb"
 	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
 	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 	"github.com/xuperchain/xupercore/lib/utils"
 	"github.com/xuperchain/xupercore/protos"
 )
@@ -78,13 +79,23 @@ func (t *TransferTxCmd) transfer() error {
 		return ErrNegativeAmount
 	}
 
+	// 签名和txid使用链配置的哈希算法
+	status, err := xcli.QueryChainStatus()
+	if err != nil {
+		return fmt.Errorf("query chain status failed.err:%v", err)
+	}
+	hasher, err := hash.GetHasher(status.GetHashType())
+	if err != nil {
+		return err
+	}
+
 	resp, err := xcli.SelectUtxo(amount)
 	if err != nil {
 		fmt.Sprintf("select utxo failed.err:%v", err)
 		return fmt.Errorf("select utxo failed")
 	}
 	//构造交易
-	tx, err := t.generateTx(resp, amount)
+	tx, err := t.generateTx(resp, amount, hasher)
 	if err != nil {
 		fmt.Sprintf("generate tx failed.err:%v", err)
 		return fmt.Errorf("generate tx failed")
@@ -100,7 +111,8 @@ func (t *TransferTxCmd) transfer() error {
 	return nil
 }
 
-func (t *TransferTxCmd) generateTx(utxoRes *xchainpb.SelectUtxoResp, amount *big.Int) (*xldgpb.Transaction, error) {
+func (t *TransferTxCmd) generateTx(utxoRes *xchainpb.SelectUtxoResp, amount *big.Int,
+	hasher *hash.Hasher) (*xldgpb.Transaction, error) {
 	addr, err := global.LoadAccount(global.GFlagCrypto, global.GFlagKeys)
 	if err != nil {
 		return nil, fmt.Errorf("load account info failed.KeyPath:%s Err:%v", global.GFlagKeys, err)
@@ -137,7 +149,7 @@ func (t *TransferTxCmd) generateTx(utxoRes *xchainpb.SelectUtxoResp, amount *big
 	tx.AuthRequire = genAuthRequire(addr.Address)
 
 	// 签名和生成txid
-	signTx, err := txhash.ProcessSignTx(cryptoClient, tx, []byte(addr.PrivateKeyStr))
+	signTx, err := txhash.ProcessSignTxWithHasher(cryptoClient, tx, []byte(addr.PrivateKeyStr), hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -146,11 +158,11 @@ func (t *TransferTxCmd) generateTx(utxoRes *xchainpb.SelectUtxoResp, amount *big
 		Sign:      signTx,
 	}
 	tx.InitiatorSigns = append(tx.InitiatorSigns, signInfo)
-	tx.AuthRequireSigns, err = genAuthRequireSigns(cryptoClient, tx, addr.PrivateKeyStr, addr.PublicKeyStr)
+	tx.AuthRequireSigns, err = genAuthRequireSigns(cryptoClient, tx, addr.PrivateKeyStr, addr.PublicKeyStr, hasher)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to genAuthRequireSigns %s", err)
 	}
-	tx.Txid, err = txhash.MakeTransactionID(tx)
+	tx.Txid, err = txhash.MakeTransactionIDWithHasher(tx, hasher)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to gen txid %s", err)
 	}
@@ -185,9 +197,10 @@ func (t *TransferTxCmd) genTxInputs(utxoRes *xchainpb.SelectUtxoResp, totalNeed
 	return txTxInputs, txOutput, nil
 }
 
-func genAuthRequireSigns(cryptoClient cryptoBase.CryptoClient, tx *xldgpb.Transaction, initScrkey, initPubkey string) ([]*protos.SignatureInfo, error) {
+func genAuthRequireSigns(cryptoClient cryptoBase.CryptoClient, tx *xldgpb.Transaction, initScrkey, initPubkey string,
+	hasher *hash.Hasher) ([]*protos.SignatureInfo, error) {
 	authRequireSigns := []*protos.SignatureInfo{}
-	signTx, err := txhash.ProcessSignTx(cryptoClient, tx, []byte(initScrkey))
+	signTx, err := txhash.ProcessSignTxWithHasher(cryptoClient, tx, []byte(initScrkey), hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 
+	"github.com/xuperchain/xupercore/example/xchain/common/def"
 	"github.com/xuperchain/xupercore/lib/utils"
 
 	"github.com/spf13/viper"
@@ -16,6 +17,14 @@ type ServConf struct {
 	WriteBufSize       int   `yaml:"writeBufSize,omitempty"`
 	InitWindowSize     int32 `yaml:"initWindowSize,omitempty"`
 	InitConnWindowSize int32 `yaml:"initConnWindowSize,omitempty"`
+	// eth json-rpc server listen port, 0 means disable
+	EthRpcPort int `yaml:"ethRpcPort,omitempty"`
+	// chain served by eth json-rpc
+	EthBCName string `yaml:"ethBCName,omitempty"`
+	// eth addresses whose raw transactions can be relayed by the node account, empty means relaying disabled
+	EthRelayAllowList []string `yaml:"ethRelayAllowList,omitempty"`
+	// max raw transactions relayed by the node account per minute
+	EthRelayPerMinute int `yaml:"ethRelayPerMinute,omitempty"`
 }
 
 func LoadServConf(cfgFile string) (*ServConf, error) {
@@ -36,6 +45,9 @@ func GetDefServConf() *ServConf {
 		WriteBufSize:       32 << 10,
 		InitWindowSize:     128 << 10,
 		InitConnWindowSize: 64 << 10,
+		EthRpcPort:         0,
+		EthBCName:          def.DefChainName,
+		EthRelayPerMinute:  60,
 	}
 }
 
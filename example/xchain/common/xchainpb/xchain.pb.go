@@ -829,14 +829,16 @@ func (m *QueryChainStatusReq) GetNeedBranchBlock() bool {
 }
 
 type QueryChainStatusResp struct {
-	Header               *RespHeader        `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	Bcname               string             `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
-	LedgerMeta           *xldgpb.LedgerMeta `protobuf:"bytes,3,opt,name=ledgerMeta,proto3" json:"ledgerMeta,omitempty"`
-	UtxoMeta             *xldgpb.UtxoMeta   `protobuf:"bytes,4,opt,name=utxoMeta,proto3" json:"utxoMeta,omitempty"`
-	BranchBlockId        []string           `protobuf:"bytes,5,rep,name=branchBlockId,proto3" json:"branchBlockId,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Header        *RespHeader        `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Bcname        string             `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	LedgerMeta    *xldgpb.LedgerMeta `protobuf:"bytes,3,opt,name=ledgerMeta,proto3" json:"ledgerMeta,omitempty"`
+	UtxoMeta      *xldgpb.UtxoMeta   `protobuf:"bytes,4,opt,name=utxoMeta,proto3" json:"utxoMeta,omitempty"`
+	BranchBlockId []string           `protobuf:"bytes,5,rep,name=branchBlockId,proto3" json:"branchBlockId,omitempty"`
+	// 链配置的区块id、交易id和merkle树哈希算法
+	HashType             string   `protobuf:"bytes,6,opt,name=hashType,proto3" json:"hashType,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *QueryChainStatusResp) Reset()         { *m = QueryChainStatusResp{} }
@@ -899,6 +901,13 @@ func (m *QueryChainStatusResp) GetBranchBlockId() []string {
 	return nil
 }
 
+func (m *QueryChainStatusResp) GetHashType() string {
+	if m != nil {
+		return m.HashType
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ReqHeader)(nil), "xchainpb.ReqHeader")
 	proto.RegisterType((*RespHeader)(nil), "xchainpb.RespHeader")
@@ -920,62 +929,63 @@ func init() {
 func init() { proto.RegisterFile("xchain.proto", fileDescriptor_db0991b9525664ca) }
 
 var fileDescriptor_db0991b9525664ca = []byte{
-	// 876 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0xee, 0xc6, 0x8d, 0xbd, 0x3e, 0x4e, 0x42, 0x99, 0xd6, 0xed, 0xd6, 0xfc, 0x59, 0x0b, 0x17,
-	0x96, 0x52, 0x39, 0x8a, 0x11, 0xd0, 0x3b, 0x94, 0x44, 0x48, 0x58, 0x4a, 0x2b, 0x98, 0x14, 0x89,
-	0xbb, 0x68, 0xbc, 0x7b, 0xb0, 0x57, 0x59, 0xcf, 0x38, 0x33, 0xe3, 0x6a, 0xb9, 0x47, 0x42, 0x82,
-	0x1b, 0x2e, 0x79, 0x04, 0xee, 0x78, 0x09, 0x24, 0x5e, 0x84, 0x07, 0x41, 0x33, 0xfb, 0x37, 0x59,
-	0xd2, 0x08, 0x4b, 0xee, 0xd5, 0xee, 0xf9, 0x3f, 0xe7, 0x3b, 0x3f, 0x03, 0x7b, 0x59, 0xb4, 0x60,
-	0x09, 0x1f, 0xaf, 0xa4, 0xd0, 0x82, 0xf8, 0x39, 0xb5, 0x9a, 0x0d, 0x8e, 0xb3, 0xf5, 0x0a, 0x65,
-	0x24, 0x24, 0x1e, 0xcd, 0x22, 0x75, 0x94, 0x62, 0x3c, 0x47, 0x79, 0x94, 0x55, 0xdf, 0x78, 0xbe,
-	0x9a, 0x95, 0x64, 0x6e, 0x3c, 0xf8, 0xa8, 0x36, 0xb1, 0x0c, 0x75, 0x14, 0x09, 0xae, 0x25, 0x8b,
-	0x74, 0xae, 0x10, 0x7e, 0x09, 0x5d, 0x8a, 0xd7, 0x5f, 0x23, 0x8b, 0x51, 0x92, 0x3e, 0xb4, 0x53,
-	0x31, 0xbf, 0x4c, 0xe2, 0xc0, 0x1b, 0x7a, 0xa3, 0x2e, 0xdd, 0x4d, 0xc5, 0x7c, 0x1a, 0x93, 0xf7,
-	0xa0, 0xab, 0x30, 0xfd, 0xe1, 0x92, 0xb3, 0x25, 0x06, 0x3b, 0x56, 0xe2, 0x1b, 0xc6, 0x4b, 0xb6,
-	0xc4, 0x50, 0x02, 0x50, 0x54, 0xab, 0xbb, 0x3d, 0x3c, 0x05, 0x1f, 0xa5, 0xbc, 0x8c, 0x44, 0x9c,
-	0x3b, 0x68, 0xd1, 0x0e, 0x4a, 0x79, 0x26, 0x62, 0x24, 0x4f, 0xc0, 0xfc, 0x5e, 0x2e, 0xd5, 0x3c,
-	0x68, 0x59, 0x93, 0x36, 0x4a, 0xf9, 0x42, 0xcd, 0x8d, 0x8d, 0x49, 0x14, 0x8d, 0xb3, 0xfb, 0x56,
-	0xd2, 0xb1, 0xf4, 0x34, 0x0e, 0x3f, 0x87, 0xce, 0x29, 0x53, 0x48, 0xf1, 0x9a, 0x1c, 0x42, 0x7b,
-	0x61, 0x43, 0xdb, 0x80, 0xbd, 0xc9, 0xc3, 0x71, 0x09, 0xd7, 0xb8, 0xaa, 0x8b, 0x16, 0x2a, 0xe1,
-	0x73, 0xf0, 0x73, 0x3b, 0xb5, 0x22, 0xcf, 0x1a, 0x86, 0x8f, 0x5c, 0xc3, 0xb2, 0x9e, 0xca, 0xf2,
-	0x57, 0x0f, 0x7a, 0x17, 0xeb, 0xd9, 0x32, 0xd1, 0xaf, 0xb2, 0x4d, 0xc3, 0x92, 0xc7, 0xd0, 0x9e,
-	0x45, 0x0e, 0x78, 0x05, 0x45, 0x08, 0xdc, 0xd7, 0x59, 0x12, 0xdb, 0xba, 0xf7, 0xa8, 0xfd, 0x27,
-	0x1f, 0xc3, 0x8e, 0xce, 0x6c, 0xbd, 0xd6, 0xa9, 0xed, 0xe9, 0xf8, 0x95, 0x64, 0x5c, 0xb1, 0x48,
-	0x27, 0x82, 0xd3, 0x1d, 0x9d, 0x85, 0x7f, 0x79, 0x00, 0xdf, 0x48, 0xfc, 0x2a, 0xc3, 0x68, 0x6b,
-	0xc9, 0x1c, 0x83, 0x2f, 0xf1, 0x7a, 0x8d, 0x4a, 0xab, 0xa0, 0x35, 0x6c, 0x8d, 0x7a, 0x93, 0x7e,
-	0x3e, 0x22, 0x6a, 0x3c, 0xe5, 0xaf, 0xc5, 0x95, 0x41, 0xdb, 0x48, 0x69, 0xa5, 0x46, 0xde, 0x87,
-	0x6e, 0xc2, 0x13, 0x9d, 0x30, 0x2d, 0x64, 0xd1, 0xa2, 0x9a, 0x41, 0x86, 0xd0, 0x63, 0x6b, 0xbd,
-	0x30, 0x66, 0x89, 0xc4, 0x60, 0x77, 0xd8, 0x1a, 0x75, 0xa9, 0xcb, 0x0a, 0x7f, 0xf6, 0xa0, 0x57,
-	0x95, 0xb1, 0x69, 0x4b, 0xde, 0x58, 0xc8, 0xc4, 0x14, 0xa2, 0x56, 0x82, 0x2b, 0xb4, 0xc8, 0xf6,
-	0x26, 0x8f, 0x9b, 0x85, 0xe4, 0x52, 0x5a, 0xe9, 0x85, 0x7f, 0x78, 0xb0, 0x7f, 0x81, 0x29, 0x46,
-	0xfa, 0x3b, 0x9d, 0x89, 0xad, 0x61, 0x1a, 0x40, 0x87, 0xc5, 0xb1, 0x44, 0xa5, 0x8a, 0xd9, 0x2e,
-	0x49, 0x03, 0x9d, 0x16, 0x9a, 0xa5, 0x2f, 0x11, 0xe3, 0x60, 0x37, 0x87, 0xae, 0x62, 0x90, 0x01,
-	0xf8, 0x1c, 0x31, 0x3e, 0x17, 0xd1, 0x55, 0xd0, 0x1e, 0x7a, 0x23, 0x9f, 0x56, 0x74, 0xf8, 0x8b,
-	0x07, 0x07, 0x6e, 0xaa, 0x1b, 0xe3, 0x36, 0x02, 0x7f, 0xad, 0x33, 0x71, 0x9e, 0x28, 0x1d, 0xec,
-	0xd8, 0x46, 0xef, 0x95, 0x73, 0x66, 0x3d, 0x56, 0x52, 0xd3, 0x41, 0x9b, 0xd3, 0xc9, 0x52, 0xac,
-	0xb9, 0x2e, 0x4a, 0x70, 0x59, 0x21, 0x02, 0x7c, 0xbb, 0x46, 0xf9, 0xe3, 0xdb, 0x5d, 0x8a, 0xf0,
-	0x4f, 0x0f, 0x7a, 0x55, 0x9c, 0x8d, 0x0b, 0x3e, 0x86, 0xb6, 0xd2, 0x4c, 0xaf, 0x95, 0x8d, 0x74,
-	0x30, 0x79, 0x7a, 0xcb, 0x5a, 0x5d, 0x58, 0x05, 0x5a, 0x28, 0x9a, 0x06, 0xc4, 0x89, 0xd2, 0x8c,
-	0x47, 0xf9, 0x0c, 0xb5, 0x68, 0x45, 0xff, 0xbf, 0x0d, 0xfd, 0xcd, 0x83, 0x7d, 0x9b, 0xf1, 0x69,
-	0x2a, 0xa2, 0xab, 0x6d, 0x0e, 0xd4, 0xcc, 0x38, 0x9c, 0x96, 0xf8, 0x94, 0xa4, 0xe9, 0x95, 0x19,
-	0x91, 0x33, 0xc1, 0x35, 0x72, 0x6d, 0xd3, 0xf3, 0xa9, 0xcb, 0x0a, 0x7f, 0xf7, 0xe0, 0xc0, 0x4d,
-	0x69, 0x63, 0x1c, 0x0f, 0x1b, 0x38, 0x56, 0xc5, 0x5b, 0x87, 0x0d, 0x04, 0x0f, 0x61, 0xd7, 0xa6,
-	0x56, 0xac, 0x60, 0xbf, 0xd4, 0x9d, 0x72, 0x8d, 0x92, 0xb3, 0x34, 0x4f, 0x22, 0xd7, 0x09, 0x7f,
-	0xf2, 0xe0, 0xa1, 0x4d, 0xed, 0xcc, 0x44, 0x2f, 0x3c, 0x6d, 0x0b, 0xb3, 0x11, 0xbc, 0x63, 0x60,
-	0x38, 0x95, 0x8c, 0x47, 0x8b, 0xd3, 0x2a, 0x27, 0x9f, 0x36, 0xd9, 0xe1, 0x3f, 0x1e, 0x3c, 0xfa,
-	0x6f, 0x1a, 0x5b, 0x3c, 0x4c, 0x90, 0xbf, 0xcd, 0x2f, 0x50, 0xb3, 0x02, 0x17, 0x52, 0xe2, 0x72,
-	0x5e, 0x49, 0xa8, 0xa3, 0x45, 0x9e, 0xe5, 0xcb, 0x6a, 0x2d, 0xf2, 0x91, 0x7b, 0xe0, 0x2e, 0xab,
-	0xd5, 0xaf, 0x34, 0xc8, 0x27, 0xb0, 0x3f, 0xab, 0xeb, 0x99, 0xc6, 0xc5, 0xd1, 0xbd, 0xc9, 0x9c,
-	0xfc, 0xdd, 0x82, 0xf6, 0xf7, 0x36, 0x7f, 0xf2, 0x19, 0xc0, 0xd9, 0x02, 0xa3, 0xab, 0x93, 0x34,
-	0x79, 0x8d, 0xe4, 0xdd, 0xba, 0xac, 0xe2, 0x79, 0x1d, 0x90, 0x26, 0x4b, 0xad, 0xc2, 0x7b, 0xe4,
-	0x0b, 0xf0, 0xcb, 0xc7, 0x90, 0xf4, 0x6b, 0x0d, 0xe7, 0x81, 0x7c, 0x83, 0xe1, 0x73, 0xe8, 0x14,
-	0x07, 0x9f, 0x38, 0x18, 0xd6, 0x4f, 0xd9, 0xa0, 0x7f, 0x0b, 0xd7, 0x5a, 0x9e, 0x00, 0xd4, 0x57,
-	0x8f, 0x3c, 0x71, 0x82, 0xba, 0x67, 0x7b, 0x10, 0xdc, 0x2e, 0x28, 0x83, 0x17, 0x47, 0xc4, 0x0d,
-	0x5e, 0xdf, 0x2f, 0x37, 0xb8, 0x73, 0x6d, 0xf2, 0xe0, 0xf5, 0xe6, 0xb8, 0xc1, 0x6f, 0xac, 0xb8,
-	0x1b, 0xfc, 0xe6, 0xa2, 0x85, 0xf7, 0xc8, 0x05, 0x3c, 0x68, 0x8e, 0x16, 0xf9, 0xa0, 0xa1, 0x7f,
-	0x73, 0xfa, 0x07, 0x1f, 0xde, 0x25, 0x36, 0x4e, 0x67, 0x6d, 0xfb, 0xae, 0x7d, 0xfa, 0x6f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xb0, 0x44, 0x04, 0xda, 0x31, 0x0a, 0x00, 0x00,
+	// 889 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xcd, 0x6f, 0x1b, 0x45,
+	0x14, 0xef, 0xda, 0x8d, 0xbd, 0x7e, 0x4e, 0x42, 0x99, 0xd6, 0xed, 0xd6, 0x7c, 0x59, 0x0b, 0x07,
+	0x4b, 0xa9, 0x1c, 0xc5, 0x08, 0xe8, 0x0d, 0x25, 0x11, 0x12, 0x96, 0xd2, 0x0a, 0x26, 0x41, 0xe2,
+	0x16, 0x8d, 0x77, 0x1f, 0xf6, 0x2a, 0xeb, 0x9d, 0xcd, 0xcc, 0xb8, 0xda, 0xde, 0x38, 0x20, 0x21,
+	0xc1, 0x85, 0x23, 0x7f, 0x02, 0x37, 0xfe, 0x09, 0x24, 0xfe, 0x2d, 0x34, 0xb3, 0x5f, 0xe3, 0x25,
+	0xad, 0xb0, 0x64, 0x4e, 0xbb, 0xef, 0xfb, 0xbd, 0xdf, 0xbc, 0x0f, 0xd8, 0xcf, 0x82, 0x25, 0x8b,
+	0x92, 0x49, 0x2a, 0xb8, 0xe2, 0xc4, 0xcd, 0xa9, 0x74, 0x3e, 0x3c, 0xc9, 0xd6, 0x29, 0x8a, 0x80,
+	0x0b, 0x3c, 0x9e, 0x07, 0xf2, 0x38, 0xc6, 0x70, 0x81, 0xe2, 0x38, 0xab, 0xbe, 0xe1, 0x22, 0x9d,
+	0x97, 0x64, 0x6e, 0x3c, 0xfc, 0xa8, 0x36, 0x31, 0x0c, 0x79, 0x1c, 0xf0, 0x44, 0x09, 0x16, 0xa8,
+	0x5c, 0xc1, 0xff, 0x12, 0x7a, 0x14, 0x6f, 0xbf, 0x46, 0x16, 0xa2, 0x20, 0x03, 0xe8, 0xc4, 0x7c,
+	0x71, 0x1d, 0x85, 0x9e, 0x33, 0x72, 0xc6, 0x3d, 0xba, 0x17, 0xf3, 0xc5, 0x2c, 0x24, 0xef, 0x41,
+	0x4f, 0x62, 0xfc, 0xc3, 0x75, 0xc2, 0x56, 0xe8, 0xb5, 0x8c, 0xc4, 0xd5, 0x8c, 0x97, 0x6c, 0x85,
+	0xbe, 0x00, 0xa0, 0x28, 0xd3, 0xb7, 0x7b, 0x78, 0x0a, 0x2e, 0x0a, 0x71, 0x1d, 0xf0, 0x30, 0x77,
+	0xd0, 0xa6, 0x5d, 0x14, 0xe2, 0x9c, 0x87, 0x48, 0x9e, 0x80, 0xfe, 0xbd, 0x5e, 0xc9, 0x85, 0xd7,
+	0x36, 0x26, 0x1d, 0x14, 0xe2, 0x85, 0x5c, 0x68, 0x1b, 0x9d, 0x28, 0x6a, 0x67, 0xf7, 0x8d, 0xa4,
+	0x6b, 0xe8, 0x59, 0xe8, 0x7f, 0x0e, 0xdd, 0x33, 0x26, 0x91, 0xe2, 0x2d, 0x39, 0x82, 0xce, 0xd2,
+	0x84, 0x36, 0x01, 0xfb, 0xd3, 0x87, 0x93, 0x12, 0xae, 0x49, 0x55, 0x17, 0x2d, 0x54, 0xfc, 0xe7,
+	0xe0, 0xe6, 0x76, 0x32, 0x25, 0xcf, 0x1a, 0x86, 0x8f, 0x6c, 0xc3, 0xb2, 0x9e, 0xca, 0xf2, 0x57,
+	0x07, 0xfa, 0x97, 0xeb, 0xf9, 0x2a, 0x52, 0x57, 0xd9, 0xb6, 0x61, 0xc9, 0x63, 0xe8, 0xcc, 0x03,
+	0x0b, 0xbc, 0x82, 0x22, 0x04, 0xee, 0xab, 0x2c, 0x0a, 0x4d, 0xdd, 0xfb, 0xd4, 0xfc, 0x93, 0x8f,
+	0xa1, 0xa5, 0x32, 0x53, 0xaf, 0x71, 0x6a, 0xde, 0x74, 0x72, 0x25, 0x58, 0x22, 0x59, 0xa0, 0x22,
+	0x9e, 0xd0, 0x96, 0xca, 0xfc, 0xbf, 0x1c, 0x80, 0x6f, 0x04, 0x7e, 0x95, 0x61, 0xb0, 0xb3, 0x64,
+	0x4e, 0xc0, 0x15, 0x78, 0xbb, 0x46, 0xa9, 0xa4, 0xd7, 0x1e, 0xb5, 0xc7, 0xfd, 0xe9, 0x20, 0x6f,
+	0x11, 0x39, 0x99, 0x25, 0xaf, 0xf8, 0x8d, 0x46, 0x5b, 0x4b, 0x69, 0xa5, 0x46, 0xde, 0x87, 0x5e,
+	0x94, 0x44, 0x2a, 0x62, 0x8a, 0x8b, 0xe2, 0x89, 0x6a, 0x06, 0x19, 0x41, 0x9f, 0xad, 0xd5, 0x52,
+	0x9b, 0x45, 0x02, 0xbd, 0xbd, 0x51, 0x7b, 0xdc, 0xa3, 0x36, 0xcb, 0xff, 0xd9, 0x81, 0x7e, 0x55,
+	0xc6, 0xb6, 0x4f, 0xf2, 0xc6, 0x42, 0xa6, 0xba, 0x10, 0x99, 0xf2, 0x44, 0xa2, 0x41, 0xb6, 0x3f,
+	0x7d, 0xdc, 0x2c, 0x24, 0x97, 0xd2, 0x4a, 0xcf, 0xff, 0xc3, 0x81, 0x83, 0x4b, 0x8c, 0x31, 0x50,
+	0xdf, 0xa9, 0x8c, 0xef, 0x0c, 0x53, 0x0f, 0xba, 0x2c, 0x0c, 0x05, 0x4a, 0x59, 0xf4, 0x76, 0x49,
+	0x6a, 0xe8, 0x14, 0x57, 0x2c, 0x7e, 0x89, 0x18, 0x7a, 0x7b, 0x39, 0x74, 0x15, 0x83, 0x0c, 0xc1,
+	0x4d, 0x10, 0xc3, 0x0b, 0x1e, 0xdc, 0x78, 0x9d, 0x91, 0x33, 0x76, 0x69, 0x45, 0xfb, 0xbf, 0x38,
+	0x70, 0x68, 0xa7, 0xba, 0x35, 0x6e, 0x63, 0x70, 0xd7, 0x2a, 0xe3, 0x17, 0x91, 0x54, 0x5e, 0xcb,
+	0x3c, 0xf4, 0x7e, 0xd9, 0x67, 0xc6, 0x63, 0x25, 0xd5, 0x2f, 0x68, 0x72, 0x3a, 0x5d, 0xf1, 0x75,
+	0xa2, 0x8a, 0x12, 0x6c, 0x96, 0x8f, 0x00, 0xdf, 0xae, 0x51, 0xbc, 0xfe, 0x7f, 0x87, 0xc2, 0xff,
+	0xd3, 0x81, 0x7e, 0x15, 0x67, 0xeb, 0x82, 0x4f, 0xa0, 0x23, 0x15, 0x53, 0x6b, 0x69, 0x22, 0x1d,
+	0x4e, 0x9f, 0xde, 0x31, 0x56, 0x97, 0x46, 0x81, 0x16, 0x8a, 0xfa, 0x01, 0xc2, 0x48, 0x2a, 0x96,
+	0x04, 0x79, 0x0f, 0xb5, 0x69, 0x45, 0xff, 0xb7, 0x09, 0xfd, 0xcd, 0x81, 0x03, 0x93, 0xf1, 0x59,
+	0xcc, 0x83, 0x9b, 0x5d, 0x36, 0xd4, 0x5c, 0x3b, 0x9c, 0x95, 0xf8, 0x94, 0xa4, 0x7e, 0x2b, 0xdd,
+	0x22, 0xe7, 0x3c, 0x51, 0x98, 0x28, 0x93, 0x9e, 0x4b, 0x6d, 0x96, 0xff, 0xbb, 0x03, 0x87, 0x76,
+	0x4a, 0x5b, 0xe3, 0x78, 0xd4, 0xc0, 0xb1, 0x2a, 0xde, 0x38, 0x6c, 0x20, 0x78, 0x04, 0x7b, 0x26,
+	0xb5, 0x62, 0x04, 0x07, 0xa5, 0xee, 0x2c, 0x51, 0x28, 0x12, 0x16, 0xe7, 0x49, 0xe4, 0x3a, 0xfe,
+	0x4f, 0x0e, 0x3c, 0x34, 0xa9, 0x9d, 0xeb, 0xe8, 0x85, 0xa7, 0x5d, 0x61, 0x36, 0x86, 0x77, 0x34,
+	0x0c, 0x67, 0x82, 0x25, 0xc1, 0xf2, 0xac, 0xca, 0xc9, 0xa5, 0x4d, 0xb6, 0xff, 0x63, 0x0b, 0x1e,
+	0xfd, 0x3b, 0x8d, 0x1d, 0x2e, 0x26, 0xc8, 0x6f, 0xf3, 0x0b, 0x54, 0xac, 0xc0, 0x85, 0x94, 0xb8,
+	0x5c, 0x54, 0x12, 0x6a, 0x69, 0x91, 0x67, 0xf9, 0xb0, 0x1a, 0x8b, 0xbc, 0xe5, 0x1e, 0xd8, 0xc3,
+	0x6a, 0xf4, 0x2b, 0x0d, 0xf2, 0x09, 0x1c, 0xcc, 0xeb, 0x7a, 0x66, 0x61, 0xb1, 0x74, 0x37, 0x99,
+	0xba, 0xb9, 0x97, 0x4c, 0x2e, 0xaf, 0x5e, 0xa7, 0x68, 0xb6, 0x4b, 0x8f, 0x56, 0xf4, 0xf4, 0xef,
+	0x36, 0x74, 0xbe, 0x37, 0xb5, 0x91, 0xcf, 0x00, 0xce, 0x97, 0x18, 0xdc, 0x9c, 0xc6, 0xd1, 0x2b,
+	0x24, 0xef, 0xd6, 0x25, 0x17, 0xa7, 0x77, 0x48, 0x9a, 0x2c, 0x99, 0xfa, 0xf7, 0xc8, 0x17, 0xe0,
+	0x96, 0x87, 0x92, 0x0c, 0x6a, 0x0d, 0xeb, 0x78, 0xbe, 0xc1, 0xf0, 0x39, 0x74, 0x8b, 0x63, 0x40,
+	0x2c, 0x7c, 0xeb, 0x33, 0x37, 0x1c, 0xdc, 0xc1, 0x35, 0x96, 0xa7, 0x00, 0xf5, 0x46, 0x24, 0x4f,
+	0xac, 0xa0, 0xf6, 0x4a, 0x1f, 0x7a, 0x77, 0x0b, 0xca, 0xe0, 0xc5, 0x82, 0xb1, 0x83, 0xd7, 0xbb,
+	0xcd, 0x0e, 0x6e, 0x6d, 0xa2, 0x3c, 0x78, 0x3d, 0x55, 0x76, 0xf0, 0x8d, 0xf1, 0xb7, 0x83, 0x6f,
+	0x0e, 0xa1, 0x7f, 0x8f, 0x5c, 0xc2, 0x83, 0x66, 0xdb, 0x91, 0x0f, 0x1a, 0xfa, 0x9b, 0x93, 0x31,
+	0xfc, 0xf0, 0x6d, 0x62, 0xed, 0x74, 0xde, 0x31, 0x37, 0xef, 0xd3, 0x7f, 0x02, 0x00, 0x00, 0xff,
+	0xff, 0xf2, 0xa2, 0x4b, 0x90, 0x4d, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
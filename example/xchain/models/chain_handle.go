@@ -62,10 +62,27 @@ func (t *ChainHandle) QueryBlock(blkId []byte, needContent bool) (*xpb.BlockInfo
 	return reader.NewLedgerReader(t.chain.Context(), t.genXctx()).QueryBlock(blkId, needContent)
 }
 
+func (t *ChainHandle) QueryBlockByHeight(height int64, needContent bool) (*xpb.BlockInfo, error) {
+	return reader.NewLedgerReader(t.chain.Context(), t.genXctx()).QueryBlockByHeight(height, needContent)
+}
+
+func (t *ChainHandle) GetBalance(address string) (string, error) {
+	return reader.NewUtxoReader(t.chain.Context(), t.genXctx()).GetBalance(address)
+}
+
+func (t *ChainHandle) GetContractAbi(contractName string) ([]byte, error) {
+	return reader.NewContractReader(t.chain.Context(), t.genXctx()).GetContractAbi(contractName)
+}
+
 func (t *ChainHandle) QueryChainStatus(needBranch bool) (*xpb.ChainStatus, error) {
 	return reader.NewChainReader(t.chain.Context(), t.genXctx()).GetChainStatus()
 }
 
+// 获取链上下文，用于获取节点账户等信息
+func (t *ChainHandle) GetChainCtx() *ecom.ChainCtx {
+	return t.chain.Context()
+}
+
 func (t *ChainHandle) genXctx() xctx.XContext {
 	return &xctx.BaseCtx{
 		XLog:  t.reqCtx.GetLog(),
@@ -0,0 +1,577 @@
+package ethrpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/burrow/crypto"
+
+	"github.com/xuperchain/xupercore/bcs/contract/evm"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/example/xchain/models"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 注意：
+// 1.接口参数为json-rpc请求的参数数组，按以太坊json-rpc规范解析
+// 2.数值和字节数组按以太坊规范使用0x开头的hex字符串表示
+// 3.区块参数只支持最新状态，历史状态查询按最新状态处理
+
+const (
+	// eth_getLogs单次查询的最大区块数
+	maxLogsBlockRange = 1000
+)
+
+// callArgs eth_call和eth_estimateGas的调用参数
+type callArgs struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Data  string `json:"data"`
+	Input string `json:"input"`
+	Value string `json:"value"`
+}
+
+// filterArgs eth_getLogs的过滤参数
+type filterArgs struct {
+	FromBlock string            `json:"fromBlock"`
+	ToBlock   string            `json:"toBlock"`
+	BlockHash string            `json:"blockHash"`
+	Address   json.RawMessage   `json:"address"`
+	Topics    []json.RawMessage `json:"topics"`
+}
+
+type ethLog struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	BlockHash        string   `json:"blockHash"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+	LogIndex         string   `json:"logIndex"`
+	Removed          bool     `json:"removed"`
+}
+
+type ethReceipt struct {
+	TransactionHash   string    `json:"transactionHash"`
+	TransactionIndex  string    `json:"transactionIndex"`
+	BlockHash         string    `json:"blockHash"`
+	BlockNumber       string    `json:"blockNumber"`
+	From              string    `json:"from"`
+	To                *string   `json:"to"`
+	CumulativeGasUsed string    `json:"cumulativeGasUsed"`
+	GasUsed           string    `json:"gasUsed"`
+	EffectiveGasPrice string    `json:"effectiveGasPrice"`
+	ContractAddress   *string   `json:"contractAddress"`
+	Logs              []*ethLog `json:"logs"`
+	LogsBloom         string    `json:"logsBloom"`
+	Type              string    `json:"type"`
+	Status            string    `json:"status"`
+}
+
+// 链id
+func (t *EthRpcServ) ChainId(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	return encodeUint64(uint64(ethChainId(handle))), nil
+}
+
+// 网络id，与链id保持一致
+func (t *EthRpcServ) NetVersion(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	return strconv.FormatInt(ethChainId(handle), 10), nil
+}
+
+// 最新区块高度
+func (t *EthRpcServ) BlockNumber(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	height, err := t.latestHeight(handle)
+	if err != nil {
+		return nil, err
+	}
+	return encodeUint64(uint64(height)), nil
+}
+
+// 交易手续费由中继交易的节点账户支付，gas价格固定为0
+func (t *EthRpcServ) GasPrice(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	return encodeUint64(0), nil
+}
+
+// 查询地址余额，以太坊地址按地址转换规则映射为xchain地址、合约名或合约账户
+func (t *EthRpcServ) GetBalance(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	var addrStr string
+	if err := parseParams(params, 1, &addrStr); err != nil {
+		return nil, err
+	}
+	addr, err := decodeAddress(addrStr)
+	if err != nil {
+		return nil, err
+	}
+	account, err := evm.EVMAddressToXchainAccount(addr, t.bcName)
+	if err != nil {
+		return nil, newRpcError(errCodeInvalidParams, err.Error())
+	}
+	balance, err := handle.GetBalance(account)
+	if err != nil {
+		return nil, err
+	}
+	amount, ok := new(big.Int).SetString(balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance %s", balance)
+	}
+	return encodeBig(amount), nil
+}
+
+// 查询地址下一笔交易的nonce，nonce由evm合约虚拟机记录在链上状态中，包含未确认的交易
+func (t *EthRpcServ) GetTransactionCount(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	var addrStr string
+	if err := parseParams(params, 1, &addrStr); err != nil {
+		return nil, err
+	}
+	addr, err := decodeAddress(addrStr)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := ethNonce(handle, addr)
+	if err != nil {
+		return nil, err
+	}
+	return encodeUint64(nonce), nil
+}
+
+// 预执行合约调用，返回合约执行结果
+func (t *EthRpcServ) Call(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	_, resp, err := t.preExecCall(handle, params)
+	if err != nil {
+		return nil, err
+	}
+	return encodeBytes(resp.GetBody()), nil
+}
+
+// 预执行合约调用，返回消耗的gas
+func (t *EthRpcServ) EstimateGas(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	res, _, err := t.preExecCall(handle, params)
+	if err != nil {
+		return nil, err
+	}
+	return encodeUint64(uint64(res.GetGasUsed())), nil
+}
+
+// 提交已签名的以太坊交易，交易由节点账户作为中继者转换为合约调用交易后提交，
+// 返回以太坊交易哈希，可以通过eth_getTransactionReceipt查询交易回执
+func (t *EthRpcServ) SendRawTransaction(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	var rawStr string
+	if err := parseParams(params, 1, &rawStr); err != nil {
+		return nil, err
+	}
+	raw, err := decodeHex(rawStr)
+	if err != nil {
+		return nil, err
+	}
+	ethTx, err := evm.DecodeRawTx(raw, ethChainId(handle))
+	if err != nil {
+		return nil, newRpcError(errCodeInvalidParams, err.Error())
+	}
+	if !ethTx.Protected {
+		return nil, newRpcError(errCodeInvalidParams, evm.ErrUnprotectedTx.Error())
+	}
+	// 中继交易的手续费由节点账户支付，只中继允许的发起地址的交易
+	if !t.relayAllowList[ethTx.From] {
+		return nil, newRpcError(errCodeServer, errRelayNotAllowed.Error())
+	}
+
+	// 交易哈希和nonce在合约执行时校验，此处提前检查以返回以太坊节点通用的错误信息
+	if _, ok, err := relayedTxid(handle, ethTx.Hash); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, newRpcError(errCodeServer, evm.ErrRawTxKnown.Error())
+	}
+	nonce, err := ethNonce(handle, ethTx.From)
+	if err != nil {
+		return nil, err
+	}
+	if ethTx.Nonce < nonce {
+		return nil, newRpcError(errCodeServer, evm.ErrNonceTooLow.Error())
+	}
+	if ethTx.Nonce > nonce {
+		return nil, newRpcError(errCodeServer, evm.ErrNonceTooHigh.Error())
+	}
+
+	if !t.relayLimiter.allow() {
+		return nil, newRpcError(errCodeServer, errRelayRateLimit.Error())
+	}
+	if _, err := t.relayTx(handle, ethTx, raw); err != nil {
+		return nil, err
+	}
+	return encodeBytes(ethTx.Hash), nil
+}
+
+// 查询交易回执，支持以太坊交易哈希和链上交易id，交易未确认时返回null
+func (t *EthRpcServ) GetTransactionReceipt(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	var hashStr string
+	if err := parseParams(params, 1, &hashStr); err != nil {
+		return nil, err
+	}
+	txid, err := decodeHex(hashStr)
+	if err != nil {
+		return nil, err
+	}
+	if relayed, ok, err := relayedTxid(handle, txid); err != nil {
+		return nil, err
+	} else if ok {
+		txid = relayed
+	}
+
+	txInfo, err := handle.QueryTx(txid)
+	if err != nil || txInfo.GetStatus() != lpb.TransactionStatus_TX_CONFIRM {
+		return nil, nil
+	}
+	blockInfo, err := handle.QueryBlock(txInfo.GetTx().GetBlockid(), true)
+	if err != nil {
+		return nil, err
+	}
+	block := blockInfo.GetBlock()
+	txIndex := -1
+	for i, tx := range block.GetTransactions() {
+		if string(tx.GetTxid()) == string(txid) {
+			txIndex = i
+			break
+		}
+	}
+	if txIndex < 0 {
+		return nil, nil
+	}
+
+	tx := block.GetTransactions()[txIndex]
+	receipt := &ethReceipt{
+		TransactionHash:   txHash(tx),
+		TransactionIndex:  encodeUint64(uint64(txIndex)),
+		BlockHash:         encodeBytes(block.GetBlockid()),
+		BlockNumber:       encodeUint64(uint64(block.GetHeight())),
+		From:              txFrom(handle, tx),
+		EffectiveGasPrice: encodeUint64(0),
+		Logs:              []*ethLog{},
+		Type:              encodeUint64(0),
+		Status:            encodeUint64(1),
+	}
+	if to, ok := txTo(tx); ok {
+		receipt.To = &to
+	}
+	gasUsed := txGasUsed(tx)
+	receipt.GasUsed = encodeUint64(gasUsed)
+	cumulative := gasUsed
+	for _, preTx := range block.GetTransactions()[:txIndex] {
+		cumulative += txGasUsed(preTx)
+	}
+	receipt.CumulativeGasUsed = encodeUint64(cumulative)
+
+	logs := newLogBuilder(handle).blockLogs(block)
+	for _, log := range logs {
+		if log.TransactionIndex == receipt.TransactionIndex {
+			receipt.Logs = append(receipt.Logs, log)
+		}
+	}
+	receipt.LogsBloom = encodeBytes(logsBloom(receipt.Logs))
+	return receipt, nil
+}
+
+// 按区块范围、合约地址和topics查询evm合约日志
+func (t *EthRpcServ) GetLogs(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error) {
+	var args filterArgs
+	if err := parseParams(params, 1, &args); err != nil {
+		return nil, err
+	}
+	filter, err := newLogFilter(&args)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := newLogBuilder(handle)
+	logs := make([]*ethLog, 0)
+	if args.BlockHash != "" {
+		blockid, err := decodeHex(args.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		blockInfo, err := handle.QueryBlock(blockid, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range builder.blockLogs(blockInfo.GetBlock()) {
+			if filter.match(log) {
+				logs = append(logs, log)
+			}
+		}
+		return logs, nil
+	}
+
+	from, err := t.blockHeight(handle, args.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := t.blockHeight(handle, args.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := t.latestHeight(handle)
+	if err != nil {
+		return nil, err
+	}
+	if to > latest {
+		to = latest
+	}
+	if from > to {
+		return logs, nil
+	}
+	if to-from >= maxLogsBlockRange {
+		return nil, newRpcError(errCodeServer,
+			fmt.Sprintf("block range should not exceed %d", maxLogsBlockRange))
+	}
+	for height := from; height <= to; height++ {
+		blockInfo, err := handle.QueryBlockByHeight(height, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range builder.blockLogs(blockInfo.GetBlock()) {
+			if filter.match(log) {
+				logs = append(logs, log)
+			}
+		}
+	}
+	return logs, nil
+}
+
+// preExecCall 预执行eth_call请求，返回预执行结果和合约调用的响应
+func (t *EthRpcServ) preExecCall(handle *models.ChainHandle,
+	params []json.RawMessage) (*protos.InvokeResponse, *protos.ContractResponse, error) {
+	var args callArgs
+	if err := parseParams(params, 1, &args); err != nil {
+		return nil, nil, err
+	}
+	req, err := t.makeInvokeRequest(handle, args.To, args.input(), args.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+	initiator, err := t.callInitiator(handle, args.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := handle.PreExec([]*protos.InvokeRequest{req}, initiator, nil)
+	if err != nil {
+		return nil, nil, newRpcError(errCodeServer, err.Error())
+	}
+	resp, err := lastResponse(res)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, resp, nil
+}
+
+// callInitiator 预执行的发起者，未指定from时使用节点账户
+func (t *EthRpcServ) callInitiator(handle *models.ChainHandle, from string) (string, error) {
+	if from == "" {
+		return handle.GetChainCtx().Address.Address, nil
+	}
+	addr, err := decodeAddress(from)
+	if err != nil {
+		return "", err
+	}
+	initiator, err := evm.EVMAddressToXchainAccount(addr, t.bcName)
+	if err != nil {
+		return "", newRpcError(errCodeInvalidParams, err.Error())
+	}
+	return initiator, nil
+}
+
+// makeInvokeRequest 构造evm合约调用请求，合约方法名根据abi解析，
+// 不支持部署合约和转账
+func (t *EthRpcServ) makeInvokeRequest(handle *models.ChainHandle, to string,
+	input []byte, value string) (*protos.InvokeRequest, error) {
+	if to == "" {
+		return nil, newRpcError(errCodeInvalidParams, "contract deployment not supported")
+	}
+	if value != "" {
+		amount, err := decodeBig(value)
+		if err != nil {
+			return nil, err
+		}
+		if amount.Sign() != 0 {
+			return nil, newRpcError(errCodeInvalidParams, "transfer value not supported")
+		}
+	}
+	addr, err := decodeAddress(to)
+	if err != nil {
+		return nil, err
+	}
+	return newInvokeRequest(handle, addr, input)
+}
+
+func newInvokeRequest(handle *models.ChainHandle, to crypto.Address,
+	input []byte) (*protos.InvokeRequest, error) {
+	contractName, err := evm.DetermineContractNameFromEVM(to)
+	if err != nil {
+		return nil, newRpcError(errCodeInvalidParams, err.Error())
+	}
+	abiBuf, err := handle.GetContractAbi(contractName)
+	if err != nil {
+		return nil, newRpcError(errCodeInvalidParams, err.Error())
+	}
+	return &protos.InvokeRequest{
+		ModuleName:   "evm",
+		ContractName: contractName,
+		MethodName:   methodName(abiBuf, input),
+		Args: map[string][]byte{
+			"input":       input,
+			"jsonEncoded": []byte("false"),
+		},
+	}, nil
+}
+
+// ethNonce 查询链上记录的以太坊地址下一笔交易的nonce
+func ethNonce(handle *models.ChainHandle, addr crypto.Address) (uint64, error) {
+	data, err := handle.GetChainCtx().State.CreateXMReader().Get(evm.EthNonceBucket, addr.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return evm.ParseEthNonce(data.GetPureData().GetValue())
+}
+
+// relayedTxid 查询执行以太坊交易的链上交易id，交易未执行时返回false
+func relayedTxid(handle *models.ChainHandle, hash []byte) ([]byte, bool, error) {
+	data, err := handle.GetChainCtx().State.CreateXMReader().Get(evm.EthTxBucket, hash)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(data.GetPureData().GetValue()) == 0 {
+		return nil, false, nil
+	}
+	return data.GetRefTxid(), true, nil
+}
+
+// lastResponse 返回合约调用的响应，预执行时系统合约的调用在前
+func lastResponse(res *protos.InvokeResponse) (*protos.ContractResponse, error) {
+	responses := res.GetResponses()
+	if len(responses) == 0 {
+		return nil, newRpcError(errCodeServer, "contract no response")
+	}
+	resp := responses[len(responses)-1]
+	if resp.GetStatus() >= 400 {
+		return nil, &rpcError{
+			Code:    errCodeExecution,
+			Message: "execution reverted: " + resp.GetMessage(),
+			Data:    encodeBytes(resp.GetBody()),
+		}
+	}
+	return resp, nil
+}
+
+func (t *EthRpcServ) latestHeight(handle *models.ChainHandle) (int64, error) {
+	status, err := handle.QueryChainStatus(false)
+	if err != nil {
+		return 0, err
+	}
+	return status.GetLedgerMeta().GetTrunkHeight(), nil
+}
+
+// blockHeight 解析区块参数，默认为最新区块
+func (t *EthRpcServ) blockHeight(handle *models.ChainHandle, tag string) (int64, error) {
+	switch tag {
+	case "", "latest", "pending", "safe", "finalized":
+		return t.latestHeight(handle)
+	case "earliest":
+		return 0, nil
+	}
+	height, err := decodeUint64(tag)
+	if err != nil {
+		return 0, err
+	}
+	if height > math.MaxInt64 {
+		return 0, newRpcError(errCodeInvalidParams, "block number too large")
+	}
+	return int64(height), nil
+}
+
+func (a *callArgs) input() []byte {
+	data := a.Input
+	if data == "" {
+		data = a.Data
+	}
+	b, _ := decodeHex(data)
+	return b
+}
+
+// parseParams 解析参数数组的前几个参数，参数个数不能少于required
+func parseParams(params []json.RawMessage, required int, args ...interface{}) error {
+	if len(params) < required {
+		return newRpcError(errCodeInvalidParams, fmt.Sprintf("missing value for required argument %d", len(params)))
+	}
+	for i, arg := range args {
+		if i >= len(params) {
+			break
+		}
+		if err := json.Unmarshal(params[i], arg); err != nil {
+			return newRpcError(errCodeInvalidParams, fmt.Sprintf("invalid argument %d: %v", i, err))
+		}
+	}
+	return nil
+}
+
+func encodeUint64(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+func encodeBig(n *big.Int) string {
+	return "0x" + n.Text(16)
+}
+
+func encodeBytes(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func encodeAddress(addr crypto.Address) string {
+	return encodeBytes(addr.Bytes())
+}
+
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, newRpcError(errCodeInvalidParams, "invalid hex string")
+	}
+	return b, nil
+}
+
+func decodeUint64(s string) (uint64, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return 0, newRpcError(errCodeInvalidParams, "hex string without 0x prefix")
+	}
+	n, err := strconv.ParseUint(s[2:], 16, 64)
+	if err != nil {
+		return 0, newRpcError(errCodeInvalidParams, "invalid hex number")
+	}
+	return n, nil
+}
+
+func decodeBig(s string) (*big.Int, error) {
+	b, err := decodeHex(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func decodeAddress(s string) (crypto.Address, error) {
+	b, err := decodeHex(s)
+	if err != nil {
+		return crypto.ZeroAddress, err
+	}
+	addr, err := crypto.AddressFromBytes(b)
+	if err != nil {
+		return crypto.ZeroAddress, newRpcError(errCodeInvalidParams, "invalid address")
+	}
+	return addr, nil
+}
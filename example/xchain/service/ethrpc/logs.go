@@ -0,0 +1,165 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/burrow/crypto"
+
+	"github.com/xuperchain/xupercore/bcs/contract/evm"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/example/xchain/models"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+// logBuilder 将区块中evm合约的事件还原为以太坊日志，同一次查询内缓存合约abi
+type logBuilder struct {
+	handle *models.ChainHandle
+	abis   map[string][]byte
+}
+
+func newLogBuilder(handle *models.ChainHandle) *logBuilder {
+	return &logBuilder{
+		handle: handle,
+		abis:   make(map[string][]byte),
+	}
+}
+
+// blockLogs 返回区块中所有evm合约日志，日志序号在区块内递增，
+// 无法按abi还原的事件（如非evm合约的事件）不会返回
+func (b *logBuilder) blockLogs(block *lpb.InternalBlock) []*ethLog {
+	var logs []*ethLog
+	for txIndex, tx := range block.GetTransactions() {
+		events, err := sandbox.ParseContractEvents(tx)
+		if err != nil || len(events) == 0 {
+			continue
+		}
+		hash := txHash(tx)
+		for _, event := range events {
+			abiBuf := b.contractAbi(event.GetContract())
+			if abiBuf == nil {
+				continue
+			}
+			topics, data, err := evm.PackEventToLog(abiBuf, event)
+			if err != nil {
+				continue
+			}
+			addr, err := evm.ContractNameToEVMAddress(event.GetContract())
+			if err != nil {
+				continue
+			}
+			log := &ethLog{
+				Address:          encodeAddress(addr),
+				Topics:           make([]string, 0, len(topics)),
+				Data:             encodeBytes(data),
+				BlockNumber:      encodeUint64(uint64(block.GetHeight())),
+				BlockHash:        encodeBytes(block.GetBlockid()),
+				TransactionHash:  hash,
+				TransactionIndex: encodeUint64(uint64(txIndex)),
+				LogIndex:         encodeUint64(uint64(len(logs))),
+			}
+			for _, topic := range topics {
+				log.Topics = append(log.Topics, encodeBytes(topic.Bytes()))
+			}
+			logs = append(logs, log)
+		}
+	}
+	return logs
+}
+
+func (b *logBuilder) contractAbi(contractName string) []byte {
+	if abiBuf, ok := b.abis[contractName]; ok {
+		return abiBuf
+	}
+	abiBuf, err := b.handle.GetContractAbi(contractName)
+	if err != nil {
+		abiBuf = nil
+	}
+	b.abis[contractName] = abiBuf
+	return abiBuf
+}
+
+// logFilter eth_getLogs的地址和topics过滤条件，
+// 同一位置的多个topic满足任意一个即可，空条件匹配所有日志
+type logFilter struct {
+	addresses map[string]bool
+	topics    []map[string]bool
+}
+
+func newLogFilter(args *filterArgs) (*logFilter, error) {
+	filter := &logFilter{}
+
+	var addrs []string
+	if len(args.Address) > 0 && string(args.Address) != "null" {
+		var addr string
+		if err := json.Unmarshal(args.Address, &addr); err == nil {
+			addrs = []string{addr}
+		} else if err := json.Unmarshal(args.Address, &addrs); err != nil {
+			return nil, newRpcError(errCodeInvalidParams, "invalid address filter")
+		}
+	}
+	if len(addrs) > 0 {
+		filter.addresses = make(map[string]bool, len(addrs))
+		for _, addr := range addrs {
+			filter.addresses[strings.ToLower(addr)] = true
+		}
+	}
+
+	for _, raw := range args.Topics {
+		if len(raw) == 0 || string(raw) == "null" {
+			filter.topics = append(filter.topics, nil)
+			continue
+		}
+		var topics []string
+		var topic string
+		if err := json.Unmarshal(raw, &topic); err == nil {
+			topics = []string{topic}
+		} else if err := json.Unmarshal(raw, &topics); err != nil {
+			return nil, newRpcError(errCodeInvalidParams, "invalid topics filter")
+		}
+		set := make(map[string]bool, len(topics))
+		for _, topic := range topics {
+			set[strings.ToLower(topic)] = true
+		}
+		filter.topics = append(filter.topics, set)
+	}
+	return filter, nil
+}
+
+func (f *logFilter) match(log *ethLog) bool {
+	if f.addresses != nil && !f.addresses[log.Address] {
+		return false
+	}
+	if len(f.topics) > len(log.Topics) {
+		return false
+	}
+	for i, set := range f.topics {
+		if len(set) > 0 && !set[log.Topics[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// logsBloom 计算日志的布隆过滤器，计算方式与以太坊一致
+func logsBloom(logs []*ethLog) []byte {
+	bloom := make([]byte, 256)
+	add := func(hexStr string) {
+		b, err := decodeHex(hexStr)
+		if err != nil {
+			return
+		}
+		h := crypto.Keccak256(b)
+		for i := 0; i < 6; i += 2 {
+			bit := (uint(h[i])<<8 | uint(h[i+1])) & 2047
+			bloom[256-1-bit/8] |= 1 << (bit % 8)
+		}
+	}
+	for _, log := range logs {
+		add(log.Address)
+		for _, topic := range log.Topics {
+			add(topic)
+		}
+	}
+	return bloom
+}
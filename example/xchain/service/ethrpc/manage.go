@@ -0,0 +1,108 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	sconf "github.com/xuperchain/xupercore/example/xchain/common/config"
+	"github.com/xuperchain/xupercore/example/xchain/common/def"
+	"github.com/xuperchain/xupercore/kernel/engines"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos"
+	ecom "github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+// eth json-rpc server启停控制管理
+type EthRpcServMG struct {
+	scfg     *sconf.ServConf
+	engine   ecom.Engine
+	log      logs.Logger
+	ethServ  *EthRpcServ
+	servHD   *http.Server
+	isInit   bool
+	exitOnce *sync.Once
+}
+
+func NewEthRpcServMG(scfg *sconf.ServConf, engine engines.BCEngine) (*EthRpcServMG, error) {
+	if scfg == nil || engine == nil {
+		return nil, fmt.Errorf("param error")
+	}
+	xosEngine, err := xuperos.EngineConvert(engine)
+	if err != nil {
+		return nil, fmt.Errorf("not xuperos engine")
+	}
+
+	log, _ := logs.NewLogger("", def.SubModName)
+	obj := &EthRpcServMG{
+		scfg:     scfg,
+		engine:   xosEngine,
+		log:      log,
+		ethServ:  NewEthRpcServ(xosEngine, scfg, log),
+		isInit:   true,
+		exitOnce: &sync.Once{},
+	}
+
+	return obj, nil
+}
+
+// 启动eth json-rpc服务
+func (t *EthRpcServMG) Run() error {
+	if !t.isInit {
+		return errors.New("EthRpcServMG not init")
+	}
+
+	t.log.Trace("run eth json-rpc server")
+
+	// 启动http server，阻塞直到退出
+	err := t.runEthRpcServ()
+	if err != nil {
+		t.log.Error("eth json-rpc server abnormal exit", "err", err)
+		return err
+	}
+
+	t.log.Trace("eth json-rpc server exit")
+	return nil
+}
+
+// 退出eth json-rpc服务，释放相关资源，需要幂等
+func (t *EthRpcServMG) Exit() {
+	if !t.isInit {
+		return
+	}
+
+	t.exitOnce.Do(func() {
+		t.stopEthRpcServ()
+	})
+}
+
+// 启动http server，阻塞直到退出
+func (t *EthRpcServMG) runEthRpcServ() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", t.scfg.EthRpcPort))
+	if err != nil {
+		t.log.Error("failed to listen", "err", err.Error())
+		return fmt.Errorf("failed to listen")
+	}
+
+	t.servHD = &http.Server{
+		Handler: t.ethServ,
+	}
+	if err := t.servHD.Serve(lis); err != nil && err != http.ErrServerClosed {
+		t.log.Error("failed to serve", "err", err.Error())
+		return err
+	}
+
+	t.log.Trace("eth json-rpc server exit")
+	return nil
+}
+
+// 需要幂等
+func (t *EthRpcServMG) stopEthRpcServ() {
+	if t.servHD != nil {
+		// 优雅关闭http server
+		t.servHD.Shutdown(context.Background())
+	}
+}
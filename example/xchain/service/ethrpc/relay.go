@@ -0,0 +1,262 @@
+package ethrpc
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/evm/abi"
+
+	"github.com/xuperchain/xupercore/bcs/contract/evm"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/example/xchain/models"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	errRelayNotAllowed = errors.New("sender is not allowed to relay transactions by this node")
+	errRelayRateLimit  = errors.New("relay rate limit exceeded, try again later")
+)
+
+const (
+	// 交易手续费的转出地址
+	feePlaceholder = "$"
+	// abi中找不到对应函数时使用的合约方法名
+	fallbackMethod = "fallback"
+	// evm合约调用中以太坊原始交易的参数名
+	rawTxArg = "raw_tx"
+)
+
+// ethChainId 链上接受的以太坊交易的EIP-155链id，来自创世配置，全网一致
+func ethChainId(handle *models.ChainHandle) int64 {
+	return handle.GetChainCtx().Ledger.GenesisBlock.GetConfig().EthChainId
+}
+
+// relayLimiter 限制节点账户每分钟中继的交易数，避免节点账户的余额被手续费耗尽
+type relayLimiter struct {
+	mutex  sync.Mutex
+	limit  int
+	minute int64
+	count  int
+}
+
+func newRelayLimiter(limit int) *relayLimiter {
+	return &relayLimiter{limit: limit}
+}
+
+func (l *relayLimiter) allow() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	minute := time.Now().Unix() / 60
+	if minute != l.minute {
+		l.minute = minute
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
+
+// relayTx 由节点账户作为中继者提交以太坊交易对应的evm合约调用交易，中继者只支付交易手续费，
+// 调用方需要先检查发起地址在中继允许列表中并通过中继频率限制，
+// 原始交易在合约调用参数中，由evm合约虚拟机在各节点校验签名和nonce，合约内msg.sender为以太坊交易的发起地址
+func (t *EthRpcServ) relayTx(handle *models.ChainHandle, ethTx *evm.RawTx, raw []byte) (*lpb.Transaction, error) {
+	if len(ethTx.To) == 0 {
+		return nil, newRpcError(errCodeInvalidParams, "contract deployment not supported")
+	}
+	if ethTx.Value.Sign() != 0 {
+		return nil, newRpcError(errCodeInvalidParams, "transfer value not supported")
+	}
+	to, err := crypto.AddressFromBytes(ethTx.To)
+	if err != nil {
+		return nil, newRpcError(errCodeInvalidParams, err.Error())
+	}
+	req, err := newInvokeRequest(handle, to, ethTx.Data)
+	if err != nil {
+		return nil, err
+	}
+	req.Args = map[string][]byte{
+		rawTxArg: raw,
+	}
+
+	chainCtx := handle.GetChainCtx()
+	node := chainCtx.Address
+	authRequire := []string{node.Address}
+	res, err := handle.PreExec([]*protos.InvokeRequest{req}, node.Address, authRequire)
+	if err != nil {
+		return nil, newRpcError(errCodeServer, err.Error())
+	}
+	if _, err := lastResponse(res); err != nil {
+		return nil, err
+	}
+
+	tx := &lpb.Transaction{
+		Version:          1,
+		Nonce:            utils.GenNonce(),
+		Timestamp:        time.Now().UnixNano(),
+		Initiator:        node.Address,
+		AuthRequire:      authRequire,
+		TxInputsExt:      res.GetInputs(),
+		TxOutputsExt:     res.GetOutputs(),
+		ContractRequests: res.GetRequests(),
+	}
+	if !chainCtx.Ledger.GetNoFee() {
+		if err := fillFee(handle, tx, res.GetGasUsed()); err != nil {
+			return nil, err
+		}
+	}
+	tx.TxInputs = append(tx.TxInputs, res.GetUtxoInputs()...)
+	tx.TxOutputs = append(tx.TxOutputs, res.GetUtxoOutputs()...)
+
+	// 签名和生成txid
+	sign, err := txhash.ProcessSignTxWithHasher(chainCtx.Crypto, tx, []byte(node.PrivateKeyStr),
+		chainCtx.Ledger.GetHasher())
+	if err != nil {
+		return nil, err
+	}
+	signInfo := &protos.SignatureInfo{
+		PublicKey: node.PublicKeyStr,
+		Sign:      sign,
+	}
+	tx.InitiatorSigns = []*protos.SignatureInfo{signInfo}
+	tx.AuthRequireSigns = []*protos.SignatureInfo{signInfo}
+	tx.Txid, err = txhash.MakeTransactionIDWithHasher(tx, chainCtx.Ledger.GetHasher())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := handle.SubmitTx(tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// fillFee 使用节点账户的utxo支付手续费，
+// 需要手续费的链上交易不能没有utxo输入，手续费为0时也至少选择一个utxo并全部找零
+func fillFee(handle *models.ChainHandle, tx *lpb.Transaction, fee int64) error {
+	need := big.NewInt(fee)
+	if need.Sign() == 0 {
+		need = big.NewInt(1)
+	}
+	utxos, err := handle.SelectUtxo(tx.GetInitiator(), need, true, false)
+	if err != nil {
+		return err
+	}
+	total, ok := new(big.Int).SetString(utxos.GetTotalSelected(), 10)
+	if !ok {
+		return newRpcError(errCodeServer, "select utxo failed")
+	}
+	for _, utxo := range utxos.GetUtxoList() {
+		tx.TxInputs = append(tx.TxInputs, &protos.TxInput{
+			RefTxid:   utxo.GetRefTxid(),
+			RefOffset: utxo.GetRefOffset(),
+			FromAddr:  utxo.GetToAddr(),
+			Amount:    utxo.GetAmount(),
+		})
+	}
+	if fee > 0 {
+		tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{
+			ToAddr: []byte(feePlaceholder),
+			Amount: big.NewInt(fee).Bytes(),
+		})
+	}
+	// 多出来的utxo再转给自己
+	delta := total.Sub(total, big.NewInt(fee))
+	if delta.Sign() > 0 {
+		tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{
+			ToAddr: []byte(tx.GetInitiator()),
+			Amount: delta.Bytes(),
+		})
+	}
+	return nil
+}
+
+// methodName 根据函数选择器从abi中查找合约方法名
+func methodName(abiBuf []byte, input []byte) string {
+	if len(input) < abi.FunctionIDSize {
+		return fallbackMethod
+	}
+	spec, err := abi.ReadSpec(abiBuf)
+	if err != nil {
+		return fallbackMethod
+	}
+	for _, fn := range spec.Functions {
+		if bytes.Equal(fn.FunctionID[:], input[:abi.FunctionIDSize]) {
+			return fn.Name
+		}
+	}
+	return fallbackMethod
+}
+
+// relayedRawTx 返回中继交易的以太坊原始交易，非中继交易返回nil
+func relayedRawTx(tx *lpb.Transaction) []byte {
+	reqs := tx.GetContractRequests()
+	for i := len(reqs) - 1; i >= 0; i-- {
+		if reqs[i].GetModuleName() != "evm" {
+			continue
+		}
+		return reqs[i].GetArgs()[rawTxArg]
+	}
+	return nil
+}
+
+// txHash 中继交易返回以太坊交易哈希，其他交易返回交易id
+func txHash(tx *lpb.Transaction) string {
+	if raw := relayedRawTx(tx); raw != nil {
+		return encodeBytes(crypto.Keccak256(raw))
+	}
+	return encodeBytes(tx.GetTxid())
+}
+
+// txFrom 中继交易返回以太坊交易的发起地址，其他交易返回发起者对应的evm地址
+func txFrom(handle *models.ChainHandle, tx *lpb.Transaction) string {
+	if raw := relayedRawTx(tx); raw != nil {
+		if ethTx, err := evm.DecodeRawTx(raw, ethChainId(handle)); err == nil {
+			return encodeAddress(ethTx.From)
+		}
+	}
+	var addr crypto.Address
+	var err error
+	if evm.IsContractAccount(tx.GetInitiator()) {
+		addr, err = evm.ContractAccountToEVMAddress(tx.GetInitiator())
+	} else {
+		addr, err = evm.XchainToEVMAddress(tx.GetInitiator())
+	}
+	if err != nil {
+		return encodeAddress(crypto.ZeroAddress)
+	}
+	return encodeAddress(addr)
+}
+
+// txTo 返回交易中最后一个evm合约调用的合约地址
+func txTo(tx *lpb.Transaction) (string, bool) {
+	reqs := tx.GetContractRequests()
+	for i := len(reqs) - 1; i >= 0; i-- {
+		if reqs[i].GetModuleName() != "evm" {
+			continue
+		}
+		addr, err := evm.ContractNameToEVMAddress(reqs[i].GetContractName())
+		if err != nil {
+			return "", false
+		}
+		return encodeAddress(addr), true
+	}
+	return "", false
+}
+
+// txGasUsed 交易支付的手续费
+func txGasUsed(tx *lpb.Transaction) uint64 {
+	for _, output := range tx.GetTxOutputs() {
+		if string(output.GetToAddr()) == feePlaceholder {
+			return new(big.Int).SetBytes(output.GetAmount()).Uint64()
+		}
+	}
+	return 0
+}
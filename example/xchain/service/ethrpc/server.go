@@ -0,0 +1,237 @@
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"github.com/hyperledger/burrow/crypto"
+
+	sconf "github.com/xuperchain/xupercore/example/xchain/common/config"
+	sctx "github.com/xuperchain/xupercore/example/xchain/common/context"
+	"github.com/xuperchain/xupercore/example/xchain/models"
+	ecom "github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	jsonrpcVersion = "2.0"
+
+	// json-rpc标准错误码
+	errCodeParse          = -32700
+	errCodeInvalidRequest = -32600
+	errCodeMethodNotFound = -32601
+	errCodeInvalidParams  = -32602
+	errCodeInternal       = -32603
+	// 以太坊节点通用的服务端错误码
+	errCodeServer = -32000
+	// 合约执行失败
+	errCodeExecution = 3
+)
+
+type jsonrpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type jsonrpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func newRpcError(code int, msg string) *rpcError {
+	return &rpcError{Code: code, Message: msg}
+}
+
+// 接口处理函数，params为json-rpc请求的参数数组
+type handlerFunc func(handle *models.ChainHandle, params []json.RawMessage) (interface{}, error)
+
+// EthRpcServ 以太坊json-rpc兼容服务，将eth_*接口转换为链上的合约预执行、交易提交和账本查询
+type EthRpcServ struct {
+	engine  ecom.Engine
+	bcName  string
+	maxSize int
+	log     logs.Logger
+	methods map[string]handlerFunc
+
+	// 允许节点账户中继的以太坊交易发起地址
+	relayAllowList map[crypto.Address]bool
+	relayLimiter   *relayLimiter
+}
+
+func NewEthRpcServ(engine ecom.Engine, scfg *sconf.ServConf, log logs.Logger) *EthRpcServ {
+	t := &EthRpcServ{
+		engine:         engine,
+		bcName:         scfg.EthBCName,
+		maxSize:        scfg.MaxRecvMsgSize,
+		log:            log,
+		relayAllowList: make(map[crypto.Address]bool),
+		relayLimiter:   newRelayLimiter(scfg.EthRelayPerMinute),
+	}
+	for _, s := range scfg.EthRelayAllowList {
+		addr, err := decodeAddress(s)
+		if err != nil {
+			log.Warn("invalid eth relay address in config", "address", s)
+			continue
+		}
+		t.relayAllowList[addr] = true
+	}
+	t.methods = map[string]handlerFunc{
+		"eth_chainId":               t.ChainId,
+		"net_version":               t.NetVersion,
+		"eth_blockNumber":           t.BlockNumber,
+		"eth_gasPrice":              t.GasPrice,
+		"eth_getBalance":            t.GetBalance,
+		"eth_getTransactionCount":   t.GetTransactionCount,
+		"eth_call":                  t.Call,
+		"eth_estimateGas":           t.EstimateGas,
+		"eth_sendRawTransaction":    t.SendRawTransaction,
+		"eth_getTransactionReceipt": t.GetTransactionReceipt,
+		"eth_getLogs":               t.GetLogs,
+	}
+	return t
+}
+
+// ServeHTTP 处理json-rpc请求，支持批量请求
+func (t *EthRpcServ) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, int64(t.maxSize)))
+	if err != nil {
+		t.writeResponse(w, &jsonrpcResponse{
+			JSONRPC: jsonrpcVersion,
+			ID:      json.RawMessage("null"),
+			Error:   newRpcError(errCodeParse, err.Error()),
+		})
+		return
+	}
+
+	clientIp, _, _ := net.SplitHostPort(r.RemoteAddr)
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []*jsonrpcRequest
+		if err := json.Unmarshal(body, &reqs); err != nil || len(reqs) == 0 {
+			t.writeResponse(w, &jsonrpcResponse{
+				JSONRPC: jsonrpcVersion,
+				ID:      json.RawMessage("null"),
+				Error:   newRpcError(errCodeParse, "invalid batch request"),
+			})
+			return
+		}
+		resps := make([]*jsonrpcResponse, 0, len(reqs))
+		for _, req := range reqs {
+			resps = append(resps, t.handleRequest(req, clientIp))
+		}
+		t.writeResponse(w, resps)
+		return
+	}
+
+	var req jsonrpcRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.writeResponse(w, &jsonrpcResponse{
+			JSONRPC: jsonrpcVersion,
+			ID:      json.RawMessage("null"),
+			Error:   newRpcError(errCodeParse, err.Error()),
+		})
+		return
+	}
+	t.writeResponse(w, t.handleRequest(&req, clientIp))
+}
+
+func (t *EthRpcServ) handleRequest(req *jsonrpcRequest, clientIp string) *jsonrpcResponse {
+	resp := &jsonrpcResponse{
+		JSONRPC: jsonrpcVersion,
+		ID:      req.ID,
+	}
+	if len(resp.ID) == 0 {
+		resp.ID = json.RawMessage("null")
+	}
+
+	// panic recover
+	defer func() {
+		if e := recover(); e != nil {
+			t.log.Error("eth json-rpc server happen panic.", "error", e, "rpc_method", req.Method)
+			resp.Result = nil
+			resp.Error = newRpcError(errCodeInternal, "internal error")
+		}
+	}()
+
+	if req.JSONRPC != jsonrpcVersion || req.Method == "" {
+		resp.Error = newRpcError(errCodeInvalidRequest, "invalid request")
+		return resp
+	}
+	handler, ok := t.methods[req.Method]
+	if !ok {
+		resp.Error = newRpcError(errCodeMethodNotFound,
+			"the method "+req.Method+" does not exist/is not available")
+		return resp
+	}
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			resp.Error = newRpcError(errCodeInvalidParams, "params must be an array")
+			return resp
+		}
+	}
+
+	// 创建请求上下文
+	rctx, err := sctx.NewReqCtx(t.engine, utils.GenLogId(), clientIp)
+	if err != nil {
+		t.log.Error("create request context failed", "error", err)
+		resp.Error = newRpcError(errCodeInternal, "create request context failed")
+		return resp
+	}
+	rctx.GetLog().Trace("access request", "client_ip", clientIp, "rpc_method", req.Method)
+
+	result, err := t.invoke(rctx, handler, params)
+	if err == nil {
+		resp.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		if rerr, ok := err.(*rpcError); ok {
+			resp.Error = rerr
+		} else {
+			resp.Error = newRpcError(errCodeServer, err.Error())
+		}
+	}
+
+	rctx.GetLog().Info("request done", "client_ip", clientIp, "rpc_method", req.Method,
+		"err", err, "cost_time", rctx.GetTimer().Print())
+	return resp
+}
+
+func (t *EthRpcServ) invoke(rctx sctx.ReqCtx, handler handlerFunc,
+	params []json.RawMessage) (interface{}, error) {
+	handle, err := models.NewChainHandle(t.bcName, rctx)
+	if err != nil {
+		rctx.GetLog().Warn("new chain handle failed", "err", err.Error())
+		return nil, err
+	}
+	return handler(handle, params)
+}
+
+func (t *EthRpcServ) writeResponse(w http.ResponseWriter, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		t.log.Warn("write eth json-rpc response failed", "err", err)
+	}
+}
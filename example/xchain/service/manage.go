@@ -5,6 +5,7 @@ import (
 
 	sconf "github.com/xuperchain/xupercore/example/xchain/common/config"
 	"github.com/xuperchain/xupercore/example/xchain/common/def"
+	"github.com/xuperchain/xupercore/example/xchain/service/ethrpc"
 	"github.com/xuperchain/xupercore/example/xchain/service/rpc"
 	"github.com/xuperchain/xupercore/kernel/engines"
 	"github.com/xuperchain/xupercore/lib/logs"
@@ -42,6 +43,15 @@ func NewServMG(scfg *sconf.ServConf, engine engines.BCEngine) (*ServMG, error) {
 	}
 	obj.servers = append(obj.servers, rpcServ)
 
+	// 实例化eth json-rpc服务，未配置端口时不启动
+	if scfg.EthRpcPort > 0 {
+		ethRpcServ, err := ethrpc.NewEthRpcServMG(scfg, engine)
+		if err != nil {
+			return nil, err
+		}
+		obj.servers = append(obj.servers, ethRpcServ)
+	}
+
 	return obj, nil
 }
 
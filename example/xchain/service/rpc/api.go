@@ -208,6 +208,7 @@ func (t *RpcServ) QueryChainStatus(gctx context.Context,
 		resp.LedgerMeta = res.LedgerMeta
 		resp.UtxoMeta = res.UtxoMeta
 		resp.BranchBlockId = res.BranchIds
+		resp.HashType = handle.GetChainCtx().Ledger.GetHasher().Name()
 	}
 
 	return resp, err
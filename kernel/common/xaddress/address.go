@@ -4,7 +4,9 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 
 	cryptoClinet "github.com/xuperchain/xupercore/lib/crypto/client/base"
 )
@@ -15,6 +17,8 @@ type Address struct {
 	PrivateKeyStr string
 	PublicKey     *ecdsa.PublicKey
 	PublicKeyStr  string
+	// BlsPrivateKeyStr 为hex编码的BLS私钥，可选，用于chained-bft的BLS聚合签名
+	BlsPrivateKeyStr string
 }
 
 func LoadAddress(keyDir string) (string, error) {
@@ -50,12 +54,19 @@ func LoadAddrInfo(keyDir string, crypto cryptoClinet.CryptoClient) (*Address, er
 		return nil, fmt.Errorf("decode public.key error: %v", err)
 	}
 
+	// bls.key为可选文件，仅开启BLS聚合签名的共识节点需要
+	blsKey, err := ioutil.ReadFile(filepath.Join(keyDir, "bls.key"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read bls.key error: %v", err)
+	}
+
 	addInfo := &Address{
-		Address:       string(addr),
-		PrivateKey:    privateKey,
-		PrivateKeyStr: string(priKey),
-		PublicKey:     publicKey,
-		PublicKeyStr:  string(pubKey),
+		Address:          string(addr),
+		PrivateKey:       privateKey,
+		PrivateKeyStr:    string(priKey),
+		PublicKey:        publicKey,
+		PublicKeyStr:     string(pubKey),
+		BlsPrivateKeyStr: strings.TrimSpace(string(blsKey)),
 	}
 	return addInfo, nil
 }
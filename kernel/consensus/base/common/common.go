@@ -103,13 +103,20 @@ func OldQCToNew(storage []byte) (bftStorage.QuorumCertInterface, error) {
 	if err != nil {
 		return nil, err
 	}
-	newQC := bftStorage.NewQuorumCert(
-		&bftStorage.VoteInfo{
-			ProposalId:   oldQC.ProposalId,
-			ProposalView: oldQC.ViewNumber,
-			ParentId:     justifyQC.ProposalId,
-			ParentView:   justifyQC.ViewNumber,
-		}, nil, OldSignToNew(storage))
+	vote := &bftStorage.VoteInfo{
+		ProposalId:   oldQC.ProposalId,
+		ProposalView: oldQC.ViewNumber,
+		ParentId:     justifyQC.ProposalId,
+		ParentView:   justifyQC.ViewNumber,
+	}
+	// BLS模式下justify仅携带聚合签名
+	if agg := oldQC.GetAggregateSign(); agg != nil {
+		return bftStorage.NewAggregateQuorumCert(vote, nil, &bftPb.QuorumCertAggregateSign{
+			Bitmap: agg.Bitmap,
+			Sign:   agg.Sign,
+		}), nil
+	}
+	newQC := bftStorage.NewQuorumCert(vote, nil, OldSignToNew(storage))
 	return newQC, nil
 }
 
@@ -133,6 +140,12 @@ func NewToOldQC(new *bftStorage.QuorumCert) (*lpb.QuorumCert, error) {
 		QCSignInfos: sign,
 	}
 	oldQC.SignInfos = ss
+	if agg := new.GetAggregateSign(); agg != nil {
+		oldQC.AggregateSign = &lpb.QCAggregateSign{
+			Bitmap: agg.Bitmap,
+			Sign:   agg.Sign,
+		}
+	}
 	return oldQC, nil
 }
 
@@ -170,3 +183,19 @@ func NewSignToOld(new []*bftPb.QuorumCertSign) []*lpb.SignInfo {
 	}
 	return oldS
 }
+
+// OldAggregateSignToNew 获取老结构中BLS模式下的聚合签名
+func OldAggregateSignToNew(storage []byte) *bftPb.QuorumCertAggregateSign {
+	oldS, err := ParseOldQCStorage(storage)
+	if err != nil {
+		return nil
+	}
+	oldQC := oldS.Justify
+	if oldQC == nil || oldQC.GetAggregateSign() == nil {
+		return nil
+	}
+	return &bftPb.QuorumCertAggregateSign{
+		Bitmap: oldQC.GetAggregateSign().Bitmap,
+		Sign:   oldQC.GetAggregateSign().Sign,
+	}
+}
@@ -4,26 +4,58 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"math/bits"
 
-	"github.com/xuperchain/crypto/core/hash"
 	pb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/lib/crypto/bls"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
 )
 
+const timeoutMsgPrefix = "chained-bft-timeout"
+
+var (
+	ErrEmptyBlsKey        = errors.New("bls private key of local node is empty")
+	ErrEmptyAggregation   = errors.New("no valid vote sign to aggregate")
+	ErrInvalidBitmap      = errors.New("signer bitmap of aggregate sign is invalid")
+	ErrInvalidAggregate   = errors.New("aggregate sign is invalid")
+	ErrBlsNotEnabled      = errors.New("bls mode is not enabled")
+	ErrUnregisteredSigner = errors.New("signer hasn't registered a bls public key")
+)
+
+// BlsKeyReader 用于查询validator注册的BLS公钥
+type BlsKeyReader interface {
+	GetBlsPublicKey(address string) (*bls.PublicKey, error)
+}
+
 type CBFTCrypto struct {
 	Address      *cctx.Address
 	CryptoClient cctx.CryptoClient
+	// BcName 所属链名，加入超时消息摘要，避免签名在validators相同的其他链上被重放
+	BcName string
+	// Hasher 消息摘要使用的哈希算法，与链的区块id哈希算法一致
+	Hasher *hash.Hasher
+	// BlsKey 本地节点的BLS私钥，仅validator需要，用于vote签名
+	BlsKey *bls.PrivateKey
+	// BlsKeys 非空时开启BLS模式，vote使用BLS签名，QC使用聚合签名
+	BlsKeys BlsKeyReader
 }
 
 func NewCBFTCrypto(addr *cctx.Address, c cctx.CryptoClient) *CBFTCrypto {
+	return NewCBFTCryptoWithHasher(addr, c, hash.DefaultHasher)
+}
+
+// NewCBFTCryptoWithHasher 使用链配置的哈希算法计算消息摘要
+func NewCBFTCryptoWithHasher(addr *cctx.Address, c cctx.CryptoClient, hasher *hash.Hasher) *CBFTCrypto {
 	return &CBFTCrypto{
 		Address:      addr,
 		CryptoClient: c,
+		Hasher:       hasher,
 	}
 }
 
 func (c *CBFTCrypto) SignProposalMsg(msg *pb.ProposalMsg) (*pb.ProposalMsg, error) {
-	msgDigest, err := MakeProposalMsgDigest(msg)
+	msgDigest, err := MakeProposalMsgDigestWithHasher(msg, c.Hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -42,12 +74,17 @@ func (c *CBFTCrypto) SignProposalMsg(msg *pb.ProposalMsg) (*pb.ProposalMsg, erro
 
 // MakePhaseMsgDigest make ChainedBftPhaseMessage Digest
 func MakeProposalMsgDigest(msg *pb.ProposalMsg) ([]byte, error) {
+	return MakeProposalMsgDigestWithHasher(msg, hash.DefaultHasher)
+}
+
+// MakeProposalMsgDigestWithHasher make ChainedBftPhaseMessage Digest with hash algorithm of chain
+func MakeProposalMsgDigestWithHasher(msg *pb.ProposalMsg, hasher *hash.Hasher) ([]byte, error) {
 	msgEncoder, err := encodeProposalMsg(msg)
 	if err != nil {
 		return nil, err
 	}
-	msg.MsgDigest = hash.DoubleSha256(msgEncoder)
-	return hash.DoubleSha256(msgEncoder), nil
+	msg.MsgDigest = hasher.DoubleHash(msgEncoder)
+	return msg.MsgDigest, nil
 }
 
 func encodeProposalMsg(msg *pb.ProposalMsg) ([]byte, error) {
@@ -68,8 +105,75 @@ func encodeProposalMsg(msg *pb.ProposalMsg) ([]byte, error) {
 	return msgBuf.Bytes(), nil
 }
 
+// SignTimeoutMsg make ChainedBftTimeoutMessage sign
+// 签名内容仅包含链名和超时的view，因此同一view的超时签名可直接聚合为TimeoutCert并统一验证
+func (c *CBFTCrypto) SignTimeoutMsg(msg *pb.TimeoutMsg) (*pb.TimeoutMsg, error) {
+	msgDigest, err := c.MakeTimeoutMsgDigest(msg.View)
+	if err != nil {
+		return nil, err
+	}
+	msg.MsgDigest = msgDigest
+	sign, err := c.CryptoClient.SignECDSA(c.Address.PrivateKey, msgDigest)
+	if err != nil {
+		return nil, err
+	}
+	msg.Sign = &pb.QuorumCertSign{
+		Address:   c.Address.Address,
+		PublicKey: c.Address.PublicKeyStr,
+		Sign:      sign,
+	}
+	return msg, nil
+}
+
+// MakeTimeoutMsgDigest make ChainedBftTimeoutMessage Digest with chain name and hash algorithm of c
+func (c *CBFTCrypto) MakeTimeoutMsgDigest(view int64) ([]byte, error) {
+	return MakeTimeoutMsgDigestWithHasher(c.BcName, view, c.Hasher)
+}
+
+// MakeTimeoutMsgDigestWithHasher make ChainedBftTimeoutMessage Digest with hash algorithm of chain
+func MakeTimeoutMsgDigestWithHasher(bcName string, view int64, hasher *hash.Hasher) ([]byte, error) {
+	var msgBuf bytes.Buffer
+	encoder := json.NewEncoder(&msgBuf)
+	// 加入消息类型前缀和链名，避免超时签名被挪用为其他类型消息或其他链的签名
+	if err := encoder.Encode(timeoutMsgPrefix); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bcName); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(view); err != nil {
+		return nil, err
+	}
+	return hasher.DoubleHash(msgBuf.Bytes()), nil
+}
+
+// EnableBls 开启BLS模式，sk为本地节点BLS私钥，非validator节点可为nil
+func (c *CBFTCrypto) EnableBls(sk *bls.PrivateKey, keys BlsKeyReader) {
+	c.BlsKey = sk
+	c.BlsKeys = keys
+}
+
+// IsBlsEnabled 是否开启了BLS模式
+func (c *CBFTCrypto) IsBlsEnabled() bool {
+	return c.BlsKeys != nil
+}
+
 // SignVoteMsg make ChainedBftVoteMessage sign
+// BLS模式下签名为BLS签名，此时公钥由链上注册信息获取，不再随签名携带
 func (c *CBFTCrypto) SignVoteMsg(msg []byte) (*pb.QuorumCertSign, error) {
+	if c.IsBlsEnabled() {
+		if c.BlsKey == nil {
+			return nil, ErrEmptyBlsKey
+		}
+		sign, err := c.BlsKey.Sign(msg)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.QuorumCertSign{
+			Address: c.Address.Address,
+			Sign:    sign,
+		}, nil
+	}
 	sign, err := c.CryptoClient.SignECDSA(c.Address.PrivateKey, msg)
 	if err != nil {
 		return nil, err
@@ -95,3 +199,100 @@ func (c *CBFTCrypto) VerifyVoteMsgSign(sig *pb.QuorumCertSign, msg []byte) (bool
 	}
 	return c.CryptoClient.VerifyECDSA(ak, sig.GetSign(), msg)
 }
+
+// VerifyBlsVoteSign 使用签名者注册的BLS公钥验证单个vote签名
+func (c *CBFTCrypto) VerifyBlsVoteSign(sig *pb.QuorumCertSign, msg []byte) (bool, error) {
+	if !c.IsBlsEnabled() {
+		return false, ErrBlsNotEnabled
+	}
+	pk, err := c.BlsKeys.GetBlsPublicKey(sig.GetAddress())
+	if err != nil {
+		return false, err
+	}
+	if pk == nil {
+		return false, ErrUnregisteredSigner
+	}
+	if !pk.Verify(msg, sig.GetSign()) {
+		return false, errors.New("VerifyBlsVoteSign error, sign not match bls pk of " + sig.GetAddress())
+	}
+	return true, nil
+}
+
+// AggregateVoteSigns 将来自validators的BLS vote签名聚合为一个签名
+// 位图第i位(第i/8字节的第i%8位，低位在前)标记validators[i]是否签名，同一地址仅计一次，非validator的签名将被忽略
+func (c *CBFTCrypto) AggregateVoteSigns(signs []*pb.QuorumCertSign, validators []string) (*pb.QuorumCertAggregateSign, error) {
+	index := make(map[string]int, len(validators))
+	for i, v := range validators {
+		if _, ok := index[v]; !ok {
+			index[v] = i
+		}
+	}
+	bitmap := make([]byte, (len(validators)+7)/8)
+	var sigs [][]byte
+	for _, sign := range signs {
+		i, ok := index[sign.GetAddress()]
+		if !ok || bitmap[i/8]&(1<<uint(i%8)) != 0 {
+			continue
+		}
+		bitmap[i/8] |= 1 << uint(i%8)
+		sigs = append(sigs, sign.GetSign())
+	}
+	if len(sigs) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	aggSign, err := bls.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.QuorumCertAggregateSign{
+		Bitmap: bitmap,
+		Sign:   aggSign,
+	}, nil
+}
+
+// VerifyAggregateSign 验证聚合签名，返回位图中签名者的数量
+func (c *CBFTCrypto) VerifyAggregateSign(agg *pb.QuorumCertAggregateSign, msg []byte, validators []string) (int, error) {
+	if !c.IsBlsEnabled() {
+		return 0, ErrBlsNotEnabled
+	}
+	bitmap := agg.GetBitmap()
+	if len(bitmap) != (len(validators)+7)/8 {
+		return 0, ErrInvalidBitmap
+	}
+	var pks []*bls.PublicKey
+	counted := make(map[string]bool)
+	for i := 0; i < len(bitmap)*8; i++ {
+		if bitmap[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		// 超出validators范围的位必须为0，保证同一组签名者仅有唯一的位图
+		if i >= len(validators) || counted[validators[i]] {
+			return 0, ErrInvalidBitmap
+		}
+		counted[validators[i]] = true
+		pk, err := c.BlsKeys.GetBlsPublicKey(validators[i])
+		if err != nil {
+			return 0, err
+		}
+		if pk == nil {
+			return 0, ErrUnregisteredSigner
+		}
+		pks = append(pks, pk)
+	}
+	if len(pks) == 0 {
+		return 0, ErrInvalidBitmap
+	}
+	if !bls.VerifyAggregate(pks, msg, agg.GetSign()) {
+		return 0, ErrInvalidAggregate
+	}
+	return len(pks), nil
+}
+
+// CountAggregateSigners 返回聚合签名位图中的签名者数量，不做签名校验
+func CountAggregateSigners(agg *pb.QuorumCertAggregateSign) int {
+	cnt := 0
+	for _, b := range agg.GetBitmap() {
+		cnt += bits.OnesCount8(b)
+	}
+	return cnt
+}
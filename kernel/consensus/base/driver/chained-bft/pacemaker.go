@@ -2,6 +2,7 @@ package chained_bft
 
 import (
 	"errors"
+	"time"
 
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
 )
@@ -15,19 +16,35 @@ type PacemakerInterface interface {
 	GetCurrentView() int64
 	// 原NextNewProposal，generate new proposal directly.
 	AdvanceView(qc storage.QuorumCertInterface) (bool, error)
+	// AdvanceViewByTC 收到超时证书后跳过超时的view
+	AdvanceViewByTC(tc *storage.TimeoutCert) (bool, error)
+	// GetTimeout 返回当前view的超时时长，返回0表示不开启超时机制
+	GetTimeout() time.Duration
 }
 
 var (
 	ErrNilQC = errors.New("pacemaker meets a nil qc")
+	ErrNilTC = errors.New("pacemaker meets a nil tc")
+)
+
+const (
+	// DefaultMaxBackoff 为超时时长指数退避的最大指数
+	DefaultMaxBackoff = 6
 )
 
 // DefaultPaceMaker 是一个PacemakerInterface的默认实现，我们与PacemakerInterface放置在一起，方便查看
 // PacemakerInterface的新实现直接直接替代DefaultPaceMaker即可
 // The Pacemaker keeps track of votes and of time.
-// TODO:  the Pacemaker broadcasts a TimeoutMsg notification.
+// 当前view超时后，smr广播TimeoutMsg，收集到2f+1个TimeoutMsg后生成TC并调用AdvanceViewByTC跳过该view
+// 连续超时的情况下，超时时长按BaseTimeout*2^n指数退避，直至收到新的QC后重置
 type DefaultPaceMaker struct {
 	CurrentView int64
-	// timeout int64
+	// BaseTimeout 为view的基础超时时长，为0时不开启超时机制
+	BaseTimeout time.Duration
+	// MaxTimeout 为退避后的超时时长上限，为0时以BaseTimeout*2^DefaultMaxBackoff为上限
+	MaxTimeout time.Duration
+	// timeoutCount 记录自上一个QC以来连续超时的次数
+	timeoutCount int
 }
 
 func (p *DefaultPaceMaker) AdvanceView(qc storage.QuorumCertInterface) (bool, error) {
@@ -37,6 +54,23 @@ func (p *DefaultPaceMaker) AdvanceView(qc storage.QuorumCertInterface) (bool, er
 	r := qc.GetProposalView()
 	if r+1 > p.CurrentView {
 		p.CurrentView = r + 1
+		// 收到新QC证明网络已恢复，重置退避
+		p.timeoutCount = 0
+	}
+	return true, nil
+}
+
+func (p *DefaultPaceMaker) AdvanceViewByTC(tc *storage.TimeoutCert) (bool, error) {
+	if tc == nil {
+		return false, ErrNilTC
+	}
+	r := tc.GetView()
+	if r+1 <= p.CurrentView {
+		return false, nil
+	}
+	p.CurrentView = r + 1
+	if p.timeoutCount < DefaultMaxBackoff {
+		p.timeoutCount++
 	}
 	return true, nil
 }
@@ -44,3 +78,18 @@ func (p *DefaultPaceMaker) AdvanceView(qc storage.QuorumCertInterface) (bool, er
 func (p *DefaultPaceMaker) GetCurrentView() int64 {
 	return p.CurrentView
 }
+
+func (p *DefaultPaceMaker) GetTimeout() time.Duration {
+	if p.BaseTimeout <= 0 {
+		return 0
+	}
+	timeout := p.BaseTimeout << uint(p.timeoutCount)
+	maxTimeout := p.MaxTimeout
+	if maxTimeout <= 0 {
+		maxTimeout = p.BaseTimeout << DefaultMaxBackoff
+	}
+	if timeout > maxTimeout {
+		return maxTimeout
+	}
+	return timeout
+}
@@ -2,6 +2,7 @@ package chained_bft
 
 import (
 	"testing"
+	"time"
 
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
 )
@@ -25,3 +26,52 @@ func TestPaceMaker(t *testing.T) {
 		t.Error("GetCurrentView error.")
 	}
 }
+
+func TestPaceMakerTimeout(t *testing.T) {
+	p := &DefaultPaceMaker{
+		CurrentView: 1,
+	}
+	if p.GetTimeout() != 0 {
+		t.Error("GetTimeout should be disabled without BaseTimeout.")
+		return
+	}
+	p.BaseTimeout = time.Second
+	p.MaxTimeout = 3 * time.Second
+	if p.GetTimeout() != time.Second {
+		t.Error("GetTimeout base error.", "timeout", p.GetTimeout())
+		return
+	}
+	tc := storage.NewTimeoutCert(1, nil)
+	if ok, _ := p.AdvanceViewByTC(tc); !ok || p.GetCurrentView() != 2 {
+		t.Error("AdvanceViewByTC error.", "view", p.GetCurrentView())
+		return
+	}
+	// 重复的TC不推进view，也不增加退避
+	if ok, _ := p.AdvanceViewByTC(tc); ok {
+		t.Error("AdvanceViewByTC with stale tc error.")
+		return
+	}
+	if p.GetTimeout() != 2*time.Second {
+		t.Error("GetTimeout backoff error.", "timeout", p.GetTimeout())
+		return
+	}
+	p.AdvanceViewByTC(storage.NewTimeoutCert(2, nil))
+	if p.GetTimeout() != 3*time.Second {
+		t.Error("GetTimeout max timeout error.", "timeout", p.GetTimeout())
+		return
+	}
+	// 收到新QC后重置退避
+	qc := &storage.QuorumCert{
+		VoteInfo: &storage.VoteInfo{
+			ProposalId:   []byte{3},
+			ProposalView: 3,
+		},
+	}
+	p.AdvanceView(qc)
+	if p.GetCurrentView() != 4 || p.GetTimeout() != time.Second {
+		t.Error("AdvanceView reset backoff error.", "view", p.GetCurrentView(), "timeout", p.GetTimeout())
+	}
+	if _, err := p.AdvanceViewByTC(nil); err != ErrNilTC {
+		t.Error("AdvanceViewByTC nil tc error.")
+	}
+}
@@ -76,6 +76,54 @@ func (m *QuorumCertSign) GetSign() []byte {
 	return nil
 }
 
+// QuorumCertAggregateSign 是BLS模式下的聚合签名，Bitmap按验证人顺序标记签名者
+type QuorumCertAggregateSign struct {
+	Bitmap               []byte   `protobuf:"bytes,1,opt,name=Bitmap,proto3" json:"Bitmap,omitempty"`
+	Sign                 []byte   `protobuf:"bytes,2,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuorumCertAggregateSign) Reset()         { *m = QuorumCertAggregateSign{} }
+func (m *QuorumCertAggregateSign) String() string { return proto.CompactTextString(m) }
+func (*QuorumCertAggregateSign) ProtoMessage()    {}
+func (*QuorumCertAggregateSign) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f59372df81539441, []int{1}
+}
+
+func (m *QuorumCertAggregateSign) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuorumCertAggregateSign.Unmarshal(m, b)
+}
+func (m *QuorumCertAggregateSign) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuorumCertAggregateSign.Marshal(b, m, deterministic)
+}
+func (m *QuorumCertAggregateSign) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuorumCertAggregateSign.Merge(m, src)
+}
+func (m *QuorumCertAggregateSign) XXX_Size() int {
+	return xxx_messageInfo_QuorumCertAggregateSign.Size(m)
+}
+func (m *QuorumCertAggregateSign) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuorumCertAggregateSign.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuorumCertAggregateSign proto.InternalMessageInfo
+
+func (m *QuorumCertAggregateSign) GetBitmap() []byte {
+	if m != nil {
+		return m.Bitmap
+	}
+	return nil
+}
+
+func (m *QuorumCertAggregateSign) GetSign() []byte {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
 // ProposalMsg 是chained-bft中定义的Block形式，区别在于其有一个parentQC，该存储只供chained-bft类使用
 // ProposalMsg的结构就类似一个Block的结构
 type ProposalMsg struct {
@@ -89,7 +137,9 @@ type ProposalMsg struct {
 	// 签名
 	Sign *QuorumCertSign `protobuf:"bytes,5,opt,name=Sign,proto3" json:"Sign,omitempty"`
 	// 消息摘要
-	MsgDigest            []byte   `protobuf:"bytes,6,opt,name=MsgDigest,proto3" json:"MsgDigest,omitempty"`
+	MsgDigest []byte `protobuf:"bytes,6,opt,name=MsgDigest,proto3" json:"MsgDigest,omitempty"`
+	// 超时证书，proposal跳过view时作为依据
+	TimeoutCert          []byte   `protobuf:"bytes,7,opt,name=TimeoutCert,proto3" json:"TimeoutCert,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -99,7 +149,7 @@ func (m *ProposalMsg) Reset()         { *m = ProposalMsg{} }
 func (m *ProposalMsg) String() string { return proto.CompactTextString(m) }
 func (*ProposalMsg) ProtoMessage()    {}
 func (*ProposalMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f59372df81539441, []int{1}
+	return fileDescriptor_f59372df81539441, []int{2}
 }
 
 func (m *ProposalMsg) XXX_Unmarshal(b []byte) error {
@@ -162,6 +212,13 @@ func (m *ProposalMsg) GetMsgDigest() []byte {
 	return nil
 }
 
+func (m *ProposalMsg) GetTimeoutCert() []byte {
+	if m != nil {
+		return m.TimeoutCert
+	}
+	return nil
+}
+
 // VoteMsg is the vote message of the protocal.
 type VoteMsg struct {
 	VoteInfo             []byte            `protobuf:"bytes,1,opt,name=VoteInfo,proto3" json:"VoteInfo,omitempty"`
@@ -176,7 +233,7 @@ func (m *VoteMsg) Reset()         { *m = VoteMsg{} }
 func (m *VoteMsg) String() string { return proto.CompactTextString(m) }
 func (*VoteMsg) ProtoMessage()    {}
 func (*VoteMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f59372df81539441, []int{2}
+	return fileDescriptor_f59372df81539441, []int{3}
 }
 
 func (m *VoteMsg) XXX_Unmarshal(b []byte) error {
@@ -218,34 +275,108 @@ func (m *VoteMsg) GetSignature() []*QuorumCertSign {
 	return nil
 }
 
+// TimeoutMsg 是节点在本地view超时后广播的消息，携带本地HighQC
+type TimeoutMsg struct {
+	// 超时的view
+	View int64 `protobuf:"varint,1,opt,name=View,proto3" json:"View,omitempty"`
+	// 本地HighQC
+	HighQC []byte `protobuf:"bytes,2,opt,name=HighQC,proto3" json:"HighQC,omitempty"`
+	// 签名
+	Sign *QuorumCertSign `protobuf:"bytes,3,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	// 消息摘要
+	MsgDigest            []byte   `protobuf:"bytes,4,opt,name=MsgDigest,proto3" json:"MsgDigest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TimeoutMsg) Reset()         { *m = TimeoutMsg{} }
+func (m *TimeoutMsg) String() string { return proto.CompactTextString(m) }
+func (*TimeoutMsg) ProtoMessage()    {}
+func (*TimeoutMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f59372df81539441, []int{4}
+}
+
+func (m *TimeoutMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TimeoutMsg.Unmarshal(m, b)
+}
+func (m *TimeoutMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TimeoutMsg.Marshal(b, m, deterministic)
+}
+func (m *TimeoutMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimeoutMsg.Merge(m, src)
+}
+func (m *TimeoutMsg) XXX_Size() int {
+	return xxx_messageInfo_TimeoutMsg.Size(m)
+}
+func (m *TimeoutMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimeoutMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimeoutMsg proto.InternalMessageInfo
+
+func (m *TimeoutMsg) GetView() int64 {
+	if m != nil {
+		return m.View
+	}
+	return 0
+}
+
+func (m *TimeoutMsg) GetHighQC() []byte {
+	if m != nil {
+		return m.HighQC
+	}
+	return nil
+}
+
+func (m *TimeoutMsg) GetSign() *QuorumCertSign {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+func (m *TimeoutMsg) GetMsgDigest() []byte {
+	if m != nil {
+		return m.MsgDigest
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*QuorumCertSign)(nil), "chainedBftPb.QuorumCertSign")
+	proto.RegisterType((*QuorumCertAggregateSign)(nil), "chainedBftPb.QuorumCertAggregateSign")
 	proto.RegisterType((*ProposalMsg)(nil), "chainedBftPb.ProposalMsg")
 	proto.RegisterType((*VoteMsg)(nil), "chainedBftPb.VoteMsg")
+	proto.RegisterType((*TimeoutMsg)(nil), "chainedBftPb.TimeoutMsg")
 }
 
 func init() { proto.RegisterFile("chainedBFTMsg.proto", fileDescriptor_f59372df81539441) }
 
 var fileDescriptor_f59372df81539441 = []byte{
-	// 307 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x41, 0x4b, 0xc3, 0x30,
-	0x14, 0xc7, 0x89, 0x9d, 0x9b, 0x7b, 0x2b, 0x22, 0xf1, 0x12, 0x64, 0x48, 0xe9, 0x69, 0x78, 0x18,
-	0xa2, 0x37, 0x6f, 0x3a, 0x11, 0xa6, 0x16, 0xba, 0x28, 0x3b, 0x79, 0x69, 0xd7, 0x34, 0x06, 0xd6,
-	0xa6, 0x24, 0x29, 0xb2, 0x0f, 0xe1, 0x47, 0xf4, 0xbb, 0x48, 0xd2, 0x75, 0xdd, 0xf0, 0xe0, 0x2d,
-	0xff, 0x5f, 0xf2, 0xde, 0xff, 0xfd, 0x5f, 0xe0, 0x7c, 0xf5, 0x99, 0x88, 0x92, 0x65, 0x0f, 0x4f,
-	0xef, 0x91, 0xe6, 0xd3, 0x4a, 0x49, 0x23, 0xb1, 0xdf, 0xc2, 0xdc, 0xc4, 0x69, 0xf8, 0x01, 0xa7,
-	0x8b, 0x5a, 0xaa, 0xba, 0x98, 0x31, 0x65, 0xde, 0x04, 0x2f, 0x31, 0x81, 0xc1, 0x7d, 0x96, 0x29,
-	0xa6, 0x35, 0x41, 0x01, 0x9a, 0x0c, 0x69, 0x2b, 0xf1, 0x18, 0x86, 0x71, 0x9d, 0xae, 0xc5, 0xea,
-	0x85, 0x6d, 0xc8, 0x91, 0xbb, 0xeb, 0x00, 0xc6, 0xd0, 0xb3, 0xf5, 0xc4, 0x0b, 0xd0, 0xc4, 0xa7,
-	0xee, 0x1c, 0xfe, 0x20, 0x18, 0xc5, 0x4a, 0x56, 0x52, 0x27, 0xeb, 0x48, 0x73, 0x1c, 0x82, 0x5f,
-	0x6d, 0xe5, 0x52, 0xb0, 0x2f, 0x67, 0xe0, 0xd1, 0x03, 0x86, 0x2f, 0x01, 0x5a, 0x3d, 0xcf, 0x9c,
-	0x8d, 0x4f, 0xf7, 0x88, 0x9d, 0xc2, 0x88, 0x82, 0x69, 0x93, 0x14, 0x95, 0x33, 0xf3, 0x68, 0x07,
-	0xec, 0xed, 0x73, 0xad, 0x8d, 0xc8, 0x37, 0x8b, 0x19, 0xe9, 0xb9, 0xe2, 0x0e, 0xe0, 0xeb, 0xed,
-	0x8c, 0xc7, 0x01, 0x9a, 0x8c, 0x6e, 0xc6, 0xd3, 0xfd, 0x55, 0x4c, 0x0f, 0xf7, 0xd0, 0x24, 0xb0,
-	0xfd, 0x22, 0xcd, 0x1f, 0x05, 0x67, 0xda, 0x90, 0x7e, 0xd3, 0x6f, 0x07, 0xc2, 0x6f, 0x04, 0x83,
-	0xa5, 0x34, 0xcc, 0x66, 0xbb, 0x80, 0x13, 0x7b, 0x9c, 0x97, 0xb9, 0x74, 0xb9, 0x7c, 0xba, 0xd3,
-	0xf8, 0x0a, 0xce, 0x5e, 0x59, 0xc6, 0x99, 0x9a, 0xc9, 0xa2, 0x10, 0xc6, 0xbd, 0x69, 0x92, 0xfd,
-	0xe1, 0xf8, 0x0e, 0x86, 0xd6, 0x39, 0x31, 0xb5, 0x62, 0xc4, 0x0b, 0xbc, 0x7f, 0x07, 0xed, 0x9e,
-	0xa7, 0x7d, 0xf7, 0xc5, 0xb7, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd7, 0xbd, 0x59, 0xaa, 0xf9,
-	0x01, 0x00, 0x00,
+	// 384 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0xc1, 0x6e, 0xe2, 0x30,
+	0x10, 0x55, 0x48, 0x16, 0x96, 0x21, 0x5a, 0xad, 0xbc, 0xd2, 0xae, 0xb5, 0x42, 0x55, 0x94, 0x13,
+	0xea, 0x01, 0x55, 0xed, 0xad, 0x37, 0x48, 0x5b, 0x95, 0xb6, 0x48, 0x90, 0x22, 0x4e, 0xbd, 0x04,
+	0x62, 0x8c, 0x25, 0x82, 0x23, 0xdb, 0x51, 0xc5, 0x1f, 0xf4, 0xd2, 0x6f, 0x6e, 0x65, 0x63, 0x92,
+	0xd0, 0x1e, 0x2a, 0xf5, 0x36, 0xef, 0x8d, 0xe7, 0xf9, 0xcd, 0xb3, 0xe1, 0xcf, 0x72, 0x9d, 0xb0,
+	0x2d, 0x49, 0x87, 0x37, 0xb3, 0xb1, 0xa4, 0xfd, 0x5c, 0x70, 0xc5, 0x91, 0x7f, 0x20, 0x57, 0x6a,
+	0xb2, 0x08, 0x9f, 0xe0, 0xd7, 0xb4, 0xe0, 0xa2, 0xc8, 0x22, 0x22, 0xd4, 0x23, 0xa3, 0x5b, 0x84,
+	0xa1, 0x35, 0x48, 0x53, 0x41, 0xa4, 0xc4, 0x4e, 0xe0, 0xf4, 0xda, 0xf1, 0x01, 0xa2, 0x2e, 0xb4,
+	0x27, 0xc5, 0x62, 0xc3, 0x96, 0xf7, 0x64, 0x87, 0x1b, 0xa6, 0x57, 0x11, 0x08, 0x81, 0xa7, 0xe7,
+	0xb1, 0x1b, 0x38, 0x3d, 0x3f, 0x36, 0x75, 0x78, 0x0d, 0xff, 0x2a, 0xf5, 0x01, 0xa5, 0x82, 0xd0,
+	0x44, 0x11, 0x73, 0xcd, 0x5f, 0x68, 0x0e, 0x99, 0xca, 0x92, 0xdc, 0xdc, 0xe2, 0xc7, 0x16, 0x95,
+	0x32, 0x8d, 0x9a, 0xcc, 0x9b, 0x03, 0x9d, 0x89, 0xe0, 0x39, 0x97, 0xc9, 0x66, 0x2c, 0x29, 0x0a,
+	0xc1, 0xcf, 0x2d, 0x9c, 0x33, 0xf2, 0x6c, 0x14, 0xdc, 0xf8, 0x88, 0x43, 0x27, 0x00, 0x07, 0x3c,
+	0x4a, 0xad, 0x5a, 0x8d, 0xd1, 0xcb, 0x28, 0x96, 0x11, 0xa9, 0x92, 0x2c, 0x37, 0x9e, 0xdd, 0xb8,
+	0x22, 0x74, 0xf7, 0xae, 0x90, 0x8a, 0xad, 0x76, 0xd3, 0x08, 0x7b, 0x66, 0xb8, 0x22, 0xd0, 0x99,
+	0xf5, 0xf8, 0x23, 0x70, 0x7a, 0x9d, 0xf3, 0x6e, 0xbf, 0x9e, 0x68, 0xff, 0x38, 0xce, 0xfd, 0x06,
+	0x5a, 0x6f, 0x2c, 0xe9, 0x15, 0xa3, 0x44, 0x2a, 0xdc, 0xdc, 0xeb, 0x95, 0x04, 0x0a, 0xa0, 0x33,
+	0x63, 0x19, 0xe1, 0x85, 0xd2, 0x63, 0xb8, 0x65, 0xfa, 0x75, 0x2a, 0x7c, 0x75, 0xa0, 0x35, 0xe7,
+	0x8a, 0xe8, 0xed, 0xff, 0xc3, 0x4f, 0x5d, 0x8e, 0xb6, 0x2b, 0x6e, 0xb3, 0x2b, 0x31, 0x3a, 0x85,
+	0xdf, 0x0f, 0x24, 0xa5, 0x44, 0x44, 0x3c, 0xcb, 0x98, 0x32, 0x67, 0xf6, 0xbb, 0x7f, 0xe2, 0xd1,
+	0x25, 0xb4, 0xb5, 0xb7, 0x44, 0x15, 0x82, 0x60, 0x37, 0x70, 0xbf, 0x5c, 0xa5, 0x3a, 0x1e, 0xbe,
+	0x38, 0x00, 0xd6, 0x9f, 0xb6, 0x84, 0xc0, 0xab, 0x3d, 0x84, 0xa9, 0xf5, 0x03, 0xdf, 0x32, 0xba,
+	0x9e, 0x46, 0xd6, 0x80, 0x45, 0x65, 0x78, 0xee, 0xf7, 0xc2, 0xf3, 0x3e, 0x84, 0xb7, 0x68, 0x9a,
+	0x6f, 0x7d, 0xf1, 0x1e, 0x00, 0x00, 0xff, 0xff, 0xca, 0x34, 0xe8, 0xf1, 0xed, 0x02, 0x00, 0x00,
 }
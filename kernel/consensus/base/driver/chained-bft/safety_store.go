@@ -0,0 +1,95 @@
+package chained_bft
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SafetyStateFileName 为saftyrules投票状态在链数据目录下的默认文件名
+const SafetyStateFileName = "chained_bft_safety.json"
+
+// SafetyState 为saftyrules中与投票安全相关的状态，节点重启后需恢复，否则可能在同一view重复投票
+type SafetyState struct {
+	LastVoteRound int64 `json:"lastVoteRound"`
+	// LastVoteId 为最近一次投票的proposalId，同一view只能为该proposal投票
+	LastVoteId     []byte `json:"lastVoteId"`
+	PreferredRound int64  `json:"preferredRound"`
+}
+
+// SafetyStore 持久化SafetyState，Save返回成功后状态必须已经落盘
+type SafetyStore interface {
+	Load() (*SafetyState, error)
+	Save(state *SafetyState) error
+}
+
+// FileSafetyStore 使用本地单个文件存储SafetyState，写入时先写临时文件再rename，保证文件内容完整
+type FileSafetyStore struct {
+	path string
+	mtx  sync.Mutex
+}
+
+// NewSafetyStore 在共识数据目录下创建SafetyStore，目录为空时返回nil，即不持久化
+func NewSafetyStore(dataDir string) SafetyStore {
+	if dataDir == "" {
+		return nil
+	}
+	return NewFileSafetyStore(filepath.Join(dataDir, SafetyStateFileName))
+}
+
+func NewFileSafetyStore(path string) *FileSafetyStore {
+	return &FileSafetyStore{
+		path: path,
+	}
+}
+
+// Load 读取本地状态，文件不存在时返回零值状态
+func (f *FileSafetyStore) Load() (*SafetyState, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	state := &SafetyState{}
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (f *FileSafetyStore) Save(state *SafetyState) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
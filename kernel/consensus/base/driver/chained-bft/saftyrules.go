@@ -1,9 +1,11 @@
 package chained_bft
 
 import (
+	"bytes"
 	"errors"
 
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
 	"github.com/xuperchain/xupercore/lib/logs"
 )
@@ -24,6 +26,9 @@ var (
 	NoEnoughVotes      = errors.New("Parent qc doesn't have enough votes.")
 	EmptyParentNode    = errors.New("Parent's node is empty.")
 	EmptyValidators    = errors.New("Justify validators are empty.")
+	TooLowTimeoutView  = errors.New("Timeout received is lower than local lastVoteRound.")
+	NoEnoughTimeouts   = errors.New("Timeout cert doesn't have enough signs.")
+	EmptyTimeoutCert   = errors.New("Proposal skips views without a timeout cert.")
 )
 
 type saftyRulesInterface interface {
@@ -33,42 +38,94 @@ type saftyRulesInterface interface {
 	CalVotesThreshold(input, sum int) bool
 	CheckProposal(proposal, parent storage.QuorumCertInterface, justifyValidators []string) error
 	CheckPacemaker(pending, local int64) bool
+	CheckQuorumCert(qc storage.QuorumCertInterface, validators []string) error
+	CheckTimeout(msg *chainedBftPb.TimeoutMsg, validators []string) error
+	CheckTimeoutCert(tc *storage.TimeoutCert, validators []string) error
+	CheckSkipView(proposalView, parentView int64, tc *storage.TimeoutCert) error
+	LoadSafetyState() error
 }
 
 type DefaultSaftyRules struct {
 	// lastVoteRound 存储着本地最近一次投票的轮数
 	lastVoteRound int64
+	// lastVoteId 存储着本地最近一次投票的proposalId
+	lastVoteId []byte
 	// preferredRound 存储着本地PendingTree
 	// 即有[两个子孙节点的节点]
 	// 若本地有相同高度的节点，则自然排序后选出preferredRound
 	preferredRound int64
 	Crypto         *cCrypto.CBFTCrypto
 	QcTree         *storage.QCPendingTree
+	// Store 持久化lastVoteRound和preferredRound，为空时仅保存在内存中
+	Store SafetyStore
 
 	Log logs.Logger
 }
 
 func (s *DefaultSaftyRules) UpdatePreferredRound(round int64) bool {
 	if round-1 > s.preferredRound {
+		if err := s.saveSafetyState(s.lastVoteRound, s.lastVoteId, round-1); err != nil {
+			s.Log.Error("DefaultSaftyRules::UpdatePreferredRound save safety state error", "error", err)
+			return false
+		}
 		s.preferredRound = round - 1
 	}
 	// TODO: 检查LedgerInfo是否一致
 	return true
 }
 
+// LoadSafetyState 从Store恢复本地投票状态，需在处理任何消息之前调用
+func (s *DefaultSaftyRules) LoadSafetyState() error {
+	if s.Store == nil {
+		return nil
+	}
+	state, err := s.Store.Load()
+	if err != nil {
+		return err
+	}
+	if state.LastVoteRound > s.lastVoteRound {
+		s.lastVoteRound = state.LastVoteRound
+		s.lastVoteId = state.LastVoteId
+	}
+	if state.PreferredRound > s.preferredRound {
+		s.preferredRound = state.PreferredRound
+	}
+	return nil
+}
+
+// saveSafetyState 在内存状态变更前写入Store，写入失败时调用方不得变更状态
+func (s *DefaultSaftyRules) saveSafetyState(lastVoteRound int64, lastVoteId []byte, preferredRound int64) error {
+	if s.Store == nil {
+		return nil
+	}
+	return s.Store.Save(&SafetyState{
+		LastVoteRound:  lastVoteRound,
+		LastVoteId:     lastVoteId,
+		PreferredRound: preferredRound,
+	})
+}
+
 // VoteProposal 返回是否需要发送voteMsg给下一个Leader
 // DefaultSaftyRules 并没有严格比对proposalRound和parentRound的相邻自增关系
 // 但需要注意的是，在上层bcs的实现中，由于共识操纵了账本回滚。因此实际上safetyrules需要proposalRound和parentRound严格相邻的
-// 因此由于账本的可回滚性，因此lastVoteRound和preferredRound比对时，仅需比对新来的数据是否小于local数据-3即可
+// 因此由于账本的可回滚性，因此preferredRound比对时，仅需比对新来的数据是否小于local数据-3即可
 // 此处-3代表数据已经落盘
+// 但投票本身不能回退：不为低于lastVoteRound的proposal投票，lastVoteRound上只为已投票的同一proposal投票
 func (s *DefaultSaftyRules) VoteProposal(proposalId []byte, proposalRound int64, parentQc storage.QuorumCertInterface) bool {
-	if proposalRound < s.lastVoteRound-StrictInternal {
+	if proposalRound < s.lastVoteRound {
+		return false
+	}
+	if proposalRound == s.lastVoteRound && s.lastVoteId != nil && !bytes.Equal(proposalId, s.lastVoteId) {
 		return false
 	}
 	if parentQc.GetProposalView() < s.preferredRound-StrictInternal {
 		return false
 	}
-	s.increaseLastVoteRound(proposalRound)
+	// 投票状态需在vote发出之前落盘，避免重启后在同一view重复投票
+	if err := s.increaseLastVoteRound(proposalRound, proposalId); err != nil {
+		s.Log.Error("DefaultSaftyRules::VoteProposal save safety state error", "error", err)
+		return false
+	}
 	return true
 }
 
@@ -84,8 +141,8 @@ func (s *DefaultSaftyRules) CheckVote(qc storage.QuorumCertInterface, logid stri
 		s.Log.Error("DefaultSaftyRules::CheckVote error", "validators", validators, "from", signs[0].GetAddress())
 		return InvalidVoteAddr
 	}
-	// 签名和公钥是否匹配
-	if ok, err := s.Crypto.VerifyVoteMsgSign(signs[0], qc.GetProposalId()); !ok {
+	// 签名和公钥是否匹配，BLS模式下公钥为链上注册的BLS公钥
+	if ok, err := s.verifyVoteSign(signs[0], qc.GetProposalId()); !ok {
 		return err
 	}
 	// 检查voteinfo信息, proposalView小于lastVoteRound，parentView不小于preferredRound
@@ -99,10 +156,15 @@ func (s *DefaultSaftyRules) CheckVote(qc storage.QuorumCertInterface, logid stri
 	return nil
 }
 
-func (s *DefaultSaftyRules) increaseLastVoteRound(round int64) {
-	if round > s.lastVoteRound {
+func (s *DefaultSaftyRules) increaseLastVoteRound(round int64, proposalId []byte) error {
+	if round > s.lastVoteRound || s.lastVoteId == nil {
+		if err := s.saveSafetyState(round, proposalId, s.preferredRound); err != nil {
+			return err
+		}
 		s.lastVoteRound = round
+		s.lastVoteId = proposalId
 	}
+	return nil
 }
 
 func (s *DefaultSaftyRules) CalVotesThreshold(input, sum int) bool {
@@ -144,25 +206,115 @@ func (s *DefaultSaftyRules) CheckProposal(proposal, parent storage.QuorumCertInt
 	}
 
 	// 检查justify的所有vote签名
-	justifySigns := parent.GetSignsInfo()
 	s.Log.Debug("DefaultSaftyRules::CheckProposal", "parent", parent, "justifyValidators", justifyValidators)
-	validCnt := 0
-	for _, v := range justifySigns {
-		if !isInSlice(v.GetAddress(), justifyValidators) {
-			continue
-		}
-		// 签名和公钥是否匹配
-		if ok, _ := s.Crypto.VerifyVoteMsgSign(v, parent.GetProposalId()); !ok {
+	return s.CheckQuorumCert(parent, justifyValidators)
+}
+
+// CheckQuorumCert 检查qc中来自validators的签名是否均合法，且数量满足2f+1
+// qc携带聚合签名时验证聚合签名，否则按老格式逐个验证签名
+func (s *DefaultSaftyRules) CheckQuorumCert(qc storage.QuorumCertInterface, validators []string) error {
+	var validCnt int
+	var err error
+	if agg := qc.GetAggregateSign(); agg != nil {
+		validCnt, err = s.Crypto.VerifyAggregateSign(agg, qc.GetProposalId(), validators)
+		if err != nil {
+			s.Log.Debug("DefaultSaftyRules::CheckQuorumCert VerifyAggregateSign error", "error", err)
 			return InvalidVoteSign
 		}
-		validCnt++
+	} else {
+		validCnt, err = s.countValidSigns(qc.GetSignsInfo(), qc.GetProposalId(), validators)
+		if err != nil {
+			return err
+		}
 	}
-	if !s.CalVotesThreshold(validCnt, len(justifyValidators)) {
+	if !s.CalVotesThreshold(validCnt, len(validators)) {
 		return NoEnoughVotes
 	}
 	return nil
 }
 
+// CheckTimeout 检查TimeoutMsg是否来自合法的validator，以及超时的view是否过低
+func (s *DefaultSaftyRules) CheckTimeout(msg *chainedBftPb.TimeoutMsg, validators []string) error {
+	sign := msg.GetSign()
+	if sign == nil {
+		return EmptyVoteSignErr
+	}
+	if !isInSlice(sign.GetAddress(), validators) {
+		s.Log.Error("DefaultSaftyRules::CheckTimeout error", "validators", validators, "from", sign.GetAddress())
+		return InvalidVoteAddr
+	}
+	digest, err := s.Crypto.MakeTimeoutMsgDigest(msg.GetView())
+	if err != nil {
+		return err
+	}
+	if ok, _ := s.Crypto.VerifyVoteMsgSign(sign, digest); !ok {
+		return InvalidVoteSign
+	}
+	if msg.GetView() < s.lastVoteRound-StrictInternal {
+		return TooLowTimeoutView
+	}
+	return nil
+}
+
+// CheckTimeoutCert 检查TC中的超时签名是否均合法，且数量满足2f+1
+func (s *DefaultSaftyRules) CheckTimeoutCert(tc *storage.TimeoutCert, validators []string) error {
+	if tc == nil || len(tc.GetSignsInfo()) == 0 {
+		return EmptyTimeoutCert
+	}
+	digest, err := s.Crypto.MakeTimeoutMsgDigest(tc.GetView())
+	if err != nil {
+		return err
+	}
+	validCnt, err := s.countValidSigns(tc.GetSignsInfo(), digest, validators)
+	if err != nil {
+		return err
+	}
+	// CalVotesThreshold默认为本地额外计入一票，而TC需要自身完整包含2f+1个签名，故此处减一
+	if !s.CalVotesThreshold(validCnt-1, len(validators)) {
+		return NoEnoughTimeouts
+	}
+	return nil
+}
+
+// CheckSkipView proposal和parent的view不相邻时，需要一个覆盖proposal前一view的TC作为跳过中间view的依据
+// 注意：TC本身的签名需要事先通过CheckTimeoutCert检查
+func (s *DefaultSaftyRules) CheckSkipView(proposalView, parentView int64, tc *storage.TimeoutCert) error {
+	if proposalView <= parentView+1 {
+		return nil
+	}
+	if tc == nil {
+		return EmptyTimeoutCert
+	}
+	if tc.GetView() < proposalView-1 {
+		return TooLowTimeoutView
+	}
+	return nil
+}
+
+// countValidSigns 统计signs中来自validators且签名合法的数量，同一地址仅计一次
+func (s *DefaultSaftyRules) countValidSigns(signs []*chainedBftPb.QuorumCertSign, msg []byte, validators []string) (int, error) {
+	counted := make(map[string]bool)
+	for _, v := range signs {
+		if !isInSlice(v.GetAddress(), validators) || counted[v.GetAddress()] {
+			continue
+		}
+		// 签名和公钥是否匹配
+		if ok, _ := s.Crypto.VerifyVoteMsgSign(v, msg); !ok {
+			return 0, InvalidVoteSign
+		}
+		counted[v.GetAddress()] = true
+	}
+	return len(counted), nil
+}
+
+// verifyVoteSign 验证单个vote签名，BLS模式下使用BLS签名
+func (s *DefaultSaftyRules) verifyVoteSign(sign *chainedBftPb.QuorumCertSign, msg []byte) (bool, error) {
+	if s.Crypto.IsBlsEnabled() {
+		return s.Crypto.VerifyBlsVoteSign(sign, msg)
+	}
+	return s.Crypto.VerifyVoteMsgSign(sign, msg)
+}
+
 // CheckPacemaker
 // 注意： 由于本smr支持不同节点产生同一round， 因此下述round比较和leader比较与原文(验证Proposal的Round是否和pacemaker的Round相等)并不同。
 // 仅需proposal round不超过范围即可
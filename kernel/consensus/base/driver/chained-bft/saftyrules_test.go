@@ -1,11 +1,18 @@
 package chained_bft
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/mock"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	"github.com/xuperchain/xupercore/lib/crypto/bls"
 )
 
 func TestCalVotesThreshold(t *testing.T) {
@@ -76,3 +83,255 @@ func TestCheckProposal(t *testing.T) {
 	s.VoteProposal([]byte{2}, 2, generic)
 	s.CheckVote(generic, "123", []string{"gNhga8vLc4JcmoHB2yeef2adBhntkc5d1"})
 }
+
+func TestCheckTimeout(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	validators := []string{NodeA, NodeB, NodeC}
+	var signs []*chainedBftPb.QuorumCertSign
+	var s *DefaultSaftyRules
+	for _, node := range []string{"nodeA", "nodeB", "nodeC"} {
+		a, cc := NewFakeCryptoClient(node, t)
+		c := cCrypto.NewCBFTCrypto(&a, cc)
+		c.BcName = "xuper"
+		if s == nil {
+			s = &DefaultSaftyRules{
+				Crypto: c,
+				QcTree: mock.MockInitQcTree(),
+				Log:    th.Log,
+			}
+		}
+		msg, err := c.SignTimeoutMsg(&chainedBftPb.TimeoutMsg{View: 5})
+		if err != nil {
+			t.Error("SignTimeoutMsg error", "err", err)
+			return
+		}
+		if err := s.CheckTimeout(msg, validators); err != nil {
+			t.Error("CheckTimeout error", "err", err)
+			return
+		}
+		signs = append(signs, msg.GetSign())
+	}
+	// 篡改view后签名失效
+	forged := &chainedBftPb.TimeoutMsg{View: 6, Sign: signs[0]}
+	if err := s.CheckTimeout(forged, validators); err != InvalidVoteSign {
+		t.Error("CheckTimeout forged msg error", "err", err)
+		return
+	}
+	if err := s.CheckTimeout(&chainedBftPb.TimeoutMsg{View: 5, Sign: signs[0]}, []string{NodeB}); err != InvalidVoteAddr {
+		t.Error("CheckTimeout invalid addr error", "err", err)
+		return
+	}
+
+	// 3个节点时TC需要全部3个签名，重复签名不重复计数
+	if err := s.CheckTimeoutCert(storage.NewTimeoutCert(5, signs[:2]), validators); err != NoEnoughTimeouts {
+		t.Error("CheckTimeoutCert not enough error", "err", err)
+		return
+	}
+	if err := s.CheckTimeoutCert(storage.NewTimeoutCert(5, append(signs[:2:2], signs[0])), validators); err != NoEnoughTimeouts {
+		t.Error("CheckTimeoutCert duplicate error", "err", err)
+		return
+	}
+	tc := storage.NewTimeoutCert(5, signs)
+	if err := s.CheckTimeoutCert(tc, validators); err != nil {
+		t.Error("CheckTimeoutCert error", "err", err)
+		return
+	}
+	if err := s.CheckTimeoutCert(storage.NewTimeoutCert(6, signs), validators); err != InvalidVoteSign {
+		t.Error("CheckTimeoutCert wrong view error", "err", err)
+		return
+	}
+	// 其他链上相同validators的超时签名不能被重放
+	a, cc := NewFakeCryptoClient("nodeA", t)
+	other := cCrypto.NewCBFTCrypto(&a, cc)
+	other.BcName = "other"
+	otherRules := &DefaultSaftyRules{Crypto: other, QcTree: mock.MockInitQcTree(), Log: th.Log}
+	if err := otherRules.CheckTimeout(&chainedBftPb.TimeoutMsg{View: 5, Sign: signs[0]}, validators); err != InvalidVoteSign {
+		t.Error("CheckTimeout replayed msg error", "err", err)
+		return
+	}
+	if err := otherRules.CheckTimeoutCert(tc, validators); err != InvalidVoteSign {
+		t.Error("CheckTimeoutCert replayed tc error", "err", err)
+		return
+	}
+
+	// 相邻view无需TC，跳过view时TC需覆盖proposal的前一个view
+	if err := s.CheckSkipView(5, 4, nil); err != nil {
+		t.Error("CheckSkipView adjacent error", "err", err)
+	}
+	if err := s.CheckSkipView(6, 4, nil); err != EmptyTimeoutCert {
+		t.Error("CheckSkipView without tc error", "err", err)
+	}
+	if err := s.CheckSkipView(6, 4, tc); err != nil {
+		t.Error("CheckSkipView with tc error", "err", err)
+	}
+	if err := s.CheckSkipView(7, 4, tc); err != TooLowTimeoutView {
+		t.Error("CheckSkipView low tc error", "err", err)
+	}
+}
+
+// fakeBlsKeys 为测试用的链上BLS公钥注册信息
+type fakeBlsKeys map[string]*bls.PublicKey
+
+func (f fakeBlsKeys) GetBlsPublicKey(address string) (*bls.PublicKey, error) {
+	return f[address], nil
+}
+
+// newBlsCryptos 为nodeA、nodeB、nodeC创建开启BLS模式的CBFTCrypto
+func newBlsCryptos(t *testing.T) []*cCrypto.CBFTCrypto {
+	keys := fakeBlsKeys{}
+	var cryptos []*cCrypto.CBFTCrypto
+	for _, node := range []string{"nodeA", "nodeB", "nodeC"} {
+		a, cc := NewFakeCryptoClient(node, t)
+		sk, err := bls.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[a.Address] = sk.PublicKey()
+		c := cCrypto.NewCBFTCrypto(&a, cc)
+		c.EnableBls(sk, keys)
+		cryptos = append(cryptos, c)
+	}
+	return cryptos
+}
+
+func TestCheckQuorumCertBls(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	validators := []string{NodeA, NodeB, NodeC}
+	cryptos := newBlsCryptos(t)
+	s := &DefaultSaftyRules{
+		Crypto: cryptos[0],
+		QcTree: mock.MockInitQcTree(),
+		Log:    th.Log,
+	}
+	proposalId := []byte{1}
+	vote := &storage.VoteInfo{
+		ProposalId:   proposalId,
+		ProposalView: 1,
+	}
+	var signs []*chainedBftPb.QuorumCertSign
+	for _, c := range cryptos[1:] {
+		sign, err := c.SignVoteMsg(proposalId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.CheckVote(storage.NewQuorumCert(vote, nil, []*chainedBftPb.QuorumCertSign{sign}), "", validators); err != nil {
+			t.Fatal("CheckVote bls sign error", err)
+		}
+		signs = append(signs, sign)
+	}
+
+	// 重复签名及非validator的签名不计入聚合签名
+	agg, err := s.Crypto.AggregateVoteSigns(append(signs, signs[0]), validators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(agg.GetBitmap(), []byte{6}) {
+		t.Fatalf("unexpected bitmap %v", agg.GetBitmap())
+	}
+	if err := s.CheckQuorumCert(storage.NewAggregateQuorumCert(vote, nil, agg), validators); err != nil {
+		t.Fatal("CheckQuorumCert aggregate sign error", err)
+	}
+	// 签名数量不足
+	part, _ := s.Crypto.AggregateVoteSigns(signs[:1], validators)
+	if err := s.CheckQuorumCert(storage.NewAggregateQuorumCert(vote, nil, part), validators); err != NoEnoughVotes {
+		t.Fatal("CheckQuorumCert not enough votes error", err)
+	}
+	// 位图与签名不符
+	forged := &chainedBftPb.QuorumCertAggregateSign{Bitmap: []byte{7}, Sign: agg.GetSign()}
+	if err := s.CheckQuorumCert(storage.NewAggregateQuorumCert(vote, nil, forged), validators); err != InvalidVoteSign {
+		t.Fatal("CheckQuorumCert forged bitmap error", err)
+	}
+	// 超出validators范围的位
+	forged = &chainedBftPb.QuorumCertAggregateSign{Bitmap: []byte{0x0e}, Sign: agg.GetSign()}
+	if err := s.CheckQuorumCert(storage.NewAggregateQuorumCert(vote, nil, forged), validators); err != InvalidVoteSign {
+		t.Fatal("CheckQuorumCert overflow bitmap error", err)
+	}
+	other := &storage.VoteInfo{
+		ProposalId:   []byte{2},
+		ProposalView: 1,
+	}
+	if err := s.CheckQuorumCert(storage.NewAggregateQuorumCert(other, nil, agg), validators); err != InvalidVoteSign {
+		t.Fatal("CheckQuorumCert wrong proposal error", err)
+	}
+
+	// 开启BLS后仍可验证老格式的QC
+	var legacy []*chainedBftPb.QuorumCertSign
+	for _, node := range []string{"nodeB", "nodeC"} {
+		a, cc := NewFakeCryptoClient(node, t)
+		sign, err := cCrypto.NewCBFTCrypto(&a, cc).SignVoteMsg(proposalId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		legacy = append(legacy, sign)
+	}
+	if err := s.CheckQuorumCert(storage.NewQuorumCert(vote, nil, legacy), validators); err != nil {
+		t.Fatal("CheckQuorumCert legacy signs error", err)
+	}
+}
+
+type failSafetyStore struct{}
+
+func (f failSafetyStore) Load() (*SafetyState, error) {
+	return &SafetyState{}, nil
+}
+
+func (f failSafetyStore) Save(state *SafetyState) error {
+	return errors.New("disk error")
+}
+
+func TestVoteProposalSafetyStore(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	parent := mock.MockCreateQC([]byte{1}, 1, []byte{0}, 0)
+
+	// 落盘失败时不得投票，且内存状态不变
+	s := &DefaultSaftyRules{
+		QcTree: mock.MockInitQcTree(),
+		Store:  failSafetyStore{},
+		Log:    th.Log,
+	}
+	if s.VoteProposal([]byte{2}, 2, parent) {
+		t.Fatal("VoteProposal should fail when safety state can't be saved")
+	}
+	if s.lastVoteRound != 0 {
+		t.Fatal("lastVoteRound changed without being saved", "round", s.lastVoteRound)
+	}
+
+	dir, err := ioutil.TempDir("", "safety_store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileSafetyStore(filepath.Join(dir, SafetyStateFileName))
+	s = &DefaultSaftyRules{
+		QcTree: mock.MockInitQcTree(),
+		Store:  store,
+		Log:    th.Log,
+	}
+	if !s.VoteProposal([]byte{2}, 2, parent) || !s.UpdatePreferredRound(2) {
+		t.Fatal("VoteProposal error")
+	}
+	restarted := &DefaultSaftyRules{
+		QcTree: mock.MockInitQcTree(),
+		Store:  store,
+		Log:    th.Log,
+	}
+	if err := restarted.LoadSafetyState(); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.lastVoteRound != 2 || restarted.preferredRound != 1 {
+		t.Fatal("LoadSafetyState error", "lastVoteRound", restarted.lastVoteRound, "preferredRound", restarted.preferredRound)
+	}
+	// 重启后同一view只能为重启前投票的proposal投票
+	if restarted.VoteProposal([]byte{3}, 2, parent) {
+		t.Fatal("VoteProposal should reject conflicting proposal at the same view after restart")
+	}
+	if restarted.VoteProposal([]byte{1}, 1, parent) {
+		t.Fatal("VoteProposal should reject proposal at lower view after restart")
+	}
+	if !restarted.VoteProposal([]byte{2}, 2, parent) || !restarted.VoteProposal([]byte{3}, 3, parent) {
+		t.Fatal("VoteProposal error")
+	}
+}
@@ -72,6 +72,15 @@ type Smr struct {
 	localProposal *sync.Map
 	// votes of QC in mem, key: voteId, value: []*QuorumCertSign
 	qcVoteMsgs *sync.Map
+	// aggregate signs of QC in mem, key: voteId, value: *QuorumCertAggregateSign
+	// BLS模式下从justify中获得的聚合签名无法拆分，单独存储
+	qcAggSigns *sync.Map
+	// signs of TimeoutMsg in mem, key: view, value: []*QuorumCertSign
+	timeoutMsgs *sync.Map
+	// highTC 为本地已知view最高的超时证书
+	highTC *storage.TimeoutCert
+	// lastTimeoutView 为本地最近一次广播TimeoutMsg的view
+	lastTimeoutView int64
 
 	// 该锁保护状态机处理msg或者bcs层操作过程，防止状态机get/set时由于bcs操作和msg处理并发导致的脏读脏写
 	mtx sync.Mutex
@@ -94,6 +103,13 @@ func NewSmr(bcName, address string, log logs.Logger, p2p cctx.P2pCtxInConsensus,
 		qcTree:        qcTree,
 		localProposal: &sync.Map{},
 		qcVoteMsgs:    &sync.Map{},
+		qcAggSigns:    &sync.Map{},
+		timeoutMsgs:   &sync.Map{},
+	}
+	// 重启时恢复本地投票状态，失败时不能启动，否则可能重复投票
+	if err := saftyrules.LoadSafetyState(); err != nil {
+		log.Error("smr::NewSmr::LoadSafetyState error", "error", err)
+		return nil
 	}
 	// smr初始值装载
 	s.localProposal.Store(utils.F(qcTree.GetRootQC().In.GetProposalId()), 0)
@@ -113,6 +129,13 @@ func (s *Smr) LoadVotes(proposalId []byte, signs []*chainedBftPb.QuorumCertSign)
 	}
 }
 
+// LoadAggregateSign 重启时装载justify中的聚合签名
+func (s *Smr) LoadAggregateSign(proposalId []byte, agg *chainedBftPb.QuorumCertAggregateSign) {
+	if agg != nil {
+		s.storeAggregateSign(proposalId, agg)
+	}
+}
+
 // RegisterToNetwork register msg handler to p2p network
 func (s *Smr) RegisterToNetwork() error {
 	sub1 := s.p2p.NewSubscriber(xuperp2p.XuperMessage_CHAINED_BFT_NEW_VIEW_MSG, s.p2pMsgChan)
@@ -148,13 +171,28 @@ func (s *Smr) UnRegisterToNetwork() {
 }
 
 // Start used to start smr instance and process msg
+// 若pacemaker开启了超时机制，smr同时维护一个view定时器，定时器触发时view仍未推进则进入超时流程
 func (s *Smr) Start() {
 	s.RegisterToNetwork()
 	go func() {
+		var timer *time.Timer
+		var timeoutCh <-chan time.Time
+		if timeout := s.getTimeout(); timeout > 0 {
+			timer = time.NewTimer(timeout)
+			defer timer.Stop()
+			timeoutCh = timer.C
+		}
+		view := s.GetCurrentView()
 		for {
 			select {
 			case msg := <-s.p2pMsgChan:
 				s.handleReceivedMsg(msg)
+			case <-timeoutCh:
+				if s.GetCurrentView() == view {
+					s.processLocalTimeout()
+				}
+				view = s.GetCurrentView()
+				timer.Reset(s.getTimeout())
 			case <-s.quitCh:
 				return
 			}
@@ -288,6 +326,8 @@ func (s *Smr) handleReceivedMsg(msg *xuperp2p.XuperMessage) error {
 		s.handleReceivedProposal(msg)
 	case xuperp2p.XuperMessage_CHAINED_BFT_VOTE_MSG:
 		s.handleReceivedVoteMsg(msg)
+	case xuperp2p.XuperMessage_CHAINED_BFT_NEW_VIEW_MSG:
+		s.handleReceivedTimeoutMsg(msg)
 	default:
 		s.log.Error("smr::handleReceivedMsg receive unknow type msg", "type", msg.GetHeader().GetType())
 		return nil
@@ -302,6 +342,12 @@ func (s *Smr) updateJustifyQcStatus(justify storage.QuorumCertInterface) {
 	if justify == nil {
 		return
 	}
+	// BLS模式的justify仅携带聚合签名
+	if agg := justify.GetAggregateSign(); agg != nil {
+		s.storeAggregateSign(justify.GetProposalId(), agg)
+		s.qcTree.UpdateHighQC(justify.GetProposalId())
+		return
+	}
 	v, ok := s.qcVoteMsgs.Load(utils.F(justify.GetProposalId()))
 	var signs []*chainedBftPb.QuorumCertSign
 	if ok {
@@ -368,6 +414,15 @@ func (s *Smr) ProcessProposal(viewNumber int64, proposalID []byte, parentID []by
 		Timestamp:    time.Now().UnixNano(),
 		JustifyQC:    parentQuorumCertBytes,
 	}
+	// 若本地持有比parentQC更新的TC，则随proposal一并发出，作为跳过中间view的依据
+	// TC自身携带了2f+1个签名，因此无需计入proposal摘要
+	if s.highTC != nil && s.highTC.GetView() > parentQuorumCert.GetProposalView() {
+		tcBytes, err := json.Marshal(s.highTC)
+		if err != nil {
+			return err
+		}
+		proposal.TimeoutCert = tcBytes
+	}
 	propMsg, err := s.cryptoClient.SignProposalMsg(proposal)
 	if err != nil {
 		s.log.Error("smr::ProcessProposal SignProposalMsg error", "error", err)
@@ -447,11 +502,15 @@ func (s *Smr) reloadJustifyQC(parentID []byte) (storage.QuorumCertInterface, err
 
 	// 根据qcTree生成一个parentQC
 	// 上一个view的votes
-	value, ok := s.qcVoteMsgs.Load(utils.F(v.ProposalId))
-	if !ok {
+	signs, agg := s.loadQCSigns(v.ProposalId, v.ProposalView)
+	if agg != nil {
+		return storage.NewAggregateQuorumCert(v, &storage.LedgerCommitInfo{
+			CommitStateId: commitId,
+		}, agg), nil
+	}
+	if signs == nil {
 		return nil, ErrJustifyVotesEmpty
 	}
-	signs, _ := value.([]*chainedBftPb.QuorumCertSign)
 	parentQuorumCert := storage.NewQuorumCert(v, &storage.LedgerCommitInfo{
 		CommitStateId: commitId,
 	}, signs)
@@ -507,6 +566,20 @@ func (s *Smr) handleReceivedProposal(msg *xuperp2p.XuperMessage) {
 				"parentView", parentQC.GetProposalView(), "parentId", utils.F(parentQC.GetProposalId()))
 			return
 		}
+		// proposal跳过了中间view时，需检查其携带的TC
+		tc, err := s.loadTimeoutCert(newProposalMsg.GetTimeoutCert())
+		if err != nil {
+			s.log.Debug("smr::handleReceivedProposal::loadTimeoutCert error", "error", err)
+			return
+		}
+		if err := s.saftyrules.CheckSkipView(newVote.ProposalView, newVote.ParentView, tc); err != nil {
+			s.log.Debug("smr::handleReceivedProposal::CheckSkipView error", "error", err,
+				"proposalView", newVote.ProposalView, "parentView", newVote.ParentView)
+			return
+		}
+		if tc != nil {
+			s.processTimeoutCert(tc)
+		}
 	}
 	// 1.检查账本状态和收到新round是否符合要求
 	if s.ledgerState+StrictInternal < newVote.ProposalView {
@@ -676,6 +749,154 @@ func (s *Smr) handleReceivedVoteMsg(msg *xuperp2p.XuperMessage) error {
 	return nil
 }
 
+// processLocalTimeout 本地view超时，向所有validators广播TimeoutMsg
+func (s *Smr) processLocalTimeout() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.log.Debug("smr::processLocalTimeout::local view timeout", "view", s.pacemaker.GetCurrentView(), "timeout", s.pacemaker.GetTimeout())
+	s.broadcastTimeout(s.pacemaker.GetCurrentView())
+}
+
+// broadcastTimeout 广播本地对指定view的TimeoutMsg，消息中携带本地HighQC，以便下一Leader同步到最新的QC
+func (s *Smr) broadcastTimeout(view int64) {
+	validators := s.election.GetValidators(view)
+	if validators == nil {
+		s.log.Warn("smr::broadcastTimeout::empty validators", "view", view)
+		return
+	}
+	highQCBytes, err := json.Marshal(s.getCompleteHighQC())
+	if err != nil {
+		s.log.Error("smr::broadcastTimeout::Marshal highQC error", "err", err)
+		return
+	}
+	timeoutMsg, err := s.cryptoClient.SignTimeoutMsg(&chainedBftPb.TimeoutMsg{
+		View:   view,
+		HighQC: highQCBytes,
+	})
+	if err != nil {
+		s.log.Error("smr::broadcastTimeout::SignTimeoutMsg error", "err", err)
+		return
+	}
+	s.lastTimeoutView = view
+	netMsg := p2p.NewMessage(xuperp2p.XuperMessage_CHAINED_BFT_NEW_VIEW_MSG, timeoutMsg, p2p.WithBCName(s.bcName))
+	if netMsg == nil {
+		s.log.Error("smr::broadcastTimeout::NewMessage error")
+		return
+	}
+	if others := s.removeLocalValidator(validators); len(others) > 0 {
+		go s.p2p.SendMessage(createNewBCtx(), netMsg, p2p.WithAccounts(others))
+	}
+	s.log.Debug("smr::broadcastTimeout::timeout", "view", view, "validators", validators)
+	// 本地的超时签名同样计入TC
+	s.collectTimeout(view, timeoutMsg.GetSign(), validators)
+}
+
+// handleReceivedTimeoutMsg 收到其他节点的TimeoutMsg，同步其HighQC并收集超时签名
+func (s *Smr) handleReceivedTimeoutMsg(msg *xuperp2p.XuperMessage) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	timeoutMsg := &chainedBftPb.TimeoutMsg{}
+	if err := p2p.Unmarshal(msg, timeoutMsg); err != nil {
+		s.log.Error("smr::handleReceivedTimeoutMsg Unmarshal msg error", "logid", msg.GetHeader().GetLogid(), "error", err)
+		return err
+	}
+	validators := s.election.GetValidators(timeoutMsg.GetView())
+	if err := s.saftyrules.CheckTimeout(timeoutMsg, validators); err != nil {
+		s.log.Error("smr::handleReceivedTimeoutMsg CheckTimeout error", "error", err, "view", timeoutMsg.GetView())
+		return err
+	}
+	s.log.Debug("smr::handleReceivedTimeoutMsg::receive timeout", "view", timeoutMsg.GetView(), "from", timeoutMsg.GetSign().GetAddress())
+	s.syncHighQC(timeoutMsg.GetHighQC())
+	s.collectTimeout(timeoutMsg.GetView(), timeoutMsg.GetSign(), validators)
+	return nil
+}
+
+// collectTimeout 收集同一view的超时签名
+// 达到f+1个时说明至少有一个诚实节点超时，本地随之广播超时以加速TC生成；达到2f+1个时生成TC并推进pacemaker
+func (s *Smr) collectTimeout(view int64, sign *chainedBftPb.QuorumCertSign, validators []string) {
+	if s.highTC != nil && s.highTC.GetView() >= view {
+		return
+	}
+	var signs []*chainedBftPb.QuorumCertSign
+	if v, ok := s.timeoutMsgs.Load(view); ok {
+		signs, _ = v.([]*chainedBftPb.QuorumCertSign)
+	}
+	signs = appendSigns(signs, []*chainedBftPb.QuorumCertSign{sign})
+	s.timeoutMsgs.Store(view, signs)
+	// 签名在收到时均已验证，且TC需要自身完整包含2f+1个签名
+	if !s.saftyrules.CalVotesThreshold(len(signs)-1, len(validators)) {
+		if len(signs) > (len(validators)-1)/3 && view > s.lastTimeoutView && view == s.pacemaker.GetCurrentView() {
+			s.broadcastTimeout(view)
+		}
+		return
+	}
+	s.processTimeoutCert(storage.NewTimeoutCert(view, signs))
+}
+
+// processTimeoutCert 更新本地highTC，并根据TC跳过超时的view
+func (s *Smr) processTimeoutCert(tc *storage.TimeoutCert) {
+	if s.highTC != nil && s.highTC.GetView() >= tc.GetView() {
+		return
+	}
+	s.highTC = tc
+	if ok, _ := s.pacemaker.AdvanceViewByTC(tc); ok {
+		s.log.Debug("smr::processTimeoutCert::view changed by TC", "tc view", tc.GetView(), "pacemaker view", s.pacemaker.GetCurrentView())
+	}
+	// 清理已过期的超时签名
+	s.timeoutMsgs.Range(func(k, v interface{}) bool {
+		if view, ok := k.(int64); ok && view <= tc.GetView() {
+			s.timeoutMsgs.Delete(k)
+		}
+		return true
+	})
+}
+
+// loadTimeoutCert 反序列化proposal中携带的TC并检查其签名，proposal未携带TC时返回nil
+func (s *Smr) loadTimeoutCert(raw []byte) (*storage.TimeoutCert, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	tc := &storage.TimeoutCert{}
+	if err := json.Unmarshal(raw, tc); err != nil {
+		return nil, err
+	}
+	if err := s.saftyrules.CheckTimeoutCert(tc, s.election.GetValidators(tc.GetView())); err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
+
+// syncHighQC 若TimeoutMsg携带的HighQC不低于本地HighQC且本地尚未持有其完整签名，则校验后更新本地HighQC及签名
+func (s *Smr) syncHighQC(raw []byte) {
+	highQC := &storage.QuorumCert{}
+	if err := json.Unmarshal(raw, highQC); err != nil || highQC.VoteInfo == nil {
+		return
+	}
+	if highQC.GetProposalView() < s.getHighQC().GetProposalView() {
+		return
+	}
+	if s.qcTree.DFSQueryNode(highQC.GetProposalId()) == nil {
+		return
+	}
+	validators := s.election.GetValidators(highQC.GetProposalView())
+	if s.validNewHighQC(highQC.GetProposalId(), validators) {
+		return
+	}
+	if err := s.saftyrules.CheckQuorumCert(highQC, validators); err != nil {
+		s.log.Debug("smr::syncHighQC::CheckQuorumCert error", "error", err, "highQC", utils.F(highQC.GetProposalId()))
+		return
+	}
+	s.updateJustifyQcStatus(highQC)
+}
+
+func (s *Smr) getTimeout() time.Duration {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.pacemaker.GetTimeout()
+}
+
 // voteMsgToQC 提供一个从VoteMsg转化为quorumCert的方法，注意，两者struct其实相仿
 func (s *Smr) voteMsgToQC(msg *chainedBftPb.VoteMsg) (storage.QuorumCertInterface, error) {
 	voteInfo := &storage.VoteInfo{}
@@ -716,27 +937,68 @@ func (s *Smr) getCompleteHighQC() storage.QuorumCertInterface {
 		ParentId:     raw.GetParentProposalId(),
 		ParentView:   raw.GetProposalView(),
 	}
-	signInfo, ok := s.qcVoteMsgs.Load(utils.F(raw.GetProposalId()))
-	if !ok {
-		return storage.NewQuorumCert(vote, nil, nil)
+	signs, agg := s.loadQCSigns(raw.GetProposalId(), raw.GetProposalView())
+	if agg != nil {
+		return storage.NewAggregateQuorumCert(vote, nil, agg)
 	}
-	signs, _ := signInfo.([]*chainedBftPb.QuorumCertSign)
 	return storage.NewQuorumCert(vote, nil, signs)
 }
 
+// loadQCSigns 获取本地收集的QC签名，BLS模式下返回聚合签名
+// 本地收集的签名多于已有的聚合签名时，使用view对应的validators重新聚合
+func (s *Smr) loadQCSigns(proposalId []byte, view int64) ([]*chainedBftPb.QuorumCertSign, *chainedBftPb.QuorumCertAggregateSign) {
+	var signs []*chainedBftPb.QuorumCertSign
+	if v, ok := s.qcVoteMsgs.Load(utils.F(proposalId)); ok {
+		signs, _ = v.([]*chainedBftPb.QuorumCertSign)
+	}
+	if !s.cryptoClient.IsBlsEnabled() {
+		return signs, nil
+	}
+	agg := s.loadAggregateSign(proposalId)
+	if len(signs) > cCrypto.CountAggregateSigners(agg) {
+		newAgg, err := s.cryptoClient.AggregateVoteSigns(signs, s.election.GetValidators(view))
+		if err == nil {
+			return nil, newAgg
+		}
+		s.log.Warn("smr::loadQCSigns::AggregateVoteSigns error", "error", err, "proposalId", utils.F(proposalId))
+	}
+	if agg != nil {
+		return nil, agg
+	}
+	return signs, nil
+}
+
+func (s *Smr) loadAggregateSign(proposalId []byte) *chainedBftPb.QuorumCertAggregateSign {
+	v, ok := s.qcAggSigns.Load(utils.F(proposalId))
+	if !ok {
+		return nil
+	}
+	agg, _ := v.(*chainedBftPb.QuorumCertAggregateSign)
+	return agg
+}
+
+// storeAggregateSign 仅保留签名者最多的聚合签名
+func (s *Smr) storeAggregateSign(proposalId []byte, agg *chainedBftPb.QuorumCertAggregateSign) {
+	if cCrypto.CountAggregateSigners(agg) > cCrypto.CountAggregateSigners(s.loadAggregateSign(proposalId)) {
+		s.qcAggSigns.Store(utils.F(proposalId), agg)
+	}
+}
+
 func (s *Smr) validNewHighQC(inProposalId []byte, validators []string) bool {
 	signInfo, ok := s.qcVoteMsgs.Load(utils.F(inProposalId))
-	if !ok {
+	agg := s.loadAggregateSign(inProposalId)
+	if !ok && agg == nil {
 		return false
 	}
-	signs, ok := signInfo.([]*chainedBftPb.QuorumCertSign)
-	if !ok {
-		return false
+	signs, _ := signInfo.([]*chainedBftPb.QuorumCertSign)
+	signCnt := len(signs)
+	if aggCnt := cCrypto.CountAggregateSigners(agg); aggCnt > signCnt {
+		signCnt = aggCnt
 	}
 	if len(validators) == 1 {
-		return len(signs) == len(validators)
+		return signCnt == len(validators)
 	}
-	return s.saftyrules.CalVotesThreshold(len(signs), len(validators))
+	return s.saftyrules.CalVotesThreshold(signCnt, len(validators))
 }
 
 func (s *Smr) enforceUpdateHighQC(inProposalId []byte) (bool, error) {
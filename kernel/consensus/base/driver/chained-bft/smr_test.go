@@ -2,9 +2,14 @@ package chained_bft
 
 import (
 	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/mock"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
@@ -12,9 +17,11 @@ import (
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
 	"github.com/xuperchain/xupercore/kernel/network"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
 	"github.com/xuperchain/xupercore/lib/crypto/client"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/utils"
+	xuperp2p "github.com/xuperchain/xupercore/protos"
 )
 
 var (
@@ -32,6 +39,9 @@ var (
 	NodeCIp = "/ip4/127.0.0.1/tcp/38203/p2p/QmZXjZibcL5hy2Ttv5CnAQnssvnCbPEGBzqk7sAnL69R1E"
 	PubKeyC = `{"Curvname":"P-256","X":71906497517774261659269469667273855852584750869988271615606376825756756449950,"Y":55040402911390674344019238894549124488349793311280846384605615474571192214233}`
 	PriKeyC = `{"Curvname":"P-256","X":71906497517774261659269469667273855852584750869988271615606376825756756449950,"Y":55040402911390674344019238894549124488349793311280846384605615474571192214233,"D":88987246094484003072412401376409995742867407472451866878930049879250160571952}`
+
+	// NodeD 为一个始终离线的validator
+	NodeD = "akf7qunmeaqb51Wu418d6TyPKp4jdLdpV"
 )
 
 type ElectionA struct {
@@ -121,6 +131,38 @@ func NewSMR(node string, log logs.Logger, p2p network.Network, t *testing.T) *Sm
 	return s
 }
 
+// ElectionB 包含4个validators，round按A、B、C、D轮转
+type ElectionB struct {
+	addrs []string
+}
+
+func (e *ElectionB) GetLeader(round int64) string {
+	pos := (round - 1) % 4
+	return e.addrs[pos]
+}
+
+func (e *ElectionB) GetValidators(round int64) []string {
+	return e.addrs
+}
+
+func NewTimeoutSMR(node string, log logs.Logger, p2p network.Network, timeout time.Duration, t *testing.T) *Smr {
+	a, cc := NewFakeCryptoClient(node, t)
+	cryptoClient := cCrypto.NewCBFTCrypto(&a, cc)
+	pacemaker := &DefaultPaceMaker{
+		BaseTimeout: timeout,
+	}
+	q := InitQcTee(log)
+	saftyrules := &DefaultSaftyRules{
+		Crypto: cryptoClient,
+		QcTree: q,
+		Log:    log,
+	}
+	election := &ElectionB{
+		addrs: []string{NodeA, NodeB, NodeC, NodeD},
+	}
+	return NewSmr("xuper", a.Address, log, p2p, cryptoClient, pacemaker, saftyrules, election, q)
+}
+
 func TestSMR(t *testing.T) {
 	th, _ := mock.NewTestHelper()
 	defer th.Close()
@@ -130,6 +172,9 @@ func TestSMR(t *testing.T) {
 	pA.Init(ctxA)
 	pB.Init(ctxB)
 	pC.Init(ctxC)
+	defer pA.Stop()
+	defer pB.Stop()
+	defer pC.Stop()
 	sA := NewSMR("nodeA", th.Log, pA, t)
 	sB := NewSMR("nodeB", th.Log, pB, t)
 	sC := NewSMR("nodeC", th.Log, pC, t)
@@ -203,7 +248,7 @@ func TestSMR(t *testing.T) {
 	}
 
 	// 模拟第三个Proposal交互, 此时模拟一个分叉情况，除B之外，A也创建了一个高度为2的块
-	// 注意，由于本状态机支持回滚，因此round可重复
+	// 注意，同一round内节点只为一个proposal投票，已为B的块投票的节点不会再为A的块投票
 	// 注意，为了支持回滚操作，必须调用smr的UpdateJustifyQcStatus
 	// 次数round1的全部选票在B手中
 	vote := &storage.VoteInfo{
@@ -232,15 +277,309 @@ func TestSMR(t *testing.T) {
 	}
 	time.Sleep(time.Second * 10)
 	nodeCH = sC.qcTree.GetHighQC()
-	if !bytes.Equal(nodeCH.In.GetProposalId(), []byte{3}) {
+	if !bytes.Equal(nodeCH.In.GetProposalId(), []byte{2}) {
 		t.Error("ProcessProposal error", "id", nodeCH.In.GetProposalId())
 	}
 	nodeBH = sB.qcTree.GetHighQC()
-	if len(nodeBH.Sons) != 2 {
-		t.Error("ProcessProposal error")
+	if len(nodeBH.Sons) != 1 {
+		t.Error("ProcessProposal error", "sons", len(nodeBH.Sons))
 	}
 	nodeAH = sA.qcTree.GetHighQC()
-	if len(nodeAH.Sons) != 2 {
-		t.Error("ProcessProposal error", "highQC", nodeAH.In.GetProposalView())
+	if len(nodeAH.Sons) != 1 {
+		t.Error("ProcessProposal error", "sons", len(nodeAH.Sons))
+	}
+}
+
+// TestSMRTimeout 模拟4个validators中D节点宕机的情况
+// A、B、C依次发起proposal，轮到D收集选票时无法形成QC，A、B、C超时后聚合出TC跳过该view，
+// 随后A携带TC发起跳过view的proposal，B、C应当接受
+func TestSMRTimeout(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	pA, ctxA, _ := kmock.NewP2P("nodeA")
+	pB, ctxB, _ := kmock.NewP2P("nodeB")
+	pC, ctxC, _ := kmock.NewP2P("nodeC")
+	pA.Init(ctxA)
+	pB.Init(ctxB)
+	pC.Init(ctxC)
+	defer pA.Stop()
+	defer pB.Stop()
+	defer pC.Stop()
+	go pA.Start()
+	go pB.Start()
+	go pC.Start()
+	time.Sleep(time.Second * 10)
+
+	timeout := time.Second * 3
+	sA := NewTimeoutSMR("nodeA", th.Log, pA, timeout, t)
+	sB := NewTimeoutSMR("nodeB", th.Log, pB, timeout, t)
+	sC := NewTimeoutSMR("nodeC", th.Log, pC, timeout, t)
+	sA.Start()
+	sB.Start()
+	sC.Start()
+	defer sA.Stop()
+	defer sB.Stop()
+	defer sC.Stop()
+	validators := []string{NodeA, NodeB, NodeC, NodeD}
+
+	// view1由A发起，B收集选票；view2由B发起，C收集选票
+	if err := sA.ProcessProposal(1, []byte{1}, []byte{0}, validators); err != nil {
+		t.Error("ProcessProposal error", "error", err)
+		return
+	}
+	time.Sleep(time.Second)
+	if err := sB.ProcessProposal(2, []byte{2}, []byte{1}, validators); err != nil {
+		t.Error("ProcessProposal error", "error", err)
+		return
+	}
+	time.Sleep(time.Second)
+	if sC.qcTree.GetHighQC().In.GetProposalView() != 2 {
+		t.Error("C collect votes error", "highQC", sC.qcTree.GetHighQC().In.GetProposalView())
+		return
+	}
+	// view3由C发起，选票发往已宕机的D，无法形成QC
+	if err := sC.ProcessProposal(3, []byte{3}, []byte{2}, validators); err != nil {
+		t.Error("ProcessProposal error", "error", err)
+		return
+	}
+
+	// 等待A、B、C超时并聚合出view3的TC
+	time.Sleep(timeout * 4)
+	for name, s := range map[string]*Smr{"A": sA, "B": sB, "C": sC} {
+		s.mtx.Lock()
+		highTC, view := s.highTC, s.pacemaker.GetCurrentView()
+		highQC := s.getHighQC().GetProposalView()
+		s.mtx.Unlock()
+		if highTC == nil || highTC.GetView() < 3 || view < 4 {
+			t.Error("timeout cert error", "node", name, "view", view, "highTC", highTC)
+			return
+		}
+		if err := s.saftyrules.CheckTimeoutCert(highTC, validators); err != nil {
+			t.Error("CheckTimeoutCert error", "node", name, "error", err)
+			return
+		}
+		// TimeoutMsg中携带的HighQC同步至所有节点
+		if highQC != 2 {
+			t.Error("sync highQC error", "node", name, "highQC", highQC)
+			return
+		}
+	}
+
+	// A携带TC发起view4的proposal，其parent为view2，跳过了超时的view3
+	// 模拟账本已同步至高度3
+	for _, s := range []*Smr{sA, sB, sC} {
+		s.mtx.Lock()
+		s.ledgerState = 3
+		s.mtx.Unlock()
+	}
+	sA.mtx.Lock()
+	err := sA.ProcessProposal(4, []byte{4}, []byte{2}, validators)
+	sA.mtx.Unlock()
+	if err != nil {
+		t.Error("ProcessProposal with tc error", "error", err)
+		return
+	}
+	time.Sleep(time.Second * 2)
+	for name, s := range map[string]*Smr{"B": sB, "C": sC} {
+		s.mtx.Lock()
+		node := s.qcTree.DFSQueryNode([]byte{4})
+		s.mtx.Unlock()
+		if node == nil || node.In.GetParentView() != 2 {
+			t.Error("accept proposal with tc error", "node", name)
+			return
+		}
+	}
+}
+
+// TestSMRAggregateSign 测试BLS模式下QC签名的聚合、传递及接收
+func TestSMRAggregateSign(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	cryptos := newBlsCryptos(t)
+	validators := []string{NodeA, NodeB, NodeC}
+	newBlsSMR := func(c *cCrypto.CBFTCrypto) *Smr {
+		q := InitQcTee(th.Log)
+		saftyrules := &DefaultSaftyRules{
+			Crypto: c,
+			QcTree: q,
+			Log:    th.Log,
+		}
+		election := &ElectionA{
+			addrs: validators,
+		}
+		s := NewSmr("xuper", c.Address.Address, th.Log, nil, c, &DefaultPaceMaker{}, saftyrules, election, q)
+		node := &storage.ProposalNode{
+			In: storage.NewQuorumCert(&storage.VoteInfo{
+				ProposalId:   []byte{1},
+				ProposalView: 1,
+				ParentId:     []byte{0},
+			}, nil, nil),
+		}
+		if err := s.qcTree.UpdateQcStatus(node); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	// A作为leader收集到B、C的选票后，生成的QC仅携带聚合签名
+	sA := newBlsSMR(cryptos[0])
+	var signs []*chainedBftPb.QuorumCertSign
+	for _, c := range cryptos[1:] {
+		sign, err := c.SignVoteMsg([]byte{1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs = append(signs, sign)
+	}
+	sA.LoadVotes([]byte{1}, signs)
+	if !sA.validNewHighQC([]byte{1}, validators) {
+		t.Fatal("validNewHighQC error")
+	}
+	qc, err := sA.reloadJustifyQC([]byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qc.GetSignsInfo() != nil || cCrypto.CountAggregateSigners(qc.GetAggregateSign()) != 2 {
+		t.Fatal("reloadJustifyQC should carry aggregate sign only")
+	}
+	if err := sA.saftyrules.CheckQuorumCert(qc, validators); err != nil {
+		t.Fatal("CheckQuorumCert error", err)
+	}
+
+	// 聚合签名随proposal的JustifyQC传递
+	raw, _ := json.Marshal(qc)
+	received := &storage.QuorumCert{}
+	if err := json.Unmarshal(raw, received); err != nil {
+		t.Fatal(err)
+	}
+	sB := newBlsSMR(cryptos[1])
+	if sB.validNewHighQC([]byte{1}, validators) {
+		t.Fatal("validNewHighQC should fail without signs")
+	}
+	sB.updateJustifyQcStatus(received)
+	if !sB.validNewHighQC([]byte{1}, validators) {
+		t.Fatal("validNewHighQC with aggregate sign error")
+	}
+	highQC := sB.getCompleteHighQC()
+	if !bytes.Equal(highQC.GetProposalId(), []byte{1}) ||
+		!bytes.Equal(highQC.GetAggregateSign().GetSign(), qc.GetAggregateSign().GetSign()) {
+		t.Fatal("getCompleteHighQC error")
+	}
+
+	// 老格式的QC序列化结果不变
+	legacy, _ := json.Marshal(storage.NewQuorumCert(&storage.VoteInfo{ProposalId: []byte{1}}, nil, nil))
+	if bytes.Contains(legacy, []byte("AggregateSign")) {
+		t.Fatal("legacy qc should not contain aggregate sign")
+	}
+}
+
+// crashNetwork 模拟在vote发出前宕机的节点，vote消息不会被真正发出
+// 发送时记录当时已落盘的投票状态，用于检查状态是否先于vote写入
+type crashNetwork struct {
+	network.Network
+	store *FileSafetyStore
+	votes chan *SafetyState
+}
+
+func (n *crashNetwork) SendMessage(_ xctx.XContext, msg *xuperp2p.XuperMessage, _ ...p2p.OptionFunc) error {
+	if msg.GetHeader().GetType() != xuperp2p.XuperMessage_CHAINED_BFT_VOTE_MSG {
+		return nil
+	}
+	state, _ := n.store.Load()
+	n.votes <- state
+	return nil
+}
+
+func newRestartSMR(node string, store *FileSafetyStore, log logs.Logger, t *testing.T) (*Smr, *crashNetwork) {
+	a, cc := NewFakeCryptoClient(node, t)
+	cryptoClient := cCrypto.NewCBFTCrypto(&a, cc)
+	q := InitQcTee(log)
+	saftyrules := &DefaultSaftyRules{
+		Crypto: cryptoClient,
+		QcTree: q,
+		Store:  store,
+		Log:    log,
+	}
+	election := &ElectionA{
+		addrs: []string{NodeA, NodeB, NodeC},
+	}
+	net := &crashNetwork{
+		store: store,
+		votes: make(chan *SafetyState, 1),
+	}
+	s := NewSmr("xuper", a.Address, log, net, cryptoClient, &DefaultPaceMaker{}, saftyrules, election, q)
+	if s == nil {
+		t.Fatal("NewSmr error")
+	}
+	// 模拟账本已同步，使proposal的view不受账本状态限制
+	s.ledgerState = 10
+	return s, net
+}
+
+func newProposalNetMsg(view int64, proposalId []byte, t *testing.T) *xuperp2p.XuperMessage {
+	a, cc := NewFakeCryptoClient("nodeA", t)
+	justify, _ := json.Marshal(storage.NewQuorumCert(&storage.VoteInfo{
+		ProposalId: []byte{0},
+	}, nil, nil))
+	msg, err := cCrypto.NewCBFTCrypto(&a, cc).SignProposalMsg(&chainedBftPb.ProposalMsg{
+		ProposalView: view,
+		ProposalId:   proposalId,
+		Timestamp:    time.Now().UnixNano(),
+		JustifyQC:    justify,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p2p.NewMessage(xuperp2p.XuperMessage_CHAINED_BFT_NEW_PROPOSAL_MSG, msg, p2p.WithBCName("xuper"))
+}
+
+// TestSMRRestartBetweenProposalAndVote C收到view8的proposal后在vote发出前宕机，
+// 重启后不能再为同一view或更低view的冲突proposal投票
+func TestSMRRestartBetweenProposalAndVote(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	dir, err := ioutil.TempDir("", "chained-bft-safety")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileSafetyStore(filepath.Join(dir, SafetyStateFileName))
+
+	sC, net := newRestartSMR("nodeC", store, th.Log, t)
+	sC.handleReceivedProposal(newProposalNetMsg(8, []byte{8}, t))
+	select {
+	case state := <-net.votes:
+		if state == nil || state.LastVoteRound != 8 || !bytes.Equal(state.LastVoteId, []byte{8}) {
+			t.Fatal("safety state should be persisted before vote is sent", state)
+		}
+	case <-time.After(time.Second * 3):
+		t.Fatal("vote for view 8 not sent")
+	}
+
+	// 重启，NewSmr从本地存储恢复投票状态
+	sC, net = newRestartSMR("nodeC", NewFileSafetyStore(filepath.Join(dir, SafetyStateFileName)), th.Log, t)
+	if rules := sC.saftyrules.(*DefaultSaftyRules); rules.lastVoteRound != 8 || !bytes.Equal(rules.lastVoteId, []byte{8}) {
+		t.Fatal("reload safety state error", rules.lastVoteRound, rules.lastVoteId)
+	}
+	sC.handleReceivedProposal(newProposalNetMsg(8, []byte{9}, t))
+	select {
+	case <-net.votes:
+		t.Fatal("restarted node should not vote for conflicting proposal at the same view")
+	case <-time.After(time.Second):
+	}
+	sC.handleReceivedProposal(newProposalNetMsg(4, []byte{4}, t))
+	select {
+	case <-net.votes:
+		t.Fatal("restarted node should not vote for lower view")
+	case <-time.After(time.Second):
+	}
+
+	// 作为对照，未持久化状态的节点重启后会为该proposal投票
+	sA, netA := newRestartSMR("nodeA", NewFileSafetyStore(filepath.Join(dir, "empty.json")), th.Log, t)
+	sA.handleReceivedProposal(newProposalNetMsg(4, []byte{4}, t))
+	select {
+	case <-netA.votes:
+	case <-time.After(time.Second * 3):
+		t.Fatal("vote for view 4 not sent")
 	}
 }
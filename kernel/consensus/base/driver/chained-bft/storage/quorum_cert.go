@@ -25,6 +25,7 @@ type QuorumCertInterface interface {
 	GetParentProposalId() []byte
 	GetParentView() int64
 	GetSignsInfo() []*pb.QuorumCertSign
+	GetAggregateSign() *pb.QuorumCertAggregateSign
 }
 
 // VoteInfo 包含了本次和上次的vote对象
@@ -53,3 +54,13 @@ func NewQuorumCert(v *VoteInfo, l *LedgerCommitInfo, s []*pb.QuorumCertSign) Quo
 	}
 	return &qc
 }
+
+// NewAggregateQuorumCert 创建BLS模式下的QC，签名为聚合签名及签名者位图
+func NewAggregateQuorumCert(v *VoteInfo, l *LedgerCommitInfo, agg *pb.QuorumCertAggregateSign) QuorumCertInterface {
+	qc := QuorumCert{
+		VoteInfo:         v,
+		LedgerCommitInfo: l,
+		AggregateSign:    agg,
+	}
+	return &qc
+}
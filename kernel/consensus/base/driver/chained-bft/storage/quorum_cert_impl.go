@@ -12,6 +12,8 @@ type QuorumCert struct {
 	LedgerCommitInfo *LedgerCommitInfo
 	// SignInfos is the signs of the leader gathered from replicas of a specifically certType.
 	SignInfos []*pb.QuorumCertSign
+	// AggregateSign 为BLS模式下的聚合签名，非BLS模式下为空，序列化时省略以兼容老格式
+	AggregateSign *pb.QuorumCertAggregateSign `json:",omitempty"`
 }
 
 func (qc *QuorumCert) GetProposalView() int64 {
@@ -33,3 +35,7 @@ func (qc *QuorumCert) GetParentView() int64 {
 func (qc *QuorumCert) GetSignsInfo() []*pb.QuorumCertSign {
 	return qc.SignInfos
 }
+
+func (qc *QuorumCert) GetAggregateSign() *pb.QuorumCertAggregateSign {
+	return qc.AggregateSign
+}
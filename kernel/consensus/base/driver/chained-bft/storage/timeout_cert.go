@@ -0,0 +1,29 @@
+package storage
+
+import (
+	pb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+)
+
+// TimeoutCert 即超时证书(TC)，由2f+1个节点对同一view的TimeoutMsg签名聚合而成
+// TC证明了该view已无法产生QC，节点可据此跳过该view
+type TimeoutCert struct {
+	// 超时的view
+	View int64
+	// 各节点对该view超时消息的签名
+	Signs []*pb.QuorumCertSign
+}
+
+func NewTimeoutCert(view int64, signs []*pb.QuorumCertSign) *TimeoutCert {
+	return &TimeoutCert{
+		View:  view,
+		Signs: signs,
+	}
+}
+
+func (tc *TimeoutCert) GetView() int64 {
+	return tc.View
+}
+
+func (tc *TimeoutCert) GetSignsInfo() []*pb.QuorumCertSign {
+	return tc.Signs
+}
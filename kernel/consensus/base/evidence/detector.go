@@ -0,0 +1,186 @@
+package evidence
+
+import (
+	"fmt"
+	"sync"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// MaxCacheSize Detector最多缓存的时隙数
+const MaxCacheSize = 1000
+
+// slotRecord 同一proposer在同一时隙内或同一高度上签名的区块，及本地见到的对这些区块的vote签名
+type slotRecord struct {
+	// 出块时隙或heightSlot生成的高度标识，只上报Verifier给出相同标识的证据，避免同一证据被两个记录重复上报
+	slot   string
+	blocks []*lpb.InternalBlock
+	// blockid -> address -> sign
+	votes map[string]map[string]*chainedBftPb.QuorumCertSign
+	// 已生成过的证据，避免重复上报
+	reported map[string]bool
+}
+
+// Detector 记录本地见到的区块和vote签名，发现冲突时生成证据
+// 生成的证据均已通过Verifier校验，调用方可直接用于构造证据交易
+type Detector struct {
+	verifier *Verifier
+
+	mtx sync.Mutex
+	// proposer_slot -> slotRecord
+	slots map[string]*slotRecord
+	// blockid -> 区块所在的proposer_slot，每个区块同时记录在出块时隙和高度两个slotRecord中
+	blocks map[string][]string
+	// 按插入顺序记录的slot，用于淘汰
+	order []string
+}
+
+func NewDetector(v *Verifier) *Detector {
+	return &Detector{
+		verifier: v,
+		slots:    make(map[string]*slotRecord),
+		blocks:   make(map[string][]string),
+	}
+}
+
+// AddBlock 记录区块头，返回由该区块新发现的证据
+func (d *Detector) AddBlock(block *lpb.InternalBlock) []*Evidence {
+	if block == nil || d.verifier.verifyBlock(block) != nil {
+		return nil
+	}
+	slots := []string{heightSlot(block.Height)}
+	if slot, err := d.verifier.Slot(block.Timestamp); err == nil {
+		slots = append(slots, slot)
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	id := utils.F(block.Blockid)
+	if _, ok := d.blocks[id]; ok {
+		return nil
+	}
+
+	var evidences []*Evidence
+	for _, slot := range slots {
+		key := fmt.Sprintf("%s_%s", block.Proposer, slot)
+		record, ok := d.slots[key]
+		if !ok {
+			record = &slotRecord{
+				slot:     slot,
+				votes:    make(map[string]map[string]*chainedBftPb.QuorumCertSign),
+				reported: make(map[string]bool),
+			}
+			d.slots[key] = record
+			d.order = append(d.order, key)
+		}
+		d.blocks[id] = append(d.blocks[id], key)
+
+		for _, other := range record.blocks {
+			if e := d.check(record, &Evidence{
+				Type:   TypeBlock,
+				Blocks: []*lpb.InternalBlock{other, block},
+			}); e != nil {
+				evidences = append(evidences, e)
+			}
+		}
+		record.blocks = append(record.blocks, block)
+	}
+	d.evict()
+	return evidences
+}
+
+// AddVotes 记录对blockid的vote签名，返回由这些签名新发现的vote证据
+func (d *Detector) AddVotes(blockid []byte, signs []*chainedBftPb.QuorumCertSign) []*Evidence {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	id := utils.F(blockid)
+	var evidences []*Evidence
+	for _, key := range d.blocks[id] {
+		evidences = append(evidences, d.addVotes(d.slots[key], blockid, signs)...)
+	}
+	return evidences
+}
+
+// addVotes 在一个slotRecord中记录vote签名并检查冲突
+func (d *Detector) addVotes(record *slotRecord, blockid []byte, signs []*chainedBftPb.QuorumCertSign) []*Evidence {
+	id := utils.F(blockid)
+	if _, ok := record.votes[id]; !ok {
+		record.votes[id] = make(map[string]*chainedBftPb.QuorumCertSign)
+	}
+	var evidences []*Evidence
+	for _, sign := range signs {
+		if sign == nil || sign.GetAddress() == "" {
+			continue
+		}
+		record.votes[id][sign.GetAddress()] = sign
+		for _, other := range record.blocks {
+			otherId := utils.F(other.Blockid)
+			if otherId == id {
+				continue
+			}
+			otherSign, ok := record.votes[otherId][sign.GetAddress()]
+			if !ok {
+				continue
+			}
+			if e := d.check(record, &Evidence{
+				Type:   TypeVote,
+				Blocks: []*lpb.InternalBlock{other, d.block(record, blockid)},
+				Votes:  []*chainedBftPb.QuorumCertSign{otherSign, sign},
+			}); e != nil {
+				evidences = append(evidences, e)
+			}
+		}
+	}
+	return evidences
+}
+
+// check 校验证据，对每个作恶行为仅返回一次
+func (d *Detector) check(record *slotRecord, e *Evidence) *Evidence {
+	r, err := d.verifier.Verify(e)
+	if err != nil || r.Slot != record.slot || record.reported[r.Key()] {
+		return nil
+	}
+	record.reported[r.Key()] = true
+	return e
+}
+
+func (d *Detector) block(record *slotRecord, blockid []byte) *lpb.InternalBlock {
+	for _, b := range record.blocks {
+		if utils.F(b.Blockid) == utils.F(blockid) {
+			return b
+		}
+	}
+	return nil
+}
+
+// evict 淘汰最早的slot，保证缓存大小有界
+func (d *Detector) evict() {
+	for len(d.order) > MaxCacheSize {
+		key := d.order[0]
+		d.order = d.order[1:]
+		record := d.slots[key]
+		for _, b := range record.blocks {
+			d.removeBlockKey(utils.F(b.Blockid), key)
+		}
+		delete(d.slots, key)
+	}
+}
+
+// removeBlockKey 从区块的slot列表中删除key，区块不在任何slotRecord中时删除区块记录
+func (d *Detector) removeBlockKey(id, key string) {
+	keys := d.blocks[id]
+	for i, k := range keys {
+		if k == key {
+			keys = append(keys[:i], keys[i+1:]...)
+			break
+		}
+	}
+	if len(keys) == 0 {
+		delete(d.blocks, id)
+		return
+	}
+	d.blocks[id] = keys
+}
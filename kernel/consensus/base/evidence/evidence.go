@@ -0,0 +1,289 @@
+// evidence 定义了双签证据的结构、校验方法和惩罚策略，供tdpos、xpoa等按时隙出块的共识使用
+// 一个proposer在同一出块时隙内、同一高度上都只应签名一个区块，
+// 同一时隙或同一高度的两个不同区块的签名即构成区块双签证据
+// chained-bft的vote签名内容仅为proposalId，无法从签名本身判断两次投票是否冲突，
+// 因此vote双签证据定义为同一validator对一组区块双签证据中的两个区块均进行了投票
+package evidence
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	xledger "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// TypeBlock 同一proposer在同一时隙内或同一高度上签名了两个不同的区块
+	TypeBlock = "block"
+	// TypeVote 同一validator对两个冲突区块均进行了投票
+	TypeVote = "vote"
+
+	// MethodSubmitEvidence 共识合约bucket上提交证据的kernel方法
+	MethodSubmitEvidence = "submitEvidence"
+	// ArgEvidence 提交证据时json编码的Evidence参数名
+	ArgEvidence = "evidence"
+
+	// MaxRatio 销毁比例的单位，即BurnRatio为百分比
+	MaxRatio = 100
+)
+
+var (
+	ErrEvidenceType  = errors.New("evidence type is unknown")
+	ErrEvidenceArgs  = errors.New("evidence args is invalid")
+	ErrBlockSign     = errors.New("blockid or sign of block in evidence is invalid")
+	ErrNotConflict   = errors.New("blocks in evidence are not conflicting")
+	ErrVoteSign      = errors.New("vote sign in evidence is invalid")
+	ErrEvidenceExist = errors.New("evidence has been submitted")
+	ErrInvalidPolicy = errors.New("slash config is invalid")
+	ErrRedoSigned    = errors.New("block at the redo height has been signed by the proposer")
+)
+
+// Evidence 双签证据，Blocks为两个冲突区块的区块头(不含交易)
+type Evidence struct {
+	Type   string               `json:"type"`
+	Blocks []*lpb.InternalBlock `json:"blocks"`
+	// Votes 仅在TypeVote时存在，Votes[i]为作恶validator对Blocks[i]的vote签名
+	Votes []*chainedBftPb.QuorumCertSign `json:"votes,omitempty"`
+}
+
+// Record 证据校验通过后的结果，Key用于链上去重，保证同一时隙的同一作恶行为仅被惩罚一次
+type Record struct {
+	Type      string `json:"type"`
+	Offender  string `json:"offender"`
+	Slot      string `json:"slot"`
+	Timestamp int64  `json:"timestamp"`
+	// JailUntil、Burned 由共识在执行惩罚后填写
+	JailUntil int64 `json:"jail_until,omitempty"`
+	Burned    int64 `json:"burned,omitempty"`
+}
+
+func (r *Record) Key() string {
+	return fmt.Sprintf("%s_%s_%s", r.Type, r.Offender, r.Slot)
+}
+
+// Policy 双签惩罚策略，对应共识配置中的slash_config
+type Policy struct {
+	// JailTerms 区块双签的候选人在之后JailTerms轮内不能被选为候选人
+	JailTerms int64 `json:"jail_terms"`
+	// BurnRatio 区块双签时销毁的提名质押比例，单位为百分之一
+	BurnRatio int64 `json:"burn_ratio"`
+	// VoteJailTerms、VoteBurnRatio 为vote双签的惩罚
+	// 作恶的proposer向不同节点发送同一时隙的不同区块时，诚实节点也可能对两者都投票，因此默认不惩罚vote双签
+	VoteJailTerms int64 `json:"vote_jail_terms,omitempty"`
+	VoteBurnRatio int64 `json:"vote_burn_ratio,omitempty"`
+}
+
+func (p *Policy) Validate() error {
+	if p.JailTerms < 0 || p.VoteJailTerms < 0 {
+		return ErrInvalidPolicy
+	}
+	if p.BurnRatio < 0 || p.BurnRatio > MaxRatio || p.VoteBurnRatio < 0 || p.VoteBurnRatio > MaxRatio {
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+// Penalty 返回对应证据类型的禁闭轮数和销毁比例
+func (p *Policy) Penalty(evidenceType string) (int64, int64) {
+	if evidenceType == TypeVote {
+		return p.VoteJailTerms, p.VoteBurnRatio
+	}
+	return p.JailTerms, p.BurnRatio
+}
+
+// SlotFunc 返回timestamp所在出块时隙的标识
+type SlotFunc func(timestamp int64) (string, error)
+
+// Verifier 校验双签证据，Crypto需与链的哈希算法及BLS配置一致
+type Verifier struct {
+	Crypto *cCrypto.CBFTCrypto
+	Slot   SlotFunc
+}
+
+func NewVerifier(c *cCrypto.CBFTCrypto, slot SlotFunc) *Verifier {
+	return &Verifier{
+		Crypto: c,
+		Slot:   slot,
+	}
+}
+
+// Verify 校验证据，返回作恶者及时隙信息
+func (v *Verifier) Verify(e *Evidence) (*Record, error) {
+	if e == nil || len(e.Blocks) != 2 {
+		return nil, ErrEvidenceArgs
+	}
+	slot, err := v.verifyConflict(e.Blocks[0], e.Blocks[1])
+	if err != nil {
+		return nil, err
+	}
+	record := &Record{
+		Type:      e.Type,
+		Offender:  string(e.Blocks[0].Proposer),
+		Slot:      slot,
+		Timestamp: e.Blocks[0].Timestamp,
+	}
+	switch e.Type {
+	case TypeBlock:
+		if len(e.Votes) != 0 {
+			return nil, ErrEvidenceArgs
+		}
+	case TypeVote:
+		if len(e.Votes) != 2 || e.Votes[0] == nil || e.Votes[1] == nil {
+			return nil, ErrEvidenceArgs
+		}
+		voter := e.Votes[0].GetAddress()
+		if voter == "" || voter != e.Votes[1].GetAddress() {
+			return nil, ErrVoteSign
+		}
+		for i, sign := range e.Votes {
+			if err := v.verifyVote(sign, e.Blocks[i].Blockid); err != nil {
+				return nil, err
+			}
+		}
+		record.Offender = voter
+	default:
+		return nil, ErrEvidenceType
+	}
+	return record, nil
+}
+
+// verifyConflict 检查两个区块由同一proposer在同一时隙或同一高度签名，返回时隙标识
+// 两个区块同时满足时以出块时隙为准，同一高度的标识由heightSlot生成
+func (v *Verifier) verifyConflict(a, b *lpb.InternalBlock) (string, error) {
+	if err := v.verifyBlock(a); err != nil {
+		return "", err
+	}
+	if err := v.verifyBlock(b); err != nil {
+		return "", err
+	}
+	if !bytes.Equal(a.Proposer, b.Proposer) || bytes.Equal(a.Blockid, b.Blockid) {
+		return "", ErrNotConflict
+	}
+	slotA, errA := v.Slot(a.Timestamp)
+	slotB, errB := v.Slot(b.Timestamp)
+	if errA == nil && errB == nil && slotA == slotB {
+		return slotA, nil
+	}
+	// chained-bft同一view只会投票一次，同一高度的两个区块即使在不同时隙也属于双签
+	if a.Height == b.Height {
+		return heightSlot(a.Height), nil
+	}
+	if errA != nil {
+		return "", errA
+	}
+	if errB != nil {
+		return "", errB
+	}
+	return "", ErrNotConflict
+}
+
+// heightSlot 返回同一高度双签使用的时隙标识
+func heightSlot(height int64) string {
+	return fmt.Sprintf("height_%d", height)
+}
+
+// verifyBlock 检查区块头的blockid和proposer签名
+func (v *Verifier) verifyBlock(block *lpb.InternalBlock) error {
+	if block == nil || len(block.Blockid) == 0 || len(block.Proposer) == 0 {
+		return ErrEvidenceArgs
+	}
+	blockid, err := xledger.MakeBlockIDWithHasher(block, v.Crypto.Hasher)
+	if err != nil || !bytes.Equal(blockid, block.Blockid) {
+		return ErrBlockSign
+	}
+	ak, err := v.Crypto.CryptoClient.GetEcdsaPublicKeyFromJsonStr(string(block.Pubkey))
+	if err != nil {
+		return ErrBlockSign
+	}
+	if ok, _ := v.Crypto.CryptoClient.VerifyAddressUsingPublicKey(string(block.Proposer), ak); !ok {
+		return ErrBlockSign
+	}
+	if ok, err := v.Crypto.CryptoClient.VerifyECDSA(ak, block.Sign, block.Blockid); err != nil || !ok {
+		return ErrBlockSign
+	}
+	return nil
+}
+
+func (v *Verifier) verifyVote(sign *chainedBftPb.QuorumCertSign, blockid []byte) error {
+	var ok bool
+	var err error
+	if v.Crypto.IsBlsEnabled() {
+		ok, err = v.Crypto.VerifyBlsVoteSign(sign, blockid)
+	} else {
+		ok, err = v.Crypto.VerifyVoteMsgSign(sign, blockid)
+	}
+	if err != nil || !ok {
+		return ErrVoteSign
+	}
+	return nil
+}
+
+// ParseEvidence 从kernel方法参数中解析证据
+func ParseEvidence(args map[string][]byte) (*Evidence, error) {
+	raw, ok := args[ArgEvidence]
+	if !ok || len(raw) == 0 {
+		return nil, ErrEvidenceArgs
+	}
+	e := &Evidence{}
+	if err := json.Unmarshal(raw, e); err != nil {
+		return nil, ErrEvidenceArgs
+	}
+	return e, nil
+}
+
+// NewEvidenceRequest 构造向共识合约bucket提交证据的合约调用，可直接作为交易的ContractRequests
+func NewEvidenceRequest(contractName string, e *Evidence) (*protos.InvokeRequest, error) {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.InvokeRequest{
+		ModuleName:   "xkernel",
+		ContractName: contractName,
+		MethodName:   MethodSubmitEvidence,
+		Args: map[string][]byte{
+			ArgEvidence: raw,
+		},
+	}, nil
+}
+
+// blockHeaderGetter 由xledger的BlockAgent实现，用于获取构造证据所需的完整区块头
+type blockHeaderGetter interface {
+	GetBlockHeader() *lpb.InternalBlock
+}
+
+// BlockHeader 返回block的完整区块头，block不支持时返回nil
+func BlockHeader(block cctx.BlockInterface) *lpb.InternalBlock {
+	if b, ok := block.(blockHeaderGetter); ok {
+		return b.GetBlockHeader()
+	}
+	return nil
+}
+
+// CheckRedo 共识回滚到target重做区块前检查，从tip回溯到紧接target的区块，
+// 若该区块由proposer签名，重做会在同一高度签名两个不同区块而构成双签，此时返回ErrRedoSigned，应由其他proposer重做
+func CheckRedo(tip cctx.BlockInterface, target []byte, proposer string,
+	query func(blkId []byte) (ledger.BlockHandle, error)) error {
+	var block ledger.BlockHandle = tip
+	for !bytes.Equal(block.GetPreHash(), target) {
+		if bytes.Equal(block.GetBlockid(), target) || block.GetHeight() <= 0 {
+			return nil
+		}
+		var err error
+		if block, err = query(block.GetPreHash()); err != nil {
+			return err
+		}
+	}
+	if string(block.GetProposer()) == proposer {
+		return ErrRedoSigned
+	}
+	return nil
+}
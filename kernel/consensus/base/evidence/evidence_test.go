@@ -0,0 +1,232 @@
+package evidence
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	xledger "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
+)
+
+var (
+	NodeA   = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
+	PubKeyA = `{"Curvname":"P-256","X":36505150171354363400464126431978257855318414556425194490762274938603757905292,"Y":79656876957602994269528255245092635964473154458596947290316223079846501380076}`
+	PriKeyA = `{"Curvname":"P-256","X":36505150171354363400464126431978257855318414556425194490762274938603757905292,"Y":79656876957602994269528255245092635964473154458596947290316223079846501380076,"D":111497060296999106528800133634901141644446751975433315540300236500052690483486}`
+
+	NodeB   = "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"
+	PubKeyB = `{"Curvname":"P-256","X":12866043091588565003171939933628544430893620588191336136713947797738961176765,"Y":82755103183873558994270855453149717093321792154549800459286614469868720031056}`
+	PriKeyB = `{"Curvname":"P-256","X":12866043091588565003171939933628544430893620588191336136713947797738961176765,"Y":82755103183873558994270855453149717093321792154549800459286614469868720031056,"D":74053182141043989390619716280199465858509830752513286817516873984288039572219}`
+
+	// 测试使用3s一个时隙
+	slotPeriod = int64(3 * time.Second)
+)
+
+func newTestCrypto(addr, pub, pri string, t *testing.T) *cCrypto.CBFTCrypto {
+	cc, err := client.CreateCryptoClientFromJSONPrivateKey([]byte(pri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, _ := cc.GetEcdsaPrivateKeyFromJsonStr(pri)
+	pk, _ := cc.GetEcdsaPublicKeyFromJsonStr(pub)
+	a := &cctx.Address{
+		Address:       addr,
+		PrivateKeyStr: pri,
+		PublicKeyStr:  pub,
+		PrivateKey:    sk,
+		PublicKey:     pk,
+	}
+	return cCrypto.NewCBFTCryptoWithHasher(a, cc, hash.DefaultHasher)
+}
+
+func newTestVerifier(t *testing.T) *Verifier {
+	return NewVerifier(newTestCrypto(NodeA, PubKeyA, PriKeyA, t), func(timestamp int64) (string, error) {
+		return fmt.Sprintf("%d", timestamp/slotPeriod), nil
+	})
+}
+
+func newSignedBlock(c *cCrypto.CBFTCrypto, height, timestamp int64, preHash []byte, t *testing.T) *lpb.InternalBlock {
+	b := &lpb.InternalBlock{
+		Version:   1,
+		PreHash:   preHash,
+		Proposer:  []byte(c.Address.Address),
+		Pubkey:    []byte(c.Address.PublicKeyStr),
+		Height:    height,
+		Timestamp: timestamp,
+	}
+	id, err := xledger.MakeBlockIDWithHasher(b, c.Hasher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Blockid = id
+	b.Sign, err = c.CryptoClient.SignECDSA(c.Address.PrivateKey, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestVerifyBlockEvidence(t *testing.T) {
+	v := newTestVerifier(t)
+	a := newTestCrypto(NodeA, PubKeyA, PriKeyA, t)
+	ts := 100 * slotPeriod
+	b1 := newSignedBlock(a, 10, ts, []byte{9}, t)
+	b2 := newSignedBlock(a, 10, ts+1, []byte{8}, t)
+
+	record, err := v.Verify(&Evidence{Type: TypeBlock, Blocks: []*lpb.InternalBlock{b1, b2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Offender != NodeA || record.Key() != fmt.Sprintf("%s_%s_%d", TypeBlock, NodeA, 100) {
+		t.Fatal("unexpected record", record)
+	}
+
+	// 不同时隙的同一高度区块同样构成双签
+	b3 := newSignedBlock(a, 10, ts+slotPeriod, []byte{8}, t)
+	record, err = v.Verify(&Evidence{Type: TypeBlock, Blocks: []*lpb.InternalBlock{b1, b3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Key() != fmt.Sprintf("%s_%s_height_%d", TypeBlock, NodeA, 10) {
+		t.Fatal("unexpected record", record)
+	}
+	// 不同时隙且不同高度的区块不冲突
+	b5 := newSignedBlock(a, 11, ts+slotPeriod, []byte{8}, t)
+	if _, err := v.Verify(&Evidence{Type: TypeBlock, Blocks: []*lpb.InternalBlock{b1, b5}}); err != ErrNotConflict {
+		t.Fatal("different slots and heights should not conflict", err)
+	}
+	if _, err := v.Verify(&Evidence{Type: TypeBlock, Blocks: []*lpb.InternalBlock{b1, b1}}); err != ErrNotConflict {
+		t.Fatal("same block should not conflict", err)
+	}
+	// 篡改区块内容
+	forged := *b2
+	forged.Timestamp = ts + 2
+	if _, err := v.Verify(&Evidence{Type: TypeBlock, Blocks: []*lpb.InternalBlock{b1, &forged}}); err != ErrBlockSign {
+		t.Fatal("forged block should be rejected", err)
+	}
+	// 不同proposer
+	b := newTestCrypto(NodeB, PubKeyB, PriKeyB, t)
+	b4 := newSignedBlock(b, 10, ts, []byte{9}, t)
+	if _, err := v.Verify(&Evidence{Type: TypeBlock, Blocks: []*lpb.InternalBlock{b1, b4}}); err != ErrNotConflict {
+		t.Fatal("blocks of different proposers should not conflict", err)
+	}
+}
+
+func TestVerifyVoteEvidence(t *testing.T) {
+	v := newTestVerifier(t)
+	a := newTestCrypto(NodeA, PubKeyA, PriKeyA, t)
+	b := newTestCrypto(NodeB, PubKeyB, PriKeyB, t)
+	ts := 100 * slotPeriod
+	b1 := newSignedBlock(a, 10, ts, []byte{9}, t)
+	b2 := newSignedBlock(a, 10, ts+1, []byte{8}, t)
+	s1, _ := b.SignVoteMsg(b1.Blockid)
+	s2, _ := b.SignVoteMsg(b2.Blockid)
+
+	e := &Evidence{
+		Type:   TypeVote,
+		Blocks: []*lpb.InternalBlock{b1, b2},
+		Votes:  []*chainedBftPb.QuorumCertSign{s1, s2},
+	}
+	record, err := v.Verify(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Offender != NodeB {
+		t.Fatal("vote offender should be the voter", record.Offender)
+	}
+	// 签名与区块不对应
+	e.Votes = []*chainedBftPb.QuorumCertSign{s2, s1}
+	if _, err := v.Verify(e); err != ErrVoteSign {
+		t.Fatal("swapped votes should be rejected", err)
+	}
+	// 被投票的区块不冲突时，vote不构成证据
+	b3 := newSignedBlock(a, 11, ts+slotPeriod, []byte{8}, t)
+	s3, _ := b.SignVoteMsg(b3.Blockid)
+	e = &Evidence{
+		Type:   TypeVote,
+		Blocks: []*lpb.InternalBlock{b1, b3},
+		Votes:  []*chainedBftPb.QuorumCertSign{s1, s3},
+	}
+	if _, err := v.Verify(e); err != ErrNotConflict {
+		t.Fatal("votes on different slots and heights should not conflict", err)
+	}
+}
+
+func TestDetector(t *testing.T) {
+	d := NewDetector(newTestVerifier(t))
+	a := newTestCrypto(NodeA, PubKeyA, PriKeyA, t)
+	b := newTestCrypto(NodeB, PubKeyB, PriKeyB, t)
+	ts := 100 * slotPeriod
+	b1 := newSignedBlock(a, 10, ts, []byte{9}, t)
+	b2 := newSignedBlock(a, 10, ts+1, []byte{8}, t)
+	s1, _ := b.SignVoteMsg(b1.Blockid)
+	s2, _ := b.SignVoteMsg(b2.Blockid)
+
+	if evs := d.AddBlock(b1); len(evs) != 0 {
+		t.Fatal("single block should not produce evidence")
+	}
+	if evs := d.AddVotes(b1.Blockid, []*chainedBftPb.QuorumCertSign{s1}); len(evs) != 0 {
+		t.Fatal("single vote should not produce evidence")
+	}
+	evs := d.AddBlock(b2)
+	if len(evs) != 1 || evs[0].Type != TypeBlock {
+		t.Fatal("conflicting block should produce evidence", evs)
+	}
+	if evs := d.AddBlock(b2); len(evs) != 0 {
+		t.Fatal("evidence should be reported only once")
+	}
+	evs = d.AddVotes(b2.Blockid, []*chainedBftPb.QuorumCertSign{s2})
+	if len(evs) != 1 || evs[0].Type != TypeVote || evs[0].Votes[0].GetAddress() != NodeB {
+		t.Fatal("conflicting votes should produce evidence", evs)
+	}
+
+	// 证据可直接构造为合约调用并被解析
+	req, err := NewEvidenceRequest("$tdpos", evs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseEvidence(req.Args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(parsed)
+	if string(raw) != string(req.Args[ArgEvidence]) {
+		t.Fatal("parsed evidence mismatch")
+	}
+	if _, err := newTestVerifier(t).Verify(parsed); err != nil {
+		t.Fatal("parsed evidence should be valid", err)
+	}
+
+	// 不同时隙的同一高度区块与b1、b2都冲突，同一高度的双签仅上报一次
+	b3 := newSignedBlock(a, 10, ts+slotPeriod, []byte{7}, t)
+	evs = d.AddBlock(b3)
+	if len(evs) != 1 || evs[0].Type != TypeBlock {
+		t.Fatal("block at the same height should produce evidence", evs)
+	}
+	if evs := d.AddBlock(newSignedBlock(a, 11, ts+2*slotPeriod, []byte{7}, t)); len(evs) != 0 {
+		t.Fatal("block at different slot and height should not produce evidence", evs)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	p := &Policy{JailTerms: 2, BurnRatio: 10}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if jail, burn := p.Penalty(TypeVote); jail != 0 || burn != 0 {
+		t.Fatal("vote penalty should be disabled by default")
+	}
+	if jail, burn := p.Penalty(TypeBlock); jail != 2 || burn != 10 {
+		t.Fatal("unexpected block penalty")
+	}
+	p.BurnRatio = MaxRatio + 1
+	if err := p.Validate(); err != ErrInvalidPolicy {
+		t.Fatal("burn ratio over 100 should be rejected")
+	}
+}
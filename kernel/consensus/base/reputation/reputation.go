@@ -0,0 +1,141 @@
+// reputation 根据账本中最近区块的出块记录为按时隙轮值出块的共识提供leader替换，供tdpos、xpoa等使用
+// 轮值到的proposer在最近一个统计窗口内一个区块都没有产出时，被视为不可靠，其时隙由顺位下一个可靠的validator代为出块，
+// 避免离线节点在每轮轮值中都造成一次出块超时
+// 出块记录全部读取自账本，统计窗口按高度对齐并取round-3的稳定高度，因此各节点计算结果一致
+// 被跳过的validator无法再出块证明自己，因此每ProbeInterval个窗口设置一个探测窗口，探测窗口内不做任何跳过
+package reputation
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+)
+
+var (
+	ErrInvalidConfig = errors.New("reputation config is invalid")
+)
+
+// Config leader替换策略，对应共识配置中的reputation
+type Config struct {
+	// Window 统计窗口的区块个数，应覆盖若干个完整的轮值周期，否则正常节点也可能因未轮到而被跳过
+	Window int64 `json:"window"`
+	// ProbeInterval 每ProbeInterval个窗口中有一个探测窗口，需大于1
+	ProbeInterval int64 `json:"probe_interval"`
+}
+
+func (c *Config) Validate() error {
+	if c.Window <= 0 || c.ProbeInterval <= 1 {
+		return ErrInvalidConfig
+	}
+	return nil
+}
+
+// ValidatorsFunc 返回指定round的候选人集合，与ProposerElectionInterface.GetValidators一致
+type ValidatorsFunc func(round int64) []string
+
+// Reputation 统计各validator在窗口内的出块情况，并对不可靠的leader进行顺位替换
+type Reputation struct {
+	config      *Config
+	ledger      cctx.LedgerRely
+	validators  ValidatorsFunc
+	startHeight int64
+
+	mutex sync.Mutex
+	// 缓存最近一个窗口的统计结果，anchorId为窗口末尾区块的blockid
+	anchorId []byte
+	window   *windowStat
+}
+
+// windowStat 统计窗口内各节点的出块数及窗口开始时的候选人集合
+type windowStat struct {
+	proposed map[string]int64
+	members  map[string]bool
+}
+
+// isUnreliable 窗口内未出块且窗口开始时已是候选人的节点为不可靠节点
+// 无法获取窗口开始时的候选人集合时，仅以出块数判断
+func (w *windowStat) isUnreliable(address string) bool {
+	if w == nil || w.proposed[address] > 0 {
+		return false
+	}
+	return len(w.members) == 0 || w.members[address]
+}
+
+// NewReputation 新建实例，startHeight为共识起始高度，统计窗口不会跨越共识起始高度
+func NewReputation(config *Config, ledger cctx.LedgerRely, validators ValidatorsFunc, startHeight int64) *Reputation {
+	return &Reputation{
+		config:      config,
+		ledger:      ledger,
+		validators:  validators,
+		startHeight: startHeight,
+	}
+}
+
+// Substitute 返回round实际应出块的节点，leader为validators按时隙轮值计算出的节点
+// leader可靠时直接返回，否则返回validators中leader之后第一个可靠的节点，全部不可靠时仍返回leader
+func (r *Reputation) Substitute(round int64, validators []string, leader string) string {
+	w := r.getWindow(round)
+	if !w.isUnreliable(leader) {
+		return leader
+	}
+	pos := -1
+	for i, v := range validators {
+		if v == leader {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return leader
+	}
+	for k := 1; k < len(validators); k++ {
+		v := validators[(pos+k)%len(validators)]
+		if !w.isUnreliable(v) {
+			return v
+		}
+	}
+	return leader
+}
+
+// getWindow 计算round所在窗口的出块统计，不需要替换时返回nil
+// 窗口末尾anchor为round-3向下对齐到Window整数倍的高度，统计(anchor-Window, anchor]内的区块
+// 仅在窗口开始时已是候选人的节点才会被判定为不可靠，新加入的候选人不受影响
+func (r *Reputation) getWindow(round int64) *windowStat {
+	w := r.config.Window
+	anchor := (round - 3) / w * w
+	if anchor-w < r.startHeight {
+		return nil
+	}
+	if (anchor/w)%r.config.ProbeInterval == 0 {
+		return nil
+	}
+	anchorBlock, err := r.ledger.QueryBlockHeaderByHeight(anchor)
+	if err != nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.anchorId != nil && bytes.Equal(r.anchorId, anchorBlock.GetBlockid()) {
+		return r.window
+	}
+	stat := &windowStat{
+		proposed: make(map[string]int64),
+		members:  make(map[string]bool),
+	}
+	for h := anchor - w + 1; h <= anchor; h++ {
+		b, err := r.ledger.QueryBlockHeaderByHeight(h)
+		if err != nil {
+			return nil
+		}
+		stat.proposed[string(b.GetProposer())]++
+	}
+	for _, v := range r.validators(anchor - w + 1) {
+		stat.members[v] = true
+	}
+	r.anchorId = anchorBlock.GetBlockid()
+	r.window = stat
+	return stat
+}
@@ -0,0 +1,152 @@
+package reputation
+
+import (
+	"fmt"
+	"testing"
+
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+)
+
+var (
+	validators = []string{"A", "B", "C", "D"}
+)
+
+func newTestLedger() *kmock.FakeLedger {
+	// FakeLedger 初始包含高度0~2的区块
+	return kmock.NewFakeLedger(nil)
+}
+
+func putBlock(l *kmock.FakeLedger, height int64, proposer string) {
+	b := kmock.NewBlock(int(height))
+	b.Blockid = []byte(fmt.Sprintf("block_%d", height))
+	b.PreHash = []byte(fmt.Sprintf("block_%d", height-1))
+	b.Proposer = proposer
+	l.Put(b)
+}
+
+func getValidators(round int64) []string {
+	return validators
+}
+
+// simulate 模拟slots个时隙的轮值出块，down中的节点离线，返回错过的时隙数及最终高度
+func simulate(r *Reputation, l *kmock.FakeLedger, slots int, down map[string]bool) (int, int64) {
+	missed := 0
+	height := l.QueryTipBlockHeader().GetHeight()
+	for s := 0; s < slots; s++ {
+		leader := validators[s%len(validators)]
+		if r != nil {
+			leader = r.Substitute(height+1, validators, leader)
+		}
+		if down[leader] {
+			missed++
+			continue
+		}
+		height++
+		putBlock(l, height, leader)
+	}
+	return missed, height
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Config{Window: 20, ProbeInterval: 5}).Validate(); err != nil {
+		t.Error("Validate error", err)
+	}
+	if err := (&Config{Window: 0, ProbeInterval: 5}).Validate(); err != ErrInvalidConfig {
+		t.Error("zero window should be rejected")
+	}
+	if err := (&Config{Window: 20, ProbeInterval: 1}).Validate(); err != ErrInvalidConfig {
+		t.Error("probe interval 1 should be rejected")
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	l := newTestLedger()
+	// 高度3~22中D没有出块
+	for h := int64(3); h <= 22; h++ {
+		putBlock(l, h, validators[h%3])
+	}
+	r := NewReputation(&Config{Window: 10, ProbeInterval: 3}, l, getValidators, 0)
+	// round=23时anchor=20，统计(10, 20]，窗口序号2非探测窗口
+	if leader := r.Substitute(23, validators, "D"); leader != "A" {
+		t.Error("unreliable leader should be substituted", "leader", leader)
+	}
+	if leader := r.Substitute(23, validators, "B"); leader != "B" {
+		t.Error("reliable leader should be kept", "leader", leader)
+	}
+	// round=13时anchor=10，统计(0, 10]，窗口序号1非探测窗口
+	if leader := r.Substitute(13, validators, "D"); leader != "A" {
+		t.Error("unreliable leader should be substituted", "leader", leader)
+	}
+	// 探测窗口不替换
+	for h := int64(23); h <= 33; h++ {
+		putBlock(l, h, validators[h%3])
+	}
+	if leader := r.Substitute(33, validators, "D"); leader != "D" {
+		t.Error("probe window should not substitute", "leader", leader)
+	}
+	// 窗口跨越共识起始高度时不替换
+	r = NewReputation(&Config{Window: 10, ProbeInterval: 3}, l, getValidators, 15)
+	if leader := r.Substitute(23, validators, "D"); leader != "D" {
+		t.Error("window before start height should not substitute", "leader", leader)
+	}
+}
+
+func TestSubstituteNewValidator(t *testing.T) {
+	l := newTestLedger()
+	for h := int64(3); h <= 22; h++ {
+		putBlock(l, h, validators[h%3])
+	}
+	// D在窗口开始之后才成为候选人
+	r := NewReputation(&Config{Window: 10, ProbeInterval: 3}, l, func(round int64) []string {
+		return validators[:3]
+	}, 0)
+	if leader := r.Substitute(23, validators, "D"); leader != "D" {
+		t.Error("new validator should not be substituted", "leader", leader)
+	}
+}
+
+func TestSubstituteAllUnreliable(t *testing.T) {
+	l := newTestLedger()
+	for h := int64(3); h <= 22; h++ {
+		putBlock(l, h, "E")
+	}
+	r := NewReputation(&Config{Window: 10, ProbeInterval: 3}, l, getValidators, 0)
+	if leader := r.Substitute(23, validators, "B"); leader != "B" {
+		t.Error("leader should be kept when all validators are unreliable", "leader", leader)
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	down := map[string]bool{"D": true}
+	slots := 400
+
+	missedRR, heightRR := simulate(nil, newTestLedger(), slots, down)
+	l := newTestLedger()
+	r := NewReputation(&Config{Window: 20, ProbeInterval: 5}, l, getValidators, 0)
+	missed, height := simulate(r, l, slots, down)
+	t.Log("round robin", "missed", missedRR, "height", heightRR, "reputation", "missed", missed, "height", height)
+	if missedRR != slots/len(validators) {
+		t.Fatal("round robin missed slots unexpected", "missed", missedRR)
+	}
+	// 仅在首个统计窗口及探测窗口内会轮值到离线节点
+	if missed*3 > missedRR {
+		t.Error("reputation should reduce missed slots", "missed", missed, "round robin", missedRR)
+	}
+	if height <= heightRR {
+		t.Error("reputation should produce more blocks", "height", height, "round robin", heightRR)
+	}
+
+	// 节点恢复后在探测窗口内重新出块，之后不再被跳过
+	recovered := 0
+	tip := l.QueryTipBlockHeader().GetHeight()
+	simulate(r, l, slots, nil)
+	for h := tip + 1; h <= l.QueryTipBlockHeader().GetHeight(); h++ {
+		b, _ := l.QueryBlockHeaderByHeight(h)
+		if string(b.GetProposer()) == "D" {
+			recovered++
+		}
+	}
+	if recovered < slots/len(validators)/2 {
+		t.Error("recovered validator should propose again", "blocks", recovered)
+	}
+}
@@ -21,6 +21,12 @@ type ConsensusInterface interface {
 	GetConsensusStatus() (ConsensusStatus, error)
 }
 
+// AwardInterface 共识实例可选实现的接口，用于将区块奖励转入指定地址而非矿工地址
+type AwardInterface interface {
+	// GetAwardAddress 返回height高度区块奖励的接收地址，返回空字符串时奖励归矿工所有
+	GetAwardAddress(height int64) string
+}
+
 type PluggableConsensusInterface interface {
 	ConsensusInterface
 	SwitchConsensus(height int64) error
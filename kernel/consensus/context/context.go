@@ -8,6 +8,8 @@ import (
 	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/kernel/network"
 	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 type BlockInterface ledger.BlockHandle
@@ -27,6 +29,12 @@ type LedgerRely interface {
 	QueryTipBlockHeader() ledger.BlockHandle
 }
 
+// TxRely 共识向链上提交交易使用的接口，如提交双签证据
+type TxRely interface {
+	// InvokeTx 以节点账户为发起者构造、签名并提交合约调用交易，返回交易id
+	InvokeTx(xctx.XContext, *protos.InvokeRequest) ([]byte, error)
+}
+
 // ConsensusCtx共识运行环境上下文
 type ConsensusCtx struct {
 	xctx.BaseCtx
@@ -36,4 +44,10 @@ type ConsensusCtx struct {
 	Contract contract.Manager
 	Ledger   LedgerRely
 	Network  network.Network
+	// Hasher 链配置的区块id哈希算法，共识消息摘要与之保持一致
+	Hasher *hash.Hasher
+	// DataDir 链数据目录，共识可在此存放需跨重启保留的本地状态
+	DataDir string
+	// Tx 提交共识交易，未设置时共识无法主动发起交易
+	Tx TxRely
 }
@@ -251,6 +251,13 @@ func (l *FakeLedger) GetTipSnapshot() (ledger.XMReader, error) {
 	return nil, nil
 }
 
+// FakeAward FakeLedger每个区块的奖励
+const FakeAward = 100
+
+func (l *FakeLedger) CalcAward(height int64) *big.Int {
+	return big.NewInt(FakeAward)
+}
+
 func (l *FakeLedger) SetConsensusStorage(height int, s []byte) {
 	if len(l.ledgerSlice)-1 < height {
 		return
@@ -361,6 +368,9 @@ func (c *FakeKContext) UTXORWSet() *contract.UTXORWSet {
 func (c *FakeKContext) Transfer(from string, to string, amount *big.Int) error {
 	return nil
 }
+func (c *FakeKContext) CrossQuery(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta) (*protos.ContractResponse, error) {
+	return &protos.ContractResponse{}, nil
+}
 func (c *FakeKContext) QueryBlock(blockid []byte) (*xldgpb.InternalBlock, error) {
 	return &xldgpb.InternalBlock{}, nil
 }
@@ -460,3 +470,19 @@ func NewP2P(node string) (p2p.Server, *nctx.NetCtx, error) {
 	p2pNode := p2pv2.NewP2PServerV2()
 	return p2pNode, ctx, nil
 }
+
+// FakeTxRely 记录共识提交的合约调用请求
+type FakeTxRely struct {
+	Requests chan *protos.InvokeRequest
+}
+
+func NewFakeTxRely() *FakeTxRely {
+	return &FakeTxRely{
+		Requests: make(chan *protos.InvokeRequest, 16),
+	}
+}
+
+func (t *FakeTxRely) InvokeTx(ctx xctx.XContext, req *protos.InvokeRequest) ([]byte, error) {
+	t.Requests <- req
+	return []byte(req.GetMethodName()), nil
+}
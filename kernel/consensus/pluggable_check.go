@@ -0,0 +1,147 @@
+package consensus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+const (
+	// contractCheckMethod 为共识升级提案的预检查方法，参数与updateConsensus一致，只读不写
+	// 提案在trigger高度才会执行updateConsensus，配置错误时链已无法切换共识，因此发起提案前应先通过预执行调用该方法
+	contractCheckMethod = "checkUpdateConsensus"
+
+	// 预检查的各个步骤
+	checkStepArgs    = "args"
+	checkStepPow     = "pow"
+	checkStepHeight  = "height"
+	checkStepVersion = "version"
+	checkStepBuild   = "build"
+)
+
+// CheckResult 单个检查步骤的结果，Logs为该步骤中共识实现输出的Warn及Error日志
+type CheckResult struct {
+	Step  string   `json:"step"`
+	Pass  bool     `json:"pass"`
+	Error string   `json:"error,omitempty"`
+	Logs  []string `json:"logs,omitempty"`
+}
+
+// UpdateDiagnosis 共识升级提案的预检查结果
+type UpdateDiagnosis struct {
+	Pass          bool           `json:"pass"`
+	ConsensusName string         `json:"name,omitempty"`
+	TriggerHeight int64          `json:"trigger_height,omitempty"`
+	StartHeight   int64          `json:"start_height,omitempty"`
+	TipHeight     int64          `json:"tip_height"`
+	Checks        []*CheckResult `json:"checks"`
+}
+
+func (d *UpdateDiagnosis) add(step string, err error, logs []string) bool {
+	r := &CheckResult{
+		Step: step,
+		Pass: err == nil,
+		Logs: logs,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	d.Checks = append(d.Checks, r)
+	return r.Pass
+}
+
+// checkUpdateConsensus 按updateConsensus的流程依次检查提案参数、生效高度、版本号并试构建新共识实例，
+// 返回每一步的检查结果，不写入合约存储，也不修改当前共识列表
+// Args: height::提案的trigger高度, args::{"name":共识名称, "config":共识配置}
+func (pc *PluggableConsensus) checkUpdateConsensus(contractCtx contract.KContext) (*contract.Response, error) {
+	d := pc.diagnoseUpdate(contractCtx)
+	body, err := json.Marshal(d)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	return common.NewContractOKResponse(body), nil
+}
+
+func (pc *PluggableConsensus) diagnoseUpdate(contractCtx contract.KContext) *UpdateDiagnosis {
+	d := &UpdateDiagnosis{
+		TipHeight: pc.ctx.Ledger.GetTipBlock().GetHeight(),
+	}
+	cfg, err := pc.proposalArgsUnmarshal(contractCtx.Args())
+	if !d.add(checkStepArgs, err, nil) {
+		return d
+	}
+	d.ConsensusName = cfg.ConsensusName
+	d.TriggerHeight = cfg.StartHeight - 1
+	d.StartHeight = cfg.StartHeight
+
+	pass := d.add(checkStepPow, pc.checkPowUpgrade(cfg), nil)
+
+	// 提案在trigger高度执行，trigger高度不高于当前高度时提案将不会再被触发
+	err = pc.checkConsensusHeight(cfg)
+	if err == nil && d.TriggerHeight <= d.TipHeight {
+		err = UpdateTriggerError
+	}
+	pass = d.add(checkStepHeight, err, nil) && pass
+
+	history, err := pc.loadConsensusHistory(contractCtx)
+	if err == nil {
+		err = checkConsensusVersion(history, cfg)
+	}
+	pass = d.add(checkStepVersion, err, nil) && pass
+
+	// 构建新共识实例使用的是当前链上状态，共识实现的构建过程仅读取账本，实例不会被Start
+	recorder := newRecordLogger(pc.ctx.XLog)
+	cCtx := pc.ctx
+	cCtx.XLog = recorder
+	item, err := NewPluginConsensus(cCtx, *cfg)
+	if err == nil && item == nil {
+		err = BuildConsensusError
+	}
+	pass = d.add(checkStepBuild, err, recorder.records()) && pass
+
+	d.Pass = pass
+	return d
+}
+
+// recordLogger 记录共识实现在构建过程中输出的Warn及Error日志，用于返回详细的检查结果
+type recordLogger struct {
+	logs.Logger
+	mutex sync.Mutex
+	lines []string
+}
+
+func newRecordLogger(l logs.Logger) *recordLogger {
+	return &recordLogger{
+		Logger: l,
+	}
+}
+
+func (l *recordLogger) Error(msg string, ctx ...interface{}) {
+	l.record("error", msg, ctx)
+	l.Logger.Error(msg, ctx...)
+}
+
+func (l *recordLogger) Warn(msg string, ctx ...interface{}) {
+	l.record("warn", msg, ctx)
+	l.Logger.Warn(msg, ctx...)
+}
+
+func (l *recordLogger) record(level, msg string, ctx []interface{}) {
+	line := fmt.Sprintf("[%s] %s", level, msg)
+	for i := 0; i+1 < len(ctx); i += 2 {
+		line += fmt.Sprintf(" %v=%v", ctx[i], ctx[i+1])
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.lines = append(l.lines, line)
+}
+
+func (l *recordLogger) records() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.lines
+}
@@ -65,6 +65,7 @@ func NewPluggableConsensus(cCtx cctx.ConsensusCtx) (PluggableConsensusInterface,
 	}
 	// 向合约注册升级方法
 	cCtx.Contract.GetKernRegistry().RegisterKernMethod(contractBucket, contractUpdateMethod, pc.updateConsensus)
+	cCtx.Contract.GetKernRegistry().RegisterKernMethod(contractBucket, contractCheckMethod, pc.checkUpdateConsensus)
 	xMReader, err := cCtx.Ledger.GetTipXMSnapshotReader()
 	if err != nil {
 		return nil, err
@@ -202,42 +203,17 @@ func (pc *PluggableConsensus) updateConsensus(contractCtx contract.KContext) (*c
 		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
 	}
 
-	// 不允许升级为 pow 类共识
-	if cfg.ConsensusName == "pow" {
-		pc.ctx.XLog.Warn("Pluggable Consensus::updateConsensus can not be pow")
-		return common.NewContractErrResponse(common.StatusErr,
-				"Pluggable Consensus::updateConsensus target can not be pow"),
-			errors.New("updateConsensus target can not be pow")
-	}
-
-	// 当前共识如果是pow类共识，不允许升级
-	if cur := pc.stepConsensus.tail(); cur != nil {
-		if curStatus, err := cur.GetConsensusStatus(); err != nil || curStatus.GetConsensusName() == "pow" {
-			pc.ctx.XLog.Warn("Pluggable Consensus::updateConsensus current consensus is pow, can not upgrade from pow", "err", err)
-			return common.NewContractErrResponse(common.StatusErr,
-					"Pluggable Consensus::updateConsensus current consensus is pow"),
-				errors.New("updateConsensus can not upgrade from pow")
-		}
+	// 不允许升级为 pow 类共识，当前共识如果是pow类共识，也不允许升级
+	if err := pc.checkPowUpgrade(cfg); err != nil {
+		pc.ctx.XLog.Warn("Pluggable Consensus::updateConsensus can not upgrade with pow", "err", err)
+		return common.NewContractErrResponse(common.StatusErr, "Pluggable Consensus::"+err.Error()), err
 	}
 
 	// 更新合约存储, 注意, 此次更新需要检查是否是初次升级情况，此时需要把genesisConf也写进map中
-	pluggableConfig, _ := contractCtx.Get(contractBucket, []byte(consensusKey))
-	c := map[int]def.ConsensusConfig{}
-	if pluggableConfig == nil {
-		// 尚未写入过任何值，此时需要先写入genesisConfig，即初始共识配置值, 此处不存在err情况
-		consensusBuf, _ := pc.ctx.Ledger.GetConsensusConf()
-		// 解析提取字段生成ConsensusConfig
-		config := def.ConsensusConfig{}
-		_ = json.Unmarshal(consensusBuf, &config)
-		config.StartHeight = 1
-		config.Index = 0
-		c[0] = config
-	} else {
-		err = json.Unmarshal(pluggableConfig, &c)
-		if err != nil {
-			pc.ctx.XLog.Warn("Pluggable Consensus::updateConsensus::unmarshal error", "error", err)
-			return common.NewContractErrResponse(common.StatusErr, BuildConsensusError.Error()), BuildConsensusError
-		}
+	c, err := pc.loadConsensusHistory(contractCtx)
+	if err != nil {
+		pc.ctx.XLog.Warn("Pluggable Consensus::updateConsensus::unmarshal error", "error", err)
+		return common.NewContractErrResponse(common.StatusErr, BuildConsensusError.Error()), BuildConsensusError
 	}
 
 	// 检查生效高度
@@ -282,6 +258,40 @@ func (pc *PluggableConsensus) updateConsensus(contractCtx contract.KContext) (*c
 	return common.NewContractOKResponse([]byte("ok")), nil
 }
 
+// checkPowUpgrade 不允许升级为pow类共识，当前共识如果是pow类共识，也不允许升级
+func (pc *PluggableConsensus) checkPowUpgrade(cfg *def.ConsensusConfig) error {
+	if cfg.ConsensusName == "pow" {
+		return errors.New("updateConsensus target can not be pow")
+	}
+	if cur := pc.stepConsensus.tail(); cur != nil {
+		if curStatus, err := cur.GetConsensusStatus(); err != nil || curStatus.GetConsensusName() == "pow" {
+			return errors.New("updateConsensus can not upgrade from pow")
+		}
+	}
+	return nil
+}
+
+// loadConsensusHistory 读取合约存储中的历史共识配置，尚未升级过时仅包含创世块中的初始共识配置
+func (pc *PluggableConsensus) loadConsensusHistory(contractCtx contract.KContext) (map[int]def.ConsensusConfig, error) {
+	pluggableConfig, _ := contractCtx.Get(contractBucket, []byte(consensusKey))
+	c := map[int]def.ConsensusConfig{}
+	if pluggableConfig == nil {
+		// 尚未写入过任何值，此时需要先写入genesisConfig，即初始共识配置值, 此处不存在err情况
+		consensusBuf, _ := pc.ctx.Ledger.GetConsensusConf()
+		// 解析提取字段生成ConsensusConfig
+		config := def.ConsensusConfig{}
+		_ = json.Unmarshal(consensusBuf, &config)
+		config.StartHeight = 1
+		config.Index = 0
+		c[0] = config
+		return c, nil
+	}
+	if err := json.Unmarshal(pluggableConfig, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // CheckConsensusConfig 同名配置文件检查:
 // 1. 同一个链的共识版本只能增加，不能升级到旧版本
 // 2. 将合法的配置写到map中
@@ -400,6 +410,15 @@ func (pc *PluggableConsensus) GetConsensusStatus() (ConsensusStatus, error) {
 	return con.GetConsensusStatus()
 }
 
+// GetAwardAddress 当前共识实例实现了AwardInterface时返回其指定的奖励接收地址
+func (pc *PluggableConsensus) GetAwardAddress(height int64) string {
+	con, _ := pc.getCurrentConsensusItem(height)
+	if a, ok := con.(AwardInterface); ok {
+		return a.GetAwardAddress(height)
+	}
+	return ""
+}
+
 // SwitchConsensus 用于共识升级时切换共识实例
 func (pc *PluggableConsensus) SwitchConsensus(height int64) error {
 	// 获取最新的共识实例
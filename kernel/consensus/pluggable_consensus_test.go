@@ -20,6 +20,7 @@ import (
 var (
 	_     = Register("fake", NewFakeConsensus)
 	_     = Register("another", NewAnotherConsensus)
+	_     = Register("broken", NewBrokenConsensus)
 	Miner = "xuper5"
 )
 
@@ -174,6 +175,12 @@ func (con *AnotherConsensusImp) Start() error {
 	return nil
 }
 
+// NewBrokenConsensus 模拟配置错误时共识实现输出错误日志并返回nil
+func NewBrokenConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) ConsensusImplInterface {
+	cCtx.XLog.Error("consensus:broken:NewBrokenConsensus: config error", "config", cCfg.Config)
+	return nil
+}
+
 func NewFakeLogger() logs.Logger {
 	confFile := utils.GetCurFileDir()
 	confFile = filepath.Join(confFile, "config/log.yaml")
@@ -302,6 +309,74 @@ func TestUpdateConsensus(t *testing.T) {
 	//}
 }
 
+func NewCheckArgs(name string, version string, height int64) map[string][]byte {
+	args := NewUpdateArgs()
+	a := make(map[string]interface{})
+	a["name"] = name
+	a["config"] = map[string]interface{}{
+		"version": version,
+	}
+	args["args"], _ = json.Marshal(&a)
+	args["height"] = []byte(strconv.FormatInt(height, 10))
+	return args
+}
+
+func TestCheckUpdateConsensus(t *testing.T) {
+	l := mock.NewFakeLedger(mock.GetGenesisConsensusConf())
+	ctx := GetConsensusCtx(l)
+	pc, _ := NewPluggableConsensus(ctx)
+	np, _ := pc.(*PluggableConsensus)
+	if _, ok := ctx.Contract.GetKernRegistry().(*mock.FakeRegistry).M[contractCheckMethod]; !ok {
+		t.Fatal("checkUpdateConsensus should be registered")
+	}
+	check := func(args map[string][]byte) *UpdateDiagnosis {
+		m := NewUpdateM()
+		resp, err := np.checkUpdateConsensus(mock.NewFakeKContext(args, m))
+		if err != nil {
+			t.Fatal("checkUpdateConsensus error", err)
+		}
+		if len(m) != 0 || np.stepConsensus.len() != 1 {
+			t.Fatal("checkUpdateConsensus should not write anything")
+		}
+		d := &UpdateDiagnosis{}
+		if err := json.Unmarshal(resp.Body, d); err != nil {
+			t.Fatal("unmarshal diagnosis error", err)
+		}
+		return d
+	}
+	failed := func(d *UpdateDiagnosis) []string {
+		var steps []string
+		for _, c := range d.Checks {
+			if !c.Pass {
+				steps = append(steps, c.Step)
+			}
+		}
+		return steps
+	}
+
+	if d := check(NewCheckArgs("another", "1", 30)); !d.Pass || d.StartHeight != 31 || len(d.Checks) != 5 {
+		t.Error("valid proposal should pass", "diagnosis", d)
+	}
+	d := check(map[string][]byte{"height": []byte("30"), "args": []byte("{")})
+	if d.Pass || len(d.Checks) != 1 || d.Checks[0].Step != checkStepArgs {
+		t.Error("malformed args should fail", "diagnosis", d)
+	}
+	if d := check(NewCheckArgs("unknown", "1", 30)); d.Pass || d.Checks[0].Error != ConsensusNotRegister.Error() {
+		t.Error("unregistered consensus should fail", "diagnosis", d)
+	}
+	if d := check(NewCheckArgs("another", "1", 8)); d.Pass || len(failed(d)) != 1 || failed(d)[0] != checkStepHeight {
+		t.Error("height too close to last consensus should fail", "diagnosis", d)
+	}
+	if d := check(NewCheckArgs("another", "0", 30)); d.Pass || len(failed(d)) != 1 || failed(d)[0] != checkStepVersion {
+		t.Error("non-increasing version should fail", "diagnosis", d)
+	}
+	d = check(NewCheckArgs("broken", "1", 30))
+	build := d.Checks[len(d.Checks)-1]
+	if d.Pass || build.Step != checkStepBuild || build.Pass || len(build.Logs) != 1 {
+		t.Error("broken consensus should fail with logs", "diagnosis", d)
+	}
+}
+
 func TestCompeteMaster(t *testing.T) {
 	// ledger的高度为2
 	l := mock.NewFakeLedger(mock.GetGenesisConsensusConf())
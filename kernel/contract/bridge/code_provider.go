@@ -99,7 +99,7 @@ func (c *codeProvider) GetContractCode(name string) ([]byte, error) {
 }
 
 func (c *codeProvider) GetContractAbi(name string) ([]byte, error) {
-	value, err := c.xstore.Get("contract", contractAbiKey(name))
+	value, err := c.xstore.Get("contract", ContractAbiKey(name))
 	if err != nil {
 		return nil, fmt.Errorf("get contract abi for '%s' error:%s", name, err)
 	}
@@ -141,7 +141,7 @@ func (c *codeProvider) GetContractCodeFromCache(name string) ([]byte, error) {
 }
 
 func (c *codeProvider) GetContractAbiFromCache(name string) ([]byte, error) {
-	value, err := c.xstore.GetUncommited("contract", contractAbiKey(name))
+	value, err := c.xstore.GetUncommited("contract", ContractAbiKey(name))
 	if err != nil {
 		return nil, fmt.Errorf("from cache get contract abi for '%s' error:%s", name, err)
 	}
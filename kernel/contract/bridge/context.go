@@ -7,6 +7,7 @@ import (
 
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/protos"
 )
 
@@ -59,6 +60,9 @@ type Context struct {
 	ReadFromCache bool
 
 	ChainName string
+
+	// 合约执行所在的区块
+	Block ledger.BlockHandle
 }
 
 // DiskUsed returns the bytes written to xmodel
@@ -7,11 +7,17 @@ import (
 
 	"github.com/xuperchain/crypto/core/hash"
 	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/protos"
 
 	"github.com/golang/protobuf/proto"
 )
 
+// execBlockGetter 由kernel合约上下文实现，返回合约执行所在的区块
+type execBlockGetter interface {
+	ExecBlock() ledger.BlockHandle
+}
+
 type contractManager struct {
 	xbridge      *XBridge
 	codeProvider ContractCodeProvider
@@ -64,7 +70,7 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 
 	if desc.ContractType == string(TypeEvm) {
 		abiBuf := args["contract_abi"]
-		if err := state.Put("contract", contractAbiKey(contractName), abiBuf); err != nil {
+		if err := state.Put("contract", ContractAbiKey(contractName), abiBuf); err != nil {
 			return nil, contract.Limits{}, err
 		}
 	}
@@ -101,6 +107,9 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 		ContractCodeFromCache: true,
 		// ChainName: c.xbridge.config.,
 	}
+	if g, ok := kctx.(execBlockGetter); ok {
+		initConfig.Block = g.ExecBlock()
+	}
 	initConfig.ContractName = contractName
 	initConfig.CanInitialize = true
 	initConfig.ContractCodeFromCache = true
@@ -202,7 +211,7 @@ func contractCodeKey(contractName string) []byte {
 	return []byte(contractName + "." + "code")
 }
 
-func contractAbiKey(contractName string) []byte {
+func ContractAbiKey(contractName string) []byte {
 	return []byte(contractName + "." + "abi")
 }
 
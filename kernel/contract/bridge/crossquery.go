@@ -0,0 +1,41 @@
+package bridge
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const (
+	// XuperScheme 跨链查询URI的scheme，格式为xuper://chain?module=&contract=&method=
+	XuperScheme = "xuper"
+)
+
+// CrossChainURI 跨链查询的目标合约
+type CrossChainURI struct {
+	ChainName    string
+	ModuleName   string
+	ContractName string
+	MethodName   string
+}
+
+// ParseCrossChainURI 解析跨链查询URI，module可以为空，由目标链根据合约名字查询
+func ParseCrossChainURI(uri string) (*CrossChainURI, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != XuperScheme {
+		return nil, fmt.Errorf("unsupported cross chain scheme:%s", u.Scheme)
+	}
+	query := u.Query()
+	crossChainURI := &CrossChainURI{
+		ChainName:    u.Host,
+		ModuleName:   query.Get("module"),
+		ContractName: query.Get("contract"),
+		MethodName:   query.Get("method"),
+	}
+	if crossChainURI.ChainName == "" || crossChainURI.ContractName == "" || crossChainURI.MethodName == "" {
+		return nil, fmt.Errorf("bad cross chain uri:%s", uri)
+	}
+	return crossChainURI, nil
+}
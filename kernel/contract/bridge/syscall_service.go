@@ -13,6 +13,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
 	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
 	"github.com/xuperchain/xupercore/protos"
 )
 
@@ -172,6 +173,7 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 		Caller:         nctx.ContractName,
 		ResourceLimits: *limits,
 		ContractSet:    nctx.ContractSet,
+		Block:          nctx.Block,
 	}
 	vctx, err := c.bridge.NewContext(cfg)
 	if err != nil {
@@ -198,39 +200,50 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 
 // CrossContractQuery implements Syscall interface
 func (c *SyscallService) CrossContractQuery(ctx context.Context, in *pb.CrossContractQueryRequest) (*pb.CrossContractQueryResponse, error) {
-	return nil, ErrNotImplementation
-	// nctx, ok := c.ctxmgr.Context(in.GetHeader().Ctxid)
-	// if !ok {
-	// 	return nil, fmt.Errorf("bad ctx id:%d", in.Header.Ctxid)
-	// }
-
-	// crossChainURI, err := ParseCrossChainURI(in.GetUri())
-	// if err != nil {
-	// 	return nil, fmt.Errorf("ParseCrossChainURI error, err:%s ctx id:%d", err.Error(), in.Header.Ctxid)
-	// }
-
-	// crossQueryMeta, err := nctx.Core.ResolveChain(crossChainURI.GetChainName())
-	// if err != nil {
-	// 	return nil, fmt.Errorf("ResolveChain error, err:%s ctx id:%d", err.Error(), in.Header.Ctxid)
-	// }
-
-	// // Assemble crossQueryRequest
-	// crossQueryRequest := &xchainpb.CrossQueryRequest{}
-	// crossScheme := GetChainScheme(crossChainURI.GetScheme())
-	// crossQueryRequest, err = crossScheme.GetCrossQueryRequest(crossChainURI, in.GetArgs(), nctx.GetInitiator(), nctx.GetAuthRequire())
-	// if err != nil {
-	// 	return nil, fmt.Errorf("GetCrossQueryRequest error, err:%s ctx id: %d", err.Error(), in.Header.Ctxid)
-	// }
-
-	// // CrossQuery cross query from other chain
-	// contractResponse, err := nctx.Store.CrossQuery(crossQueryRequest, crossQueryMeta)
-	// return &pb.CrossContractQueryResponse{
-	// 	Response: &pb.Response{
-	// 		Status:  contractResponse.GetStatus(),
-	// 		Message: contractResponse.GetMessage(),
-	// 		Body:    contractResponse.GetBody(),
-	// 	},
-	// }, err
+	nctx, ok := c.ctxmgr.Context(in.GetHeader().Ctxid)
+	if !ok {
+		return nil, fmt.Errorf("bad ctx id:%d", in.Header.Ctxid)
+	}
+
+	crossChainURI, err := ParseCrossChainURI(in.GetUri())
+	if err != nil {
+		return nil, fmt.Errorf("ParseCrossChainURI error, err:%s ctx id:%d", err.Error(), in.Header.Ctxid)
+	}
+
+	// 背书信息从本链的链上记录读取，验证交易时从读集重放，保证各节点结论一致
+	crossQueryMeta, err := sandbox.ResolveCrossQueryMeta(nctx.State, crossChainURI.ChainName)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveCrossQueryMeta error, err:%s ctx id:%d", err.Error(), in.Header.Ctxid)
+	}
+
+	args := make(map[string][]byte)
+	for _, arg := range in.GetArgs() {
+		args[arg.GetKey()] = arg.GetValue()
+	}
+	crossQueryRequest := &protos.CrossQueryRequest{
+		Bcname:      crossChainURI.ChainName,
+		Initiator:   nctx.Initiator,
+		AuthRequire: nctx.AuthRequire,
+		Request: &protos.InvokeRequest{
+			ModuleName:   crossChainURI.ModuleName,
+			ContractName: crossChainURI.ContractName,
+			MethodName:   crossChainURI.MethodName,
+			Args:         args,
+		},
+	}
+
+	// CrossQuery cross query from other chain
+	contractResponse, err := nctx.State.CrossQuery(crossQueryRequest, crossQueryMeta)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CrossContractQueryResponse{
+		Response: &pb.Response{
+			Status:  contractResponse.GetStatus(),
+			Message: contractResponse.GetMessage(),
+			Body:    contractResponse.GetBody(),
+		},
+	}, nil
 }
 
 // PutObject implements Syscall interface
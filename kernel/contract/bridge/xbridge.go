@@ -156,6 +156,7 @@ func (b *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context,
 		ctx.Logger, err = logs.NewLogger(fmt.Sprintf("%016d", ctx.ID), "contract")
 	}
 	ctx.ChainName = ctxCfg.ChainName
+	ctx.Block = ctxCfg.Block
 
 	if err != nil {
 		return nil, err
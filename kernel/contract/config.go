@@ -59,6 +59,9 @@ func (w *WasmConfig) IsEnable() bool {
 type EVMConfig struct {
 	Enable bool
 	Driver string
+	// ChainId 校验中继的以太坊交易使用的EIP-155链id，为0时不接受以太坊交易
+	// 链id决定交易是否有效，由创世配置的eth_chain_id设置，不从节点配置读取
+	ChainId int64 `yaml:"-"`
 }
 
 func (e *EVMConfig) DriverName() string {
@@ -1,5 +1,9 @@
 package contract
 
+import (
+	"github.com/xuperchain/xupercore/kernel/ledger"
+)
+
 const (
 	// StatusOK is used when contract successfully ends.
 	StatusOK = 200
@@ -56,4 +60,7 @@ type ContextConfig struct {
 	TxInBlock bool
 
 	ChainName string
+
+	// Block 合约执行所在的区块，验证区块时为被验证的区块，预执行和交易进入交易池时为待打包的下一个区块
+	Block ledger.BlockHandle
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/protos"
 )
 
@@ -45,6 +46,11 @@ func (k *kcontextImpl) AuthRequire() []string {
 	return k.ctx.AuthRequire
 }
 
+// ExecBlock 合约执行所在的区块
+func (k *kcontextImpl) ExecBlock() ledger.BlockHandle {
+	return k.ctx.Block
+}
+
 func (k *kcontextImpl) AddResourceUsed(delta contract.Limits) {
 	k.used.Add(delta)
 }
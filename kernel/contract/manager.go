@@ -28,6 +28,8 @@ type ManagerConfig struct {
 	EnvConf  *xconfig.EnvConf
 	Core     ChainCore
 	XMReader ledger.XMReader
+	// EthChainId 校验以太坊交易的EIP-155链id，来自创世配置
+	EthChainId int64
 
 	Config *ContractConfig // used by testing
 }
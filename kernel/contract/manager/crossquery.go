@@ -0,0 +1,135 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+var (
+	errCrossQueryChainName = errors.New("cross query chain name is empty")
+	errCrossQueryAdmin     = errors.New("initiator is not admin of cross query chain")
+	errCrossQueryGovern    = errors.New("cross query chain can only be registered by proposal")
+)
+
+// crossQueryChainArgs 治理提案trigger的参数，由$proposal以json形式放在args字段中传入
+type crossQueryChainArgs struct {
+	Name           string   `json:"name"`
+	Endorsors      []string `json:"endorsors"`
+	MinEndorsorNum int64    `json:"min_endorsor_num"`
+	Admin          []string `json:"admin"`
+}
+
+// setCrossQueryChain 登记或修改跨链查询目标链的背书节点和最少背书数
+// 新记录只能由治理提案（调用者为$proposal）登记，避免任意账户抢先占用链名；
+// 已存在的记录可以由治理提案或记录中的管理员修改
+func (m *managerImpl) setCrossQueryChain(ctx contract.KContext) (*contract.Response, error) {
+	byProposal := ctx.Caller() == utils.ProposalKernelContract
+	name, record, err := parseCrossQueryChainArgs(ctx.Args(), byProposal)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValue, err := ctx.Get(sandbox.CrossQueryKernelContract, name)
+	switch err {
+	case nil:
+		old := &sandbox.CrossQueryChain{}
+		if err := json.Unmarshal(oldValue, old); err != nil {
+			return nil, err
+		}
+		if !byProposal && !contains(old.Admin, ctx.Initiator()) {
+			return nil, errCrossQueryAdmin
+		}
+		if record.Admin == nil {
+			record.Admin = old.Admin
+		}
+	case sandbox.ErrNotFound, sandbox.ErrHasDel:
+		if !byProposal {
+			return nil, errCrossQueryGovern
+		}
+	default:
+		return nil, err
+	}
+	if err := record.Validate(); err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Put(sandbox.CrossQueryKernelContract, name, value); err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status: contract.StatusOK,
+		Body:   value,
+	}, nil
+}
+
+// getCrossQueryChain 查询目标链的背书信息
+func (m *managerImpl) getCrossQueryChain(ctx contract.KContext) (*contract.Response, error) {
+	name := ctx.Args()["name"]
+	if len(name) == 0 {
+		return nil, errCrossQueryChainName
+	}
+	value, err := ctx.Get(sandbox.CrossQueryKernelContract, name)
+	if err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status: contract.StatusOK,
+		Body:   value,
+	}, nil
+}
+
+// parseCrossQueryChainArgs 解析登记参数，治理提案的参数为json，管理员直接调用时参数逐项传入
+func parseCrossQueryChainArgs(args map[string][]byte, byProposal bool) ([]byte, *sandbox.CrossQueryChain, error) {
+	if byProposal {
+		proposalArgs := &crossQueryChainArgs{}
+		if err := json.Unmarshal(args["args"], proposalArgs); err != nil {
+			return nil, nil, err
+		}
+		if proposalArgs.Name == "" {
+			return nil, nil, errCrossQueryChainName
+		}
+		return []byte(proposalArgs.Name), &sandbox.CrossQueryChain{
+			Endorsors:      proposalArgs.Endorsors,
+			MinEndorsorNum: proposalArgs.MinEndorsorNum,
+			Admin:          proposalArgs.Admin,
+		}, nil
+	}
+
+	name := args["name"]
+	if len(name) == 0 {
+		return nil, nil, errCrossQueryChainName
+	}
+	record := &sandbox.CrossQueryChain{}
+	if err := json.Unmarshal(args["endorsors"], &record.Endorsors); err != nil {
+		return nil, nil, err
+	}
+	minEndorsorNum, err := strconv.ParseInt(string(args["min_endorsor_num"]), 10, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+	record.MinEndorsorNum = minEndorsorNum
+	if args["admin"] != nil {
+		if err := json.Unmarshal(args["admin"], &record.Admin); err != nil {
+			return nil, nil, err
+		}
+	}
+	return name, record, nil
+}
+
+func contains(items []string, item string) bool {
+	for _, v := range items {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
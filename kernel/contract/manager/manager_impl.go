@@ -48,6 +48,8 @@ func newManagerImpl(cfg *contract.ManagerConfig) (contract.Manager, error) {
 	m := &managerImpl{
 		core: cfg.Core,
 	}
+	evmConfig := xcfg.EVM
+	evmConfig.ChainId = cfg.EthChainId
 	var logDriver logs.Logger
 	if cfg.Config != nil {
 		logDriver = cfg.Config.LogDriver
@@ -57,7 +59,7 @@ func newManagerImpl(cfg *contract.ManagerConfig) (contract.Manager, error) {
 		VMConfigs: map[bridge.ContractType]bridge.VMConfig{
 			bridge.TypeWasm:   &xcfg.Wasm,
 			bridge.TypeNative: &xcfg.Native,
-			bridge.TypeEvm:    &xcfg.EVM,
+			bridge.TypeEvm:    &evmConfig,
 			bridge.TypeKernel: &contract.XkernelConfig{
 				Driver:   xcfg.Xkernel.Driver,
 				Enable:   xcfg.Xkernel.Enable,
@@ -78,6 +80,8 @@ func newManagerImpl(cfg *contract.ManagerConfig) (contract.Manager, error) {
 	registry.RegisterKernMethod("$contract", "upgradeContract", m.upgradeContract)
 	registry.RegisterShortcut("Deploy", "$contract", "deployContract")
 	registry.RegisterShortcut("Upgrade", "$contract", "upgradeContract")
+	registry.RegisterKernMethod(sandbox.CrossQueryKernelContract, "setChain", m.setCrossQueryChain)
+	registry.RegisterKernMethod(sandbox.CrossQueryKernelContract, "getChain", m.getCrossQueryChain)
 	return m, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract"
 	_ "github.com/xuperchain/xupercore/kernel/contract/kernel"
 	"github.com/xuperchain/xupercore/kernel/contract/mock"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
 	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
 )
 
@@ -67,3 +68,82 @@ func (h *helloContract) Hi(ctx contract.KContext) (*contract.Response, error) {
 		Body: []byte("hello " + string(name)),
 	}, nil
 }
+
+func TestCrossQueryChain(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+
+	// 模拟$proposal在trigger高度调用setChain，参数以json形式放在args中
+	th.Manager().GetKernRegistry().RegisterKernMethod(utils.ProposalKernelContract, "Trigger",
+		func(ctx contract.KContext) (*contract.Response, error) {
+			return ctx.Call("xkernel", sandbox.CrossQueryKernelContract, "setChain", ctx.Args())
+		})
+	proposeChain := func(proposalArgs string) error {
+		_, err := th.Invoke("xkernel", utils.ProposalKernelContract, "Trigger", map[string][]byte{
+			"args": []byte(proposalArgs),
+		})
+		return err
+	}
+	setChain := func(endorsors string, minEndorsorNum string, admin string) error {
+		args := map[string][]byte{
+			"name":             []byte("parachain"),
+			"endorsors":        []byte(endorsors),
+			"min_endorsor_num": []byte(minEndorsorNum),
+		}
+		if admin != "" {
+			args["admin"] = []byte(admin)
+		}
+		_, err := th.Invoke("xkernel", sandbox.CrossQueryKernelContract, "setChain", args)
+		return err
+	}
+	// 普通账户不能登记新的链名
+	if err := setChain(`["a"]`, "1", ""); err == nil {
+		t.Fatal("cross query chain should only be registered by proposal")
+	}
+	if err := proposeChain(`{"name":"parachain","endorsors":["a","b"],"min_endorsor_num":3}`); err == nil {
+		t.Error("min endorsor num larger than endorsors should be rejected")
+	}
+	if err := proposeChain(`{"name":"parachain","endorsors":["a","a"],"min_endorsor_num":1}`); err == nil {
+		t.Error("duplicated endorsors should be rejected")
+	}
+	// 未指定管理员时记录只能通过提案修改
+	if err := proposeChain(`{"name":"parachain","endorsors":["a"],"min_endorsor_num":1}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := setChain(`["c"]`, "1", ""); err == nil {
+		t.Error("non-admin should not edit cross query chain")
+	}
+	if err := proposeChain(`{"name":"parachain","endorsors":["a"],"min_endorsor_num":1,"admin":["` + mock.ContractAccount + `"]}`); err != nil {
+		t.Fatal(err)
+	}
+	// 管理员可以修改记录
+	if err := setChain(`["a","b"]`, "2", `["admin"]`); err != nil {
+		t.Fatal(err)
+	}
+	// 管理员转移后原发起者不能再修改
+	if err := setChain(`["c"]`, "1", ""); err == nil {
+		t.Error("non-admin should not edit cross query chain")
+	}
+
+	resp, err := th.Invoke("xkernel", sandbox.CrossQueryKernelContract, "getChain", map[string][]byte{
+		"name": []byte("parachain"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s", resp.Body)
+
+	state, err := th.Manager().NewStateSandbox(&contract.SandboxConfig{
+		XMReader: th.State(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := sandbox.ResolveCrossQueryMeta(state, "parachain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.GetMinEndorsorNum() != 2 || len(meta.GetEndorsors()) != 2 {
+		t.Errorf("unexpected cross query meta %v", meta)
+	}
+}
@@ -1,12 +1,36 @@
 package mock
 
 import (
+	"fmt"
+
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
 	"github.com/xuperchain/xupercore/kernel/ledger"
 )
 
+const (
+	// FakeLastBlockHeight 测试链最新区块的高度
+	FakeLastBlockHeight = 100
+	// FakeLastBlockTimestamp 测试链最新区块的时间戳，单位纳秒
+	FakeLastBlockTimestamp = 1600000000 * 1e9
+)
+
+// FakeBlockid 测试链指定高度的区块id
+func FakeBlockid(height int64) []byte {
+	return []byte(fmt.Sprintf("blockid_%d", height))
+}
+
+// FakeBlock 测试链指定高度的区块，高度超过最新区块时为待打包的区块
+func FakeBlock(height int64) ledger.BlockHandle {
+	return state.NewBlockAgent(&xldgpb.InternalBlock{
+		Blockid:   FakeBlockid(height),
+		PreHash:   FakeBlockid(height - 1),
+		Height:    height,
+		Timestamp: FakeLastBlockTimestamp - (FakeLastBlockHeight-height)*3*1e9,
+	})
+}
+
 type fakeChainCore struct {
 }
 
@@ -26,6 +50,10 @@ func (f *fakeChainCore) VerifyContractOwnerPermission(contractName string, authR
 }
 
 func (t *fakeChainCore) QueryBlock(blockid []byte) (ledger.BlockHandle, error) {
+	var height int64
+	if _, err := fmt.Sscanf(string(blockid), "blockid_%d", &height); err == nil {
+		return FakeBlock(height), nil
+	}
 	return state.NewBlockAgent(&xldgpb.InternalBlock{
 		Blockid: []byte("testblockid"),
 	}), nil
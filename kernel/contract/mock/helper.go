@@ -30,6 +30,7 @@ type TestHelper struct {
 	utxoReader sandbox.UtxoReader  // UTXO 读取器
 	state      *sandbox.MemXModel  // 沙盒内存状态 使用红黑树保存合约执行过程中的参数 中间变量等
 	manager    contract.Manager    // 合约管理器
+	block      ledger.BlockHandle  // 合约执行所在的区块
 }
 
 func NewTestHelper(cfg *contract.ContractConfig) *TestHelper {
@@ -60,6 +61,7 @@ func NewTestHelper(cfg *contract.ContractConfig) *TestHelper {
 		basedir: basedir,
 		manager: m,
 		state:   state,
+		block:   FakeBlock(FakeLastBlockHeight + 1),
 	}
 
 	// 初始化账户 使用的是UTXO类型账户 MIS暂不支持 需要后续实现
@@ -118,6 +120,7 @@ func (t *TestHelper) Deploy(module, lang, contractName string, bin []byte, args
 		ContractName:   "$contract",
 		State:          state,
 		ResourceLimits: contract.MaxLimits,
+		Block:          t.block,
 		Initiator:      ContractAccount,
 	})
 	if err != nil {
@@ -171,6 +174,7 @@ func (t *TestHelper) DeployWithABI(module, lang, contractName string, bin []byte
 		ContractName:   "$contract",
 		State:          state,
 		ResourceLimits: contract.MaxLimits,
+		Block:          t.block,
 		Initiator:      ContractAccount,
 	})
 	if err != nil {
@@ -223,6 +227,7 @@ func (t *TestHelper) Upgrade(contractName string, bin []byte) error {
 		ContractName:   "$contract",
 		State:          state,
 		ResourceLimits: contract.MaxLimits,
+		Block:          t.block,
 	})
 	if err != nil {
 		return err
@@ -252,6 +257,7 @@ func (t *TestHelper) Invoke(module, contractName, method string, args map[string
 		ContractName:   contractName,
 		State:          state,
 		ResourceLimits: contract.MaxLimits,
+		Block:          t.block,
 		Initiator:      ContractAccount,
 	})
 	if err != nil {
@@ -284,6 +284,75 @@ func (t *KernMethod) UnLockGovernTokens(ctx contract.KContext) (*contract.Respon
 	}, nil
 }
 
+// BurnGovernTokens 销毁账户指定场景下锁定的治理代币，用于共识对作恶节点的质押惩罚
+// 销毁数量超过锁定余额时仅销毁锁定部分
+func (t *KernMethod) BurnGovernTokens(ctx contract.KContext) (*contract.Response, error) {
+	// 调用权限校验
+	if ctx.Caller() != utils.TDPOSKernelContract && ctx.Caller() != utils.XPOSKernelContract {
+		return nil, fmt.Errorf("caller %s no authority to BurnGovernTokens", ctx.Caller())
+	}
+	args := ctx.Args()
+	accountBuf := args["from"]
+	amountBuf := args["amount"]
+	lockTypeBuf := args["lock_type"]
+	if accountBuf == nil || amountBuf == nil || lockTypeBuf == nil {
+		return nil, fmt.Errorf("burn gov tokens failed, account, amount or lock_type is nil")
+	}
+	lockType := string(lockTypeBuf)
+	if lockType != utils.GovernTokenTypeOrdinary && lockType != utils.GovernTokenTypeTDPOS {
+		return nil, fmt.Errorf("burn gov tokens failed, lock_type invalid: %s", lockType)
+	}
+	amountBurn := big.NewInt(0)
+	_, isAmount := amountBurn.SetString(string(amountBuf), 10)
+	if !isAmount || amountBurn.Cmp(big.NewInt(0)) < 0 {
+		return nil, fmt.Errorf("burn gov tokens failed, parse amount error")
+	}
+
+	// 查询account余额
+	accountBalance, err := t.balanceOf(ctx, string(accountBuf))
+	if err != nil {
+		return nil, fmt.Errorf("burn gov tokens failed, query account balance error")
+	}
+	if accountBalance.LockedBalance[lockType].Cmp(amountBurn) < 0 {
+		amountBurn.Set(accountBalance.LockedBalance[lockType])
+	}
+	accountBalance.LockedBalance[lockType].Sub(accountBalance.LockedBalance[lockType], amountBurn)
+	accountBalance.TotalBalance.Sub(accountBalance.TotalBalance, amountBurn)
+
+	// 更新account余额
+	accountBalanceBuf, _ := json.Marshal(accountBalance)
+	accountKey := utils.MakeAccountBalanceKey(string(accountBuf))
+	err = ctx.Put(utils.GetGovernTokenBucket(), []byte(accountKey), accountBalanceBuf)
+	if err != nil {
+		return nil, fmt.Errorf("burn gov tokens failed, update account's balance")
+	}
+
+	// 更新总额
+	totalSupplyKey := utils.MakeTotalSupplyKey()
+	totalSupplyBuf, err := ctx.Get(utils.GetGovernTokenBucket(), []byte(totalSupplyKey))
+	if err != nil {
+		return nil, fmt.Errorf("burn gov tokens failed, query total supply error")
+	}
+	totalSupply := big.NewInt(0)
+	totalSupply.SetString(string(totalSupplyBuf), 10)
+	totalSupply.Sub(totalSupply, amountBurn)
+	err = ctx.Put(utils.GetGovernTokenBucket(), []byte(totalSupplyKey), []byte(totalSupply.String()))
+	if err != nil {
+		return nil, fmt.Errorf("burn gov tokens failed, update total supply error")
+	}
+
+	delta := contract.Limits{
+		XFee: t.NewGovResourceAmount / 1000,
+	}
+	ctx.AddResourceUsed(delta)
+
+	return &contract.Response{
+		Status:  utils.StatusOK,
+		Message: "success",
+		Body:    []byte(amountBurn.String()),
+	}, nil
+}
+
 func (t *KernMethod) QueryAccountGovernTokens(ctx contract.KContext) (*contract.Response, error) {
 	args := ctx.Args()
 	accountBuf := args["account"]
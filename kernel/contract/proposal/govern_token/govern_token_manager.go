@@ -34,6 +34,7 @@ func NewGovManager(ctx *GovCtx) (GovManager, error) {
 	register.RegisterKernMethod(utils.GovernTokenKernelContract, "Transfer", t.TransferGovernTokens)
 	register.RegisterKernMethod(utils.GovernTokenKernelContract, "Lock", t.LockGovernTokens)
 	register.RegisterKernMethod(utils.GovernTokenKernelContract, "UnLock", t.UnLockGovernTokens)
+	register.RegisterKernMethod(utils.GovernTokenKernelContract, "Burn", t.BurnGovernTokens)
 	register.RegisterKernMethod(utils.GovernTokenKernelContract, "Query", t.QueryAccountGovernTokens)
 	register.RegisterKernMethod(utils.GovernTokenKernelContract, "TotalSupply", t.TotalSupply)
 
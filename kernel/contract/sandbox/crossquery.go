@@ -0,0 +1,200 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	// ErrCrossQueryNotSupported is returned when sandbox has no cross query reader
+	ErrCrossQueryNotSupported = errors.New("cross query not supported")
+	// ErrCrossQueryMismatch is returned when cross query request mismatch with the one in tx
+	ErrCrossQueryMismatch = errors.New("cross query request mismatch")
+	// ErrCrossQueryNotEnough is returned when cross query infos in tx is used up
+	ErrCrossQueryNotEnough = errors.New("cross query not enough in cross query cache")
+	// ErrEndorsementNotEnough is returned when valid endorsements less than required
+	ErrEndorsementNotEnough = errors.New("cross query endorsement not enough")
+	// ErrInvalidEndorsement is returned when endorsement sign is invalid
+	ErrInvalidEndorsement = errors.New("invalid cross query endorsement")
+	// ErrInvalidCrossQueryChain is returned when endorsement record of cross query chain is invalid
+	ErrInvalidCrossQueryChain = errors.New("invalid cross query chain record")
+)
+
+const (
+	// CrossQueryKernelContract 记录跨链查询目标链背书信息的系统合约，记录以目标链名为key存在同名bucket中
+	CrossQueryKernelContract = "$crossQuery"
+)
+
+// CrossQueryChain 链上记录的跨链查询目标链背书信息，记录由治理提案创建，Admin和治理提案可以修改该记录
+type CrossQueryChain struct {
+	Endorsors      []string `json:"endorsors"`
+	MinEndorsorNum int64    `json:"min_endorsor_num"`
+	Admin          []string `json:"admin"`
+}
+
+// Validate 背书节点不能重复，最少背书数在[1, 背书节点个数]内；Admin可以为空，此时记录只能通过治理提案修改
+func (c *CrossQueryChain) Validate() error {
+	endorsors := make(map[string]bool, len(c.Endorsors))
+	for _, addr := range c.Endorsors {
+		if addr == "" || endorsors[addr] {
+			return ErrInvalidCrossQueryChain
+		}
+		endorsors[addr] = true
+	}
+	if c.MinEndorsorNum < 1 || c.MinEndorsorNum > int64(len(c.Endorsors)) {
+		return ErrInvalidCrossQueryChain
+	}
+	return nil
+}
+
+// ResolveCrossQueryMeta 从合约状态中读取目标链的背书信息
+// 读取的记录进入交易读集，背书信息因此固定为交易所在区块高度上的链上记录，
+// 验证节点从交易读集重放得到同一记录，与节点本地运行了哪些链、目标链当前的验证节点无关
+func ResolveCrossQueryMeta(state contract.XMState, chainName string) (*protos.CrossQueryMeta, error) {
+	value, err := state.Get(CrossQueryKernelContract, []byte(chainName))
+	if err != nil {
+		return nil, fmt.Errorf("cross query chain %s not registered: %v", chainName, err)
+	}
+	record := &CrossQueryChain{}
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, ErrInvalidCrossQueryChain
+	}
+	if err := record.Validate(); err != nil {
+		return nil, err
+	}
+	return &protos.CrossQueryMeta{
+		Bcname:         chainName,
+		MinEndorsorNum: record.MinEndorsorNum,
+		Endorsors:      record.Endorsors,
+	}, nil
+}
+
+// CrossQueryCache 缓存合约执行过程中的跨链查询，按调用顺序生成跨链查询读写集
+type CrossQueryCache struct {
+	reader contract.CrossQueryReader
+	infos  []*protos.CrossQueryInfo
+}
+
+// NewCrossQueryCache new an instance of CrossQueryCache
+func NewCrossQueryCache(reader contract.CrossQueryReader) *CrossQueryCache {
+	return &CrossQueryCache{
+		reader: reader,
+	}
+}
+
+// CrossQuery 执行跨链查询并记录查询信息
+func (c *CrossQueryCache) CrossQuery(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta) (*protos.ContractResponse, error) {
+	if c.reader == nil {
+		return nil, ErrCrossQueryNotSupported
+	}
+	info, err := c.reader.CrossQuery(req, meta)
+	if err != nil {
+		return nil, err
+	}
+	if !proto.Equal(info.GetRequest(), req) {
+		return nil, ErrCrossQueryMismatch
+	}
+	c.infos = append(c.infos, info)
+	return info.GetResponse().GetResponse(), nil
+}
+
+// GetCrossQueryRWSets get cross query infos in calling order
+func (c *CrossQueryCache) GetCrossQueryRWSets() []*protos.CrossQueryInfo {
+	return c.infos
+}
+
+// CrossQueryReader 从交易记录的跨链查询信息中按顺序读取查询结果，用于验证交易时重放跨链查询
+type CrossQueryReader struct {
+	crypto   cryptoBase.CryptoClient
+	infos    []*protos.CrossQueryInfo
+	queryIdx int
+}
+
+// NewCrossQueryReaderFromInfos new cross query reader from cross query infos of tx
+func NewCrossQueryReaderFromInfos(infos []*protos.CrossQueryInfo, crypto cryptoBase.CryptoClient) contract.CrossQueryReader {
+	return &CrossQueryReader{
+		crypto: crypto,
+		infos:  infos,
+	}
+}
+
+// CrossQuery 返回下一条记录的跨链查询信息，并校验请求和背书签名
+func (r *CrossQueryReader) CrossQuery(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta) (*protos.CrossQueryInfo, error) {
+	if r.queryIdx >= len(r.infos) {
+		return nil, ErrCrossQueryNotEnough
+	}
+	info := r.infos[r.queryIdx]
+	// Since contract calls bridge serially, a mismatched request is an error
+	if !proto.Equal(info.GetRequest(), req) {
+		return nil, ErrCrossQueryMismatch
+	}
+	err := VerifyCrossQueryEndorsements(r.crypto, info, meta)
+	if err != nil {
+		return nil, err
+	}
+	r.queryIdx++
+	return info, nil
+}
+
+// MakeCrossQueryDigest 计算跨链查询请求和结果的摘要，背书节点对该摘要签名
+func MakeCrossQueryDigest(req *protos.CrossQueryRequest, resp *protos.CrossQueryResponse) ([]byte, error) {
+	buf := proto.NewBuffer(nil)
+	// 请求参数是map，需要保证序列化结果确定
+	buf.SetDeterministic(true)
+	err := buf.Marshal(&protos.CrossQueryInfo{
+		Request:  req,
+		Response: resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hash.DoubleSha256(buf.Bytes()), nil
+}
+
+// VerifyCrossQueryEndorsements 校验跨链查询的背书签名，
+// 来自目标链背书节点的有效签名个数不能少于要求的最少背书数
+func VerifyCrossQueryEndorsements(crypto cryptoBase.CryptoClient, info *protos.CrossQueryInfo, meta *protos.CrossQueryMeta) error {
+	if crypto == nil || meta == nil || meta.GetBcname() != info.GetRequest().GetBcname() || meta.GetMinEndorsorNum() < 1 {
+		return ErrInvalidEndorsement
+	}
+	digest, err := MakeCrossQueryDigest(info.GetRequest(), info.GetResponse())
+	if err != nil {
+		return err
+	}
+
+	endorsors := make(map[string]bool, len(meta.GetEndorsors()))
+	for _, addr := range meta.GetEndorsors() {
+		endorsors[addr] = true
+	}
+	signed := make(map[string]bool)
+	for _, sign := range info.GetSigns() {
+		pubKey, err := crypto.GetEcdsaPublicKeyFromJsonStr(sign.GetPublicKey())
+		if err != nil {
+			return ErrInvalidEndorsement
+		}
+		addr, err := crypto.GetAddressFromPublicKey(pubKey)
+		if err != nil {
+			return ErrInvalidEndorsement
+		}
+		if !endorsors[addr] || signed[addr] {
+			continue
+		}
+		valid, err := crypto.VerifyECDSA(pubKey, sign.GetSign(), digest)
+		if err != nil || !valid {
+			return ErrInvalidEndorsement
+		}
+		signed[addr] = true
+	}
+	if int64(len(signed)) < meta.GetMinEndorsorNum() {
+		return ErrEndorsementNotEnough
+	}
+	return nil
+}
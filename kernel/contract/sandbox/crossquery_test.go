@@ -0,0 +1,260 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	endorsorAddress = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
+	endorsorPubKey  = `{"Curvname":"P-256","X":36505150171354363400464126431978257855318414556425194490762274938603757905292,"Y":79656876957602994269528255245092635964473154458596947290316223079846501380076}`
+	endorsorPriKey  = `{"Curvname":"P-256","X":36505150171354363400464126431978257855318414556425194490762274938603757905292,"Y":79656876957602994269528255245092635964473154458596947290316223079846501380076,"D":111497060296999106528800133634901141644446751975433315540300236500052690483486}`
+)
+
+type endorseCrossQueryReader struct {
+	crypto cryptoBase.CryptoClient
+	body   []byte
+}
+
+func (r *endorseCrossQueryReader) CrossQuery(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta) (*protos.CrossQueryInfo, error) {
+	info := &protos.CrossQueryInfo{
+		Request: req,
+		Response: &protos.CrossQueryResponse{
+			Response: &protos.ContractResponse{
+				Status: 200,
+				Body:   r.body,
+			},
+		},
+	}
+	digest, err := MakeCrossQueryDigest(info.Request, info.Response)
+	if err != nil {
+		return nil, err
+	}
+	priKey, err := r.crypto.GetEcdsaPrivateKeyFromJsonStr(endorsorPriKey)
+	if err != nil {
+		return nil, err
+	}
+	sign, err := r.crypto.SignECDSA(priKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	info.Signs = []*protos.SignatureInfo{{PublicKey: endorsorPubKey, Sign: sign}}
+	return info, nil
+}
+
+func newCrossQueryRequest(key string) *protos.CrossQueryRequest {
+	return &protos.CrossQueryRequest{
+		Bcname:    "parachain",
+		Initiator: endorsorAddress,
+		Request: &protos.InvokeRequest{
+			ModuleName:   "wasm",
+			ContractName: "counter",
+			MethodName:   "get",
+			Args: map[string][]byte{
+				"key":   []byte(key),
+				"extra": []byte("value"),
+			},
+		},
+	}
+}
+
+func TestCrossQuery(t *testing.T) {
+	crypto, err := client.CreateCryptoClient(client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &protos.CrossQueryMeta{
+		Bcname:         "parachain",
+		MinEndorsorNum: 1,
+		Endorsors:      []string{endorsorAddress},
+	}
+
+	// 预执行时查询目标链并记录查询信息
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: NewMemXModel(),
+		CrossQueryReader: &endorseCrossQueryReader{
+			crypto: crypto,
+			body:   []byte("10"),
+		},
+	})
+	resp, err := mc.CrossQuery(newCrossQueryRequest("xuper"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.GetBody()) != "10" {
+		t.Fatalf("unexpected response body %s", resp.GetBody())
+	}
+	if err := mc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := txFromWSet(mc)
+	infos, err := ParseCrossQuery(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("expect 1 cross query info, got %d", len(infos))
+	}
+
+	// 验证时使用交易中记录的查询信息重放
+	vc := NewXModelCache(&contract.SandboxConfig{
+		XMReader:         NewMemXModel(),
+		CrossQueryReader: NewCrossQueryReaderFromInfos(infos, crypto),
+	})
+	if _, err := vc.CrossQuery(newCrossQueryRequest("other"), meta); err != ErrCrossQueryMismatch {
+		t.Fatalf("expect ErrCrossQueryMismatch, got %v", err)
+	}
+	resp, err = vc.CrossQuery(newCrossQueryRequest("xuper"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.GetBody()) != "10" {
+		t.Fatalf("unexpected response body %s", resp.GetBody())
+	}
+	if _, err := vc.CrossQuery(newCrossQueryRequest("xuper"), meta); err != ErrCrossQueryNotEnough {
+		t.Fatalf("expect ErrCrossQueryNotEnough, got %v", err)
+	}
+	if err := vc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(tx, txFromWSet(vc)) {
+		t.Fatal("cross query write set mismatch")
+	}
+
+	// 篡改查询结果后背书校验失败
+	tampered := proto.Clone(infos[0]).(*protos.CrossQueryInfo)
+	tampered.Response.Response.Body = []byte("20")
+	reader := NewCrossQueryReaderFromInfos([]*protos.CrossQueryInfo{tampered}, crypto)
+	if _, err := reader.CrossQuery(newCrossQueryRequest("xuper"), meta); err != ErrInvalidEndorsement {
+		t.Fatalf("expect ErrInvalidEndorsement, got %v", err)
+	}
+
+	// 签名节点不是目标链的背书节点
+	otherMeta := &protos.CrossQueryMeta{
+		Bcname:         "parachain",
+		MinEndorsorNum: 1,
+		Endorsors:      []string{"otheraddress"},
+	}
+	reader = NewCrossQueryReaderFromInfos(infos, crypto)
+	if _, err := reader.CrossQuery(newCrossQueryRequest("xuper"), otherMeta); err != ErrEndorsementNotEnough {
+		t.Fatalf("expect ErrEndorsementNotEnough, got %v", err)
+	}
+
+	// 没有跨链查询能力的沙盒
+	nc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: NewMemXModel(),
+	})
+	if _, err := nc.CrossQuery(newCrossQueryRequest("xuper"), meta); err != ErrCrossQueryNotSupported {
+		t.Fatalf("expect ErrCrossQueryNotSupported, got %v", err)
+	}
+}
+
+func TestCrossQueryMetaOnChain(t *testing.T) {
+	crypto, err := client.CreateCryptoClient(client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	putRecord := func(state *MemXModel, record *CrossQueryChain) {
+		value, _ := json.Marshal(record)
+		state.Put(CrossQueryKernelContract, []byte("parachain"), &ledger.VersionedData{
+			RefTxid:  []byte("record"),
+			PureData: &ledger.PureData{Bucket: CrossQueryKernelContract, Key: []byte("parachain"), Value: value},
+		})
+	}
+
+	// 预执行节点从链上记录解析背书信息，记录进入交易读集
+	state := NewMemXModel()
+	putRecord(state, &CrossQueryChain{Endorsors: []string{endorsorAddress}, MinEndorsorNum: 1, Admin: []string{endorsorAddress}})
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: state,
+		CrossQueryReader: &endorseCrossQueryReader{
+			crypto: crypto,
+			body:   []byte("10"),
+		},
+	})
+	meta, err := ResolveCrossQueryMeta(mc, "parachain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.CrossQuery(newCrossQueryRequest("xuper"), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	rwSet := mc.RWSet()
+	infos, err := ParseCrossQuery(txFromWSet(mc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 验证节点从交易读集重放，结论只取决于链上记录
+	verify := func(rwSet *contract.RWSet) error {
+		vc := NewXModelCache(&contract.SandboxConfig{
+			XMReader:         XMReaderFromRWSet(rwSet),
+			CrossQueryReader: NewCrossQueryReaderFromInfos(infos, crypto),
+		})
+		meta, err := ResolveCrossQueryMeta(vc, "parachain")
+		if err != nil {
+			return err
+		}
+		_, err = vc.CrossQuery(newCrossQueryRequest("xuper"), meta)
+		return err
+	}
+	// 两个本地视图不同的节点: 一个的最新状态中没有该记录，一个已经看到记录被修改为要求更多背书，
+	// 验证同一交易时都使用交易读集中的记录，结论相同
+	laggingNode := NewMemXModel()
+	advancedNode := NewMemXModel()
+	putRecord(advancedNode, &CrossQueryChain{Endorsors: []string{endorsorAddress, "otheraddress"}, MinEndorsorNum: 2,
+		Admin: []string{endorsorAddress}})
+	for name, local := range map[string]*MemXModel{"lagging": laggingNode, "advanced": advancedNode} {
+		localMeta, _ := ResolveCrossQueryMeta(NewXModelCache(&contract.SandboxConfig{XMReader: local}), "parachain")
+		if proto.Equal(localMeta, meta) {
+			t.Fatalf("node %s should have a different local view", name)
+		}
+		if err := verify(rwSet); err != nil {
+			t.Fatalf("node %s: verify cross query error: %v", name, err)
+		}
+	}
+
+	// 读集中的记录要求更多背书时，所有节点都拒绝
+	sc := NewXModelCache(&contract.SandboxConfig{XMReader: advancedNode})
+	if _, err := ResolveCrossQueryMeta(sc, "parachain"); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(sc.RWSet()); err != ErrEndorsementNotEnough {
+		t.Fatalf("expect ErrEndorsementNotEnough, got %v", err)
+	}
+
+	// 未登记的目标链和非法记录
+	if _, err := ResolveCrossQueryMeta(NewXModelCache(&contract.SandboxConfig{XMReader: NewMemXModel()}), "parachain"); err == nil {
+		t.Fatal("unregistered chain should be rejected")
+	}
+	invalid := NewMemXModel()
+	putRecord(invalid, &CrossQueryChain{Endorsors: []string{endorsorAddress}, MinEndorsorNum: 0, Admin: []string{endorsorAddress}})
+	if _, err := ResolveCrossQueryMeta(NewXModelCache(&contract.SandboxConfig{XMReader: invalid}), "parachain"); err != ErrInvalidCrossQueryChain {
+		t.Fatalf("expect ErrInvalidCrossQueryChain, got %v", err)
+	}
+}
+
+func txFromWSet(mc *XMCache) *lpb.Transaction {
+	tx := &lpb.Transaction{}
+	for _, w := range mc.RWSet().WSet {
+		tx.TxOutputsExt = append(tx.TxOutputsExt, &protos.TxOutputExt{
+			Bucket: w.GetBucket(),
+			Key:    w.GetKey(),
+			Value:  w.GetValue(),
+		})
+	}
+	return tx
+}
@@ -48,20 +48,19 @@ type XMCache struct {
 
 	model ledger.XMReader
 
-	utxoSandbox *utxo.UTXOSandbox
-	// crossQueryCache *CrossQueryCache
-	events []*protos.ContractEvent
+	utxoSandbox     *utxo.UTXOSandbox
+	crossQueryCache *CrossQueryCache
+	events          []*protos.ContractEvent
 }
 
 // NewXModelCache new an instance of XModel Cache
 func NewXModelCache(cfg *contract.SandboxConfig) *XMCache {
 	return &XMCache{
-		model:        cfg.XMReader,
-		inputsCache:  NewMemXModel(),
-		outputsCache: NewMemXModel(),
-		utxoSandbox:  utxo.NewUTXOSandbox(cfg),
-
-		// crossQueryCache: NewCrossQueryCache(),
+		model:           cfg.XMReader,
+		inputsCache:     NewMemXModel(),
+		outputsCache:    NewMemXModel(),
+		utxoSandbox:     utxo.NewUTXOSandbox(cfg),
+		crossQueryCache: NewCrossQueryCache(cfg.CrossQueryReader),
 	}
 }
 
@@ -378,61 +377,44 @@ func (xc *XMCache) flushUTXORWSet() error {
 //}
 
 // CrossQuery will query contract from other chain
-//func (xc *XMCache) CrossQuery(crossQueryRequest *pb.CrossQueryRequest, queryMeta *pb.CrossQueryMeta) (*pb.ContractResponse, error) {
-//	return xc.crossQueryCache.CrossQuery(crossQueryRequest, queryMeta)
-//}
+func (xc *XMCache) CrossQuery(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta) (*protos.ContractResponse, error) {
+	return xc.crossQueryCache.CrossQuery(req, meta)
+}
 
 // ParseCrossQuery parse cross query from tx
-//func ParseCrossQuery(tx *pb.Transaction) ([]*pb.CrossQueryInfo, error) {
-//	var (
-//		crossQueryInfos []*pb.CrossQueryInfo
-//		queryInfos      []byte
-//	)
-//	for _, out := range tx.GetTxOutputsExt() {
-//		if out.GetBucket() != TransientBucket {
-//			continue
-//		}
-//		if bytes.Equal(out.GetKey(), crossQueryInfosKey) {
-//			queryInfos = out.GetValue()
-//		}
-//	}
-//	if queryInfos != nil {
-//		err := UnmsarshalMessages(queryInfos, &crossQueryInfos)
-//		if err != nil {
-//			return nil, err
-//		}
-//	}
-//	return crossQueryInfos, nil
-//}
-
-// IsCrossQueryEffective check if crossQueryInfos effective
-// TODO: zq
-//func IsCrossQueryEffective(cqi []*pb.CrossQueryInfo, tx *pb.Transaction) bool {
-//	return true
-//}
+func ParseCrossQuery(tx *lpb.Transaction) ([]*protos.CrossQueryInfo, error) {
+	var crossQueryInfos []*protos.CrossQueryInfo
+	for _, out := range tx.GetTxOutputsExt() {
+		if out.GetBucket() != TransientBucket {
+			continue
+		}
+		if !bytes.Equal(out.GetKey(), crossQueryInfosKey) {
+			continue
+		}
+		err := xmodel.UnmsarshalMessages(out.GetValue(), &crossQueryInfos)
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+	return crossQueryInfos, nil
+}
 
-// PutCrossQueries put queryInfos to db
-//func (xc *XMCache) putCrossQueries(queryInfos []*pb.CrossQueryInfo) error {
-//	var qi []byte
-//	var err error
-//	if len(queryInfos) != 0 {
-//		qi, err = MarshalMessages(queryInfos)
-//		if err != nil {
-//			return err
-//		}
-//	}
-//	if qi != nil {
-//		err = xc.Put(TransientBucket, crossQueryInfosKey, qi)
-//		if err != nil {
-//			return err
-//		}
-//	}
-//	return nil
-//}
+// putCrossQueries put queryInfos to TransientBucket
+func (xc *XMCache) putCrossQueries(queryInfos []*protos.CrossQueryInfo) error {
+	if len(queryInfos) == 0 {
+		return nil
+	}
+	qi, err := xmodel.MarshalMessages(queryInfos)
+	if err != nil {
+		return err
+	}
+	return xc.Put(TransientBucket, crossQueryInfosKey, qi)
+}
 
-//func (xc *XMCache) writeCrossQueriesRWSet() error {
-//	return xc.putCrossQueries(xc.crossQueryCache.GetCrossQueryRWSets())
-//}
+func (xc *XMCache) writeCrossQueriesRWSet() error {
+	return xc.putCrossQueries(xc.crossQueryCache.GetCrossQueryRWSets())
+}
 
 // ParseContractEvents parse contract events from tx
 func ParseContractEvents(tx *lpb.Transaction) ([]*protos.ContractEvent, error) {
@@ -479,10 +461,10 @@ func (xc *XMCache) Flush() error {
 		return err
 	}
 
-	// err = xc.writeCrossQueriesRWSet()
-	// if err != nil {
-	// 	return err
-	// }
+	err = xc.writeCrossQueriesRWSet()
+	if err != nil {
+		return err
+	}
 
 	err = xc.writeEventRWSet()
 	if err != nil {
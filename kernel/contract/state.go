@@ -7,13 +7,19 @@ import (
 )
 
 type SandboxConfig struct {
-	XMReader   ledger.XMReader
-	UTXOReader UtxoReader
+	XMReader         ledger.XMReader
+	UTXOReader       UtxoReader
+	CrossQueryReader CrossQueryReader
 }
 type UtxoReader interface {
 	SelectUtxo(string, *big.Int, bool, bool) ([]*protos.TxInput, [][]byte, *big.Int, error)
 }
 
+// CrossQueryReader 执行跨链查询，返回带有背书签名的查询信息
+type CrossQueryReader interface {
+	CrossQuery(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta) (*protos.CrossQueryInfo, error)
+}
+
 // Iterator iterates over key/value pairs in key order
 type Iterator interface {
 	Key() []byte
@@ -41,6 +47,7 @@ type UTXOState interface {
 
 // CrossQueryState 对XuperBridge暴露对跨链只读合约的操作能力
 type CrossQueryState interface {
+	CrossQuery(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta) (*protos.ContractResponse, error)
 }
 
 type ContractEventState interface {
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/timer"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	ErrNotEndorsor       = errors.New("node is not endorsor of cross query chain")
+	ErrCrossQueryNoReply = errors.New("cross query got no response")
+)
+
+// CrossQueryAgent 执行跨链查询并收集目标链背书节点的背书，
+// 本节点是背书节点时先在本地查询背书，背书不足时向其他背书节点请求
+type CrossQueryAgent struct {
+	log      logs.Logger
+	chainCtx *common.ChainCtx
+}
+
+func NewCrossQueryAgent(chainCtx *common.ChainCtx) *CrossQueryAgent {
+	return &CrossQueryAgent{
+		log:      chainCtx.GetLog(),
+		chainCtx: chainCtx,
+	}
+}
+
+// CrossQuery 执行跨链查询，返回有效背书数不少于链上记录的最少背书数的查询信息
+func (t *CrossQueryAgent) CrossQuery(req *protos.CrossQueryRequest,
+	meta *protos.CrossQueryMeta) (*protos.CrossQueryInfo, error) {
+	var infos []*protos.CrossQueryInfo
+	if addr := t.chainCtx.Address; addr != nil && isEndorsor(meta, addr.Address) {
+		info, err := t.Endorse(req)
+		if err != nil {
+			t.log.Warn("cross query endorse failed", "bcname", req.GetBcname(), "err", err)
+		} else {
+			infos = append(infos, info)
+		}
+	}
+	if int64(len(infos)) < meta.GetMinEndorsorNum() {
+		infos = append(infos, t.requestEndorsements(req, meta)...)
+	}
+	return t.mergeEndorsements(req, meta, infos)
+}
+
+// Endorse 在本节点运行的目标链上预执行跨链查询，并使用节点账户对查询结果背书
+func (t *CrossQueryAgent) Endorse(req *protos.CrossQueryRequest) (*protos.CrossQueryInfo, error) {
+	addr := t.chainCtx.Address
+	if addr == nil {
+		return nil, ErrNotEndorsor
+	}
+	chain, err := t.chainCtx.EngCtx.ChainM.Get(req.GetBcname())
+	if err != nil {
+		t.log.Warn("cross query chain not exist", "bcname", req.GetBcname(), "err", err)
+		return nil, err
+	}
+	ctx := &xctx.BaseCtx{
+		XLog:  t.log,
+		Timer: timer.NewXTimer(),
+	}
+	resp, err := chain.PreExec(ctx, []*protos.InvokeRequest{req.GetRequest()},
+		req.GetInitiator(), req.GetAuthRequire())
+	if err != nil {
+		t.log.Warn("cross query pre exec failed", "bcname", req.GetBcname(), "err", err)
+		return nil, err
+	}
+	// 目标链预执行时会在请求前添加保留合约请求，查询结果为最后一个
+	responses := resp.GetResponses()
+	if len(responses) == 0 {
+		return nil, ErrCrossQueryNoReply
+	}
+
+	info := &protos.CrossQueryInfo{
+		Request: req,
+		Response: &protos.CrossQueryResponse{
+			Response: responses[len(responses)-1],
+		},
+	}
+	digest, err := sandbox.MakeCrossQueryDigest(info.GetRequest(), info.GetResponse())
+	if err != nil {
+		return nil, err
+	}
+	sign, err := t.chainCtx.Crypto.SignECDSA(addr.PrivateKey, digest)
+	if err != nil {
+		t.log.Warn("cross query sign failed", "err", err)
+		return nil, err
+	}
+	info.Signs = []*protos.SignatureInfo{
+		{
+			PublicKey: addr.PublicKeyStr,
+			Sign:      sign,
+		},
+	}
+	return info, nil
+}
+
+// requestEndorsements 向除本节点外的背书节点请求背书
+func (t *CrossQueryAgent) requestEndorsements(req *protos.CrossQueryRequest,
+	meta *protos.CrossQueryMeta) []*protos.CrossQueryInfo {
+	var others []string
+	for _, endorsor := range meta.GetEndorsors() {
+		if t.chainCtx.Address == nil || endorsor != t.chainCtx.Address.Address {
+			others = append(others, endorsor)
+		}
+	}
+	if t.chainCtx.EngCtx.Net == nil || len(others) == 0 {
+		return nil
+	}
+	ctx := &xctx.BaseCtx{
+		XLog:  t.log,
+		Timer: timer.NewXTimer(),
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_CROSS_QUERY, req, p2p.WithBCName(req.GetBcname()))
+	responses, err := t.chainCtx.EngCtx.Net.SendMessageWithResponse(ctx, msg, p2p.WithAccounts(others))
+	if err != nil {
+		t.log.Warn("cross query request endorsements failed", "bcname", req.GetBcname(), "err", err)
+	}
+	var infos []*protos.CrossQueryInfo
+	for _, resp := range responses {
+		if resp.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			continue
+		}
+		info := &protos.CrossQueryInfo{}
+		if err := p2p.Unmarshal(resp, info); err != nil {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// mergeEndorsements 按查询结果合并背书，只合并单独校验有效的背书节点签名，
+// 返回第一个背书满足链上记录要求的查询结果
+func (t *CrossQueryAgent) mergeEndorsements(req *protos.CrossQueryRequest, meta *protos.CrossQueryMeta,
+	infos []*protos.CrossQueryInfo) (*protos.CrossQueryInfo, error) {
+	single := &protos.CrossQueryMeta{
+		Bcname:         meta.GetBcname(),
+		MinEndorsorNum: 1,
+		Endorsors:      meta.GetEndorsors(),
+	}
+	merged := make(map[string]*protos.CrossQueryInfo)
+	for _, info := range infos {
+		if !proto.Equal(info.GetRequest(), req) || len(info.GetSigns()) != 1 ||
+			sandbox.VerifyCrossQueryEndorsements(t.chainCtx.Crypto, info, single) != nil {
+			continue
+		}
+		digest, err := sandbox.MakeCrossQueryDigest(info.GetRequest(), info.GetResponse())
+		if err != nil {
+			continue
+		}
+		m, ok := merged[string(digest)]
+		if !ok {
+			m = &protos.CrossQueryInfo{
+				Request:  info.GetRequest(),
+				Response: info.GetResponse(),
+			}
+			merged[string(digest)] = m
+		}
+		m.Signs = append(m.Signs, info.GetSigns()...)
+		if sandbox.VerifyCrossQueryEndorsements(t.chainCtx.Crypto, m, meta) == nil {
+			return m, nil
+		}
+	}
+	return nil, sandbox.ErrEndorsementNotEnough
+}
+
+func isEndorsor(meta *protos.CrossQueryMeta, address string) bool {
+	for _, endorsor := range meta.GetEndorsors() {
+		if endorsor == address {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package agent
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 
@@ -44,6 +45,11 @@ func (t *LedgerAgent) GetGenesisPreDistribution() ([]ledger.Predistribution, err
 	return preDistribution, nil
 }
 
+// 从创世块计算对应高度的区块奖励
+func (t *LedgerAgent) CalcAward(height int64) *big.Int {
+	return t.chainCtx.Ledger.GenesisBlock.CalcAward(height)
+}
+
 // 从创世块获取加密算法类型
 func (t *LedgerAgent) GetCryptoType() (string, error) {
 	cryptoType := t.chainCtx.Ledger.GenesisBlock.GetConfig().GetCryptoType()
@@ -88,7 +94,7 @@ func (t *LedgerAgent) QueryBlock(blkId []byte) (kledger.BlockHandle, error) {
 		return nil, err
 	}
 
-	return state.NewBlockAgent(block), nil
+	return state.NewBlockAgentWithHasher(block, t.chainCtx.Ledger.GetHasher()), nil
 }
 
 func (t *LedgerAgent) QueryBlockByHeight(height int64) (kledger.BlockHandle, error) {
@@ -97,7 +103,7 @@ func (t *LedgerAgent) QueryBlockByHeight(height int64) (kledger.BlockHandle, err
 		return nil, err
 	}
 
-	return state.NewBlockAgent(block), nil
+	return state.NewBlockAgentWithHasher(block, t.chainCtx.Ledger.GetHasher()), nil
 }
 
 func (t *LedgerAgent) QueryTipBlockHeight() (int64, error) {
@@ -122,7 +128,7 @@ func (t *LedgerAgent) QueryBlockHeader(blkId []byte) (kledger.BlockHandle, error
 		return nil, err
 	}
 
-	return state.NewBlockAgent(block), nil
+	return state.NewBlockAgentWithHasher(block, t.chainCtx.Ledger.GetHasher()), nil
 }
 
 func (t *LedgerAgent) QueryBlockHeaderByHeight(height int64) (kledger.BlockHandle, error) {
@@ -131,7 +137,7 @@ func (t *LedgerAgent) QueryBlockHeaderByHeight(height int64) (kledger.BlockHandl
 		return nil, err
 	}
 
-	return state.NewBlockAgent(block), nil
+	return state.NewBlockAgentWithHasher(block, t.chainCtx.Ledger.GetHasher()), nil
 }
 
 func (t *LedgerAgent) GetTipBlock() kledger.BlockHandle {
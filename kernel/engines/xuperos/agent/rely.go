@@ -133,11 +133,12 @@ func (t *ChainRelyAgentImpl) CreateContract(xmreader kledger.XMReader) (contract
 	basedir := filepath.Join(envcfg.GenDataAbsPath(envcfg.ChainDir), ctx.BCName)
 
 	mgCfg := &contract.ManagerConfig{
-		BCName:   ctx.BCName,
-		Basedir:  basedir,
-		EnvConf:  envcfg,
-		Core:     NewChainCoreAgent(ctx),
-		XMReader: xmreader,
+		BCName:     ctx.BCName,
+		Basedir:    basedir,
+		EnvConf:    envcfg,
+		Core:       NewChainCoreAgent(ctx),
+		XMReader:   xmreader,
+		EthChainId: ctx.Ledger.GenesisBlock.GetConfig().EthChainId,
 	}
 	contractObj, err := contract.CreateManager("default", mgCfg)
 	if err != nil {
@@ -151,6 +152,7 @@ func (t *ChainRelyAgentImpl) CreateContract(xmreader kledger.XMReader) (contract
 func (t *ChainRelyAgentImpl) CreateConsensus() (consensus.PluggableConsensusInterface, error) {
 	ctx := t.chain.Context()
 	legAgent := NewLedgerAgent(ctx)
+	envcfg := ctx.EngCtx.EnvCfg
 	consCtx := cctx.ConsensusCtx{
 		BcName:   ctx.BCName,
 		Address:  (*cctx.Address)(ctx.Address),
@@ -158,6 +160,9 @@ func (t *ChainRelyAgentImpl) CreateConsensus() (consensus.PluggableConsensusInte
 		Contract: ctx.Contract,
 		Ledger:   legAgent,
 		Network:  ctx.EngCtx.Net,
+		Hasher:   ctx.Ledger.GetHasher(),
+		DataDir:  filepath.Join(envcfg.GenDataAbsPath(envcfg.ChainDir), ctx.BCName),
+		Tx:       NewTxAgent(t.chain),
 	}
 
 	log, err := logs.NewLogger("", cdef.SubModName)
@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// 交易手续费的转出地址
+	feePlaceholder = "$"
+	// 共识交易的描述
+	consensusTxDesc = "consensus"
+)
+
+// TxAgent 代理共识发起的交易，以节点账户为发起者构造、签名后提交到本地交易池并广播
+type TxAgent struct {
+	chain common.Chain
+}
+
+func NewTxAgent(chain common.Chain) *TxAgent {
+	return &TxAgent{chain: chain}
+}
+
+// InvokeTx 预执行合约调用后构造交易，需要手续费的链由节点账户的utxo支付
+func (t *TxAgent) InvokeTx(ctx xctx.XContext, req *protos.InvokeRequest) ([]byte, error) {
+	chainCtx := t.chain.Context()
+	if chainCtx == nil || chainCtx.Address == nil {
+		return nil, common.ErrParameter
+	}
+	node := chainCtx.Address
+	authRequire := []string{node.Address}
+	res, err := t.chain.PreExec(ctx, []*protos.InvokeRequest{req}, node.Address, authRequire)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &lpb.Transaction{
+		Version:          1,
+		Desc:             []byte(consensusTxDesc),
+		Nonce:            utils.GenNonce(),
+		Timestamp:        time.Now().UnixNano(),
+		Initiator:        node.Address,
+		AuthRequire:      authRequire,
+		TxInputsExt:      res.GetInputs(),
+		TxOutputsExt:     res.GetOutputs(),
+		ContractRequests: res.GetRequests(),
+	}
+	if !chainCtx.Ledger.GetNoFee() {
+		if err := t.fillFee(tx, res.GetGasUsed()); err != nil {
+			return nil, err
+		}
+	}
+	tx.TxInputs = append(tx.TxInputs, res.GetUtxoInputs()...)
+	tx.TxOutputs = append(tx.TxOutputs, res.GetUtxoOutputs()...)
+
+	// 签名和生成txid
+	hasher := chainCtx.Ledger.GetHasher()
+	sign, err := txhash.ProcessSignTxWithHasher(chainCtx.Crypto, tx, []byte(node.PrivateKeyStr), hasher)
+	if err != nil {
+		return nil, err
+	}
+	signInfo := &protos.SignatureInfo{
+		PublicKey: node.PublicKeyStr,
+		Sign:      sign,
+	}
+	tx.InitiatorSigns = []*protos.SignatureInfo{signInfo}
+	tx.AuthRequireSigns = []*protos.SignatureInfo{signInfo}
+	tx.Txid, err = txhash.MakeTransactionIDWithHasher(tx, hasher)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.chain.SubmitTx(ctx, tx); err != nil {
+		return nil, err
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_POSTTX, tx, p2p.WithBCName(chainCtx.BCName))
+	go chainCtx.EngCtx.Net.SendMessage(ctx, msg)
+	return tx.GetTxid(), nil
+}
+
+// fillFee 使用节点账户的utxo支付手续费，
+// 需要手续费的链上交易不能没有utxo输入，手续费为0时也至少选择一个utxo并全部找零
+func (t *TxAgent) fillFee(tx *lpb.Transaction, fee int64) error {
+	need := big.NewInt(fee)
+	if need.Sign() == 0 {
+		need = big.NewInt(1)
+	}
+	inputs, _, total, err := t.chain.Context().State.SelectUtxos(tx.GetInitiator(), need, true, false)
+	if err != nil {
+		return fmt.Errorf("select utxo failed.err:%v", err)
+	}
+	tx.TxInputs = append(tx.TxInputs, inputs...)
+	if fee > 0 {
+		tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{
+			ToAddr: []byte(feePlaceholder),
+			Amount: big.NewInt(fee).Bytes(),
+		})
+	}
+	// 多出来的utxo再转给自己
+	delta := new(big.Int).Sub(total, big.NewInt(fee))
+	if delta.Sign() > 0 {
+		tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{
+			ToAddr: []byte(tx.GetInitiator()),
+			Amount: delta.Bytes(),
+		})
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/miner"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/parachain"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/statesync"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/lib/timer"
@@ -40,6 +41,8 @@ type Chain struct {
 	log logs.Logger
 	// 矿工
 	miner *miner.Miner
+	// 状态快照
+	snapshotter *statesync.Snapshotter
 	// 依赖代理组件
 	relyAgent common.ChainRelyAgent
 
@@ -77,6 +80,14 @@ func LoadChain(engCtx *common.EngineCtx, bcName string) (*Chain, error) {
 		return nil, common.ErrNewChainCtxFailed.More("err:%v", err)
 	}
 
+	// 创建状态快照管理
+	chainObj.snapshotter, err = statesync.NewSnapshotter(ctx)
+	if err != nil {
+		log.Error("create state snapshotter failed", "bcName", bcName, "err", err)
+		return nil, common.ErrNewChainCtxFailed.More("err:%v", err)
+	}
+	ctx.StateSnapshot = chainObj.snapshotter
+
 	// 创建矿工
 	chainObj.miner = miner.NewMiner(ctx)
 	chainObj.txIdCache = cache.New(TxIdCacheExpired, TxIdCacheGCInterval)
@@ -96,6 +107,8 @@ func (t *Chain) SetRelyAgent(agent common.ChainRelyAgent) error {
 
 // 阻塞
 func (t *Chain) Start() {
+	// 启动账本裁剪，仍被状态机引用的交易保留详情
+	t.ctx.Ledger.StartPrune(t.ctx.State.IsTxReferenced)
 	// 启动矿工
 	t.miner.Start()
 }
@@ -103,10 +116,12 @@ func (t *Chain) Start() {
 func (t *Chain) Stop() {
 	// 停止矿工等其余组件
 	t.miner.Stop()
+	t.snapshotter.Stop()
 	t.ctx.Ledger.Close()
 	t.ctx.State.Close()
 	t.ctx = nil
 	t.miner = nil
+	t.snapshotter = nil
 	t.txIdCache = nil
 }
 
@@ -137,8 +152,9 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 	}
 
 	stateConfig := &contract.SandboxConfig{
-		XMReader:   t.ctx.State.CreateXMReader(),
-		UTXOReader: t.ctx.State.CreateUtxoReader(),
+		XMReader:         t.ctx.State.CreateXMReader(),
+		UTXOReader:       t.ctx.State.CreateUtxoReader(),
+		CrossQueryReader: agent.NewCrossQueryAgent(t.ctx),
 	}
 	sandbox, err := t.ctx.Contract.NewStateSandbox(stateConfig)
 	if err != nil {
@@ -146,12 +162,19 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 		return nil, common.ErrContractNewSandboxFailed
 	}
 
+	// 预执行的合约运行在待打包的下一个区块中
+	block, err := t.ctx.State.PendingBlock()
+	if err != nil {
+		t.log.Error("PreExec query pending block error", "error", err)
+		return nil, common.ErrContractNewCtxFailed
+	}
 	contextConfig := &contract.ContextConfig{
 		State:          sandbox,
 		Initiator:      initiator,
 		AuthRequire:    authRequires,
 		ResourceLimits: contract.MaxLimits,
 		ChainName:      t.ctx.BCName,
+		Block:          block,
 	}
 
 	gasPrice := t.ctx.State.GetMeta().GetGasPrice()
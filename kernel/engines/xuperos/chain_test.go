@@ -10,6 +10,7 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/agent"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/lib/utils"
 	"github.com/xuperchain/xupercore/protos"
@@ -223,3 +224,43 @@ func TestChain_PreExec(t *testing.T) {
 		return
 	}
 }
+
+func TestTxAgent_InvokeTx(t *testing.T) {
+	engine, err := MockEngine("p2pv2/node1/conf/env.yaml")
+	if err != nil {
+		t.Logf("%v", err)
+		return
+	}
+
+	chain, err := engine.Get("xuper")
+	if err != nil {
+		t.Errorf("get chain error: %v", err)
+		return
+	}
+
+	req := &protos.InvokeRequest{
+		ModuleName:   "xkernel",
+		ContractName: "$acl",
+		MethodName:   "NewAccount",
+		Args: map[string][]byte{
+			"account_name": []byte("1234567890123457"),
+			"acl":          []byte(`{"pm": {"rule": 1,"acceptValue": 1.0},"aksWeight": {"TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY": 1}}`),
+		},
+	}
+	txid, err := agent.NewTxAgent(chain).InvokeTx(chain.Context(), req)
+	if err != nil {
+		t.Errorf("invoke tx error: %v", err)
+		return
+	}
+
+	// 交易以节点账户为发起者进入未确认交易表
+	tx, _, err := chain.Context().State.QueryTx(txid)
+	if err != nil {
+		t.Errorf("query tx error: %v", err)
+		return
+	}
+	if tx.GetInitiator() != chain.Context().Address.Address || len(tx.GetContractRequests()) != 1 ||
+		tx.GetContractRequests()[0].GetMethodName() != req.MethodName {
+		t.Errorf("invoke tx error: %v", tx)
+	}
+}
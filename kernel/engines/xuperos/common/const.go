@@ -25,3 +25,9 @@ const (
 	// SyncWithFactorBucket 从每个bucket中抽取节点同步区块
 	SyncWithFactorBucket
 )
+
+// 区块同步
+const (
+	// MaxSyncBlockIdsSize 单次GET_BLOCKIDS请求最多返回的blockid个数
+	MaxSyncBlockIdsSize = 1000
+)
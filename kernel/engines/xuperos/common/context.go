@@ -62,4 +62,6 @@ type ChainCtx struct {
 	Address *xaddress.Address
 	// 异步任务
 	Asyncworker AsyncworkerAgent
+	// 状态快照
+	StateSnapshot StateSnapshotAgent
 }
@@ -87,6 +87,7 @@ var (
 	ErrBlockNotExist    = &Error{ErrStatusInternalErr, 50300, "block not exist"}
 	ErrProcBlockFailed  = &Error{ErrStatusInternalErr, 50301, "process block failed"}
 	ErrGenesisBlockDiff = &Error{ErrStatusInternalErr, 50302, "genesis block diff"}
+	ErrBlockPruned      = &Error{ErrStatusInternalErr, 50303, "block txs pruned"}
 
 	// tx
 	ErrTxVerifyFailed        = &Error{ErrStatusInternalErr, 50400, "verify tx failed"}
@@ -95,6 +96,7 @@ var (
 	ErrTxNotEnough           = &Error{ErrStatusInternalErr, 50403, "tx not enough"}
 	ErrSubmitTxFailed        = &Error{ErrStatusInternalErr, 50404, "submit tx failed"}
 	ErrGenerateTimerTxFailed = &Error{ErrStatusInternalErr, 50405, "generate timer tx failed"}
+	ErrTxPruned              = &Error{ErrStatusInternalErr, 50406, "tx pruned"}
 
 	// contract
 	ErrContractNewCtxFailed     = &Error{ErrStatusInternalErr, 50500, "contract new context failed"}
@@ -109,4 +111,8 @@ var (
 
 	// consensus
 	ErrConsensusStatus = &Error{ErrStatusInternalErr, 50701, "consensus status error"}
+
+	// state snapshot
+	ErrSnapshotNotExist  = &Error{ErrStatusInternalErr, 50800, "state snapshot not exist"}
+	ErrSnapshotCorrupted = &Error{ErrStatusInternalErr, 50801, "state snapshot corrupted"}
 )
@@ -12,6 +12,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract/proposal/propose"
 	timerTask "github.com/xuperchain/xupercore/kernel/contract/proposal/timer"
 	"github.com/xuperchain/xupercore/kernel/engines"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xtoken/base"
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/kernel/network"
@@ -84,3 +85,13 @@ type TaskContext interface {
 	ParseArgs(v interface{}) error
 	RetryTimes() int
 }
+
+// 避免循环调用
+type StateSnapshotAgent interface {
+	// 获取最新的可供同步的状态快照
+	GetLatestSnapshot() (*xpb.StateSnapshot, error)
+	// 获取状态快照的序列化分片数据
+	GetSnapshotChunk(blockid []byte, index int64) ([]byte, error)
+	// 状态机确认区块后调用，到达导出周期时导出状态快照
+	OnConfirmBlock(block *lpb.InternalBlock)
+}
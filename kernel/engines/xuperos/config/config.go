@@ -31,6 +31,21 @@ type EngineConf struct {
 	SyncBlockFilterMode int `yaml:"syncBlockFilterMode,omitempty"`
 	// SyncFactorForFactorBucketMode only use for SyncWithFactorBucket mode of SyncBlockFilterMode configuration item
 	SyncFactorForFactorBucketMode float64 `yaml:"SyncFactorForFactorBucketMode,omitempty"`
+	// StateSnapshotInterval 每隔多少个区块导出一次状态快照，0表示不导出快照
+	StateSnapshotInterval int64 `yaml:"stateSnapshotInterval,omitempty"`
+	// StateSnapshotChunkSize 状态快照单个分片的数据大小上限(byte)
+	StateSnapshotChunkSize int `yaml:"stateSnapshotChunkSize,omitempty"`
+	// StateSnapshotRetain 本地保留的状态快照个数
+	StateSnapshotRetain int `yaml:"stateSnapshotRetain,omitempty"`
+	// EnableFastSync 新节点启动时是否先从邻居节点下载状态快照，再从快照高度开始同步区块
+	EnableFastSync bool `yaml:"enableFastSync,omitempty"`
+	// FastSyncTrustedValidators 校验快照签名的可信验证人，为空时使用创世配置中的验证人，
+	// 快照需要超过半数的可信验证人签名
+	FastSyncTrustedValidators []string `yaml:"fastSyncTrustedValidators,omitempty"`
+	// EnableParallelSync 同步区块时先下载并校验blockid列表，再从多个节点并行下载区块
+	EnableParallelSync bool `yaml:"enableParallelSync,omitempty"`
+	// ParallelSyncWorkers 并行下载区块时同时进行的请求数
+	ParallelSyncWorkers int `yaml:"parallelSyncWorkers,omitempty"`
 }
 
 func LoadEngineConf(cfgFile string) (*EngineConf, error) {
@@ -52,6 +67,12 @@ func GetDefEngineConf() *EngineConf {
 		MaxBlockQueueSize:             100,
 		SyncBlockFilterMode:           0,
 		SyncFactorForFactorBucketMode: 0.5,
+		StateSnapshotInterval:         0,
+		StateSnapshotChunkSize:        4 * 1024 * 1024,
+		StateSnapshotRetain:           2,
+		EnableFastSync:                false,
+		EnableParallelSync:            false,
+		ParallelSyncWorkers:           4,
 	}
 }
 
@@ -58,6 +58,10 @@ func NewBlockStore(ledger *ledger.Ledger, state *state.State) BlockStore {
 	}
 }
 
+func (b *blockStore) QueryBlockByHeight(height int64) (*pb.InternalBlock, error) {
+	return b.Ledger.QueryBlockByHeight(height)
+}
+
 func (b *blockStore) TipBlockHeight() (int64, error) {
 	tipBlockid := b.Ledger.GetMeta().GetTipBlockid()
 	block, err := b.Ledger.QueryBlockHeader(tipBlockid)
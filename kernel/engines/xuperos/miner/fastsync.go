@@ -0,0 +1,331 @@
+package miner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/golang/protobuf/proto" //nolint:staticcheck
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/statesync"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// 下载单个快照分片的最大重试次数
+	maxChunkRetryTimes = 3
+)
+
+var (
+	ErrNoStateSnapshot          = errors.New("no state snapshot found")
+	ErrSnapshotImportUnfinished = errors.New("state snapshot import unfinished, restart with fast sync or clear data")
+)
+
+// exportStateSnapshot 状态机确认区块后，到达导出周期时导出状态快照
+func (m *Miner) exportStateSnapshot(block *lpb.InternalBlock) {
+	if m.ctx.StateSnapshot != nil {
+		m.ctx.StateSnapshot.OnConfirmBlock(block)
+	}
+}
+
+// fastSync 账本中只有创世块时，从邻居节点下载已进入不可逆区间的状态快照导入状态机，
+// 之后从快照高度开始正常同步区块，避免从创世块开始逐个执行区块
+// 快照需要多数可信验证人对快照区块和摘要签名，导入时重新计算摘要校验，邻居节点无法伪造状态数据
+// 注意：快照只包含状态机数据和快照区块头，以下数据不会同步：
+//  1. 快照区块之前的区块，本地无法查询这些区块和其中的交易(快照引用的交易除外)，也无法向其他节点提供
+//  2. 共识在本地维护的数据，例如chained-bft的投票记录，共识从快照区块开始重新积累，
+//     需要读取快照区块之前历史区块的共识逻辑无法执行
+func (m *Miner) fastSync(ctx xctx.XContext) error {
+	// 上次快速同步在账本确认快照区块后中断，只需要结束导入
+	importBlockid, importing := m.ctx.State.GetSnapshotImport()
+	if importing && bytes.Equal(importBlockid, m.ctx.Ledger.GetMeta().GetTipBlockid()) {
+		ctx.GetLog().Info("finish unfinished state snapshot import", "blockid", utils.F(importBlockid))
+		return m.ctx.State.FinishSnapshotImport()
+	}
+	if !m.ctx.EngCtx.EngCfg.EnableFastSync || m.ctx.Ledger.GetMeta().GetTrunkHeight() > 0 {
+		return nil
+	}
+
+	snapshots, err := m.getStateSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	block := snapshots[0].snapshot.GetBlock()
+	ctx.GetLog().Info("fast sync with state snapshot", "peers", len(snapshots), "height", block.GetHeight(),
+		"blockid", utils.F(block.GetBlockid()), "digest", utils.F(snapshots[0].snapshot.GetDigest()))
+
+	// 依次从提供该快照的节点导入，失败时状态机会在下一次导入时重新清空
+	for _, ps := range snapshots {
+		if m.IsExit() {
+			return errors.New("miner exit")
+		}
+		reader := &snapshotChunkReader{
+			miner:    m,
+			ctx:      ctx,
+			peer:     ps.peer,
+			snapshot: ps.snapshot,
+			digest:   statesync.NewStateDigest(block.GetBlockid()),
+		}
+		err = m.ctx.State.ImportSnapshot(block.GetBlockid(), reader)
+		if err == nil {
+			break
+		}
+		ctx.GetLog().Warn("import state snapshot failed", "peer", ps.peer, "err", err)
+		if reader.invalid {
+			m.ctx.EngCtx.Net.ReportPeer(ps.peer, p2p.PeerEventInvalidMessage)
+		}
+	}
+	if err != nil {
+		ctx.GetLog().Error("import state snapshot failed from all peers", "err", err)
+		return err
+	}
+	err = m.ctx.Ledger.ConfirmSnapshotBlock(block)
+	if err != nil {
+		ctx.GetLog().Error("confirm snapshot block failed", "err", err)
+		return err
+	}
+	err = m.ctx.State.FinishSnapshotImport()
+	if err != nil {
+		return err
+	}
+	ctx.GetLog().Info("fast sync finish", "height", block.GetHeight(), "blockid", utils.F(block.GetBlockid()))
+	return nil
+}
+
+// peerSnapshot 邻居节点提供的状态快照，各节点的分片大小可能不同，分片只能从提供该快照的节点下载
+type peerSnapshot struct {
+	peer     string
+	snapshot *xpb.StateSnapshot
+}
+
+// getStateSnapshot 向邻居节点查询状态快照，返回最高的有多数可信验证人签名的快照，
+// 各节点响应中同一区块和摘要的签名合并计算，同一区块有多个摘要满足签名要求时无法确定哪个正确
+func (m *Miner) getStateSnapshot(ctx xctx.XContext) ([]*peerSnapshot, error) {
+	validators, err := m.getSnapshotValidators()
+	if err != nil {
+		ctx.GetLog().Warn("get trusted validators for state snapshot error", "err", err)
+		return nil, err
+	}
+
+	input := &xpb.GetStateSnapshotRequest{
+		Bcname: m.ctx.BCName,
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_GET_STATE_SNAPSHOT, input, p2p.WithBCName(m.ctx.BCName))
+	responses, err := m.ctx.EngCtx.Net.SendMessageWithResponse(ctx, msg,
+		p2p.WithFilter([]p2p.FilterStrategy{p2p.NearestBucketStrategy}))
+	if err != nil {
+		ctx.GetLog().Warn("p2p get state snapshot error", "err", err)
+		return nil, err
+	}
+
+	// 按区块和摘要分组，每个节点只计一次
+	hasher := m.ctx.Ledger.GetHasher()
+	groups := make(map[string][]*peerSnapshot)
+	signs := make(map[string][]*xpb.SnapshotSign)
+	peers := make(map[string]bool)
+	for _, response := range responses {
+		from := response.GetHeader().GetFrom()
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS || peers[from] {
+			continue
+		}
+		var snap xpb.StateSnapshot
+		err = p2p.Unmarshal(response, &snap)
+		if err != nil {
+			ctx.GetLog().Warn("unmarshal state snapshot error", "err", err)
+			m.ctx.EngCtx.Net.ReportPeer(from, p2p.PeerEventInvalidMessage)
+			continue
+		}
+		if err = statesync.VerifySnapshot(&snap, hasher); err != nil {
+			ctx.GetLog().Warn("verify state snapshot error", "from", from, "err", err)
+			m.ctx.EngCtx.Net.ReportPeer(from, p2p.PeerEventInvalidMessage)
+			continue
+		}
+		peers[from] = true
+		key := string(snap.GetBlock().GetBlockid()) + string(snap.GetDigest())
+		groups[key] = append(groups[key], &peerSnapshot{peer: from, snapshot: &snap})
+		signs[key] = append(signs[key], snap.GetSigns()...)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// 校验合并后的签名，记录每个区块满足签名要求的摘要个数
+	var signedGroups [][]*peerSnapshot
+	blockSigned := make(map[string]int)
+	for _, key := range keys {
+		group := groups[key]
+		signed := proto.Clone(group[0].snapshot).(*xpb.StateSnapshot)
+		signed.Signs = signs[key]
+		err = statesync.VerifySnapshotSigns(m.ctx.BCName, signed, validators, m.ctx.Crypto, hasher)
+		if err != nil {
+			ctx.GetLog().Debug("state snapshot not signed by enough validators", "height", signed.GetBlock().GetHeight(),
+				"blockid", utils.F(signed.GetBlock().GetBlockid()), "digest", utils.F(signed.GetDigest()))
+			continue
+		}
+		signedGroups = append(signedGroups, group)
+		blockSigned[string(signed.GetBlock().GetBlockid())]++
+	}
+
+	var best []*peerSnapshot
+	for _, group := range signedGroups {
+		block := group[0].snapshot.GetBlock()
+		if blockSigned[string(block.GetBlockid())] > 1 {
+			ctx.GetLog().Warn("state snapshot block has conflicting signed digests", "height", block.GetHeight(),
+				"blockid", utils.F(block.GetBlockid()))
+			continue
+		}
+		if best == nil || block.GetHeight() > best[0].snapshot.GetBlock().GetHeight() {
+			best = group
+		}
+	}
+	if best == nil {
+		ctx.GetLog().Warn("no state snapshot signed by enough validators", "validators", validators, "responses", len(responses))
+		return nil, ErrNoStateSnapshot
+	}
+	return best, nil
+}
+
+// getSnapshotValidators 获取校验快照签名的可信验证人，优先使用配置的验证人，否则使用本地共识的当前验证人，
+// 新节点只有创世块，本地共识的验证人即创世配置中的验证人，验证人变更后需要配置可信验证人
+func (m *Miner) getSnapshotValidators() ([]string, error) {
+	if validators := m.ctx.EngCtx.EngCfg.FastSyncTrustedValidators; len(validators) > 0 {
+		return validators, nil
+	}
+	return m.getValidators("")
+}
+
+// snapshotChunkReader 逐个下载并校验快照分片，供状态机导入快照使用
+// 读取的同时计算快照摘要，全部数据读取完毕时摘要不一致返回错误，状态机不会完成导入
+type snapshotChunkReader struct {
+	miner    *Miner
+	ctx      xctx.XContext
+	peer     string
+	snapshot *xpb.StateSnapshot
+	digest   *statesync.StateDigest
+	// 节点提供的数据校验不通过
+	invalid bool
+
+	index int64
+	chunk *xpb.StateChunk
+	pos   int
+}
+
+func (r *snapshotChunkReader) Read() ([]byte, []byte, error) {
+	for r.chunk == nil || r.pos >= len(r.chunk.GetKvs()) {
+		if r.index >= int64(len(r.snapshot.GetChunkDigests())) {
+			if !bytes.Equal(r.digest.Sum(), r.snapshot.GetDigest()) {
+				r.invalid = true
+				return nil, nil, statesync.ErrDigestMissMatch
+			}
+			return nil, nil, io.EOF
+		}
+		if r.miner.IsExit() {
+			return nil, nil, errors.New("miner exit")
+		}
+		chunk, err := r.downloadChunk(r.index)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = r.verifyChunkTxs(chunk); err != nil {
+			r.invalid = true
+			return nil, nil, err
+		}
+		// 分片引用的已确认交易先写入账本
+		err = r.miner.ctx.Ledger.SaveSnapshotTxs(chunk.GetTxs())
+		if err != nil {
+			return nil, nil, err
+		}
+		r.chunk = chunk
+		r.pos = 0
+		r.index++
+	}
+	kv := r.chunk.GetKvs()[r.pos]
+	r.pos++
+	r.digest.Write(kv.GetKey(), kv.GetValue())
+	return kv.GetKey(), kv.GetValue(), nil
+}
+
+// verifyChunkTxs 校验分片中的交易，交易id需要与内容一致，并且被同一分片的xmodel数据引用
+func (r *snapshotChunkReader) verifyChunkTxs(chunk *xpb.StateChunk) error {
+	refTxids := make(map[string]bool)
+	for _, kv := range chunk.GetKvs() {
+		if bytes.HasPrefix(kv.GetKey(), []byte(lpb.ExtUtxoTablePrefix)) ||
+			bytes.HasPrefix(kv.GetKey(), []byte(lpb.ExtUtxoDelTablePrefix)) {
+			refTxids[string(xmodel.GetTxidFromVersion(string(kv.GetValue())))] = true
+		}
+	}
+	hasher := r.miner.ctx.Ledger.GetHasher()
+	for _, tx := range chunk.GetTxs() {
+		if !refTxids[string(tx.GetTxid())] {
+			return fmt.Errorf("state chunk tx not referenced, txid:%s", utils.F(tx.GetTxid()))
+		}
+		txid, err := txhash.MakeTransactionIDWithHasher(tx, hasher)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(txid, tx.GetTxid()) {
+			return fmt.Errorf("state chunk txid miss match, txid:%s", utils.F(tx.GetTxid()))
+		}
+	}
+	return nil
+}
+
+func (r *snapshotChunkReader) downloadChunk(index int64) (*xpb.StateChunk, error) {
+	input := &xpb.GetStateChunkRequest{
+		Bcname:  r.miner.ctx.BCName,
+		Blockid: r.snapshot.GetBlock().GetBlockid(),
+		Index:   index,
+	}
+
+	var err error
+	for i := 0; i < maxChunkRetryTimes; i++ {
+		var chunk *xpb.StateChunk
+		chunk, err = r.requestChunk(input)
+		if err == nil {
+			return chunk, nil
+		}
+		r.ctx.GetLog().Warn("download state chunk failed", "peer", r.peer, "index", index, "retry", i, "err", err)
+	}
+	return nil, err
+}
+
+func (r *snapshotChunkReader) requestChunk(input *xpb.GetStateChunkRequest) (*xpb.StateChunk, error) {
+	msg := p2p.NewMessage(protos.XuperMessage_GET_STATE_CHUNK, input, p2p.WithBCName(r.miner.ctx.BCName))
+	responses, err := r.miner.ctx.EngCtx.Net.SendMessageWithResponse(r.ctx, msg, p2p.WithPeerIDs([]string{r.peer}))
+	if err != nil {
+		return nil, err
+	}
+	for _, response := range responses {
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			continue
+		}
+		var output xpb.GetStateChunkResponse
+		err = p2p.Unmarshal(response, &output)
+		if err != nil {
+			return nil, err
+		}
+		err = statesync.VerifyChunk(r.snapshot, input.Index, output.GetChunk())
+		if err != nil {
+			return nil, err
+		}
+		chunk := &xpb.StateChunk{}
+		err = proto.Unmarshal(output.GetChunk(), chunk)
+		if err != nil {
+			return nil, err
+		}
+		return chunk, nil
+	}
+	return nil, errors.New("get state chunk no response")
+}
@@ -0,0 +1,483 @@
+package miner
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	scontext "github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	_ "github.com/xuperchain/xupercore/kernel/contract/kernel"
+	_ "github.com/xuperchain/xupercore/kernel/contract/manager"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/statesync"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/kernel/network"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	crypto_client "github.com/xuperchain/xupercore/lib/crypto/client"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/logs"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+	"github.com/xuperchain/xupercore/lib/timer"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	fastSyncTestAddress = "dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN"
+	fastSyncTestMiner   = "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"
+)
+
+var fastSyncTestGenesis = []byte(`{
+    "version": "1",
+    "predistribution": [
+        {
+            "address": "` + fastSyncTestAddress + `",
+            "quota": "100000000"
+        }
+    ],
+    "maxblocksize": "128",
+    "award": "1000",
+    "decimals": "8",
+    "award_decay": {
+        "height_gap": 31536000,
+        "ratio": 1
+    },
+    "genesis_consensus": {
+        "name": "single",
+        "config": {
+            "miner": "` + fastSyncTestMiner + `",
+            "period": 3000
+        }
+    }
+}`)
+
+// newFastSyncTestChain 创建只有创世块的链，chunkSize为导出快照的分片大小
+func newFastSyncTestChain(t *testing.T, name string, net network.Network, chunkSize int) *common.ChainCtx {
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	econf.ChainDir = "fastsync_" + name
+	dataDir := econf.GenDataAbsPath(econf.ChainDir)
+	os.RemoveAll(dataDir)
+	t.Cleanup(func() { os.RemoveAll(dataDir) })
+
+	lctx, err := ledger.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledgerHandle, err := ledger.CreateLedger(lctx, fastSyncTestGenesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTx, err := txn.GenerateRootTx(fastSyncTestGenesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootBlock, err := ledgerHandle.FormatRootBlock([]*lpb.Transaction{rootTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledgerHandle.ConfirmBlock(rootBlock, true); !status.Succ {
+		t.Fatal("confirm root block failed", status.Error)
+	}
+	crypt := newFastSyncTestCrypto(t)
+	sctx, err := scontext.NewStateCtx(econf, "xuper", ledgerHandle, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateHandle, err := state.NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stateHandle.Play(rootBlock.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		stateHandle.Close()
+		ledgerHandle.Close()
+	})
+
+	engCfg := engconf.GetDefEngineConf()
+	engCfg.EnableFastSync = true
+	engCfg.StateSnapshotChunkSize = chunkSize
+	log, _ := logs.NewLogger("", "miner")
+	return &common.ChainCtx{
+		BaseCtx: xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()},
+		EngCtx: &common.EngineCtx{
+			BaseCtx: xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()},
+			EnvCfg:  econf,
+			EngCfg:  engCfg,
+			Net:     net,
+		},
+		BCName:  "xuper",
+		Ledger:  ledgerHandle,
+		State:   stateHandle,
+		Crypto:  crypt,
+		Address: newFastSyncTestAccount(t, name),
+	}
+}
+
+func newFastSyncTestCrypto(t *testing.T) cryptoBase.CryptoClient {
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypt
+}
+
+// newFastSyncTestAccount 根据名字生成节点账户，导出快照时使用该账户签名
+func newFastSyncTestAccount(t *testing.T, name string) *xaddress.Address {
+	crypt := newFastSyncTestCrypto(t)
+	key, err := crypt.GenerateKeyBySeed([]byte("fast sync test account seed " + name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := crypt.GetAddressFromPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := crypt.GetEcdsaPublicKeyJsonFormatStr(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &xaddress.Address{
+		Address:      addr,
+		PrivateKey:   key,
+		PublicKey:    &key.PublicKey,
+		PublicKeyStr: pubKey,
+	}
+}
+
+// appendFastSyncTestBlocks 生成count个出块奖励区块，在所有链上确认并执行
+func appendFastSyncTestBlocks(t *testing.T, count int, chains ...*common.ChainCtx) []*lpb.InternalBlock {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blocks []*lpb.InternalBlock
+	for i := 0; i < count; i++ {
+		src := chains[0]
+		meta := src.Ledger.GetMeta()
+		awardTx, err := txn.GenerateAwardTx(fastSyncTestMiner, "1000",
+			[]byte(fmt.Sprintf("award %d", meta.GetTrunkHeight()+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, err := src.Ledger.FormatBlock([]*lpb.Transaction{awardTx}, []byte(fastSyncTestMiner), key,
+			int64(1600000000000000000+i), 0, 0, meta.GetTipBlockid(), src.State.GetTotal())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, chain := range chains {
+			if status := chain.Ledger.ConfirmBlock(block, false); !status.Succ {
+				t.Fatal("confirm block failed", status.Error)
+			}
+			if err := chain.State.Play(block.Blockid); err != nil {
+				t.Fatal(err)
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// fastSyncTestPeer 模拟提供状态快照的邻居节点
+type fastSyncTestPeer struct {
+	ledger   *ledger.Ledger
+	snapshot *xpb.StateSnapshot
+	chunks   [][]byte
+}
+
+// newFastSyncTestPeer 导出源链的状态快照
+func newFastSyncTestPeer(t *testing.T, chain *common.ChainCtx) *fastSyncTestPeer {
+	snapshotter, err := statesync.NewSnapshotter(chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := snapshotter.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer := &fastSyncTestPeer{ledger: chain.Ledger, snapshot: snapshot}
+	for i := range snapshot.GetChunkDigests() {
+		chunk, err := snapshotter.GetSnapshotChunk(snapshot.GetBlock().GetBlockid(), int64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		peer.chunks = append(peer.chunks, chunk)
+	}
+	return peer
+}
+
+// forge 篡改快照中的余额数据，forgeDigest为true时同时修改快照摘要，否则冒充原快照
+func (p *fastSyncTestPeer) forge(t *testing.T, forgeDigest bool) *fastSyncTestPeer {
+	snapshot := proto.Clone(p.snapshot).(*xpb.StateSnapshot)
+	snapshot.ChunkDigests = nil
+	digest := statesync.NewStateDigest(snapshot.GetBlock().GetBlockid())
+	forged := &fastSyncTestPeer{ledger: p.ledger, snapshot: snapshot}
+	for _, data := range p.chunks {
+		chunk := &xpb.StateChunk{}
+		if err := proto.Unmarshal(data, chunk); err != nil {
+			t.Fatal(err)
+		}
+		for _, kv := range chunk.GetKvs() {
+			if bytes.HasPrefix(kv.GetKey(), []byte(lpb.UTXOTablePrefix+fastSyncTestMiner)) {
+				kv.Value = append([]byte{}, kv.GetValue()...)
+				kv.Value[len(kv.Value)-1]++
+			}
+			digest.Write(kv.GetKey(), kv.GetValue())
+		}
+		data, err := proto.Marshal(chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		forged.chunks = append(forged.chunks, data)
+		snapshot.ChunkDigests = append(snapshot.ChunkDigests, statesync.MakeChunkDigest(data))
+	}
+	if forgeDigest {
+		snapshot.Digest = digest.Sum()
+	}
+	return forged
+}
+
+// signBy 替换快照签名为accounts的签名
+func (p *fastSyncTestPeer) signBy(t *testing.T, accounts ...*xaddress.Address) *fastSyncTestPeer {
+	snapshot := proto.Clone(p.snapshot).(*xpb.StateSnapshot)
+	snapshot.Signs = nil
+	for _, account := range accounts {
+		sign, err := statesync.SignSnapshot("xuper", snapshot, account, newFastSyncTestCrypto(t), p.ledger.GetHasher())
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshot.Signs = append(snapshot.Signs, sign)
+	}
+	return &fastSyncTestPeer{ledger: p.ledger, snapshot: snapshot, chunks: p.chunks}
+}
+
+// fastSyncTestNet 模拟网络，按节点名顺序返回各节点的响应
+type fastSyncTestNet struct {
+	network.Network
+	peers    map[string]*fastSyncTestPeer
+	reported map[string]int
+}
+
+func newFastSyncTestNet() *fastSyncTestNet {
+	return &fastSyncTestNet{
+		peers:    make(map[string]*fastSyncTestPeer),
+		reported: make(map[string]int),
+	}
+}
+
+func (n *fastSyncTestNet) SendMessageWithResponse(ctx xctx.XContext, msg *protos.XuperMessage,
+	opts ...p2p.OptionFunc) ([]*protos.XuperMessage, error) {
+	targets := p2p.Apply(opts).PeerIDs
+	if len(targets) == 0 {
+		for name := range n.peers {
+			targets = append(targets, name)
+		}
+		sort.Strings(targets)
+	}
+
+	var responses []*protos.XuperMessage
+	for _, name := range targets {
+		peer, ok := n.peers[name]
+		if !ok {
+			continue
+		}
+		var output proto.Message
+		switch msg.GetHeader().GetType() {
+		case protos.XuperMessage_GET_STATE_SNAPSHOT:
+			output = peer.snapshot
+		case protos.XuperMessage_GET_BLOCK_HEADERS:
+			var input xpb.GetBlockHeaderRequest
+			if err := p2p.Unmarshal(msg, &input); err != nil {
+				return nil, err
+			}
+			block, err := peer.ledger.QueryBlockHeaderByHeight(input.GetHeight())
+			if err != nil {
+				return nil, err
+			}
+			output = &xpb.GetBlockHeaderResponse{Blocks: []*lpb.InternalBlock{block}}
+		case protos.XuperMessage_GET_STATE_CHUNK:
+			var input xpb.GetStateChunkRequest
+			if err := p2p.Unmarshal(msg, &input); err != nil {
+				return nil, err
+			}
+			output = &xpb.GetStateChunkResponse{Chunk: peer.chunks[input.GetIndex()]}
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(msg.GetHeader().GetType()), output,
+			p2p.WithBCName(msg.GetHeader().GetBcname()), p2p.WithErrorType(protos.XuperMessage_SUCCESS))
+		resp.Header.From = name
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+func (n *fastSyncTestNet) ReportPeer(peerID string, event p2p.PeerEvent) {
+	n.reported[peerID]++
+}
+
+func TestFastSync(t *testing.T) {
+	// 两个源节点执行相同的区块，分片大小不同，快照摘要相同
+	src1 := newFastSyncTestChain(t, "src1", nil, 0)
+	src2 := newFastSyncTestChain(t, "src2", nil, 64)
+	blocks := appendFastSyncTestBlocks(t, 5, src1, src2)
+	tip := blocks[len(blocks)-1]
+
+	honest1 := newFastSyncTestPeer(t, src1)
+	honest2 := newFastSyncTestPeer(t, src2)
+	if !bytes.Equal(honest1.snapshot.GetDigest(), honest2.snapshot.GetDigest()) {
+		t.Fatal("snapshots of the same block should have the same digest")
+	}
+	if len(honest1.snapshot.GetChunkDigests()) == len(honest2.snapshot.GetChunkDigests()) {
+		t.Fatal("snapshots should be exported with different chunk size")
+	}
+
+	net := newFastSyncTestNet()
+	net.peers["node1"] = honest1
+	net.peers["node2"] = honest2
+	// 篡改摘要后验证人签名失效
+	net.peers["node3"] = honest1.forge(t, true)
+	// 冒充相同摘要但分片数据被篡改，导入时重新计算摘要发现
+	net.peers["node0"] = honest1.forge(t, false)
+
+	dst := newFastSyncTestChain(t, "dst", net, 0)
+	// 两个源节点为验证人，各自只签名自己导出的快照，需要合并两个节点的签名
+	dst.EngCtx.EngCfg.FastSyncTrustedValidators = []string{src1.Address.Address, src2.Address.Address}
+	m := NewMiner(dst)
+	ctx := &xctx.BaseCtx{XLog: dst.GetLog(), Timer: timer.NewXTimer()}
+	if err := m.fastSync(ctx); err != nil {
+		t.Fatal("fast sync failed", err)
+	}
+
+	if !bytes.Equal(dst.Ledger.GetMeta().GetTipBlockid(), tip.GetBlockid()) ||
+		dst.Ledger.GetMeta().GetTrunkHeight() != tip.GetHeight() {
+		t.Fatal("ledger tip should be the snapshot block", dst.Ledger.GetMeta().GetTrunkHeight())
+	}
+	if !bytes.Equal(dst.State.GetLatestBlockid(), tip.GetBlockid()) {
+		t.Fatal("state should be at the snapshot block")
+	}
+	if _, ok := dst.State.GetSnapshotImport(); ok {
+		t.Fatal("import mark should be removed after fast sync")
+	}
+	srcBalance, _ := src1.State.GetBalance(fastSyncTestMiner)
+	dstBalance, err := dst.State.GetBalance(fastSyncTestMiner)
+	if err != nil || srcBalance.Cmp(dstBalance) != 0 {
+		t.Fatal("balance not match", "src", srcBalance, "dst", dstBalance, "err", err)
+	}
+	if net.reported["node0"] == 0 {
+		t.Error("peer serving forged chunks should be reported")
+	}
+	if net.reported["node1"] != 0 || net.reported["node2"] != 0 {
+		t.Error("honest peers should not be reported")
+	}
+
+	// 快速同步后从快照区块继续同步新区块
+	next := appendFastSyncTestBlocks(t, 1, src1, dst)[0]
+	srcBalance, _ = src1.State.GetBalance(fastSyncTestMiner)
+	dstBalance, _ = dst.State.GetBalance(fastSyncTestMiner)
+	if !bytes.Equal(dst.State.GetLatestBlockid(), next.GetBlockid()) || srcBalance.Cmp(dstBalance) != 0 {
+		t.Fatal("play block after fast sync failed", "src", srcBalance, "dst", dstBalance)
+	}
+}
+
+func TestFastSyncSigns(t *testing.T) {
+	src := newFastSyncTestChain(t, "signs_src", nil, 0)
+	appendFastSyncTestBlocks(t, 3, src)
+	honest := newFastSyncTestPeer(t, src)
+	validator := newFastSyncTestAccount(t, "signs_validator")
+
+	// 多个节点提供相同的快照，但只有一半验证人签名
+	net := newFastSyncTestNet()
+	net.peers["node1"] = honest
+	net.peers["node2"] = honest
+	net.peers["node3"] = honest
+	dst := newFastSyncTestChain(t, "signs_dst", net, 0)
+	dst.EngCtx.EngCfg.FastSyncTrustedValidators = []string{src.Address.Address, validator.Address}
+	m := NewMiner(dst)
+	ctx := &xctx.BaseCtx{XLog: dst.GetLog(), Timer: timer.NewXTimer()}
+	if err := m.fastSync(ctx); err != ErrNoStateSnapshot {
+		t.Fatal("fast sync should fail without enough validator signs", err)
+	}
+
+	// 大量节点提供非验证人签名的篡改快照
+	forged := honest.forge(t, true).signBy(t, newFastSyncTestAccount(t, "sybil1"), newFastSyncTestAccount(t, "sybil2"))
+	for i := 0; i < 5; i++ {
+		net.peers[fmt.Sprintf("sybil%d", i)] = forged
+	}
+	if err := m.fastSync(ctx); err != ErrNoStateSnapshot {
+		t.Fatal("fast sync should fail with snapshot not signed by validators", err)
+	}
+	if dst.Ledger.GetMeta().GetTrunkHeight() != 0 {
+		t.Fatal("ledger should not change when fast sync failed")
+	}
+	if _, ok := dst.State.GetSnapshotImport(); ok {
+		t.Fatal("state should not change when fast sync failed")
+	}
+
+	// 多数验证人签名的快照可以导入，篡改快照被忽略
+	net.peers["node4"] = honest.signBy(t, validator)
+	if err := m.fastSync(ctx); err != nil {
+		t.Fatal("fast sync with validator signs failed", err)
+	}
+	srcBalance, _ := src.State.GetBalance(fastSyncTestMiner)
+	dstBalance, err := dst.State.GetBalance(fastSyncTestMiner)
+	if err != nil || srcBalance.Cmp(dstBalance) != 0 {
+		t.Fatal("balance not match", "src", srcBalance, "dst", dstBalance, "err", err)
+	}
+}
+
+func TestFastSyncRecover(t *testing.T) {
+	src := newFastSyncTestChain(t, "recover_src", nil, 0)
+	appendFastSyncTestBlocks(t, 3, src)
+	peer := newFastSyncTestPeer(t, src)
+	block := peer.snapshot.GetBlock()
+
+	net := newFastSyncTestNet()
+	net.peers["node1"] = peer
+	dst := newFastSyncTestChain(t, "recover_dst", net, 0)
+	dst.EngCtx.EngCfg.EnableFastSync = false
+	m := NewMiner(dst)
+	ctx := &xctx.BaseCtx{XLog: dst.GetLog(), Timer: timer.NewXTimer()}
+
+	// 状态机导入快照后中断，账本还未确认快照区块
+	reader := &snapshotChunkReader{
+		miner:    m,
+		ctx:      ctx,
+		peer:     "node1",
+		snapshot: peer.snapshot,
+		digest:   statesync.NewStateDigest(block.GetBlockid()),
+	}
+	if err := dst.State.ImportSnapshot(block.GetBlockid(), reader); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.step(); err != ErrSnapshotImportUnfinished {
+		t.Fatal("miner should not run with unfinished snapshot import", err)
+	}
+
+	// 账本确认快照区块后中断，重启时结束导入
+	if err := dst.Ledger.ConfirmSnapshotBlock(block); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.fastSync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dst.State.GetSnapshotImport(); ok {
+		t.Fatal("import should be finished when ledger has confirmed the snapshot block")
+	}
+}
@@ -0,0 +1,479 @@
+package miner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// 并行同步时单个下载窗口的区块个数
+	syncWindowSize = maxBatchBlockNumber
+	// 已下载未确认的窗口个数上限为并发数的倍数，避免落后的窗口迟迟未完成时缓存过多区块
+	maxPendingWindowRate = 4
+	// 单个窗口的最大下载次数，每次重试更换节点
+	maxWindowRetryTimes = 3
+	// 节点连续失败达到该次数后不再为其分配窗口
+	maxPeerFailTimes = 3
+)
+
+var (
+	ErrNoSyncPeer      = errors.New("no peer available for block window")
+	ErrBadSyncBlock    = errors.New("downloaded block mismatch blockids")
+	ErrIncompleteBlock = errors.New("downloaded block window incomplete")
+)
+
+// parallelSync 先向邻居节点查询多数认可的blockid列表，再按窗口从多个节点并行下载区块，
+// 下载完成的窗口缓存后按高度顺序确认，返回确认的区块个数
+// blockid列表在下载前确定，每个区块需与列表一致并通过PreHash与前一个区块相连，
+// 因此各节点返回的窗口拼接后即为邻居节点认可的区块头链
+func (m *Miner) parallelSync(ctx xctx.XContext) (int, error) {
+	total := 0
+	for !m.IsExit() {
+		height := m.ctx.Ledger.GetMeta().TrunkHeight + 1
+		ids, tracker, err := m.getBlockIds(ctx, height)
+		if err == ErrNoNewBlock {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		ctx.GetLog().Info("parallel sync with blockids", "height", height, "size", len(ids), "peers", tracker.size())
+
+		workers := m.ctx.EngCtx.EngCfg.ParallelSyncWorkers
+		if workers <= 0 {
+			workers = 1
+		}
+		d := &blockDownloader{
+			log:     ctx.GetLog(),
+			height:  height,
+			ids:     ids,
+			workers: workers,
+			tracker: tracker,
+			fetch: func(peer string, height int64, size int) ([]*lpb.InternalBlock, error) {
+				return m.fetchBlockHeaders(ctx, peer, height, size)
+			},
+			fill: func(peer string, blocks []*lpb.InternalBlock) error {
+				return m.fillBlocksFromPeer(ctx, peer, blocks)
+			},
+			confirm: func(blocks []*lpb.InternalBlock) error {
+				return m.batchConfirmBlocks(ctx, blocks)
+			},
+			report: func(peer string) {
+				m.ctx.EngCtx.Net.ReportPeer(peer, p2p.PeerEventInvalidMessage)
+			},
+			exit: m.IsExit,
+		}
+		size, err := d.run()
+		total += size
+		if err == ErrHashMissMatch {
+			// 第一个区块与本地主干不相连，发生了分叉
+			ctx.GetLog().Error("parallel sync with fork")
+			err = m.handleFork(ctx)
+		}
+		if err != nil {
+			ctx.GetLog().Warn("parallel sync error", "height", height, "size", size, "error", err)
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// getBlockIds 查询邻居节点从height开始的blockid列表，返回多数认可的列表及可提供下载的节点
+func (m *Miner) getBlockIds(ctx xctx.XContext, height int64) ([][]byte, *peerTracker, error) {
+	input := &xpb.GetBlockIdsRequest{
+		Bcname: m.ctx.BCName,
+		Height: height,
+		Size:   common.MaxSyncBlockIdsSize,
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCKIDS, input, p2p.WithBCName(m.ctx.BCName))
+	responses, err := m.ctx.EngCtx.Net.SendMessageWithResponse(ctx, msg, m.syncFilterOptions()...)
+	if err != nil {
+		ctx.GetLog().Warn("p2p get blockids error", "err", err)
+		return nil, nil, err
+	}
+
+	peerIds := make(map[string][][]byte)
+	for _, response := range responses {
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			continue
+		}
+		var output xpb.GetBlockIdsResponse
+		err = p2p.Unmarshal(response, &output)
+		if err != nil {
+			ctx.GetLog().Warn("unmarshal blockids error", "err", err)
+			m.ctx.EngCtx.Net.ReportPeer(response.GetHeader().GetFrom(), p2p.PeerEventInvalidMessage)
+			continue
+		}
+		if len(output.GetBlockids()) == 0 {
+			continue
+		}
+		peerIds[response.GetHeader().GetFrom()] = output.GetBlockids()
+	}
+
+	ids := quorumBlockIds(peerIds)
+	if len(ids) == 0 {
+		return nil, nil, ErrNoNewBlock
+	}
+	tracker := newPeerTracker()
+	for peer, pids := range peerIds {
+		n := 0
+		for n < len(pids) && n < len(ids) && bytes.Equal(pids[n], ids[n]) {
+			n++
+		}
+		if n > 0 {
+			tracker.add(peer, height+int64(n)-1)
+		}
+	}
+	return ids, tracker, nil
+}
+
+// quorumBlockIds 根据各节点返回的blockid列表逐个高度选出多数认可的blockid，
+// 每个高度只统计此前所有高度均与结果一致的节点，保证结果是同一条链
+func quorumBlockIds(peerIds map[string][][]byte) [][]byte {
+	agreed := make([][][]byte, 0, len(peerIds))
+	for _, ids := range peerIds {
+		agreed = append(agreed, ids)
+	}
+
+	var result [][]byte
+	for i := 0; ; i++ {
+		count := make(map[string]int)
+		for _, ids := range agreed {
+			if i < len(ids) {
+				count[string(ids[i])]++
+			}
+		}
+		if len(count) == 0 {
+			break
+		}
+		best := ""
+		for id, c := range count {
+			// 票数相同时取字节序较小的blockid，保证结果确定
+			if best == "" || c > count[best] || (c == count[best] && id < best) {
+				best = id
+			}
+		}
+		result = append(result, []byte(best))
+
+		next := agreed[:0]
+		for _, ids := range agreed {
+			if i < len(ids) && string(ids[i]) == best {
+				next = append(next, ids)
+			}
+		}
+		agreed = next
+	}
+	return result
+}
+
+// fetchBlockHeaders 从指定节点下载[height, height+size)的区块头，并校验blockid与区块头内容一致
+func (m *Miner) fetchBlockHeaders(ctx xctx.XContext, peer string, height int64, size int) ([]*lpb.InternalBlock, error) {
+	input := &xpb.GetBlockHeaderRequest{
+		Bcname: m.ctx.BCName,
+		Height: height,
+		Size:   int64(size),
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCK_HEADERS, input, p2p.WithBCName(m.ctx.BCName))
+	responses, err := m.ctx.EngCtx.Net.SendMessageWithResponse(ctx, msg, p2p.WithPeerIDs([]string{peer}))
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []*lpb.InternalBlock
+	for _, response := range responses {
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			continue
+		}
+		var output xpb.GetBlockHeaderResponse
+		err = p2p.Unmarshal(response, &output)
+		if err != nil {
+			return nil, ErrBadSyncBlock
+		}
+		blocks = output.GetBlocks()
+		break
+	}
+	if len(blocks) == 0 {
+		return nil, errors.New("get block headers no response")
+	}
+	for _, blk := range blocks {
+		if blk == nil {
+			return nil, ErrIncompleteBlock
+		}
+		blkid, _ := ledger.MakeBlockIDWithHasher(blk, m.ctx.Ledger.GetHasher())
+		if !bytes.Equal(blkid, blk.GetBlockid()) {
+			ctx.GetLog().Warn("download bad block id", "peer", peer, "height", blk.GetHeight(),
+				"got", utils.F(blk.GetBlockid()), "expect", utils.F(blkid))
+			return nil, ErrBadSyncBlock
+		}
+	}
+	return blocks, nil
+}
+
+// fillBlocksFromPeer 补全区块交易，缺失的交易向下载区块头的节点获取
+func (m *Miner) fillBlocksFromPeer(ctx xctx.XContext, peer string, blocks []*lpb.InternalBlock) error {
+	// TODO: SA1029
+	ctx = xctx.WithNewContext(ctx, context.WithValue(ctx, peersKey, []string{peer})) //nolint:staticcheck
+	for _, block := range blocks {
+		err := m.fillBlockTxs(ctx, block)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// peerStat 并行同步中单个节点的下载情况
+type peerStat struct {
+	// 节点可提供的最大高度
+	height int64
+	// 进行中的请求数
+	busy int
+	// 累计下载成功的区块数及耗时，用于估算吞吐
+	blocks int64
+	cost   time.Duration
+	// 连续失败次数
+	fails int
+}
+
+// score 估算为该节点再分配一个窗口时的下载速度，未测速的节点优先分配
+func (s *peerStat) score() float64 {
+	if s.cost <= 0 {
+		return math.Inf(1)
+	}
+	return float64(s.blocks) / s.cost.Seconds() / float64(s.busy+1)
+}
+
+// peerTracker 记录各节点的吞吐及失败情况，为下载窗口选择节点
+type peerTracker struct {
+	mutex sync.Mutex
+	peers map[string]*peerStat
+}
+
+func newPeerTracker() *peerTracker {
+	return &peerTracker{
+		peers: make(map[string]*peerStat),
+	}
+}
+
+func (t *peerTracker) add(peer string, height int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.peers[peer] = &peerStat{
+		height: height,
+	}
+}
+
+func (t *peerTracker) size() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return len(t.peers)
+}
+
+// pick 为截止高度为end的窗口选择节点，跳过tried中已尝试过的节点及连续失败过多的节点，
+// 选择score最高的节点，score相同时选择请求数较少的节点，没有可用节点时返回空
+func (t *peerTracker) pick(end int64, tried map[string]bool) string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	best := ""
+	var bestStat *peerStat
+	for peer, s := range t.peers {
+		if s.height < end || s.fails >= maxPeerFailTimes || tried[peer] {
+			continue
+		}
+		if bestStat == nil || better(peer, s, best, bestStat) {
+			best, bestStat = peer, s
+		}
+	}
+	if bestStat != nil {
+		bestStat.busy++
+	}
+	return best
+}
+
+func better(peer string, s *peerStat, best string, bestStat *peerStat) bool {
+	if s.score() != bestStat.score() {
+		return s.score() > bestStat.score()
+	}
+	if s.busy != bestStat.busy {
+		return s.busy < bestStat.busy
+	}
+	return peer < best
+}
+
+// done 记录一次下载的结果
+func (t *peerTracker) done(peer string, blocks int, cost time.Duration, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	s, ok := t.peers[peer]
+	if !ok {
+		return
+	}
+	s.busy--
+	if err != nil {
+		s.fails++
+		return
+	}
+	s.fails = 0
+	s.blocks += int64(blocks)
+	s.cost += cost
+}
+
+// windowResult 单个窗口的下载结果
+type windowResult struct {
+	index  int
+	blocks []*lpb.InternalBlock
+	err    error
+}
+
+// blockDownloader 按窗口并行下载ids对应的区块，下载完成的窗口缓存后按高度顺序确认
+type blockDownloader struct {
+	log logs.Logger
+	// ids[0]对应的高度
+	height  int64
+	ids     [][]byte
+	workers int
+	tracker *peerTracker
+	// fetch 从指定节点下载[height, height+size)的区块头
+	fetch func(peer string, height int64, size int) ([]*lpb.InternalBlock, error)
+	// fill 补全区块交易
+	fill func(peer string, blocks []*lpb.InternalBlock) error
+	// confirm 按高度顺序确认区块
+	confirm func(blocks []*lpb.InternalBlock) error
+	// report 上报返回错误数据的节点
+	report func(peer string)
+	exit   func() bool
+}
+
+// run 下载并确认全部窗口，返回确认的区块个数
+// 任一窗口重试后仍下载失败或确认失败时停止，已确认的区块不回滚
+func (d *blockDownloader) run() (int, error) {
+	windows := (len(d.ids) + syncWindowSize - 1) / syncWindowSize
+	maxPending := d.workers * maxPendingWindowRate
+	// 进行中的请求数不超过workers，结果写入不会阻塞
+	results := make(chan *windowResult, d.workers)
+	buffered := make(map[int][]*lpb.InternalBlock)
+
+	next, dispatched, inflight, confirmed := 0, 0, 0, 0
+	var err error
+	for next < windows && err == nil {
+		if d.exit != nil && d.exit() {
+			err = errors.New("miner exit")
+			break
+		}
+		for dispatched < windows && inflight < d.workers && dispatched < next+maxPending {
+			go d.download(dispatched, results)
+			dispatched++
+			inflight++
+		}
+
+		r := <-results
+		inflight--
+		if r.err != nil {
+			err = r.err
+			break
+		}
+		buffered[r.index] = r.blocks
+		for blocks, ok := buffered[next]; ok; blocks, ok = buffered[next] {
+			delete(buffered, next)
+			err = d.confirm(blocks)
+			if err != nil {
+				break
+			}
+			confirmed += len(blocks)
+			next++
+		}
+	}
+	// 等待进行中的请求结束，避免与后续的同步并发
+	for ; inflight > 0; inflight-- {
+		<-results
+	}
+	return confirmed, err
+}
+
+// download 下载第index个窗口，失败时更换节点重试
+func (d *blockDownloader) download(index int, results chan<- *windowResult) {
+	begin := index * syncWindowSize
+	end := begin + syncWindowSize
+	if end > len(d.ids) {
+		end = len(d.ids)
+	}
+	height := d.height + int64(begin)
+
+	err := ErrNoSyncPeer
+	tried := make(map[string]bool)
+	for i := 0; i < maxWindowRetryTimes; i++ {
+		peer := d.tracker.pick(d.height+int64(end)-1, tried)
+		if peer == "" {
+			break
+		}
+		tried[peer] = true
+
+		start := time.Now()
+		var blocks []*lpb.InternalBlock
+		blocks, err = d.fetch(peer, height, end-begin)
+		if err == nil {
+			err = d.verify(begin, blocks)
+		}
+		if err == nil {
+			err = d.fill(peer, blocks)
+		}
+		d.tracker.done(peer, len(blocks), time.Since(start), err)
+		if err == nil {
+			results <- &windowResult{
+				index:  index,
+				blocks: blocks,
+			}
+			return
+		}
+
+		d.log.Warn("download block window failed", "peer", peer, "height", height,
+			"size", end-begin, "retry", i, "err", err)
+		if err == ErrBadSyncBlock && d.report != nil {
+			d.report(peer)
+		}
+	}
+	results <- &windowResult{
+		index: index,
+		err:   err,
+	}
+}
+
+// verify 校验窗口内的区块与blockid列表一致，且通过PreHash与前一个区块相连
+// 第一个窗口的首个区块与本地主干是否相连由确认区块时校验
+func (d *blockDownloader) verify(begin int, blocks []*lpb.InternalBlock) error {
+	end := begin + syncWindowSize
+	if end > len(d.ids) {
+		end = len(d.ids)
+	}
+	if len(blocks) != end-begin {
+		return ErrIncompleteBlock
+	}
+	for i, blk := range blocks {
+		idx := begin + i
+		if blk == nil {
+			return ErrIncompleteBlock
+		}
+		if blk.GetHeight() != d.height+int64(idx) || !bytes.Equal(blk.GetBlockid(), d.ids[idx]) {
+			return ErrBadSyncBlock
+		}
+		if idx > 0 && !bytes.Equal(blk.GetPreHash(), d.ids[idx-1]) {
+			return ErrBadSyncBlock
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func newTestLogger(t *testing.T) logs.Logger {
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+	l, _ := logs.NewLogger("", "miner")
+	return l
+}
+
+func testBlockId(height int64) []byte {
+	return []byte(fmt.Sprintf("block_%d", height))
+}
+
+func testBlockIds(height int64, size int) [][]byte {
+	ids := make([][]byte, size)
+	for i := range ids {
+		ids[i] = testBlockId(height + int64(i))
+	}
+	return ids
+}
+
+func testBlocks(height int64, size int) []*lpb.InternalBlock {
+	blocks := make([]*lpb.InternalBlock, size)
+	for i := range blocks {
+		h := height + int64(i)
+		blocks[i] = &lpb.InternalBlock{
+			Height:  h,
+			Blockid: testBlockId(h),
+			PreHash: testBlockId(h - 1),
+		}
+	}
+	return blocks
+}
+
+// testPeer 模拟提供区块下载的节点，delay为每次请求的耗时，bad为true时返回错误的区块
+type testPeer struct {
+	delay time.Duration
+	bad   bool
+	fail  bool
+}
+
+func newTestDownloader(t *testing.T, height int64, size int, peers map[string]*testPeer) (*blockDownloader, *[]*lpb.InternalBlock, map[string]int) {
+	tracker := newPeerTracker()
+	for peer := range peers {
+		tracker.add(peer, height+int64(size)-1)
+	}
+	var confirmed []*lpb.InternalBlock
+	reported := make(map[string]int)
+	var mutex sync.Mutex
+	d := &blockDownloader{
+		log:     newTestLogger(t),
+		height:  height,
+		ids:     testBlockIds(height, size),
+		workers: 4,
+		tracker: tracker,
+		fetch: func(peer string, height int64, size int) ([]*lpb.InternalBlock, error) {
+			p := peers[peer]
+			time.Sleep(p.delay)
+			if p.fail {
+				return nil, errors.New("timeout")
+			}
+			blocks := testBlocks(height, size)
+			if p.bad {
+				blocks[0].Blockid = []byte("forged")
+			}
+			return blocks, nil
+		},
+		fill: func(peer string, blocks []*lpb.InternalBlock) error {
+			return nil
+		},
+		confirm: func(blocks []*lpb.InternalBlock) error {
+			confirmed = append(confirmed, blocks...)
+			return nil
+		},
+		report: func(peer string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			reported[peer]++
+		},
+	}
+	return d, &confirmed, reported
+}
+
+func TestQuorumBlockIds(t *testing.T) {
+	fork := testBlockIds(10, 8)
+	fork[2] = []byte("fork_12")
+	peerIds := map[string][][]byte{
+		"A": testBlockIds(10, 5),
+		"B": testBlockIds(10, 6),
+		"C": fork,
+	}
+	ids := quorumBlockIds(peerIds)
+	// C在高度12分叉，之后不再参与统计，结果只延伸到B的高度
+	if len(ids) != 6 {
+		t.Fatal("quorum blockids length error", "len", len(ids))
+	}
+	for i, id := range ids {
+		if string(id) != string(testBlockId(10+int64(i))) {
+			t.Error("quorum blockid error", "index", i, "id", string(id))
+		}
+	}
+
+	if ids := quorumBlockIds(map[string][][]byte{}); len(ids) != 0 {
+		t.Error("empty responses should return no blockids")
+	}
+}
+
+func TestPeerTrackerPick(t *testing.T) {
+	tracker := newPeerTracker()
+	tracker.add("fast", 100)
+	tracker.add("slow", 100)
+	tracker.add("short", 50)
+
+	if peer := tracker.pick(60, nil); peer != "fast" {
+		t.Fatal("pick peer error", "peer", peer)
+	}
+	tracker.done("fast", 10, 10*time.Millisecond, nil)
+	// 未测速的节点优先分配
+	if peer := tracker.pick(60, nil); peer != "slow" {
+		t.Error("unmeasured peer should be picked first", "peer", peer)
+	}
+	tracker.done("slow", 10, 100*time.Millisecond, nil)
+	if peer := tracker.pick(60, nil); peer != "fast" {
+		t.Error("fast peer should be picked", "peer", peer)
+	}
+	tracker.done("fast", 10, 10*time.Millisecond, nil)
+	// 高度不足的节点不参与分配
+	if peer := tracker.pick(100, map[string]bool{"fast": true, "slow": true}); peer != "" {
+		t.Error("short peer should not be picked", "peer", peer)
+	}
+	// 连续失败过多的节点不再分配
+	for i := 0; i < maxPeerFailTimes; i++ {
+		tracker.pick(60, nil)
+		tracker.done("fast", 0, time.Millisecond, errors.New("timeout"))
+	}
+	if peer := tracker.pick(60, nil); peer != "slow" {
+		t.Error("failed peer should be skipped", "peer", peer)
+	}
+}
+
+func TestBlockDownloader(t *testing.T) {
+	peers := map[string]*testPeer{
+		"fast": {delay: time.Millisecond},
+		"slow": {delay: 20 * time.Millisecond},
+		"bad":  {delay: time.Millisecond, bad: true},
+	}
+	size := 95
+	d, confirmed, reported := newTestDownloader(t, 3, size, peers)
+	n, err := d.run()
+	if err != nil {
+		t.Fatal("download error", err)
+	}
+	if n != size || len(*confirmed) != size {
+		t.Fatal("confirmed size error", "n", n, "confirmed", len(*confirmed))
+	}
+	// 乱序下载的窗口按高度顺序确认
+	for i, blk := range *confirmed {
+		if blk.GetHeight() != 3+int64(i) {
+			t.Fatal("confirm out of order", "index", i, "height", blk.GetHeight())
+		}
+	}
+	if reported["bad"] == 0 {
+		t.Error("bad peer should be reported")
+	}
+	fast := d.tracker.peers["fast"].blocks
+	slow := d.tracker.peers["slow"].blocks
+	t.Log("downloaded blocks", "fast", fast, "slow", slow)
+	if fast <= slow {
+		t.Error("fast peer should download more blocks", "fast", fast, "slow", slow)
+	}
+}
+
+func TestBlockDownloaderFail(t *testing.T) {
+	peers := map[string]*testPeer{
+		"A": {fail: true},
+		"B": {fail: true},
+	}
+	d, confirmed, _ := newTestDownloader(t, 3, 25, peers)
+	n, err := d.run()
+	if err == nil {
+		t.Fatal("download should fail when all peers fail")
+	}
+	if n != 0 || len(*confirmed) != 0 {
+		t.Error("no block should be confirmed", "n", n)
+	}
+	for peer, s := range d.tracker.peers {
+		if s.busy != 0 {
+			t.Error("inflight request not finished", "peer", peer, "busy", s.busy)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
@@ -106,6 +107,10 @@ func (m *Miner) Start() {
 		XLog:  m.log,
 		Timer: timer.NewXTimer(),
 	}
+	// 新节点开启快速同步时，先导入邻居节点的状态快照
+	if err := m.fastSync(ctx); err != nil {
+		m.log.Warn("fast sync with state snapshot failed", "err", err)
+	}
 	_ = m.syncWithNeighbors(ctx)
 
 	// 启动矿工循环
@@ -151,6 +156,17 @@ func (m *Miner) step() error {
 		Timer: timer.NewXTimer(),
 	}
 
+	// 快照导入未完成时状态机数据不完整，重新快速同步成功前不能执行区块
+	if _, ok := m.ctx.State.GetSnapshotImport(); ok {
+		if err := m.fastSync(ctx); err != nil {
+			return err
+		}
+		if _, ok := m.ctx.State.GetSnapshotImport(); ok {
+			return ErrSnapshotImportUnfinished
+		}
+		return nil
+	}
+
 	// 账本和状态机最新区块id不一致，需要进行一次同步
 	if !bytes.Equal(ledgerTipId, stateTipId) {
 		err := m.ctx.State.Walk(ledgerTipId, false)
@@ -269,7 +285,7 @@ func (m *Miner) mining(ctx xctx.XContext) error {
 
 	// 3. 针对一些需要patch区块的共识
 	origBlkId := block.Blockid
-	blkAgent := state.NewBlockAgent(block)
+	blkAgent := state.NewBlockAgentWithHasher(block, m.ctx.Ledger.GetHasher())
 	err = m.calculateBlock(blkAgent)
 	ctx.GetTimer().Mark("CalculateBlock")
 	if err == errCalculateBlockInterrupt {
@@ -419,7 +435,11 @@ func (m *Miner) getTimerTx(height int64) (*lpb.Transaction, error) {
 }
 
 func (m *Miner) getUnconfirmedTx(sizeLimit int) ([]*lpb.Transaction, error) {
-	return m.ctx.State.GetUnconfirmedTx(false, sizeLimit)
+	txs, err := m.ctx.State.GetUnconfirmedTx(false, sizeLimit)
+	if err != nil {
+		return nil, err
+	}
+	return m.ctx.State.FilterPendingTxs(txs), nil
 }
 
 func (m *Miner) getAwardTx(height int64) (*lpb.Transaction, error) {
@@ -428,7 +448,15 @@ func (m *Miner) getAwardTx(height int64) (*lpb.Transaction, error) {
 		return nil, errors.New("amount in transaction can not be negative number")
 	}
 
-	awardTx, err := tx.GenerateAwardTx(m.ctx.Address.Address, amount.String(), []byte("award"))
+	// 共识可以指定奖励接收地址，如tdpos开启投票收益分成时奖励先转入共识合约bucket
+	to := m.ctx.Address.Address
+	if a, ok := m.ctx.Consensus.(consensus.AwardInterface); ok {
+		if addr := a.GetAwardAddress(height); addr != "" {
+			to = addr
+		}
+	}
+	awardTx, err := tx.GenerateAwardTxWithHasher(to, amount.String(), []byte("award"),
+		m.ctx.Ledger.GetHasher())
 	if err != nil {
 		return nil, err
 	}
@@ -507,10 +535,12 @@ func (m *Miner) confirmBlockForMiner(ctx xctx.XContext, block *lpb.InternalBlock
 	ctx.GetTimer().Mark("PlayForMiner")
 	if err != nil {
 		ctx.GetLog().Warn("state play error ", "error", err, "blockId", utils.F(block.Blockid))
+	} else {
+		m.exportStateSnapshot(block)
 	}
 
 	// 共识确认区块
-	blkAgent := state.NewBlockAgent(block)
+	blkAgent := state.NewBlockAgentWithHasher(block, m.ctx.Ledger.GetHasher())
 	err = m.ctx.Consensus.ProcessConfirmBlock(blkAgent)
 	ctx.GetTimer().Mark("ProcessConfirmBlock")
 	if err != nil {
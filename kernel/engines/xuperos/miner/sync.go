@@ -219,6 +219,12 @@ func (m *Miner) syncWithNeighbors(ctx xctx.XContext) error {
 		if size <= 0 {
 			break
 		}
+		// 邻居节点还有更多区块时，改为先校验blockid列表再从多个节点并行下载
+		if m.ctx.EngCtx.EngCfg.EnableParallelSync && size >= batchBlockNumber {
+			if _, err := m.parallelSync(ctx); err != nil {
+				ctx.GetLog().Warn("parallelSync error", "error", err)
+			}
+		}
 	}
 	return nil
 }
@@ -273,15 +279,7 @@ func (m *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 		ctx.GetLog().Debug("sync with peer address", "address", ctx.Value(peersKey))
 		opts = append(opts, p2p.WithPeerIDs(ctx.Value(peersKey).([]string)))
 	} else {
-		switch m.ctx.EngCtx.EngCfg.SyncBlockFilterMode {
-		case common.SyncWithNearestBucket:
-			opts = append(opts, p2p.WithFilter([]p2p.FilterStrategy{p2p.NearestBucketStrategy}))
-		case common.SyncWithFactorBucket:
-			opts = append(opts, p2p.WithFilter([]p2p.FilterStrategy{p2p.BucketsWithFactorStrategy}),
-				p2p.WithFactor(m.ctx.EngCtx.EngCfg.SyncFactorForFactorBucketMode))
-		default:
-			opts = append(opts, p2p.WithFilter([]p2p.FilterStrategy{p2p.NearestBucketStrategy}))
-		}
+		opts = m.syncFilterOptions()
 	}
 	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCK_HEADERS, input, p2p.WithBCName(m.ctx.BCName))
 	responses, err := m.ctx.EngCtx.Net.SendMessageWithResponse(ctx, msg, opts...)
@@ -292,7 +290,7 @@ func (m *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 	trace("getBlockHeader")
 	blocks := quorumBlocks(responses, size)
 	for _, blk := range blocks {
-		blkid, _ := ledger.MakeBlockID(blk)
+		blkid, _ := ledger.MakeBlockIDWithHasher(blk, m.ctx.Ledger.GetHasher())
 		if !bytes.Equal(blkid, blk.GetBlockid()) {
 			ctx.GetLog().Warn("download bad block id", "height", blk.GetHeight(),
 				"got", utils.F(blk.GetBlockid()), "expect", utils.F(blkid))
@@ -313,6 +311,19 @@ func (m *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 	return blocks, nil
 }
 
+// syncFilterOptions 按SyncBlockFilterMode配置选择同步区块的邻居节点
+func (m *Miner) syncFilterOptions() []p2p.OptionFunc {
+	switch m.ctx.EngCtx.EngCfg.SyncBlockFilterMode {
+	case common.SyncWithNearestBucket:
+		return []p2p.OptionFunc{p2p.WithFilter([]p2p.FilterStrategy{p2p.NearestBucketStrategy})}
+	case common.SyncWithFactorBucket:
+		return []p2p.OptionFunc{p2p.WithFilter([]p2p.FilterStrategy{p2p.BucketsWithFactorStrategy}),
+			p2p.WithFactor(m.ctx.EngCtx.EngCfg.SyncFactorForFactorBucketMode)}
+	default:
+		return []p2p.OptionFunc{p2p.WithFilter([]p2p.FilterStrategy{p2p.NearestBucketStrategy})}
+	}
+}
+
 func (m *Miner) fillBlockTxs(ctx xctx.XContext, block *lpb.InternalBlock) error {
 	trace := traceSync()
 	txids := block.GetMerkleTree()[:block.GetTxCount()]
@@ -399,7 +410,7 @@ func (m *Miner) downloadMissingTxs(ctx xctx.XContext, blockid []byte, txidx []in
 		return nil, errors.New("get block txs no response")
 	}
 	for _, tx := range txs {
-		txid, _ := txhash.MakeTransactionID(tx)
+		txid, _ := txhash.MakeTransactionIDWithHasher(tx, m.ctx.Ledger.GetHasher())
 		if !bytes.Equal(txid, tx.GetTxid()) {
 			ctx.GetLog().Warn("download bad tx id", "expect", utils.F(txid), "got", tx.GetTxid())
 			return nil, errors.New("bad tx id")
@@ -439,7 +450,7 @@ func (m *Miner) batchConfirmBlocks(ctx xctx.XContext, blocks []*lpb.InternalBloc
 			return ErrHashMissMatch
 		}
 
-		blockAgent := state.NewBlockAgent(block)
+		blockAgent := state.NewBlockAgentWithHasher(block, m.ctx.Ledger.GetHasher())
 		isMatch, err := m.ctx.Consensus.CheckMinerMatch(ctx, blockAgent)
 		if !isMatch {
 			ctx.GetLog().Warn("consensus check miner match failed",
@@ -462,6 +473,8 @@ func (m *Miner) batchConfirmBlocks(ctx xctx.XContext, blocks []*lpb.InternalBloc
 		err = m.ctx.State.PlayAndRepost(block.Blockid, false, false)
 		if err != nil {
 			ctx.GetLog().Warn("state play error", "error", err, "height", block.Height, "blockId", utils.F(block.Blockid))
+		} else {
+			m.exportStateSnapshot(block)
 		}
 		trace("PlayAndRepost")
 		xTimer.Mark("PlayAndRepost")
@@ -12,6 +12,7 @@ import (
 
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/agent"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/reader"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
@@ -87,13 +88,18 @@ func (e *Event) Subscriber() error {
 		protos.XuperMessage_CONFIRM_BLOCKCHAINSTATUS: e.handleConfirmChainStatus,
 		protos.XuperMessage_GET_BLOCK_HEADERS:        e.handleGetBlockHeaders,
 		protos.XuperMessage_GET_BLOCK_TXS:            e.handleGetBlockTxs,
+		protos.XuperMessage_GET_BLOCKIDS:             e.handleGetBlockIds,
+		protos.XuperMessage_GET_STATE_SNAPSHOT:       e.handleGetStateSnapshot,
+		protos.XuperMessage_GET_STATE_CHUNK:          e.handleGetStateChunk,
+		protos.XuperMessage_CROSS_QUERY:              e.handleCrossQuery,
 	}
 
 	net := e.net()
 	// 订阅异步处理消息
 	for _, msgType := range AsyncMsgList {
 		// 注册订阅
-		if err := net.Register(p2p.NewSubscriber(net.Context(), msgType, e.msgChan)); err != nil {
+		sub := p2p.NewSubscriber(net.Context(), msgType, e.msgChan, p2p.WithValidator(e.validateMessage))
+		if err := net.Register(sub); err != nil {
 			e.log.Error("register subscriber error", "type", msgType, "error", err)
 			return fmt.Errorf("register subscriber failed")
 		}
@@ -155,6 +161,13 @@ func (e *Event) handlePostTx(ctx xctx.XContext, request *protos.XuperMessage) {
 	var tx lpb.Transaction
 	if err := p2p.Unmarshal(request, &tx); err != nil {
 		ctx.GetLog().Warn("handlePostTx Unmarshal request error", "error", err)
+		e.reportPeer(request, p2p.PeerEventInvalidMessage)
+		return
+	}
+
+	if err := validatePostTx(&tx); err != nil {
+		ctx.GetLog().Warn("handlePostTx validate tx error", "error", err)
+		e.reportPeer(request, p2p.PeerEventInvalidTx)
 		return
 	}
 
@@ -167,6 +180,10 @@ func (e *Event) handlePostTx(ctx xctx.XContext, request *protos.XuperMessage) {
 	err = e.PostTx(ctx, chain, &tx)
 	if err == nil {
 		go e.sendMessage(ctx, request)
+		return
+	}
+	if isInvalidTx(err) {
+		e.reportPeer(request, p2p.PeerEventInvalidTx)
 	}
 }
 
@@ -174,6 +191,7 @@ func (e *Event) handleBatchPostTx(ctx xctx.XContext, request *protos.XuperMessag
 	var input xpb.Transactions
 	if err := p2p.Unmarshal(request, &input); err != nil {
 		ctx.GetLog().Warn("handleBatchPostTx Unmarshal request error", "error", err)
+		e.reportPeer(request, p2p.PeerEventInvalidMessage)
 		return
 	}
 
@@ -185,9 +203,18 @@ func (e *Event) handleBatchPostTx(ctx xctx.XContext, request *protos.XuperMessag
 
 	broadcastTx := make([]*lpb.Transaction, 0, len(input.Txs))
 	for _, tx := range input.Txs {
+		if err := validatePostTx(tx); err != nil {
+			ctx.GetLog().Warn("handleBatchPostTx validate tx error", "error", err)
+			e.reportPeer(request, p2p.PeerEventInvalidTx)
+			return
+		}
+
 		err := e.PostTx(ctx, chain, tx)
 		if err != nil {
 			ctx.GetLog().Warn("post tx error", "bcName", request.GetHeader().GetBcname(), "error", err)
+			if isInvalidTx(err) {
+				e.reportPeer(request, p2p.PeerEventInvalidTx)
+			}
 			return
 		}
 
@@ -195,7 +222,7 @@ func (e *Event) handleBatchPostTx(ctx xctx.XContext, request *protos.XuperMessag
 	}
 
 	input.Txs = broadcastTx
-	msg := p2p.NewMessage(protos.XuperMessage_BATCHPOSTTX, &input)
+	msg := p2p.NewMessage(protos.XuperMessage_BATCHPOSTTX, &input, p2p.WithBCName(request.Header.Bcname))
 
 	go e.sendMessage(ctx, msg)
 }
@@ -223,6 +250,7 @@ func (e *Event) handleSendBlock(ctx xctx.XContext, request *protos.XuperMessage)
 	var block lpb.InternalBlock
 	if err := p2p.Unmarshal(request, &block); err != nil {
 		ctx.GetLog().Warn("handleSendBlock Unmarshal request error", "error", err)
+		e.reportPeer(request, p2p.PeerEventInvalidMessage)
 		return
 	}
 
@@ -233,6 +261,9 @@ func (e *Event) handleSendBlock(ctx xctx.XContext, request *protos.XuperMessage)
 	}
 
 	if err := e.SendBlock(ctx, chain, &block); err != nil {
+		if isInvalidBlock(err) {
+			e.reportPeer(request, p2p.PeerEventInvalidBlock)
+		}
 		return
 	}
 
@@ -268,6 +299,89 @@ func (e *Event) handleNewBlockID(ctx xctx.XContext, request *protos.XuperMessage
 	go e.sendMessage(ctx, request)
 }
 
+// validateMessage 校验广播的交易和区块，网络层只转发校验通过的广播消息
+func (e *Event) validateMessage(msg *protos.XuperMessage) error {
+	chain, err := e.engine.Get(msg.GetHeader().GetBcname())
+	if err != nil {
+		return err
+	}
+	// chain已经Stop
+	if chain.Context() == nil {
+		return common.ErrChainStatus
+	}
+
+	switch msg.GetHeader().GetType() {
+	case protos.XuperMessage_POSTTX:
+		var tx lpb.Transaction
+		if err := p2p.Unmarshal(msg, &tx); err != nil {
+			return err
+		}
+		return e.verifyTx(chain, &tx)
+	case protos.XuperMessage_BATCHPOSTTX:
+		var input xpb.Transactions
+		if err := p2p.Unmarshal(msg, &input); err != nil {
+			return err
+		}
+		for _, tx := range input.Txs {
+			if err := e.verifyTx(chain, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	case protos.XuperMessage_SENDBLOCK:
+		var block lpb.InternalBlock
+		if err := p2p.Unmarshal(msg, &block); err != nil {
+			return err
+		}
+		if err := validateSendBlock(&block); err != nil {
+			return err
+		}
+		ok, err := chain.Context().Ledger.VerifyBlock(&block, msg.GetHeader().GetLogid())
+		if err != nil || !ok {
+			return ErrBlockInvalid
+		}
+		return nil
+	case protos.XuperMessage_NEW_BLOCKID:
+		var block lpb.InternalBlock
+		if err := p2p.Unmarshal(msg, &block); err != nil {
+			return err
+		}
+		return validateSendBlock(&block)
+	}
+	return nil
+}
+
+// verifyTx 校验交易的签名和权限
+func (e *Event) verifyTx(chain common.Chain, tx *lpb.Transaction) error {
+	if err := validatePostTx(tx); err != nil {
+		return err
+	}
+	ok, err := chain.Context().State.VerifyTx(tx)
+	if err != nil {
+		return common.ErrTxVerifyFailed.More("%v", err)
+	}
+	if !ok {
+		return common.ErrTxVerifyFailed
+	}
+	return nil
+}
+
+// reportPeer 上报消息来源节点的行为，用于节点评分
+func (e *Event) reportPeer(request *protos.XuperMessage, event p2p.PeerEvent) {
+	e.net().ReportPeer(request.GetHeader().GetFrom(), event)
+}
+
+// isInvalidTx 交易本身不合法，重复交易等正常广播中会出现的错误不算
+func isInvalidTx(err error) bool {
+	cerr := common.CastError(err)
+	return cerr.Equal(common.ErrParameter) || cerr.Equal(common.ErrTxVerifyFailed)
+}
+
+// isInvalidBlock 区块本身不合法，节点状态导致的处理失败不算
+func isInvalidBlock(err error) bool {
+	return err == ErrBlockNil || err == ErrBlockIDNil
+}
+
 // sendMessage wrapper function which ignore error
 func (e *Event) sendMessage(ctx xctx.XContext, msg *protos.XuperMessage, of ...p2p.OptionFunc) {
 	_ = e.net().SendMessage(ctx, msg, of...)
@@ -442,6 +556,62 @@ func (e *Event) handleGetBlockHeaders(ctx xctx.XContext,
 	return response(nil)
 }
 
+// handleGetBlockIds 返回主干上从指定高度开始的blockid列表，供对端先行校验区块头链
+func (e *Event) handleGetBlockIds(ctx xctx.XContext,
+	request *protos.XuperMessage) (*protos.XuperMessage, error) {
+
+	output := new(xpb.GetBlockIdsResponse)
+	defer func(begin time.Time) {
+		metrics.CallMethodHistogram.WithLabelValues("sync", "p2pGetBlockIds").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	bcName := request.Header.Bcname
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	var input xpb.GetBlockIdsRequest
+	err := p2p.Unmarshal(request, &input)
+	if err != nil {
+		ctx.GetLog().Error("unmarshal error", "bcName", bcName, "error", err)
+		return response(common.ErrParameter)
+	}
+
+	chain, err := e.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return response(common.ErrChainNotExist)
+	}
+
+	if input.Height < 0 || input.Size <= 0 {
+		return response(common.ErrParameter)
+	}
+	size := input.Size
+	if size > common.MaxSyncBlockIdsSize {
+		size = common.MaxSyncBlockIdsSize
+	}
+
+	ledger := chain.Context().Ledger
+	tipHeight := ledger.GetMeta().GetTrunkHeight()
+	for height := input.Height; height < input.Height+size && height <= tipHeight; height++ {
+		block, err := ledger.QueryBlockHeaderByHeight(height)
+		if err != nil {
+			// 查询过程中主干发生回滚时，只返回已查到的部分
+			ctx.GetLog().Debug("query block header error", "error", err, "height", height)
+			break
+		}
+		output.Blockids = append(output.Blockids, block.GetBlockid())
+	}
+
+	return response(nil)
+}
+
 func (e *Event) handleGetBlockTxs(ctx xctx.XContext,
 	request *protos.XuperMessage) (*protos.XuperMessage, error) {
 
@@ -498,6 +668,89 @@ func (e *Event) handleGetBlockTxs(ctx xctx.XContext,
 	return response(nil)
 }
 
+// handleGetStateSnapshot 返回本节点最新的可供同步的状态快照
+func (e *Event) handleGetStateSnapshot(ctx xctx.XContext,
+	request *protos.XuperMessage) (*protos.XuperMessage, error) {
+
+	output := new(xpb.StateSnapshot)
+	bcName := request.Header.Bcname
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	chain, err := e.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return response(common.ErrChainNotExist)
+	}
+
+	snapshotAgent := chain.Context().StateSnapshot
+	if snapshotAgent == nil {
+		return response(common.ErrSnapshotNotExist)
+	}
+	snapshot, err := snapshotAgent.GetLatestSnapshot()
+	if err != nil {
+		ctx.GetLog().Debug("get state snapshot error", "error", err, "bcName", bcName)
+		return response(err)
+	}
+	output = snapshot
+
+	return response(nil)
+}
+
+// handleGetStateChunk 返回状态快照的指定分片
+func (e *Event) handleGetStateChunk(ctx xctx.XContext,
+	request *protos.XuperMessage) (*protos.XuperMessage, error) {
+
+	output := new(xpb.GetStateChunkResponse)
+	defer func(begin time.Time) {
+		metrics.CallMethodHistogram.WithLabelValues("sync", "p2pGetStateChunk").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	bcName := request.Header.Bcname
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	var input xpb.GetStateChunkRequest
+	err := p2p.Unmarshal(request, &input)
+	if err != nil {
+		ctx.GetLog().Error("unmarshal error", "bcName", bcName, "error", err)
+		return response(common.ErrParameter)
+	}
+
+	chain, err := e.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return response(common.ErrChainNotExist)
+	}
+
+	snapshotAgent := chain.Context().StateSnapshot
+	if snapshotAgent == nil {
+		return response(common.ErrSnapshotNotExist)
+	}
+	chunk, err := snapshotAgent.GetSnapshotChunk(input.Blockid, input.Index)
+	if err != nil {
+		ctx.GetLog().Warn("get state chunk error", "error", err, "blockid", utils.F(input.Blockid), "index", input.Index)
+		return response(err)
+	}
+	output.Chunk = chunk
+
+	return response(nil)
+}
+
 func (e *Event) handleGetChainStatus(ctx xctx.XContext, request *protos.XuperMessage) (*protos.XuperMessage, error) {
 	var output *xpb.ChainStatus
 
@@ -578,3 +831,42 @@ func (e *Event) handleConfirmChainStatus(ctx xctx.XContext,
 func (e *Event) net() network.Network {
 	return e.engine.Context().Net
 }
+
+// handleCrossQuery 在本节点运行的目标链上执行跨链查询，返回本节点背书的查询结果
+func (e *Event) handleCrossQuery(ctx xctx.XContext,
+	request *protos.XuperMessage) (*protos.XuperMessage, error) {
+
+	output := new(protos.CrossQueryInfo)
+	bcName := request.Header.Bcname
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	chain, err := e.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return response(common.ErrChainNotExist)
+	}
+
+	var input protos.CrossQueryRequest
+	err = p2p.Unmarshal(request, &input)
+	if err != nil || input.GetBcname() != bcName {
+		ctx.GetLog().Warn("unmarshal error", "bcName", bcName, "error", err)
+		return response(common.ErrParameter)
+	}
+
+	info, err := agent.NewCrossQueryAgent(chain.Context()).Endorse(&input)
+	if err != nil {
+		ctx.GetLog().Debug("cross query endorse error", "error", err, "bcName", bcName)
+		return response(err)
+	}
+	output = info
+
+	return response(nil)
+}
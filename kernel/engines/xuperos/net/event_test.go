@@ -96,6 +96,13 @@ func (m mockNet) PeerInfo() protos.PeerInfo {
 	panic("implement me")
 }
 
+func (m mockNet) ReportPeer(peerID string, event p2p.PeerEvent) {
+}
+
+func (m mockNet) PeerScores() []*p2p.PeerScore {
+	panic("implement me")
+}
+
 func TestNetEvent_sendMessage(t *testing.T) {
 	type fields struct {
 		log      logs.Logger
@@ -19,6 +19,8 @@ var (
 	ErrBlockNil = errors.New("validation error: validateSendBlock Block.Block can't be null")
 	// ErrTxInvalid is returned when tx invaild
 	ErrTxInvalid = errors.New("validation error: tx info is invaild")
+	// ErrBlockInvalid is returned when block id, merkle root or signature invalid
+	ErrBlockInvalid = errors.New("validation error: block info is invalid")
 )
 
 func validatePostTx(tx *lpb.Transaction) error {
@@ -26,6 +26,8 @@ type ContractReader interface {
 	QueryAccountGovernTokenBalance(account string) (*protos.GovernTokenBalance, error)
 	//
 	GetContractDesc(name string) (*protos.WasmCodeDesc, error)
+	// 查询evm合约abi
+	GetContractAbi(name string) ([]byte, error)
 }
 
 type contractReader struct {
@@ -141,3 +143,10 @@ func (t *contractReader) GetContractDesc(name string) (*protos.WasmCodeDesc, err
 	}
 	return t.chainCtx.State.GetContractDesc(name)
 }
+
+func (t *contractReader) GetContractAbi(name string) ([]byte, error) {
+	if name == "" {
+		return nil, errors.New("contract name can not be empty")
+	}
+	return t.chainCtx.State.GetContractAbi(name)
+}
@@ -59,6 +59,9 @@ func (t *ledgerReader) QueryTx(txId []byte) (*xpb.TxInfo, error) {
 			out.Tx = tx
 			return out, nil
 		}
+		if err == ledger.ErrTxPruned {
+			return nil, common.ErrTxPruned
+		}
 
 		return nil, common.ErrTxNotExist
 	}
@@ -87,11 +90,18 @@ func (t *ledgerReader) QueryTx(txId []byte) (*xpb.TxInfo, error) {
 func (t *ledgerReader) QueryBlock(blkId []byte, needContent bool) (*xpb.BlockInfo, error) {
 	out := &xpb.BlockInfo{}
 	block, err := t.chainCtx.Ledger.QueryBlock(blkId)
+	if err == ledger.ErrBlockPruned && !needContent {
+		// 交易详情已裁剪，不需要交易内容时只查询区块头
+		block, err = t.chainCtx.Ledger.QueryBlockHeader(blkId)
+	}
 	if err != nil {
 		if err == ledger.ErrBlockNotExist {
 			out.Status = lpb.BlockStatus_BLOCK_NOEXIST
 			return out, common.ErrBlockNotExist
 		}
+		if err == ledger.ErrBlockPruned {
+			return nil, common.ErrBlockPruned
+		}
 
 		t.log.Warn("query block error", "err", err)
 		return nil, common.ErrBlockNotExist
@@ -137,11 +147,18 @@ func (t *ledgerReader) QueryBlockHeader(blkId []byte) (*xpb.BlockInfo, error) {
 func (t *ledgerReader) QueryBlockByHeight(height int64, needContent bool) (*xpb.BlockInfo, error) {
 	out := &xpb.BlockInfo{}
 	block, err := t.chainCtx.Ledger.QueryBlockByHeight(height)
+	if err == ledger.ErrBlockPruned && !needContent {
+		// 交易详情已裁剪，不需要交易内容时只查询区块头
+		block, err = t.chainCtx.Ledger.QueryBlockHeaderByHeight(height)
+	}
 	if err != nil {
 		if err == ledger.ErrBlockNotExist {
 			out.Status = lpb.BlockStatus_BLOCK_NOEXIST
 			return out, nil
 		}
+		if err == ledger.ErrBlockPruned {
+			return nil, common.ErrBlockPruned
+		}
 
 		t.log.Warn("query block by height error", "err", err)
 		return nil, common.ErrBlockNotExist
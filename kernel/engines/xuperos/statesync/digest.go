@@ -0,0 +1,160 @@
+package statesync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	stdhash "hash"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
+)
+
+const snapshotSignPrefix = "state-snapshot"
+
+var (
+	ErrInvalidSnapshot   = errors.New("invalid state snapshot")
+	ErrDigestMissMatch   = errors.New("state snapshot digest miss match")
+	ErrSnapshotterExit   = errors.New("snapshotter exit")
+	ErrSnapshotNotSigned = errors.New("state snapshot not signed by enough validators")
+)
+
+// MakeChunkDigest 计算分片数据的哈希
+func MakeChunkDigest(chunk []byte) []byte {
+	return hash.DoubleSha256(chunk)
+}
+
+// StateDigest 按顺序计算快照区块和全部状态数据的摘要，与分片大小无关，
+// 各节点在同一区块导出的快照摘要一致，快速同步时要求多个节点提供相同的摘要，导入时重新计算校验
+type StateDigest struct {
+	h stdhash.Hash
+}
+
+func NewStateDigest(blockid []byte) *StateDigest {
+	d := &StateDigest{h: sha256.New()}
+	d.write(blockid)
+	return d
+}
+
+// Write 按顺序写入一条状态数据
+func (d *StateDigest) Write(key, value []byte) {
+	d.write(key)
+	d.write(value)
+}
+
+// Sum 返回摘要，与hash.DoubleSha256一致对数据做两次sha256
+func (d *StateDigest) Sum() []byte {
+	return hash.UsingSha256(d.h.Sum(nil))
+}
+
+// write 写入带长度前缀的数据，避免不同的key/value切分得到相同的摘要
+func (d *StateDigest) write(data []byte) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(data)))
+	d.h.Write(buf[:n])
+	d.h.Write(data)
+}
+
+// VerifySnapshot 校验快照描述信息，区块id使用链配置的哈希算法计算
+// 快照摘要覆盖全部状态数据，只能在导入时重新计算校验
+func VerifySnapshot(snapshot *xpb.StateSnapshot, hasher *hash.Hasher) error {
+	block := snapshot.GetBlock()
+	if block == nil || len(snapshot.GetChunkDigests()) == 0 || len(snapshot.GetDigest()) != sha256.Size {
+		return ErrInvalidSnapshot
+	}
+	blkid, err := ledger.MakeBlockIDWithHasher(block, hasher)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(blkid, block.GetBlockid()) {
+		return ErrInvalidSnapshot
+	}
+	return nil
+}
+
+// VerifyChunk 校验分片数据与快照中记录的分片哈希是否一致
+func VerifyChunk(snapshot *xpb.StateSnapshot, index int64, chunk []byte) error {
+	if index < 0 || index >= int64(len(snapshot.GetChunkDigests())) {
+		return ErrInvalidSnapshot
+	}
+	if !bytes.Equal(MakeChunkDigest(chunk), snapshot.GetChunkDigests()[index]) {
+		return ErrDigestMissMatch
+	}
+	return nil
+}
+
+// MakeSnapshotSignDigest 计算验证人签名的内容，包括链名、快照区块和快照摘要
+func MakeSnapshotSignDigest(bcName string, snapshot *xpb.StateSnapshot, hasher *hash.Hasher) ([]byte, error) {
+	var msgBuf bytes.Buffer
+	encoder := json.NewEncoder(&msgBuf)
+	fields := []interface{}{snapshotSignPrefix, bcName, snapshot.GetBlock().GetHeight(),
+		snapshot.GetBlock().GetBlockid(), snapshot.GetDigest()}
+	for _, field := range fields {
+		if err := encoder.Encode(field); err != nil {
+			return nil, err
+		}
+	}
+	return hasher.DoubleHash(msgBuf.Bytes()), nil
+}
+
+// SignSnapshot 使用节点账户对快照签名
+func SignSnapshot(bcName string, snapshot *xpb.StateSnapshot, addr *xaddress.Address,
+	crypto cryptoBase.CryptoClient, hasher *hash.Hasher) (*xpb.SnapshotSign, error) {
+	digest, err := MakeSnapshotSignDigest(bcName, snapshot, hasher)
+	if err != nil {
+		return nil, err
+	}
+	sign, err := crypto.SignECDSA(addr.PrivateKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	return &xpb.SnapshotSign{
+		Address:   addr.Address,
+		PublicKey: addr.PublicKeyStr,
+		Sign:      sign,
+	}, nil
+}
+
+// VerifySnapshotSigns 校验快照签名，validators中超过半数的验证人签名有效时通过
+// 签名可能来自多个节点的响应，非验证人签名和无效签名不计数，同一验证人只计一次
+func VerifySnapshotSigns(bcName string, snapshot *xpb.StateSnapshot, validators []string,
+	crypto cryptoBase.CryptoClient, hasher *hash.Hasher) error {
+	if len(validators) == 0 {
+		return ErrSnapshotNotSigned
+	}
+	digest, err := MakeSnapshotSignDigest(bcName, snapshot, hasher)
+	if err != nil {
+		return err
+	}
+	isValidator := make(map[string]bool, len(validators))
+	for _, v := range validators {
+		isValidator[v] = true
+	}
+	signed := make(map[string]bool)
+	for _, sign := range snapshot.GetSigns() {
+		if !isValidator[sign.GetAddress()] || signed[sign.GetAddress()] {
+			continue
+		}
+		pk, err := crypto.GetEcdsaPublicKeyFromJsonStr(sign.GetPublicKey())
+		if err != nil {
+			continue
+		}
+		addr, err := crypto.GetAddressFromPublicKey(pk)
+		if err != nil || addr != sign.GetAddress() {
+			continue
+		}
+		if ok, err := crypto.VerifyECDSA(pk, sign.GetSign(), digest); err != nil || !ok {
+			continue
+		}
+		signed[sign.GetAddress()] = true
+	}
+	if len(signed)*2 <= len(isValidator) {
+		return ErrSnapshotNotSigned
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+// 状态快照，用于新节点快速同步
+// 节点在区块高度为导出周期整数倍时导出状态机全量数据并签名，已进入不可逆区间且仍在主干上的快照可以对外提供同步
+// 验证人需要配置相同的导出周期，新节点才能收集到多数验证人对同一快照的签名
+package statesync
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	// 快照存储目录名
+	SnapshotDirName = "snapshot"
+	// 快照描述信息文件名
+	manifestFileName = "manifest"
+	// 分片文件名前缀
+	chunkFilePrefix = "chunk_"
+	// 导出中的临时目录后缀
+	tmpDirSuffix = ".tmp"
+
+	// 默认分片大小
+	defaultChunkSize = 4 * 1024 * 1024
+	// 账本未配置不可逆区间时，快照可对外提供同步需要的最小确认区块数
+	defaultIrreversibleDepth = 100
+)
+
+// Snapshotter 负责状态快照的导出、管理和读取
+type Snapshotter struct {
+	ctx       *common.ChainCtx
+	log       logs.Logger
+	dir       string
+	interval  int64
+	chunkSize int
+	retain    int
+
+	// 本地快照列表，按高度从低到高排列
+	snapshots []*xpb.StateSnapshot
+	mutex     sync.RWMutex
+	// 是否有快照正在后台导出
+	exporting int32
+	exitCh    chan struct{}
+	exitWG    sync.WaitGroup
+}
+
+func NewSnapshotter(ctx *common.ChainCtx) (*Snapshotter, error) {
+	engCfg := ctx.EngCtx.EngCfg
+	dir := filepath.Join(ctx.EngCtx.EnvCfg.GenDataAbsPath(ctx.EngCtx.EnvCfg.ChainDir), ctx.BCName, SnapshotDirName)
+	s := &Snapshotter{
+		ctx:       ctx,
+		log:       ctx.XLog,
+		dir:       dir,
+		interval:  engCfg.StateSnapshotInterval,
+		chunkSize: engCfg.StateSnapshotChunkSize,
+		retain:    engCfg.StateSnapshotRetain,
+		exitCh:    make(chan struct{}),
+	}
+	if s.chunkSize <= 0 {
+		s.chunkSize = defaultChunkSize
+	}
+	if s.retain <= 0 {
+		s.retain = 1
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Snapshotter) Stop() {
+	close(s.exitCh)
+	s.exitWG.Wait()
+}
+
+// OnConfirmBlock 状态机确认区块后调用，区块高度为导出周期的整数倍时导出快照
+// 各节点在相同高度导出，同一区块的快照摘要一致。数据迭代器在调用时创建，写入快照文件在后台进行
+func (s *Snapshotter) OnConfirmBlock(block *lpb.InternalBlock) {
+	if s.interval <= 0 || block.GetHeight() <= 0 || block.GetHeight()%s.interval != 0 {
+		return
+	}
+	// 上一个快照还在导出时跳过本次导出
+	if !atomic.CompareAndSwapInt32(&s.exporting, 0, 1) {
+		s.log.Warn("state snapshot is exporting, skip", "height", block.GetHeight())
+		return
+	}
+
+	blockid, iter, err := s.ctx.State.NewSnapshotIterator()
+	if err != nil {
+		atomic.StoreInt32(&s.exporting, 0)
+		s.log.Warn("create state snapshot iterator failed", "height", block.GetHeight(), "err", err)
+		return
+	}
+	// 状态机执行区块失败时不导出
+	if !bytes.Equal(blockid, block.GetBlockid()) {
+		iter.Release()
+		atomic.StoreInt32(&s.exporting, 0)
+		s.log.Warn("state not match confirmed block, skip export snapshot", "height", block.GetHeight(),
+			"blockid", utils.F(block.GetBlockid()), "state", utils.F(blockid))
+		return
+	}
+
+	s.exitWG.Add(1)
+	go func() {
+		defer s.exitWG.Done()
+		defer atomic.StoreInt32(&s.exporting, 0)
+		if _, err := s.export(blockid, iter); err != nil {
+			s.log.Warn("export state snapshot failed", "height", block.GetHeight(), "err", err)
+		}
+	}()
+}
+
+// Export 导出状态机当前数据作为快照
+func (s *Snapshotter) Export() (*xpb.StateSnapshot, error) {
+	blockid, iter, err := s.ctx.State.NewSnapshotIterator()
+	if err != nil {
+		return nil, err
+	}
+	return s.export(blockid, iter)
+}
+
+// export 将迭代器中的数据按分片写入快照目录，写入完成后释放迭代器
+func (s *Snapshotter) export(blockid []byte, iter kvdb.Iterator) (*xpb.StateSnapshot, error) {
+	defer iter.Release()
+
+	block, err := s.ctx.Ledger.QueryBlockHeader(blockid)
+	if err != nil {
+		return nil, err
+	}
+	header := proto.Clone(block).(*lpb.InternalBlock)
+	header.Transactions = nil
+
+	snapDir := filepath.Join(s.dir, snapshotDirName(header))
+	tmpDir := snapDir + tmpDirSuffix
+	os.RemoveAll(tmpDir)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	beginTime := time.Now()
+	snapshot := &xpb.StateSnapshot{
+		Bcname: s.ctx.BCName,
+		Block:  header,
+	}
+	chunk := &xpb.StateChunk{}
+	chunkSize := 0
+	flush := func() error {
+		data, err := proto.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		fileName := filepath.Join(tmpDir, fmt.Sprintf("%s%d", chunkFilePrefix, chunk.Index))
+		if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+			return err
+		}
+		snapshot.ChunkDigests = append(snapshot.ChunkDigests, MakeChunkDigest(data))
+		chunk = &xpb.StateChunk{Index: chunk.Index + 1}
+		chunkSize = 0
+		return nil
+	}
+
+	// 已确认交易只记录一次
+	exportedTxs := map[string]bool{}
+	digest := NewStateDigest(header.Blockid)
+	for iter.Next() {
+		select {
+		case <-s.exitCh:
+			return nil, ErrSnapshotterExit
+		default:
+		}
+		kv := &xpb.StateKV{
+			Key:   append([]byte{}, iter.Key()...),
+			Value: append([]byte{}, iter.Value()...),
+		}
+		chunk.Kvs = append(chunk.Kvs, kv)
+		digest.Write(kv.Key, kv.Value)
+		chunkSize += len(kv.Key) + len(kv.Value)
+
+		// xmodel数据存储在交易中，需要一并导出引用的已确认交易
+		if bytes.HasPrefix(kv.Key, []byte(lpb.ExtUtxoTablePrefix)) ||
+			bytes.HasPrefix(kv.Key, []byte(lpb.ExtUtxoDelTablePrefix)) {
+			txid := xmodel.GetTxidFromVersion(string(kv.Value))
+			if !exportedTxs[string(txid)] {
+				exportedTxs[string(txid)] = true
+				tx, err := s.ctx.Ledger.QueryTransaction(txid)
+				if err != nil && err != ledger.ErrTxNotFound {
+					return nil, err
+				}
+				// 未确认交易在状态机未确认表中，已包含在快照数据里
+				if tx != nil {
+					chunk.Txs = append(chunk.Txs, tx)
+					chunkSize += proto.Size(tx)
+				}
+			}
+		}
+		if chunkSize >= s.chunkSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	if len(chunk.Kvs) > 0 || len(snapshot.ChunkDigests) == 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	snapshot.Digest = digest.Sum()
+	// 节点对导出的快照签名，新节点要求多数验证人签名相同的快照
+	if s.ctx.Address != nil && s.ctx.Crypto != nil {
+		sign, err := SignSnapshot(s.ctx.BCName, snapshot, s.ctx.Address, s.ctx.Crypto, s.ctx.Ledger.GetHasher())
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Signs = []*xpb.SnapshotSign{sign}
+	}
+
+	manifest, err := proto.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, manifestFileName), manifest, 0644); err != nil {
+		return nil, err
+	}
+	os.RemoveAll(snapDir)
+	if err := os.Rename(tmpDir, snapDir); err != nil {
+		return nil, err
+	}
+	s.log.Info("export state snapshot succ", "height", header.Height, "blockid", utils.F(header.Blockid),
+		"chunks", len(snapshot.ChunkDigests), "costs", time.Since(beginTime))
+
+	s.add(snapshot)
+	return snapshot, nil
+}
+
+// GetLatestSnapshot 获取最新的可供同步的状态快照
+// 快照区块需要进入不可逆区间，并且仍在主干上
+func (s *Snapshotter) GetLatestSnapshot() (*xpb.StateSnapshot, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	depth := s.ctx.Ledger.GetIrreversibleSlideWindow()
+	if depth <= 0 {
+		depth = defaultIrreversibleDepth
+	}
+	trunkHeight := s.ctx.Ledger.GetMeta().GetTrunkHeight()
+	for i := len(s.snapshots) - 1; i >= 0; i-- {
+		block := s.snapshots[i].GetBlock()
+		if trunkHeight-block.GetHeight() < depth {
+			continue
+		}
+		trunkBlock, err := s.ctx.Ledger.QueryBlockHeaderByHeight(block.GetHeight())
+		if err != nil || !bytes.Equal(trunkBlock.GetBlockid(), block.GetBlockid()) {
+			continue
+		}
+		return s.snapshots[i], nil
+	}
+	return nil, common.ErrSnapshotNotExist
+}
+
+// GetSnapshotChunk 获取状态快照的序列化分片数据
+func (s *Snapshotter) GetSnapshotChunk(blockid []byte, index int64) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, snapshot := range s.snapshots {
+		if !bytes.Equal(snapshot.GetBlock().GetBlockid(), blockid) {
+			continue
+		}
+		if index < 0 || index >= int64(len(snapshot.GetChunkDigests())) {
+			return nil, common.ErrParameter
+		}
+		fileName := filepath.Join(s.dir, snapshotDirName(snapshot.GetBlock()),
+			fmt.Sprintf("%s%d", chunkFilePrefix, index))
+		return ioutil.ReadFile(fileName)
+	}
+	return nil, common.ErrSnapshotNotExist
+}
+
+// load 加载本地已导出的快照
+func (s *Snapshotter) load() error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if !info.IsDir() || strings.HasSuffix(info.Name(), tmpDirSuffix) {
+			continue
+		}
+		manifest, err := ioutil.ReadFile(filepath.Join(s.dir, info.Name(), manifestFileName))
+		if err != nil {
+			s.log.Warn("read state snapshot manifest failed", "dir", info.Name(), "err", err)
+			continue
+		}
+		snapshot := &xpb.StateSnapshot{}
+		if err := proto.Unmarshal(manifest, snapshot); err != nil || VerifySnapshot(snapshot, s.ctx.Ledger.GetHasher()) != nil {
+			s.log.Warn("state snapshot manifest corrupted", "dir", info.Name())
+			continue
+		}
+		s.snapshots = append(s.snapshots, snapshot)
+	}
+	sort.Slice(s.snapshots, func(i, j int) bool {
+		return s.snapshots[i].GetBlock().GetHeight() < s.snapshots[j].GetBlock().GetHeight()
+	})
+	return nil
+}
+
+// add 记录新导出的快照，超出保留个数的旧快照会被删除
+func (s *Snapshotter) add(snapshot *xpb.StateSnapshot) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	snapshots := make([]*xpb.StateSnapshot, 0, len(s.snapshots)+1)
+	for _, old := range s.snapshots {
+		if !bytes.Equal(old.GetBlock().GetBlockid(), snapshot.GetBlock().GetBlockid()) {
+			snapshots = append(snapshots, old)
+		}
+	}
+	snapshots = append(snapshots, snapshot)
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].GetBlock().GetHeight() < snapshots[j].GetBlock().GetHeight()
+	})
+	for len(snapshots) > s.retain {
+		os.RemoveAll(filepath.Join(s.dir, snapshotDirName(snapshots[0].GetBlock())))
+		snapshots = snapshots[1:]
+	}
+	s.snapshots = snapshots
+}
+
+func snapshotDirName(block *lpb.InternalBlock) string {
+	return fmt.Sprintf("%020d_%x", block.GetHeight(), block.GetBlockid())
+}
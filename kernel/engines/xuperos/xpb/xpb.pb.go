@@ -526,6 +526,102 @@ func (m *GetBlockHeaderResponse) GetBlocks() []*xldgpb.InternalBlock {
 	return nil
 }
 
+// GetBlockIdsRequest 查询主干上从height开始的size个区块的blockid
+type GetBlockIdsRequest struct {
+	Bcname               string   `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	Height               int64    `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Size                 int64    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBlockIdsRequest) Reset()         { *m = GetBlockIdsRequest{} }
+func (m *GetBlockIdsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockIdsRequest) ProtoMessage()    {}
+func (*GetBlockIdsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{10}
+}
+
+func (m *GetBlockIdsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlockIdsRequest.Unmarshal(m, b)
+}
+func (m *GetBlockIdsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlockIdsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBlockIdsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlockIdsRequest.Merge(m, src)
+}
+func (m *GetBlockIdsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBlockIdsRequest.Size(m)
+}
+func (m *GetBlockIdsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlockIdsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlockIdsRequest proto.InternalMessageInfo
+
+func (m *GetBlockIdsRequest) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *GetBlockIdsRequest) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *GetBlockIdsRequest) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type GetBlockIdsResponse struct {
+	// 按高度顺序排列，长度小于size时表示对端主干没有更多区块
+	Blockids             [][]byte `protobuf:"bytes,1,rep,name=blockids,proto3" json:"blockids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBlockIdsResponse) Reset()         { *m = GetBlockIdsResponse{} }
+func (m *GetBlockIdsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBlockIdsResponse) ProtoMessage()    {}
+func (*GetBlockIdsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{11}
+}
+
+func (m *GetBlockIdsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlockIdsResponse.Unmarshal(m, b)
+}
+func (m *GetBlockIdsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlockIdsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBlockIdsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlockIdsResponse.Merge(m, src)
+}
+func (m *GetBlockIdsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBlockIdsResponse.Size(m)
+}
+func (m *GetBlockIdsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlockIdsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlockIdsResponse proto.InternalMessageInfo
+
+func (m *GetBlockIdsResponse) GetBlockids() [][]byte {
+	if m != nil {
+		return m.Blockids
+	}
+	return nil
+}
+
 type GetBlockTxsRequest struct {
 	Bcname               string   `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
 	Blockid              []byte   `protobuf:"bytes,2,opt,name=blockid,proto3" json:"blockid,omitempty"`
@@ -539,7 +635,7 @@ func (m *GetBlockTxsRequest) Reset()         { *m = GetBlockTxsRequest{} }
 func (m *GetBlockTxsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetBlockTxsRequest) ProtoMessage()    {}
 func (*GetBlockTxsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{10}
+	return fileDescriptor_e9685bde11a1952e, []int{12}
 }
 
 func (m *GetBlockTxsRequest) XXX_Unmarshal(b []byte) error {
@@ -592,7 +688,7 @@ func (m *GetBlockTxsResponse) Reset()         { *m = GetBlockTxsResponse{} }
 func (m *GetBlockTxsResponse) String() string { return proto.CompactTextString(m) }
 func (*GetBlockTxsResponse) ProtoMessage()    {}
 func (*GetBlockTxsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{11}
+	return fileDescriptor_e9685bde11a1952e, []int{13}
 }
 
 func (m *GetBlockTxsResponse) XXX_Unmarshal(b []byte) error {
@@ -620,6 +716,377 @@ func (m *GetBlockTxsResponse) GetTxs() []*xldgpb.Transaction {
 	return nil
 }
 
+type GetStateSnapshotRequest struct {
+	Bcname               string   `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateSnapshotRequest) Reset()         { *m = GetStateSnapshotRequest{} }
+func (m *GetStateSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateSnapshotRequest) ProtoMessage()    {}
+func (*GetStateSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{14}
+}
+
+func (m *GetStateSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateSnapshotRequest.Unmarshal(m, b)
+}
+func (m *GetStateSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStateSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateSnapshotRequest.Merge(m, src)
+}
+func (m *GetStateSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateSnapshotRequest.Size(m)
+}
+func (m *GetStateSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateSnapshotRequest proto.InternalMessageInfo
+
+func (m *GetStateSnapshotRequest) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+// StateSnapshot 状态快照描述信息
+type StateSnapshot struct {
+	Bcname string `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	// 快照对应的区块头
+	Block *xldgpb.InternalBlock `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	// 各分片数据的哈希
+	ChunkDigests [][]byte `protobuf:"bytes,3,rep,name=chunk_digests,json=chunkDigests,proto3" json:"chunk_digests,omitempty"`
+	// 快照摘要，由区块id和全部状态数据按顺序计算得到，与分片大小无关
+	Digest []byte `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+	// 验证人对快照区块和摘要的签名，新节点要求多数可信验证人签名
+	Signs                []*SnapshotSign `protobuf:"bytes,5,rep,name=signs,proto3" json:"signs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *StateSnapshot) Reset()         { *m = StateSnapshot{} }
+func (m *StateSnapshot) String() string { return proto.CompactTextString(m) }
+func (*StateSnapshot) ProtoMessage()    {}
+func (*StateSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{15}
+}
+
+func (m *StateSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateSnapshot.Unmarshal(m, b)
+}
+func (m *StateSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateSnapshot.Marshal(b, m, deterministic)
+}
+func (m *StateSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateSnapshot.Merge(m, src)
+}
+func (m *StateSnapshot) XXX_Size() int {
+	return xxx_messageInfo_StateSnapshot.Size(m)
+}
+func (m *StateSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateSnapshot proto.InternalMessageInfo
+
+func (m *StateSnapshot) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *StateSnapshot) GetBlock() *xldgpb.InternalBlock {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *StateSnapshot) GetChunkDigests() [][]byte {
+	if m != nil {
+		return m.ChunkDigests
+	}
+	return nil
+}
+
+func (m *StateSnapshot) GetDigest() []byte {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
+func (m *StateSnapshot) GetSigns() []*SnapshotSign {
+	if m != nil {
+		return m.Signs
+	}
+	return nil
+}
+
+// SnapshotSign 验证人对状态快照的签名
+type SnapshotSign struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	PublicKey            string   `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Sign                 []byte   `protobuf:"bytes,3,opt,name=sign,proto3" json:"sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotSign) Reset()         { *m = SnapshotSign{} }
+func (m *SnapshotSign) String() string { return proto.CompactTextString(m) }
+func (*SnapshotSign) ProtoMessage()    {}
+func (*SnapshotSign) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{16}
+}
+
+func (m *SnapshotSign) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotSign.Unmarshal(m, b)
+}
+func (m *SnapshotSign) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotSign.Marshal(b, m, deterministic)
+}
+func (m *SnapshotSign) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotSign.Merge(m, src)
+}
+func (m *SnapshotSign) XXX_Size() int {
+	return xxx_messageInfo_SnapshotSign.Size(m)
+}
+func (m *SnapshotSign) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotSign.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotSign proto.InternalMessageInfo
+
+func (m *SnapshotSign) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SnapshotSign) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *SnapshotSign) GetSign() []byte {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+type StateKV struct {
+	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateKV) Reset()         { *m = StateKV{} }
+func (m *StateKV) String() string { return proto.CompactTextString(m) }
+func (*StateKV) ProtoMessage()    {}
+func (*StateKV) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{17}
+}
+
+func (m *StateKV) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateKV.Unmarshal(m, b)
+}
+func (m *StateKV) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateKV.Marshal(b, m, deterministic)
+}
+func (m *StateKV) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateKV.Merge(m, src)
+}
+func (m *StateKV) XXX_Size() int {
+	return xxx_messageInfo_StateKV.Size(m)
+}
+func (m *StateKV) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateKV.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateKV proto.InternalMessageInfo
+
+func (m *StateKV) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StateKV) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// StateChunk 状态快照分片
+type StateChunk struct {
+	Index int64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// 状态机存储的原始kv数据
+	Kvs []*StateKV `protobuf:"bytes,2,rep,name=kvs,proto3" json:"kvs,omitempty"`
+	// 分片内xmodel数据引用的已确认交易
+	Txs                  []*xldgpb.Transaction `protobuf:"bytes,3,rep,name=txs,proto3" json:"txs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *StateChunk) Reset()         { *m = StateChunk{} }
+func (m *StateChunk) String() string { return proto.CompactTextString(m) }
+func (*StateChunk) ProtoMessage()    {}
+func (*StateChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{18}
+}
+
+func (m *StateChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateChunk.Unmarshal(m, b)
+}
+func (m *StateChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateChunk.Marshal(b, m, deterministic)
+}
+func (m *StateChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateChunk.Merge(m, src)
+}
+func (m *StateChunk) XXX_Size() int {
+	return xxx_messageInfo_StateChunk.Size(m)
+}
+func (m *StateChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateChunk proto.InternalMessageInfo
+
+func (m *StateChunk) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *StateChunk) GetKvs() []*StateKV {
+	if m != nil {
+		return m.Kvs
+	}
+	return nil
+}
+
+func (m *StateChunk) GetTxs() []*xldgpb.Transaction {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+type GetStateChunkRequest struct {
+	Bcname               string   `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	Blockid              []byte   `protobuf:"bytes,2,opt,name=blockid,proto3" json:"blockid,omitempty"`
+	Index                int64    `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateChunkRequest) Reset()         { *m = GetStateChunkRequest{} }
+func (m *GetStateChunkRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateChunkRequest) ProtoMessage()    {}
+func (*GetStateChunkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{19}
+}
+
+func (m *GetStateChunkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateChunkRequest.Unmarshal(m, b)
+}
+func (m *GetStateChunkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateChunkRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStateChunkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateChunkRequest.Merge(m, src)
+}
+func (m *GetStateChunkRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateChunkRequest.Size(m)
+}
+func (m *GetStateChunkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateChunkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateChunkRequest proto.InternalMessageInfo
+
+func (m *GetStateChunkRequest) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *GetStateChunkRequest) GetBlockid() []byte {
+	if m != nil {
+		return m.Blockid
+	}
+	return nil
+}
+
+func (m *GetStateChunkRequest) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type GetStateChunkResponse struct {
+	// 序列化后的StateChunk，分片哈希基于该数据计算
+	Chunk                []byte   `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateChunkResponse) Reset()         { *m = GetStateChunkResponse{} }
+func (m *GetStateChunkResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStateChunkResponse) ProtoMessage()    {}
+func (*GetStateChunkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{20}
+}
+
+func (m *GetStateChunkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateChunkResponse.Unmarshal(m, b)
+}
+func (m *GetStateChunkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateChunkResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStateChunkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateChunkResponse.Merge(m, src)
+}
+func (m *GetStateChunkResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStateChunkResponse.Size(m)
+}
+func (m *GetStateChunkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateChunkResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateChunkResponse proto.InternalMessageInfo
+
+func (m *GetStateChunkResponse) GetChunk() []byte {
+	if m != nil {
+		return m.Chunk
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Transactions)(nil), "protos.Transactions")
 	proto.RegisterType((*TxInfo)(nil), "protos.TxInfo")
@@ -631,8 +1098,17 @@ func init() {
 	proto.RegisterType((*ConsensusStatus)(nil), "protos.ConsensusStatus")
 	proto.RegisterType((*GetBlockHeaderRequest)(nil), "protos.GetBlockHeaderRequest")
 	proto.RegisterType((*GetBlockHeaderResponse)(nil), "protos.GetBlockHeaderResponse")
+	proto.RegisterType((*GetBlockIdsRequest)(nil), "protos.GetBlockIdsRequest")
+	proto.RegisterType((*GetBlockIdsResponse)(nil), "protos.GetBlockIdsResponse")
 	proto.RegisterType((*GetBlockTxsRequest)(nil), "protos.GetBlockTxsRequest")
 	proto.RegisterType((*GetBlockTxsResponse)(nil), "protos.GetBlockTxsResponse")
+	proto.RegisterType((*GetStateSnapshotRequest)(nil), "protos.GetStateSnapshotRequest")
+	proto.RegisterType((*StateSnapshot)(nil), "protos.StateSnapshot")
+	proto.RegisterType((*SnapshotSign)(nil), "protos.SnapshotSign")
+	proto.RegisterType((*StateKV)(nil), "protos.StateKV")
+	proto.RegisterType((*StateChunk)(nil), "protos.StateChunk")
+	proto.RegisterType((*GetStateChunkRequest)(nil), "protos.GetStateChunkRequest")
+	proto.RegisterType((*GetStateChunkResponse)(nil), "protos.GetStateChunkResponse")
 }
 
 func init() {
@@ -640,47 +1116,62 @@ func init() {
 }
 
 var fileDescriptor_e9685bde11a1952e = []byte{
-	// 672 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x51, 0x6b, 0xdb, 0x3a,
-	0x14, 0x26, 0x71, 0x9a, 0xc6, 0xc7, 0xb9, 0x6d, 0x51, 0x69, 0xf1, 0xed, 0xe5, 0x42, 0xea, 0xad,
-	0x2c, 0x50, 0x9a, 0xd0, 0x94, 0xed, 0x61, 0xf4, 0xa9, 0x1d, 0xb4, 0x85, 0x6d, 0x0f, 0x6e, 0x0a,
-	0x63, 0x83, 0x19, 0xc5, 0x3e, 0x4d, 0x44, 0x1d, 0xc9, 0x93, 0xe4, 0xe2, 0x8d, 0xfd, 0x96, 0xfd,
-	0x93, 0xfd, 0xb7, 0x61, 0xc9, 0x4e, 0xb3, 0xb2, 0xac, 0x0f, 0xc6, 0x3a, 0x47, 0xdf, 0xa7, 0xef,
-	0x3b, 0xc7, 0xf2, 0x81, 0xe7, 0x77, 0x28, 0x39, 0xa6, 0x43, 0xe4, 0x53, 0xc6, 0x51, 0x0d, 0x8b,
-	0x3c, 0x43, 0x29, 0xd4, 0xb0, 0xc8, 0x26, 0xe5, 0x33, 0xc8, 0xa4, 0xd0, 0x82, 0xb4, 0xcd, 0x4b,
-	0xed, 0x1d, 0x9b, 0xed, 0x58, 0x48, 0x1c, 0x4e, 0x62, 0x35, 0x4c, 0x31, 0x99, 0xa2, 0x1c, 0x16,
-	0x8b, 0x77, 0x32, 0x2d, 0x69, 0x36, 0xb4, 0xd4, 0xe0, 0x25, 0x74, 0xc7, 0x92, 0x72, 0x45, 0x63,
-	0xcd, 0x04, 0x57, 0xe4, 0x00, 0x1c, 0x5d, 0x28, 0xbf, 0xd1, 0x73, 0xfa, 0xde, 0x68, 0x7b, 0x60,
-	0x39, 0x83, 0x25, 0x48, 0x58, 0xee, 0x07, 0xdf, 0xa1, 0x3d, 0x2e, 0xae, 0xf8, 0xad, 0x20, 0xc7,
-	0xd0, 0x56, 0x9a, 0xea, 0xbc, 0xe4, 0x34, 0xfa, 0x1b, 0xa3, 0x7f, 0xff, 0xc0, 0xb9, 0x36, 0x80,
-	0xb0, 0x02, 0x92, 0x3d, 0xe8, 0x24, 0x4c, 0x69, 0xca, 0x63, 0xf4, 0x9b, 0xbd, 0x46, 0xdf, 0x09,
-	0x17, 0x31, 0x79, 0x06, 0x4d, 0x5d, 0xf8, 0x4e, 0xaf, 0xb1, 0x4a, 0xbe, 0xa9, 0x8b, 0x00, 0xc1,
-	0x3d, 0x4b, 0x45, 0x7c, 0x67, 0x0c, 0x1c, 0x3e, 0x32, 0xb0, 0x60, 0x19, 0xc8, 0x23, 0xe9, 0x43,
-	0x58, 0x9b, 0x94, 0x69, 0xa3, 0xeb, 0x8d, 0x76, 0x6a, 0xec, 0x15, 0xd7, 0x28, 0x39, 0x4d, 0x0d,
-	0x27, 0xb4, 0x98, 0xe0, 0x67, 0x03, 0xbc, 0xf3, 0x19, 0x65, 0x95, 0x7f, 0x72, 0x02, 0x9e, 0xed,
-	0x5d, 0x34, 0x47, 0x4d, 0x8d, 0x9c, 0x37, 0x22, 0xf5, 0x11, 0x6f, 0xcd, 0xd6, 0x3b, 0xd4, 0x34,
-	0x84, 0x74, 0xb1, 0x26, 0x47, 0xe0, 0xe6, 0xba, 0x10, 0x96, 0x62, 0x55, 0xb7, 0x6a, 0xca, 0x8d,
-	0x2e, 0x84, 0x21, 0x74, 0xf2, 0x6a, 0xf5, 0x60, 0xd0, 0x79, 0xda, 0x20, 0xf9, 0x1f, 0x60, 0x22,
-	0x29, 0x8f, 0x67, 0x11, 0x4b, 0x94, 0xdf, 0xea, 0x39, 0x7d, 0x37, 0x74, 0x6d, 0xe6, 0x2a, 0x51,
-	0x41, 0x0c, 0xdd, 0xeb, 0xaf, 0x4a, 0xe3, 0xbc, 0xf2, 0xff, 0x0a, 0xba, 0x71, 0x59, 0x4e, 0xb4,
-	0xd4, 0xaf, 0xb2, 0xcb, 0xf6, 0xf6, 0x0c, 0x96, 0x4a, 0x0d, 0xbd, 0x78, 0xa9, 0xee, 0xff, 0xc0,
-	0xcd, 0x10, 0x65, 0x94, 0xcb, 0x54, 0xf9, 0x4d, 0xa3, 0xd2, 0x29, 0x13, 0x37, 0x32, 0x55, 0xc1,
-	0x11, 0xb8, 0x63, 0x96, 0x55, 0xc8, 0x1e, 0x74, 0x99, 0x8a, 0xb4, 0xcc, 0xf9, 0x5d, 0xa4, 0x59,
-	0x66, 0x14, 0x3a, 0x21, 0x30, 0x35, 0x2e, 0x53, 0x63, 0x96, 0x05, 0x9f, 0x61, 0xdd, 0x7e, 0xba,
-	0x37, 0x64, 0x17, 0xda, 0x93, 0x98, 0xd3, 0x39, 0x1a, 0x98, 0x1b, 0x56, 0x11, 0xf1, 0x61, 0xdd,
-	0x94, 0xc7, 0x12, 0xd3, 0xaf, 0x6e, 0x58, 0x87, 0x64, 0x1f, 0xba, 0x1c, 0x31, 0x89, 0x62, 0xc1,
-	0x35, 0x72, 0x6d, 0x7a, 0xd4, 0x09, 0xbd, 0x32, 0x77, 0x6e, 0x53, 0xc1, 0x8f, 0x06, 0x6c, 0x9e,
-	0x0b, 0xae, 0x90, 0xab, 0x5c, 0x55, 0xae, 0x7c, 0x58, 0xbf, 0x47, 0xa9, 0x98, 0xe0, 0x95, 0x52,
-	0x1d, 0x92, 0x03, 0xd8, 0x88, 0x6b, 0x70, 0x64, 0xac, 0x34, 0x0d, 0xe0, 0x9f, 0x45, 0xf6, 0x7d,
-	0xe9, 0x68, 0x1f, 0xba, 0x4a, 0x53, 0xa9, 0xa3, 0x19, 0xb2, 0xe9, 0xcc, 0xea, 0xba, 0xa1, 0x67,
-	0x72, 0x97, 0x26, 0x45, 0x5e, 0xc0, 0xe6, 0x3d, 0x4d, 0x59, 0x42, 0xb5, 0x90, 0x2a, 0x62, 0xfc,
-	0x56, 0xf8, 0x2d, 0x83, 0xda, 0x78, 0x48, 0x97, 0xd7, 0x35, 0xf8, 0x04, 0x3b, 0x17, 0xa8, 0x4d,
-	0x0f, 0x2e, 0x91, 0x26, 0x28, 0x43, 0xfc, 0x92, 0xa3, 0xd2, 0x2b, 0xdb, 0xb1, 0x0b, 0xed, 0x4a,
-	0xd6, 0xfe, 0x2b, 0x55, 0x44, 0x08, 0xb4, 0x14, 0xfb, 0x86, 0xc6, 0x8c, 0x13, 0x9a, 0x75, 0x70,
-	0x01, 0xbb, 0x8f, 0x0f, 0x57, 0x59, 0x59, 0x0a, 0x39, 0x82, 0xb6, 0xe9, 0x62, 0xfd, 0x6b, 0xaf,
-	0xb8, 0x58, 0x15, 0x28, 0xf8, 0x00, 0xa4, 0x3e, 0x68, 0x5c, 0xa8, 0xa7, 0x2c, 0xae, 0xfe, 0x62,
-	0x5b, 0x76, 0x9c, 0x38, 0x3d, 0xa7, 0xbf, 0x66, 0x27, 0xc7, 0x29, 0x6c, 0xff, 0x76, 0x72, 0xe5,
-	0xaf, 0x9a, 0x3b, 0xad, 0xbf, 0xcf, 0x9d, 0xb3, 0xd3, 0x8f, 0xaf, 0xa7, 0x4c, 0xcf, 0xf2, 0xc9,
-	0x20, 0x16, 0x73, 0x3b, 0x0d, 0xcd, 0x4d, 0x1d, 0x3e, 0x4c, 0xbe, 0xd5, 0x13, 0x73, 0x62, 0xe7,
-	0xe4, 0xc9, 0xaf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x5b, 0x60, 0xeb, 0x04, 0x56, 0x05, 0x00, 0x00,
+	// 912 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x6f, 0xdb, 0x36,
+	0x10, 0x87, 0xad, 0xd8, 0xb1, 0xcf, 0x4a, 0x52, 0xb0, 0x69, 0xe6, 0x65, 0x18, 0xe0, 0xa8, 0x2b,
+	0x66, 0xac, 0x88, 0x0d, 0xbb, 0xd8, 0x1e, 0x86, 0x3e, 0x35, 0x05, 0xd2, 0xa0, 0xdb, 0x1e, 0x18,
+	0x77, 0x18, 0x56, 0xa0, 0x82, 0x2c, 0x5d, 0x6d, 0xc2, 0x0a, 0xa5, 0x89, 0x54, 0xa0, 0x0c, 0xfb,
+	0x2c, 0xfb, 0x26, 0xfb, 0x6e, 0x03, 0x8f, 0x94, 0xe3, 0x06, 0x33, 0x8c, 0x0d, 0x7b, 0x10, 0xc4,
+	0x3b, 0xde, 0x8f, 0xf7, 0xbb, 0x3f, 0x3c, 0x10, 0xbe, 0x5a, 0x61, 0x21, 0x31, 0x1d, 0xa3, 0x5c,
+	0x08, 0x89, 0x6a, 0x5c, 0x95, 0x39, 0x16, 0x99, 0x1a, 0x57, 0xf9, 0xdc, 0x7c, 0xa3, 0xbc, 0xc8,
+	0x74, 0xc6, 0xda, 0xf4, 0x53, 0xa7, 0x13, 0xda, 0x8e, 0xb3, 0x02, 0xc7, 0xf3, 0x58, 0x8d, 0x53,
+	0x4c, 0x16, 0x58, 0x8c, 0xab, 0xf5, 0x3f, 0x59, 0x18, 0x98, 0x15, 0x2d, 0x34, 0xf8, 0x16, 0xfc,
+	0x59, 0x11, 0x49, 0x15, 0xc5, 0x5a, 0x64, 0x52, 0xb1, 0x67, 0xe0, 0xe9, 0x4a, 0xf5, 0x1b, 0x03,
+	0x6f, 0xd8, 0x9b, 0x3e, 0x1e, 0x59, 0xcc, 0x68, 0xc3, 0x84, 0x9b, 0xfd, 0xe0, 0x0f, 0x68, 0xcf,
+	0xaa, 0x2b, 0xf9, 0x31, 0x63, 0x13, 0x68, 0x2b, 0x1d, 0xe9, 0xd2, 0x60, 0x1a, 0xc3, 0xc3, 0xe9,
+	0xe7, 0xff, 0x80, 0xb9, 0x26, 0x03, 0xee, 0x0c, 0xd9, 0x29, 0x74, 0x12, 0xa1, 0x74, 0x24, 0x63,
+	0xec, 0x37, 0x07, 0x8d, 0xa1, 0xc7, 0xd7, 0x32, 0x7b, 0x0a, 0x4d, 0x5d, 0xf5, 0xbd, 0x41, 0x63,
+	0x9b, 0xfb, 0xa6, 0xae, 0x02, 0x84, 0xee, 0xab, 0x34, 0x8b, 0x57, 0x44, 0xe0, 0xf9, 0x03, 0x02,
+	0x6b, 0x14, 0x99, 0x3c, 0x70, 0xfd, 0x1c, 0x5a, 0x73, 0xa3, 0x26, 0xbf, 0xbd, 0xe9, 0x93, 0xda,
+	0xf6, 0x4a, 0x6a, 0x2c, 0x64, 0x94, 0x12, 0x86, 0x5b, 0x9b, 0xe0, 0xaf, 0x06, 0xf4, 0x2e, 0x96,
+	0x91, 0x70, 0xfc, 0xd9, 0x0b, 0xe8, 0xd9, 0xdc, 0x85, 0x37, 0xa8, 0x23, 0x72, 0xd7, 0x9b, 0xb2,
+	0xfa, 0x88, 0x1f, 0x68, 0xeb, 0x47, 0xd4, 0x11, 0x87, 0x74, 0xbd, 0x66, 0xe7, 0xd0, 0x2d, 0x75,
+	0x95, 0x59, 0x88, 0xf5, 0xfa, 0xa8, 0x86, 0xbc, 0xd3, 0x55, 0x46, 0x80, 0x4e, 0xe9, 0x56, 0xf7,
+	0x04, 0xbd, 0xdd, 0x04, 0xd9, 0x97, 0x00, 0xf3, 0x22, 0x92, 0xf1, 0x32, 0x14, 0x89, 0xea, 0xef,
+	0x0d, 0xbc, 0x61, 0x97, 0x77, 0xad, 0xe6, 0x2a, 0x51, 0x41, 0x0c, 0xfe, 0xf5, 0x9d, 0xd2, 0x78,
+	0xe3, 0xf8, 0x7f, 0x07, 0x7e, 0x6c, 0xc2, 0x09, 0x37, 0xf2, 0x65, 0xb2, 0x6c, 0xbb, 0x67, 0xb4,
+	0x11, 0x2a, 0xef, 0xc5, 0x1b, 0x71, 0x7f, 0x01, 0xdd, 0x1c, 0xb1, 0x08, 0xcb, 0x22, 0x55, 0xfd,
+	0x26, 0x79, 0xe9, 0x18, 0xc5, 0xbb, 0x22, 0x55, 0xc1, 0x39, 0x74, 0x67, 0x22, 0x77, 0x96, 0x03,
+	0xf0, 0x85, 0x0a, 0x75, 0x51, 0xca, 0x55, 0xa8, 0x45, 0x4e, 0x1e, 0x3a, 0x1c, 0x84, 0x9a, 0x19,
+	0xd5, 0x4c, 0xe4, 0xc1, 0x07, 0xd8, 0xb7, 0xa5, 0x7b, 0xcd, 0x4e, 0xa0, 0x3d, 0x8f, 0x65, 0x74,
+	0x83, 0x64, 0xd6, 0xe5, 0x4e, 0x62, 0x7d, 0xd8, 0xa7, 0xf0, 0x44, 0x42, 0xf9, 0xf2, 0x79, 0x2d,
+	0xb2, 0x33, 0xf0, 0x25, 0x62, 0x12, 0xc6, 0x99, 0xd4, 0x28, 0x35, 0xe5, 0xa8, 0xc3, 0x7b, 0x46,
+	0x77, 0x61, 0x55, 0xc1, 0x9f, 0x0d, 0x38, 0xba, 0xc8, 0xa4, 0x42, 0xa9, 0x4a, 0xe5, 0x58, 0xf5,
+	0x61, 0xff, 0x16, 0x0b, 0x25, 0x32, 0xe9, 0x3c, 0xd5, 0x22, 0x7b, 0x06, 0x87, 0x71, 0x6d, 0x1c,
+	0x12, 0x95, 0x26, 0x19, 0x1c, 0xac, 0xb5, 0x3f, 0x19, 0x46, 0x67, 0xe0, 0x2b, 0x1d, 0x15, 0x3a,
+	0x5c, 0xa2, 0x58, 0x2c, 0xad, 0xdf, 0x2e, 0xef, 0x91, 0xee, 0x0d, 0xa9, 0xd8, 0xd7, 0x70, 0x74,
+	0x1b, 0xa5, 0x22, 0x89, 0x74, 0x56, 0xa8, 0x50, 0xc8, 0x8f, 0x59, 0x7f, 0x8f, 0xac, 0x0e, 0xef,
+	0xd5, 0xa6, 0x5d, 0x83, 0xf7, 0xf0, 0xe4, 0x12, 0x35, 0xe5, 0xe0, 0x0d, 0x46, 0x09, 0x16, 0x1c,
+	0x7f, 0x2b, 0x51, 0xe9, 0xad, 0xe9, 0x38, 0x81, 0xb6, 0x73, 0x6b, 0xef, 0x8a, 0x93, 0x18, 0x83,
+	0x3d, 0x25, 0x7e, 0x47, 0x22, 0xe3, 0x71, 0x5a, 0x07, 0x97, 0x70, 0xf2, 0xf0, 0x70, 0x95, 0x9b,
+	0x50, 0xd8, 0x39, 0xb4, 0x29, 0x8b, 0xf5, 0xd5, 0xde, 0xd2, 0x58, 0xce, 0x28, 0xf8, 0x05, 0x58,
+	0x7d, 0xd0, 0x55, 0xa2, 0xfe, 0x4f, 0x8a, 0x13, 0x78, 0xfc, 0xc9, 0xc9, 0x8e, 0xdf, 0x29, 0x74,
+	0x5c, 0x95, 0x2d, 0x43, 0x9f, 0xaf, 0xe5, 0x4d, 0x32, 0xb3, 0x6a, 0x27, 0x99, 0xed, 0xed, 0xf3,
+	0xc8, 0xce, 0x36, 0x6f, 0xe0, 0x0d, 0x5b, 0x76, 0x8c, 0xbd, 0xbc, 0x27, 0x43, 0x27, 0x3b, 0x32,
+	0x6e, 0x08, 0xee, 0xed, 0x18, 0x82, 0x13, 0xf8, 0xec, 0x12, 0xb5, 0x69, 0x32, 0xbc, 0x96, 0x51,
+	0xae, 0x96, 0x99, 0xde, 0x41, 0xce, 0x8c, 0x94, 0x83, 0x4f, 0x00, 0x5b, 0xc3, 0xf8, 0x37, 0x93,
+	0x8a, 0x3d, 0x85, 0x83, 0x78, 0x69, 0x2e, 0x5d, 0x22, 0x16, 0xa8, 0xb4, 0x8d, 0xd1, 0xe7, 0x3e,
+	0x29, 0x5f, 0x5b, 0x9d, 0xf1, 0x64, 0xb7, 0xa9, 0x33, 0x7d, 0xee, 0x24, 0xf6, 0x0d, 0xb4, 0x94,
+	0x58, 0x48, 0xd5, 0x6f, 0x51, 0xbc, 0xc7, 0xf5, 0x3c, 0xa8, 0x29, 0x5e, 0x8b, 0x85, 0xe4, 0xd6,
+	0x24, 0x78, 0x0f, 0xfe, 0xa6, 0xda, 0x24, 0x3b, 0x4a, 0x92, 0x02, 0x95, 0xaa, 0xaf, 0x96, 0x13,
+	0xcd, 0x6c, 0xca, 0xcb, 0x79, 0x2a, 0xe2, 0x70, 0x85, 0x77, 0xee, 0x5a, 0x75, 0xad, 0xe6, 0x2d,
+	0xde, 0xd9, 0xd6, 0x58, 0x48, 0x6a, 0x0d, 0x9f, 0xd3, 0x3a, 0x98, 0xc0, 0x3e, 0xe5, 0xe6, 0xed,
+	0xcf, 0xa6, 0x54, 0x06, 0xd6, 0xa0, 0x5d, 0xb3, 0x64, 0xc7, 0xd0, 0xba, 0x8d, 0xd2, 0x12, 0x5d,
+	0x51, 0xad, 0x10, 0xa4, 0x00, 0x04, 0xb9, 0x30, 0x81, 0x1a, 0x1b, 0x21, 0x13, 0xac, 0x08, 0xe7,
+	0x71, 0x2b, 0xb0, 0x33, 0xf0, 0x56, 0xb7, 0x76, 0x70, 0xf5, 0xa6, 0x47, 0xeb, 0xe8, 0xac, 0x27,
+	0x6e, 0xf6, 0xea, 0x82, 0x7b, 0x3b, 0x0a, 0xfe, 0x01, 0x8e, 0xeb, 0x82, 0x93, 0xc3, 0xff, 0xde,
+	0x8a, 0x6b, 0xa6, 0xde, 0x06, 0xd3, 0xe0, 0x9c, 0x66, 0xc3, 0xe6, 0xf9, 0xae, 0x21, 0x8f, 0xa1,
+	0x45, 0xa5, 0x74, 0x09, 0xb1, 0xc2, 0xab, 0x97, 0xbf, 0x7e, 0xbf, 0x10, 0x7a, 0x59, 0xce, 0x47,
+	0x71, 0x76, 0x63, 0x9f, 0x06, 0x34, 0xb6, 0xc7, 0xf7, 0xcf, 0x80, 0xed, 0xcf, 0x87, 0xb9, 0x7d,
+	0x34, 0xbc, 0xf8, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x97, 0x41, 0xc2, 0xec, 0x63, 0x08, 0x00, 0x00,
 }
@@ -741,3 +741,75 @@ func TestExecEVMEMISContract(t *testing.T) {
 	}
 	t.Log(string(resp.Body))
 }
+
+func TestExecEVMBlockContext(t *testing.T) {
+	var contractConfig = &contract.ContractConfig{
+		EnableUpgrade: true,
+		Xkernel: contract.XkernelConfig{
+			Enable: true,
+			Driver: "default",
+		},
+		Native: contract.NativeConfig{
+			Enable: true,
+			Driver: "native",
+		},
+		EVM: contract.EVMConfig{
+			Enable: true,
+			Driver: "evm",
+		},
+		LogDriver: mock.NewMockLogger(),
+	}
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+
+	// BlockInfo合约的blockInfo方法返回(block.number, block.timestamp, blockhash(block.number - 1))
+	bin, err := ioutil.ReadFile("testdata/BlockInfo.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	abi, err := ioutil.ReadFile("testdata/BlockInfo.abi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := map[string][]byte{
+		"contract_abi": abi,
+		"input":        bin,
+		"jsonEncoded":  []byte("false"),
+	}
+	data, err := hex.DecodeString(string(bin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = th.Deploy("evm", "BlockInfo", "BlockInfo", data, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := th.Invoke("evm", "BlockInfo", "blockInfo", map[string][]byte{
+		"input":       []byte(`{}`),
+		"jsonEncoded": []byte("true"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result []map[string]string
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result %s", resp.Body)
+	}
+
+	// 区块高度和时间取自合约执行所在的区块
+	block := mock.FakeBlock(mock.FakeLastBlockHeight + 1)
+	if number := result[0]["number"]; number != fmt.Sprint(block.GetHeight()) {
+		t.Errorf("unexpected block.number %s", number)
+	}
+	if timestamp := result[1]["timestamp"]; timestamp != fmt.Sprint(block.GetTimestamp()/1e9) {
+		t.Errorf("unexpected block.timestamp %s", timestamp)
+	}
+	// abi解码bytes32时会去掉补齐的0
+	if hash := result[2]["parentHash"]; hash != string(mock.FakeBlockid(mock.FakeLastBlockHeight)) {
+		t.Errorf("unexpected blockhash %s", hash)
+	}
+}
@@ -22,6 +22,9 @@ const (
 	DefaultMaxBroadcastPeers = 20
 	DefaultServiceName       = "localhost"
 	DefaultIsBroadCast       = true
+	DefaultBanScoreThreshold = -100
+	DefaultBanDuration       = 600
+	DefaultEnableGossipsub   = false
 )
 
 // Config is the config of p2p server. Attention, config of dht are not expose
@@ -59,6 +62,14 @@ type NetConf struct {
 	IsTls bool `yaml:"isTls,omitempty"`
 	// ServiceName
 	ServiceName string `yaml:"serviceName,omitempty"`
+	// BanScoreThreshold peers whose score drops to this value are disconnected and banned,
+	// set 0 to disable peer scoring
+	BanScoreThreshold int64 `yaml:"banScoreThreshold,omitempty"`
+	// BanDuration config how long a peer is banned, in seconds
+	BanDuration int64 `yaml:"banDuration,omitempty"`
+	// EnableGossipsub broadcast block and tx messages over gossipsub mesh of each chain,
+	// request/response messages still use direct streams, only supported by p2pv2
+	EnableGossipsub bool `yaml:"enableGossipsub,omitempty"`
 }
 
 func LoadP2PConf(cfgFile string) (*NetConf, error) {
@@ -89,6 +100,9 @@ func GetDefP2PConf() *NetConf {
 		StaticNodes:       make(map[string][]string),
 		ServiceName:       DefaultServiceName,
 		IsBroadCast:       DefaultIsBroadCast,
+		BanScoreThreshold: DefaultBanScoreThreshold,
+		BanDuration:       DefaultBanDuration,
+		EnableGossipsub:   DefaultEnableGossipsub,
 	}
 }
 
@@ -72,6 +72,9 @@ type Network interface {
 
 	Context() *nctx.NetCtx
 	PeerInfo() pb.PeerInfo
+
+	ReportPeer(peerID string, event p2p.PeerEvent)
+	PeerScores() []*p2p.PeerScore
 }
 
 // 如果有领域内公共逻辑，可以在这层扩展，对上层暴露高级接口
@@ -163,6 +166,22 @@ func (t *NetworkImpl) PeerInfo() pb.PeerInfo {
 	return t.p2pServ.PeerInfo()
 }
 
+func (t *NetworkImpl) ReportPeer(peerID string, event p2p.PeerEvent) {
+	if !t.isInit() {
+		return
+	}
+
+	t.p2pServ.ReportPeer(peerID, event)
+}
+
+func (t *NetworkImpl) PeerScores() []*p2p.PeerScore {
+	if !t.isInit() {
+		return nil
+	}
+
+	return t.p2pServ.PeerScores()
+}
+
 func (t *NetworkImpl) isInit() bool {
 	if t.ctx == nil || t.p2pServ == nil {
 		return false
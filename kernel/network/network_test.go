@@ -62,6 +62,13 @@ func (t *MockP2PServ) PeerInfo() pb.PeerInfo {
 	return pb.PeerInfo{}
 }
 
+func (t *MockP2PServ) ReportPeer(string, p2p.PeerEvent) {
+}
+
+func (t *MockP2PServ) PeerScores() []*p2p.PeerScore {
+	return nil
+}
+
 func TestNewNetwork(t *testing.T) {
 	mock.InitLogForTest()
 
@@ -34,6 +34,8 @@ type Dispatcher interface {
 
 	// Dispatch dispatch message to registered subscriber
 	Dispatch(*pb.XuperMessage, Stream) error
+	// Validate validate message content by registered subscriber
+	Validate(*pb.XuperMessage) error
 }
 
 // dispatcher implement interface Dispatcher
@@ -156,6 +158,32 @@ func (d *dispatcher) Dispatch(msg *pb.XuperMessage, stream Stream) error {
 	return nil
 }
 
+// Validate 由订阅该消息的订阅者校验消息内容，没有订阅者时返回ErrNotRegister
+func (d *dispatcher) Validate(msg *pb.XuperMessage) error {
+	if msg == nil || msg.GetHeader() == nil || msg.GetData() == nil {
+		return ErrMessageEmpty
+	}
+
+	d.mu.RLock()
+	subs := make([]Subscriber, 0, len(d.mc[msg.GetHeader().GetType()]))
+	for sub := range d.mc[msg.GetHeader().GetType()] {
+		if sub.Match(msg) {
+			subs = append(subs, sub)
+		}
+	}
+	d.mu.RUnlock()
+
+	if len(subs) <= 0 {
+		return ErrNotRegister
+	}
+	for _, sub := range subs {
+		if err := sub.Validate(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func MessageKey(msg *pb.XuperMessage) string {
 	if msg == nil || msg.GetHeader() == nil {
 		return ""
@@ -1,6 +1,7 @@
 package p2p
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/xuperchain/xupercore/kernel/mock"
@@ -121,3 +122,48 @@ func TestDispatcher(t *testing.T) {
 		}
 	}
 }
+
+func TestDispatcherValidate(t *testing.T) {
+	mock.InitLogForTest()
+	ecfg, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	netCtx, _ := nctx.NewNetCtx(ecfg)
+	dispatcher := NewDispatcher(netCtx)
+
+	msg := NewMessage(pb.XuperMessage_POSTTX, &pb.XuperMessage{}, WithBCName("xuper"))
+	if err := dispatcher.Validate(msg); err != ErrNotRegister {
+		t.Error("validate message without subscriber error", err)
+	}
+
+	ch := make(chan *pb.XuperMessage, 1)
+	if err := dispatcher.Register(NewSubscriber(netCtx, pb.XuperMessage_POSTTX, ch)); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Validate(msg); err != nil {
+		t.Error("subscriber without validator should pass", err)
+	}
+
+	errInvalid := errors.New("invalid")
+	validator := func(m *pb.XuperMessage) error {
+		if m.GetHeader().GetBcname() != "xuper" {
+			return errInvalid
+		}
+		return nil
+	}
+	sub := NewSubscriber(netCtx, pb.XuperMessage_POSTTX, ch, WithValidator(validator))
+	if err := dispatcher.Register(sub); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Validate(msg); err != nil {
+		t.Error("validate message error", err)
+	}
+	msg = NewMessage(pb.XuperMessage_POSTTX, &pb.XuperMessage{}, WithBCName("other"))
+	if err := dispatcher.Validate(msg); err != errInvalid {
+		t.Error("invalid message should be rejected", err)
+	}
+	if err := dispatcher.Validate(nil); err != ErrMessageEmpty {
+		t.Error("validate empty message error", err)
+	}
+}
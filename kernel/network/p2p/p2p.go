@@ -22,4 +22,9 @@ type Server interface {
 	Context() *nctx.NetCtx
 
 	PeerInfo() pb.PeerInfo
+
+	// ReportPeer 上报节点行为，评分过低的节点会被断开并封禁
+	ReportPeer(peerID string, event PeerEvent)
+	// PeerScores 返回节点评分，用于诊断
+	PeerScores() []*PeerScore
 }
@@ -0,0 +1,203 @@
+package p2p
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/network/config"
+)
+
+// PeerEvent 影响节点评分的行为
+type PeerEvent int
+
+const (
+	// PeerEventInvalidMessage 消息无法解析或格式错误
+	PeerEventInvalidMessage PeerEvent = iota
+	// PeerEventInvalidTx 广播了校验不通过的交易
+	PeerEventInvalidTx
+	// PeerEventInvalidBlock 广播或返回了校验不通过的区块
+	PeerEventInvalidBlock
+	// PeerEventTimeout 请求等待返回超时
+	PeerEventTimeout
+	// PeerEventUsefulResponse 正常返回了请求的数据
+	PeerEventUsefulResponse
+)
+
+func (e PeerEvent) String() string {
+	switch e {
+	case PeerEventInvalidMessage:
+		return "InvalidMessage"
+	case PeerEventInvalidTx:
+		return "InvalidTx"
+	case PeerEventInvalidBlock:
+		return "InvalidBlock"
+	case PeerEventTimeout:
+		return "Timeout"
+	case PeerEventUsefulResponse:
+		return "UsefulResponse"
+	default:
+		return "Unknown"
+	}
+}
+
+// 各行为对应的分值
+var peerEventScores = map[PeerEvent]float64{
+	PeerEventInvalidMessage: -20,
+	PeerEventInvalidTx:      -5,
+	PeerEventInvalidBlock:   -50,
+	PeerEventTimeout:        -5,
+	PeerEventUsefulResponse: 1,
+}
+
+const (
+	// 节点评分的上限，避免长期正常的节点积累过多信用后作恶不被封禁
+	maxPeerScore = 100
+	// 绝对值低于该值的评分视为已衰减完
+	minPeerScore = 0.01
+	// 评分向0衰减的半衰期
+	peerScoreHalfLife = 10 * time.Minute
+)
+
+// PeerScore 节点评分信息，用于诊断查询
+type PeerScore struct {
+	PeerID string
+	Score  float64
+	// 封禁到期时间，未被封禁时为零值
+	BannedUntil time.Time
+}
+
+// IsBanned 节点当前是否处于封禁状态
+func (s *PeerScore) IsBanned(now time.Time) bool {
+	return now.Before(s.BannedUntil)
+}
+
+// BanHandler 节点被封禁时的回调，用于断开连接
+type BanHandler func(peerID string)
+
+// 节点评分记录，updated为上次计算衰减的时间
+type peerScore struct {
+	PeerScore
+	updated time.Time
+}
+
+// ScoreBoard 根据节点行为对节点评分，评分低于阈值的节点被封禁一段时间，
+// 评分随时间向0衰减，封禁到期后评分清零
+type ScoreBoard struct {
+	mu     sync.Mutex
+	scores map[string]*peerScore
+
+	threshold   float64
+	banDuration time.Duration
+	onBan       BanHandler
+
+	now func() time.Time
+}
+
+// NewScoreBoard 创建节点评分表，BanScoreThreshold为0时不做评分和封禁
+func NewScoreBoard(cfg *config.NetConf, onBan BanHandler) *ScoreBoard {
+	return &ScoreBoard{
+		scores:      make(map[string]*peerScore),
+		threshold:   float64(cfg.BanScoreThreshold),
+		banDuration: time.Duration(cfg.BanDuration) * time.Second,
+		onBan:       onBan,
+		now:         time.Now,
+	}
+}
+
+func (b *ScoreBoard) enabled() bool {
+	return b != nil && b.threshold < 0
+}
+
+// Report 记录节点行为，节点评分低于阈值时封禁节点并返回true
+func (b *ScoreBoard) Report(peerID string, event PeerEvent) bool {
+	if !b.enabled() || peerID == "" {
+		return false
+	}
+
+	b.mu.Lock()
+	now := b.now()
+	score := b.get(peerID, now)
+	if score.IsBanned(now) {
+		b.mu.Unlock()
+		return true
+	}
+	score.Score = math.Min(score.Score+peerEventScores[event], maxPeerScore)
+	banned := score.Score <= b.threshold
+	if banned {
+		score.BannedUntil = now.Add(b.banDuration)
+	}
+	b.mu.Unlock()
+
+	if banned && b.onBan != nil {
+		b.onBan(peerID)
+	}
+	return banned
+}
+
+// IsBanned 节点是否处于封禁状态
+func (b *ScoreBoard) IsBanned(peerID string) bool {
+	if !b.enabled() {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	score, ok := b.scores[peerID]
+	return ok && score.IsBanned(b.now())
+}
+
+// Scores 返回所有节点的当前评分，按评分从低到高排序
+func (b *ScoreBoard) Scores() []*PeerScore {
+	if !b.enabled() {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	scores := make([]*PeerScore, 0, len(b.scores))
+	for peerID := range b.scores {
+		score := b.get(peerID, now).PeerScore
+		if math.Abs(score.Score) < minPeerScore && !score.IsBanned(now) {
+			// 评分已衰减完的节点不再保留
+			delete(b.scores, peerID)
+			continue
+		}
+		scores = append(scores, &score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score == scores[j].Score {
+			return scores[i].PeerID < scores[j].PeerID
+		}
+		return scores[i].Score < scores[j].Score
+	})
+	return scores
+}
+
+// get 返回衰减到当前时间的节点评分，调用方需持有锁
+func (b *ScoreBoard) get(peerID string, now time.Time) *peerScore {
+	score, ok := b.scores[peerID]
+	if !ok {
+		score = &peerScore{
+			PeerScore: PeerScore{PeerID: peerID},
+			updated:   now,
+		}
+		b.scores[peerID] = score
+		return score
+	}
+
+	if score.IsBanned(now) {
+		return score
+	}
+	if !score.BannedUntil.IsZero() {
+		// 封禁到期，重新开始评分
+		score.Score = 0
+		score.BannedUntil = time.Time{}
+	}
+	elapsed := now.Sub(score.updated)
+	score.Score *= math.Pow(0.5, float64(elapsed)/float64(peerScoreHalfLife))
+	score.updated = now
+	return score
+}
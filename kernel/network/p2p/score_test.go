@@ -0,0 +1,81 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/network/config"
+)
+
+func newTestScoreBoard(onBan BanHandler) (*ScoreBoard, *time.Time) {
+	cfg := config.GetDefP2PConf()
+	board := NewScoreBoard(cfg, onBan)
+	now := time.Unix(1600000000, 0)
+	board.now = func() time.Time { return now }
+	return board, &now
+}
+
+func TestScoreBoardBan(t *testing.T) {
+	var banned []string
+	board, now := newTestScoreBoard(func(peerID string) {
+		banned = append(banned, peerID)
+	})
+
+	board.Report("peer1", PeerEventUsefulResponse)
+	board.Report("peer1", PeerEventInvalidBlock)
+	board.Report("peer1", PeerEventInvalidBlock)
+	if board.IsBanned("peer1") {
+		t.Fatal("peer1 should not be banned")
+	}
+	if !board.Report("peer1", PeerEventInvalidMessage) {
+		t.Fatal("peer1 should be banned")
+	}
+	if !board.IsBanned("peer1") || len(banned) != 1 || banned[0] != "peer1" {
+		t.Fatalf("unexpected ban state, banned:%v", banned)
+	}
+	// 封禁期间的上报不重复触发封禁
+	board.Report("peer1", PeerEventInvalidBlock)
+	if len(banned) != 1 {
+		t.Fatalf("ban handler called again, banned:%v", banned)
+	}
+
+	*now = now.Add(time.Duration(config.DefaultBanDuration)*time.Second + time.Second)
+	if board.IsBanned("peer1") {
+		t.Fatal("peer1 ban should expire")
+	}
+	board.Report("peer1", PeerEventTimeout)
+	scores := board.Scores()
+	if len(scores) != 1 || scores[0].Score != -5 {
+		t.Fatalf("score should restart after ban expired, got %+v", scores[0])
+	}
+}
+
+func TestScoreBoardDecay(t *testing.T) {
+	board, now := newTestScoreBoard(nil)
+	board.Report("peer1", PeerEventInvalidBlock)
+	board.Report("peer2", PeerEventUsefulResponse)
+
+	*now = now.Add(peerScoreHalfLife)
+	scores := board.Scores()
+	if len(scores) != 2 || scores[0].PeerID != "peer1" || scores[0].Score != -25 {
+		t.Fatalf("unexpected scores: %+v", scores)
+	}
+
+	// 衰减完的评分被清理
+	*now = now.Add(20 * peerScoreHalfLife)
+	if scores := board.Scores(); len(scores) != 0 {
+		t.Fatalf("decayed scores should be removed: %+v", scores)
+	}
+}
+
+func TestScoreBoardDisabled(t *testing.T) {
+	cfg := config.GetDefP2PConf()
+	cfg.BanScoreThreshold = 0
+	board := NewScoreBoard(cfg, nil)
+	for i := 0; i < 10; i++ {
+		board.Report("peer1", PeerEventInvalidBlock)
+	}
+	if board.IsBanned("peer1") || len(board.Scores()) != 0 {
+		t.Fatal("disabled score board should not ban peers")
+	}
+}
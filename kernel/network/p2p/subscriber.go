@@ -25,6 +25,8 @@ type Subscriber interface {
 	GetMessageType() pb.XuperMessage_MessageType
 	Match(*pb.XuperMessage) bool
 	HandleMessage(xctx.XContext, *pb.XuperMessage, Stream) error
+	// Validate 校验消息内容，未设置校验函数时直接通过
+	Validate(*pb.XuperMessage) error
 }
 
 // Stream send p2p response message
@@ -36,6 +38,9 @@ type HandleFunc func(xctx.XContext, *pb.XuperMessage) (*pb.XuperMessage, error)
 
 type SubscriberOption func(*subscriber)
 
+// ValidateFunc 校验消息内容，广播消息在转发给其他节点前调用
+type ValidateFunc func(*pb.XuperMessage) error
+
 func WithFilterFrom(from string) SubscriberOption {
 	return func(s *subscriber) {
 		s.from = from
@@ -48,6 +53,13 @@ func WithFilterBCName(bcName string) SubscriberOption {
 	}
 }
 
+// WithValidator 设置消息内容的校验函数
+func WithValidator(validator ValidateFunc) SubscriberOption {
+	return func(s *subscriber) {
+		s.validator = validator
+	}
+}
+
 func NewSubscriber(ctx *nctx.NetCtx, typ pb.XuperMessage_MessageType,
 	v interface{}, opts ...SubscriberOption) Subscriber {
 
@@ -89,8 +101,9 @@ type subscriber struct {
 	bcName string // 接收指定链的消息
 	from   string // 接收指定节点的消息
 
-	channel chan *pb.XuperMessage
-	handler HandleFunc
+	channel   chan *pb.XuperMessage
+	handler   HandleFunc
+	validator ValidateFunc
 }
 
 var _ Subscriber = &subscriber{}
@@ -165,3 +178,10 @@ func (s *subscriber) HandleMessage(ctx xctx.XContext, msg *pb.XuperMessage, stre
 
 	return nil
 }
+
+func (s *subscriber) Validate(msg *pb.XuperMessage) error {
+	if s.validator == nil {
+		return nil
+	}
+	return s.validator(msg)
+}
@@ -91,10 +91,13 @@ func update(ctx contract.KContext, aclJSON []byte, accountName string, method st
 	permissionRule := acl.GetPm().GetRule()
 
 	switch permissionRule {
-	case pb.PermissionRule_SIGN_THRESHOLD:
+	case pb.PermissionRule_SIGN_THRESHOLD, pb.PermissionRule_SIGN_RATE, pb.PermissionRule_SIGN_SUM:
 		return updateForThreshold(ctx, aksWeight, accountName, method)
 	case pb.PermissionRule_SIGN_AKSET:
 		return updateForAKSet(ctx, akSets, accountName, method)
+	case pb.PermissionRule_CA_SERVER:
+		// members of CA_SERVER account are decided by certs, no ak to account reflection
+		return nil
 	default:
 		return errors.New("update ak to account reflection failed, permission model is not found")
 	}
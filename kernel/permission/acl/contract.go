@@ -1,11 +1,16 @@
 package acl
 
 import (
+	"bytes"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math"
 
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/permission/acl/rule"
 	"github.com/xuperchain/xupercore/kernel/permission/acl/utils"
 	pb "github.com/xuperchain/xupercore/protos"
 )
@@ -54,6 +59,10 @@ func (t *KernMethod) NewAccount(ctx contract.KContext) (*contract.Response, erro
 	if validErr := validACL(aclBuf); validErr != nil {
 		return nil, validErr
 	}
+	aclJSON, err = markAkSetsExpression(aclBuf, aclJSON)
+	if err != nil {
+		return nil, err
+	}
 
 	oldAccount, err := ctx.Get(utils.GetAccountBucket(), []byte(accountStr))
 	if err != nil && err != sandbox.ErrNotFound {
@@ -102,6 +111,10 @@ func (t *KernMethod) SetAccountACL(ctx contract.KContext) (*contract.Response, e
 	if validErr := validACL(aclBuf); validErr != nil {
 		return nil, validErr
 	}
+	aclJSON, err = markAkSetsExpression(aclBuf, aclJSON)
+	if err != nil {
+		return nil, err
+	}
 
 	data, err := ctx.Get(utils.GetAccountBucket(), accountName)
 	if err != nil {
@@ -156,6 +169,10 @@ func (t *KernMethod) SetMethodACL(ctx contract.KContext) (*contract.Response, er
 	if validErr := validACL(aclBuf); validErr != nil {
 		return nil, validErr
 	}
+	aclJSON, err = markAkSetsExpression(aclBuf, aclJSON)
+	if err != nil {
+		return nil, err
+	}
 	key := utils.MakeContractMethodKey(contractName, methodName)
 	err = ctx.Put(utils.GetContractBucket(), []byte(key), aclJSON)
 	if err != nil {
@@ -174,6 +191,68 @@ func (t *KernMethod) SetMethodACL(ctx contract.KContext) (*contract.Response, er
 	}, nil
 }
 
+// SetCaCRL 设置CA签发的证书吊销列表，CRL需由参数中的CA签名，且只能用更新的CRL覆盖已有CRL
+func (t *KernMethod) SetCaCRL(ctx contract.KContext) (*contract.Response, error) {
+	if ctx.ResourceLimit().XFee < t.NewAccountResourceAmount/1000 {
+		return nil, fmt.Errorf("gas not enough, expect no less than %d", t.NewAccountResourceAmount/1000)
+	}
+	args := ctx.Args()
+	caBuf := args["ca"]
+	crlBuf := args["crl"]
+	if caBuf == nil || crlBuf == nil {
+		return nil, fmt.Errorf("set ca crl failed, ca is nil or crl is nil")
+	}
+
+	ca, err := rule.ParsePEMCert(caBuf)
+	if err != nil {
+		return nil, fmt.Errorf("set ca crl failed, parse ca error: %v", err)
+	}
+	if !ca.IsCA {
+		return nil, fmt.Errorf("set ca crl failed, cert is not CA")
+	}
+	// CRL支持PEM和DER格式，统一存储为DER格式
+	if block, _ := pem.Decode(crlBuf); block != nil {
+		crlBuf = block.Bytes
+	}
+	crl, err := x509.ParseDERCRL(crlBuf)
+	if err != nil {
+		return nil, fmt.Errorf("set ca crl failed, parse crl error: %v", err)
+	}
+	if err := ca.CheckCRLSignature(crl); err != nil {
+		return nil, fmt.Errorf("set ca crl failed, check crl signature error: %v", err)
+	}
+
+	key := []byte(rule.CaKeyID(ca))
+	oldCRLBuf, err := ctx.Get(utils.GetCaCRLBucket(), key)
+	if err != nil && err != sandbox.ErrNotFound {
+		return nil, err
+	}
+	if len(oldCRLBuf) > 0 {
+		if bytes.Equal(oldCRLBuf, crlBuf) {
+			return nil, fmt.Errorf("set ca crl failed, crl already exists")
+		}
+		oldCRL, err := x509.ParseDERCRL(oldCRLBuf)
+		if err == nil && !crl.TBSCertList.ThisUpdate.After(oldCRL.TBSCertList.ThisUpdate) {
+			return nil, fmt.Errorf("set ca crl failed, crl is older than the existing one")
+		}
+	}
+	err = ctx.Put(utils.GetCaCRLBucket(), key, crlBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := contract.Limits{
+		XFee: t.NewAccountResourceAmount / 1000,
+	}
+	ctx.AddResourceUsed(delta)
+
+	return &contract.Response{
+		Status:  utils.StatusOK,
+		Message: "success",
+		Body:    key,
+	}, nil
+}
+
 func validACL(acl *pb.Acl) error {
 	// param absence check
 	if acl == nil {
@@ -183,6 +262,10 @@ func validACL(acl *pb.Acl) error {
 	// permission model check
 	if permissionModel := acl.GetPm(); permissionModel != nil {
 		permissionRule := permissionModel.GetRule()
+		// CA_SERVER的成员由根证书决定，不需要配置ak
+		if permissionRule == pb.PermissionRule_CA_SERVER {
+			return validCaServerACL(acl)
+		}
 		akSets := acl.GetAkSets()
 		aksWeight := acl.GetAksWeight()
 		if akSets == nil && aksWeight == nil {
@@ -199,9 +282,19 @@ func validACL(acl *pb.Acl) error {
 				if sets == nil || len(sets) > utils.GetAkLimit() {
 					return fmt.Errorf("valid acl failed, Sets is nil or size of Sets is very big")
 				}
+				if err := validAkSetsExpression(akSets); err != nil {
+					return err
+				}
 			} else {
 				return fmt.Errorf("valid acl failed, akSets is nil")
 			}
+		} else if permissionRule == pb.PermissionRule_SIGN_RATE || permissionRule == pb.PermissionRule_SIGN_SUM {
+			if len(aksWeight) == 0 || len(aksWeight) > utils.GetAkLimit() {
+				return fmt.Errorf("valid acl failed, aksWeight is nil or size of aksWeight is very big")
+			}
+			if err := validSignAcceptValue(permissionRule, permissionModel.GetAcceptValue(), len(aksWeight)); err != nil {
+				return err
+			}
 		} else {
 			return fmt.Errorf("valid acl failed, permission model is not found")
 		}
@@ -211,3 +304,69 @@ func validACL(acl *pb.Acl) error {
 
 	return nil
 }
+
+// validSignAcceptValue 校验签名率和签名个数策略的acceptValue，
+// 签名率取值范围为(0, 1]，签名个数为不超过成员数的正整数
+func validSignAcceptValue(rule pb.PermissionRule, acceptValue float64, memberNum int) error {
+	if rule == pb.PermissionRule_SIGN_RATE {
+		if acceptValue <= 0 || acceptValue > 1 {
+			return fmt.Errorf("valid acl failed, acceptValue of SIGN_RATE should be in (0, 1]")
+		}
+		return nil
+	}
+	if acceptValue < 1 || acceptValue > float64(memberNum) || acceptValue != math.Trunc(acceptValue) {
+		return fmt.Errorf("valid acl failed, acceptValue of SIGN_SUM should be an integer in [1, %d]", memberNum)
+	}
+	return nil
+}
+
+// validCaServerACL 校验CA_SERVER策略，根证书必须是合法的CA证书，acceptValue为通过证书校验的最少签名个数
+func validCaServerACL(acl *pb.Acl) error {
+	caRoots := acl.GetCaRoots()
+	if len(caRoots) == 0 || len(caRoots) > utils.GetAkLimit() {
+		return fmt.Errorf("valid acl failed, caRoots is nil or size of caRoots is very big")
+	}
+	if _, err := rule.ParseCaRoots(caRoots); err != nil {
+		return fmt.Errorf("valid acl failed, %v", err)
+	}
+	acceptValue := acl.GetPm().GetAcceptValue()
+	if acceptValue < 1 || acceptValue != math.Trunc(acceptValue) {
+		return fmt.Errorf("valid acl failed, acceptValue of CA_SERVER should be a positive integer")
+	}
+	return nil
+}
+
+// markAkSetsExpression 设置AkSets表达式版本，只有升级后由合约写入的表达式才会生效，
+// 升级前写入的ACL版本为0，其中的表达式不会被计算；版本不需要变化时保持原始ACL数据不变
+func markAkSetsExpression(acl *pb.Acl, aclJSON []byte) ([]byte, error) {
+	akSets := acl.GetAkSets()
+	if akSets == nil {
+		return aclJSON, nil
+	}
+	version := int32(0)
+	if akSets.GetExpression() != "" {
+		version = rule.AkSetsExpressionVersion
+	}
+	if akSets.GetExpressionVersion() == version {
+		return aclJSON, nil
+	}
+	akSets.ExpressionVersion = version
+	return json.Marshal(acl)
+}
+
+// validAkSetsExpression 校验AkSets表达式语法，表达式中的集合名必须存在，空表达式表示集合间是or
+func validAkSetsExpression(akSets *pb.AkSets) error {
+	if akSets.GetExpression() == "" {
+		return nil
+	}
+	expr, err := rule.ParseAkSetsExpression(akSets.GetExpression())
+	if err != nil {
+		return fmt.Errorf("valid acl failed, %v", err)
+	}
+	for _, name := range expr.SetNames() {
+		if _, ok := akSets.GetSets()[name]; !ok {
+			return fmt.Errorf("valid acl failed, AkSet %s in expression not found", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package acl
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/permission/acl/rule"
+	"github.com/xuperchain/xupercore/kernel/permission/acl/utils"
+	xutils "github.com/xuperchain/xupercore/lib/utils"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	pem  []byte
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, serial int64, isCA bool) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "xuper-test-ca"},
+		NotBefore:             time.Unix(1600000000, 0),
+		NotAfter:              time.Unix(1900000000, 0),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{
+		cert: cert,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:  key,
+	}
+}
+
+func (ca *testCA) crl(t *testing.T, thisUpdate time.Time, serials ...int64) []byte {
+	revoked := make([]pkix.RevokedCertificate, 0, len(serials))
+	for _, serial := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: thisUpdate})
+	}
+	crl, err := ca.cert.CreateCRL(rand.Reader, ca.key, revoked, thisUpdate, thisUpdate.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crl
+}
+
+func TestSetCaCRL(t *testing.T) {
+	kMethod := NewKernContractMethod("xuper", 0)
+	state := make(map[string]map[string][]byte)
+	setCRL := func(ca, crl []byte) ([]byte, error) {
+		ctx := mock.NewFakeKContext(map[string][]byte{"ca": ca, "crl": crl}, state)
+		resp, err := kMethod.SetCaCRL(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Body, nil
+	}
+	storedCRL := func(key []byte) []byte {
+		return state[utils.GetCaCRLBucket()][xutils.F(key)]
+	}
+
+	now := time.Unix(1650000000, 0)
+	ca := newTestCA(t, 1, true)
+	crl := ca.crl(t, now, 2)
+	key, err := setCRL(ca.pem, crl)
+	if err != nil {
+		t.Fatalf("set crl error: %v", err)
+	}
+	if string(key) != rule.CaKeyID(ca.cert) || !bytes.Equal(storedCRL(key), crl) {
+		t.Error("crl should be stored by the key of CA")
+	}
+
+	if _, err := setCRL(ca.pem, nil); err == nil {
+		t.Error("empty crl should be rejected")
+	}
+	if _, err := setCRL(newTestCA(t, 3, false).pem, crl); err == nil {
+		t.Error("crl of non-CA cert should be rejected")
+	}
+	if _, err := setCRL(newTestCA(t, 4, true).pem, crl); err == nil {
+		t.Error("crl signed by other CA should be rejected")
+	}
+	if _, err := setCRL(ca.pem, crl); err == nil {
+		t.Error("existing crl should be rejected")
+	}
+	if _, err := setCRL(ca.pem, ca.crl(t, now.Add(-time.Hour))); err == nil {
+		t.Error("older crl should be rejected")
+	}
+	if !bytes.Equal(storedCRL(key), crl) {
+		t.Error("rejected crl should not replace the existing one")
+	}
+
+	// PEM encoded CRL is stored as DER
+	newCRL := ca.crl(t, now.Add(time.Hour), 2, 5)
+	if _, err := setCRL(ca.pem, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: newCRL})); err != nil {
+		t.Fatalf("set newer crl error: %v", err)
+	}
+	if !bytes.Equal(storedCRL(key), newCRL) {
+		t.Error("newer crl should replace the existing one")
+	}
+}
+
+func TestMarkAkSetsExpression(t *testing.T) {
+	mark := func(aclJSON string) (*pb.Acl, []byte) {
+		acl := &pb.Acl{}
+		if err := json.Unmarshal([]byte(aclJSON), acl); err != nil {
+			t.Fatal(err)
+		}
+		buf, err := markAkSetsExpression(acl, []byte(aclJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored := &pb.Acl{}
+		if err := json.Unmarshal(buf, stored); err != nil {
+			t.Fatal(err)
+		}
+		return stored, buf
+	}
+
+	withExpr, _ := mark(`{"pm":{"rule":2},"akSets":{"sets":{"a":{"aks":["ak1"]},"b":{"aks":["ak2"]}},"expression":"a AND b"}}`)
+	if withExpr.GetAkSets().GetExpressionVersion() != rule.AkSetsExpressionVersion {
+		t.Errorf("expression version should be set, got %d", withExpr.GetAkSets().GetExpressionVersion())
+	}
+	noExpr, _ := mark(`{"pm":{"rule":2},"akSets":{"sets":{"a":{"aks":["ak1"]}},"expressionVersion":1}}`)
+	if noExpr.GetAkSets().GetExpressionVersion() != 0 {
+		t.Errorf("expression version without expression should be reset, got %d", noExpr.GetAkSets().GetExpressionVersion())
+	}
+	aclJSON := `{"pm":{"rule":2},"akSets":{"sets":{"a":{"aks":["ak1"]}}}}`
+	if _, buf := mark(aclJSON); string(buf) != aclJSON {
+		t.Errorf("acl without expression should be kept, got %s", buf)
+	}
+}
@@ -32,9 +32,11 @@ func NewACLManager(ctx *actx.AclCtx) (base.AclManager, error) {
 	register.RegisterKernMethod(utils.SubModName, "NewAccount", t.NewAccount)
 	register.RegisterKernMethod(utils.SubModName, "SetAccountAcl", t.SetAccountACL)
 	register.RegisterKernMethod(utils.SubModName, "SetMethodAcl", t.SetMethodACL)
+	register.RegisterKernMethod(utils.SubModName, "SetCaCRL", t.SetCaCRL)
 	register.RegisterShortcut("NewAccount", utils.SubModName, "NewAccount")
 	register.RegisterShortcut("SetAccountAcl", utils.SubModName, "SetAccountAcl")
 	register.RegisterShortcut("SetMethodAcl", utils.SubModName, "SetMethodAcl")
+	register.RegisterShortcut("SetCaCRL", utils.SubModName, "SetCaCRL")
 
 	mg := &Manager{
 		Ctx: ctx,
@@ -106,7 +108,7 @@ func (mgr *Manager) getAddressesByACL(acl *pb.Acl) ([]string, error) {
 	addresses := make([]string, 0)
 
 	switch acl.GetPm().GetRule() {
-	case pb.PermissionRule_SIGN_THRESHOLD:
+	case pb.PermissionRule_SIGN_THRESHOLD, pb.PermissionRule_SIGN_RATE, pb.PermissionRule_SIGN_SUM:
 		for ak := range acl.GetAksWeight() {
 			addresses = append(addresses, ak)
 		}
@@ -115,6 +117,8 @@ func (mgr *Manager) getAddressesByACL(acl *pb.Acl) ([]string, error) {
 			aks := set.GetAks()
 			addresses = append(addresses, aks...)
 		}
+	case pb.PermissionRule_CA_SERVER:
+		// members of CA_SERVER account are decided by certs, no address bound to account
 	default:
 		return nil, errors.New("Unknown permission rule")
 	}
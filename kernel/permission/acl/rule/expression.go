@@ -0,0 +1,202 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxExpressionDepth limits the nesting depth of AkSets expression
+const maxExpressionDepth = 32
+
+// expression operators, case insensitive
+const (
+	exprAnd = "AND"
+	exprOr  = "OR"
+	exprNot = "NOT"
+)
+
+// AkSetsExpressionVersion is the expression version set by acl contract when writing AkSets with expression,
+// expression of ACLs written before the upgrade has version 0 and is ignored, so that their semantics keep unchanged
+const AkSetsExpressionVersion = 1
+
+// ErrEmptyExpression is returned when parsing an empty AkSets expression
+var ErrEmptyExpression = errors.New("AkSets expression is empty")
+
+// AkSetsExpr is the syntax tree of AkSets expression, such as `(legal AND cfo) OR (ceo AND board)`,
+// operands are names of AkSet, operators are AND, OR, NOT and parentheses
+type AkSetsExpr struct {
+	op       string        // operator, empty for operand
+	name     string        // name of AkSet for operand
+	operands []*AkSetsExpr // operands of operator
+}
+
+// ParseAkSetsExpression parses AkSets expression, precedence from high to low is NOT, AND, OR
+func ParseAkSetsExpression(expr string) (*AkSetsExpr, error) {
+	tokens := tokenizeExpression(expr)
+	if len(tokens) == 0 {
+		return nil, ErrEmptyExpression
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q in AkSets expression", p.tokens[p.pos])
+	}
+	return root, nil
+}
+
+// SetNames returns names of all AkSets referenced by the expression
+func (e *AkSetsExpr) SetNames() []string {
+	if e.op == "" {
+		return []string{e.name}
+	}
+	names := make([]string, 0, len(e.operands))
+	for _, operand := range e.operands {
+		names = append(names, operand.SetNames()...)
+	}
+	return names
+}
+
+// Eval evaluates the expression, isValid returns whether the named AkSet is valid
+func (e *AkSetsExpr) Eval(isValid func(name string) bool) bool {
+	switch e.op {
+	case exprNot:
+		return !e.operands[0].Eval(isValid)
+	case exprAnd:
+		for _, operand := range e.operands {
+			if !operand.Eval(isValid) {
+				return false
+			}
+		}
+		return true
+	case exprOr:
+		for _, operand := range e.operands {
+			if operand.Eval(isValid) {
+				return true
+			}
+		}
+		return false
+	}
+	return isValid(e.name)
+}
+
+// tokenizeExpression splits expression into parentheses and words
+func tokenizeExpression(expr string) []string {
+	tokens := make([]string, 0)
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range expr {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+// peekOp returns the upper case of next token if it is an operator
+func (p *exprParser) peekOp() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	op := strings.ToUpper(p.tokens[p.pos])
+	if op == exprAnd || op == exprOr || op == exprNot {
+		return op
+	}
+	return ""
+}
+
+// parseOr: and_expr {OR and_expr}
+func (p *exprParser) parseOr(depth int) (*AkSetsExpr, error) {
+	return p.parseBinary(exprOr, depth, p.parseAnd)
+}
+
+// parseAnd: not_expr {AND not_expr}
+func (p *exprParser) parseAnd(depth int) (*AkSetsExpr, error) {
+	return p.parseBinary(exprAnd, depth, p.parseNot)
+}
+
+func (p *exprParser) parseBinary(op string, depth int,
+	next func(int) (*AkSetsExpr, error)) (*AkSetsExpr, error) {
+	first, err := next(depth)
+	if err != nil {
+		return nil, err
+	}
+	node := &AkSetsExpr{op: op, operands: []*AkSetsExpr{first}}
+	for p.peekOp() == op {
+		p.pos++
+		operand, err := next(depth)
+		if err != nil {
+			return nil, err
+		}
+		node.operands = append(node.operands, operand)
+	}
+	if len(node.operands) == 1 {
+		return first, nil
+	}
+	return node, nil
+}
+
+// parseNot: NOT not_expr | primary
+func (p *exprParser) parseNot(depth int) (*AkSetsExpr, error) {
+	if p.peekOp() != exprNot {
+		return p.parsePrimary(depth)
+	}
+	if depth >= maxExpressionDepth {
+		return nil, errors.New("AkSets expression is too deep")
+	}
+	p.pos++
+	operand, err := p.parseNot(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	return &AkSetsExpr{op: exprNot, operands: []*AkSetsExpr{operand}}, nil
+}
+
+// parsePrimary: '(' or_expr ')' | name
+func (p *exprParser) parsePrimary(depth int) (*AkSetsExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of AkSets expression")
+	}
+	token := p.tokens[p.pos]
+	switch {
+	case token == "(":
+		if depth >= maxExpressionDepth {
+			return nil, errors.New("AkSets expression is too deep")
+		}
+		p.pos++
+		node, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, errors.New("missing ')' in AkSets expression")
+		}
+		p.pos++
+		return node, nil
+	case token == ")" || p.peekOp() != "":
+		return nil, fmt.Errorf("unexpected token %q in AkSets expression", token)
+	}
+	p.pos++
+	return &AkSetsExpr{name: token}, nil
+}
@@ -15,6 +15,8 @@ type ACLValidator interface {
 
 // ACLValidatorFactory create ACLValidator for specified permission model
 type ACLValidatorFactory struct {
+	// cert chains of signers, only used by CA_SERVER permission model
+	Certs *CertContext
 }
 
 // GetACLValidator returns ACLValidator for specified permission model
@@ -25,11 +27,11 @@ func (vf *ACLValidatorFactory) GetACLValidator(pr pb.PermissionRule) (ACLValidat
 	case pb.PermissionRule_SIGN_AKSET:
 		return NewAKSetsValidator(), nil
 	case pb.PermissionRule_SIGN_RATE:
-		return vf.notImplementedValidator()
+		return NewSignRateValidator(), nil
 	case pb.PermissionRule_SIGN_SUM:
-		return vf.notImplementedValidator()
+		return NewSignSumValidator(), nil
 	case pb.PermissionRule_CA_SERVER:
-		return vf.notImplementedValidator()
+		return NewCAValidator(vf.Certs), nil
 	case pb.PermissionRule_COMMUNITY_VOTE:
 		return vf.notImplementedValidator()
 	}
@@ -2,6 +2,7 @@ package rule
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/xuperchain/xupercore/kernel/permission/acl/ptree"
 	pb "github.com/xuperchain/xupercore/protos"
@@ -32,9 +33,14 @@ func (asv *AKSetsValidator) Validate(pnode *ptree.PermNode) (bool, error) {
 		return false, nil
 	}
 
-	// AkSets.Expression is not supported now, only support default expression:
-	// 1. AkSet: a set is valid only if all aks pass signature verification
-	// 2. AkSets: an AkSets is valid only if at least one AkSet pass signature verification
+	// an AkSet is valid only if all aks pass signature verification,
+	// and AkSets.Expression combines AkSets by name with AND, OR, NOT and parentheses,
+	// expression only takes effect for ACLs written by acl contract after the upgrade
+	if pnode.ACL.AkSets.Expression != "" && pnode.ACL.AkSets.ExpressionVersion >= AkSetsExpressionVersion {
+		return asv.validateExpression(pnode.ACL.AkSets, pnode.Children)
+	}
+
+	// default expression: an AkSets is valid only if at least one AkSet pass signature verification
 	for _, set := range pnode.ACL.AkSets.Sets {
 		if isValid := asv.validateAkSet(set, pnode.Children); isValid {
 			expResult = true
@@ -45,6 +51,24 @@ func (asv *AKSetsValidator) Validate(pnode *ptree.PermNode) (bool, error) {
 	return expResult, nil
 }
 
+// validateExpression validate AkSets with boolean expression over names of AkSet
+func (asv *AKSetsValidator) validateExpression(akSets *pb.AkSets, signedAks []*ptree.PermNode) (bool, error) {
+	expr, err := ParseAkSetsExpression(akSets.Expression)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range expr.SetNames() {
+		if _, ok := akSets.Sets[name]; !ok {
+			return false, fmt.Errorf("AkSet %s in expression not found", name)
+		}
+	}
+
+	result := expr.Eval(func(name string) bool {
+		return asv.validateAkSet(akSets.Sets[name], signedAks)
+	})
+	return result, nil
+}
+
 // validateAkSet validate single AkSet
 func (asv *AKSetsValidator) validateAkSet(set *pb.AkSet, signedAks []*ptree.PermNode) bool {
 	// empty set or empty signature means validate failed
@@ -0,0 +1,191 @@
+package rule
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/permission/acl/ptree"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+)
+
+// CRLReader reads the CRL stored on chain by the key of CA, returns nil if not found,
+// it should be pinned to the block containing the tx so that all nodes get the same result
+type CRLReader interface {
+	GetCaCRL(caKey string) ([]byte, error)
+}
+
+// CertContext carries the X.509 cert chains of signers, used by CA_SERVER permission model
+type CertContext struct {
+	// signer address -> DER encoded cert chain, leaf cert first
+	Chains map[string][][]byte
+	// time to check the expiry of certs, usually the timestamp of the parent block of the tx
+	// so that all nodes get the same result
+	Time time.Time
+	// CRLs stored on chain
+	CRLs CRLReader
+	// crypto client of chain, used to check the address of signer matches the leaf cert
+	Crypto cryptoBase.CryptoClient
+}
+
+// CAValidator is Valiator for CA_SERVER permission model
+// an ak passes the validation only if its cert chain is issued by one of the root CAs in ACL,
+// not expired and not revoked, and AcceptValue is the minimum number of such aks
+type CAValidator struct {
+	certs *CertContext
+}
+
+// NewCAValidator return instance of CAValidator
+func NewCAValidator(certs *CertContext) *CAValidator {
+	return &CAValidator{
+		certs: certs,
+	}
+}
+
+// Validate implements the interface of ACLValidator
+func (cv *CAValidator) Validate(pnode *ptree.PermNode) (bool, error) {
+	if pnode == nil || pnode.ACL == nil || pnode.ACL.Pm == nil {
+		return false, errors.New("Validate: Invalid Param")
+	}
+
+	// no cert chain attached means no one can pass the validation
+	if cv.certs == nil || len(cv.certs.Chains) == 0 {
+		return false, nil
+	}
+
+	roots, err := ParseCaRoots(pnode.ACL.CaRoots)
+	if err != nil {
+		return false, err
+	}
+
+	count := 0
+	for _, node := range pnode.Children {
+		// the signature of ak must be passed the validation before
+		if node.Status != ptree.Success {
+			continue
+		}
+		chain, ok := cv.certs.Chains[node.Name]
+		if !ok {
+			continue
+		}
+		// invalid cert chain of one signer should not affect others
+		if err := cv.verifyCertChain(node.Name, chain, roots); err != nil {
+			continue
+		}
+		count++
+	}
+	return count > 0 && float64(count) >= pnode.ACL.Pm.AcceptValue, nil
+}
+
+// verifyCertChain verifies the cert chain of address against the root CAs
+func (cv *CAValidator) verifyCertChain(address string, chain [][]byte, roots *x509.CertPool) error {
+	if len(chain) == 0 {
+		return errors.New("empty cert chain")
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range chain[1:] {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return err
+		}
+		intermediates.AddCert(cert)
+	}
+
+	// the leaf cert must belong to the signer
+	pubKey, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return errors.New("public key of cert is not ecdsa")
+	}
+	if cv.certs.Crypto == nil {
+		return errors.New("crypto client is nil")
+	}
+	if isMatch, _ := cv.certs.Crypto.VerifyAddressUsingPublicKey(address, pubKey); !isMatch {
+		return errors.New("address and cert not match")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   cv.certs.Time,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	verifiedChains, err := leaf.Verify(opts)
+	if err != nil {
+		return err
+	}
+	return cv.checkRevocation(verifiedChains[0])
+}
+
+// checkRevocation checks every cert in chain against the CRL of its issuer
+func (cv *CAValidator) checkRevocation(chain []*x509.Certificate) error {
+	if cv.certs.CRLs == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(chain); i++ {
+		cert, issuer := chain[i], chain[i+1]
+		crlBuf, err := cv.certs.CRLs.GetCaCRL(CaKeyID(issuer))
+		if err != nil {
+			return err
+		}
+		if len(crlBuf) == 0 {
+			continue
+		}
+		crl, err := x509.ParseCRL(crlBuf)
+		if err != nil {
+			return err
+		}
+		if err := issuer.CheckCRLSignature(crl); err != nil {
+			return err
+		}
+		for _, revoked := range crl.TBSCertList.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return fmt.Errorf("cert %s has been revoked", cert.SerialNumber)
+			}
+		}
+	}
+	return nil
+}
+
+// CaKeyID returns the key of CA to store its CRL on chain, which is the hash of CA public key,
+// so that a CA with the same subject but different key can not replace the CRL
+func CaKeyID(ca *x509.Certificate) string {
+	sum := sha256.Sum256(ca.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseCaRoots parses PEM encoded root CA certs
+func ParseCaRoots(caRoots []string) (*x509.CertPool, error) {
+	if len(caRoots) == 0 {
+		return nil, errors.New("root CA certs are empty")
+	}
+	roots := x509.NewCertPool()
+	for _, caRoot := range caRoots {
+		ca, err := ParsePEMCert([]byte(caRoot))
+		if err != nil {
+			return nil, err
+		}
+		if !ca.IsCA {
+			return nil, errors.New("root cert is not CA")
+		}
+		roots.AddCert(ca)
+	}
+	return roots, nil
+}
+
+// ParsePEMCert parses a PEM encoded cert
+func ParsePEMCert(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("invalid PEM encoded cert")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
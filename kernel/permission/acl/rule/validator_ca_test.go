@@ -0,0 +1,156 @@
+package rule
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/permission/acl/ptree"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	der  []byte
+	key  *ecdsa.PrivateKey
+}
+
+// issueTestCert issues a cert signed by parent, issues a self-signed cert if parent is nil
+func issueTestCert(t *testing.T, serial int64, isCA bool, notAfter time.Time, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "xuper-test-" + big.NewInt(serial).String()},
+		NotBefore:             time.Unix(1600000000, 0),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	parentCert, parentKey := tmpl, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, der: der, key: key}
+}
+
+func (tc *testCert) pem() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tc.der}))
+}
+
+func testCrypto(t *testing.T) base.CryptoClient {
+	xcc, err := client.CreateCryptoClient(client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return xcc
+}
+
+func (tc *testCert) address(t *testing.T) string {
+	addr, err := testCrypto(t).GetAddressFromPublicKey(&tc.key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+type mockCRLReader map[string][]byte
+
+func (m mockCRLReader) GetCaCRL(caKey string) ([]byte, error) {
+	return m[caKey], nil
+}
+
+func Test_CAValidator(t *testing.T) {
+	now := time.Unix(1650000000, 0)
+	expire := now.Add(24 * time.Hour)
+	root := issueTestCert(t, 1, true, expire, nil)
+	inter := issueTestCert(t, 2, true, expire, root)
+	leaf := issueTestCert(t, 3, false, expire, inter)
+	expiredLeaf := issueTestCert(t, 4, false, now.Add(-time.Hour), inter)
+	otherRoot := issueTestCert(t, 5, true, expire, nil)
+	otherLeaf := issueTestCert(t, 6, false, expire, otherRoot)
+
+	validate := func(caRoot *testCert, signer *testCert, chain [][]byte, crls CRLReader) bool {
+		acl := &pb.Acl{
+			Pm:      &pb.PermissionModel{Rule: pb.PermissionRule_CA_SERVER, AcceptValue: 1},
+			CaRoots: []string{caRoot.pem()},
+		}
+		root := ptree.NewPermNode("Test_CAValidator", acl)
+		addr := signer.address(t)
+		root.Children = append(root.Children, &ptree.PermNode{Name: addr, Status: ptree.Success})
+		vf := &ACLValidatorFactory{Certs: &CertContext{
+			Chains: map[string][][]byte{addr: chain},
+			Time:   now,
+			CRLs:   crls,
+			Crypto: testCrypto(t),
+		}}
+		cv, err := vf.GetACLValidator(pb.PermissionRule_CA_SERVER)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := cv.Validate(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	if !validate(root, leaf, [][]byte{leaf.der, inter.der}, nil) {
+		t.Error("valid cert chain should pass")
+	}
+	if validate(root, leaf, [][]byte{leaf.der}, nil) {
+		t.Error("cert chain without intermediate CA should not pass")
+	}
+	if validate(root, expiredLeaf, [][]byte{expiredLeaf.der, inter.der}, nil) {
+		t.Error("expired cert should not pass")
+	}
+	if validate(root, otherLeaf, [][]byte{otherLeaf.der, otherRoot.der}, nil) {
+		t.Error("cert issued by other root CA should not pass")
+	}
+	if validate(root, otherLeaf, [][]byte{leaf.der, inter.der}, nil) {
+		t.Error("cert of other signer should not pass")
+	}
+
+	revoked := []pkix.RevokedCertificate{{SerialNumber: leaf.cert.SerialNumber, RevocationTime: now}}
+	crl, err := inter.cert.CreateCRL(rand.Reader, inter.key, revoked, now, expire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validate(root, leaf, [][]byte{leaf.der, inter.der}, mockCRLReader{CaKeyID(inter.cert): crl}) {
+		t.Error("revoked cert should not pass")
+	}
+	// CRL signed by other CA is invalid
+	fakeCRL, err := otherRoot.cert.CreateCRL(rand.Reader, otherRoot.key, revoked, now, expire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validate(root, leaf, [][]byte{leaf.der, inter.der}, mockCRLReader{CaKeyID(inter.cert): fakeCRL}) {
+		t.Error("cert with invalid CRL should not pass")
+	}
+	emptyCRL, err := inter.cert.CreateCRL(rand.Reader, inter.key, nil, now, expire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !validate(root, leaf, [][]byte{leaf.der, inter.der}, mockCRLReader{CaKeyID(inter.cert): emptyCRL}) {
+		t.Error("cert not in CRL should pass")
+	}
+}
@@ -0,0 +1,50 @@
+package rule
+
+import (
+	"errors"
+
+	"github.com/xuperchain/xupercore/kernel/permission/acl/ptree"
+)
+
+// SignRateValidator is Valiator for SignRate permission model
+// members of ACL are the keys of AksWeight, and the weights are ignored
+type SignRateValidator struct{}
+
+// NewSignRateValidator return instance of SignRateValidator
+func NewSignRateValidator() *SignRateValidator {
+	return &SignRateValidator{}
+}
+
+// Validate implements the interface of ACLValidator
+func (srv *SignRateValidator) Validate(pnode *ptree.PermNode) (bool, error) {
+	if pnode == nil || pnode.ACL == nil || pnode.ACL.Pm == nil {
+		return false, errors.New("Validate: Invalid Param")
+	}
+
+	// empty member list means no one can pass the validation
+	total := len(pnode.ACL.AksWeight)
+	if total == 0 {
+		return false, nil
+	}
+
+	// compare the rate directly, so that a rate like 0.6 matches 3 of 5 exactly
+	rate := float64(countSignedMembers(pnode)) / float64(total)
+	return rate >= pnode.ACL.Pm.AcceptValue, nil
+}
+
+// countSignedMembers returns the number of ACL members passed the validation
+func countSignedMembers(pnode *ptree.PermNode) int {
+	count := 0
+	for _, node := range pnode.Children {
+		// the child account/ak must be passed the validation before
+		if node.Status != ptree.Success {
+			continue
+		}
+
+		// the child account/ak should be member in ACL list
+		if _, ok := pnode.ACL.AksWeight[node.Name]; ok {
+			count++
+		}
+	}
+	return count
+}
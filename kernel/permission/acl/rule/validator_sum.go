@@ -0,0 +1,30 @@
+package rule
+
+import (
+	"errors"
+
+	"github.com/xuperchain/xupercore/kernel/permission/acl/ptree"
+)
+
+// SignSumValidator is Valiator for SignSum permission model
+// members of ACL are the keys of AksWeight, and the weights are ignored
+type SignSumValidator struct{}
+
+// NewSignSumValidator return instance of SignSumValidator
+func NewSignSumValidator() *SignSumValidator {
+	return &SignSumValidator{}
+}
+
+// Validate implements the interface of ACLValidator
+func (ssv *SignSumValidator) Validate(pnode *ptree.PermNode) (bool, error) {
+	if pnode == nil || pnode.ACL == nil || pnode.ACL.Pm == nil {
+		return false, errors.New("Validate: Invalid Param")
+	}
+
+	// empty member list means no one can pass the validation
+	if len(pnode.ACL.AksWeight) == 0 {
+		return false, nil
+	}
+
+	return float64(countSignedMembers(pnode)) >= pnode.ACL.Pm.AcceptValue, nil
+}
@@ -16,8 +16,14 @@ func Test_ValidatorFactory(t *testing.T) {
 	}
 
 	_, err = vf.GetACLValidator(pb.PermissionRule_CA_SERVER)
+	if err != nil {
+		t.Error("CA_SERVER create failed")
+		return
+	}
+
+	_, err = vf.GetACLValidator(pb.PermissionRule_COMMUNITY_VOTE)
 	if err == nil || err.Error() != "This permission rule is not implemented" {
-		t.Error("CA_SERVER error not match")
+		t.Error("COMMUNITY_VOTE error not match")
 		return
 	}
 
@@ -149,3 +155,180 @@ func Test_AkSetsValidator(t *testing.T) {
 		return
 	}
 }
+
+// buildSignTree build perm tree with 5 members, signed marks the members passed the validation
+func buildSignTree(rule pb.PermissionRule, acceptValue float64, signed ...string) *ptree.PermNode {
+	aclObj := &pb.Acl{
+		Pm: &pb.PermissionModel{
+			Rule:        rule,
+			AcceptValue: acceptValue,
+		},
+		AksWeight: map[string]float64{"ak1": 0, "ak2": 0, "ak3": 0, "ak4": 0, "ak5": 0},
+	}
+	rootNode := ptree.NewPermNode("Alice", aclObj)
+	for _, name := range signed {
+		node := ptree.NewPermNode(name, nil)
+		node.Status = ptree.Success
+		rootNode.Children = append(rootNode.Children, node)
+	}
+	return rootNode
+}
+
+func Test_SignRateValidator(t *testing.T) {
+	vf := ACLValidatorFactory{}
+	srv, err := vf.GetACLValidator(pb.PermissionRule_SIGN_RATE)
+	if err != nil {
+		t.Error("SIGN_RATE create failed")
+		return
+	}
+
+	// 2 of 5 is less than 60%, and non-member signature is not counted
+	result, err := srv.Validate(buildSignTree(pb.PermissionRule_SIGN_RATE, 0.6, "ak1", "ak2", "ak6"))
+	if err != nil || result {
+		t.Error("validate failed, should have no error and result is false")
+		return
+	}
+
+	result, err = srv.Validate(buildSignTree(pb.PermissionRule_SIGN_RATE, 0.6, "ak1", "ak2", "ak3"))
+	if err != nil || !result {
+		t.Error("validate failed, should have no error and result is true")
+		return
+	}
+
+	// weights are ignored, empty member list means no one can pass
+	rootNode := buildSignTree(pb.PermissionRule_SIGN_RATE, 0.1, "ak1")
+	rootNode.ACL.AksWeight = nil
+	result, err = srv.Validate(rootNode)
+	if err != nil || result {
+		t.Error("validate failed, empty members should have no error and result is false")
+		return
+	}
+}
+
+func Test_SignSumValidator(t *testing.T) {
+	vf := ACLValidatorFactory{}
+	ssv, err := vf.GetACLValidator(pb.PermissionRule_SIGN_SUM)
+	if err != nil {
+		t.Error("SIGN_SUM create failed")
+		return
+	}
+
+	result, err := ssv.Validate(buildSignTree(pb.PermissionRule_SIGN_SUM, 3, "ak1", "ak2"))
+	if err != nil || result {
+		t.Error("validate failed, should have no error and result is false")
+		return
+	}
+
+	// nested account Bob is member of Alice, and failed its own validation
+	rootNode := buildSignTree(pb.PermissionRule_SIGN_SUM, 3, "ak1", "ak2")
+	rootNode.ACL.AksWeight["Bob"] = 0
+	bobNode := ptree.NewPermNode("Bob", buildSignTree(pb.PermissionRule_SIGN_SUM, 2, "ak7").ACL)
+	bobNode.Children = append(bobNode.Children, ptree.NewPermNode("ak7", nil))
+	bobNode.Children[0].Status = ptree.Success
+	rootNode.Children = append(rootNode.Children, bobNode)
+	bobResult, err := ssv.Validate(bobNode)
+	if err != nil || bobResult {
+		t.Error("validate nested account failed, should have no error and result is false")
+		return
+	}
+	bobNode.Status = ptree.Failed
+	result, err = ssv.Validate(rootNode)
+	if err != nil || result {
+		t.Error("validate failed, failed nested account should not be counted")
+		return
+	}
+
+	// nested account Bob passed its own validation
+	bobNode.Status = ptree.Success
+	result, err = ssv.Validate(rootNode)
+	if err != nil || !result {
+		t.Error("validate failed, should have no error and result is true")
+		return
+	}
+}
+
+func Test_ParseAkSetsExpression(t *testing.T) {
+	valid := []string{"legal", "(legal AND cfo) OR (ceo AND board)", "legal and not (cfo or ceo)", "NOT NOT legal"}
+	for _, expr := range valid {
+		if _, err := ParseAkSetsExpression(expr); err != nil {
+			t.Errorf("parse expression %q failed: %v", expr, err)
+		}
+	}
+
+	invalid := []string{"", "  ", "legal AND", "(legal OR cfo", "legal cfo", "AND legal", "legal OR )", "()", "NOT"}
+	for _, expr := range invalid {
+		if _, err := ParseAkSetsExpression(expr); err == nil {
+			t.Errorf("parse malformed expression %q should fail", expr)
+		}
+	}
+}
+
+func Test_AkSetsValidatorWithExpression(t *testing.T) {
+	vf := ACLValidatorFactory{}
+	akv, err := vf.GetACLValidator(pb.PermissionRule_SIGN_AKSET)
+	if err != nil {
+		t.Error("SIGN_AKSET create failed")
+		return
+	}
+	aclObj := &pb.Acl{
+		Pm: &pb.PermissionModel{
+			Rule: pb.PermissionRule_SIGN_AKSET,
+		},
+		AkSets: &pb.AkSets{
+			Sets: map[string]*pb.AkSet{
+				"legal": {Aks: []string{"ak1"}},
+				"cfo":   {Aks: []string{"ak2"}},
+				"ceo":   {Aks: []string{"ak3"}},
+				"board": {Aks: []string{"ak4", "ak5"}},
+			},
+			Expression:        "(legal AND cfo) OR (ceo AND board)",
+			ExpressionVersion: AkSetsExpressionVersion,
+		},
+	}
+
+	cases := []struct {
+		signed []string
+		expr   string
+		result bool
+	}{
+		{[]string{"ak1", "ak3"}, "", true},
+		{[]string{"ak1", "ak3"}, "(legal AND cfo) OR (ceo AND board)", false},
+		{[]string{"ak1", "ak2"}, "(legal AND cfo) OR (ceo AND board)", true},
+		{[]string{"ak3", "ak4"}, "(legal AND cfo) OR (ceo AND board)", false},
+		{[]string{"ak3", "ak4", "ak5"}, "(legal AND cfo) OR (ceo AND board)", true},
+		{[]string{"ak1"}, "legal AND NOT cfo", true},
+		{[]string{"ak1", "ak2"}, "legal AND NOT cfo", false},
+	}
+	for i, c := range cases {
+		rootNode := ptree.NewPermNode("Alice", aclObj)
+		for _, name := range c.signed {
+			node := ptree.NewPermNode(name, nil)
+			node.Status = ptree.Success
+			rootNode.Children = append(rootNode.Children, node)
+		}
+		aclObj.AkSets.Expression = c.expr
+		result, err := akv.Validate(rootNode)
+		if err != nil || result != c.result {
+			t.Errorf("case %d validate failed, result=%v, err=%v", i, result, err)
+		}
+	}
+
+	// expression refers to unknown AkSet
+	aclObj.AkSets.Expression = "legal OR cto"
+	if _, err := akv.Validate(ptree.NewPermNode("Alice", aclObj)); err == nil {
+		t.Error("validate expression with unknown AkSet should fail")
+	}
+
+	// expression of ACL written before the upgrade is ignored, AkSets are combined with OR
+	aclObj.AkSets.Expression = "(legal AND cfo) OR (ceo AND board)"
+	aclObj.AkSets.ExpressionVersion = 0
+	rootNode := ptree.NewPermNode("Alice", aclObj)
+	for _, name := range []string{"ak1", "ak3"} {
+		node := ptree.NewPermNode(name, nil)
+		node.Status = ptree.Success
+		rootNode.Children = append(rootNode.Children, node)
+	}
+	if result, err := akv.Validate(rootNode); err != nil || !result {
+		t.Errorf("expression without version should be ignored, result=%v, err=%v", result, err)
+	}
+}
@@ -15,6 +15,7 @@ const (
 	contract2AccountBucket = "XCContract2Account"
 	account2ContractBucket = "XCAccount2Contract"
 	ak2AccountBucket       = "XCAK2Account"
+	caCRLBucket            = "XCCaCRL"
 	akLimit                = 1024
 	aclSeparator           = "\x01"
 	accountBcnameSep       = "@"
@@ -57,6 +58,11 @@ func GetAccountPrefix() string {
 	return accountPrefix
 }
 
+// GetCaCRLBucket return the bucket name of CRLs issued by CA
+func GetCaCRLBucket() string {
+	return caCRLBucket
+}
+
 // GetAccountBucket return the account bucket name
 func GetAccountBucket() string {
 	return accountBucket
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/kernel/permission/acl/base"
 	"github.com/xuperchain/xupercore/kernel/permission/acl/ptree"
 	"github.com/xuperchain/xupercore/kernel/permission/acl/rule"
@@ -21,6 +22,13 @@ func IdentifyAK(akURI string, sign *pb.SignatureInfo, msg []byte) (bool, error)
 }
 
 func IdentifyAccount(aclMgr base.AclManager, account string, akURIs []string) (bool, error) {
+	return IdentifyAccountWithCerts(aclMgr, account, akURIs, nil)
+}
+
+// IdentifyAccountWithCerts likes IdentifyAccount, certs are the X.509 cert chains of signers
+// which are required by CA_SERVER permission model
+func IdentifyAccountWithCerts(aclMgr base.AclManager, account string, akURIs []string,
+	certs *rule.CertContext) (bool, error) {
 	// aks and signs could have zero length for permission rule Null
 	if aclMgr == nil {
 		return false, fmt.Errorf("Invalid Param, aclMgr=%v", aclMgr)
@@ -32,11 +40,18 @@ func IdentifyAccount(aclMgr base.AclManager, account string, akURIs []string) (b
 		return false, err
 	}
 
-	return validatePermTree(tree, true)
+	return validatePermTree(aclMgr, tree, true, certs)
 }
 
 func CheckContractMethodPerm(aclMgr base.AclManager, akURIs []string,
 	contractName, methodName string) (bool, error) {
+	return CheckContractMethodPermWithCerts(aclMgr, akURIs, contractName, methodName, nil)
+}
+
+// CheckContractMethodPermWithCerts likes CheckContractMethodPerm, certs are the X.509 cert chains
+// of signers which are required by CA_SERVER permission model
+func CheckContractMethodPermWithCerts(aclMgr base.AclManager, akURIs []string,
+	contractName, methodName string, certs *rule.CertContext) (bool, error) {
 
 	// aks and signs could have zero length for permission rule Null
 	if aclMgr == nil {
@@ -50,10 +65,11 @@ func CheckContractMethodPerm(aclMgr base.AclManager, akURIs []string,
 	}
 
 	// validate perm tree
-	return validatePermTree(tree, false)
+	return validatePermTree(aclMgr, tree, false, certs)
 }
 
-func validatePermTree(root *ptree.PermNode, isAccount bool) (bool, error) {
+func validatePermTree(aclMgr base.AclManager, root *ptree.PermNode, isAccount bool,
+	certs *rule.CertContext) (bool, error) {
 	if root == nil {
 		return false, errors.New("Root is null")
 	}
@@ -64,7 +80,7 @@ func validatePermTree(root *ptree.PermNode, isAccount bool) (bool, error) {
 		return false, err
 	}
 	size := len(plist)
-	vf := &rule.ACLValidatorFactory{}
+	vf := &rule.ACLValidatorFactory{Certs: certs}
 
 	// reverse travel the perm tree
 	for i := size - 1; i >= 0; i-- {
@@ -116,6 +132,26 @@ func validatePermTree(root *ptree.PermNode, isAccount bool) (bool, error) {
 	return root.Status == ptree.Success, nil
 }
 
+// crlReader reads CRLs stored on chain through a snapshot reader
+type crlReader struct {
+	reader ledger.XMSnapshotReader
+}
+
+// NewCRLReader returns CRLReader which reads CRLs through reader, the reader should be
+// pinned to the block containing the tx so that all nodes get the same result
+func NewCRLReader(reader ledger.XMSnapshotReader) rule.CRLReader {
+	return &crlReader{reader: reader}
+}
+
+// GetCaCRL implements the interface of CRLReader
+func (r *crlReader) GetCaCRL(caKey string) ([]byte, error) {
+	crl, err := r.reader.Get(GetCaCRLBucket(), []byte(caKey))
+	if err != nil {
+		return nil, fmt.Errorf("query ca crl failed.err:%v", err)
+	}
+	return crl, nil
+}
+
 // ExtractAkFromAuthRequire extracts required AK from auth requirement
 // return AK in `Account/AK`
 func ExtractAkFromAuthRequire(authRequire string) string {
@@ -0,0 +1,225 @@
+// Package bls implements BLS signatures over BLS12-381 curve,
+// signatures are points of G1 and public keys are points of G2.
+// Signatures of the same message can be aggregated into one signature,
+// and verified with the aggregated public key of all signers.
+// 聚合验证要求每个公钥都已通过持有证明(proof of possession)校验，以防止rogue key攻击
+package bls
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// PrivateKeySize 私钥序列化长度
+	PrivateKeySize = fr.Bytes
+	// PublicKeySize 公钥序列化长度，G2压缩格式
+	PublicKeySize = bls12381.SizeOfG2AffineCompressed
+	// SignatureSize 签名序列化长度，G1压缩格式
+	SignatureSize = bls12381.SizeOfG1AffineCompressed
+)
+
+var (
+	// 签名和持有证明使用不同的domain，避免持有证明被当作普通签名使用
+	signDST = []byte("XUPER_BLS_SIG_BLS12381G1_XMD:SHA-256_SVDW_RO_POP_")
+	popDST  = []byte("XUPER_BLS_POP_BLS12381G1_XMD:SHA-256_SVDW_RO_POP_")
+
+	g2Gen    bls12381.G2Affine
+	g2GenNeg bls12381.G2Affine
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New("invalid bls private key")
+	ErrInvalidPublicKey  = errors.New("invalid bls public key")
+	ErrInvalidSignature  = errors.New("invalid bls signature")
+	ErrEmptyAggregation  = errors.New("nothing to aggregate")
+)
+
+func init() {
+	_, _, _, g2Gen = bls12381.Generators()
+	g2GenNeg.Neg(&g2Gen)
+}
+
+// PrivateKey BLS私钥
+type PrivateKey struct {
+	x   *big.Int
+	pub *PublicKey
+}
+
+// PublicKey BLS公钥
+type PublicKey struct {
+	p bls12381.G2Affine
+}
+
+// GenerateKey 使用随机源生成私钥，random为nil时使用crypto/rand
+func GenerateKey(random io.Reader) (*PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	for {
+		x, err := rand.Int(random, fr.Modulus())
+		if err != nil {
+			return nil, err
+		}
+		if x.Sign() > 0 {
+			return newPrivateKey(x), nil
+		}
+	}
+}
+
+// NewPrivateKey 从大端序的字节数组恢复私钥
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeySize {
+		return nil, ErrInvalidPrivateKey
+	}
+	x := new(big.Int).SetBytes(b)
+	if x.Sign() <= 0 || x.Cmp(fr.Modulus()) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+	return newPrivateKey(x), nil
+}
+
+func newPrivateKey(x *big.Int) *PrivateKey {
+	pub := &PublicKey{}
+	pub.p.ScalarMultiplication(&g2Gen, x)
+	return &PrivateKey{x: x, pub: pub}
+}
+
+// Bytes 私钥序列化
+func (sk *PrivateKey) Bytes() []byte {
+	b := make([]byte, PrivateKeySize)
+	return sk.x.FillBytes(b)
+}
+
+// PublicKey 获取私钥对应的公钥
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return sk.pub
+}
+
+// Sign 对消息签名，返回G1压缩格式的签名
+func (sk *PrivateKey) Sign(msg []byte) ([]byte, error) {
+	return sk.sign(msg, signDST)
+}
+
+// ProvePossession 生成持有证明，即使用独立domain对公钥本身的签名
+func (sk *PrivateKey) ProvePossession() ([]byte, error) {
+	return sk.sign(sk.pub.Bytes(), popDST)
+}
+
+func (sk *PrivateKey) sign(msg, dst []byte) ([]byte, error) {
+	h, err := bls12381.HashToCurveG1Svdw(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	var sig bls12381.G1Affine
+	sig.ScalarMultiplication(&h, sk.x)
+	b := sig.Bytes()
+	return b[:], nil
+}
+
+// NewPublicKey 从G2压缩格式恢复公钥，会检查公钥是否在子群中且不为无穷远点
+func NewPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	pk := &PublicKey{}
+	if _, err := pk.p.SetBytes(b); err != nil || pk.p.IsInfinity() {
+		return nil, ErrInvalidPublicKey
+	}
+	return pk, nil
+}
+
+// Bytes 公钥序列化，G2压缩格式
+func (pk *PublicKey) Bytes() []byte {
+	b := pk.p.Bytes()
+	return b[:]
+}
+
+// Verify 验证单个签名
+func (pk *PublicKey) Verify(msg, sig []byte) bool {
+	return verify(&pk.p, msg, sig, signDST)
+}
+
+// VerifyPossession 验证公钥的持有证明，通过验证的公钥才能参与聚合验证
+func (pk *PublicKey) VerifyPossession(proof []byte) bool {
+	return verify(&pk.p, pk.Bytes(), proof, popDST)
+}
+
+// AggregateSignatures 聚合多个签名
+func AggregateSignatures(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	var agg bls12381.G1Jac
+	for _, sig := range sigs {
+		p, err := parseSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+		var pj bls12381.G1Jac
+		pj.FromAffine(p)
+		agg.AddAssign(&pj)
+	}
+	var res bls12381.G1Affine
+	res.FromJacobian(&agg)
+	b := res.Bytes()
+	return b[:], nil
+}
+
+// AggregatePublicKeys 聚合多个公钥
+func AggregatePublicKeys(pks []*PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	var agg bls12381.G2Jac
+	for _, pk := range pks {
+		if pk == nil {
+			return nil, ErrInvalidPublicKey
+		}
+		var pj bls12381.G2Jac
+		pj.FromAffine(&pk.p)
+		agg.AddAssign(&pj)
+	}
+	res := &PublicKey{}
+	res.p.FromJacobian(&agg)
+	return res, nil
+}
+
+// VerifyAggregate 验证多个签名者对同一消息的聚合签名
+func VerifyAggregate(pks []*PublicKey, msg, aggSig []byte) bool {
+	aggPk, err := AggregatePublicKeys(pks)
+	if err != nil {
+		return false
+	}
+	return aggPk.Verify(msg, aggSig)
+}
+
+// verify 检查 e(sig, g2) == e(H(msg), pk)，即 e(sig, -g2) * e(H(msg), pk) == 1
+func verify(pk *bls12381.G2Affine, msg, sig, dst []byte) bool {
+	s, err := parseSignature(sig)
+	if err != nil {
+		return false
+	}
+	h, err := bls12381.HashToCurveG1Svdw(msg, dst)
+	if err != nil {
+		return false
+	}
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{*s, h}, []bls12381.G2Affine{g2GenNeg, *pk})
+	return err == nil && ok
+}
+
+func parseSignature(sig []byte) (*bls12381.G1Affine, error) {
+	if len(sig) != SignatureSize {
+		return nil, ErrInvalidSignature
+	}
+	p := &bls12381.G1Affine{}
+	if _, err := p.SetBytes(sig); err != nil || p.IsInfinity() {
+		return nil, ErrInvalidSignature
+	}
+	return p, nil
+}
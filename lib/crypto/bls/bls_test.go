@@ -0,0 +1,121 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func genKeys(t *testing.T, n int) []*PrivateKey {
+	keys := make([]*PrivateKey, 0, n)
+	for i := 0; i < n; i++ {
+		sk, err := GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, sk)
+	}
+	return keys
+}
+
+func TestSignVerify(t *testing.T) {
+	sk := genKeys(t, 1)[0]
+	msg := []byte("this is a test msg")
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig) != SignatureSize {
+		t.Fatalf("unexpected signature size %d", len(sig))
+	}
+	if !sk.PublicKey().Verify(msg, sig) {
+		t.Fatal("verify signature failed")
+	}
+	if sk.PublicKey().Verify([]byte("another msg"), sig) {
+		t.Fatal("signature should not match another msg")
+	}
+
+	// 序列化后恢复的密钥应保持一致
+	sk2, err := NewPrivateKey(sk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := NewPublicKey(sk2.PublicKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pk.Bytes(), sk.PublicKey().Bytes()) || !pk.Verify(msg, sig) {
+		t.Fatal("restored key not match")
+	}
+}
+
+func TestPossession(t *testing.T) {
+	keys := genKeys(t, 2)
+	proof, err := keys[0].ProvePossession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !keys[0].PublicKey().VerifyPossession(proof) {
+		t.Fatal("verify possession failed")
+	}
+	if keys[1].PublicKey().VerifyPossession(proof) {
+		t.Fatal("proof should not match another key")
+	}
+	// 持有证明与普通签名不能互用
+	if keys[0].PublicKey().Verify(keys[0].PublicKey().Bytes(), proof) {
+		t.Fatal("proof should not be a valid signature")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	keys := genKeys(t, 4)
+	msg := []byte("proposal")
+	var sigs [][]byte
+	var pks []*PublicKey
+	for _, sk := range keys {
+		sig, err := sk.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+		pks = append(pks, sk.PublicKey())
+	}
+	agg, err := AggregateSignatures(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyAggregate(pks, msg, agg) {
+		t.Fatal("verify aggregate signature failed")
+	}
+	if VerifyAggregate(pks[:3], msg, agg) {
+		t.Fatal("aggregate signature should not match part of signers")
+	}
+	part, _ := AggregateSignatures(sigs[1:])
+	if !VerifyAggregate(pks[1:], msg, part) {
+		t.Fatal("verify partial aggregate signature failed")
+	}
+	if VerifyAggregate(pks, []byte("other"), agg) {
+		t.Fatal("aggregate signature should not match another msg")
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	if _, err := NewPrivateKey(make([]byte, PrivateKeySize)); err == nil {
+		t.Fatal("zero private key should be rejected")
+	}
+	if _, err := NewPublicKey(make([]byte, PublicKeySize-1)); err == nil {
+		t.Fatal("short public key should be rejected")
+	}
+	if _, err := NewPublicKey(bytes.Repeat([]byte{0xff}, PublicKeySize)); err == nil {
+		t.Fatal("malformed public key should be rejected")
+	}
+	if _, err := AggregateSignatures(nil); err == nil {
+		t.Fatal("empty aggregation should be rejected")
+	}
+	if _, err := AggregateSignatures([][]byte{[]byte("bad")}); err == nil {
+		t.Fatal("malformed signature should be rejected")
+	}
+	sk := genKeys(t, 1)[0]
+	if sk.PublicKey().Verify([]byte("msg"), make([]byte, SignatureSize)) {
+		t.Fatal("malformed signature should not pass")
+	}
+}
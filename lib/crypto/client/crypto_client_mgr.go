@@ -9,6 +9,7 @@ import (
 	"github.com/xuperchain/crypto/core/account"
 	"github.com/xuperchain/xupercore/lib/crypto/client/base"
 	"github.com/xuperchain/xupercore/lib/crypto/client/gm"
+	"github.com/xuperchain/xupercore/lib/crypto/client/schnorr"
 	"github.com/xuperchain/xupercore/lib/crypto/client/xchain"
 )
 
@@ -67,6 +68,7 @@ func CreateCryptoClient(cryptoType string) (base.CryptoClient, error) {
 func init() {
 	Register(CryptoTypeDefault, NewCryptoFunc(eccdefault.GetInstance))
 	Register(CryptoTypeGM, NewCryptoFunc(gm.GetInstance))
+	Register(CryptoTypeSchnorr, NewCryptoFunc(schnorr.GetInstance))
 }
 
 // CreateCryptoClientFromJSONPublicKey create CryptoClient by json encoded public key
@@ -115,7 +117,7 @@ func getTypeByCurveName(name string) (string, error) {
 		return CryptoTypeDefault, nil
 	case "SM2-P-256":
 		return CryptoTypeGM, nil
-	case "P-256-SN":
+	case schnorr.CurveName:
 		return CryptoTypeSchnorr, nil
 	default:
 		return "", errors.New("Unknown curve name")
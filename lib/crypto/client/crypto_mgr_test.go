@@ -56,3 +56,35 @@ func Test_CreateCryptoClientByPK(t *testing.T) {
 		return
 	}
 }
+
+func Test_CreateSchnorrCryptoClientByPK(t *testing.T) {
+	cc, err := CreateCryptoClient(CryptoTypeSchnorr)
+	if err != nil {
+		t.Fatalf("gen schnorr crypto client fail.err:%v", err)
+	}
+	key, err := cc.GenerateKeyBySeed([]byte("Hello World Hello World"))
+	if err != nil {
+		t.Fatalf("gen key fail.err:%v", err)
+	}
+	pubKey, err := cc.GetEcdsaPublicKeyJsonFormatStr(key)
+	if err != nil {
+		t.Fatalf("GetEcdsaPublicKeyJsonFormatStr failed, err=%v", err)
+	}
+	msg := []byte("This is test msg")
+	sign, err := cc.SignECDSA(key, msg)
+	if err != nil {
+		t.Fatalf("SignECDSA failed, err=%v", err)
+	}
+
+	cc, err = CreateCryptoClientFromJSONPublicKey([]byte(pubKey))
+	if err != nil {
+		t.Fatalf("create crypto client by pub key fail.err:%v", err)
+	}
+	ecdPubKey, err := cc.GetEcdsaPublicKeyFromJsonStr(pubKey)
+	if err != nil {
+		t.Fatalf("GetEcdsaPublicKeyFromJSON failed, err=%v", err)
+	}
+	if ok, err := cc.VerifyECDSA(ecdPubKey, sign, msg); err != nil || !ok {
+		t.Fatalf("VerifyECDSA failed, err=%v", err)
+	}
+}
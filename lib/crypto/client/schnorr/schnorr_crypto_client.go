@@ -0,0 +1,266 @@
+// Package schnorr is the crypto client of xchain using Nist P-256 curve with Schnorr signature
+package schnorr
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+
+	"github.com/xuperchain/crypto/client/service/xchain"
+	"github.com/xuperchain/crypto/common/account"
+	"github.com/xuperchain/crypto/core/common"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+)
+
+// CurveName 密钥json中的曲线名，用于区分Schnorr签名和默认的ECDSA签名，
+// 曲线参数与P-256相同，地址也与相同私钥的P-256地址一致
+const CurveName = "P-256-SN"
+
+// ErrInvalidSignature is returned when the signature is not a valid schnorr signature
+var ErrInvalidSignature = errors.New("invalid schnorr signature")
+
+// make sure this plugin implemented the interface
+var _ base.CryptoClient = (*SchnorrCryptoClient)(nil)
+
+// SchnorrCryptoClient is the implementation for Nist P-256 + Schnorr crypto,
+// only the json format of keys and the signature algorithm differ from the default crypto
+type SchnorrCryptoClient struct {
+	xchain.XchainCryptoClient
+}
+
+func GetInstance() base.CryptoClient {
+	snCryptoClient := SchnorrCryptoClient{}
+	return &snCryptoClient
+}
+
+// jsonPrivateKey 私钥json格式，与默认密码学库的格式一致
+type jsonPrivateKey struct {
+	Curvname string
+	X, Y, D  *big.Int
+}
+
+// jsonPublicKey 公钥json格式，与默认密码学库的格式一致
+type jsonPublicKey struct {
+	Curvname string
+	X, Y     *big.Int
+}
+
+// SignECDSA 使用Schnorr签名算法签名，生成统一签名格式XuperSignature
+func (scc *SchnorrCryptoClient) SignECDSA(k *ecdsa.PrivateKey, msg []byte) ([]byte, error) {
+	return scc.SignSchnorr(k, msg)
+}
+
+// VerifyECDSA 验证Schnorr签名，只接受统一签名格式XuperSignature的Schnorr签名
+func (scc *SchnorrCryptoClient) VerifyECDSA(k *ecdsa.PublicKey, signature, msg []byte) (bool, error) {
+	xuperSig := new(common.XuperSignature)
+	if err := json.Unmarshal(signature, xuperSig); err != nil {
+		return false, ErrInvalidSignature
+	}
+	if xuperSig.SigType != common.Schnorr {
+		return false, fmt.Errorf("signature type [%v] is not supported", xuperSig.SigType)
+	}
+	return scc.verifySchnorrContent(k, xuperSig.SigContent, msg)
+}
+
+// VerifyXuperSignature 统一验签接口，只支持Schnorr签名和基于Schnorr的多重签名
+func (scc *SchnorrCryptoClient) VerifyXuperSignature(publicKeys []*ecdsa.PublicKey, sig []byte, message []byte) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, errors.New("public keys are empty")
+	}
+	xuperSig := new(common.XuperSignature)
+	if err := json.Unmarshal(sig, xuperSig); err != nil {
+		return false, ErrInvalidSignature
+	}
+	switch xuperSig.SigType {
+	case common.Schnorr:
+		if len(publicKeys) != 1 {
+			return false, errors.New("schnorr signature requires exactly one public key")
+		}
+		return scc.verifySchnorrContent(publicKeys[0], xuperSig.SigContent, message)
+	case common.MultiSig:
+		return scc.XchainCryptoClient.VerifyXuperSignature(publicKeys, sig, message)
+	default:
+		return false, fmt.Errorf("signature type [%v] is not supported", xuperSig.SigType)
+	}
+}
+
+// verifySchnorrContent 校验签名内容后验签，避免恶意签名导致底层库panic
+func (scc *SchnorrCryptoClient) verifySchnorrContent(k *ecdsa.PublicKey, content, msg []byte) (bool, error) {
+	if k == nil || k.X == nil || k.Y == nil || !k.Curve.IsOnCurve(k.X, k.Y) {
+		return false, errors.New("invalid public key")
+	}
+	schnorrSig := new(common.SchnorrSignature)
+	if err := json.Unmarshal(content, schnorrSig); err != nil {
+		return false, ErrInvalidSignature
+	}
+	if schnorrSig.E == nil || schnorrSig.S == nil || schnorrSig.E.Sign() <= 0 || schnorrSig.S.Sign() <= 0 {
+		return false, ErrInvalidSignature
+	}
+	return scc.VerifySchnorr(k, content, msg)
+}
+
+// GetEcdsaPrivateKeyJsonFormatStr 获取私钥的json格式的表达
+func (scc *SchnorrCryptoClient) GetEcdsaPrivateKeyJsonFormatStr(k *ecdsa.PrivateKey) (string, error) {
+	data, err := json.Marshal(&jsonPrivateKey{Curvname: CurveName, X: k.X, Y: k.Y, D: k.D})
+	return string(data), err
+}
+
+// GetEcdsaPublicKeyJsonFormatStr 通过私钥获取公钥的json格式的表达
+func (scc *SchnorrCryptoClient) GetEcdsaPublicKeyJsonFormatStr(k *ecdsa.PrivateKey) (string, error) {
+	return scc.GetEcdsaPublicKeyJsonFormatStrFromPublicKey(&k.PublicKey)
+}
+
+// GetEcdsaPublicKeyJsonFormatStrFromPublicKey 获取公钥的json格式的表达
+func (scc *SchnorrCryptoClient) GetEcdsaPublicKeyJsonFormatStrFromPublicKey(k *ecdsa.PublicKey) (string, error) {
+	data, err := json.Marshal(&jsonPublicKey{Curvname: CurveName, X: k.X, Y: k.Y})
+	return string(data), err
+}
+
+// GetEcdsaPrivateKeyFromJsonStr 从json格式私钥内容字符串产生私钥
+func (scc *SchnorrCryptoClient) GetEcdsaPrivateKeyFromJsonStr(keyStr string) (*ecdsa.PrivateKey, error) {
+	key := new(jsonPrivateKey)
+	if err := json.Unmarshal([]byte(keyStr), key); err != nil {
+		return nil, err
+	}
+	if key.Curvname != CurveName {
+		return nil, fmt.Errorf("curve [%v] is not supported yet", key.Curvname)
+	}
+	privateKey := &ecdsa.PrivateKey{D: key.D}
+	privateKey.Curve = elliptic.P256()
+	privateKey.X, privateKey.Y = key.X, key.Y
+	return privateKey, nil
+}
+
+// GetEcdsaPublicKeyFromJsonStr 从json格式公钥内容字符串产生公钥
+func (scc *SchnorrCryptoClient) GetEcdsaPublicKeyFromJsonStr(keyStr string) (*ecdsa.PublicKey, error) {
+	key := new(jsonPublicKey)
+	if err := json.Unmarshal([]byte(keyStr), key); err != nil {
+		return nil, err
+	}
+	if key.Curvname != CurveName {
+		return nil, fmt.Errorf("curve [%v] is not supported yet", key.Curvname)
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: key.X, Y: key.Y}, nil
+}
+
+// GetEcdsaPrivateKeyFromFile 从导出的私钥文件读取私钥
+func (scc *SchnorrCryptoClient) GetEcdsaPrivateKeyFromFile(filename string) (*ecdsa.PrivateKey, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return scc.GetEcdsaPrivateKeyFromJsonStr(string(content))
+}
+
+// GetEcdsaPublicKeyFromFile 从导出的公钥文件读取公钥
+func (scc *SchnorrCryptoClient) GetEcdsaPublicKeyFromFile(filename string) (*ecdsa.PublicKey, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return scc.GetEcdsaPublicKeyFromJsonStr(string(content))
+}
+
+// ExportNewAccount 创建新账户(不使用助记词，不推荐使用)，生成私钥、公钥、地址文件
+func (scc *SchnorrCryptoClient) ExportNewAccount(path string) error {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	ecdsaAccount, err := scc.newAccount(privateKey)
+	if err != nil {
+		return err
+	}
+	return exportAccount(path, ecdsaAccount)
+}
+
+// CreateNewAccountWithMnemonic 创建含有助记词的新的账户
+func (scc *SchnorrCryptoClient) CreateNewAccountWithMnemonic(language int, strength uint8) (*account.ECDSAAccount, error) {
+	ecdsaAccount, err := scc.XchainCryptoClient.CreateNewAccountWithMnemonic(language, strength)
+	if err != nil {
+		return nil, err
+	}
+	return scc.convertAccount(ecdsaAccount)
+}
+
+// ExportNewAccountWithMnemonic 创建新的账户，并导出助记词、私钥、公钥、地址文件
+func (scc *SchnorrCryptoClient) ExportNewAccountWithMnemonic(path string, language int, strength uint8) error {
+	ecdsaAccount, err := scc.CreateNewAccountWithMnemonic(language, strength)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(path, "mnemonic"), []byte(ecdsaAccount.Mnemonic), 0666)
+	if err != nil {
+		return err
+	}
+	return exportAccount(path, ecdsaAccount)
+}
+
+// RetrieveAccountByMnemonic 从助记词恢复钱包账户
+func (scc *SchnorrCryptoClient) RetrieveAccountByMnemonic(mnemonic string, language int) (*account.ECDSAAccount, error) {
+	ecdsaAccount, err := scc.XchainCryptoClient.RetrieveAccountByMnemonic(mnemonic, language)
+	if err != nil {
+		return nil, err
+	}
+	return scc.convertAccount(ecdsaAccount)
+}
+
+// convertAccount 将默认密码学库生成的账户转换为Schnorr账户，私钥和地址不变
+func (scc *SchnorrCryptoClient) convertAccount(ecdsaAccount *account.ECDSAAccount) (*account.ECDSAAccount, error) {
+	privateKey, err := scc.XchainCryptoClient.GetEcdsaPrivateKeyFromJsonStr(ecdsaAccount.JsonPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	snAccount, err := scc.newAccount(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	snAccount.EntropyByte = ecdsaAccount.EntropyByte
+	snAccount.Mnemonic = ecdsaAccount.Mnemonic
+	return snAccount, nil
+}
+
+func (scc *SchnorrCryptoClient) newAccount(privateKey *ecdsa.PrivateKey) (*account.ECDSAAccount, error) {
+	jsonPrivateKey, err := scc.GetEcdsaPrivateKeyJsonFormatStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	jsonPublicKey, err := scc.GetEcdsaPublicKeyJsonFormatStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	address, err := scc.GetAddressFromPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &account.ECDSAAccount{
+		JsonPrivateKey: jsonPrivateKey,
+		JsonPublicKey:  jsonPublicKey,
+		Address:        address,
+	}, nil
+}
+
+// exportAccount 导出私钥、公钥、地址文件
+func exportAccount(path string, ecdsaAccount *account.ECDSAAccount) error {
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"private.key", ecdsaAccount.JsonPrivateKey},
+		{"public.key", ecdsaAccount.JsonPublicKey},
+		{"address", ecdsaAccount.Address},
+	}
+	for _, file := range files {
+		err := ioutil.WriteFile(filepath.Join(path, file.name), []byte(file.content), 0666)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
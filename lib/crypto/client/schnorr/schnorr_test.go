@@ -0,0 +1,137 @@
+package schnorr
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	eccdefault "github.com/xuperchain/xupercore/lib/crypto/client/xchain"
+)
+
+func Test_Schnorr(t *testing.T) {
+	scc := GetInstance()
+
+	dir, err := ioutil.TempDir("", "schnorr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := scc.ExportNewAccount(dir); err != nil {
+		t.Fatalf("generate key failed, err=%v", err)
+	}
+	priv, err := scc.GetEcdsaPrivateKeyFromFile(dir + "/private.key")
+	if err != nil {
+		t.Fatalf("GetEcdsaPrivateKeyFromFile failed, err=%v", err)
+	}
+	pub, err := scc.GetEcdsaPublicKeyFromFile(dir + "/public.key")
+	if err != nil {
+		t.Fatalf("GetEcdsaPublicKeyFromFile failed, err=%v", err)
+	}
+	addr, err := ioutil.ReadFile(dir + "/address")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := scc.VerifyAddressUsingPublicKey(string(addr), pub); !ok {
+		t.Fatal("address and public key not match")
+	}
+
+	jsonPub, err := scc.GetEcdsaPublicKeyJsonFormatStr(priv)
+	if err != nil || !strings.Contains(jsonPub, CurveName) {
+		t.Fatalf("public key json should contain curve name %s, got %s", CurveName, jsonPub)
+	}
+	// keys of schnorr and default crypto can not be mixed
+	if _, err := eccdefault.GetInstance().GetEcdsaPublicKeyFromJsonStr(jsonPub); err == nil {
+		t.Fatal("default crypto should not accept schnorr public key")
+	}
+
+	msg := []byte("this is a test msg")
+	sign, err := scc.SignECDSA(priv, msg)
+	if err != nil {
+		t.Fatalf("SignECDSA failed, err=%v", err)
+	}
+	if ok, err := scc.VerifyECDSA(pub, sign, msg); err != nil || !ok {
+		t.Fatalf("VerifyECDSA failed, err=%v", err)
+	}
+	if ok, err := scc.VerifyXuperSignature([]*ecdsa.PublicKey{pub}, sign, msg); err != nil || !ok {
+		t.Fatalf("VerifyXuperSignature failed, err=%v", err)
+	}
+	if ok, _ := scc.VerifyECDSA(pub, sign, []byte("this is another msg")); ok {
+		t.Fatal("VerifyECDSA should fail with wrong msg")
+	}
+	ecdsaSign, err := eccdefault.GetInstance().SignECDSA(priv, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := scc.VerifyECDSA(pub, ecdsaSign, msg); ok {
+		t.Fatal("VerifyECDSA should not accept ecdsa signature")
+	}
+}
+
+func Test_SchnorrMultiSig(t *testing.T) {
+	scc := GetInstance()
+	keys := make([]*ecdsa.PrivateKey, 0, 3)
+	pubs := make([]*ecdsa.PublicKey, 0, 3)
+	for _, seed := range []string{"alice-seed-0123456789", "bob-seed-0123456789", "carol-seed-0123456789"} {
+		key, err := scc.GenerateKeyBySeed([]byte(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		pubs = append(pubs, &key.PublicKey)
+	}
+
+	msg := []byte("this is a multisig test msg")
+	sign, err := scc.MultiSign(keys, msg)
+	if err != nil {
+		t.Fatalf("MultiSign failed, err=%v", err)
+	}
+	if ok, err := scc.VerifyXuperSignature(pubs, sign, msg); err != nil || !ok {
+		t.Fatalf("VerifyXuperSignature failed, err=%v", err)
+	}
+	if ok, _ := scc.VerifyXuperSignature(pubs[:2], sign, msg); ok {
+		t.Fatal("VerifyXuperSignature should fail with missing public key")
+	}
+}
+
+func Test_SchnorrMnemonic(t *testing.T) {
+	scc := GetInstance()
+	acc, err := scc.CreateNewAccountWithMnemonic(1, 1)
+	if err != nil {
+		t.Fatalf("CreateNewAccountWithMnemonic failed, err=%v", err)
+	}
+	if !strings.Contains(acc.JsonPrivateKey, CurveName) || !strings.Contains(acc.JsonPublicKey, CurveName) {
+		t.Fatal("keys of account should be schnorr keys")
+	}
+	retrieved, err := scc.RetrieveAccountByMnemonic(acc.Mnemonic, 1)
+	if err != nil {
+		t.Fatalf("RetrieveAccountByMnemonic failed, err=%v", err)
+	}
+	if retrieved.Address != acc.Address || retrieved.JsonPrivateKey != acc.JsonPrivateKey {
+		t.Fatal("retrieved account not match")
+	}
+}
+
+func Test_SchnorrInvalidSignature(t *testing.T) {
+	scc := GetInstance()
+	key, err := scc.GenerateKeyBySeed([]byte("alice-seed-0123456789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("this is a test msg")
+	invalidSigns := []string{
+		``,
+		`{"SigType":"Schnorr","SigContent":"e30="}`,
+		`{"SigType":"Schnorr","SigContent":"eyJFIjoxfQ=="}`,
+		`{"SigType":"ECDSA","SigContent":"e30="}`,
+	}
+	for _, sign := range invalidSigns {
+		if ok, _ := scc.VerifyECDSA(&key.PublicKey, []byte(sign), msg); ok {
+			t.Fatalf("VerifyECDSA should fail with signature %s", sign)
+		}
+		if ok, _ := scc.VerifyXuperSignature([]*ecdsa.PublicKey{&key.PublicKey}, []byte(sign), msg); ok {
+			t.Fatalf("VerifyXuperSignature should fail with signature %s", sign)
+		}
+	}
+}
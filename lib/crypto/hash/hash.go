@@ -6,7 +6,9 @@ import (
 	"crypto/sha256"
 	"crypto/sha512"
 
+	"github.com/xuperchain/crypto/gm/gmsm/sm3"
 	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
 )
 
 // UsingSha256 get the hash result of data using SHA256
@@ -39,3 +41,17 @@ func HashUsingHmac512(seed, key []byte) []byte {
 
 	return out
 }
+
+// UsingSM3 get the hash result of data using SM3
+func UsingSM3(data []byte) []byte {
+	h := sm3.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// UsingSha3 get the hash result of data using SHA3-256
+func UsingSha3(data []byte) []byte {
+	h := sha3.New256()
+	h.Write(data)
+	return h.Sum(nil)
+}
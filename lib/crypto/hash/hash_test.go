@@ -1,6 +1,8 @@
 package hash
 
 import (
+	"bytes"
+	"encoding/hex"
 	"testing"
 )
 
@@ -16,3 +18,47 @@ func Test_Hash(t *testing.T) {
 
 	t.Logf("sha256=%v, doubleSha256=%v, ripemd160=%v, hmac512=%v\n", sha256, doubleSha256, ripemd160, hmac512)
 }
+
+func Test_Hasher(t *testing.T) {
+	msg := []byte("abc")
+	// 标准测试向量
+	expects := map[string]string{
+		HashTypeSha256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		HashTypeSM3:    "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0",
+		HashTypeSha3:   "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+	}
+	for hashType, expect := range expects {
+		h, err := GetHasher(hashType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		single := h.New()
+		single.Write(msg)
+		first := single.Sum(nil)
+		if hex.EncodeToString(first) != expect {
+			t.Fatalf("%s hash not match, got %x", hashType, first)
+		}
+		h2 := h.New()
+		h2.Write(first)
+		if !bytes.Equal(h.DoubleHash(msg), h2.Sum(nil)) {
+			t.Fatalf("%s double hash not match", hashType)
+		}
+		if !bytes.Equal(h.DoubleHash([]byte("a"), []byte("bc")), h.DoubleHash(msg)) {
+			t.Fatalf("%s double hash of split data not match", hashType)
+		}
+	}
+	if hex.EncodeToString(UsingSM3(msg)) != expects[HashTypeSM3] || hex.EncodeToString(UsingSha3(msg)) != expects[HashTypeSha3] {
+		t.Fatal("hash helper not match")
+	}
+
+	var nilHasher *Hasher
+	if !bytes.Equal(nilHasher.DoubleHash(msg), DoubleSha256(msg)) || nilHasher.Name() != HashTypeSha256 {
+		t.Fatal("nil hasher should be sha256")
+	}
+	if h, err := GetHasher(""); err != nil || h != DefaultHasher {
+		t.Fatal("empty hash type should be default")
+	}
+	if _, err := GetHasher("md5"); err == nil {
+		t.Fatal("unsupported hash type should fail")
+	}
+}
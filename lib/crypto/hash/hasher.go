@@ -0,0 +1,82 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"fmt"
+	stdhash "hash"
+
+	"github.com/xuperchain/crypto/gm/gmsm/sm3"
+	"golang.org/x/crypto/sha3"
+)
+
+// 区块id、交易id和merkle树支持的哈希算法，由创世块配置指定
+const (
+	// HashTypeSha256 默认算法，执行2次SHA256
+	HashTypeSha256 = "sha256"
+	// HashTypeSM3 国密算法，执行2次SM3
+	HashTypeSM3 = "sm3"
+	// HashTypeSha3 执行2次SHA3-256
+	HashTypeSha3 = "sha3"
+)
+
+// Hasher 链上摘要使用的哈希算法，所有方法都执行2次哈希，与DoubleSha256保持一致，
+// nil Hasher等价于默认的SHA256，便于未配置哈希算法的场景直接使用
+type Hasher struct {
+	name    string
+	newHash func() stdhash.Hash
+}
+
+var hashers = map[string]*Hasher{
+	HashTypeSha256: {name: HashTypeSha256, newHash: sha256.New},
+	HashTypeSM3:    {name: HashTypeSM3, newHash: sm3.New},
+	HashTypeSha3:   {name: HashTypeSha3, newHash: sha3.New256},
+}
+
+// DefaultHasher 默认哈希算法，执行2次SHA256
+var DefaultHasher = hashers[HashTypeSha256]
+
+// GetHasher 根据算法名获取Hasher，空算法名表示默认的SHA256
+func GetHasher(hashType string) (*Hasher, error) {
+	if hashType == "" {
+		return DefaultHasher, nil
+	}
+	if h, ok := hashers[hashType]; ok {
+		return h, nil
+	}
+	return nil, fmt.Errorf("hash type %s is not supported", hashType)
+}
+
+// Name 返回算法名
+func (h *Hasher) Name() string {
+	return h.get().name
+}
+
+// New 返回一次哈希的hash.Hash，调用Sum时只能传nil，国密SM3的Sum会把参数写入哈希状态
+func (h *Hasher) New() stdhash.Hash {
+	return h.get().newHash()
+}
+
+// DoubleHash 对data执行2次哈希
+func (h *Hasher) DoubleHash(data ...[]byte) []byte {
+	first := h.New()
+	for _, d := range data {
+		first.Write(d)
+	}
+	second := h.New()
+	second.Write(first.Sum(nil))
+	return second.Sum(nil)
+}
+
+// DoubleHashSum 对已写入数据的hash.Hash再执行一次哈希，用于流式编码后计算摘要
+func (h *Hasher) DoubleHashSum(first stdhash.Hash) []byte {
+	second := h.New()
+	second.Write(first.Sum(nil))
+	return second.Sum(nil)
+}
+
+func (h *Hasher) get() *Hasher {
+	if h == nil {
+		return DefaultHasher
+	}
+	return h
+}
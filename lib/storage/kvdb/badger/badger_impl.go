@@ -86,7 +86,10 @@ func (bdb *BadgerDatabase) Put(key []byte, value []byte) error {
 func (bdb *BadgerDatabase) Delete(key []byte) error {
 	wb := bdb.db.NewWriteBatch()
 	defer wb.Cancel()
-	return wb.Delete(key)
+	if err := wb.Delete(key); err != nil {
+		return err
+	}
+	return wb.Flush()
 }
 
 func (bdb *BadgerDatabase) Get(key []byte) ([]byte, error) {
@@ -114,7 +117,7 @@ func (bdb *BadgerDatabase) Has(key []byte) (bool, error) {
 		return err
 	})
 	// align with leveldb, if the key doesn't exist, leveldb returns nil
-	if kvdb.ErrNotFound(err) {
+	if err != nil && kvdb.ErrNotFound(err) {
 		err = nil
 	}
 	return exist, err
@@ -20,6 +20,7 @@ type KVParameter struct {
 const (
 	KVEngineTypeLDB    = "leveldb"
 	KVEngineTypeBadger = "badger"
+	KVEngineTypePebble = "pebble"
 )
 
 const (
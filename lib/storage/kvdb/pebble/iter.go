@@ -0,0 +1,68 @@
+package pebbledb
+
+import (
+	"github.com/cockroachdb/pebble"
+)
+
+// PebbleIterator 对pebble.Iterator的封装
+// 与leveldb保持一致，新建的迭代器位于第一个元素之前，首次调用Next定位到第一个元素，首次调用Prev定位到最后一个元素
+type PebbleIterator struct {
+	iter *pebble.Iterator
+	init bool
+}
+
+func newPebbleIterator(iter *pebble.Iterator) *PebbleIterator {
+	return &PebbleIterator{
+		iter: iter,
+	}
+}
+
+func (pi *PebbleIterator) Key() []byte {
+	if !pi.iter.Valid() {
+		return nil
+	}
+	return pi.iter.Key()
+}
+
+func (pi *PebbleIterator) Value() []byte {
+	if !pi.iter.Valid() {
+		return nil
+	}
+	return pi.iter.Value()
+}
+
+func (pi *PebbleIterator) Next() bool {
+	if !pi.init {
+		pi.init = true
+		return pi.iter.First()
+	}
+	return pi.iter.Next()
+}
+
+func (pi *PebbleIterator) Prev() bool {
+	if !pi.init {
+		pi.init = true
+		return pi.iter.Last()
+	}
+	return pi.iter.Prev()
+}
+
+// Last skip to the last iterator
+func (pi *PebbleIterator) Last() bool {
+	pi.init = true
+	return pi.iter.Last()
+}
+
+// First skip to the first iterator
+func (pi *PebbleIterator) First() bool {
+	pi.init = true
+	return pi.iter.First()
+}
+
+func (pi *PebbleIterator) Error() error {
+	return pi.iter.Error()
+}
+
+func (pi *PebbleIterator) Release() {
+	pi.iter.Close()
+}
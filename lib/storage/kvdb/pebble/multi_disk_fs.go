@@ -0,0 +1,149 @@
+package pebbledb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+const sstSuffix = ".sst"
+
+// multiDiskFS 多盘存储，与leveldb的mstorage语义保持一致：
+// sst文件按文件编号对数据盘取模分散存储在dataPaths/base(path)目录下，其余文件(MANIFEST、WAL等)仍在主目录，
+// 读取时找不到则依次在主目录和所有数据盘中查找
+type multiDiskFS struct {
+	vfs.FS
+	path      string
+	dataPaths []string
+}
+
+func newMultiDiskFS(fs vfs.FS, path string, dataPaths []string) (*multiDiskFS, error) {
+	fullDataPaths := []string{}
+	for _, dp := range dataPaths {
+		fullPath := filepath.Join(dp, filepath.Base(path))
+		if err := fs.MkdirAll(fullPath, 0755); err != nil {
+			return nil, err
+		}
+		fullDataPaths = append(fullDataPaths, fullPath)
+	}
+	return &multiDiskFS{
+		FS:        fs,
+		path:      filepath.Clean(path),
+		dataPaths: fullDataPaths,
+	}, nil
+}
+
+func (mfs *multiDiskFS) isTable(name string) bool {
+	return len(mfs.dataPaths) > 0 && strings.HasSuffix(name, sstSuffix)
+}
+
+func (mfs *multiDiskFS) getRealPath(name string) string {
+	var fileNum uint64
+	base := filepath.Base(name)
+	fmt.Sscanf(base, "%d"+sstSuffix, &fileNum)
+	N := uint64(len(mfs.dataPaths))
+	return filepath.Join(mfs.dataPaths[fileNum%N], base)
+}
+
+func (mfs *multiDiskFS) expandDataPaths(name string) []string {
+	base := filepath.Base(name)
+	paths := []string{name}
+	for _, dp := range mfs.dataPaths {
+		paths = append(paths, filepath.Join(dp, base))
+	}
+	return paths
+}
+
+func (mfs *multiDiskFS) Create(name string) (vfs.File, error) {
+	if mfs.isTable(name) {
+		return mfs.FS.Create(mfs.getRealPath(name))
+	}
+	return mfs.FS.Create(name)
+}
+
+func (mfs *multiDiskFS) Link(oldname, newname string) error {
+	if mfs.isTable(newname) {
+		return mfs.FS.Link(oldname, mfs.getRealPath(newname))
+	}
+	return mfs.FS.Link(oldname, newname)
+}
+
+func (mfs *multiDiskFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, error) {
+	if !mfs.isTable(name) {
+		return mfs.FS.Open(name, opts...)
+	}
+	f, err := mfs.FS.Open(mfs.getRealPath(name), opts...)
+	if err == nil || !os.IsNotExist(err) {
+		return f, err
+	}
+	// 数据盘数量变化后，文件可能不在取模对应的盘上
+	for _, fullName := range mfs.expandDataPaths(name) {
+		f, err = mfs.FS.Open(fullName, opts...)
+		if err == nil {
+			return f, nil
+		}
+	}
+	return nil, err
+}
+
+func (mfs *multiDiskFS) Remove(name string) error {
+	if !mfs.isTable(name) {
+		return mfs.FS.Remove(name)
+	}
+	err := mfs.FS.Remove(mfs.getRealPath(name))
+	if err == nil || !os.IsNotExist(err) {
+		return err
+	}
+	for _, fullName := range mfs.expandDataPaths(name) {
+		err = mfs.FS.Remove(fullName)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (mfs *multiDiskFS) Stat(name string) (os.FileInfo, error) {
+	if !mfs.isTable(name) {
+		return mfs.FS.Stat(name)
+	}
+	info, err := mfs.FS.Stat(mfs.getRealPath(name))
+	if err == nil || !os.IsNotExist(err) {
+		return info, err
+	}
+	for _, fullName := range mfs.expandDataPaths(name) {
+		info, err = mfs.FS.Stat(fullName)
+		if err == nil {
+			return info, nil
+		}
+	}
+	return nil, err
+}
+
+// List 列出主目录时合并所有数据盘上的sst文件
+func (mfs *multiDiskFS) List(dir string) ([]string, error) {
+	names, err := mfs.FS.List(dir)
+	if err != nil || filepath.Clean(dir) != mfs.path {
+		return names, err
+	}
+	exist := map[string]bool{}
+	for _, name := range names {
+		exist[name] = true
+	}
+	for _, dp := range mfs.dataPaths {
+		dpNames, err := mfs.FS.List(dp)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range dpNames {
+			if strings.HasSuffix(name, sstSuffix) && !exist[name] {
+				exist[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
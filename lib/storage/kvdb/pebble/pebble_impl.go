@@ -0,0 +1,246 @@
+// pebble wrapper plugin
+// 基于纯Go实现的LSM引擎pebble，无需cgo
+package pebbledb
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	log "github.com/xuperchain/log15"
+
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+const (
+	// MiB 缓存大小配置的单位
+	MiB = 1024 * 1024
+)
+
+// PebbleDatabase define db backend based on pebble
+type PebbleDatabase struct {
+	fn  string     // filename of db
+	db  *pebble.DB // db instance
+	log log.Logger // logger instance
+}
+
+// NewKVDBInstance get instance of PebbleDatabase
+func NewKVDBInstance(param *kvdb.KVParameter) (kvdb.Database, error) {
+	baseDB := new(PebbleDatabase)
+	err := baseDB.Open(param.GetDBPath(), map[string]interface{}{
+		"cache":       param.GetMemCacheSize(),
+		"fds":         param.GetFileHandlersCacheSize(),
+		"dataPaths":   param.GetOtherPaths(),
+		"storageType": param.GetStorageType(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return baseDB, nil
+}
+
+func init() {
+	kvdb.Register(kvdb.KVEngineTypePebble, NewKVDBInstance)
+}
+
+func setDefaultOptions(options map[string]interface{}) {
+	if options["cache"] == nil {
+		options["cache"] = 16
+	}
+	if options["fds"] == nil {
+		options["fds"] = 16
+	}
+	if options["dataPaths"] == nil {
+		options["dataPaths"] = []string{}
+	}
+	if options["storageType"] == nil {
+		options["storageType"] = kvdb.StorageTypeSingle
+	}
+}
+
+// Open opens an instance of pebble with parameters (db path and other options)
+func (pdb *PebbleDatabase) Open(path string, options map[string]interface{}) error {
+	setDefaultOptions(options)
+	cacheSize := options["cache"].(int)
+	fds := options["fds"].(int)
+	dataPaths := options["dataPaths"].([]string)
+
+	var fs vfs.FS
+	switch options["storageType"] {
+	case kvdb.StorageTypeSingle:
+		if len(dataPaths) > 0 {
+			return fmt.Errorf("open database fail. err:dataPaths need storageType %s", kvdb.StorageTypeMulti)
+		}
+		fs = vfs.Default
+	case kvdb.StorageTypeMulti:
+		mfs, err := newMultiDiskFS(vfs.Default, path, dataPaths)
+		if err != nil {
+			return err
+		}
+		fs = mfs
+	default:
+		return fmt.Errorf("open database fail. err:invalid storageType:%v", options["storageType"])
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		os.MkdirAll(path, 0755)
+	}
+	// 与leveldb保持一致，一半用作块缓存，四分之一用作memtable(内部同时存在两个)
+	cache := pebble.NewCache(int64(cacheSize / 2 * MiB))
+	defer cache.Unref()
+	db, err := pebble.Open(path, &pebble.Options{
+		Cache:        cache,
+		MaxOpenFiles: fds,
+		MemTableSize: cacheSize / 4 * MiB,
+		FS:           fs,
+	})
+	if err != nil {
+		log.Warn("pebble open failed", "path", path, "err", err)
+		return err
+	}
+	pdb.fn = path
+	pdb.db = db
+	pdb.log = log.New("database", path)
+	return nil
+}
+
+// Path returns the path to the database directory
+func (pdb *PebbleDatabase) Path() string {
+	return pdb.fn
+}
+
+// Close close database instance
+func (pdb *PebbleDatabase) Close() {
+	err := pdb.db.Close()
+	if err == nil {
+		pdb.log.Info("database closed")
+	} else {
+		pdb.log.Error("failed to close database", "err", err)
+	}
+}
+
+// Put puts the given key / value to the database
+func (pdb *PebbleDatabase) Put(key []byte, value []byte) error {
+	return pdb.db.Set(key, value, pebble.NoSync)
+}
+
+// Delete deletes the key from the database
+func (pdb *PebbleDatabase) Delete(key []byte) error {
+	return pdb.db.Delete(key, pebble.NoSync)
+}
+
+// Get returns the given key if it's present.
+func (pdb *PebbleDatabase) Get(key []byte) ([]byte, error) {
+	val, closer, err := pdb.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	// pebble返回的value在closer关闭后失效，需要拷贝
+	ival := make([]byte, len(val))
+	copy(ival, val)
+	return ival, nil
+}
+
+// Has if the given key exists
+func (pdb *PebbleDatabase) Has(key []byte) (bool, error) {
+	_, closer, err := pdb.db.Get(key)
+	if err != nil {
+		// align with leveldb, if the key doesn't exist, leveldb returns nil
+		if err == pebble.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+// NewIteratorWithRange returns an instance of Iterator with range
+func (pdb *PebbleDatabase) NewIteratorWithRange(start []byte, limit []byte) kvdb.Iterator {
+	opts := &pebble.IterOptions{LowerBound: start}
+	if len(limit) > 0 {
+		opts.UpperBound = limit
+	}
+	return newPebbleIterator(pdb.db.NewIter(opts))
+}
+
+// NewIteratorWithPrefix returns an instance of Iterator with prefix
+func (pdb *PebbleDatabase) NewIteratorWithPrefix(prefix []byte) kvdb.Iterator {
+	keyRange := util.BytesPrefix(prefix)
+	return newPebbleIterator(pdb.db.NewIter(&pebble.IterOptions{
+		LowerBound: keyRange.Start,
+		UpperBound: keyRange.Limit,
+	}))
+}
+
+// NewBatch returns batch instance of pebble
+func (pdb *PebbleDatabase) NewBatch() kvdb.Batch {
+	return &pebbleBatch{db: pdb.db, b: pdb.db.NewBatch(), keys: map[string]bool{}}
+}
+
+type pebbleBatch struct {
+	db   *pebble.DB
+	b    *pebble.Batch
+	size int
+	keys map[string]bool
+}
+
+func (b *pebbleBatch) Put(key, value []byte) error {
+	err := b.b.Set(key, value, nil)
+	if err != nil {
+		return err
+	}
+	b.size += len(value)
+	return nil
+}
+
+func (b *pebbleBatch) Delete(key []byte) error {
+	err := b.b.Delete(key, nil)
+	if err != nil {
+		return err
+	}
+	b.size += len(key)
+	return nil
+}
+
+func (b *pebbleBatch) PutIfAbsent(key, value []byte) error {
+	if !b.keys[string(key)] {
+		err := b.b.Set(key, value, nil)
+		if err != nil {
+			return err
+		}
+		b.size += len(value)
+		b.keys[string(key)] = true
+		return nil
+	}
+	return fmt.Errorf("duplicated key in batch, (HEX) %x", key)
+}
+
+func (b *pebbleBatch) Exist(key []byte) bool {
+	return b.keys[string(key)]
+}
+
+// Write 提交batch，与leveldb一致，提交后batch内容保留，需要调用Reset清空
+func (b *pebbleBatch) Write() error {
+	// pebble的batch提交后不能再次提交，这里提交一个副本
+	wb := b.db.NewBatch()
+	defer wb.Close()
+	if err := wb.SetRepr(append([]byte(nil), b.b.Repr()...)); err != nil {
+		return err
+	}
+	return wb.Commit(pebble.NoSync)
+}
+
+func (b *pebbleBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *pebbleBatch) Reset() {
+	b.b.Reset()
+	b.size = 0
+	b.keys = map[string]bool{}
+}
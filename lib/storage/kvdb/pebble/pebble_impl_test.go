@@ -0,0 +1,301 @@
+package pebbledb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/badger"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+)
+
+// 同样的用例在leveldb和badger上运行，保证行为一致
+var engines = []string{
+	kvdb.KVEngineTypeLDB,
+	kvdb.KVEngineTypeBadger,
+	kvdb.KVEngineTypePebble,
+}
+
+func openDB(t *testing.T, engine string, dir string) kvdb.Database {
+	db, err := kvdb.CreateKVInstance(&kvdb.KVParameter{
+		DBPath:                filepath.Join(dir, engine),
+		KVEngineType:          engine,
+		StorageType:           kvdb.StorageTypeSingle,
+		MemCacheSize:          128,
+		FileHandlersCacheSize: 1024,
+	})
+	if err != nil {
+		t.Fatalf("create %s instance error: %v", engine, err)
+	}
+	return db
+}
+
+func TestDatabase(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, db kvdb.Database)
+	}{
+		{"PutGet", testPutGet},
+		{"Delete", testDelete},
+		{"Batch", testBatch},
+		{"IteratorWithRange", testIteratorWithRange},
+		{"IteratorWithPrefix", testIteratorWithPrefix},
+		{"Table", testTable},
+	}
+	for _, engine := range engines {
+		for _, c := range cases {
+			t.Run(engine+"/"+c.name, func(t *testing.T) {
+				dir, err := ioutil.TempDir("", "kvdb")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer os.RemoveAll(dir)
+				db := openDB(t, engine, dir)
+				defer db.Close()
+				c.run(t, db)
+			})
+		}
+	}
+}
+
+func testPutGet(t *testing.T, db kvdb.Database) {
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	value, err := db.Get([]byte("key"))
+	if err != nil || string(value) != "value" {
+		t.Fatalf("get unexpected, value:%s err:%v", value, err)
+	}
+	has, err := db.Has([]byte("key"))
+	if err != nil || !has {
+		t.Fatalf("has unexpected, has:%v err:%v", has, err)
+	}
+	has, err = db.Has([]byte("nokey"))
+	if err != nil || has {
+		t.Fatalf("has unexpected, has:%v err:%v", has, err)
+	}
+	_, err = db.Get([]byte("nokey"))
+	if err == nil || !kvdb.ErrNotFound(err) {
+		t.Fatalf("expect not found, err:%v", err)
+	}
+}
+
+func testDelete(t *testing.T, db kvdb.Database) {
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete([]byte("key")); err != nil {
+		t.Fatal(err)
+	}
+	_, err := db.Get([]byte("key"))
+	if err == nil || !kvdb.ErrNotFound(err) {
+		t.Fatalf("expect not found, err:%v", err)
+	}
+}
+
+func testBatch(t *testing.T, db kvdb.Database) {
+	if err := db.Put([]byte("del"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	batch := db.NewBatch()
+	batch.Put([]byte("key1"), []byte("value1"))
+	batch.Delete([]byte("del"))
+	if err := batch.PutIfAbsent([]byte("key2"), []byte("value2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.PutIfAbsent([]byte("key2"), []byte("value2")); err == nil {
+		t.Fatal("expect duplicated key error")
+	}
+	if !batch.Exist([]byte("key2")) || batch.Exist([]byte("key1")) {
+		t.Fatal("exist unexpected")
+	}
+	if batch.ValueSize() == 0 {
+		t.Fatal("value size unexpected")
+	}
+	// 提交前不可见
+	if has, _ := db.Has([]byte("key1")); has {
+		t.Fatal("batch visible before write")
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{"key1": "value1", "key2": "value2"} {
+		value, err := db.Get([]byte(k))
+		if err != nil || string(value) != v {
+			t.Fatalf("get unexpected, key:%s value:%s err:%v", k, value, err)
+		}
+	}
+	if has, _ := db.Has([]byte("del")); has {
+		t.Fatal("batch delete failed")
+	}
+
+	batch.Reset()
+	if batch.ValueSize() != 0 || batch.Exist([]byte("key2")) {
+		t.Fatal("reset unexpected")
+	}
+	batch.Put([]byte("key3"), []byte("value3"))
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	value, err := db.Get([]byte("key3"))
+	if err != nil || string(value) != "value3" {
+		t.Fatalf("get unexpected, value:%s err:%v", value, err)
+	}
+}
+
+func putKeys(t *testing.T, db kvdb.Database, keys []string) {
+	batch := db.NewBatch()
+	for _, k := range keys {
+		batch.Put([]byte(k), []byte("v_"+k))
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func collect(t *testing.T, iter kvdb.Iterator) []string {
+	defer iter.Release()
+	var keys []string
+	for iter.Next() {
+		if string(iter.Value()) != "v_"+string(iter.Key()) {
+			t.Fatalf("value unexpected, key:%s value:%s", iter.Key(), iter.Value())
+		}
+		keys = append(keys, string(iter.Key()))
+	}
+	if err := iter.Error(); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func testIteratorWithRange(t *testing.T, db kvdb.Database) {
+	putKeys(t, db, []string{"a1", "a2", "b1", "b2", "c1"})
+	cases := []struct {
+		start  string
+		limit  string
+		expect []string
+	}{
+		{"a1", "b2", []string{"a1", "a2", "b1"}},
+		{"a3", "c", []string{"b1", "b2"}},
+		{"b", "c2", []string{"b1", "b2", "c1"}},
+		{"d", "e", nil},
+	}
+	for _, c := range cases {
+		keys := collect(t, db.NewIteratorWithRange([]byte(c.start), []byte(c.limit)))
+		if fmt.Sprint(keys) != fmt.Sprint(c.expect) {
+			t.Fatalf("range [%s, %s) expect %v, got %v", c.start, c.limit, c.expect, keys)
+		}
+	}
+}
+
+func testIteratorWithPrefix(t *testing.T, db kvdb.Database) {
+	putKeys(t, db, []string{"a1", "a2", "b1", "b2", "c1"})
+	cases := []struct {
+		prefix string
+		expect []string
+	}{
+		{"a", []string{"a1", "a2"}},
+		{"b2", []string{"b2"}},
+		{"", []string{"a1", "a2", "b1", "b2", "c1"}},
+		{"d", nil},
+	}
+	for _, c := range cases {
+		keys := collect(t, db.NewIteratorWithPrefix([]byte(c.prefix)))
+		if fmt.Sprint(keys) != fmt.Sprint(c.expect) {
+			t.Fatalf("prefix %s expect %v, got %v", c.prefix, c.expect, keys)
+		}
+	}
+}
+
+func testTable(t *testing.T, db kvdb.Database) {
+	t1 := kvdb.NewTable(db, "T1")
+	t2 := kvdb.NewTable(db, "T2")
+	if err := t1.Put([]byte("key"), []byte("v_T1key")); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := t2.Has([]byte("key")); has {
+		t.Fatal("table isolation failed")
+	}
+	keys := collect(t, t1.NewIteratorWithPrefix(nil))
+	if fmt.Sprint(keys) != "[T1key]" {
+		t.Fatalf("table iterator unexpected: %v", keys)
+	}
+}
+
+func TestMultiDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	kvParam := &kvdb.KVParameter{
+		DBPath:                filepath.Join(dir, "data", "pebble"),
+		KVEngineType:          kvdb.KVEngineTypePebble,
+		StorageType:           kvdb.StorageTypeMulti,
+		MemCacheSize:          128,
+		FileHandlersCacheSize: 1024,
+		OtherPaths: []string{filepath.Join(dir, "disk1"), filepath.Join(dir, "disk2"),
+			filepath.Join(dir, "disk3")},
+	}
+	db, err := NewKVDBInstance(kvParam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 每次flush生成一个新的sst文件，按文件编号分散到各个数据盘
+	for i := 0; i < 6; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if err := db.Put([]byte(key), []byte("v_"+key)); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.(*PebbleDatabase).db.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	for _, dp := range kvParam.OtherPaths {
+		names, err := ioutil.ReadDir(filepath.Join(dp, "pebble"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables := 0
+		for _, info := range names {
+			if strings.HasSuffix(info.Name(), sstSuffix) {
+				tables++
+			}
+		}
+		if tables == 0 {
+			t.Fatalf("no table in data path %s", dp)
+		}
+	}
+	mainNames, err := ioutil.ReadDir(kvParam.DBPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range mainNames {
+		if strings.HasSuffix(info.Name(), sstSuffix) {
+			t.Fatalf("table %s should not be in main path", info.Name())
+		}
+	}
+
+	// 重新打开后数据可读
+	db, err = NewKVDBInstance(kvParam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	keys := collect(t, db.NewIteratorWithPrefix([]byte("key")))
+	if fmt.Sprint(keys) != "[key0 key1 key2 key3 key4 key5]" {
+		t.Fatalf("keys unexpected after reopen: %v", keys)
+	}
+
+	// 单盘模式不允许配置数据盘
+	kvParam.StorageType = kvdb.StorageTypeSingle
+	if _, err := NewKVDBInstance(kvParam); err == nil {
+		t.Fatal("expect error when single storage with other paths")
+	}
+}
@@ -649,6 +649,224 @@ func (m *ContractStatus) GetRuntime() string {
 	return ""
 }
 
+// 跨链只读查询请求
+type CrossQueryRequest struct {
+	Bcname               string         `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	Initiator            string         `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	AuthRequire          []string       `protobuf:"bytes,3,rep,name=auth_require,json=authRequire,proto3" json:"auth_require,omitempty"`
+	Request              *InvokeRequest `protobuf:"bytes,4,opt,name=request,proto3" json:"request,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CrossQueryRequest) Reset()         { *m = CrossQueryRequest{} }
+func (m *CrossQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*CrossQueryRequest) ProtoMessage()    {}
+func (*CrossQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_919de52f3bf773d2, []int{9}
+}
+
+func (m *CrossQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CrossQueryRequest.Unmarshal(m, b)
+}
+func (m *CrossQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CrossQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *CrossQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrossQueryRequest.Merge(m, src)
+}
+func (m *CrossQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_CrossQueryRequest.Size(m)
+}
+func (m *CrossQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrossQueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrossQueryRequest proto.InternalMessageInfo
+
+func (m *CrossQueryRequest) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *CrossQueryRequest) GetInitiator() string {
+	if m != nil {
+		return m.Initiator
+	}
+	return ""
+}
+
+func (m *CrossQueryRequest) GetAuthRequire() []string {
+	if m != nil {
+		return m.AuthRequire
+	}
+	return nil
+}
+
+func (m *CrossQueryRequest) GetRequest() *InvokeRequest {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+// 跨链只读查询结果
+type CrossQueryResponse struct {
+	Response             *ContractResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CrossQueryResponse) Reset()         { *m = CrossQueryResponse{} }
+func (m *CrossQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*CrossQueryResponse) ProtoMessage()    {}
+func (*CrossQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_919de52f3bf773d2, []int{10}
+}
+
+func (m *CrossQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CrossQueryResponse.Unmarshal(m, b)
+}
+func (m *CrossQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CrossQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *CrossQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrossQueryResponse.Merge(m, src)
+}
+func (m *CrossQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_CrossQueryResponse.Size(m)
+}
+func (m *CrossQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrossQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrossQueryResponse proto.InternalMessageInfo
+
+func (m *CrossQueryResponse) GetResponse() *ContractResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+// 带有背书签名的跨链查询信息，记录在交易中供验证节点校验
+type CrossQueryInfo struct {
+	Request              *CrossQueryRequest  `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Response             *CrossQueryResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	Signs                []*SignatureInfo    `protobuf:"bytes,3,rep,name=signs,proto3" json:"signs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *CrossQueryInfo) Reset()         { *m = CrossQueryInfo{} }
+func (m *CrossQueryInfo) String() string { return proto.CompactTextString(m) }
+func (*CrossQueryInfo) ProtoMessage()    {}
+func (*CrossQueryInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_919de52f3bf773d2, []int{11}
+}
+
+func (m *CrossQueryInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CrossQueryInfo.Unmarshal(m, b)
+}
+func (m *CrossQueryInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CrossQueryInfo.Marshal(b, m, deterministic)
+}
+func (m *CrossQueryInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrossQueryInfo.Merge(m, src)
+}
+func (m *CrossQueryInfo) XXX_Size() int {
+	return xxx_messageInfo_CrossQueryInfo.Size(m)
+}
+func (m *CrossQueryInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrossQueryInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrossQueryInfo proto.InternalMessageInfo
+
+func (m *CrossQueryInfo) GetRequest() *CrossQueryRequest {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *CrossQueryInfo) GetResponse() *CrossQueryResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *CrossQueryInfo) GetSigns() []*SignatureInfo {
+	if m != nil {
+		return m.Signs
+	}
+	return nil
+}
+
+// 跨链查询目标链的背书信息
+type CrossQueryMeta struct {
+	Bcname string `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	// 有效背书签名的最少个数
+	MinEndorsorNum int64 `protobuf:"varint,2,opt,name=min_endorsor_num,json=minEndorsorNum,proto3" json:"min_endorsor_num,omitempty"`
+	// 背书节点地址
+	Endorsors            []string `protobuf:"bytes,3,rep,name=endorsors,proto3" json:"endorsors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CrossQueryMeta) Reset()         { *m = CrossQueryMeta{} }
+func (m *CrossQueryMeta) String() string { return proto.CompactTextString(m) }
+func (*CrossQueryMeta) ProtoMessage()    {}
+func (*CrossQueryMeta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_919de52f3bf773d2, []int{12}
+}
+
+func (m *CrossQueryMeta) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CrossQueryMeta.Unmarshal(m, b)
+}
+func (m *CrossQueryMeta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CrossQueryMeta.Marshal(b, m, deterministic)
+}
+func (m *CrossQueryMeta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrossQueryMeta.Merge(m, src)
+}
+func (m *CrossQueryMeta) XXX_Size() int {
+	return xxx_messageInfo_CrossQueryMeta.Size(m)
+}
+func (m *CrossQueryMeta) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrossQueryMeta.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrossQueryMeta proto.InternalMessageInfo
+
+func (m *CrossQueryMeta) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *CrossQueryMeta) GetMinEndorsorNum() int64 {
+	if m != nil {
+		return m.MinEndorsorNum
+	}
+	return 0
+}
+
+func (m *CrossQueryMeta) GetEndorsors() []string {
+	if m != nil {
+		return m.Endorsors
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("protos.ResourceType", ResourceType_name, ResourceType_value)
 	proto.RegisterType((*GasPrice)(nil), "protos.GasPrice")
@@ -661,62 +879,78 @@ func init() {
 	proto.RegisterType((*ContractEvent)(nil), "protos.ContractEvent")
 	proto.RegisterType((*ContractStatData)(nil), "protos.ContractStatData")
 	proto.RegisterType((*ContractStatus)(nil), "protos.ContractStatus")
+	proto.RegisterType((*CrossQueryRequest)(nil), "protos.CrossQueryRequest")
+	proto.RegisterType((*CrossQueryResponse)(nil), "protos.CrossQueryResponse")
+	proto.RegisterType((*CrossQueryInfo)(nil), "protos.CrossQueryInfo")
+	proto.RegisterType((*CrossQueryMeta)(nil), "protos.CrossQueryMeta")
 }
 
 func init() { proto.RegisterFile("protos/contract.proto", fileDescriptor_919de52f3bf773d2) }
 
 var fileDescriptor_919de52f3bf773d2 = []byte{
-	// 817 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x55, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xc6, 0xbe, 0x8b, 0x7d, 0x9e, 0x38, 0xa9, 0xb5, 0xb4, 0xe8, 0x08, 0xa0, 0x46, 0x07, 0x42,
-	0x56, 0x25, 0x62, 0x91, 0x4a, 0x14, 0xf1, 0x01, 0x89, 0x3a, 0x06, 0x45, 0x50, 0x52, 0x6d, 0x5a,
-	0x51, 0x10, 0x92, 0xb5, 0xbe, 0x5b, 0x2e, 0xa7, 0x78, 0x6f, 0x8f, 0x7d, 0xb1, 0x6c, 0x7e, 0x0e,
-	0x3f, 0x81, 0xcf, 0xfc, 0x2a, 0x7e, 0x01, 0xda, 0x37, 0xfb, 0xec, 0xf6, 0xdb, 0x3e, 0x33, 0xcf,
-	0xcc, 0x3d, 0x33, 0xb3, 0xb3, 0x07, 0x8f, 0x1a, 0xc1, 0x15, 0x97, 0x93, 0x9c, 0xd7, 0x4a, 0x90,
-	0x5c, 0x5d, 0x58, 0x8c, 0x7a, 0xce, 0x7c, 0xf6, 0xc9, 0x5a, 0x37, 0x54, 0xe4, 0x5c, 0xd0, 0x89,
-	0x27, 0x2e, 0x69, 0x51, 0x52, 0xe1, 0x68, 0xd9, 0x5f, 0x90, 0xfc, 0x40, 0xe4, 0x4b, 0x51, 0xe5,
-	0x14, 0x7d, 0x08, 0x49, 0xde, 0xe8, 0xb9, 0x20, 0x8a, 0xa6, 0x9d, 0xf3, 0xce, 0x38, 0xc2, 0xfd,
-	0xbc, 0xd1, 0x98, 0x28, 0xeb, 0x62, 0x94, 0x39, 0x57, 0xd7, 0xb9, 0x18, 0x65, 0xd6, 0xf5, 0x11,
-	0x0c, 0x8a, 0x4a, 0xde, 0x3b, 0x5f, 0x64, 0x7d, 0x89, 0x31, 0x04, 0xe7, 0xfa, 0x0f, 0x4a, 0x9d,
-	0x33, 0x76, 0x4e, 0x63, 0x30, 0xce, 0xec, 0x06, 0x4e, 0x30, 0x95, 0x5c, 0x8b, 0x9c, 0xfe, 0x54,
-	0xb1, 0x4a, 0xa1, 0x31, 0xc4, 0x6a, 0xd3, 0xb8, 0x8f, 0x9f, 0x5e, 0x3e, 0x74, 0x12, 0xe5, 0x45,
-	0x20, 0xbd, 0xda, 0x34, 0x14, 0x5b, 0x06, 0x7a, 0x08, 0x47, 0x4b, 0x13, 0xe2, 0xc5, 0x38, 0x90,
-	0xfd, 0xdb, 0x85, 0x93, 0xeb, 0x7a, 0xc5, 0xef, 0x29, 0xa6, 0x7f, 0x6a, 0x2a, 0x15, 0x7a, 0x0c,
-	0xc7, 0x8c, 0x17, 0x7a, 0x49, 0xe7, 0x35, 0x61, 0x2e, 0xf1, 0x00, 0x83, 0x33, 0xfd, 0x4c, 0x18,
-	0x45, 0x9f, 0xc2, 0x49, 0x68, 0x9c, 0xa3, 0x74, 0x2d, 0x65, 0x18, 0x8c, 0x96, 0x64, 0xb2, 0x50,
-	0x75, 0xc7, 0x0b, 0x47, 0x89, 0x7c, 0x16, 0x6b, 0xb2, 0x84, 0xa7, 0x10, 0x13, 0x51, 0xca, 0x34,
-	0x3e, 0x8f, 0xc6, 0xc7, 0x97, 0x8f, 0x83, 0xf0, 0x3d, 0x2d, 0x17, 0xdf, 0x89, 0x52, 0xce, 0x6a,
-	0x25, 0x36, 0xd8, 0x92, 0xd1, 0xb7, 0xf0, 0x40, 0xf8, 0xca, 0xe6, 0x56, 0xbf, 0x4c, 0x8f, 0x6c,
-	0xfc, 0xa3, 0xc3, 0xc2, 0x6d, 0x77, 0xf0, 0xa9, 0x68, 0x43, 0x89, 0x3e, 0x80, 0x1e, 0x61, 0x5c,
-	0xd7, 0x2a, 0xed, 0x59, 0x41, 0x1e, 0x9d, 0x3d, 0x83, 0xc1, 0xf6, 0x53, 0x68, 0x04, 0xd1, 0x3d,
-	0xdd, 0xf8, 0xc2, 0xcd, 0xd1, 0xb4, 0x6e, 0x45, 0x96, 0xda, 0x55, 0x3a, 0xc4, 0x0e, 0x7c, 0xd3,
-	0xfd, 0xba, 0x93, 0xfd, 0xd7, 0x85, 0xd3, 0x20, 0x59, 0x36, 0xbc, 0x96, 0x14, 0x3d, 0x81, 0x5e,
-	0x55, 0x37, 0x5a, 0xc9, 0xb4, 0x63, 0xa5, 0xa1, 0x20, 0xed, 0xd5, 0xfa, 0xda, 0xd8, 0x67, 0x6b,
-	0x85, 0x3d, 0x03, 0x7d, 0x01, 0x7d, 0xae, 0x95, 0x25, 0x77, 0x2d, 0xf9, 0xfd, 0x1d, 0xf9, 0xc6,
-	0x3a, 0x0c, 0x3b, 0x70, 0xd0, 0x19, 0x24, 0xc2, 0x7f, 0x26, 0x8d, 0xce, 0xa3, 0xf1, 0x10, 0x6f,
-	0xb1, 0xb9, 0x6e, 0x25, 0x91, 0x73, 0x2d, 0x69, 0xe1, 0x6f, 0x4d, 0xbf, 0x24, 0xf2, 0xb5, 0xa4,
-	0x05, 0xfa, 0xd2, 0x84, 0xd9, 0x86, 0xbe, 0xd5, 0xae, 0xbd, 0x76, 0xe3, 0x2d, 0x0d, 0x7d, 0x05,
-	0x83, 0x90, 0x59, 0xa6, 0x3d, 0x1b, 0x93, 0x86, 0x98, 0xa9, 0x9f, 0x73, 0xa8, 0x18, 0xef, 0xa8,
-	0x68, 0x02, 0xa0, 0xd5, 0x9a, 0x5f, 0xbb, 0x06, 0xf4, 0x6d, 0xe0, 0x83, 0x83, 0x06, 0xe0, 0x16,
-	0x05, 0x5d, 0xc2, 0xb1, 0x41, 0x37, 0xbe, 0x0b, 0x89, 0x8d, 0x18, 0x1d, 0x76, 0x01, 0xb7, 0x49,
-	0xd9, 0x1b, 0x18, 0x1d, 0x6a, 0x30, 0x93, 0x95, 0x8a, 0x28, 0x2d, 0xed, 0xdc, 0x8e, 0xb0, 0x47,
-	0x28, 0x85, 0x3e, 0xa3, 0x52, 0x92, 0x32, 0x5c, 0xd3, 0x00, 0x11, 0x82, 0x78, 0xc1, 0x8b, 0x8d,
-	0xbd, 0x9a, 0x43, 0x6c, 0xcf, 0xd9, 0xdf, 0x1d, 0x18, 0xfe, 0x42, 0x24, 0x9b, 0xf2, 0x82, 0x5e,
-	0x51, 0x99, 0x9b, 0x70, 0xa1, 0x6b, 0x55, 0x6d, 0x17, 0x21, 0x40, 0x33, 0x8b, 0x9c, 0xb3, 0xa6,
-	0x5a, 0x52, 0xe1, 0x33, 0x6f, 0xb1, 0x11, 0x53, 0x54, 0x25, 0x95, 0xca, 0x27, 0xf7, 0xc8, 0x2c,
-	0xc5, 0x8a, 0xcd, 0xb7, 0x61, 0xb1, 0x5b, 0x8a, 0x15, 0x9b, 0x86, 0xc0, 0xf6, 0x6a, 0xd9, 0xb5,
-	0x3e, 0xda, 0x5f, 0x2d, 0xb3, 0xce, 0xd9, 0x2d, 0x9c, 0x84, 0xf2, 0x67, 0x2b, 0x5a, 0x2b, 0x27,
-	0xc5, 0x19, 0xbc, 0xca, 0x2d, 0x36, 0x55, 0xb6, 0x76, 0xd4, 0x9e, 0xdf, 0x59, 0xf9, 0xef, 0xbb,
-	0x9e, 0xde, 0x2a, 0xa2, 0xae, 0x88, 0x22, 0x28, 0x83, 0x21, 0xc9, 0x73, 0xb3, 0x20, 0x53, 0xbb,
-	0x33, 0xee, 0x81, 0xdb, 0xb3, 0xa1, 0xcf, 0x76, 0x8a, 0x1d, 0xc9, 0xbd, 0x2e, 0xfb, 0xc6, 0xec,
-	0x9f, 0x0e, 0x9c, 0xb6, 0xd3, 0x6b, 0xf9, 0xf6, 0x2b, 0xd2, 0x79, 0xc7, 0x2b, 0x82, 0x20, 0x56,
-	0xeb, 0xaa, 0x08, 0xea, 0xcd, 0xd9, 0xd8, 0x0a, 0x2a, 0xf3, 0xa0, 0xde, 0x9c, 0xcd, 0x9b, 0x59,
-	0xc9, 0xf9, 0x82, 0xd4, 0xb5, 0xbf, 0xfd, 0x09, 0x4e, 0x2a, 0xf9, 0xdc, 0x62, 0xf4, 0x31, 0x0c,
-	0xcc, 0xc4, 0xa4, 0x22, 0xac, 0xb1, 0x0d, 0x8d, 0xf0, 0xce, 0xd0, 0x9e, 0x70, 0x6f, 0x6f, 0xc2,
-	0x4f, 0x9e, 0xc1, 0xb0, 0xfd, 0x8c, 0xa2, 0x3e, 0x44, 0xd3, 0x97, 0xaf, 0x47, 0xef, 0x21, 0x80,
-	0xde, 0x8b, 0xd9, 0x8b, 0x1b, 0xfc, 0xeb, 0xa8, 0x83, 0x12, 0x88, 0xaf, 0xae, 0x6f, 0x7f, 0x1c,
-	0x75, 0xcd, 0xe9, 0xcd, 0xf7, 0xb3, 0xd9, 0x28, 0x7a, 0x3e, 0xfe, 0xed, 0xf3, 0xb2, 0x52, 0x77,
-	0x7a, 0x71, 0x91, 0x73, 0x36, 0x71, 0x3f, 0x93, 0x3b, 0x52, 0xd5, 0x93, 0xc3, 0xff, 0xca, 0xc2,
-	0xfd, 0x71, 0x9e, 0xfe, 0x1f, 0x00, 0x00, 0xff, 0xff, 0x4e, 0xe4, 0xd3, 0xa2, 0x91, 0x06, 0x00,
-	0x00,
+	// 1011 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x56, 0x5b, 0x6f, 0x5b, 0x45,
+	0x10, 0xc6, 0x97, 0xf8, 0x32, 0x71, 0x52, 0xb3, 0xb4, 0x95, 0x1b, 0x40, 0x6d, 0x0f, 0x08, 0x59,
+	0x45, 0xc4, 0x22, 0x41, 0x2d, 0xe2, 0x01, 0x89, 0x3a, 0x06, 0x05, 0x48, 0x53, 0x36, 0xad, 0x28,
+	0x08, 0xc9, 0xda, 0x9c, 0xb3, 0x75, 0x56, 0xc9, 0xd9, 0x3d, 0xec, 0xc5, 0xb2, 0xf9, 0x2f, 0xbc,
+	0xf0, 0xc8, 0x23, 0xcf, 0xfc, 0x2a, 0x7e, 0x01, 0xda, 0xdb, 0xf1, 0xb1, 0x93, 0xbc, 0x9d, 0xf9,
+	0xe6, 0x9b, 0xd9, 0x6f, 0x66, 0x76, 0xd6, 0x86, 0x7b, 0x85, 0x14, 0x5a, 0xa8, 0x51, 0x2a, 0xb8,
+	0x96, 0x24, 0xd5, 0xfb, 0xce, 0x46, 0x2d, 0x0f, 0xef, 0x7d, 0xb8, 0x30, 0x05, 0x95, 0xa9, 0x90,
+	0x74, 0x14, 0x88, 0x57, 0x34, 0x9b, 0x51, 0xe9, 0x69, 0x7b, 0x8f, 0xaf, 0xb9, 0x0b, 0x2a, 0x73,
+	0xa6, 0x14, 0x13, 0xdc, 0x53, 0x92, 0x3f, 0xa0, 0xf3, 0x1d, 0x51, 0x2f, 0x25, 0x4b, 0x29, 0x7a,
+	0x00, 0x9d, 0xb4, 0x30, 0x53, 0x49, 0x34, 0x1d, 0xd4, 0x1e, 0xd5, 0x86, 0x0d, 0xdc, 0x4e, 0x0b,
+	0x83, 0x89, 0x76, 0xae, 0x9c, 0xe6, 0xde, 0x55, 0xf7, 0xae, 0x9c, 0xe6, 0xce, 0xf5, 0x3e, 0x74,
+	0x33, 0xa6, 0x2e, 0xbd, 0xaf, 0xe1, 0x7c, 0x1d, 0x0b, 0x44, 0xe7, 0xe2, 0x2d, 0xa5, 0xde, 0xd9,
+	0xf4, 0x4e, 0x0b, 0x58, 0x67, 0x72, 0x0a, 0x3b, 0x98, 0x2a, 0x61, 0x64, 0x4a, 0x7f, 0x64, 0x39,
+	0xd3, 0x68, 0x08, 0x4d, 0xbd, 0x2c, 0xfc, 0xe1, 0xbb, 0x07, 0x77, 0xbd, 0x44, 0xb5, 0x1f, 0x49,
+	0xaf, 0x96, 0x05, 0xc5, 0x8e, 0x81, 0xee, 0xc2, 0xd6, 0x95, 0x0d, 0x09, 0x62, 0xbc, 0x91, 0xfc,
+	0x5b, 0x87, 0x9d, 0x63, 0x3e, 0x17, 0x97, 0x14, 0xd3, 0xdf, 0x0d, 0x55, 0x1a, 0x3d, 0x84, 0xed,
+	0x5c, 0x64, 0xe6, 0x8a, 0x4e, 0x39, 0xc9, 0x7d, 0xe2, 0x2e, 0x06, 0x0f, 0xbd, 0x20, 0x39, 0x45,
+	0x1f, 0xc1, 0x4e, 0xec, 0xad, 0xa7, 0xd4, 0x1d, 0xa5, 0x17, 0x41, 0x47, 0xb2, 0x59, 0xa8, 0xbe,
+	0x10, 0x99, 0xa7, 0x34, 0x42, 0x16, 0x07, 0x39, 0xc2, 0x21, 0x34, 0x89, 0x9c, 0xa9, 0x41, 0xf3,
+	0x51, 0x63, 0xb8, 0x7d, 0xf0, 0x30, 0x0a, 0x5f, 0xd3, 0xb2, 0xff, 0x8d, 0x9c, 0xa9, 0x09, 0xd7,
+	0x72, 0x89, 0x1d, 0x19, 0x7d, 0x0d, 0x77, 0x64, 0xa8, 0x6c, 0xea, 0xf4, 0xab, 0xc1, 0x96, 0x8b,
+	0xbf, 0xb7, 0x59, 0xb8, 0xeb, 0x0e, 0xde, 0x95, 0x55, 0x53, 0xa1, 0xfb, 0xd0, 0x22, 0xb9, 0x30,
+	0x5c, 0x0f, 0x5a, 0x4e, 0x50, 0xb0, 0xf6, 0x9e, 0x41, 0xb7, 0x3c, 0x0a, 0xf5, 0xa1, 0x71, 0x49,
+	0x97, 0xa1, 0x70, 0xfb, 0x69, 0x5b, 0x37, 0x27, 0x57, 0xc6, 0x57, 0xda, 0xc3, 0xde, 0xf8, 0xaa,
+	0xfe, 0x65, 0x2d, 0xf9, 0xaf, 0x0e, 0xbb, 0x51, 0xb2, 0x2a, 0x04, 0x57, 0x14, 0x3d, 0x81, 0x16,
+	0xe3, 0x85, 0xd1, 0x6a, 0x50, 0x73, 0xd2, 0x50, 0x94, 0xf6, 0x6a, 0x71, 0x6c, 0xf1, 0xc9, 0x42,
+	0xe3, 0xc0, 0x40, 0x9f, 0x41, 0x5b, 0x18, 0xed, 0xc8, 0x75, 0x47, 0x7e, 0x6f, 0x45, 0x3e, 0x75,
+	0x0e, 0xcb, 0x8e, 0x1c, 0xb4, 0x07, 0x1d, 0x19, 0x8e, 0x19, 0x34, 0x1e, 0x35, 0x86, 0x3d, 0x5c,
+	0xda, 0xf6, 0xba, 0xcd, 0x88, 0x9a, 0x1a, 0x45, 0xb3, 0x70, 0x6b, 0xda, 0x33, 0xa2, 0x5e, 0x2b,
+	0x9a, 0xa1, 0xcf, 0x6d, 0x98, 0x6b, 0xe8, 0xb5, 0x76, 0xad, 0xb5, 0x1b, 0x97, 0x34, 0xf4, 0x14,
+	0xba, 0x31, 0xb3, 0x1a, 0xb4, 0x5c, 0xcc, 0x20, 0xc6, 0x8c, 0xc3, 0x9c, 0x63, 0xc5, 0x78, 0x45,
+	0x45, 0x23, 0x00, 0xa3, 0x17, 0xe2, 0xd8, 0x37, 0xa0, 0xed, 0x02, 0xef, 0x6c, 0x34, 0x00, 0x57,
+	0x28, 0xe8, 0x00, 0xb6, 0xad, 0x75, 0x1a, 0xba, 0xd0, 0x71, 0x11, 0xfd, 0xcd, 0x2e, 0xe0, 0x2a,
+	0x29, 0x79, 0x03, 0xfd, 0x4d, 0x0d, 0x76, 0xb2, 0x4a, 0x13, 0x6d, 0x94, 0x9b, 0xdb, 0x16, 0x0e,
+	0x16, 0x1a, 0x40, 0x3b, 0xa7, 0x4a, 0x91, 0x59, 0xbc, 0xa6, 0xd1, 0x44, 0x08, 0x9a, 0xe7, 0x22,
+	0x5b, 0xba, 0xab, 0xd9, 0xc3, 0xee, 0x3b, 0xf9, 0xab, 0x06, 0xbd, 0x9f, 0x89, 0xca, 0xc7, 0x22,
+	0xa3, 0x47, 0x54, 0xa5, 0x36, 0x5c, 0x1a, 0xae, 0x59, 0xb9, 0x08, 0xd1, 0xb4, 0xb3, 0x48, 0x45,
+	0x5e, 0xb0, 0x2b, 0x2a, 0x43, 0xe6, 0xd2, 0xb6, 0x62, 0x32, 0x36, 0xa3, 0x4a, 0x87, 0xe4, 0xc1,
+	0xb2, 0x4b, 0x31, 0xcf, 0xa7, 0x65, 0x58, 0xd3, 0x2f, 0xc5, 0x3c, 0x1f, 0xc7, 0xc0, 0xea, 0x6a,
+	0xb9, 0xb5, 0xde, 0x5a, 0x5f, 0x2d, 0xbb, 0xce, 0xc9, 0x19, 0xec, 0xc4, 0xf2, 0x27, 0x73, 0xca,
+	0xb5, 0x97, 0xe2, 0x81, 0xa0, 0xb2, 0xb4, 0x6d, 0x95, 0x95, 0x1d, 0x75, 0xdf, 0x37, 0x56, 0xfe,
+	0xdb, 0xaa, 0xa7, 0x67, 0x9a, 0xe8, 0x23, 0xa2, 0x09, 0x4a, 0xa0, 0x47, 0xd2, 0xd4, 0x2e, 0xc8,
+	0xd8, 0xed, 0x8c, 0x7f, 0xe0, 0xd6, 0x30, 0xf4, 0xf1, 0x4a, 0xb1, 0x27, 0xf9, 0xd7, 0x65, 0x1d,
+	0x4c, 0xfe, 0xa9, 0xc1, 0x6e, 0x35, 0xbd, 0x51, 0xd7, 0x5f, 0x91, 0xda, 0x0d, 0xaf, 0x08, 0x82,
+	0xa6, 0x5e, 0xb0, 0x2c, 0xaa, 0xb7, 0xdf, 0x16, 0xcb, 0xa8, 0x4a, 0xa3, 0x7a, 0xfb, 0x6d, 0xdf,
+	0x4c, 0xa6, 0xa6, 0xe7, 0x84, 0xf3, 0x70, 0xfb, 0x3b, 0xb8, 0xc3, 0xd4, 0x73, 0x67, 0xa3, 0x0f,
+	0xa0, 0x6b, 0x27, 0xa6, 0x34, 0xc9, 0x0b, 0xd7, 0xd0, 0x06, 0x5e, 0x01, 0xd5, 0x09, 0xb7, 0xd6,
+	0x26, 0x9c, 0xfc, 0x59, 0x83, 0x77, 0xc7, 0x52, 0x28, 0xf5, 0x93, 0xa1, 0x72, 0x19, 0x9f, 0xc7,
+	0xfb, 0xd0, 0x3a, 0x4f, 0x2b, 0x82, 0x83, 0x65, 0x4f, 0x61, 0x9c, 0x69, 0x46, 0xb4, 0x88, 0x17,
+	0x62, 0x05, 0xa0, 0xc7, 0xd0, 0x23, 0x46, 0x5f, 0x4c, 0xed, 0x82, 0x31, 0xe9, 0xb7, 0xb7, 0x8b,
+	0xb7, 0x2d, 0x86, 0x3d, 0x84, 0x46, 0xd0, 0x0e, 0xeb, 0xe7, 0x2a, 0xb8, 0x75, 0x49, 0x23, 0x2b,
+	0xf9, 0x1e, 0x50, 0x55, 0x5e, 0x58, 0x84, 0x2f, 0x2a, 0x6f, 0x44, 0xcd, 0xe5, 0xb9, 0x7d, 0x71,
+	0x4b, 0x66, 0xf2, 0xb7, 0x1d, 0x50, 0x99, 0xec, 0x98, 0xbf, 0x15, 0xe8, 0x70, 0xa5, 0xc7, 0xe7,
+	0x79, 0x50, 0xe6, 0xd9, 0x6c, 0x4a, 0xa9, 0x09, 0x3d, 0xad, 0x9c, 0x5e, 0x77, 0x51, 0x7b, 0x37,
+	0x45, 0x6d, 0x9e, 0x8f, 0x3e, 0x85, 0x2d, 0xc5, 0x66, 0x5c, 0xb9, 0xc6, 0x54, 0x4a, 0x3f, 0x63,
+	0x33, 0x4e, 0xb4, 0x91, 0xd4, 0x4a, 0xc2, 0x9e, 0x93, 0x14, 0x55, 0xad, 0x27, 0x54, 0x93, 0x5b,
+	0x87, 0x32, 0x84, 0x7e, 0xce, 0xf8, 0x94, 0xf2, 0x4c, 0x48, 0x25, 0xe4, 0x94, 0x9b, 0x3c, 0x5c,
+	0xd0, 0xdd, 0x9c, 0xf1, 0x49, 0x80, 0x5f, 0x98, 0xdc, 0x8e, 0x2f, 0xb2, 0x54, 0x98, 0xce, 0x0a,
+	0x78, 0xf2, 0x0c, 0x7a, 0xd5, 0x5f, 0x54, 0xd4, 0x86, 0xc6, 0xf8, 0xe5, 0xeb, 0xfe, 0x3b, 0x08,
+	0xa0, 0x75, 0x32, 0x39, 0x39, 0xc5, 0xbf, 0xf4, 0x6b, 0xa8, 0x03, 0xcd, 0xa3, 0xe3, 0xb3, 0x1f,
+	0xfa, 0x75, 0xfb, 0xf5, 0xe6, 0xdb, 0xc9, 0xa4, 0xdf, 0x78, 0x3e, 0xfc, 0xf5, 0x93, 0x19, 0xd3,
+	0x17, 0xe6, 0x7c, 0x3f, 0x15, 0xf9, 0xc8, 0xff, 0xb7, 0xb8, 0x20, 0x8c, 0x8f, 0x36, 0xff, 0x66,
+	0x9c, 0xfb, 0xff, 0x27, 0x87, 0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0xe8, 0x5d, 0x2d, 0xbe, 0xbf,
+	0x08, 0x00, 0x00,
 }
@@ -71,6 +71,22 @@ const (
 	XuperMessage_GET_BLOCKS_HEADERS_RES XuperMessage_MessageType = 27
 	XuperMessage_GET_BLOCK_TXS          XuperMessage_MessageType = 28
 	XuperMessage_GET_BLOCKS_TXS_RES     XuperMessage_MessageType = 29
+	// 状态快照同步(GET_STATE_SNAPSHOT <-> GET_STATE_SNAPSHOT_RES, GET_STATE_CHUNK <-> GET_STATE_CHUNK_RES),
+	// 新节点先获取对端最新的可用状态快照描述信息, 再逐个下载快照分片导入状态机
+	XuperMessage_GET_STATE_SNAPSHOT     XuperMessage_MessageType = 30
+	XuperMessage_GET_STATE_SNAPSHOT_RES XuperMessage_MessageType = 31
+	XuperMessage_GET_STATE_CHUNK        XuperMessage_MessageType = 32
+	XuperMessage_GET_STATE_CHUNK_RES    XuperMessage_MessageType = 33
+	// raft共识消息对(RAFT_REQUEST_VOTE <-> RAFT_REQUEST_VOTE_RES, RAFT_APPEND_ENTRIES <-> RAFT_APPEND_ENTRIES_RES),
+	// candidate通过RAFT_REQUEST_VOTE拉票, leader通过不含日志条目的RAFT_APPEND_ENTRIES发送心跳
+	XuperMessage_RAFT_REQUEST_VOTE       XuperMessage_MessageType = 34
+	XuperMessage_RAFT_REQUEST_VOTE_RES   XuperMessage_MessageType = 35
+	XuperMessage_RAFT_APPEND_ENTRIES     XuperMessage_MessageType = 36
+	XuperMessage_RAFT_APPEND_ENTRIES_RES XuperMessage_MessageType = 37
+	// 跨链查询背书(CROSS_QUERY <-> CROSS_QUERY_RES),
+	// 发起节点向目标链的背书节点请求执行只读查询, 背书节点返回带有本节点签名的查询结果
+	XuperMessage_CROSS_QUERY     XuperMessage_MessageType = 38
+	XuperMessage_CROSS_QUERY_RES XuperMessage_MessageType = 39
 )
 
 var XuperMessage_MessageType_name = map[int32]string{
@@ -104,6 +120,16 @@ var XuperMessage_MessageType_name = map[int32]string{
 	27: "GET_BLOCKS_HEADERS_RES",
 	28: "GET_BLOCK_TXS",
 	29: "GET_BLOCKS_TXS_RES",
+	30: "GET_STATE_SNAPSHOT",
+	31: "GET_STATE_SNAPSHOT_RES",
+	32: "GET_STATE_CHUNK",
+	33: "GET_STATE_CHUNK_RES",
+	34: "RAFT_REQUEST_VOTE",
+	35: "RAFT_REQUEST_VOTE_RES",
+	36: "RAFT_APPEND_ENTRIES",
+	37: "RAFT_APPEND_ENTRIES_RES",
+	38: "CROSS_QUERY",
+	39: "CROSS_QUERY_RES",
 }
 
 var XuperMessage_MessageType_value = map[string]int32{
@@ -137,6 +163,16 @@ var XuperMessage_MessageType_value = map[string]int32{
 	"GET_BLOCKS_HEADERS_RES":       27,
 	"GET_BLOCK_TXS":                28,
 	"GET_BLOCKS_TXS_RES":           29,
+	"GET_STATE_SNAPSHOT":           30,
+	"GET_STATE_SNAPSHOT_RES":       31,
+	"GET_STATE_CHUNK":              32,
+	"GET_STATE_CHUNK_RES":          33,
+	"RAFT_REQUEST_VOTE":            34,
+	"RAFT_REQUEST_VOTE_RES":        35,
+	"RAFT_APPEND_ENTRIES":          36,
+	"RAFT_APPEND_ENTRIES_RES":      37,
+	"CROSS_QUERY":                  38,
+	"CROSS_QUERY_RES":              39,
 }
 
 func (x XuperMessage_MessageType) String() string {
@@ -465,62 +501,68 @@ func init() {
 func init() { proto.RegisterFile("protos/network.proto", fileDescriptor_9898f5d59e04eeea) }
 
 var fileDescriptor_9898f5d59e04eeea = []byte{
-	// 865 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xcd, 0x73, 0x1a, 0x37,
-	0x14, 0x37, 0x18, 0xf3, 0xf1, 0xf8, 0xb0, 0xfc, 0x4c, 0x9c, 0x2d, 0x71, 0x53, 0x86, 0xe9, 0xa4,
-	0x9c, 0xec, 0x0e, 0xed, 0xa9, 0xd3, 0x0b, 0x2c, 0xb2, 0xd9, 0x71, 0xd0, 0xee, 0x48, 0x22, 0x26,
-	0xbd, 0xec, 0xac, 0x41, 0xb1, 0x99, 0x04, 0x96, 0x59, 0x70, 0xda, 0xfc, 0x29, 0x9d, 0xe9, 0xb9,
-	0x97, 0xfe, 0x93, 0x1d, 0x69, 0x77, 0x31, 0xb6, 0x49, 0x4e, 0xf0, 0x7e, 0x1f, 0xd2, 0x7b, 0x4f,
-	0xd2, 0x5b, 0xa8, 0x2f, 0xa3, 0x70, 0x1d, 0xae, 0xce, 0x17, 0x6a, 0xfd, 0x67, 0x18, 0x7d, 0x3c,
-	0x33, 0x21, 0xe6, 0x63, 0xb4, 0xf5, 0x4f, 0x19, 0x2a, 0xe3, 0xfb, 0xa5, 0x8a, 0x86, 0x6a, 0xb5,
-	0x0a, 0x6e, 0x15, 0xfe, 0x06, 0xf9, 0x81, 0x0a, 0xa6, 0x2a, 0xb2, 0x32, 0xcd, 0x4c, 0xbb, 0xdc,
-	0x69, 0xc5, 0x86, 0xd5, 0xd9, 0xb6, 0xea, 0x2c, 0xf9, 0x8d, 0x95, 0x3c, 0x71, 0xe0, 0xaf, 0x90,
-	0xeb, 0x07, 0xeb, 0xc0, 0xca, 0x1a, 0x67, 0xf3, 0x5b, 0x4e, 0xad, 0xe3, 0x46, 0xdd, 0xf8, 0x2f,
-	0x0b, 0xd5, 0x47, 0xeb, 0xa1, 0x05, 0x85, 0xcf, 0x2a, 0x5a, 0xcd, 0xc2, 0x85, 0x49, 0xa2, 0xc4,
-	0xd3, 0x10, 0xeb, 0x70, 0xf0, 0x29, 0xbc, 0x9d, 0x4d, 0xcd, 0x16, 0x25, 0x1e, 0x07, 0x88, 0x90,
-	0xfb, 0x10, 0x85, 0x73, 0x6b, 0xdf, 0x80, 0xe6, 0x3f, 0x9e, 0x40, 0xfe, 0x66, 0xb2, 0x08, 0xe6,
-	0xca, 0xca, 0x19, 0x34, 0x89, 0x74, 0x8e, 0xeb, 0x2f, 0x4b, 0x65, 0x1d, 0x34, 0x33, 0xed, 0xda,
-	0xb7, 0x73, 0x94, 0x5f, 0x96, 0x8a, 0x1b, 0x35, 0xb6, 0xa0, 0x32, 0x0d, 0xd6, 0x81, 0x7d, 0xa7,
-	0x26, 0x1f, 0xc5, 0xfd, 0xdc, 0xca, 0x37, 0x33, 0xed, 0x2a, 0x7f, 0x84, 0xe1, 0xef, 0x50, 0x52,
-	0x51, 0x14, 0x46, 0xda, 0x66, 0x15, 0xcc, 0xf2, 0xaf, 0x77, 0x2e, 0x4f, 0x53, 0x15, 0x7f, 0x30,
-	0xe0, 0x1b, 0xa8, 0xa9, 0x45, 0x70, 0xf3, 0x49, 0xd9, 0xe1, 0x7c, 0x19, 0xa9, 0xd5, 0xca, 0x2a,
-	0x36, 0x33, 0xed, 0x22, 0x7f, 0x82, 0x36, 0x7e, 0x82, 0xf2, 0x56, 0x0b, 0x75, 0xab, 0xe6, 0xab,
-	0x5b, 0x67, 0xf1, 0x21, 0x34, 0xd5, 0x57, 0x78, 0x1a, 0xb6, 0xfe, 0x3e, 0xd8, 0x28, 0xcd, 0x06,
-	0x55, 0x28, 0x09, 0xca, 0xfa, 0xbd, 0xb7, 0xae, 0x7d, 0x45, 0xf6, 0x10, 0x20, 0xef, 0xb9, 0x42,
-	0xca, 0x31, 0xc9, 0xe0, 0x21, 0x94, 0x7b, 0x5d, 0x69, 0x0f, 0x12, 0x20, 0xab, 0xb5, 0x97, 0x54,
-	0xfa, 0xb1, 0x76, 0x1f, 0x8b, 0x90, 0xf3, 0x1c, 0x76, 0x49, 0x72, 0x68, 0x41, 0x7d, 0x43, 0xd8,
-	0x83, 0xae, 0xc3, 0x84, 0xec, 0xca, 0x91, 0x20, 0x07, 0x78, 0x04, 0xd5, 0x0d, 0xe3, 0x73, 0x2a,
-	0x48, 0x1e, 0x4f, 0xc1, 0xda, 0x25, 0x36, 0x6c, 0x41, 0xb3, 0xb6, 0xcb, 0x2e, 0x1c, 0x3e, 0x7c,
-	0xbe, 0x5c, 0x11, 0x9b, 0x70, 0xfa, 0x35, 0xd6, 0xf8, 0x4b, 0x7a, 0xc3, 0xa1, 0xb8, 0xf4, 0xe5,
-	0x7b, 0x8f, 0xfa, 0xcc, 0x65, 0x94, 0x00, 0x12, 0xa8, 0xe8, 0x0d, 0xb9, 0x67, 0xfb, 0x9e, 0xcb,
-	0x25, 0x29, 0x63, 0x1d, 0xc8, 0x36, 0x62, 0xac, 0x15, 0x3c, 0x01, 0xd4, 0x68, 0x77, 0x24, 0x07,
-	0x94, 0x49, 0xc7, 0xee, 0x4a, 0xc7, 0x65, 0xa4, 0x8a, 0x0d, 0x38, 0x79, 0x8e, 0x1b, 0x4f, 0xcd,
-	0xa4, 0xab, 0x73, 0xa0, 0x7d, 0xbf, 0x77, 0x21, 0x7d, 0x46, 0xaf, 0xfd, 0x77, 0x0e, 0xbd, 0xf6,
-	0x87, 0xe2, 0x92, 0x1c, 0x9a, 0x74, 0x9f, 0xb0, 0x1e, 0x77, 0x3d, 0x57, 0x74, 0xdf, 0x1a, 0x05,
-	0xd1, 0x9d, 0xdb, 0x56, 0xbc, 0x73, 0x25, 0x35, 0xcc, 0x91, 0xee, 0xbe, 0xd6, 0x9b, 0x32, 0x9d,
-	0x3e, 0x41, 0xac, 0x40, 0x51, 0x03, 0xcc, 0xed, 0x53, 0x72, 0x9c, 0x16, 0x95, 0xd0, 0x82, 0xd4,
-	0xd3, 0xa2, 0x52, 0xc4, 0x24, 0xf8, 0x02, 0x6b, 0x00, 0x1b, 0x54, 0x90, 0x13, 0x44, 0xa8, 0x3d,
-	0xc4, 0x46, 0xf3, 0x32, 0x3d, 0x24, 0x8f, 0x52, 0xee, 0x3b, 0xec, 0xc2, 0x25, 0x16, 0xbe, 0x80,
-	0xa3, 0x47, 0x90, 0x51, 0x7e, 0x97, 0xc2, 0xf1, 0x71, 0x0e, 0x68, 0xb7, 0x4f, 0xb9, 0x20, 0x8d,
-	0xb4, 0x43, 0xc9, 0xa2, 0x09, 0x6e, 0x2c, 0xaf, 0x1e, 0xdf, 0x00, 0x39, 0x16, 0xe4, 0x34, 0x6d,
-	0x74, 0x22, 0x97, 0xe3, 0x58, 0xfa, 0x7d, 0xeb, 0xdf, 0x2c, 0x94, 0x36, 0xaf, 0x00, 0xcb, 0x50,
-	0x10, 0x23, 0xdb, 0xa6, 0x42, 0x90, 0x3d, 0x7d, 0xd7, 0xcc, 0x69, 0x66, 0x74, 0xe1, 0x23, 0x76,
-	0xc5, 0xdc, 0x6b, 0x9f, 0x72, 0xee, 0x72, 0x92, 0xc5, 0x63, 0x38, 0xb4, 0x07, 0xd4, 0xbe, 0xf2,
-	0xc5, 0x68, 0x98, 0x80, 0xfb, 0xfa, 0x60, 0x46, 0x6c, 0xd8, 0xe5, 0x62, 0x10, 0xf7, 0xda, 0xef,
-	0xb9, 0xfd, 0xf7, 0x09, 0x9b, 0xd3, 0x5d, 0xb0, 0x5d, 0xc6, 0xa8, 0xad, 0xcf, 0xfe, 0x62, 0x24,
-	0x28, 0x39, 0x78, 0x7e, 0x89, 0x13, 0x75, 0x1e, 0x5f, 0xc2, 0xf1, 0x16, 0xca, 0x5c, 0x49, 0xc7,
-	0x8e, 0x90, 0xa4, 0xa0, 0x77, 0x7e, 0xa8, 0x2d, 0x56, 0x17, 0xb1, 0x05, 0xaf, 0xbf, 0x7a, 0x47,
-	0x63, 0x4d, 0x29, 0x7d, 0x03, 0x4f, 0xae, 0x54, 0xcc, 0x02, 0xfe, 0x00, 0xaf, 0x76, 0xb0, 0xcc,
-	0x95, 0xbe, 0xd7, 0x15, 0x82, 0x94, 0x5b, 0x6b, 0x28, 0x7a, 0x4a, 0x45, 0xfa, 0x41, 0x63, 0x0d,
-	0xb2, 0xb3, 0x69, 0x32, 0x10, 0xb3, 0xb3, 0xa9, 0x7e, 0xfa, 0xc1, 0x74, 0x6a, 0x46, 0x45, 0x3c,
-	0x0d, 0xd3, 0xd0, 0x30, 0x93, 0x49, 0x78, 0xbf, 0x58, 0x27, 0x23, 0x31, 0x0d, 0xf1, 0x47, 0xc8,
-	0x2d, 0x95, 0x8a, 0xac, 0x5c, 0x73, 0xbf, 0x5d, 0xee, 0x90, 0x74, 0x3c, 0xa5, 0x7b, 0x70, 0xc3,
-	0x76, 0x3c, 0x80, 0x65, 0x67, 0x29, 0x54, 0xf4, 0x79, 0x36, 0x51, 0xd8, 0x83, 0x9a, 0x50, 0x8b,
-	0xa9, 0xd7, 0x59, 0xa6, 0xdf, 0x88, 0xfa, 0xae, 0xb1, 0xd6, 0xd8, 0x89, 0xb6, 0xf6, 0xda, 0x99,
-	0x9f, 0x33, 0xbd, 0xf6, 0x1f, 0x6f, 0x6e, 0x67, 0xeb, 0xbb, 0xfb, 0x9b, 0xb3, 0x49, 0x38, 0x3f,
-	0xff, 0x4b, 0x0b, 0x26, 0x77, 0xc1, 0x6c, 0x91, 0xfc, 0x0d, 0x23, 0x75, 0x1e, 0x9b, 0x6f, 0xe2,
-	0x0f, 0xd3, 0x2f, 0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0xb2, 0xf2, 0x31, 0x64, 0xb7, 0x06, 0x00,
-	0x00,
+	// 964 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xdd, 0x52, 0xe3, 0xb6,
+	0x17, 0x27, 0x21, 0xe4, 0xe3, 0xe4, 0x03, 0x21, 0x02, 0x78, 0x81, 0x3f, 0x9b, 0x7f, 0xba, 0x65,
+	0x73, 0x05, 0x1d, 0xda, 0xab, 0x4e, 0x6f, 0x1c, 0x47, 0x10, 0x0f, 0x1b, 0xd9, 0x2b, 0xc9, 0x4b,
+	0xe8, 0x8d, 0xc7, 0x24, 0x5a, 0xc8, 0xec, 0x26, 0xce, 0x38, 0x61, 0xdb, 0x7d, 0x84, 0xce, 0xf4,
+	0x39, 0x7a, 0xd3, 0x97, 0xec, 0x48, 0xb6, 0x43, 0x02, 0xd9, 0xbd, 0x4a, 0xce, 0xef, 0xe3, 0xe8,
+	0xe8, 0x1c, 0x4b, 0x82, 0xfa, 0x34, 0x0a, 0xe7, 0xe1, 0xec, 0x7c, 0x22, 0xe7, 0x7f, 0x84, 0xd1,
+	0xa7, 0x33, 0x1d, 0xe2, 0x7c, 0x8c, 0x36, 0xff, 0xae, 0x42, 0xa5, 0xff, 0x38, 0x95, 0x51, 0x4f,
+	0xce, 0x66, 0xc1, 0xbd, 0xc4, 0xbf, 0x42, 0xbe, 0x2b, 0x83, 0xa1, 0x8c, 0x8c, 0x4c, 0x23, 0xd3,
+	0x2a, 0x5f, 0x34, 0x63, 0xc3, 0xec, 0x6c, 0x59, 0x75, 0x96, 0xfc, 0xc6, 0x4a, 0x96, 0x38, 0xf0,
+	0x2f, 0x90, 0xeb, 0x04, 0xf3, 0xc0, 0xc8, 0x6a, 0x67, 0xe3, 0x7b, 0x4e, 0xa5, 0x63, 0x5a, 0x7d,
+	0xf8, 0x6f, 0x16, 0xaa, 0x2b, 0xf9, 0xb0, 0x01, 0x85, 0x2f, 0x32, 0x9a, 0x8d, 0xc2, 0x89, 0x2e,
+	0xa2, 0xc4, 0xd2, 0x10, 0xd7, 0x61, 0xeb, 0x73, 0x78, 0x3f, 0x1a, 0xea, 0x25, 0x4a, 0x2c, 0x0e,
+	0x30, 0x86, 0xdc, 0xc7, 0x28, 0x1c, 0x1b, 0x9b, 0x1a, 0xd4, 0xff, 0xf1, 0x3e, 0xe4, 0xef, 0x06,
+	0x93, 0x60, 0x2c, 0x8d, 0x9c, 0x46, 0x93, 0x48, 0xd5, 0x38, 0xff, 0x3a, 0x95, 0xc6, 0x56, 0x23,
+	0xd3, 0xaa, 0x7d, 0xbf, 0x46, 0xf1, 0x75, 0x2a, 0x99, 0x56, 0xe3, 0x26, 0x54, 0x86, 0xc1, 0x3c,
+	0xb0, 0x1e, 0xe4, 0xe0, 0x13, 0x7f, 0x1c, 0x1b, 0xf9, 0x46, 0xa6, 0x55, 0x65, 0x2b, 0x18, 0xfe,
+	0x0d, 0x4a, 0x32, 0x8a, 0xc2, 0x48, 0xd9, 0x8c, 0x82, 0x4e, 0x7f, 0xb2, 0x36, 0x3d, 0x49, 0x55,
+	0xec, 0xc9, 0x80, 0x4f, 0xa1, 0x26, 0x27, 0xc1, 0xdd, 0x67, 0x69, 0x85, 0xe3, 0x69, 0x24, 0x67,
+	0x33, 0xa3, 0xd8, 0xc8, 0xb4, 0x8a, 0xec, 0x19, 0x7a, 0xf8, 0x16, 0xca, 0x4b, 0x2d, 0x54, 0xad,
+	0x1a, 0xcf, 0xee, 0xed, 0xc9, 0xc7, 0x50, 0xef, 0xbe, 0xc2, 0xd2, 0xb0, 0xf9, 0x57, 0x61, 0xa1,
+	0xd4, 0x0b, 0x54, 0xa1, 0xc4, 0x09, 0xed, 0xb4, 0xdf, 0x39, 0xd6, 0x35, 0xda, 0xc0, 0x00, 0x79,
+	0xd7, 0xe1, 0x42, 0xf4, 0x51, 0x06, 0x6f, 0x43, 0xb9, 0x6d, 0x0a, 0xab, 0x9b, 0x00, 0x59, 0xa5,
+	0xbd, 0x22, 0xc2, 0x8f, 0xb5, 0x9b, 0xb8, 0x08, 0x39, 0xd7, 0xa6, 0x57, 0x28, 0x87, 0x0d, 0xa8,
+	0x2f, 0x08, 0xab, 0x6b, 0xda, 0x94, 0x0b, 0x53, 0x78, 0x1c, 0x6d, 0xe1, 0x1d, 0xa8, 0x2e, 0x18,
+	0x9f, 0x11, 0x8e, 0xf2, 0xf8, 0x18, 0x8c, 0x75, 0x62, 0xcd, 0x16, 0x14, 0x6b, 0x39, 0xf4, 0xd2,
+	0x66, 0xbd, 0x97, 0xe9, 0x8a, 0xb8, 0x01, 0xc7, 0xdf, 0x62, 0xb5, 0xbf, 0xa4, 0x16, 0xec, 0xf1,
+	0x2b, 0x5f, 0xdc, 0xba, 0xc4, 0xa7, 0x0e, 0x25, 0x08, 0x30, 0x82, 0x8a, 0x5a, 0x90, 0xb9, 0x96,
+	0xef, 0x3a, 0x4c, 0xa0, 0x32, 0xae, 0x03, 0x5a, 0x46, 0xb4, 0xb5, 0x82, 0xf7, 0x01, 0x2b, 0xd4,
+	0xf4, 0x44, 0x97, 0x50, 0x61, 0x5b, 0xa6, 0xb0, 0x1d, 0x8a, 0xaa, 0xf8, 0x10, 0xf6, 0x5f, 0xe2,
+	0xda, 0x53, 0xd3, 0xe5, 0xaa, 0x1a, 0x48, 0xc7, 0x6f, 0x5f, 0x0a, 0x9f, 0x92, 0x1b, 0xff, 0x83,
+	0x4d, 0x6e, 0xfc, 0x1e, 0xbf, 0x42, 0xdb, 0xba, 0xdc, 0x67, 0xac, 0xcb, 0x1c, 0xd7, 0xe1, 0xe6,
+	0x3b, 0xad, 0x40, 0xaa, 0x73, 0xcb, 0x8a, 0x0f, 0x8e, 0x20, 0x9a, 0xd9, 0x51, 0xdd, 0x57, 0x7a,
+	0xbd, 0x4d, 0xbb, 0x83, 0x30, 0xae, 0x40, 0x51, 0x01, 0xd4, 0xe9, 0x10, 0xb4, 0x9b, 0x6e, 0x2a,
+	0xa1, 0x39, 0xaa, 0xa7, 0x9b, 0x4a, 0x11, 0x5d, 0xe0, 0x1e, 0xae, 0x01, 0x2c, 0x50, 0x8e, 0xf6,
+	0x31, 0x86, 0xda, 0x53, 0xac, 0x35, 0x07, 0xe9, 0x90, 0x5c, 0x42, 0x98, 0x6f, 0xd3, 0x4b, 0x07,
+	0x19, 0x78, 0x0f, 0x76, 0x56, 0x20, 0xad, 0x7c, 0x95, 0xc2, 0xf1, 0x38, 0xbb, 0xc4, 0xec, 0x10,
+	0xc6, 0xd1, 0x61, 0xda, 0xa1, 0x24, 0x69, 0x82, 0x6b, 0xcb, 0xd1, 0xea, 0x17, 0x20, 0xfa, 0x1c,
+	0x1d, 0xa7, 0x8d, 0x4e, 0xe4, 0xa2, 0x1f, 0x4b, 0xff, 0x97, 0xe2, 0x6a, 0x9e, 0xc4, 0xe7, 0xd4,
+	0x74, 0x79, 0xd7, 0x11, 0xe8, 0x24, 0x4d, 0xbf, 0x8a, 0x6b, 0xcf, 0x6b, 0xbc, 0x0b, 0xdb, 0x4f,
+	0x9c, 0xd5, 0xf5, 0xe8, 0x35, 0x6a, 0xe0, 0x03, 0xd8, 0x7d, 0x06, 0x6a, 0xf5, 0xff, 0x55, 0xfd,
+	0xcc, 0xbc, 0x54, 0xde, 0xf7, 0x1e, 0xe1, 0x71, 0xbf, 0x51, 0x13, 0xbf, 0x82, 0xbd, 0x17, 0xb0,
+	0x76, 0xfc, 0xa0, 0x52, 0x69, 0xca, 0x74, 0x5d, 0x42, 0x3b, 0x3e, 0xa1, 0x82, 0xd9, 0x84, 0xa3,
+	0x37, 0xf8, 0x08, 0x0e, 0xd6, 0x10, 0xda, 0xf5, 0xa3, 0x1a, 0x9e, 0xc5, 0x1c, 0xce, 0xfd, 0xf7,
+	0x1e, 0x61, 0xb7, 0xe8, 0x54, 0x95, 0xb9, 0x04, 0x68, 0xd5, 0xdb, 0xe6, 0x3f, 0x59, 0x28, 0x2d,
+	0x4e, 0x3d, 0x2e, 0x43, 0x81, 0x7b, 0x96, 0x45, 0x38, 0x47, 0x1b, 0xea, 0x6c, 0xe9, 0xaf, 0x37,
+	0xa3, 0x06, 0xed, 0xd1, 0x6b, 0xea, 0xdc, 0xf8, 0x84, 0x31, 0x87, 0xa1, 0xac, 0xce, 0xd5, 0x25,
+	0xd6, 0xb5, 0xcf, 0xbd, 0x5e, 0x02, 0x6e, 0xaa, 0x0f, 0xd1, 0xa3, 0x3d, 0x93, 0xf1, 0x6e, 0xfc,
+	0x6d, 0xf9, 0x6d, 0xa7, 0x73, 0x9b, 0xb0, 0x39, 0x35, 0x75, 0xcb, 0xa1, 0x94, 0x58, 0x6a, 0x8f,
+	0x97, 0x1e, 0x27, 0x68, 0xeb, 0xe5, 0xa1, 0x4d, 0xd4, 0x79, 0xb5, 0xe7, 0x25, 0x94, 0x3a, 0x82,
+	0xf4, 0x6d, 0x2e, 0x50, 0x21, 0x6d, 0x76, 0x3c, 0xcb, 0x58, 0x5d, 0xc4, 0x4d, 0x38, 0xf9, 0xe6,
+	0x99, 0x8c, 0x35, 0xa5, 0xf4, 0xcc, 0x3f, 0x3b, 0x42, 0x31, 0x0b, 0xf8, 0x35, 0x1c, 0xad, 0x61,
+	0xa9, 0x23, 0x7c, 0xd7, 0xe4, 0x1c, 0x95, 0x9b, 0x73, 0x28, 0xba, 0x52, 0x46, 0xea, 0x02, 0xc3,
+	0x35, 0xc8, 0x8e, 0x86, 0xc9, 0x03, 0x90, 0x1d, 0x0d, 0xd5, 0x55, 0x17, 0x0c, 0x87, 0xfa, 0x6a,
+	0x8c, 0x6f, 0xff, 0x34, 0xd4, 0xcc, 0x60, 0x10, 0x3e, 0x4e, 0xe6, 0xc9, 0x13, 0x90, 0x86, 0xf8,
+	0x0d, 0xe4, 0xa6, 0x52, 0x46, 0x46, 0xae, 0xb1, 0xd9, 0x2a, 0x5f, 0xa0, 0xf4, 0x3a, 0x4e, 0xd7,
+	0x60, 0x9a, 0xbd, 0x70, 0x01, 0xa6, 0x17, 0x53, 0x2e, 0xa3, 0x2f, 0xa3, 0x81, 0xc4, 0x6d, 0xa8,
+	0x71, 0x39, 0x19, 0xba, 0x17, 0xd3, 0xf4, 0x4d, 0xac, 0xaf, 0xbb, 0xc6, 0x0f, 0xd7, 0xa2, 0xcd,
+	0x8d, 0x56, 0xe6, 0xa7, 0x4c, 0xbb, 0xf5, 0xfb, 0xe9, 0xfd, 0x68, 0xfe, 0xf0, 0x78, 0x77, 0x36,
+	0x08, 0xc7, 0xe7, 0x7f, 0x2a, 0xc1, 0xe0, 0x21, 0x18, 0x4d, 0x92, 0xbf, 0x61, 0x24, 0xcf, 0x63,
+	0xf3, 0x5d, 0xfc, 0x10, 0xff, 0xfc, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xf6, 0x73, 0x1e, 0x8e,
+	0xa7, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
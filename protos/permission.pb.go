@@ -65,6 +65,7 @@ func (PermissionRule) EnumDescriptor() ([]byte, []int) {
 type SignatureInfo struct {
 	PublicKey            string   `protobuf:"bytes,1,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
 	Sign                 []byte   `protobuf:"bytes,2,opt,name=Sign,proto3" json:"Sign,omitempty"`
+	CertChain            [][]byte `protobuf:"bytes,3,rep,name=CertChain,proto3" json:"CertChain,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -109,6 +110,13 @@ func (m *SignatureInfo) GetSign() []byte {
 	return nil
 }
 
+func (m *SignatureInfo) GetCertChain() [][]byte {
+	if m != nil {
+		return m.CertChain
+	}
+	return nil
+}
+
 type PermissionModel struct {
 	Rule                 PermissionRule `protobuf:"varint,1,opt,name=rule,proto3,enum=protos.PermissionRule" json:"rule,omitempty"`
 	AcceptValue          float64        `protobuf:"fixed64,2,opt,name=acceptValue,proto3" json:"acceptValue,omitempty"`
@@ -199,6 +207,7 @@ func (m *AkSet) GetAks() []string {
 type AkSets struct {
 	Sets                 map[string]*AkSet `protobuf:"bytes,1,rep,name=sets,proto3" json:"sets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	Expression           string            `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	ExpressionVersion    int32             `protobuf:"varint,3,opt,name=expressionVersion,proto3" json:"expressionVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -243,11 +252,19 @@ func (m *AkSets) GetExpression() string {
 	return ""
 }
 
+func (m *AkSets) GetExpressionVersion() int32 {
+	if m != nil {
+		return m.ExpressionVersion
+	}
+	return 0
+}
+
 // Acl实际使用的结构
 type Acl struct {
 	Pm                   *PermissionModel   `protobuf:"bytes,1,opt,name=pm,proto3" json:"pm,omitempty"`
 	AksWeight            map[string]float64 `protobuf:"bytes,2,rep,name=aksWeight,proto3" json:"aksWeight,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
 	AkSets               *AkSets            `protobuf:"bytes,3,opt,name=akSets,proto3" json:"akSets,omitempty"`
+	CaRoots              []string           `protobuf:"bytes,4,rep,name=caRoots,proto3" json:"caRoots,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
 	XXX_sizecache        int32              `json:"-"`
@@ -299,6 +316,13 @@ func (m *Acl) GetAkSets() *AkSets {
 	return nil
 }
 
+func (m *Acl) GetCaRoots() []string {
+	if m != nil {
+		return m.CaRoots
+	}
+	return nil
+}
+
 // 查询Acl
 type AclStatus struct {
 	Bcname               string   `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
@@ -490,44 +514,47 @@ func init() {
 func init() { proto.RegisterFile("protos/permission.proto", fileDescriptor_7c4abdc3fb06a8dd) }
 
 var fileDescriptor_7c4abdc3fb06a8dd = []byte{
-	// 613 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0x5d, 0x6f, 0xd3, 0x30,
-	0x14, 0x25, 0x4d, 0x5b, 0x96, 0xdb, 0xad, 0x04, 0x0b, 0x6d, 0x61, 0x02, 0x54, 0x05, 0x69, 0x54,
-	0x13, 0xea, 0xa4, 0xf2, 0x32, 0x21, 0x5e, 0xc2, 0xc8, 0xd8, 0xb4, 0xb5, 0x9b, 0x9c, 0x6e, 0x08,
-	0x5e, 0xaa, 0xd4, 0xf5, 0xd6, 0xa8, 0x49, 0x1c, 0x62, 0x07, 0x6d, 0x2f, 0xfc, 0x14, 0x7e, 0x0e,
-	0x8f, 0xfc, 0x26, 0x64, 0x3b, 0x6d, 0x53, 0xf1, 0xf1, 0x52, 0xf9, 0x9e, 0x73, 0x7c, 0xae, 0xef,
-	0xb1, 0x1b, 0xd8, 0xc9, 0x72, 0x26, 0x18, 0x3f, 0xc8, 0x68, 0x9e, 0x44, 0x9c, 0x47, 0x2c, 0xed,
-	0x29, 0x04, 0x35, 0x35, 0xe1, 0x7a, 0xb0, 0x15, 0x44, 0xb7, 0x69, 0x28, 0x8a, 0x9c, 0x9e, 0xa6,
-	0x37, 0x0c, 0x3d, 0x03, 0xeb, 0xb2, 0x98, 0xc4, 0x11, 0x39, 0xa3, 0xf7, 0x8e, 0xd1, 0x31, 0xba,
-	0x16, 0x5e, 0x01, 0x08, 0x41, 0x5d, 0xca, 0x9d, 0x5a, 0xc7, 0xe8, 0x6e, 0x62, 0xb5, 0x76, 0xc7,
-	0xf0, 0xe8, 0x72, 0x69, 0x3f, 0x60, 0x53, 0x1a, 0xa3, 0x7d, 0xa8, 0xe7, 0x45, 0x4c, 0xd5, 0xfe,
-	0x76, 0x7f, 0x5b, 0xf7, 0xe4, 0xbd, 0x95, 0x0c, 0x17, 0x31, 0xc5, 0x4a, 0x83, 0x3a, 0xd0, 0x0a,
-	0x09, 0xa1, 0x99, 0xb8, 0x0e, 0xe3, 0x82, 0x2a, 0x67, 0x03, 0x57, 0x21, 0xf7, 0x29, 0x34, 0xbc,
-	0x79, 0x40, 0x05, 0xb2, 0xc1, 0x0c, 0xe7, 0xdc, 0x31, 0x3a, 0x66, 0xd7, 0xc2, 0x72, 0xe9, 0xfe,
-	0x30, 0xa0, 0xa9, 0x38, 0x8e, 0x5e, 0x43, 0x9d, 0x53, 0xa1, 0xd9, 0x56, 0xdf, 0x59, 0xf4, 0xd4,
-	0x6c, 0x4f, 0xfe, 0xf8, 0xa9, 0xc8, 0xef, 0xb1, 0x52, 0xa1, 0x17, 0x00, 0xf4, 0x2e, 0xcb, 0xa9,
-	0x3a, 0x8d, 0x6a, 0x6a, 0xe1, 0x0a, 0xb2, 0x7b, 0x0c, 0xd6, 0x72, 0x8b, 0xec, 0x3b, 0x5f, 0xa6,
-	0x21, 0x97, 0xe8, 0x25, 0x34, 0xbe, 0x2d, 0x8f, 0xdb, 0xea, 0x6f, 0xad, 0x75, 0xc3, 0x9a, 0x7b,
-	0x5b, 0x3b, 0x34, 0xdc, 0x5f, 0x06, 0x98, 0x1e, 0x89, 0xd1, 0x2b, 0xa8, 0x65, 0x89, 0x72, 0x68,
-	0xf5, 0x77, 0xfe, 0xcc, 0x43, 0xc5, 0x86, 0x6b, 0x59, 0x82, 0x0e, 0xc1, 0x0a, 0xe7, 0xfc, 0x13,
-	0x8d, 0x6e, 0x67, 0xc2, 0xa9, 0xa9, 0x59, 0x76, 0x97, 0xee, 0x24, 0xee, 0x79, 0x0b, 0x52, 0x4f,
-	0xb3, 0x12, 0xa3, 0x3d, 0x68, 0x86, 0x6a, 0x58, 0xc7, 0x54, 0x6d, 0xda, 0xeb, 0x11, 0xe0, 0x92,
-	0xdd, 0x7d, 0x07, 0xed, 0x75, 0x93, 0xbf, 0xcc, 0xf7, 0xa4, 0x3a, 0x9f, 0x51, 0x1d, 0xe8, 0xa7,
-	0x01, 0x96, 0x47, 0xe2, 0x40, 0x84, 0xa2, 0xe0, 0x68, 0x1b, 0x9a, 0x13, 0x92, 0x86, 0x09, 0x2d,
-	0x37, 0x97, 0x55, 0x79, 0xa9, 0xac, 0x48, 0xc5, 0x50, 0x92, 0x3a, 0xdf, 0x2a, 0x84, 0x5c, 0xd8,
-	0x24, 0x2c, 0x15, 0x79, 0x48, 0xb4, 0xc4, 0x54, 0x92, 0x35, 0x4c, 0x5e, 0x52, 0x42, 0xc5, 0x8c,
-	0x4d, 0x95, 0xa2, 0xae, 0x2f, 0x69, 0x85, 0xc8, 0xb7, 0x4a, 0x58, 0x7a, 0x13, 0xe5, 0x09, 0x9d,
-	0x3a, 0x8d, 0x8e, 0xd1, 0xdd, 0xc0, 0x2b, 0x00, 0x3d, 0x07, 0x33, 0x24, 0xb1, 0xd3, 0x54, 0x61,
-	0xb4, 0x2a, 0x19, 0x62, 0x89, 0xbb, 0x3e, 0x3c, 0xf6, 0xce, 0xfa, 0x9e, 0x3e, 0x12, 0xa6, 0x5f,
-	0x0b, 0xca, 0xc5, 0x3f, 0xe7, 0x71, 0xe0, 0x61, 0x38, 0x9d, 0xca, 0xc7, 0x51, 0xce, 0xb2, 0x28,
-	0xdd, 0x63, 0x40, 0x55, 0x1b, 0x9e, 0xb1, 0x94, 0xd3, 0xff, 0xfa, 0x68, 0xa9, 0xba, 0x5b, 0xe9,
-	0xa3, 0xcb, 0xfd, 0xef, 0xd0, 0x5e, 0xff, 0x7b, 0xa0, 0x0d, 0xa8, 0x0f, 0xaf, 0xce, 0xcf, 0xed,
-	0x07, 0x08, 0x41, 0x3b, 0x38, 0xfd, 0x38, 0x1c, 0x8f, 0x4e, 0xb0, 0x1f, 0x9c, 0x5c, 0x9c, 0x7f,
-	0xb0, 0x0d, 0xd4, 0x06, 0x50, 0x98, 0x77, 0x16, 0xf8, 0x23, 0xbb, 0x86, 0xb6, 0xc0, 0x52, 0x35,
-	0xf6, 0x46, 0xbe, 0x6d, 0xa2, 0x4d, 0xd8, 0x50, 0x65, 0x70, 0x35, 0xb0, 0xeb, 0x92, 0x3c, 0xf2,
-	0xc6, 0x81, 0x8f, 0xaf, 0x7d, 0x6c, 0x37, 0xa4, 0xdf, 0xd1, 0xc5, 0x60, 0x70, 0x35, 0x3c, 0x1d,
-	0x7d, 0x1e, 0x5f, 0x5f, 0x8c, 0x7c, 0xbb, 0xf9, 0xbe, 0xfb, 0x65, 0xef, 0x36, 0x12, 0xb3, 0x62,
-	0xd2, 0x23, 0x2c, 0x39, 0xb8, 0x2b, 0x32, 0x9a, 0x93, 0x59, 0x18, 0xa5, 0xe5, 0x92, 0xe5, 0xf4,
-	0x40, 0x27, 0x38, 0xd1, 0x9f, 0x8e, 0x37, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0xfa, 0x39, 0xa6,
-	0x18, 0x5c, 0x04, 0x00, 0x00,
+	// 660 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0xc1, 0x6e, 0xda, 0x4a,
+	0x14, 0x7d, 0xc6, 0x40, 0xe2, 0x0b, 0xe1, 0x39, 0xa3, 0xa7, 0xc4, 0x2f, 0x6a, 0x2b, 0xe4, 0x4a,
+	0x29, 0x8a, 0x22, 0x22, 0xd1, 0x4d, 0x54, 0x75, 0xe3, 0x52, 0xa7, 0x89, 0x12, 0x48, 0x34, 0x26,
+	0x54, 0xed, 0x06, 0x99, 0x61, 0x12, 0x2c, 0x6c, 0x8f, 0x6b, 0x8f, 0xab, 0x64, 0xd3, 0xcf, 0xeb,
+	0xaa, 0x1f, 0xd2, 0xcf, 0xa8, 0x66, 0x06, 0xb0, 0x51, 0xda, 0x6e, 0xd0, 0xdc, 0x73, 0x8e, 0xef,
+	0xbd, 0xe7, 0x8c, 0x31, 0xec, 0x27, 0x29, 0xe3, 0x2c, 0x3b, 0x49, 0x68, 0x1a, 0x05, 0x59, 0x16,
+	0xb0, 0xb8, 0x2b, 0x11, 0x54, 0x57, 0x84, 0x3d, 0x81, 0x1d, 0x2f, 0xb8, 0x8f, 0x7d, 0x9e, 0xa7,
+	0xf4, 0x22, 0xbe, 0x63, 0xe8, 0x19, 0x18, 0x37, 0xf9, 0x34, 0x0c, 0xc8, 0x25, 0x7d, 0xb4, 0xb4,
+	0xb6, 0xd6, 0x31, 0x70, 0x01, 0x20, 0x04, 0x55, 0x21, 0xb7, 0x2a, 0x6d, 0xad, 0xd3, 0xc4, 0xf2,
+	0x2c, 0x9e, 0xe8, 0xd3, 0x94, 0xf7, 0xe7, 0x7e, 0x10, 0x5b, 0x7a, 0x5b, 0xef, 0x34, 0x71, 0x01,
+	0xd8, 0x13, 0xf8, 0xf7, 0x66, 0x3d, 0x7c, 0xc0, 0x66, 0x34, 0x44, 0x47, 0x50, 0x4d, 0xf3, 0x90,
+	0xca, 0xee, 0xad, 0xde, 0x9e, 0xda, 0x28, 0xeb, 0x16, 0x32, 0x9c, 0x87, 0x14, 0x4b, 0x0d, 0x6a,
+	0x43, 0xc3, 0x27, 0x84, 0x26, 0x7c, 0xec, 0x87, 0x39, 0x95, 0x73, 0x35, 0x5c, 0x86, 0xec, 0xff,
+	0xa1, 0xe6, 0x2c, 0x3c, 0xca, 0x91, 0x09, 0xba, 0xbf, 0xc8, 0x2c, 0xad, 0xad, 0x77, 0x0c, 0x2c,
+	0x8e, 0xf6, 0x0f, 0x0d, 0xea, 0x92, 0xcb, 0xd0, 0x31, 0x54, 0x33, 0xca, 0x15, 0xdb, 0xe8, 0x59,
+	0xab, 0x99, 0x8a, 0xed, 0x8a, 0x1f, 0x37, 0xe6, 0xe9, 0x23, 0x96, 0x2a, 0xf4, 0x02, 0x80, 0x3e,
+	0x24, 0x29, 0x95, 0xdb, 0xc8, 0xa1, 0x06, 0x2e, 0x21, 0xe8, 0x18, 0x76, 0x8b, 0x6a, 0x4c, 0x53,
+	0x29, 0xd3, 0xdb, 0x5a, 0xa7, 0x86, 0x9f, 0x12, 0x07, 0x67, 0x60, 0xac, 0x07, 0x88, 0x2d, 0x17,
+	0xeb, 0x64, 0xc5, 0x11, 0xbd, 0x84, 0xda, 0xd7, 0xb5, 0xb9, 0x46, 0x6f, 0x67, 0x63, 0x37, 0xac,
+	0xb8, 0x37, 0x95, 0x53, 0xcd, 0xfe, 0xa9, 0x81, 0xee, 0x90, 0x10, 0xbd, 0x82, 0x4a, 0x12, 0xc9,
+	0x0e, 0x8d, 0xde, 0xfe, 0xd3, 0xf4, 0x64, 0xc8, 0xb8, 0x92, 0x44, 0xe8, 0x14, 0x0c, 0x7f, 0x91,
+	0x7d, 0xa4, 0xc1, 0xfd, 0x9c, 0x5b, 0x15, 0xe9, 0xfc, 0x60, 0xdd, 0x9d, 0x84, 0x5d, 0x67, 0x45,
+	0x2a, 0xef, 0x85, 0x18, 0x1d, 0x42, 0xdd, 0x97, 0xd1, 0x48, 0x57, 0x8d, 0x5e, 0x6b, 0x33, 0x30,
+	0xbc, 0x64, 0x91, 0x05, 0x5b, 0xc4, 0xc7, 0x8c, 0xf1, 0xcc, 0xaa, 0xca, 0xdc, 0x57, 0xe5, 0xc1,
+	0x5b, 0x68, 0x6d, 0xb6, 0xff, 0x8d, 0xf3, 0xff, 0xca, 0xce, 0xb5, 0xb2, 0xd5, 0xef, 0x1a, 0x18,
+	0x0e, 0x09, 0x3d, 0xee, 0xf3, 0x3c, 0x43, 0x7b, 0x50, 0x9f, 0x92, 0xd8, 0x8f, 0xe8, 0xf2, 0xe1,
+	0x65, 0xb5, 0x7c, 0x39, 0x58, 0x1e, 0xf3, 0xa1, 0x20, 0xd5, 0x3d, 0x95, 0x21, 0x64, 0x43, 0x93,
+	0xb0, 0x98, 0xa7, 0x3e, 0x51, 0x12, 0x5d, 0x4a, 0x36, 0x30, 0x71, 0xd9, 0x11, 0xe5, 0x73, 0x36,
+	0x93, 0x8a, 0xaa, 0xba, 0xec, 0x02, 0x11, 0xef, 0x37, 0x61, 0xf1, 0x5d, 0x90, 0x46, 0x74, 0x66,
+	0xd5, 0xda, 0x5a, 0x67, 0x1b, 0x17, 0x00, 0x7a, 0x0e, 0xba, 0x4f, 0x42, 0xab, 0x2e, 0x63, 0x6a,
+	0x94, 0xd2, 0xc5, 0x02, 0xb7, 0x5d, 0xd8, 0x75, 0x2e, 0x7b, 0x8e, 0x5a, 0x09, 0xd3, 0x2f, 0x39,
+	0xcd, 0xf8, 0x1f, 0xfd, 0x58, 0xb0, 0xe5, 0xcf, 0x66, 0xe2, 0xed, 0x59, 0x7a, 0x59, 0x95, 0xf6,
+	0x19, 0xa0, 0x72, 0x9b, 0x2c, 0x61, 0x71, 0x46, 0xff, 0xda, 0x47, 0x49, 0xe5, 0xad, 0x8b, 0x3e,
+	0xaa, 0x3c, 0xfa, 0x06, 0xad, 0xcd, 0xbf, 0x19, 0xda, 0x86, 0xea, 0xf0, 0xf6, 0xea, 0xca, 0xfc,
+	0x07, 0x21, 0x68, 0x79, 0x17, 0x1f, 0x86, 0x93, 0xd1, 0x39, 0x76, 0xbd, 0xf3, 0xeb, 0xab, 0xf7,
+	0xa6, 0x86, 0x5a, 0x00, 0x12, 0x73, 0x2e, 0x3d, 0x77, 0x64, 0x56, 0xd0, 0x0e, 0x18, 0xb2, 0xc6,
+	0xce, 0xc8, 0x35, 0x75, 0xd4, 0x84, 0x6d, 0x59, 0x7a, 0xb7, 0x03, 0xb3, 0x2a, 0xc8, 0xbe, 0x33,
+	0xf1, 0x5c, 0x3c, 0x76, 0xb1, 0x59, 0x13, 0xfd, 0xfa, 0xd7, 0x83, 0xc1, 0xed, 0xf0, 0x62, 0xf4,
+	0x69, 0x32, 0xbe, 0x1e, 0xb9, 0x66, 0xfd, 0x5d, 0xe7, 0xf3, 0xe1, 0x7d, 0xc0, 0xe7, 0xf9, 0xb4,
+	0x4b, 0x58, 0x74, 0xf2, 0x90, 0x27, 0x34, 0x25, 0xe2, 0x33, 0xb1, 0x3c, 0xb2, 0x94, 0x9e, 0xa8,
+	0x04, 0xa7, 0xea, 0x03, 0xf5, 0xfa, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0xdc, 0x5c, 0x27, 0xfb,
+	0xc2, 0x04, 0x00, 0x00,
 }